@@ -0,0 +1,91 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/xerrors"
+)
+
+// ApplyOperation is a single step needed to bring the device to the state
+// described by a manifest.
+type ApplyOperation struct {
+	// Kind is one of install, refresh, install-components, alias,
+	// connect, configure, create-quota or update-quota.
+	Kind    string `json:"kind"`
+	Snap    string `json:"snap,omitempty"`
+	Summary string `json:"summary"`
+}
+
+type applyRequest struct {
+	Manifest string `json:"manifest"`
+	Diff     bool   `json:"diff,omitempty"`
+}
+
+type applyResult struct {
+	Operations []*ApplyOperation `json:"operations"`
+}
+
+func encodeApplyRequest(manifest []byte, diff bool) (*bytes.Buffer, error) {
+	data, err := json.Marshal(&applyRequest{
+		Manifest: string(manifest),
+		Diff:     diff,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal apply request: %v", err)
+	}
+	return bytes.NewBuffer(data), nil
+}
+
+// ApplyDiff returns the operations that applying the given manifest, in
+// YAML format, would carry out, without carrying them out.
+func (client *Client) ApplyDiff(manifest []byte) ([]*ApplyOperation, error) {
+	body, err := encodeApplyRequest(manifest, true)
+	if err != nil {
+		return nil, err
+	}
+	var res applyResult
+	if _, err := client.doSync("POST", "/v2/apply", nil, nil, body, &res); err != nil {
+		return nil, xerrors.Errorf("cannot compute manifest operations: %w", err)
+	}
+	return res.Operations, nil
+}
+
+// Apply brings the device to the state described by the given manifest, in
+// YAML format. It returns the operations carried out by the change it
+// started, if there are none the change is already done.
+func (client *Client) Apply(manifest []byte) (ops []*ApplyOperation, changeID string, err error) {
+	body, err := encodeApplyRequest(manifest, false)
+	if err != nil {
+		return nil, "", err
+	}
+	result, changeID, err := client.doAsyncFull("POST", "/v2/apply", nil, nil, body, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	var res applyResult
+	if err := json.Unmarshal(result, &res); err != nil {
+		return nil, "", fmt.Errorf("cannot unmarshal apply result: %v", err)
+	}
+	return res.Operations, changeID, nil
+}
@@ -0,0 +1,102 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"encoding/json"
+	"io"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+)
+
+func (cs *clientSuite) TestClientApplyDiff(c *check.C) {
+	cs.rsp = `{
+		"result": {
+			"operations": [
+				{"kind": "install", "snap": "foo", "summary": "Install snap \"foo\""},
+				{"kind": "create-quota", "summary": "Create quota group \"grp\""}
+			]
+		},
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	ops, err := cs.cli.ApplyDiff([]byte("snaps: {foo: {}}"))
+	c.Assert(err, check.IsNil)
+	c.Check(ops, check.DeepEquals, []*client.ApplyOperation{
+		{Kind: "install", Snap: "foo", Summary: `Install snap "foo"`},
+		{Kind: "create-quota", Summary: `Create quota group "grp"`},
+	})
+
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/apply")
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var req map[string]interface{}
+	c.Assert(json.Unmarshal(body, &req), check.IsNil)
+	c.Check(req, check.DeepEquals, map[string]interface{}{
+		"manifest": "snaps: {foo: {}}",
+		"diff":     true,
+	})
+}
+
+func (cs *clientSuite) TestClientApplyDiffError(c *check.C) {
+	cs.status = 400
+	cs.rsp = `{
+		"result": {"message": "invalid manifest: invalid snap name: \"Foo\""},
+		"status-code": 400,
+		"type": "error"
+	}`
+
+	_, err := cs.cli.ApplyDiff([]byte("snaps: {Foo: {}}"))
+	c.Check(err, check.ErrorMatches, `cannot compute manifest operations: invalid manifest: invalid snap name: "Foo"`)
+}
+
+func (cs *clientSuite) TestClientApply(c *check.C) {
+	cs.status = 202
+	cs.rsp = `{
+		"change": "42",
+		"result": {
+			"operations": [
+				{"kind": "configure", "snap": "foo", "summary": "Configure \"key\" of snap \"foo\""}
+			]
+		},
+		"status-code": 202,
+		"type": "async"
+	}`
+
+	ops, changeID, err := cs.cli.Apply([]byte("snaps: {foo: {config: {key: value}}}"))
+	c.Assert(err, check.IsNil)
+	c.Check(changeID, check.Equals, "42")
+	c.Check(ops, check.DeepEquals, []*client.ApplyOperation{
+		{Kind: "configure", Snap: "foo", Summary: `Configure "key" of snap "foo"`},
+	})
+
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var req map[string]interface{}
+	c.Assert(json.Unmarshal(body, &req), check.IsNil)
+	c.Check(req, check.DeepEquals, map[string]interface{}{
+		"manifest": "snaps: {foo: {config: {key: value}}}",
+	})
+}
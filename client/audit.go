@@ -0,0 +1,75 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AuditEntry is a state-changing request recorded in the audit log of
+// snapd.
+type AuditEntry struct {
+	Time time.Time `json:"time"`
+	// UID and PID are those of the requesting process, if known.
+	UID *uint32 `json:"uid,omitempty"`
+	PID int32   `json:"pid,omitempty"`
+	// Snap is the snap the requesting process was running as, if any.
+	Snap string `json:"snap,omitempty"`
+	// User is the snapd user the request was authenticated as, if any.
+	User       string `json:"user,omitempty"`
+	Method     string `json:"method"`
+	Endpoint   string `json:"endpoint"`
+	Change     string `json:"change,omitempty"`
+	StatusCode int    `json:"status-code"`
+	Error      string `json:"error,omitempty"`
+}
+
+// AuditOptions selects the audit log entries to return.
+type AuditOptions struct {
+	// Since and Until restrict the entries to those recorded in the given
+	// time range, if set.
+	Since time.Time
+	Until time.Time
+	// UID restricts the entries to those made by the given user, if set.
+	UID *uint32
+}
+
+// Audit returns the entries of the audit log of snapd matching the given
+// options, oldest first.
+func (client *Client) Audit(opts *AuditOptions) ([]*AuditEntry, error) {
+	q := make(url.Values)
+	if opts != nil {
+		if !opts.Since.IsZero() {
+			q.Set("since", opts.Since.Format(time.RFC3339Nano))
+		}
+		if !opts.Until.IsZero() {
+			q.Set("until", opts.Until.Format(time.RFC3339Nano))
+		}
+		if opts.UID != nil {
+			q.Set("uid", strconv.FormatUint(uint64(*opts.UID), 10))
+		}
+	}
+
+	var entries []*AuditEntry
+	_, err := client.doSync("GET", "/v2/audit", q, nil, nil, &entries)
+	return entries, err
+}
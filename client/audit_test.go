@@ -0,0 +1,100 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+)
+
+func (cs *clientSuite) TestClientAudit(c *check.C) {
+	cs.rsp = `{
+		"result": [
+		    {
+			"time": "2026-03-01T10:00:00Z",
+			"uid": 0,
+			"pid": 100,
+			"method": "POST",
+			"endpoint": "/v2/snaps/foo",
+			"change": "42",
+			"status-code": 202
+		    },
+		    {
+			"time": "2026-03-01T11:00:00Z",
+			"uid": 1000,
+			"pid": 200,
+			"snap": "some-snap",
+			"method": "POST",
+			"endpoint": "/v2/snapctl",
+			"status-code": 403,
+			"error": "access denied"
+		    }
+		],
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	uid := uint32(1000)
+	entries, err := cs.cli.Audit(&client.AuditOptions{Since: since, UID: &uid})
+	c.Assert(err, check.IsNil)
+
+	root := uint32(0)
+	c.Check(entries, check.DeepEquals, []*client.AuditEntry{
+		{
+			Time:       time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+			UID:        &root,
+			PID:        100,
+			Method:     "POST",
+			Endpoint:   "/v2/snaps/foo",
+			Change:     "42",
+			StatusCode: 202,
+		},
+		{
+			Time:       time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+			UID:        &uid,
+			PID:        200,
+			Snap:       "some-snap",
+			Method:     "POST",
+			Endpoint:   "/v2/snapctl",
+			StatusCode: 403,
+			Error:      "access denied",
+		},
+	})
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/audit")
+	query := cs.req.URL.Query()
+	c.Check(query, check.HasLen, 2)
+	c.Check(query.Get("since"), check.Equals, "2026-03-01T00:00:00Z")
+	c.Check(query.Get("uid"), check.Equals, "1000")
+}
+
+func (cs *clientSuite) TestClientAuditNoOptions(c *check.C) {
+	cs.rsp = `{"result": [], "status": "OK", "status-code": 200, "type": "sync"}`
+
+	entries, err := cs.cli.Audit(nil)
+	c.Assert(err, check.IsNil)
+	c.Check(entries, check.HasLen, 0)
+	c.Check(cs.req.URL.Query(), check.HasLen, 0)
+}
@@ -35,6 +35,7 @@ type Change struct {
 	Status  string  `json:"status"`
 	Tasks   []*Task `json:"tasks,omitempty"`
 	Ready   bool    `json:"ready"`
+	Paused  bool    `json:"paused,omitempty"`
 	Err     string  `json:"err,omitempty"`
 
 	SpawnTime time.Time `json:"spawn-time,omitempty"`
@@ -92,10 +93,26 @@ func (client *Client) Change(id string) (*Change, error) {
 
 // Abort attempts to abort a change that is in not yet ready.
 func (client *Client) Abort(id string) (*Change, error) {
+	return client.doChangeAction(id, "abort")
+}
+
+// Pause holds a change that is not yet ready at task boundaries: tasks
+// already running are let finish, but no new ones are started until the
+// change is resumed.
+func (client *Client) Pause(id string) (*Change, error) {
+	return client.doChangeAction(id, "pause")
+}
+
+// Resume lets a paused change proceed.
+func (client *Client) Resume(id string) (*Change, error) {
+	return client.doChangeAction(id, "resume")
+}
+
+func (client *Client) doChangeAction(id, action string) (*Change, error) {
 	var postData struct {
 		Action string `json:"action"`
 	}
-	postData.Action = "abort"
+	postData.Action = action
 
 	var body bytes.Buffer
 	if err := json.NewEncoder(&body).Encode(postData); err != nil {
@@ -110,6 +127,49 @@ func (client *Client) Abort(id string) (*Change, error) {
 	return &chg, nil
 }
 
+// ChangeGraph holds the tasks of a change and the dependencies between
+// them.
+type ChangeGraph struct {
+	ID      string           `json:"id"`
+	Kind    string           `json:"kind"`
+	Summary string           `json:"summary"`
+	Status  string           `json:"status"`
+	Tasks   []*TaskGraphNode `json:"tasks"`
+	Edges   []*TaskGraphEdge `json:"edges,omitempty"`
+}
+
+// TaskGraphNode is a task in a ChangeGraph. Tasks from other changes
+// which tasks of the change depend on have Change set.
+type TaskGraphNode struct {
+	ID      string     `json:"id"`
+	Kind    string     `json:"kind"`
+	Summary string     `json:"summary"`
+	Status  string     `json:"status"`
+	Lanes   []int      `json:"lanes,omitempty"`
+	AtTime  *time.Time `json:"at-time,omitempty"`
+	Change  string     `json:"change,omitempty"`
+}
+
+// TaskGraphEdge is a dependency between two tasks in a ChangeGraph. Kind
+// is "wait" when To can only be run once From is done, or "undo" when To
+// can only be undone once From is no longer in progress. Blocking is set
+// when the dependency currently prevents To from running.
+type TaskGraphEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Kind     string `json:"kind"`
+	Blocking bool   `json:"blocking,omitempty"`
+}
+
+// ChangeGraph fetches the task dependency graph of a change given its ID.
+func (client *Client) ChangeGraph(id string) (*ChangeGraph, error) {
+	var graph ChangeGraph
+	if _, err := client.doSync("GET", "/v2/changes/"+id+"/graph", nil, nil, nil, &graph); err != nil {
+		return nil, err
+	}
+	return &graph, nil
+}
+
 type ChangeSelector uint8
 
 func (c ChangeSelector) String() string {
@@ -61,6 +61,47 @@ func (cs *clientSuite) TestClientChange(c *check.C) {
 	})
 }
 
+func (cs *clientSuite) TestClientChangeGraph(c *check.C) {
+	cs.rsp = `{"type": "sync", "result": {
+  "id":   "uno",
+  "kind": "foo",
+  "summary": "...",
+  "status": "Do",
+  "tasks": [
+    {"id": "1", "kind": "bar", "summary": "...", "status": "Done", "lanes": [1]},
+    {"id": "2", "kind": "baz", "summary": "...", "status": "Do", "lanes": [1], "at-time": "2016-04-21T01:02:03Z"},
+    {"id": "3", "kind": "quux", "summary": "...", "status": "Doing", "change": "dos"}
+  ],
+  "edges": [
+    {"from": "1", "to": "2", "kind": "wait"},
+    {"from": "2", "to": "1", "kind": "undo"},
+    {"from": "3", "to": "2", "kind": "wait", "blocking": true}
+  ]
+}}`
+
+	graph, err := cs.cli.ChangeGraph("uno")
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/changes/uno/graph")
+	atTime := time.Date(2016, 04, 21, 1, 2, 3, 0, time.UTC)
+	c.Check(graph, check.DeepEquals, &client.ChangeGraph{
+		ID:      "uno",
+		Kind:    "foo",
+		Summary: "...",
+		Status:  "Do",
+		Tasks: []*client.TaskGraphNode{
+			{ID: "1", Kind: "bar", Summary: "...", Status: "Done", Lanes: []int{1}},
+			{ID: "2", Kind: "baz", Summary: "...", Status: "Do", Lanes: []int{1}, AtTime: &atTime},
+			{ID: "3", Kind: "quux", Summary: "...", Status: "Doing", Change: "dos"},
+		},
+		Edges: []*client.TaskGraphEdge{
+			{From: "1", To: "2", Kind: "wait"},
+			{From: "2", To: "1", Kind: "undo"},
+			{From: "3", To: "2", Kind: "wait", Blocking: true},
+		},
+	})
+}
+
 func (cs *clientSuite) TestClientChangeData(c *check.C) {
 	cs.rsp = `{"type": "sync", "result": {
   "id":   "uno",
@@ -232,3 +273,40 @@ func (cs *clientSuite) TestClientAbort(c *check.C) {
 
 	c.Assert(string(body), check.Equals, "{\"action\":\"abort\"}\n")
 }
+
+func (cs *clientSuite) TestClientPauseResume(c *check.C) {
+	cs.rsp = `{"type": "sync", "result": {
+  "id":   "uno",
+  "kind": "foo",
+  "summary": "...",
+  "status": "Doing",
+  "ready": false,
+  "paused": true,
+  "spawn-time": "2016-04-21T01:02:03Z"
+}}`
+
+	chg, err := cs.cli.Pause("uno")
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/changes/uno")
+	c.Check(chg, check.DeepEquals, &client.Change{
+		ID:      "uno",
+		Kind:    "foo",
+		Summary: "...",
+		Status:  "Doing",
+		Paused:  true,
+
+		SpawnTime: time.Date(2016, 04, 21, 1, 2, 3, 0, time.UTC),
+	})
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	c.Check(string(body), check.Equals, "{\"action\":\"pause\"}\n")
+
+	_, err = cs.cli.Resume("uno")
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/changes/uno")
+	body, err = io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	c.Check(string(body), check.Equals, "{\"action\":\"resume\"}\n")
+}
@@ -670,6 +670,17 @@ type RefreshInfo struct {
 	Last     string `json:"last,omitempty"`
 	Hold     string `json:"hold,omitempty"`
 	Next     string `json:"next,omitempty"`
+	// Snaps contains the refresh information of the snaps which are
+	// refreshed on their own refresh.snap-timers.<snap> schedule.
+	Snaps map[string]*SnapRefreshInfo `json:"snaps,omitempty"`
+}
+
+// SnapRefreshInfo contains information about the refreshes of a snap with
+// its own refresh timer.
+type SnapRefreshInfo struct {
+	Timer string `json:"timer"`
+	Last  string `json:"last,omitempty"`
+	Next  string `json:"next,omitempty"`
 }
 
 // SysInfo holds system information
@@ -122,6 +122,7 @@ func (*cmdSuite) TestClientSnapFromSnapInfo(c *C) {
 		"GatingHold",
 		"RefreshInhibit",
 		"RefreshFailures",
+		"RemovableFrom",
 		"Components",
 	}
 	var checker func(string, reflect.Value)
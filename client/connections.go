@@ -20,6 +20,8 @@
 package client
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/url"
 )
 
@@ -79,3 +81,40 @@ func (client *Client) Connections(opts *ConnectionOptions) (Connections, error)
 	_, err := client.doSync("GET", "/v2/connections", query, nil, nil, &conns)
 	return conns, err
 }
+
+// RefreshConnectionOptions contains the attributes to update when refreshing
+// a connection.
+type RefreshConnectionOptions struct {
+	// PlugAttrs are set as dynamic attributes of the plug side.
+	PlugAttrs map[string]interface{}
+	// SlotAttrs are set as dynamic attributes of the slot side.
+	SlotAttrs map[string]interface{}
+}
+
+type connectionAction struct {
+	Action    string                 `json:"action"`
+	Plug      PlugRef                `json:"plug"`
+	Slot      SlotRef                `json:"slot"`
+	PlugAttrs map[string]interface{} `json:"plug-attrs,omitempty"`
+	SlotAttrs map[string]interface{} `json:"slot-attrs,omitempty"`
+}
+
+// RefreshConnection updates the dynamic attributes of an established
+// connection, re-running the interface hooks and regenerating the security
+// profiles of the snaps without disconnecting them.
+func (client *Client) RefreshConnection(plugSnapName, plugName, slotSnapName, slotName string, opts *RefreshConnectionOptions) (changeID string, err error) {
+	action := &connectionAction{
+		Action: "refresh",
+		Plug:   PlugRef{Snap: plugSnapName, Name: plugName},
+		Slot:   SlotRef{Snap: slotSnapName, Name: slotName},
+	}
+	if opts != nil {
+		action.PlugAttrs = opts.PlugAttrs
+		action.SlotAttrs = opts.SlotAttrs
+	}
+	b, err := json.Marshal(action)
+	if err != nil {
+		return "", err
+	}
+	return client.doAsync("POST", "/v2/connections", nil, nil, bytes.NewReader(b))
+}
@@ -20,6 +20,7 @@
 package client_test
 
 import (
+	"encoding/json"
 	"net/url"
 
 	"gopkg.in/check.v1"
@@ -268,3 +269,36 @@ func (cs *clientSuite) TestClientConnectionsFilter(c *check.C) {
 		"snap":      []string{"foo"},
 	})
 }
+
+func (cs *clientSuite) TestClientRefreshConnection(c *check.C) {
+	cs.status = 202
+	cs.rsp = `{
+		"type": "async",
+		"status-code": 202,
+		"result": { },
+		"change": "foo"
+	}`
+	id, err := cs.cli.RefreshConnection("consumer", "plug", "producer", "slot", &client.RefreshConnectionOptions{
+		PlugAttrs: map[string]interface{}{"path": "/dev/ttyS1"},
+	})
+	c.Assert(err, check.IsNil)
+	c.Check(id, check.Equals, "foo")
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/connections")
+	var body map[string]interface{}
+	c.Assert(json.NewDecoder(cs.req.Body).Decode(&body), check.IsNil)
+	c.Check(body, check.DeepEquals, map[string]interface{}{
+		"action": "refresh",
+		"plug": map[string]interface{}{
+			"snap": "consumer",
+			"plug": "plug",
+		},
+		"slot": map[string]interface{}{
+			"snap": "producer",
+			"slot": "slot",
+		},
+		"plug-attrs": map[string]interface{}{
+			"path": "/dev/ttyS1",
+		},
+	})
+}
@@ -137,7 +137,12 @@ const (
 	// ErrorKindDNSFailure: DNS not responding.
 	ErrorKindDNSFailure ErrorKind = "dns-failure"
 
-	// ErrorKindInsufficientDiskSpace: not enough disk space to perform the request.
+	// ErrorKindInsufficientDiskSpace: not enough disk space to perform the
+	// request. The error `value` is an object with optional fields
+	// `snap-names`, `change-kind`, the estimated `required-space` in bytes
+	// and `space-estimates` breaking it down per snap as a list of
+	// `{"name": ..., "snap": ..., "components": ..., "snapshot": ...,
+	// "total": ...}` objects.
 	ErrorKindInsufficientDiskSpace ErrorKind = "insufficient-disk-space"
 
 	// ErrorKindValidationSetNotFound: validation set cannot be found.
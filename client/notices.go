@@ -17,6 +17,10 @@ package client
 import (
 	"bytes"
 	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type NotifyOptions struct {
@@ -61,4 +65,81 @@ type NoticeType string
 const (
 	// SnapRunInhibitNotice is recorded when "snap run" is inhibited due refresh.
 	SnapRunInhibitNotice NoticeType = "snap-run-inhibit"
+
+	// InterfacesRequestsPromptNotice is recorded when a prompt is added,
+	// updated or resolved. Its key is the prompt ID.
+	InterfacesRequestsPromptNotice NoticeType = "interfaces-requests-prompt"
+
+	// InterfacesRequestsRuleUpdateNotice is recorded when a prompting rule is
+	// added, modified or removed. Its key is the rule ID.
+	InterfacesRequestsRuleUpdateNotice NoticeType = "interfaces-requests-rule-update"
 )
+
+// Notice is a notice recorded by snapd.
+type Notice struct {
+	ID            string            `json:"id"`
+	UserID        *uint32           `json:"user-id"`
+	Type          NoticeType        `json:"type"`
+	Key           string            `json:"key"`
+	FirstOccurred time.Time         `json:"first-occurred"`
+	LastOccurred  time.Time         `json:"last-occurred"`
+	LastRepeated  time.Time         `json:"last-repeated"`
+	Occurrences   int               `json:"occurrences"`
+	LastData      map[string]string `json:"last-data,omitempty"`
+	RepeatAfter   string            `json:"repeat-after,omitempty"`
+	ExpireAfter   string            `json:"expire-after,omitempty"`
+}
+
+// NoticesOptions selects the notices to return.
+type NoticesOptions struct {
+	// Types and Keys restrict the notices to those with one of the given
+	// types and keys, if set.
+	Types []NoticeType
+	Keys  []string
+	// After restricts the notices to those whose last repeat happened after
+	// the given time, if set.
+	After time.Time
+	// Timeout, if set, makes snapd wait up to the given duration for
+	// matching notices to occur if there are none yet.
+	Timeout time.Duration
+	// UserID restricts the notices to those of the given user, if set. Only
+	// admins may set it.
+	UserID *uint32
+}
+
+// Notices returns the notices matching the given options, oldest first.
+func (client *Client) Notices(opts *NoticesOptions) ([]*Notice, error) {
+	q := make(url.Values)
+	var doOpts *doOptions
+	if opts != nil {
+		if len(opts.Types) > 0 {
+			types := make([]string, 0, len(opts.Types))
+			for _, t := range opts.Types {
+				types = append(types, string(t))
+			}
+			q.Set("types", strings.Join(types, ","))
+		}
+		if len(opts.Keys) > 0 {
+			q.Set("keys", strings.Join(opts.Keys, ","))
+		}
+		if !opts.After.IsZero() {
+			q.Set("after", opts.After.Format(time.RFC3339Nano))
+		}
+		if opts.Timeout != 0 {
+			q.Set("timeout", opts.Timeout.String())
+			// the request may legitimately take as long as the given
+			// timeout, so do not let the default one cut it short
+			doOpts = &doOptions{
+				Timeout: opts.Timeout + doTimeout,
+				Retry:   opts.Timeout + doTimeout,
+			}
+		}
+		if opts.UserID != nil {
+			q.Set("user-id", strconv.FormatUint(uint64(*opts.UserID), 10))
+		}
+	}
+
+	var notices []*Notice
+	_, err := client.doSyncWithOpts("GET", "/v2/notices", q, nil, nil, &notices, doOpts)
+	return notices, err
+}
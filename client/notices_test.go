@@ -22,6 +22,8 @@ package client_test
 import (
 	"encoding/json"
 	"io"
+	"net/url"
+	"time"
 
 	"github.com/snapcore/snapd/client"
 	. "gopkg.in/check.v1"
@@ -48,3 +50,57 @@ func (cs *clientSuite) TestNotify(c *C) {
 		"key":    "snap-name",
 	})
 }
+
+func (cs *clientSuite) TestNotices(c *C) {
+	cs.rsp = `{"type": "sync", "result": [{
+		"id": "123",
+		"user-id": 1000,
+		"type": "interfaces-requests-prompt",
+		"key": "0000000000000002",
+		"first-occurred": "2026-03-01T10:00:00Z",
+		"last-occurred": "2026-03-01T10:00:01Z",
+		"last-repeated": "2026-03-01T10:00:01Z",
+		"occurrences": 2,
+		"last-data": {"resolved": "replied"},
+		"expire-after": "168h0m0s"
+	}]}`
+	userID := uint32(1000)
+	after := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	notices, err := cs.cli.Notices(&client.NoticesOptions{
+		Types:   []client.NoticeType{client.InterfacesRequestsPromptNotice, client.InterfacesRequestsRuleUpdateNotice},
+		Keys:    []string{"0000000000000002"},
+		After:   after,
+		Timeout: time.Minute,
+		UserID:  &userID,
+	})
+	c.Assert(err, IsNil)
+	c.Check(cs.req.Method, Equals, "GET")
+	c.Check(cs.req.URL.Path, Equals, "/v2/notices")
+	c.Check(cs.req.URL.Query(), DeepEquals, url.Values{
+		"types":   {"interfaces-requests-prompt,interfaces-requests-rule-update"},
+		"keys":    {"0000000000000002"},
+		"after":   {"2026-03-01T09:00:00Z"},
+		"timeout": {"1m0s"},
+		"user-id": {"1000"},
+	})
+	c.Check(notices, DeepEquals, []*client.Notice{{
+		ID:            "123",
+		UserID:        &userID,
+		Type:          client.InterfacesRequestsPromptNotice,
+		Key:           "0000000000000002",
+		FirstOccurred: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		LastOccurred:  time.Date(2026, 3, 1, 10, 0, 1, 0, time.UTC),
+		LastRepeated:  time.Date(2026, 3, 1, 10, 0, 1, 0, time.UTC),
+		Occurrences:   2,
+		LastData:      map[string]string{"resolved": "replied"},
+		ExpireAfter:   "168h0m0s",
+	}})
+}
+
+func (cs *clientSuite) TestNoticesNoOptions(c *C) {
+	cs.rsp = `{"type": "sync", "result": []}`
+	notices, err := cs.cli.Notices(nil)
+	c.Assert(err, IsNil)
+	c.Check(notices, HasLen, 0)
+	c.Check(cs.req.URL.RawQuery, Equals, "")
+}
@@ -93,6 +93,10 @@ type Snap struct {
 	RefreshInhibit *SnapRefreshInhibit `json:"refresh-inhibit,omitempty"`
 	// RefreshFailures tracks information about snap failed refreshes.
 	RefreshFailures *snap.RefreshFailuresInfo `json:"refresh-failures,omitempty"`
+	// RemovableFrom is the time from which an inactive revision is
+	// eligible for removal on the next refresh of the snap. It is only
+	// set when listing all revisions.
+	RemovableFrom *time.Time `json:"removable-from,omitempty"`
 
 	// Components is a list of the snap components
 	Components []Component `json:"components,omitempty"`
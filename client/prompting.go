@@ -0,0 +1,363 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Prompt is an outstanding request from a snap for permissions which are
+// not yet covered by its interface connections or by prompting rules.
+type Prompt struct {
+	ID          string            `json:"id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Snap        string            `json:"snap"`
+	Interface   string            `json:"interface"`
+	Constraints PromptConstraints `json:"constraints"`
+}
+
+// PromptConstraints describe what a prompt is asking for.
+type PromptConstraints struct {
+	Path                 string   `json:"path"`
+	RequestedPermissions []string `json:"requested-permissions"`
+	AvailablePermissions []string `json:"available-permissions"`
+}
+
+// PromptingRule is a rule which is used to resolve current and future
+// prompts without asking the user.
+type PromptingRule struct {
+	ID          string          `json:"id"`
+	Timestamp   time.Time       `json:"timestamp"`
+	User        uint32          `json:"user"`
+	Snap        string          `json:"snap"`
+	Interface   string          `json:"interface"`
+	Constraints RuleConstraints `json:"constraints"`
+	Outcome     string          `json:"outcome"`
+	Lifespan    string          `json:"lifespan"`
+	Expiration  time.Time       `json:"expiration,omitempty"`
+}
+
+// RuleConstraints describe what a prompting rule, or a reply to a prompt,
+// applies to.
+type RuleConstraints struct {
+	PathPattern string   `json:"path-pattern,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// PromptReply is a reply to a prompt. Unless the lifespan is "single", a
+// rule is created from the reply as well.
+type PromptReply struct {
+	Outcome     string           `json:"action"`
+	Lifespan    string           `json:"lifespan"`
+	Duration    string           `json:"duration,omitempty"`
+	Constraints *RuleConstraints `json:"constraints"`
+}
+
+// AddPromptingRule holds the contents of a new prompting rule.
+type AddPromptingRule struct {
+	Snap        string           `json:"snap"`
+	Interface   string           `json:"interface"`
+	Constraints *RuleConstraints `json:"constraints"`
+	Outcome     string           `json:"outcome"`
+	Lifespan    string           `json:"lifespan"`
+	Duration    string           `json:"duration,omitempty"`
+}
+
+// PatchPromptingRule holds the changes to make to an existing prompting
+// rule. Fields which are left empty are not changed.
+type PatchPromptingRule struct {
+	Constraints *RuleConstraints `json:"constraints,omitempty"`
+	Outcome     string           `json:"outcome,omitempty"`
+	Lifespan    string           `json:"lifespan,omitempty"`
+	Duration    string           `json:"duration,omitempty"`
+}
+
+// PromptingOptions holds options common to all prompting requests.
+type PromptingOptions struct {
+	// UserID selects the user whose prompts and rules are operated on,
+	// instead of the user making the request. Only admins may set it.
+	UserID *uint32
+}
+
+func (opts *PromptingOptions) query() url.Values {
+	q := make(url.Values)
+	if opts != nil && opts.UserID != nil {
+		q.Set("user-id", strconv.FormatUint(uint64(*opts.UserID), 10))
+	}
+	return q
+}
+
+// PromptingRulesOptions selects the prompting rules to return.
+type PromptingRulesOptions struct {
+	PromptingOptions
+	// Snap and Interface restrict the rules to those for the given snap
+	// and interface, if set. Interface may only be set along with Snap.
+	Snap      string
+	Interface string
+}
+
+// Prompts returns the outstanding prompts.
+func (client *Client) Prompts(opts *PromptingOptions) ([]*Prompt, error) {
+	var prompts []*Prompt
+	_, err := client.doSync("GET", "/v2/interfaces/requests/prompts", opts.query(), nil, nil, &prompts)
+	return prompts, err
+}
+
+// Prompt returns the outstanding prompt with the given ID.
+func (client *Client) Prompt(id string, opts *PromptingOptions) (*Prompt, error) {
+	var prompt Prompt
+	_, err := client.doSync("GET", "/v2/interfaces/requests/prompts/"+id, opts.query(), nil, nil, &prompt)
+	if err != nil {
+		return nil, err
+	}
+	return &prompt, nil
+}
+
+// ReplyToPrompt replies to the prompt with the given ID, returning the IDs
+// of all the prompts which were satisfied by the reply.
+func (client *Client) ReplyToPrompt(id string, reply *PromptReply, opts *PromptingOptions) ([]string, error) {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(reply); err != nil {
+		return nil, err
+	}
+	var satisfied []string
+	_, err := client.doSync("POST", "/v2/interfaces/requests/prompts/"+id, opts.query(), nil, &body, &satisfied)
+	return satisfied, err
+}
+
+// PromptingRules returns the prompting rules matching the given options.
+func (client *Client) PromptingRules(opts *PromptingRulesOptions) ([]*PromptingRule, error) {
+	var q url.Values
+	if opts != nil {
+		q = opts.query()
+		if opts.Snap != "" {
+			q.Set("snap", opts.Snap)
+		}
+		if opts.Interface != "" {
+			q.Set("interface", opts.Interface)
+		}
+	}
+	var rules []*PromptingRule
+	_, err := client.doSync("GET", "/v2/interfaces/requests/rules", q, nil, nil, &rules)
+	return rules, err
+}
+
+// PromptingRule returns the prompting rule with the given ID.
+func (client *Client) PromptingRule(id string, opts *PromptingOptions) (*PromptingRule, error) {
+	var rule PromptingRule
+	_, err := client.doSync("GET", "/v2/interfaces/requests/rules/"+id, opts.query(), nil, nil, &rule)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// PromptingRulesExplanation describes how the prompting rules apply to a
+// request for a particular path and permission.
+type PromptingRulesExplanation struct {
+	Snap       string `json:"snap"`
+	Interface  string `json:"interface"`
+	Path       string `json:"path"`
+	Permission string `json:"permission"`
+	// Outcome is the outcome of the first match, if any. If no rule
+	// matches, it is empty and the request results in a prompt.
+	Outcome string `json:"outcome,omitempty"`
+	// Matches holds the path pattern variants of the rules which match the
+	// path, from the highest precedence to the lowest.
+	Matches []*PromptingRulesMatch `json:"matches"`
+}
+
+// PromptingRulesMatch is a path pattern variant which matches the path of an
+// explanation, along with the rules whose path patterns render to it.
+type PromptingRulesMatch struct {
+	Variant string                    `json:"variant"`
+	Outcome string                    `json:"outcome"`
+	Rules   []*ExplainedPromptingRule `json:"rules"`
+}
+
+// ExplainedPromptingRule is a prompting rule which matches the path of an
+// explanation.
+type ExplainedPromptingRule struct {
+	PromptingRule
+	// Remaining is the time left until the rule expires, for rules with a
+	// lifespan of timespan.
+	Remaining string `json:"remaining,omitempty"`
+}
+
+// ExplainPromptingRules returns which of the prompting rules of the given
+// snap and interface apply to a request for the given path and permission.
+func (client *Client) ExplainPromptingRules(snap, iface, path, permission string, opts *PromptingOptions) (*PromptingRulesExplanation, error) {
+	q := opts.query()
+	q.Set("snap", snap)
+	q.Set("interface", iface)
+	q.Set("explain", path)
+	q.Set("permission", permission)
+
+	var explanation PromptingRulesExplanation
+	_, err := client.doSync("GET", "/v2/interfaces/requests/rules", q, nil, nil, &explanation)
+	if err != nil {
+		return nil, err
+	}
+	return &explanation, nil
+}
+
+func (client *Client) postPromptingRules(path string, payload interface{}, opts *PromptingOptions, v interface{}) error {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(payload); err != nil {
+		return err
+	}
+	_, err := client.doSync("POST", path, opts.query(), nil, &body, v)
+	return err
+}
+
+// AddPromptingRule adds a new prompting rule and returns it.
+func (client *Client) AddPromptingRule(rule *AddPromptingRule, opts *PromptingOptions) (*PromptingRule, error) {
+	payload := struct {
+		Action string            `json:"action"`
+		Rule   *AddPromptingRule `json:"rule"`
+	}{
+		Action: "add",
+		Rule:   rule,
+	}
+	var added PromptingRule
+	if err := client.postPromptingRules("/v2/interfaces/requests/rules", &payload, opts, &added); err != nil {
+		return nil, err
+	}
+	return &added, nil
+}
+
+// RemovePromptingRules removes all the prompting rules for the given snap
+// and, if set, interface, and returns the removed rules.
+func (client *Client) RemovePromptingRules(snap, iface string, opts *PromptingOptions) ([]*PromptingRule, error) {
+	type selector struct {
+		Snap      string `json:"snap"`
+		Interface string `json:"interface,omitempty"`
+	}
+	payload := struct {
+		Action   string    `json:"action"`
+		Selector *selector `json:"selector"`
+	}{
+		Action:   "remove",
+		Selector: &selector{Snap: snap, Interface: iface},
+	}
+	var removed []*PromptingRule
+	if err := client.postPromptingRules("/v2/interfaces/requests/rules", &payload, opts, &removed); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// PatchPromptingRule modifies the prompting rule with the given ID and
+// returns the modified rule.
+func (client *Client) PatchPromptingRule(id string, patch *PatchPromptingRule, opts *PromptingOptions) (*PromptingRule, error) {
+	payload := struct {
+		Action string              `json:"action"`
+		Rule   *PatchPromptingRule `json:"rule"`
+	}{
+		Action: "patch",
+		Rule:   patch,
+	}
+	var patched PromptingRule
+	if err := client.postPromptingRules("/v2/interfaces/requests/rules/"+id, &payload, opts, &patched); err != nil {
+		return nil, err
+	}
+	return &patched, nil
+}
+
+// RemovePromptingRule removes the prompting rule with the given ID and
+// returns the removed rule.
+func (client *Client) RemovePromptingRule(id string, opts *PromptingOptions) (*PromptingRule, error) {
+	payload := struct {
+		Action string `json:"action"`
+	}{
+		Action: "remove",
+	}
+	var removed PromptingRule
+	if err := client.postPromptingRules("/v2/interfaces/requests/rules/"+id, &payload, opts, &removed); err != nil {
+		return nil, err
+	}
+	return &removed, nil
+}
+
+// PromptingPolicy is a portable set of prompting rules, which may be
+// exported for one user and imported for another, possibly on another
+// system.
+type PromptingPolicy struct {
+	Version int                    `json:"version" yaml:"version"`
+	Rules   []*PromptingPolicyRule `json:"rules" yaml:"rules"`
+}
+
+// PromptingPolicyRule holds the contents of a prompting rule in a policy.
+// Path patterns beginning with "~/" are relative to the home directory of
+// the user for whom the policy is imported.
+type PromptingPolicyRule struct {
+	Snap        string   `json:"snap" yaml:"snap"`
+	Interface   string   `json:"interface" yaml:"interface"`
+	PathPattern string   `json:"path-pattern" yaml:"path-pattern"`
+	Permissions []string `json:"permissions" yaml:"permissions"`
+	Outcome     string   `json:"outcome" yaml:"outcome"`
+	Lifespan    string   `json:"lifespan,omitempty" yaml:"lifespan,omitempty"`
+	Duration    string   `json:"duration,omitempty" yaml:"duration,omitempty"`
+}
+
+// PromptingPolicyImport holds the prompting rules which were added and
+// removed by importing a policy.
+type PromptingPolicyImport struct {
+	Added   []*PromptingRule `json:"added"`
+	Removed []*PromptingRule `json:"removed"`
+}
+
+// ExportPromptingPolicy returns the prompting rules which have a lifespan
+// of "forever" as a policy.
+func (client *Client) ExportPromptingPolicy(opts *PromptingOptions) (*PromptingPolicy, error) {
+	var policy PromptingPolicy
+	_, err := client.doSync("GET", "/v2/interfaces/requests/policy", opts.query(), nil, nil, &policy)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// ImportPromptingPolicy adds the prompting rules of the given policy. If
+// replace is true, all existing prompting rules are removed first, otherwise
+// the policy is merged with the existing rules. Either way, the import is
+// atomic: if any rule is invalid or conflicts with another, nothing changes.
+func (client *Client) ImportPromptingPolicy(policy *PromptingPolicy, replace bool, opts *PromptingOptions) (*PromptingPolicyImport, error) {
+	action := "merge"
+	if replace {
+		action = "replace"
+	}
+	payload := struct {
+		Action string           `json:"action"`
+		Policy *PromptingPolicy `json:"policy"`
+	}{
+		Action: action,
+		Policy: policy,
+	}
+	var result PromptingPolicyImport
+	if err := client.postPromptingRules("/v2/interfaces/requests/policy", &payload, opts, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
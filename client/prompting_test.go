@@ -0,0 +1,473 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+)
+
+func (cs *clientSuite) TestClientPrompts(c *check.C) {
+	cs.rsp = `{
+		"result": [
+		    {
+			"id": "0000000000000002",
+			"timestamp": "2026-03-01T10:00:00Z",
+			"snap": "firefox",
+			"interface": "home",
+			"constraints": {
+			    "path": "/home/test/Downloads/foo.txt",
+			    "requested-permissions": ["write"],
+			    "available-permissions": ["read", "write", "execute"]
+			}
+		    }
+		],
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	userID := uint32(1000)
+	prompts, err := cs.cli.Prompts(&client.PromptingOptions{UserID: &userID})
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/prompts")
+	c.Check(cs.req.URL.Query().Get("user-id"), check.Equals, "1000")
+	c.Check(prompts, check.DeepEquals, []*client.Prompt{
+		{
+			ID:        "0000000000000002",
+			Timestamp: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+			Snap:      "firefox",
+			Interface: "home",
+			Constraints: client.PromptConstraints{
+				Path:                 "/home/test/Downloads/foo.txt",
+				RequestedPermissions: []string{"write"},
+				AvailablePermissions: []string{"read", "write", "execute"},
+			},
+		},
+	})
+}
+
+func (cs *clientSuite) TestClientPrompt(c *check.C) {
+	cs.rsp = `{
+		"result": {
+		    "id": "0000000000000002",
+		    "timestamp": "2026-03-01T10:00:00Z",
+		    "snap": "firefox",
+		    "interface": "camera",
+		    "constraints": {
+			"path": "/dev/video0",
+			"requested-permissions": ["access"],
+			"available-permissions": ["access"]
+		    }
+		},
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	prompt, err := cs.cli.Prompt("0000000000000002", nil)
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/prompts/0000000000000002")
+	c.Check(cs.req.URL.RawQuery, check.Equals, "")
+	c.Check(prompt.Interface, check.Equals, "camera")
+	c.Check(prompt.Constraints.Path, check.Equals, "/dev/video0")
+}
+
+func (cs *clientSuite) TestClientPromptError(c *check.C) {
+	cs.status = 404
+	cs.rsp = `{
+		"result": {"message": "cannot find prompt with the given ID for the given user"},
+		"status": "Not Found",
+		"status-code": 404,
+		"type": "error"
+	}`
+
+	_, err := cs.cli.Prompt("0000000000000002", nil)
+	c.Check(err, check.ErrorMatches, "cannot find prompt with the given ID for the given user")
+}
+
+func (cs *clientSuite) TestClientReplyToPrompt(c *check.C) {
+	cs.rsp = `{
+		"result": ["0000000000000002", "0000000000000003"],
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	satisfied, err := cs.cli.ReplyToPrompt("0000000000000002", &client.PromptReply{
+		Outcome:  "allow",
+		Lifespan: "timespan",
+		Duration: "10m",
+		Constraints: &client.RuleConstraints{
+			PathPattern: "/home/test/Downloads/**",
+			Permissions: []string{"read", "write"},
+		},
+	}, nil)
+	c.Assert(err, check.IsNil)
+	c.Check(satisfied, check.DeepEquals, []string{"0000000000000002", "0000000000000003"})
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/prompts/0000000000000002")
+
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var m map[string]interface{}
+	c.Assert(json.Unmarshal(body, &m), check.IsNil)
+	c.Check(m, check.DeepEquals, map[string]interface{}{
+		"action":   "allow",
+		"lifespan": "timespan",
+		"duration": "10m",
+		"constraints": map[string]interface{}{
+			"path-pattern": "/home/test/Downloads/**",
+			"permissions":  []interface{}{"read", "write"},
+		},
+	})
+}
+
+const promptingRuleJSON = `{
+	"id": "000000000000000A",
+	"timestamp": "2026-03-01T10:00:00Z",
+	"user": 1000,
+	"snap": "firefox",
+	"interface": "home",
+	"constraints": {
+	    "path-pattern": "/home/test/Downloads/**",
+	    "permissions": ["read", "write"]
+	},
+	"outcome": "allow",
+	"lifespan": "timespan",
+	"expiration": "2026-03-01T10:10:00Z"
+}`
+
+var promptingRule = &client.PromptingRule{
+	ID:        "000000000000000A",
+	Timestamp: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	User:      1000,
+	Snap:      "firefox",
+	Interface: "home",
+	Constraints: client.RuleConstraints{
+		PathPattern: "/home/test/Downloads/**",
+		Permissions: []string{"read", "write"},
+	},
+	Outcome:    "allow",
+	Lifespan:   "timespan",
+	Expiration: time.Date(2026, 3, 1, 10, 10, 0, 0, time.UTC),
+}
+
+func (cs *clientSuite) TestClientPromptingRules(c *check.C) {
+	cs.rsp = `{
+		"result": [` + promptingRuleJSON + `],
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	userID := uint32(1000)
+	rules, err := cs.cli.PromptingRules(&client.PromptingRulesOptions{
+		PromptingOptions: client.PromptingOptions{UserID: &userID},
+		Snap:             "firefox",
+		Interface:        "home",
+	})
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/rules")
+	q := cs.req.URL.Query()
+	c.Check(q.Get("user-id"), check.Equals, "1000")
+	c.Check(q.Get("snap"), check.Equals, "firefox")
+	c.Check(q.Get("interface"), check.Equals, "home")
+	c.Check(rules, check.DeepEquals, []*client.PromptingRule{promptingRule})
+}
+
+func (cs *clientSuite) TestClientPromptingRule(c *check.C) {
+	cs.rsp = `{
+		"result": ` + promptingRuleJSON + `,
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	rule, err := cs.cli.PromptingRule("000000000000000A", nil)
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/rules/000000000000000A")
+	c.Check(rule, check.DeepEquals, promptingRule)
+}
+
+func (cs *clientSuite) checkPromptingRulesBody(c *check.C, expected map[string]interface{}) {
+	c.Check(cs.req.Method, check.Equals, "POST")
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var m map[string]interface{}
+	c.Assert(json.Unmarshal(body, &m), check.IsNil)
+	c.Check(m, check.DeepEquals, expected)
+}
+
+func (cs *clientSuite) TestClientAddPromptingRule(c *check.C) {
+	cs.rsp = `{
+		"result": ` + promptingRuleJSON + `,
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	rule, err := cs.cli.AddPromptingRule(&client.AddPromptingRule{
+		Snap:      "firefox",
+		Interface: "home",
+		Constraints: &client.RuleConstraints{
+			PathPattern: "/home/test/Downloads/**",
+			Permissions: []string{"read", "write"},
+		},
+		Outcome:  "allow",
+		Lifespan: "timespan",
+		Duration: "10m",
+	}, nil)
+	c.Assert(err, check.IsNil)
+	c.Check(rule, check.DeepEquals, promptingRule)
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/rules")
+	cs.checkPromptingRulesBody(c, map[string]interface{}{
+		"action": "add",
+		"rule": map[string]interface{}{
+			"snap":      "firefox",
+			"interface": "home",
+			"constraints": map[string]interface{}{
+				"path-pattern": "/home/test/Downloads/**",
+				"permissions":  []interface{}{"read", "write"},
+			},
+			"outcome":  "allow",
+			"lifespan": "timespan",
+			"duration": "10m",
+		},
+	})
+}
+
+func (cs *clientSuite) TestClientRemovePromptingRules(c *check.C) {
+	cs.rsp = `{
+		"result": [` + promptingRuleJSON + `],
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	rules, err := cs.cli.RemovePromptingRules("firefox", "", nil)
+	c.Assert(err, check.IsNil)
+	c.Check(rules, check.DeepEquals, []*client.PromptingRule{promptingRule})
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/rules")
+	cs.checkPromptingRulesBody(c, map[string]interface{}{
+		"action": "remove",
+		"selector": map[string]interface{}{
+			"snap": "firefox",
+		},
+	})
+}
+
+func (cs *clientSuite) TestClientPatchPromptingRule(c *check.C) {
+	cs.rsp = `{
+		"result": ` + promptingRuleJSON + `,
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	rule, err := cs.cli.PatchPromptingRule("000000000000000A", &client.PatchPromptingRule{
+		Outcome: "deny",
+	}, nil)
+	c.Assert(err, check.IsNil)
+	c.Check(rule, check.DeepEquals, promptingRule)
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/rules/000000000000000A")
+	cs.checkPromptingRulesBody(c, map[string]interface{}{
+		"action": "patch",
+		"rule": map[string]interface{}{
+			"outcome": "deny",
+		},
+	})
+}
+
+func (cs *clientSuite) TestClientRemovePromptingRule(c *check.C) {
+	cs.rsp = `{
+		"result": ` + promptingRuleJSON + `,
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	userID := uint32(1000)
+	rule, err := cs.cli.RemovePromptingRule("000000000000000A", &client.PromptingOptions{UserID: &userID})
+	c.Assert(err, check.IsNil)
+	c.Check(rule, check.DeepEquals, promptingRule)
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/rules/000000000000000A")
+	c.Check(cs.req.URL.Query().Get("user-id"), check.Equals, "1000")
+	cs.checkPromptingRulesBody(c, map[string]interface{}{
+		"action": "remove",
+	})
+}
+
+func (cs *clientSuite) TestClientExplainPromptingRules(c *check.C) {
+	cs.rsp = `{
+		"result": {
+		    "snap": "firefox",
+		    "interface": "home",
+		    "path": "/home/test/Downloads/foo.txt",
+		    "permission": "read",
+		    "outcome": "allow",
+		    "matches": [
+			{
+			    "variant": "/home/test/Downloads/**",
+			    "outcome": "allow",
+			    "rules": [` + promptingRuleJSON + `]
+			}
+		    ]
+		},
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+	cs.rsp = strings.Replace(cs.rsp, `"expiration": "2026-03-01T10:10:00Z"`, `"expiration": "2026-03-01T10:10:00Z", "remaining": "9m59s"`, 1)
+
+	userID := uint32(1000)
+	explanation, err := cs.cli.ExplainPromptingRules("firefox", "home", "/home/test/Downloads/foo.txt", "read", &client.PromptingOptions{UserID: &userID})
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/rules")
+	c.Check(cs.req.URL.Query(), check.DeepEquals, url.Values{
+		"snap":       {"firefox"},
+		"interface":  {"home"},
+		"explain":    {"/home/test/Downloads/foo.txt"},
+		"permission": {"read"},
+		"user-id":    {"1000"},
+	})
+	c.Check(explanation, check.DeepEquals, &client.PromptingRulesExplanation{
+		Snap:       "firefox",
+		Interface:  "home",
+		Path:       "/home/test/Downloads/foo.txt",
+		Permission: "read",
+		Outcome:    "allow",
+		Matches: []*client.PromptingRulesMatch{{
+			Variant: "/home/test/Downloads/**",
+			Outcome: "allow",
+			Rules: []*client.ExplainedPromptingRule{{
+				PromptingRule: *promptingRule,
+				Remaining:     "9m59s",
+			}},
+		}},
+	})
+}
+
+func (cs *clientSuite) TestClientExportPromptingPolicy(c *check.C) {
+	cs.rsp = `{
+		"result": {
+		    "version": 1,
+		    "rules": [
+			{
+			    "snap": "libreoffice",
+			    "interface": "home",
+			    "path-pattern": "~/Documents/**",
+			    "permissions": ["read"],
+			    "outcome": "allow",
+			    "lifespan": "forever"
+			}
+		    ]
+		},
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	userID := uint32(1000)
+	policy, err := cs.cli.ExportPromptingPolicy(&client.PromptingOptions{UserID: &userID})
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/policy")
+	c.Check(cs.req.URL.Query().Get("user-id"), check.Equals, "1000")
+	c.Check(policy, check.DeepEquals, &client.PromptingPolicy{
+		Version: 1,
+		Rules: []*client.PromptingPolicyRule{
+			{
+				Snap:        "libreoffice",
+				Interface:   "home",
+				PathPattern: "~/Documents/**",
+				Permissions: []string{"read"},
+				Outcome:     "allow",
+				Lifespan:    "forever",
+			},
+		},
+	})
+}
+
+func (cs *clientSuite) TestClientImportPromptingPolicy(c *check.C) {
+	policy := &client.PromptingPolicy{
+		Version: 1,
+		Rules: []*client.PromptingPolicyRule{
+			{
+				Snap:        "libreoffice",
+				Interface:   "home",
+				PathPattern: "~/Documents/**",
+				Permissions: []string{"read"},
+				Outcome:     "allow",
+			},
+		},
+	}
+	expectedPolicy := map[string]interface{}{
+		"version": float64(1),
+		"rules": []interface{}{
+			map[string]interface{}{
+				"snap":         "libreoffice",
+				"interface":    "home",
+				"path-pattern": "~/Documents/**",
+				"permissions":  []interface{}{"read"},
+				"outcome":      "allow",
+			},
+		},
+	}
+
+	for _, replace := range []bool{false, true} {
+		cs.rsp = `{
+			"result": {
+			    "added": [` + promptingRuleJSON + `],
+			    "removed": []
+			},
+			"status": "OK",
+			"status-code": 200,
+			"type": "sync"
+		}`
+
+		result, err := cs.cli.ImportPromptingPolicy(policy, replace, nil)
+		c.Assert(err, check.IsNil)
+		c.Check(result, check.DeepEquals, &client.PromptingPolicyImport{
+			Added:   []*client.PromptingRule{promptingRule},
+			Removed: []*client.PromptingRule{},
+		})
+		c.Check(cs.req.URL.Path, check.Equals, "/v2/interfaces/requests/policy")
+		action := "merge"
+		if replace {
+			action = "replace"
+		}
+		cs.checkPromptingRulesBody(c, map[string]interface{}{
+			"action": action,
+			"policy": expectedPolicy,
+		})
+	}
+}
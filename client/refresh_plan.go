@@ -0,0 +1,106 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/snapcore/snapd/snap"
+)
+
+// RefreshPlanItem describes how a single snap would be refreshed.
+type RefreshPlanItem struct {
+	Name            string        `json:"name"`
+	Type            snap.Type     `json:"type,omitempty"`
+	CurrentRevision snap.Revision `json:"current-revision"`
+	CurrentChannel  string        `json:"current-channel,omitempty"`
+	Revision        snap.Revision `json:"revision"`
+	Channel         string        `json:"channel,omitempty"`
+	Version         string        `json:"version,omitempty"`
+	DownloadSize    int64         `json:"download-size,omitempty"`
+	// Components are installed or refreshed together with the snap.
+	Components []string `json:"components,omitempty"`
+	// Prerequisites are snaps which would be installed as well.
+	Prerequisites []string `json:"prerequisites,omitempty"`
+	NeedsReboot   bool     `json:"needs-reboot,omitempty"`
+	// ValidationSets are the enforced validation sets which constrain the
+	// revision of the snap.
+	ValidationSets []string `json:"validation-sets,omitempty"`
+	// RequiredSpace is the disk space the refresh is estimated to need,
+	// in bytes.
+	RequiredSpace uint64 `json:"required-space,omitempty"`
+}
+
+// RefreshPlan describes what refreshing snaps would do.
+type RefreshPlan struct {
+	Refreshes []*RefreshPlanItem `json:"refreshes"`
+	// Held maps the snaps which have their auto-refreshes held to the
+	// snaps holding them, or "system" if held by the user.
+	Held map[string][]string `json:"held,omitempty"`
+	// RequiredSpace is the disk space all the refreshes are estimated to
+	// need, in bytes.
+	RequiredSpace uint64 `json:"required-space,omitempty"`
+}
+
+// RefreshPlan returns what refreshing the given snaps, or all snaps if
+// none are given, would do, without actually refreshing anything.
+func (client *Client) RefreshPlan(names []string, components map[string][]string, options *SnapOptions) (*RefreshPlan, error) {
+	if options == nil {
+		options = &SnapOptions{}
+	}
+
+	var path string
+	var action interface{}
+	if len(names) == 1 {
+		path = "/v2/snaps/" + names[0]
+		action = &actionData{
+			Action:      "refresh",
+			Components:  components[names[0]],
+			DryRun:      true,
+			SnapOptions: options,
+		}
+	} else {
+		path = "/v2/snaps"
+		action = &multiActionData{
+			Action:        "refresh",
+			Snaps:         names,
+			Components:    components,
+			Transaction:   options.Transaction,
+			IgnoreRunning: options.IgnoreRunning,
+			DryRun:        true,
+		}
+	}
+	data, err := json.Marshal(action)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal snap action: %s", err)
+	}
+
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+
+	var plan RefreshPlan
+	if _, err := client.doSync("POST", path, nil, headers, bytes.NewBuffer(data), &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
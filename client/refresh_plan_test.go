@@ -0,0 +1,114 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"encoding/json"
+	"io"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/snap"
+)
+
+func (cs *clientSuite) TestClientRefreshPlanMany(c *check.C) {
+	cs.rsp = `{
+		"result": {
+		    "refreshes": [
+			{
+			    "name": "foo",
+			    "type": "kernel",
+			    "current-revision": "5",
+			    "current-channel": "latest/stable",
+			    "revision": "7",
+			    "channel": "latest/stable",
+			    "version": "2.0",
+			    "download-size": 1024,
+			    "components": ["comp"],
+			    "prerequisites": ["content-provider"],
+			    "needs-reboot": true,
+			    "validation-sets": ["acme/base"],
+			    "required-space": 2048
+			}
+		    ],
+		    "held": {"bar": ["system"]},
+		    "required-space": 2048
+		},
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	plan, err := cs.cli.RefreshPlan([]string{"foo", "bar"}, nil, &client.SnapOptions{IgnoreRunning: true})
+	c.Assert(err, check.IsNil)
+	c.Check(plan, check.DeepEquals, &client.RefreshPlan{
+		Refreshes: []*client.RefreshPlanItem{{
+			Name:            "foo",
+			Type:            snap.TypeKernel,
+			CurrentRevision: snap.R(5),
+			CurrentChannel:  "latest/stable",
+			Revision:        snap.R(7),
+			Channel:         "latest/stable",
+			Version:         "2.0",
+			DownloadSize:    1024,
+			Components:      []string{"comp"},
+			Prerequisites:   []string{"content-provider"},
+			NeedsReboot:     true,
+			ValidationSets:  []string{"acme/base"},
+			RequiredSpace:   2048,
+		}},
+		Held:          map[string][]string{"bar": {"system"}},
+		RequiredSpace: 2048,
+	})
+
+	c.Check(cs.req.Method, check.Equals, "POST")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/snaps")
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var jsonBody map[string]interface{}
+	c.Assert(json.Unmarshal(body, &jsonBody), check.IsNil)
+	c.Check(jsonBody, check.DeepEquals, map[string]interface{}{
+		"action":         "refresh",
+		"snaps":          []interface{}{"foo", "bar"},
+		"ignore-running": true,
+		"dry-run":        true,
+	})
+}
+
+func (cs *clientSuite) TestClientRefreshPlanOne(c *check.C) {
+	cs.rsp = `{"result": {"refreshes": []}, "status": "OK", "status-code": 200, "type": "sync"}`
+
+	plan, err := cs.cli.RefreshPlan([]string{"foo"}, map[string][]string{"foo": {"comp"}}, &client.SnapOptions{Channel: "edge"})
+	c.Assert(err, check.IsNil)
+	c.Check(plan.Refreshes, check.HasLen, 0)
+
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/snaps/foo")
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+	var jsonBody map[string]interface{}
+	c.Assert(json.Unmarshal(body, &jsonBody), check.IsNil)
+	c.Check(jsonBody, check.DeepEquals, map[string]interface{}{
+		"action":     "refresh",
+		"channel":    "edge",
+		"components": []interface{}{"comp"},
+		"dry-run":    true,
+	})
+}
@@ -0,0 +1,76 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// SnapExportMediaType is the media type of exported snaps in the API.
+const SnapExportMediaType = "application/x-tar"
+
+// SnapExportOptions holds the options for exporting a snap.
+type SnapExportOptions struct {
+	// Revision is the revision of the snap to export, the current one
+	// if empty.
+	Revision string
+}
+
+// SnapExport exports an installed snap revision, together with its
+// components and the assertions needed to install them on another device,
+// as a tar archive.
+func (client *Client) SnapExport(name string, opts *SnapExportOptions) (stream io.ReadCloser, err error) {
+	if opts == nil {
+		opts = &SnapExportOptions{}
+	}
+	q := url.Values{}
+	if opts.Revision != "" {
+		q.Set("revision", opts.Revision)
+	}
+
+	rsp, err := client.raw(context.Background(), "GET", fmt.Sprintf("/v2/snaps/%s/export", name), q, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode != 200 {
+		defer rsp.Body.Close()
+
+		var r response
+		dec := json.NewDecoder(rsp.Body)
+		if err := dec.Decode(&r); err == nil {
+			specificErr := r.err(client, rsp.StatusCode)
+			if specificErr != nil {
+				return nil, specificErr
+			}
+		}
+		return nil, fmt.Errorf("unexpected status code: %v", rsp.Status)
+	}
+	contentType := rsp.Header.Get("Content-Type")
+	if contentType != SnapExportMediaType {
+		rsp.Body.Close()
+		return nil, fmt.Errorf("unexpected snap export content type %q", contentType)
+	}
+
+	return rsp.Body, nil
+}
@@ -0,0 +1,69 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"io"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+)
+
+func (cs *clientSuite) TestClientSnapExport(c *check.C) {
+	cs.header = http.Header{"Content-Type": []string{client.SnapExportMediaType}}
+	cs.rsp = "tar-data"
+
+	r, err := cs.cli.SnapExport("foo", &client.SnapExportOptions{Revision: "5"})
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/snaps/foo/export")
+	c.Check(cs.req.URL.Query().Get("revision"), check.Equals, "5")
+
+	buf, err := io.ReadAll(r)
+	c.Assert(err, check.IsNil)
+	c.Check(string(buf), check.Equals, "tar-data")
+	c.Check(cs.countingCloser.closeCalled, check.Equals, 0)
+}
+
+func (cs *clientSuite) TestClientSnapExportCurrent(c *check.C) {
+	cs.header = http.Header{"Content-Type": []string{client.SnapExportMediaType}}
+	cs.rsp = "tar-data"
+
+	_, err := cs.cli.SnapExport("foo", nil)
+	c.Assert(err, check.IsNil)
+	c.Check(cs.req.URL.RawQuery, check.Equals, "")
+}
+
+func (cs *clientSuite) TestClientSnapExportErrors(c *check.C) {
+	cs.rsp = `{"type":"error","status-code":400,"result":{"message":"cannot export snap \"foo\": boom"}}`
+	cs.status = 400
+	cs.header = http.Header{"Content-Type": []string{"application/json"}}
+	_, err := cs.cli.SnapExport("foo", nil)
+	c.Check(err, check.ErrorMatches, `cannot export snap "foo": boom`)
+
+	cs.rsp = "data"
+	cs.status = 200
+	cs.header = http.Header{"Content-Type": []string{"text/plain"}}
+	_, err = cs.cli.SnapExport("foo", nil)
+	c.Check(err, check.ErrorMatches, `unexpected snap export content type "text/plain"`)
+	c.Check(cs.countingCloser.closeCalled, check.Equals, 1)
+}
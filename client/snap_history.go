@@ -0,0 +1,54 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// SnapHistoryEntry records an operation carried out on a snap.
+type SnapHistoryEntry struct {
+	// Action is one of install, refresh, revert, switch or remove.
+	Action string `json:"action"`
+	// FromRevision is empty for installs, ToRevision for removals.
+	FromRevision string    `json:"from-revision,omitempty"`
+	ToRevision   string    `json:"to-revision,omitempty"`
+	Channel      string    `json:"channel,omitempty"`
+	Time         time.Time `json:"time"`
+	// Trigger is one of manual, auto, validation-set, remodel or seed.
+	Trigger string `json:"trigger"`
+	// Outcome is either succeeded or failed.
+	Outcome  string `json:"outcome"`
+	ChangeID string `json:"change-id"`
+}
+
+// SnapHistory returns the recorded operations on the given snap, oldest
+// first. The history is kept after the snap is removed.
+func (client *Client) SnapHistory(name string) ([]*SnapHistoryEntry, error) {
+	var history []*SnapHistoryEntry
+	path := fmt.Sprintf("/v2/snaps/%s/history", name)
+	if _, err := client.doSync("GET", path, nil, nil, nil, &history); err != nil {
+		return nil, xerrors.Errorf("cannot get history of snap %q: %w", name, err)
+	}
+	return history, nil
+}
@@ -0,0 +1,99 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client_test
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+)
+
+func (cs *clientSuite) TestClientSnapHistory(c *check.C) {
+	cs.rsp = `{
+		"result": [
+		    {
+			"action": "install",
+			"to-revision": "7",
+			"channel": "latest/stable",
+			"time": "2026-03-01T10:00:00Z",
+			"trigger": "manual",
+			"outcome": "succeeded",
+			"change-id": "1"
+		    },
+		    {
+			"action": "refresh",
+			"from-revision": "7",
+			"to-revision": "8",
+			"channel": "latest/stable",
+			"time": "2026-03-02T10:00:00Z",
+			"trigger": "auto",
+			"outcome": "failed",
+			"change-id": "2"
+		    }
+		],
+		"status": "OK",
+		"status-code": 200,
+		"type": "sync"
+	}`
+
+	history, err := cs.cli.SnapHistory("foo")
+	c.Assert(err, check.IsNil)
+	c.Check(history, check.DeepEquals, []*client.SnapHistoryEntry{
+		{
+			Action:     "install",
+			ToRevision: "7",
+			Channel:    "latest/stable",
+			Time:       time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+			Trigger:    "manual",
+			Outcome:    "succeeded",
+			ChangeID:   "1",
+		},
+		{
+			Action:       "refresh",
+			FromRevision: "7",
+			ToRevision:   "8",
+			Channel:      "latest/stable",
+			Time:         time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+			Trigger:      "auto",
+			Outcome:      "failed",
+			ChangeID:     "2",
+		},
+	})
+	c.Check(cs.req.Method, check.Equals, "GET")
+	c.Check(cs.req.URL.Path, check.Equals, "/v2/snaps/foo/history")
+}
+
+func (cs *clientSuite) TestClientSnapHistoryError(c *check.C) {
+	cs.status = 404
+	cs.rsp = `{
+		"result": {"message": "snap not installed", "kind": "snap-not-found", "value": "foo"},
+		"status-code": 404,
+		"type": "error"
+	}`
+
+	_, err := cs.cli.SnapHistory("foo")
+	c.Check(err, check.ErrorMatches, `cannot get history of snap "foo": snap not installed`)
+	var e *client.Error
+	c.Assert(xerrors.As(err, &e), check.Equals, true)
+	c.Check(e.Kind, check.Equals, client.ErrorKindSnapNotFound)
+}
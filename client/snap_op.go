@@ -64,6 +64,7 @@ type SnapOptions struct {
 	Time             string          `json:"time,omitempty"`
 	HoldLevel        string          `json:"hold-level,omitempty"`
 	Users            []string        `json:"users,omitempty"`
+	At               string          `json:"at,omitempty"`
 }
 
 func writeFieldBool(mw *multipart.Writer, key string, val bool) error {
@@ -122,6 +123,7 @@ type actionData struct {
 	Name       string   `json:"name,omitempty"`
 	SnapPath   string   `json:"snap-path,omitempty"`
 	Components []string `json:"components,omitempty"`
+	DryRun     bool     `json:"dry-run,omitempty"`
 	*SnapOptions
 }
 
@@ -135,6 +137,8 @@ type multiActionData struct {
 	ValidationSets []string            `json:"validation-sets,omitempty"`
 	Time           string              `json:"time,omitempty"`
 	HoldLevel      string              `json:"hold-level,omitempty"`
+	At             string              `json:"at,omitempty"`
+	DryRun         bool                `json:"dry-run,omitempty"`
 	Components     map[string][]string `json:"components,omitempty"`
 }
 
@@ -269,6 +273,7 @@ func (client *Client) doMultiSnapActionFull(actionName string, snaps []string, c
 		action.ValidationSets = options.ValidationSets
 		action.Time = options.Time
 		action.HoldLevel = options.HoldLevel
+		action.At = options.At
 	}
 
 	data, err := json.Marshal(&action)
@@ -298,37 +303,63 @@ func (client *Client) InstallPath(path, name string, options *SnapOptions) (chan
 		SnapOptions: options,
 	}
 
-	return client.sendLocalSnaps([]string{path}, []*os.File{f}, action)
+	return client.sendLocalSnaps([]string{path}, []*os.File{f}, nil, nil, action)
 }
 
 // InstallPathMany sideloads the snaps with the given paths,
 // returning the UUID of the background operation upon success.
 func (client *Client) InstallPathMany(paths []string, options *SnapOptions) (changeID string, err error) {
+	return client.InstallPathManyWithAssertions(paths, nil, options)
+}
+
+// InstallPathManyWithAssertions sideloads the snaps with the given paths
+// together with the assertion streams in the files with the given paths,
+// which are added to the system assertion database first so that the snaps
+// can be installed as asserted revisions. It returns the UUID of the
+// background operation upon success.
+func (client *Client) InstallPathManyWithAssertions(paths, assertPaths []string, options *SnapOptions) (changeID string, err error) {
 	action := actionData{
 		Action:      "install",
 		SnapOptions: options,
 	}
 
+	files, err := openFiles(paths)
+	if err != nil {
+		return "", err
+	}
+	assertFiles, err := openFiles(assertPaths)
+	if err != nil {
+		closeFiles(files)
+		return "", err
+	}
+
+	return client.sendLocalSnaps(paths, files, assertPaths, assertFiles, action)
+}
+
+func openFiles(paths []string) ([]*os.File, error) {
 	var files []*os.File
 	for _, path := range paths {
 		f, err := os.Open(path)
 		if err != nil {
-			for _, openFile := range files {
-				openFile.Close()
-			}
-			return "", fmt.Errorf("cannot open %q: %w", path, err)
+			closeFiles(files)
+			return nil, fmt.Errorf("cannot open %q: %w", path, err)
 		}
 
 		files = append(files, f)
 	}
+	return files, nil
+}
 
-	return client.sendLocalSnaps(paths, files, action)
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
 }
 
-func (client *Client) sendLocalSnaps(paths []string, files []*os.File, action actionData) (string, error) {
+func (client *Client) sendLocalSnaps(paths []string, files []*os.File, assertPaths []string, assertFiles []*os.File, action actionData) (string, error) {
 	pr, pw := io.Pipe()
 	mw := multipart.NewWriter(pw)
-	go sendSnapFiles(paths, files, pw, mw, &action)
+	go sendSnapFiles(paths, files, assertPaths, assertFiles, pw, mw, &action)
 
 	headers := map[string]string{
 		"Content-Type": mw.FormDataContentType(),
@@ -361,11 +392,10 @@ func (client *Client) Try(path string, options *SnapOptions) (changeID string, e
 	return client.doAsync("POST", "/v2/snaps", nil, headers, buf)
 }
 
-func sendSnapFiles(paths []string, files []*os.File, pw *io.PipeWriter, mw *multipart.Writer, action *actionData) {
+func sendSnapFiles(paths []string, files []*os.File, assertPaths []string, assertFiles []*os.File, pw *io.PipeWriter, mw *multipart.Writer, action *actionData) {
 	defer func() {
-		for _, f := range files {
-			f.Close()
-		}
+		closeFiles(files)
+		closeFiles(assertFiles)
 	}()
 
 	if action.SnapOptions == nil {
@@ -405,6 +435,20 @@ func sendSnapFiles(paths []string, files []*os.File, pw *io.PipeWriter, mw *mult
 		return
 	}
 
+	for i, file := range assertFiles {
+		fw, err := mw.CreateFormFile("assertion", filepath.Base(assertPaths[i]))
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		_, err = io.Copy(fw, file)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+	}
+
 	for i, file := range files {
 		path := paths[i]
 		fw, err := mw.CreateFormFile("snap", filepath.Base(path))
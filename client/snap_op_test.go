@@ -252,6 +252,28 @@ func (cs *clientSuite) TestClientMultiOpSnapTransactional(c *check.C) {
 	}
 }
 
+func (cs *clientSuite) TestClientMultiOpSnapScheduled(c *check.C) {
+	cs.status = 202
+	cs.rsp = `{
+		"change": "d728",
+		"status-code": 202,
+		"type": "async"
+	}`
+	for _, s := range multiOps {
+		_, err := s.op(cs.cli, []string{pkgName},
+			&client.SnapOptions{At: "2026-01-02T03:04:05Z"})
+		c.Assert(err, check.IsNil)
+
+		body, err := io.ReadAll(cs.req.Body)
+		c.Assert(err, check.IsNil, check.Commentf(s.action))
+		jsonBody := make(map[string]interface{})
+		err = json.Unmarshal(body, &jsonBody)
+		c.Assert(err, check.IsNil, check.Commentf(s.action))
+		c.Check(jsonBody["at"], check.Equals, "2026-01-02T03:04:05Z", check.Commentf(s.action))
+		c.Check(jsonBody, check.HasLen, 3, check.Commentf(s.action))
+	}
+}
+
 func (cs *clientSuite) TestClientMultiOpSnapIgnoreRunning(c *check.C) {
 	cs.status = 202
 	cs.rsp = `{
@@ -474,6 +496,47 @@ func (cs *clientSuite) TestClientOpInstallPathManyTransactionally(c *check.C) {
 	c.Check(id, check.Equals, "66b3")
 }
 
+func (cs *clientSuite) TestClientOpInstallPathManyWithAssertions(c *check.C) {
+	cs.status = 202
+	cs.rsp = `{
+		"change": "66b3",
+		"status-code": 202,
+		"type": "async"
+	}`
+
+	dir := c.MkDir()
+	var paths []string
+	for _, name := range []string{"foo.snap", "bar.snap"} {
+		path := filepath.Join(dir, name)
+		paths = append(paths, path)
+		c.Assert(os.WriteFile(path, []byte("snap-data"), 0644), check.IsNil)
+	}
+	assertPath := filepath.Join(dir, "foo.assert")
+	c.Assert(os.WriteFile(assertPath, []byte("assertion-data"), 0644), check.IsNil)
+
+	id, err := cs.cli.InstallPathManyWithAssertions(paths, []string{assertPath}, &client.SnapOptions{Transaction: client.TransactionAllSnaps})
+	c.Assert(err, check.IsNil)
+	c.Check(id, check.Equals, "66b3")
+
+	body, err := io.ReadAll(cs.req.Body)
+	c.Assert(err, check.IsNil)
+
+	c.Check(string(body), check.Matches, `(?s).*Content-Disposition: form-data; name="assertion"; filename="foo.assert"\r\nContent-Type: application/octet-stream\r\n\r\nassertion-data\r\n.*`)
+	for _, name := range []string{"foo.snap", "bar.snap"} {
+		c.Check(string(body), check.Matches, fmt.Sprintf(`(?s).*Content-Disposition: form-data; name="snap"; filename="%s"\r\nContent-Type: application/octet-stream\r\n\r\nsnap-data\r\n.*`, name))
+	}
+	c.Check(string(body), check.Matches, `(?s).*Content-Disposition: form-data; name="transaction"\r\n\r\nall-snaps\r\n.*`)
+}
+
+func (cs *clientSuite) TestClientOpInstallPathManyWithAssertionsMissingFile(c *check.C) {
+	path := filepath.Join(c.MkDir(), "foo.snap")
+	c.Assert(os.WriteFile(path, []byte("snap-data"), 0644), check.IsNil)
+
+	_, err := cs.cli.InstallPathManyWithAssertions([]string{path}, []string{"/does/not/exist.assert"}, nil)
+	c.Check(err, check.ErrorMatches, `cannot open "/does/not/exist.assert": .*`)
+	c.Check(cs.req, check.IsNil)
+}
+
 func (cs *clientSuite) TestClientOpInstallPathManyWithOptions(c *check.C) {
 	cs.status = 202
 	cs.rsp = `{
@@ -0,0 +1,126 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdApply struct {
+	waitMixin
+	File string `short:"f" long:"file" required:"yes"`
+	Diff bool   `long:"diff"`
+}
+
+var shortApplyHelp = i18n.G("Bring the device to the state described by a manifest")
+var longApplyHelp = i18n.G(`
+The apply command brings the device to the state described by a manifest in
+YAML format: the channels, revisions and components of its snaps, their
+aliases, connections and configuration, and quota groups. Only what is
+described is changed, and applying the same manifest again does nothing.
+
+For example:
+
+  snaps:
+    foo:
+      channel: latest/stable
+      components: [comp1]
+      aliases:
+        foo-alias: app
+      config:
+        key: value
+  connections:
+    - plug: foo:camera
+      slot: :camera
+  quotas:
+    grp:
+      memory: 512MB
+      snaps: [foo]
+
+With --diff the operations needed are shown without carrying them out. A file
+name of - reads the manifest from standard input.
+`)
+
+func init() {
+	addCommand("apply", shortApplyHelp, longApplyHelp, func() flags.Commander {
+		return &cmdApply{}
+	}, waitDescs.also(map[string]string{
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"file": i18n.G("Read the manifest from the given file"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"diff": i18n.G("Show the operations needed without carrying them out"),
+	}), nil)
+}
+
+func (x *cmdApply) readManifest() ([]byte, error) {
+	if x.File == "-" {
+		return io.ReadAll(Stdin)
+	}
+	return os.ReadFile(x.File)
+}
+
+func (x *cmdApply) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	manifest, err := x.readManifest()
+	if err != nil {
+		return fmt.Errorf(i18n.G("cannot read manifest: %v"), err)
+	}
+
+	if x.Diff {
+		ops, err := x.client.ApplyDiff(manifest)
+		if err != nil {
+			return err
+		}
+		if len(ops) == 0 {
+			fmt.Fprintln(Stdout, i18n.G("The device already matches the manifest."))
+			return nil
+		}
+		for _, op := range ops {
+			fmt.Fprintln(Stdout, op.Summary)
+		}
+		return nil
+	}
+
+	ops, changeID, err := x.client.Apply(manifest)
+	if err != nil {
+		return err
+	}
+	if len(ops) == 0 {
+		fmt.Fprintln(Stdout, i18n.G("The device already matches the manifest."))
+		return nil
+	}
+	if _, err := x.wait(changeID); err != nil {
+		if err == noWait {
+			return nil
+		}
+		return err
+	}
+	fmt.Fprintf(Stdout, i18n.G("Applied manifest with %d operations.\n"), len(ops))
+	return nil
+}
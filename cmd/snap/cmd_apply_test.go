@@ -0,0 +1,122 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+const applyManifest = "snaps: {foo: {config: {key: value}}}\n"
+
+func (s *SnapSuite) writeManifest(c *C) string {
+	path := filepath.Join(c.MkDir(), "manifest.yaml")
+	c.Assert(os.WriteFile(path, []byte(applyManifest), 0644), IsNil)
+	return path
+}
+
+func (s *SnapSuite) TestApplyDiff(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "POST")
+		c.Check(r.URL.Path, Equals, "/v2/apply")
+		c.Check(DecodedRequestBody(c, r), DeepEquals, map[string]interface{}{
+			"manifest": applyManifest,
+			"diff":     true,
+		})
+		fmt.Fprintln(w, `{"type": "sync", "result": {"operations": [
+  {"kind": "install", "snap": "foo", "summary": "Install snap \"foo\""},
+  {"kind": "configure", "snap": "foo", "summary": "Configure \"key\" of snap \"foo\""}
+]}}`)
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"apply", "-f", s.writeManifest(c), "--diff"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `Install snap "foo"
+Configure "key" of snap "foo"
+`)
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestApplyDiffNothingToDo(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "sync", "result": {"operations": []}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"apply", "-f", s.writeManifest(c), "--diff"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "The device already matches the manifest.\n")
+}
+
+func (s *SnapSuite) TestApply(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/apply":
+			c.Check(r.Method, Equals, "POST")
+			c.Check(DecodedRequestBody(c, r), DeepEquals, map[string]interface{}{
+				"manifest": applyManifest,
+			})
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type": "async", "status-code": 202, "change": "42", "result": {"operations": [
+  {"kind": "configure", "snap": "foo", "summary": "Configure \"key\" of snap \"foo\""}
+]}}`)
+		case "/v2/changes/42":
+			c.Check(r.Method, Equals, "GET")
+			fmt.Fprintln(w, `{"type": "sync", "result": {"ready": true, "status": "Done"}}`)
+		default:
+			c.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	})
+
+	// the manifest is read from stdin
+	s.stdin.Write([]byte(applyManifest))
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"apply", "-f", "-"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, "Applied manifest with 1 operations.\n")
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestApplyNothingToDo(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, Equals, "/v2/apply")
+		w.WriteHeader(202)
+		fmt.Fprintln(w, `{"type": "async", "status-code": 202, "change": "42", "result": {"operations": []}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"apply", "-f", s.writeManifest(c)})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "The device already matches the manifest.\n")
+}
+
+func (s *SnapSuite) TestApplyErrors(c *C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"apply"})
+	c.Check(err, ErrorMatches, "the required flag `-f, --file' was not specified")
+
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"apply", "-f", filepath.Join(c.MkDir(), "missing.yaml")})
+	c.Check(err, ErrorMatches, "cannot read manifest: open .*: no such file or directory")
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"time"
 
 	"github.com/jessevdk/go-flags"
 
@@ -122,7 +123,17 @@ func (c *cmdChanges) Execute(args []string) error {
 		if chg.ReadyTime.IsZero() {
 			readyTime = "-"
 		}
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", chg.ID, chg.Status, spawnTime, readyTime, chg.Summary)
+		status, summary := chg.Status, chg.Summary
+		var scheduledTime time.Time
+		// scheduled changes are not started until the given time
+		if chg.Paused {
+			status = i18n.G("Paused")
+		} else if chg.Status == "Do" && chg.Get("scheduled-time", &scheduledTime) == nil && scheduledTime.After(timeNow()) {
+			status = i18n.G("Scheduled")
+			// TRANSLATORS: the first %s is the change summary, the second one a time
+			summary = fmt.Sprintf(i18n.G("%s (at %s)"), chg.Summary, c.fmtTime(scheduledTime))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", chg.ID, status, spawnTime, readyTime, summary)
 	}
 
 	w.Flush()
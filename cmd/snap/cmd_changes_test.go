@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"gopkg.in/check.v1"
 
@@ -250,3 +251,62 @@ func (s *SnapSuite) TestNoChanges(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Check(s.Stderr(), check.Equals, "no changes found\n")
 }
+
+func (s *SnapSuite) TestChangesScheduledAndPaused(c *check.C) {
+	restore := snap.MockTimeNow(func() time.Time {
+		return time.Date(2016, 4, 21, 12, 0, 0, 0, time.UTC)
+	})
+	defer restore()
+
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, check.Equals, "GET")
+		c.Check(r.URL.Path, check.Equals, "/v2/changes")
+		fmt.Fprintln(w, `{"type": "sync", "result": [
+  {
+    "id":   "one",
+    "kind": "refresh-snap",
+    "summary": "Refresh \"foo\" snap",
+    "status": "Do",
+    "ready": false,
+    "spawn-time": "2016-04-21T01:02:03Z",
+    "data": {"scheduled-time": "2016-04-22T01:00:00Z"}
+  },
+  {
+    "id":   "two",
+    "kind": "remove-snap",
+    "summary": "Remove \"bar\" snap",
+    "status": "Doing",
+    "ready": false,
+    "spawn-time": "2016-04-21T01:02:04Z",
+    "data": {"scheduled-time": "2016-04-21T01:02:05Z"}
+  },
+  {
+    "id":   "three",
+    "kind": "refresh-snap",
+    "summary": "Refresh \"baz\" snap",
+    "status": "Doing",
+    "ready": false,
+    "paused": true,
+    "spawn-time": "2016-04-21T01:02:06Z"
+  },
+  {
+    "id":   "four",
+    "kind": "refresh-snap",
+    "summary": "Refresh \"qux\" snap",
+    "status": "Do",
+    "ready": false,
+    "spawn-time": "2016-04-21T01:02:07Z",
+    "data": {"scheduled-time": "2016-04-21T02:00:00Z"}
+  }
+]}`)
+	})
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"changes", "--abs-time"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Matches, `(?ms)ID +Status +Spawn +Ready +Summary
+one +Scheduled +2016-04-21T01:02:03Z +- +Refresh "foo" snap \(at 2016-04-22T01:00:00Z\)
+two +Doing +2016-04-21T01:02:04Z +- +Remove "bar" snap
+three +Paused +2016-04-21T01:02:06Z +- +Refresh "baz" snap
+four +Do +2016-04-21T01:02:07Z +- +Refresh "qux" snap
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+}
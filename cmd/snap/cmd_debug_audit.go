@@ -0,0 +1,155 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/osutil/user"
+)
+
+var userLookup = user.Lookup
+
+type cmdDebugAudit struct {
+	clientMixin
+	timeMixin
+	Since string `long:"since"`
+	Until string `long:"until"`
+	User  string `long:"user"`
+}
+
+func init() {
+	addDebugCommand("audit",
+		i18n.G("Show the audit log of state-changing requests"),
+		i18n.G(`
+The audit command shows the requests which asked snapd to change the state
+of the system, who made them, and what their outcome was.
+
+The --since and --until options accept either a time in RFC3339 format or a
+duration such as 2h45m, meaning that long ago. The --user option accepts
+either a user name or a numeric user ID.
+`),
+		func() flags.Commander {
+			return &cmdDebugAudit{}
+		}, timeDescs.also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"since": i18n.G("Only show requests made at or after the given time"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"until": i18n.G("Only show requests made at or before the given time"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"user": i18n.G("Only show requests made by the given user"),
+		}), nil)
+}
+
+// parseAuditTime parses either an absolute time or a duration relative to
+// now, or returns the zero time if s is empty.
+func parseAuditTime(opt, s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return timeNow().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf(i18n.G("cannot parse --%s %q: expected a time in RFC3339 format or a duration"), opt, s)
+}
+
+func parseAuditUID(s string) (*uint32, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if uid, err := strconv.ParseUint(s, 10, 32); err == nil {
+		uid32 := uint32(uid)
+		return &uid32, nil
+	}
+	u, err := userLookup(s)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("cannot use user %q: invalid uid %q"), s, u.Uid)
+	}
+	uid32 := uint32(uid)
+	return &uid32, nil
+}
+
+func (x *cmdDebugAudit) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	var opts client.AuditOptions
+	var err error
+	if opts.Since, err = parseAuditTime("since", x.Since); err != nil {
+		return err
+	}
+	if opts.Until, err = parseAuditTime("until", x.Until); err != nil {
+		return err
+	}
+	if opts.UID, err = parseAuditUID(x.User); err != nil {
+		return err
+	}
+
+	entries, err := x.client.Audit(&opts)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No matching requests found."))
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Time\tUID\tPID\tSnap\tMethod\tEndpoint\tChange\tStatus"))
+	for _, e := range entries {
+		uid, pid := "-", "-"
+		if e.UID != nil {
+			uid = strconv.FormatUint(uint64(*e.UID), 10)
+		}
+		if e.PID != 0 {
+			pid = strconv.Itoa(int(e.PID))
+		}
+		status := strconv.Itoa(e.StatusCode)
+		if e.Error != "" {
+			status = fmt.Sprintf("%s (%s)", status, e.Error)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			x.fmtTime(e.Time), uid, pid, dashIfEmpty(e.Snap), e.Method, e.Endpoint, dashIfEmpty(e.Change), status)
+	}
+	return nil
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
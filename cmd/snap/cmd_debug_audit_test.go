@@ -0,0 +1,122 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+	"github.com/snapcore/snapd/osutil/user"
+)
+
+const auditJSON = `[
+  {"time": "2026-03-01T10:00:00Z", "uid": 0, "pid": 100, "method": "POST", "endpoint": "/v2/snaps/foo", "change": "42", "status-code": 202},
+  {"time": "2026-03-01T11:00:00Z", "uid": 1000, "pid": 200, "snap": "some-snap", "method": "POST", "endpoint": "/v2/snapctl", "status-code": 401, "error": "access denied"},
+  {"time": "2026-03-01T12:00:00Z", "method": "PUT", "endpoint": "/v2/snaps/foo/conf", "status-code": 200}
+]`
+
+func (s *SnapSuite) mockAuditAPI(c *C, result string, checkQuery func(q url.Values)) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/audit")
+		checkQuery(r.URL.Query())
+		fmt.Fprintf(w, `{"type": "sync", "result": %s}`, result)
+	})
+}
+
+func (s *SnapSuite) TestDebugAudit(c *C) {
+	s.mockAuditAPI(c, auditJSON, func(q url.Values) {
+		c.Check(q, HasLen, 0)
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "audit", "--abs-time"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `
+Time                  UID   PID  Snap       Method  Endpoint            Change  Status
+2026-03-01T10:00:00Z  0     100  -          POST    /v2/snaps/foo       42      202
+2026-03-01T11:00:00Z  1000  200  some-snap  POST    /v2/snapctl         -       401 (access denied)
+2026-03-01T12:00:00Z  -     -    -          PUT     /v2/snaps/foo/conf  -       200
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestDebugAuditFilters(c *C) {
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	restore := snap.MockTimeNow(func() time.Time { return now })
+	defer restore()
+	restore = snap.MockUserLookup(func(name string) (*user.User, error) {
+		c.Check(name, Equals, "someone")
+		return &user.User{Username: "someone", Uid: "1000"}, nil
+	})
+	defer restore()
+
+	s.mockAuditAPI(c, "[]", func(q url.Values) {
+		c.Check(q, HasLen, 3)
+		c.Check(q.Get("since"), Equals, "2026-03-01T22:00:00Z")
+		c.Check(q.Get("until"), Equals, "2026-03-01T23:00:00Z")
+		c.Check(q.Get("uid"), Equals, "1000")
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "audit", "--since=2h", "--until=2026-03-01T23:00:00Z", "--user=someone"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "")
+	c.Check(s.Stderr(), Equals, "No matching requests found.\n")
+}
+
+func (s *SnapSuite) TestDebugAuditNumericUser(c *C) {
+	restore := snap.MockUserLookup(func(name string) (*user.User, error) {
+		c.Fatalf("unexpected user lookup")
+		return nil, nil
+	})
+	defer restore()
+
+	s.mockAuditAPI(c, "[]", func(q url.Values) {
+		c.Check(q.Get("uid"), Equals, "0")
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "audit", "--user=0"})
+	c.Assert(err, IsNil)
+}
+
+func (s *SnapSuite) TestDebugAuditErrors(c *C) {
+	restore := snap.MockUserLookup(func(name string) (*user.User, error) {
+		return nil, user.UnknownUserError(name)
+	})
+	defer restore()
+
+	for _, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--since=yesterday"}, `cannot parse --since "yesterday": expected a time in RFC3339 format or a duration`},
+		{[]string{"--until=-1h"}, `cannot parse --until "-1h": expected a time in RFC3339 format or a duration`},
+		{[]string{"--user=nobody-here"}, `user: unknown user nobody-here`},
+		{[]string{"extra"}, `too many arguments for command`},
+	} {
+		_, err := snap.Parser(snap.Client()).ParseArgs(append([]string{"debug", "audit"}, t.args...))
+		c.Check(err, ErrorMatches, t.err)
+	}
+}
@@ -0,0 +1,166 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/i18n"
+)
+
+var snapFileDigest = asserts.SnapFileSHA3_384
+
+type cmdDebugAutoConnectPreview struct {
+	clientMixin
+
+	Positionals struct {
+		Snap string `positional-arg-name:"<snap>" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+func init() {
+	addDebugCommand("autoconnect-preview",
+		i18n.G("Show which plugs and slots of a snap would be auto-connected"),
+		i18n.G(`
+The autoconnect-preview command shows, for each plug and slot of the given
+snap, the plugs and slots of the installed snaps it could be connected to
+and whether it would be auto-connected to them if the snap was installed on
+this device, and why: the rule of the base declaration or of the snap
+declarations that decides it, as evaluated against the model of the device.
+
+The snap can be the path to a .snap file, in which case the snap does not
+need to be installed. Its snap-revision and snap-declaration are used if
+they are available in the assertion database of the device, otherwise it is
+considered unasserted.
+`),
+		func() flags.Commander {
+			return &cmdDebugAutoConnectPreview{}
+		}, nil, nil)
+}
+
+type autoConnectPreviewRef struct {
+	Snap string `json:"snap"`
+	Plug string `json:"plug"`
+	Slot string `json:"slot"`
+}
+
+type autoConnectPreviewCandidate struct {
+	Plug   *autoConnectPreviewRef  `json:"plug"`
+	Slot   *autoConnectPreviewRef  `json:"slot"`
+	Auto   bool                    `json:"auto"`
+	Policy *connectionPolicyResult `json:"policy"`
+	Note   string                  `json:"note"`
+}
+
+type autoConnectPreview struct {
+	Snap       string                        `json:"snap"`
+	SnapID     string                        `json:"snap-id"`
+	Candidates []autoConnectPreviewCandidate `json:"candidates"`
+}
+
+// reason describes why the plug would or would not be auto-connected to
+// the slot of the candidate.
+func (cand *autoConnectPreviewCandidate) reason() string {
+	switch {
+	case cand.Plug == nil:
+		return i18n.G("no candidate plugs")
+	case cand.Slot == nil:
+		return i18n.G("no candidate slots")
+	case cand.Note != "":
+		return cand.Note
+	case cand.Policy == nil:
+		return "-"
+	}
+	reason := cand.Policy.constraint()
+	if cand.Policy.Declaration != "" {
+		// TRANSLATORS: the first %s is a constraint, the second the rule it is part of
+		reason = fmt.Sprintf(i18n.G("%s in %s"), reason, cand.Policy.rule())
+	}
+	if cand.Policy.Mismatch != "" {
+		reason += ": " + cand.Policy.Mismatch
+	}
+	return reason
+}
+
+func fmtAutoConnectPreviewRef(ref *autoConnectPreviewRef) string {
+	switch {
+	case ref == nil:
+		return "-"
+	case ref.Plug != "":
+		return ref.Snap + ":" + ref.Plug
+	}
+	return ref.Snap + ":" + ref.Slot
+}
+
+func (x *cmdDebugAutoConnectPreview) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	params := struct {
+		Snap         string `json:"snap,omitempty"`
+		SnapYaml     string `json:"snap-yaml,omitempty"`
+		SnapSHA3_384 string `json:"snap-sha3-384,omitempty"`
+		SnapSize     uint64 `json:"snap-size,omitempty"`
+	}{Snap: x.Positionals.Snap}
+	if isSnapFilePath(params.Snap) {
+		path := params.Snap
+		yaml, err := snapYamlFromFile(path)
+		if err != nil {
+			return fmt.Errorf(i18n.G("cannot read snap file %q: %v"), path, err)
+		}
+		digest, size, err := snapFileDigest(path)
+		if err != nil {
+			return fmt.Errorf(i18n.G("cannot compute the digest of snap file %q: %v"), path, err)
+		}
+		params.Snap = ""
+		params.SnapYaml = string(yaml)
+		params.SnapSHA3_384 = digest
+		params.SnapSize = size
+	}
+
+	var result autoConnectPreview
+	if err := x.client.Debug("autoconnect-preview", params, &result); err != nil {
+		return err
+	}
+	if result.SnapID == "" {
+		fmt.Fprintf(Stdout, i18n.G("Snap %q is unasserted, it has no snap-declaration.\n"), result.Snap)
+	}
+	if len(result.Candidates) == 0 {
+		fmt.Fprintf(Stdout, i18n.G("Snap %q has no plugs or slots.\n"), result.Snap)
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Plug\tSlot\tAuto\tReason"))
+	for _, cand := range result.Candidates {
+		auto := i18n.G("no")
+		if cand.Auto {
+			auto = i18n.G("yes")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", fmtAutoConnectPreviewRef(cand.Plug), fmtAutoConnectPreviewRef(cand.Slot), auto, cand.reason())
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) mockAutoConnectPreviewAPI(c *C, expectedParams map[string]interface{}, result string) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "POST")
+		c.Check(r.URL.Path, Equals, "/v2/debug")
+		var body map[string]interface{}
+		c.Assert(json.NewDecoder(r.Body).Decode(&body), IsNil)
+		c.Check(body, DeepEquals, map[string]interface{}{
+			"action": "autoconnect-preview",
+			"params": expectedParams,
+		})
+		fmt.Fprintf(w, `{"type": "sync", "result": %s}`, result)
+	})
+}
+
+func (s *SnapSuite) TestDebugAutoConnectPreview(c *C) {
+	s.mockAutoConnectPreviewAPI(c, map[string]interface{}{"snap": "foo"}, `{
+		"snap": "foo",
+		"snap-id": "foo-id",
+		"candidates": [
+			{"plug": {"snap": "foo", "plug": "camera"}, "auto": false},
+			{"plug": {"snap": "foo", "plug": "network"}, "slot": {"snap": "snapd", "slot": "network"}, "auto": true,
+			 "policy": {"allowed": true, "kind": "auto-connection", "declaration": "base-declaration", "side": "slot", "constraint": "allow-auto-connection"}},
+			{"plug": {"snap": "foo", "plug": "data"}, "slot": {"snap": "bar", "slot": "data"}, "auto": false,
+			 "policy": {"allowed": false, "kind": "auto-connection", "declaration": "snap-declaration", "snap-name": "bar", "side": "slot", "constraint": "allow-auto-connection", "alternative": 0,
+			            "mismatch": "attribute \"content\" value \"foo\" does not match $PLUG(content)", "error": "auto-connection not allowed"}},
+			{"plug": {"snap": "foo", "plug": "x11"}, "slot": {"snap": "snapd", "slot": "x11"}, "auto": false,
+			 "policy": {"allowed": true, "kind": "auto-connection", "declaration": "base-declaration", "side": "slot", "constraint": "allow-auto-connection"},
+			 "note": "plug \"foo:x11\" has 2 candidate slots but can only be auto-connected to one"},
+			{"slot": {"snap": "foo", "slot": "service"}, "auto": false}
+		]
+	}`)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "autoconnect-preview", "foo"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `
+Plug         Slot           Auto  Reason
+foo:camera   -              no    no candidate slots
+foo:network  snapd:network  yes   allow-auto-connection in slot rule of the base-declaration
+foo:data     bar:data       no    allow-auto-connection (alternative 1) in slot rule of the snap-declaration of "bar": attribute "content" value "foo" does not match $PLUG(content)
+foo:x11      snapd:x11      no    plug "foo:x11" has 2 candidate slots but can only be auto-connected to one
+-            foo:service    no    no candidate plugs
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestDebugAutoConnectPreviewSnapFile(c *C) {
+	restore := snap.MockSnapYamlFromFile(func(path string) ([]byte, error) {
+		c.Check(path, Equals, "./foo_1_amd64.snap")
+		return []byte("name: foo\n"), nil
+	})
+	defer restore()
+	restore = snap.MockSnapFileDigest(func(path string) (string, uint64, error) {
+		c.Check(path, Equals, "./foo_1_amd64.snap")
+		return "digest", 4096, nil
+	})
+	defer restore()
+
+	s.mockAutoConnectPreviewAPI(c, map[string]interface{}{
+		"snap-yaml":     "name: foo\n",
+		"snap-sha3-384": "digest",
+		"snap-size":     4096.0,
+	}, `{"snap": "foo", "candidates": []}`)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "autoconnect-preview", "./foo_1_amd64.snap"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, `
+Snap "foo" is unasserted, it has no snap-declaration.
+Snap "foo" has no plugs or slots.
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
@@ -0,0 +1,176 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdDebugChangeGraph struct {
+	changeIDMixin
+	Format string `long:"format" default:"dot" choice:"dot" choice:"json"`
+}
+
+func init() {
+	addDebugCommand("change-graph",
+		i18n.G("Show the task dependency graph of a change"),
+		i18n.G(`
+The change-graph command shows the tasks of a change together with their
+status, lanes and the dependencies between them, either as a graphviz dot
+graph or as JSON.
+
+In the dot output solid edges point from a task to the tasks waiting for it
+to be done, while dashed edges show the order in which tasks are undone.
+Edges which currently prevent a task from running are drawn in bold red.
+`),
+		func() flags.Commander {
+			return &cmdDebugChangeGraph{}
+		}, changeIDMixinOptDesc.also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"format": i18n.G("Output format (one of: dot, json)"),
+		}), changeIDMixinArgDesc)
+}
+
+func (x *cmdDebugChangeGraph) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	chgID, err := x.GetChangeID()
+	if err != nil {
+		if err == noChangeFoundOK {
+			return nil
+		}
+		return err
+	}
+
+	graph, err := x.client.ChangeGraph(chgID)
+	if err != nil {
+		return err
+	}
+
+	if x.Format == "json" {
+		enc := json.NewEncoder(Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(graph)
+	}
+	writeChangeGraphDot(Stdout, graph)
+	return nil
+}
+
+var taskStatusColor = map[string]string{
+	"Doing":   "blue",
+	"Undoing": "orange",
+	"Done":    "darkgreen",
+	"Undone":  "orange4",
+	"Error":   "red",
+	"Hold":    "gray",
+	"Wait":    "purple",
+	"Abort":   "red",
+}
+
+func taskGraphNodeAttrs(t *client.TaskGraphNode) string {
+	label := fmt.Sprintf("%s %s\n%s", t.ID, t.Kind, t.Status)
+	if t.Change != "" {
+		label = fmt.Sprintf("%s %s\n%s (change %s)", t.ID, t.Kind, t.Status, t.Change)
+	}
+	if t.AtTime != nil {
+		label += "\n" + fmt.Sprintf(i18n.G("at %s"), t.AtTime.Format("2006-01-02T15:04:05Z07:00"))
+	}
+	attrs := fmt.Sprintf("label=%q, tooltip=%q", label, t.Summary)
+	if color, ok := taskStatusColor[t.Status]; ok {
+		attrs += fmt.Sprintf(", color=%q", color)
+	}
+	if t.Change != "" {
+		attrs += ", style=dotted"
+	}
+	return attrs
+}
+
+// taskGraphLane returns the lane a task is drawn in, which is the first
+// lane other than the default one the task is part of.
+func taskGraphLane(t *client.TaskGraphNode) int {
+	for _, lane := range t.Lanes {
+		if lane != 0 {
+			return lane
+		}
+	}
+	return 0
+}
+
+func writeChangeGraphDot(w io.Writer, graph *client.ChangeGraph) {
+	fmt.Fprintf(w, "digraph %q {\n", "change-"+graph.ID)
+	fmt.Fprintf(w, "  label=%q;\n", fmt.Sprintf("%s %s: %s (%s)", graph.ID, graph.Kind, graph.Summary, graph.Status))
+	fmt.Fprintf(w, "  node [shape=box];\n")
+
+	lanes := make(map[int][]*client.TaskGraphNode)
+	for _, t := range graph.Tasks {
+		lane := 0
+		if t.Change == "" {
+			lane = taskGraphLane(t)
+		}
+		lanes[lane] = append(lanes[lane], t)
+	}
+	laneIDs := make([]int, 0, len(lanes))
+	for lane := range lanes {
+		laneIDs = append(laneIDs, lane)
+	}
+	sort.Ints(laneIDs)
+
+	for _, lane := range laneIDs {
+		indent := "  "
+		if lane != 0 {
+			fmt.Fprintf(w, "  subgraph %q {\n", fmt.Sprintf("cluster_lane_%d", lane))
+			fmt.Fprintf(w, "    label=%q;\n", fmt.Sprintf("lane %d", lane))
+			indent = "    "
+		}
+		for _, t := range lanes[lane] {
+			fmt.Fprintf(w, "%s%q [%s];\n", indent, t.ID, taskGraphNodeAttrs(t))
+		}
+		if lane != 0 {
+			fmt.Fprintf(w, "  }\n")
+		}
+	}
+
+	for _, e := range graph.Edges {
+		var attrs []string
+		if e.Kind == "undo" {
+			attrs = append(attrs, "style=dashed", "constraint=false")
+		}
+		if e.Blocking {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		if len(attrs) == 0 {
+			fmt.Fprintf(w, "  %q -> %q;\n", e.From, e.To)
+		} else {
+			fmt.Fprintf(w, "  %q -> %q [%s];\n", e.From, e.To, strings.Join(attrs, ", "))
+		}
+	}
+	fmt.Fprintf(w, "}\n")
+}
@@ -0,0 +1,101 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+const changeGraphJSON = `{
+  "id": "42",
+  "kind": "install-snap",
+  "summary": "Install \"foo\" snap",
+  "status": "Doing",
+  "tasks": [
+    {"id": "1", "kind": "download-snap", "summary": "Download", "status": "Done", "lanes": [1]},
+    {"id": "2", "kind": "mount-snap", "summary": "Mount", "status": "Doing", "lanes": [1]},
+    {"id": "3", "kind": "run-hook", "summary": "Run hook", "status": "Do", "lanes": [0], "at-time": "2026-01-02T03:04:05Z"},
+    {"id": "7", "kind": "unlink-snap", "summary": "Unlink", "status": "Do", "lanes": [0], "change": "5"}
+  ],
+  "edges": [
+    {"from": "1", "to": "2", "kind": "wait"},
+    {"from": "2", "to": "1", "kind": "undo"},
+    {"from": "2", "to": "3", "kind": "wait", "blocking": true},
+    {"from": "7", "to": "3", "kind": "wait", "blocking": true}
+  ]
+}`
+
+func (s *SnapSuite) mockChangeGraphAPI(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/changes/42/graph")
+		fmt.Fprintf(w, `{"type": "sync", "result": %s}`, changeGraphJSON)
+	})
+}
+
+func (s *SnapSuite) TestDebugChangeGraphDot(c *C) {
+	s.mockChangeGraphAPI(c)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "change-graph", "42"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `digraph "change-42" {
+  label="42 install-snap: Install \"foo\" snap (Doing)";
+  node [shape=box];
+  "3" [label="3 run-hook\nDo\nat 2026-01-02T03:04:05Z", tooltip="Run hook"];
+  "7" [label="7 unlink-snap\nDo (change 5)", tooltip="Unlink", style=dotted];
+  subgraph "cluster_lane_1" {
+    label="lane 1";
+    "1" [label="1 download-snap\nDone", tooltip="Download", color="darkgreen"];
+    "2" [label="2 mount-snap\nDoing", tooltip="Mount", color="blue"];
+  }
+  "1" -> "2";
+  "2" -> "1" [style=dashed, constraint=false];
+  "2" -> "3" [color=red, penwidth=2];
+  "7" -> "3" [color=red, penwidth=2];
+}
+`)
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestDebugChangeGraphJSON(c *C) {
+	s.mockChangeGraphAPI(c)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "change-graph", "--format=json", "42"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+
+	var obtained, expected interface{}
+	c.Assert(json.Unmarshal([]byte(s.Stdout()), &obtained), IsNil)
+	c.Assert(json.Unmarshal([]byte(changeGraphJSON), &expected), IsNil)
+	c.Check(obtained, DeepEquals, expected)
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestDebugChangeGraphNoID(c *C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "change-graph"})
+	c.Assert(err, ErrorMatches, "please provide change ID or type with --last=<type>")
+}
@@ -0,0 +1,183 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/snap/snapfile"
+)
+
+type cmdDebugConnectionPolicy struct {
+	clientMixin
+	Auto bool `long:"auto"`
+
+	Positionals struct {
+		Plug string `positional-arg-name:"<snap>:<plug>" required:"yes"`
+		Slot string `positional-arg-name:"<snap>:<slot>" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+func init() {
+	addDebugCommand("connection-policy",
+		i18n.G("Explain whether a plug can be connected to a slot"),
+		i18n.G(`
+The connection-policy command evaluates the base declaration and the snap
+declarations of the involved snaps to tell whether the given plug can be
+connected to the given slot, and which rule and constraint decided it.
+
+With --auto the policy for automatic connection is evaluated instead.
+
+The snap of either side can be the path to a .snap file, in which case the
+snap does not need to be installed and is considered unasserted. An empty
+snap name for the slot refers to the system snap.
+`),
+		func() flags.Commander {
+			return &cmdDebugConnectionPolicy{}
+		}, map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"auto": i18n.G("Evaluate the auto-connection policy"),
+		}, nil)
+}
+
+type connectionPolicyEnd struct {
+	Snap     string `json:"snap"`
+	Name     string `json:"name"`
+	SnapYaml string `json:"snap-yaml,omitempty"`
+}
+
+type connectionPolicyResult struct {
+	Allowed     bool     `json:"allowed"`
+	Kind        string   `json:"kind"`
+	Declaration string   `json:"declaration"`
+	SnapName    string   `json:"snap-name"`
+	Side        string   `json:"side"`
+	Constraint  string   `json:"constraint"`
+	Alternative *int     `json:"alternative"`
+	Mismatch    string   `json:"mismatch"`
+	Error       string   `json:"error"`
+	Unasserted  []string `json:"unasserted"`
+}
+
+var snapYamlFromFile = func(path string) ([]byte, error) {
+	snapf, err := snapfile.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return snapf.ReadFile("meta/snap.yaml")
+}
+
+func isSnapFilePath(s string) bool {
+	return strings.HasSuffix(s, ".snap") || strings.ContainsRune(s, '/')
+}
+
+// parseConnectionPolicyEnd parses <snap>:<name>, where the snap can also be
+// the path to a snap file.
+func parseConnectionPolicyEnd(arg, what string) (connectionPolicyEnd, error) {
+	idx := strings.LastIndexByte(arg, ':')
+	if idx < 0 || idx == len(arg)-1 {
+		return connectionPolicyEnd{}, fmt.Errorf(i18n.G("invalid %s %q: expected <snap>:<%s>"), what, arg, what)
+	}
+	end := connectionPolicyEnd{Snap: arg[:idx], Name: arg[idx+1:]}
+	if isSnapFilePath(end.Snap) {
+		yaml, err := snapYamlFromFile(end.Snap)
+		if err != nil {
+			return connectionPolicyEnd{}, fmt.Errorf(i18n.G("cannot read snap file %q: %v"), end.Snap, err)
+		}
+		end.Snap = ""
+		end.SnapYaml = string(yaml)
+	}
+	return end, nil
+}
+
+// rule describes the rule which decided, if any.
+func (r *connectionPolicyResult) rule() string {
+	if r.SnapName != "" {
+		return fmt.Sprintf(i18n.G("%s rule of the %s of %q"), r.Side, r.Declaration, r.SnapName)
+	}
+	return fmt.Sprintf(i18n.G("%s rule of the %s"), r.Side, r.Declaration)
+}
+
+// constraint describes the constraint of the rule which decided.
+func (r *connectionPolicyResult) constraint() string {
+	if r.Alternative != nil {
+		return fmt.Sprintf(i18n.G("%s (alternative %s)"), r.Constraint, strconv.Itoa(*r.Alternative+1))
+	}
+	return r.Constraint
+}
+
+func (x *cmdDebugConnectionPolicy) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	plug, err := parseConnectionPolicyEnd(x.Positionals.Plug, "plug")
+	if err != nil {
+		return err
+	}
+	slot, err := parseConnectionPolicyEnd(x.Positionals.Slot, "slot")
+	if err != nil {
+		return err
+	}
+
+	params := struct {
+		Plug connectionPolicyEnd `json:"plug"`
+		Slot connectionPolicyEnd `json:"slot"`
+		Auto bool                `json:"auto,omitempty"`
+	}{plug, slot, x.Auto}
+	var result connectionPolicyResult
+	if err := x.client.Debug("connection-policy", params, &result); err != nil {
+		return err
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	outcome := i18n.G("denied")
+	if result.Allowed {
+		outcome = i18n.G("allowed")
+	}
+	fmt.Fprintf(w, "%s:\t%s\n", i18n.G("result"), outcome)
+	fmt.Fprintf(w, "%s:\t%s\n", i18n.G("kind"), result.Kind)
+	if result.Declaration != "" {
+		fmt.Fprintf(w, "%s:\t%s\n", i18n.G("rule"), result.rule())
+	}
+	if result.Constraint != "" {
+		fmt.Fprintf(w, "%s:\t%s\n", i18n.G("constraint"), result.constraint())
+	}
+	if result.Mismatch != "" {
+		fmt.Fprintf(w, "%s:\t%s\n", i18n.G("mismatch"), result.Mismatch)
+	}
+	if result.Error != "" {
+		fmt.Fprintf(w, "%s:\t%s\n", i18n.G("error"), result.Error)
+	}
+	if len(result.Unasserted) > 0 {
+		fmt.Fprintf(w, "%s:\t%s\n", i18n.G("unasserted"), strings.Join(result.Unasserted, ", "))
+		if !x.Auto {
+			fmt.Fprintf(w, "%s:\t%s\n", i18n.G("note"), i18n.G("snapd does not check the connection policy of manual connections involving unasserted snaps"))
+		}
+	}
+	return nil
+}
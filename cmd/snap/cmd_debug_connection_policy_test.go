@@ -0,0 +1,126 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) mockConnectionPolicyAPI(c *C, expectedParams map[string]interface{}, result string) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "POST")
+		c.Check(r.URL.Path, Equals, "/v2/debug")
+		var body map[string]interface{}
+		c.Assert(json.NewDecoder(r.Body).Decode(&body), IsNil)
+		c.Check(body, DeepEquals, map[string]interface{}{
+			"action": "connection-policy",
+			"params": expectedParams,
+		})
+		fmt.Fprintf(w, `{"type": "sync", "result": %s}`, result)
+	})
+}
+
+func (s *SnapSuite) TestDebugConnectionPolicyDenied(c *C) {
+	s.mockConnectionPolicyAPI(c, map[string]interface{}{
+		"plug": map[string]interface{}{"snap": "consumer", "name": "content"},
+		"slot": map[string]interface{}{"snap": "producer", "name": "content"},
+		"auto": true,
+	}, `{
+		"allowed": false,
+		"kind": "auto-connection",
+		"declaration": "snap-declaration",
+		"snap-name": "producer",
+		"side": "slot",
+		"constraint": "allow-auto-connection",
+		"mismatch": "attribute \"content\" value \"bar\" does not match ^(foo)$",
+		"error": "auto-connection not allowed by slot rule of interface \"content\" for \"producer\" snap"
+	}`)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "connection-policy", "consumer:content", "producer:content", "--auto"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `
+result:      denied
+kind:        auto-connection
+rule:        slot rule of the snap-declaration of "producer"
+constraint:  allow-auto-connection
+mismatch:    attribute "content" value "bar" does not match ^(foo)$
+error:       auto-connection not allowed by slot rule of interface "content" for "producer" snap
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestDebugConnectionPolicyAllowedUnassertedFile(c *C) {
+	restore := snap.MockSnapYamlFromFile(func(path string) ([]byte, error) {
+		c.Check(path, Equals, "./consumer_1.snap")
+		return []byte("name: consumer\n"), nil
+	})
+	defer restore()
+
+	s.mockConnectionPolicyAPI(c, map[string]interface{}{
+		"plug": map[string]interface{}{"snap": "", "name": "network", "snap-yaml": "name: consumer\n"},
+		"slot": map[string]interface{}{"snap": "", "name": "network"},
+	}, `{
+		"allowed": true,
+		"kind": "connection",
+		"declaration": "base-declaration",
+		"side": "slot",
+		"constraint": "allow-connection",
+		"alternative": 1,
+		"unasserted": ["consumer"]
+	}`)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "connection-policy", "./consumer_1.snap:network", ":network"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, `
+result:      allowed
+kind:        connection
+rule:        slot rule of the base-declaration
+constraint:  allow-connection (alternative 2)
+unasserted:  consumer
+note:        snapd does not check the connection policy of manual connections involving unasserted snaps
+`[1:])
+}
+
+func (s *SnapSuite) TestDebugConnectionPolicyErrors(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Fatalf("unexpected request")
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "connection-policy", "consumer", "producer:content"})
+	c.Check(err, ErrorMatches, `invalid plug "consumer": expected <snap>:<plug>`)
+
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"debug", "connection-policy", "consumer:content", "producer:"})
+	c.Check(err, ErrorMatches, `invalid slot "producer:": expected <snap>:<slot>`)
+
+	restore := snap.MockSnapYamlFromFile(func(path string) ([]byte, error) {
+		return nil, errors.New("boom")
+	})
+	defer restore()
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"debug", "connection-policy", "consumer.snap:content", "producer:content"})
+	c.Check(err, ErrorMatches, `cannot read snap file "consumer.snap": boom`)
+}
@@ -0,0 +1,141 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdDebugDenials struct {
+	clientMixin
+	timeMixin
+	Snap  string `long:"snap"`
+	Since string `long:"since"`
+}
+
+func init() {
+	addDebugCommand("denials",
+		i18n.G("Show the sandbox denials of snaps"),
+		i18n.G(`
+The denials command shows the accesses that AppArmor and seccomp denied to
+the apps and hooks of snaps, as logged in the journal, with identical
+denials grouped together.
+
+For each denial, the interfaces that would allow the access are suggested,
+along with the plug of the snap with that interface and whether it is
+already connected.
+
+The --since option accepts either a time in RFC3339 format or a duration
+such as 2h45m, meaning that long ago. By default the denials of the last
+24 hours are shown.
+
+Reading the denials requires administrative privileges.
+`),
+		func() flags.Commander {
+			return &cmdDebugDenials{}
+		}, timeDescs.also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"snap": i18n.G("Only show the denials of the given snap"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"since": i18n.G("Only show denials logged at or after the given time"),
+		}), nil)
+}
+
+type denialSuggestion struct {
+	Interface string `json:"interface"`
+	Plug      string `json:"plug"`
+	Connected bool   `json:"connected"`
+}
+
+type denialGroup struct {
+	Snap        string             `json:"snap"`
+	App         string             `json:"app"`
+	Kind        string             `json:"kind"`
+	Access      string             `json:"access"`
+	Comm        string             `json:"comm"`
+	Count       int                `json:"count"`
+	First       time.Time          `json:"first"`
+	Last        time.Time          `json:"last"`
+	Suggestions []denialSuggestion `json:"suggestions"`
+}
+
+func fmtDenialSuggestions(suggestions []denialSuggestion) string {
+	if len(suggestions) == 0 {
+		return "-"
+	}
+	strs := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		switch {
+		case s.Connected:
+			// TRANSLATORS: %s is the name of an interface
+			strs = append(strs, fmt.Sprintf(i18n.G("%s (connected)"), s.Interface))
+		case s.Plug != "":
+			// TRANSLATORS: the first %s is the name of an interface, the second the name of a plug
+			strs = append(strs, fmt.Sprintf(i18n.G("%s (plug %s)"), s.Interface, s.Plug))
+		default:
+			// TRANSLATORS: %s is the name of an interface
+			strs = append(strs, fmt.Sprintf(i18n.G("%s (no plug)"), s.Interface))
+		}
+	}
+	return strings.Join(strs, ", ")
+}
+
+func (x *cmdDebugDenials) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	since, err := parseAuditTime("since", x.Since)
+	if err != nil {
+		return err
+	}
+	params := map[string]string{}
+	if x.Snap != "" {
+		params["snap"] = x.Snap
+	}
+	if !since.IsZero() {
+		params["since"] = since.UTC().Format(time.RFC3339)
+	}
+
+	var groups []denialGroup
+	if err := x.client.DebugGet("denials", &groups, params); err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No denials found."))
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Last\tCount\tSnap\tApp\tKind\tAccess\tInterfaces"))
+	for _, g := range groups {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			x.fmtTime(g.Last), g.Count, g.Snap, g.App, g.Kind, g.Access, fmtDenialSuggestions(g.Suggestions))
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+const denialsJSON = `[
+  {"snap": "foo", "app": "app", "kind": "apparmor", "access": "open /sys/devices/virtual/net/lo/statistics/rx_bytes (r)", "comm": "foo", "count": 2,
+   "first": "2026-03-01T10:00:00Z", "last": "2026-03-01T11:00:00Z",
+   "suggestions": [{"interface": "hardware-observe", "plug": "hw"}, {"interface": "network-observe", "plug": "network-observe", "connected": true}]},
+  {"snap": "foo", "app": "hook:configure", "kind": "seccomp", "access": "syscall mount", "count": 1,
+   "first": "2026-03-01T12:00:00Z", "last": "2026-03-01T12:00:00Z",
+   "suggestions": [{"interface": "mount-control"}]},
+  {"snap": "foo", "app": "app", "kind": "apparmor", "access": "open /var/snap/bar/common/secret (r)", "count": 1,
+   "first": "2026-03-01T13:00:00Z", "last": "2026-03-01T13:00:00Z"}
+]`
+
+func (s *SnapSuite) mockDenialsAPI(c *C, result string, checkQuery func(q url.Values)) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/debug")
+		c.Check(r.URL.Query().Get("aspect"), Equals, "denials")
+		checkQuery(r.URL.Query())
+		fmt.Fprintf(w, `{"type": "sync", "result": %s}`, result)
+	})
+}
+
+func (s *SnapSuite) TestDebugDenials(c *C) {
+	s.mockDenialsAPI(c, denialsJSON, func(q url.Values) {
+		c.Check(q, HasLen, 1)
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "denials", "--abs-time"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `
+Last                  Count  Snap  App             Kind      Access                                                    Interfaces
+2026-03-01T11:00:00Z  2      foo   app             apparmor  open /sys/devices/virtual/net/lo/statistics/rx_bytes (r)  hardware-observe (plug hw), network-observe (connected)
+2026-03-01T12:00:00Z  1      foo   hook:configure  seccomp   syscall mount                                             mount-control (no plug)
+2026-03-01T13:00:00Z  1      foo   app             apparmor  open /var/snap/bar/common/secret (r)                      -
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestDebugDenialsFilters(c *C) {
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	restore := snap.MockTimeNow(func() time.Time { return now })
+	defer restore()
+
+	s.mockDenialsAPI(c, "[]", func(q url.Values) {
+		c.Check(q, HasLen, 3)
+		c.Check(q.Get("snap"), Equals, "foo")
+		c.Check(q.Get("since"), Equals, "2026-03-01T22:00:00Z")
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "denials", "--snap=foo", "--since=2h"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "")
+	c.Check(s.Stderr(), Equals, "No denials found.\n")
+}
+
+func (s *SnapSuite) TestDebugDenialsBadSince(c *C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "denials", "--since=yesterday"})
+	c.Assert(err, ErrorMatches, `cannot parse --since "yesterday": expected a time in RFC3339 format or a duration`)
+}
@@ -0,0 +1,108 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdDebugSandboxProfiles struct {
+	clientMixin
+	App     string `long:"app"`
+	Backend string `long:"backend" choice:"apparmor" choice:"seccomp" choice:"udev" choice:"mount" choice:"dbus" choice:"kmod"`
+
+	Positionals struct {
+		Snap string `positional-arg-name:"<snap>" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+func init() {
+	addDebugCommand("sandbox-profiles",
+		i18n.G("Show the sandbox profiles generated for a snap"),
+		i18n.G(`
+The sandbox-profiles command shows the security profiles and other sandbox
+configuration that snapd generates for the given snap, as they would be set
+up on this device, including the connections the snap would auto-connect.
+Nothing is written or loaded.
+
+The snap can be the path to a .snap file, in which case the snap does not
+need to be installed.
+`),
+		func() flags.Commander {
+			return &cmdDebugSandboxProfiles{}
+		}, map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"app": i18n.G("Only show the profiles specific to the given app"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"backend": i18n.G("Only show the profiles of the given security backend"),
+		}, nil)
+}
+
+type sandboxProfile struct {
+	Backend string `json:"backend"`
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+func (x *cmdDebugSandboxProfiles) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	params := struct {
+		Snap     string `json:"snap,omitempty"`
+		SnapYaml string `json:"snap-yaml,omitempty"`
+		App      string `json:"app,omitempty"`
+		Backend  string `json:"backend,omitempty"`
+	}{Snap: x.Positionals.Snap, App: x.App, Backend: x.Backend}
+	if isSnapFilePath(params.Snap) {
+		yaml, err := snapYamlFromFile(params.Snap)
+		if err != nil {
+			return fmt.Errorf(i18n.G("cannot read snap file %q: %v"), params.Snap, err)
+		}
+		params.Snap = ""
+		params.SnapYaml = string(yaml)
+	}
+
+	var profiles []sandboxProfile
+	if err := x.client.Debug("sandbox-profiles", params, &profiles); err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No sandbox profiles."))
+		return nil
+	}
+
+	for i, profile := range profiles {
+		if i > 0 {
+			fmt.Fprintln(Stdout)
+		}
+		fmt.Fprintf(Stdout, "==> %s: %s <==\n", profile.Backend, profile.Path)
+		fmt.Fprint(Stdout, profile.Content)
+		if n := len(profile.Content); n > 0 && profile.Content[n-1] != '\n' {
+			fmt.Fprintln(Stdout)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) mockSandboxProfilesAPI(c *C, expectedParams map[string]interface{}, result string) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "POST")
+		c.Check(r.URL.Path, Equals, "/v2/debug")
+		var body map[string]interface{}
+		c.Assert(json.NewDecoder(r.Body).Decode(&body), IsNil)
+		c.Check(body, DeepEquals, map[string]interface{}{
+			"action": "sandbox-profiles",
+			"params": expectedParams,
+		})
+		fmt.Fprintf(w, `{"type": "sync", "result": %s}`, result)
+	})
+}
+
+func (s *SnapSuite) TestDebugSandboxProfiles(c *C) {
+	s.mockSandboxProfilesAPI(c, map[string]interface{}{
+		"snap":    "foo",
+		"app":     "app",
+		"backend": "apparmor",
+	}, `[
+		{"backend": "apparmor", "path": "/var/lib/snapd/apparmor/profiles/snap.foo.app", "content": "profile snap.foo.app {\n}\n"},
+		{"backend": "apparmor", "path": "/var/lib/snapd/apparmor/profiles/snap.foo.hook.configure", "content": "no newline"}
+	]`)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "sandbox-profiles", "foo", "--app", "app", "--backend", "apparmor"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `
+==> apparmor: /var/lib/snapd/apparmor/profiles/snap.foo.app <==
+profile snap.foo.app {
+}
+
+==> apparmor: /var/lib/snapd/apparmor/profiles/snap.foo.hook.configure <==
+no newline
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestDebugSandboxProfilesSnapFile(c *C) {
+	restore := snap.MockSnapYamlFromFile(func(path string) ([]byte, error) {
+		c.Check(path, Equals, "foo_1.snap")
+		return []byte("name: foo\n"), nil
+	})
+	defer restore()
+
+	s.mockSandboxProfilesAPI(c, map[string]interface{}{
+		"snap-yaml": "name: foo\n",
+	}, `[]`)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "sandbox-profiles", "foo_1.snap"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "")
+	c.Check(s.Stderr(), Equals, "No sandbox profiles.\n")
+}
+
+func (s *SnapSuite) TestDebugSandboxProfilesInvalidBackend(c *C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"debug", "sandbox-profiles", "foo", "--backend", "selinux"})
+	c.Check(err, ErrorMatches, `Invalid value .selinux. for option .--backend.*`)
+}
@@ -0,0 +1,105 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdExport struct {
+	clientMixin
+	Revision   string `long:"revision"`
+	Output     string `short:"o" long:"output"`
+	Positional struct {
+		Snap installedSnapName `positional-arg-name:"<snap>"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+var shortExportHelp = i18n.G("Export an installed snap with its assertions")
+var longExportHelp = i18n.G(`
+The export command writes a tar archive with the installed revision of a snap,
+its components, and all the assertions needed to install them on another
+device, without access to the store.
+
+By default the current revision is exported into <snap>.tar. To install the
+exported snap elsewhere, extract the archive into a directory and use
+'snap install --from-dir' on it.
+`)
+
+func init() {
+	addCommand("export", shortExportHelp, longExportHelp, func() flags.Commander {
+		return &cmdExport{}
+	}, map[string]string{
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"revision": i18n.G("Export the given installed revision of the snap"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		"output": i18n.G("Write the archive to the given file"),
+	}, nil)
+}
+
+func (x *cmdExport) Execute(args []string) (err error) {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	name := string(x.Positional.Snap)
+	filename := x.Output
+	if filename == "" {
+		filename = name + ".tar"
+	}
+
+	r, err := x.client.SnapExport(name, &client.SnapExportOptions{Revision: x.Revision})
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := os.Create(filename + ".part")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	defer func() {
+		if err != nil {
+			os.Remove(filename + ".part")
+		}
+	}()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return fmt.Errorf(i18n.G("cannot export snap %q: %v"), name, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(filename+".part", filename); err != nil {
+		return err
+	}
+
+	// TRANSLATORS: the first argument is a snap name, the second one a file name.
+	fmt.Fprintf(Stdout, i18n.G("Exported snap %q into %q\n"), name, filename)
+	return nil
+}
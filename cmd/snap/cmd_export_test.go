@@ -0,0 +1,86 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+	"github.com/snapcore/snapd/testutil"
+)
+
+func (s *SnapSuite) TestExport(c *C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/snaps/foo/export")
+		c.Check(r.URL.Query().Get("revision"), Equals, "5")
+		w.Header().Set("Content-Type", "application/x-tar")
+		fmt.Fprint(w, "tar-data")
+	})
+
+	output := filepath.Join(c.MkDir(), "bundle.tar")
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"export", "foo", "--revision=5", "-o", output})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(n, Equals, 1)
+	c.Check(output, testutil.FileEquals, "tar-data")
+	c.Check(output+".part", testutil.FileAbsent)
+	c.Check(s.Stdout(), Equals, fmt.Sprintf("Exported snap \"foo\" into %q\n", output))
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestExportDefaultOutput(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.RawQuery, Equals, "")
+		w.Header().Set("Content-Type", "application/x-tar")
+		fmt.Fprint(w, "tar-data")
+	})
+
+	dir := c.MkDir()
+	oldCwd, err := os.Getwd()
+	c.Assert(err, IsNil)
+	c.Assert(os.Chdir(dir), IsNil)
+	defer os.Chdir(oldCwd)
+
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"export", "foo"})
+	c.Assert(err, IsNil)
+	c.Check(filepath.Join(dir, "foo.tar"), testutil.FileEquals, "tar-data")
+	c.Check(s.Stdout(), Equals, "Exported snap \"foo\" into \"foo.tar\"\n")
+}
+
+func (s *SnapSuite) TestExportError(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		fmt.Fprintln(w, `{"type": "error", "status-code": 400, "result": {"message": "cannot export snap \"foo\": boom"}}`)
+	})
+
+	output := filepath.Join(c.MkDir(), "bundle.tar")
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"export", "foo", "-o", output})
+	c.Assert(err, ErrorMatches, `cannot export snap "foo": boom`)
+	c.Check(output, testutil.FileAbsent)
+	c.Check(output+".part", testutil.FileAbsent)
+}
@@ -209,10 +209,14 @@ var helpCategories = []helpCategory{
 		Label:       i18n.G("...more"),
 		Description: i18n.G("slightly more advanced snap management"),
 		Commands:    []string{"refresh", "revert", "switch", "disable", "enable", "create-cohort"},
+		// TODO: move to Commands once used more widely
+		AllOnlyCommands: []string{"export"},
 	}, {
 		Label:       i18n.G("History"),
 		Description: i18n.G("manage system change transactions"),
 		Commands:    []string{"changes", "tasks", "abort", "watch"},
+		// TODO: move to Commands once used more widely
+		AllOnlyCommands: []string{"pause", "resume", "history"},
 	}, {
 		Label:       i18n.G("Daemons"),
 		Description: i18n.G("manage services"),
@@ -221,6 +225,8 @@ var helpCategories = []helpCategory{
 		Label:       i18n.G("Permissions"),
 		Description: i18n.G("manage permissions"),
 		Commands:    []string{"connections", "interface", "connect", "disconnect"},
+		// TODO: move to Commands once used more widely
+		AllOnlyCommands: []string{"prompts", "prompt-rules"},
 	}, {
 		Label:       i18n.G("Configuration"),
 		Description: i18n.G("system administration and configuration"),
@@ -239,9 +245,10 @@ var helpCategories = []helpCategory{
 		Commands:        []string{"saved", "save", "check-snapshot", "restore", "forget"},
 		AllOnlyCommands: []string{"export-snapshot", "import-snapshot"},
 	}, {
-		Label:       i18n.G("Device"),
-		Description: i18n.G("manage device"),
-		Commands:    []string{"model", "remodel", "reboot", "recovery"},
+		Label:           i18n.G("Device"),
+		Description:     i18n.G("manage device"),
+		Commands:        []string{"model", "remodel", "reboot", "recovery"},
+		AllOnlyCommands: []string{"apply"},
 	}, {
 		Label:       i18n.G("Warnings"),
 		Other:       true,
@@ -0,0 +1,84 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdHistory struct {
+	clientMixin
+	timeMixin
+	Positional struct {
+		Snap installedSnapName `positional-arg-name:"<snap>" required:"1"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+var shortHistoryHelp = i18n.G("Show the install, refresh and revert history of a snap")
+var longHistoryHelp = i18n.G(`
+The history command shows when the given snap was installed, refreshed,
+reverted, switched to another channel or removed, between which revisions,
+what triggered the operation and whether it succeeded, oldest first.
+
+The history is kept after the snap is removed, and is limited to the most
+recent operations.
+`)
+
+func init() {
+	addCommand("history", shortHistoryHelp, longHistoryHelp, func() flags.Commander {
+		return &cmdHistory{}
+	}, timeDescs, []argDesc{{
+		// TRANSLATORS: This needs to begin with < and end with >
+		name: i18n.G("<snap>"),
+		// TRANSLATORS: This should not start with a lowercase letter.
+		desc: i18n.G("Snap name"),
+	}})
+}
+
+func (x *cmdHistory) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	name := string(x.Positional.Snap)
+	history, err := x.client.SnapHistory(name)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		fmt.Fprintf(Stderr, i18n.G("No history recorded for snap %q.\n"), name)
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Time\tAction\tFrom\tTo\tChannel\tTrigger\tOutcome\tChange"))
+	for _, e := range history {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			x.fmtTime(e.Time), e.Action, dashIfEmpty(e.FromRevision), dashIfEmpty(e.ToRevision),
+			dashIfEmpty(e.Channel), e.Trigger, e.Outcome, e.ChangeID)
+	}
+	return nil
+}
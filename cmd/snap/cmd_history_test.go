@@ -0,0 +1,75 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+func (s *SnapSuite) mockSnapHistoryAPI(c *C, rsp string) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/snaps/foo/history")
+		fmt.Fprint(w, rsp)
+	})
+}
+
+func (s *SnapSuite) TestHistory(c *C) {
+	s.mockSnapHistoryAPI(c, `{"type": "sync", "result": [
+  {"action": "install", "to-revision": "7", "channel": "latest/stable", "time": "2026-03-01T10:00:00Z", "trigger": "manual", "outcome": "succeeded", "change-id": "1"},
+  {"action": "refresh", "from-revision": "7", "to-revision": "8", "channel": "latest/stable", "time": "2026-03-02T10:00:00Z", "trigger": "auto", "outcome": "failed", "change-id": "5"},
+  {"action": "remove", "from-revision": "7", "time": "2026-03-03T10:00:00Z", "trigger": "manual", "outcome": "succeeded", "change-id": "9"}
+]}`)
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"history", "--abs-time", "foo"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `
+Time                  Action   From  To   Channel        Trigger  Outcome    Change
+2026-03-01T10:00:00Z  install  -     7    latest/stable  manual   succeeded  1
+2026-03-02T10:00:00Z  refresh  7     8    latest/stable  auto     failed     5
+2026-03-03T10:00:00Z  remove   7     -    -              manual   succeeded  9
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestHistoryEmpty(c *C) {
+	s.mockSnapHistoryAPI(c, `{"type": "sync", "result": []}`)
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"history", "foo"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "")
+	c.Check(s.Stderr(), Equals, "No history recorded for snap \"foo\".\n")
+}
+
+func (s *SnapSuite) TestHistoryNotFound(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		fmt.Fprint(w, `{"type": "error", "status-code": 404, "result": {"message": "snap not installed", "kind": "snap-not-found", "value": "foo"}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"history", "foo"})
+	c.Assert(err, ErrorMatches, `cannot get history of snap "foo": snap not installed`)
+}
@@ -25,6 +25,7 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/jessevdk/go-flags"
 
@@ -38,6 +39,11 @@ The list command displays a summary of snaps installed in the current system.
 
 A green check mark (given color and unicode support) after a publisher name
 indicates that the publisher has been verified.
+
+With --all, the inactive revisions which are garbage collected on the next
+refresh of their snap, according to the refresh.retain, refresh.retain-max-age
+and refresh.retain-max-size system options, show when they become eligible
+for removal.
 `)
 
 type cmdList struct {
@@ -92,6 +98,18 @@ func fmtVersion(v string) string {
 	return v
 }
 
+func fmtRemovableFrom(t *time.Time) string {
+	switch {
+	case t == nil:
+		return "-"
+	case !t.After(timeNow()):
+		// TRANSLATORS: a revision can be removed right away
+		return i18n.G("now")
+	default:
+		return t.Local().Format("2006-01-02")
+	}
+}
+
 func (x *cmdList) Execute(args []string) error {
 	if len(args) > 0 {
 		return ErrExtraArgs
@@ -117,8 +135,22 @@ func (x *cmdList) Execute(args []string) error {
 	esc := x.getEscapes()
 	w := tabWriter()
 
-	// TRANSLATORS: the %s is to insert a filler escape sequence (please keep it flush to the column header, with no extra spaces)
-	fmt.Fprintf(w, i18n.G("Name\tVersion\tRev\tTracking\tPublisher%s\tNotes\n"), fillerPublisher(esc))
+	// only show when revisions can be removed if any can
+	showRemovable := false
+	for _, snap := range snaps {
+		if snap.RemovableFrom != nil {
+			showRemovable = true
+			break
+		}
+	}
+
+	if showRemovable {
+		// TRANSLATORS: the %s is to insert a filler escape sequence (please keep it flush to the column header, with no extra spaces)
+		fmt.Fprintf(w, i18n.G("Name\tVersion\tRev\tTracking\tPublisher%s\tNotes\tRemovable\n"), fillerPublisher(esc))
+	} else {
+		// TRANSLATORS: the %s is to insert a filler escape sequence (please keep it flush to the column header, with no extra spaces)
+		fmt.Fprintf(w, i18n.G("Name\tVersion\tRev\tTracking\tPublisher%s\tNotes\n"), fillerPublisher(esc))
+	}
 
 	for _, snap := range snaps {
 		// doing it this way because otherwise it's a sea of %s\t%s\t%s
@@ -130,6 +162,9 @@ func (x *cmdList) Execute(args []string) error {
 			shortPublisher(esc, snap.Publisher),
 			NotesFromLocal(snap).String(),
 		}
+		if showRemovable {
+			line = append(line, fmtRemovableFrom(snap.RemovableFrom))
+		}
 		fmt.Fprintln(w, strings.Join(line, "\t"))
 	}
 	w.Flush()
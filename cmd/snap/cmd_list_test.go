@@ -22,6 +22,7 @@ package main_test
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"gopkg.in/check.v1"
 
@@ -37,6 +38,11 @@ The list command displays a summary of snaps installed in the current system.
 A green check mark (given color and unicode support) after a publisher name
 indicates that the publisher has been verified.
 
+With --all, the inactive revisions which are garbage collected on the next
+refresh of their snap, according to the refresh.retain, refresh.retain-max-age
+and refresh.retain-max-size system options, show when they become eligible
+for removal.
+
 [list command options]
       --all                           Show all revisions
       --color=[auto|never|always]     Use a little bit of color to highlight
@@ -106,6 +112,34 @@ foo +4.2 +17 +stable +bar +-
 	c.Check(s.Stderr(), check.Equals, "")
 }
 
+func (s *SnapSuite) TestListAllRemovable(c *check.C) {
+	restore := snap.MockTimeNow(func() time.Time {
+		return time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	})
+	defer restore()
+
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, check.Equals, "GET")
+		c.Check(r.URL.Path, check.Equals, "/v2/snaps")
+		c.Check(r.URL.RawQuery, check.Equals, "select=all")
+		fmt.Fprintf(w, `{"type": "sync", "result": [
+{"name": "foo", "status": "installed", "version": "4.0", "revision": 15, "tracking-channel": "stable", "removable-from": %q},
+{"name": "foo", "status": "installed", "version": "4.1", "revision": 16, "tracking-channel": "stable", "removable-from": %q},
+{"name": "foo", "status": "active", "version": "4.2", "revision": 17, "tracking-channel": "stable"}]}`,
+			time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local).Format(time.RFC3339),
+			time.Date(2026, 3, 20, 12, 0, 0, 0, time.Local).Format(time.RFC3339))
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"list", "--all"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Matches, `Name +Version +Rev +Tracking +Publisher +Notes +Removable
+foo +4.0 +15 +stable +- +disabled +now
+foo +4.1 +16 +stable +- +disabled +2026-03-20
+foo +4.2 +17 +stable +- +- +-
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
 func (s *SnapSuite) TestListEmpty(c *check.C) {
 	n := 0
 	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,97 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/i18n"
+)
+
+type cmdPause struct{ changeIDMixin }
+
+type cmdResume struct{ changeIDMixin }
+
+var shortPauseHelp = i18n.G("Pause a pending change")
+
+var longPauseHelp = i18n.G(`
+The pause command holds a change that still has pending tasks. Tasks which
+are already running are allowed to finish, but no new tasks of the change
+are started until the change is resumed with 'snap resume'.
+`)
+
+var shortResumeHelp = i18n.G("Resume a paused change")
+
+var longResumeHelp = i18n.G(`
+The resume command lets a change paused with 'snap pause' proceed.
+`)
+
+func init() {
+	addCommand("pause",
+		shortPauseHelp,
+		longPauseHelp,
+		func() flags.Commander {
+			return &cmdPause{}
+		},
+		changeIDMixinOptDesc,
+		changeIDMixinArgDesc,
+	)
+	addCommand("resume",
+		shortResumeHelp,
+		longResumeHelp,
+		func() flags.Commander {
+			return &cmdResume{}
+		},
+		changeIDMixinOptDesc,
+		changeIDMixinArgDesc,
+	)
+}
+
+func (x *cmdPause) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	id, err := x.GetChangeID()
+	if err != nil {
+		if err == noChangeFoundOK {
+			return nil
+		}
+		return err
+	}
+	_, err = x.client.Pause(id)
+	return err
+}
+
+func (x *cmdResume) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+
+	id, err := x.GetChangeID()
+	if err != nil {
+		if err == noChangeFoundOK {
+			return nil
+		}
+		return err
+	}
+	_, err = x.client.Resume(id)
+	return err
+}
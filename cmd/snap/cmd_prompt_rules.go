@@ -0,0 +1,577 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"gopkg.in/yaml.v2"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/strutil"
+)
+
+var shortPromptRulesHelp = i18n.G("Manage permission prompting rules")
+var longPromptRulesHelp = i18n.G(`
+The prompt-rules command lists, adds, removes and modifies the rules which
+are used to reply to permission prompts without asking, and exports and
+imports them as a portable policy.
+`)
+
+type cmdPromptRules struct{}
+
+type cmdPromptRulesList struct {
+	clientMixin
+	timeMixin
+	promptingUserMixin
+	Snap      string `long:"snap"`
+	Interface string `long:"interface"`
+}
+
+type promptRuleContentsMixin struct {
+	PathPattern string `long:"path-pattern"`
+	Permissions string `long:"permissions"`
+	Outcome     string `long:"outcome"`
+	Lifespan    string `long:"lifespan"`
+	Duration    string `long:"duration"`
+}
+
+var promptRuleContentsDescs = mixinDescs{
+	// TRANSLATORS: This should not start with a lowercase letter.
+	"path-pattern": i18n.G("The path pattern the rule applies to"),
+	// TRANSLATORS: This should not start with a lowercase letter.
+	"permissions": i18n.G("Comma-separated list of the permissions the rule applies to"),
+	// TRANSLATORS: This should not start with a lowercase letter.
+	"outcome": i18n.G("Whether the rule allows or denies requests (allow|deny)"),
+	// TRANSLATORS: This should not start with a lowercase letter.
+	"lifespan": i18n.G("How long the rule applies (timespan|forever)"),
+	// TRANSLATORS: This should not start with a lowercase letter.
+	"duration": i18n.G("How long the rule applies when the lifespan is timespan, such as 10m"),
+}
+
+func (mx promptRuleContentsMixin) constraints() *client.RuleConstraints {
+	if mx.PathPattern == "" && mx.Permissions == "" {
+		return nil
+	}
+	return &client.RuleConstraints{
+		PathPattern: mx.PathPattern,
+		Permissions: strutil.CommaSeparatedList(mx.Permissions),
+	}
+}
+
+type cmdPromptRulesAdd struct {
+	clientMixin
+	promptingUserMixin
+	promptRuleContentsMixin
+	Snap      string `long:"snap" required:"yes"`
+	Interface string `long:"interface"`
+}
+
+type cmdPromptRulesExplain struct {
+	clientMixin
+	timeMixin
+	promptingUserMixin
+	Snap       string `long:"snap" required:"yes"`
+	Interface  string `long:"interface"`
+	Permission string `long:"permission" required:"yes"`
+	Positional struct {
+		Path string
+	} `positional-args:"yes" required:"yes"`
+}
+
+type promptRuleIDArg struct {
+	ID string
+}
+
+type cmdPromptRulesRemove struct {
+	clientMixin
+	promptingUserMixin
+	Snap       string          `long:"snap"`
+	Interface  string          `long:"interface"`
+	Positional promptRuleIDArg `positional-args:"yes"`
+}
+
+type cmdPromptRulesPatch struct {
+	clientMixin
+	promptingUserMixin
+	promptRuleContentsMixin
+	Positional promptRuleIDArg `positional-args:"yes" required:"yes"`
+}
+
+type cmdPromptRulesExport struct {
+	clientMixin
+	promptingUserMixin
+	Format string `long:"format" default:"yaml" choice:"yaml" choice:"json"`
+}
+
+type cmdPromptRulesImport struct {
+	clientMixin
+	promptingUserMixin
+	Replace    bool `long:"replace"`
+	Positional struct {
+		File string
+	} `positional-args:"yes" required:"yes"`
+}
+
+var promptRuleIDArgDesc = []argDesc{{
+	// TRANSLATORS: This needs to begin with < and end with >
+	name: i18n.G("<rule-id>"),
+	// TRANSLATORS: This should not start with a lowercase letter.
+	desc: i18n.G("The ID of the rule"),
+}}
+
+func init() {
+	cmd := addCommand("prompt-rules", shortPromptRulesHelp, longPromptRulesHelp, func() flags.Commander {
+		return &cmdPromptRules{}
+	}, nil, nil)
+	cmd.subcommands = []*cmdInfo{{
+		name:      "list",
+		shortHelp: i18n.G("List permission prompting rules"),
+		longHelp: i18n.G(`
+The list command lists the permission prompting rules, optionally only those
+of the given snap and interface.
+`),
+		builder: func() flags.Commander { return &cmdPromptRulesList{} },
+		optDescs: timeDescs.also(promptingUserDescs).also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"snap": i18n.G("Only list the rules of the given snap"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"interface": i18n.G("Only list the rules of the given interface (requires --snap)"),
+		}),
+	}, {
+		name:      "add",
+		shortHelp: i18n.G("Add a permission prompting rule"),
+		longHelp: i18n.G(`
+The add command adds a rule which allows or denies the requests of the given
+snap through the given interface matching the given path pattern and
+permissions. Outstanding prompts matched by the new rule are resolved too.
+
+The interface defaults to home and the lifespan to forever.
+`),
+		builder: func() flags.Commander { return &cmdPromptRulesAdd{} },
+		optDescs: promptingUserDescs.also(promptRuleContentsDescs).also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"snap": i18n.G("The snap the rule applies to"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"interface": i18n.G("The interface the rule applies to"),
+		}),
+	}, {
+		name:      "remove",
+		shortHelp: i18n.G("Remove permission prompting rules"),
+		longHelp: i18n.G(`
+The remove command removes the given rule or, with --snap, all the rules of
+the given snap, optionally only those of the given interface.
+`),
+		builder: func() flags.Commander { return &cmdPromptRulesRemove{} },
+		optDescs: promptingUserDescs.also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"snap": i18n.G("Remove all the rules of the given snap"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"interface": i18n.G("Only remove the rules of the given interface (requires --snap)"),
+		}),
+		argDescs: promptRuleIDArgDesc,
+	}, {
+		name:      "patch",
+		shortHelp: i18n.G("Modify a permission prompting rule"),
+		longHelp: i18n.G(`
+The patch command modifies the given rule. Only the given properties of the
+rule are changed.
+`),
+		builder:  func() flags.Commander { return &cmdPromptRulesPatch{} },
+		optDescs: promptingUserDescs.also(promptRuleContentsDescs),
+		argDescs: promptRuleIDArgDesc,
+	}, {
+		name:      "explain",
+		shortHelp: i18n.G("Explain which prompting rules apply to a path"),
+		longHelp: i18n.G(`
+The explain command shows which rules of the given snap and interface match
+the given path and permission, ordered from the highest precedence to the
+lowest. The rule with the highest precedence decides whether a request for
+that path and permission is allowed or denied. If no rule matches, the
+request results in a prompt.
+
+The interface defaults to home.
+`),
+		builder: func() flags.Commander { return &cmdPromptRulesExplain{} },
+		optDescs: timeDescs.also(promptingUserDescs).also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"snap": i18n.G("The snap making the request"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"interface": i18n.G("The interface through which the request is made"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"permission": i18n.G("The permission being requested, such as read"),
+		}),
+		argDescs: []argDesc{{
+			// TRANSLATORS: This needs to begin with < and end with >
+			name: i18n.G("<path>"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			desc: i18n.G("The path being requested"),
+		}},
+	}, {
+		name:      "export",
+		shortHelp: i18n.G("Export permission prompting rules as a policy"),
+		longHelp: i18n.G(`
+The export command writes the rules with a lifespan of forever to standard
+output as a versioned policy document, which can be imported for another
+user or on another system with the import command. Path patterns in the
+home directory of the user are written relative to ~/.
+`),
+		builder: func() flags.Commander { return &cmdPromptRulesExport{} },
+		optDescs: promptingUserDescs.also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"format": i18n.G("The format of the policy (yaml|json)"),
+		}),
+	}, {
+		name:      "import",
+		shortHelp: i18n.G("Import permission prompting rules from a policy"),
+		longHelp: i18n.G(`
+The import command adds the rules of the given policy document, in YAML or
+JSON format, to the existing rules. With --replace, all existing rules are
+removed first. Rules identical to an existing rule are skipped. If any rule
+of the policy is invalid or conflicts with another rule, nothing is changed.
+
+Path patterns beginning with ~/ are relative to the home directory of the
+user. A file name of - reads the policy from standard input. For example:
+
+    version: 1
+    rules:
+      - snap: libreoffice
+        interface: home
+        path-pattern: ~/Documents/**
+        permissions: [read, write]
+        outcome: allow
+`),
+		builder: func() flags.Commander { return &cmdPromptRulesImport{} },
+		optDescs: promptingUserDescs.also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"replace": i18n.G("Remove all existing rules before importing"),
+		}),
+		argDescs: []argDesc{{
+			// TRANSLATORS: This needs to begin with < and end with >
+			name: i18n.G("<file>"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			desc: i18n.G("The policy to import"),
+		}},
+	}}
+}
+
+func (x *cmdPromptRules) Execute(args []string) error {
+	return flag.ErrHelp
+}
+
+func fmtPromptRuleExpiration(mx timeMixin, rule *client.PromptingRule) string {
+	if rule.Expiration.IsZero() {
+		return "-"
+	}
+	return mx.fmtTime(rule.Expiration)
+}
+
+func (x *cmdPromptRulesList) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	if x.Interface != "" && x.Snap == "" {
+		return errors.New(i18n.G("cannot filter rules by interface without --snap"))
+	}
+	opts, err := x.promptingOptions()
+	if err != nil {
+		return err
+	}
+	rulesOpts := &client.PromptingRulesOptions{
+		Snap:      x.Snap,
+		Interface: x.Interface,
+	}
+	if opts != nil {
+		rulesOpts.PromptingOptions = *opts
+	}
+
+	rules, err := x.client.PromptingRules(rulesOpts)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No prompting rules."))
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("ID\tSnap\tInterface\tPath pattern\tPermissions\tOutcome\tLifespan\tExpires"))
+	for _, r := range rules {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.ID, r.Snap, r.Interface,
+			r.Constraints.PathPattern, fmtPromptPermissions(r.Constraints.Permissions),
+			r.Outcome, r.Lifespan, fmtPromptRuleExpiration(x.timeMixin, r))
+	}
+	return nil
+}
+
+func (x *cmdPromptRulesAdd) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	opts, err := x.promptingOptions()
+	if err != nil {
+		return err
+	}
+	if x.PathPattern == "" {
+		return errors.New(i18n.G("a path pattern is required, use --path-pattern"))
+	}
+	if x.Permissions == "" {
+		return errors.New(i18n.G("permissions are required, use --permissions"))
+	}
+	if x.Outcome == "" {
+		return errors.New(i18n.G("an outcome is required, use --outcome=allow or --outcome=deny"))
+	}
+	rule := &client.AddPromptingRule{
+		Snap:        x.Snap,
+		Interface:   x.Interface,
+		Constraints: x.constraints(),
+		Outcome:     x.Outcome,
+		Lifespan:    x.Lifespan,
+		Duration:    x.Duration,
+	}
+	if rule.Interface == "" {
+		rule.Interface = "home"
+	}
+	if rule.Lifespan == "" {
+		rule.Lifespan = "forever"
+	}
+
+	added, err := x.client.AddPromptingRule(rule, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, i18n.G("Added rule %s.\n"), added.ID)
+	return nil
+}
+
+func (x *cmdPromptRulesRemove) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	opts, err := x.promptingOptions()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case x.Positional.ID != "" && (x.Snap != "" || x.Interface != ""):
+		return errors.New(i18n.G("cannot remove a given rule together with --snap or --interface"))
+	case x.Positional.ID != "":
+		removed, err := x.client.RemovePromptingRule(x.Positional.ID, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(Stdout, i18n.G("Removed rule %s.\n"), removed.ID)
+		return nil
+	case x.Snap == "":
+		return errors.New(i18n.G("a rule ID or --snap is required"))
+	}
+
+	removed, err := x.client.RemovePromptingRules(x.Snap, x.Interface, opts)
+	if err != nil {
+		return err
+	}
+	if len(removed) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No prompting rules removed."))
+		return nil
+	}
+	ids := make([]string, 0, len(removed))
+	for _, r := range removed {
+		ids = append(ids, r.ID)
+	}
+	// TRANSLATORS: %s is a comma-separated list of rule IDs
+	fmt.Fprintf(Stdout, i18n.NG("Removed rule %s.\n", "Removed rules %s.\n", len(ids)), strings.Join(ids, ", "))
+	return nil
+}
+
+func (x *cmdPromptRulesPatch) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	opts, err := x.promptingOptions()
+	if err != nil {
+		return err
+	}
+	patch := &client.PatchPromptingRule{
+		Constraints: x.constraints(),
+		Outcome:     x.Outcome,
+		Lifespan:    x.Lifespan,
+		Duration:    x.Duration,
+	}
+	if *patch == (client.PatchPromptingRule{}) {
+		return errors.New(i18n.G("nothing to change, give at least one property of the rule"))
+	}
+	if c := patch.Constraints; c != nil && (c.PathPattern == "" || len(c.Permissions) == 0) {
+		// the constraints of a rule are replaced as a whole, so keep the
+		// part which is not given as it is
+		rule, err := x.client.PromptingRule(x.Positional.ID, opts)
+		if err != nil {
+			return err
+		}
+		if c.PathPattern == "" {
+			c.PathPattern = rule.Constraints.PathPattern
+		}
+		if len(c.Permissions) == 0 {
+			c.Permissions = rule.Constraints.Permissions
+		}
+	}
+
+	patched, err := x.client.PatchPromptingRule(x.Positional.ID, patch, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(Stdout, i18n.G("Patched rule %s.\n"), patched.ID)
+	return nil
+}
+
+func (x *cmdPromptRulesExplain) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	opts, err := x.promptingOptions()
+	if err != nil {
+		return err
+	}
+	iface := x.Interface
+	if iface == "" {
+		iface = "home"
+	}
+
+	explanation, err := x.client.ExplainPromptingRules(x.Snap, iface, x.Positional.Path, x.Permission, opts)
+	if err != nil {
+		return err
+	}
+	if len(explanation.Matches) == 0 {
+		fmt.Fprintln(Stdout, i18n.G("No prompting rule matches, the request results in a prompt."))
+		return nil
+	}
+	// TRANSLATORS: %s is the outcome of the request, allow or deny
+	fmt.Fprintf(Stdout, i18n.G("Outcome: %s, decided by the match with the highest precedence.\n"), explanation.Outcome)
+	fmt.Fprintln(Stdout)
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("Precedence\tVariant\tOutcome\tRule\tPath pattern\tLifespan\tExpires"))
+	for i, m := range explanation.Matches {
+		for _, r := range m.Rules {
+			expires := "-"
+			if !r.Expiration.IsZero() {
+				expires = x.fmtTime(r.Expiration)
+				if r.Remaining != "" {
+					// TRANSLATORS: the first %s is a time, the second the duration until then
+					expires = fmt.Sprintf(i18n.G("%s (in %s)"), expires, r.Remaining)
+				}
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", i+1, m.Variant, m.Outcome, r.ID,
+				r.Constraints.PathPattern, r.Lifespan, expires)
+		}
+	}
+	return nil
+}
+
+func (x *cmdPromptRulesExport) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	opts, err := x.promptingOptions()
+	if err != nil {
+		return err
+	}
+
+	policy, err := x.client.ExportPromptingPolicy(opts)
+	if err != nil {
+		return err
+	}
+	if policy.Rules == nil {
+		policy.Rules = []*client.PromptingPolicyRule{}
+	}
+
+	var out []byte
+	switch x.Format {
+	case "json":
+		out, err = json.MarshalIndent(policy, "", "  ")
+		out = append(out, '\n')
+	default:
+		out, err = yaml.Marshal(policy)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = Stdout.Write(out)
+	return err
+}
+
+func (x *cmdPromptRulesImport) readPolicy() (*client.PromptingPolicy, error) {
+	var data []byte
+	var err error
+	if x.Positional.File == "-" {
+		data, err = io.ReadAll(Stdin)
+	} else {
+		data, err = os.ReadFile(x.Positional.File)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// JSON documents are valid YAML as well
+	var policy client.PromptingPolicy
+	if err := yaml.UnmarshalStrict(data, &policy); err != nil {
+		return nil, fmt.Errorf(i18n.G("cannot parse policy: %v"), err)
+	}
+	return &policy, nil
+}
+
+func (x *cmdPromptRulesImport) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	opts, err := x.promptingOptions()
+	if err != nil {
+		return err
+	}
+	policy, err := x.readPolicy()
+	if err != nil {
+		return err
+	}
+
+	result, err := x.client.ImportPromptingPolicy(policy, x.Replace, opts)
+	if err != nil {
+		return err
+	}
+	if len(result.Added) == 0 && len(result.Removed) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No prompting rules changed."))
+		return nil
+	}
+	if len(result.Removed) > 0 {
+		fmt.Fprintf(Stdout, i18n.NG("Removed %d rule.\n", "Removed %d rules.\n", len(result.Removed)), len(result.Removed))
+	}
+	fmt.Fprintf(Stdout, i18n.NG("Added %d rule.\n", "Added %d rules.\n", len(result.Added)), len(result.Added))
+	return nil
+}
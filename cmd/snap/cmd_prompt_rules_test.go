@@ -0,0 +1,419 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+const promptRuleJSON = `{
+	"id": "0000000000000010",
+	"timestamp": "2026-03-01T10:00:00Z",
+	"user": 1000,
+	"snap": "firefox",
+	"interface": "home",
+	"constraints": {
+		"path-pattern": "/home/test/Downloads/**",
+		"permissions": ["read", "write"]
+	},
+	"outcome": "allow",
+	"lifespan": "forever"
+}`
+
+const promptRuleTimespanJSON = `{
+	"id": "0000000000000011",
+	"timestamp": "2026-03-01T10:00:00Z",
+	"user": 1000,
+	"snap": "cheese",
+	"interface": "camera",
+	"constraints": {
+		"path-pattern": "/dev/video*",
+		"permissions": ["access"]
+	},
+	"outcome": "deny",
+	"lifespan": "timespan",
+	"expiration": "2026-03-01T10:10:00Z"
+}`
+
+func (s *SnapSuite) TestPromptRulesList(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/rules")
+		c.Check(r.URL.RawQuery, Equals, "")
+		fmt.Fprintf(w, `{"type": "sync", "result": [%s, %s]}`, promptRuleJSON, promptRuleTimespanJSON)
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "list", "--abs-time"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `
+ID                Snap     Interface  Path pattern             Permissions  Outcome  Lifespan  Expires
+0000000000000010  firefox  home       /home/test/Downloads/**  read,write   allow    forever   -
+0000000000000011  cheese   camera     /dev/video*              access       deny     timespan  2026-03-01T10:10:00Z
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestPromptRulesListFiltered(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		c.Check(q, HasLen, 3)
+		c.Check(q.Get("snap"), Equals, "firefox")
+		c.Check(q.Get("interface"), Equals, "home")
+		c.Check(q.Get("user-id"), Equals, "1000")
+		fmt.Fprint(w, `{"type": "sync", "result": []}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "list", "--snap=firefox", "--interface=home", "--user-id=1000"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "")
+	c.Check(s.Stderr(), Equals, "No prompting rules.\n")
+
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "list", "--interface=home"})
+	c.Assert(err, ErrorMatches, "cannot filter rules by interface without --snap")
+}
+
+func (s *SnapSuite) TestPromptRulesAdd(c *C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		c.Check(r.Method, Equals, "POST")
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/rules")
+		c.Check(decodedBody(c, r), DeepEquals, map[string]interface{}{
+			"action": "add",
+			"rule": map[string]interface{}{
+				"snap":      "firefox",
+				"interface": "home",
+				"constraints": map[string]interface{}{
+					"path-pattern": "/home/test/Downloads/**",
+					"permissions":  []interface{}{"read", "write"},
+				},
+				"outcome":  "allow",
+				"lifespan": "forever",
+			},
+		})
+		fmt.Fprintf(w, `{"type": "sync", "result": %s}`, promptRuleJSON)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "add", "--snap=firefox",
+		"--path-pattern=/home/test/Downloads/**", "--permissions=read,write", "--outcome=allow"})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+	c.Check(s.Stdout(), Equals, "Added rule 0000000000000010.\n")
+}
+
+func (s *SnapSuite) TestPromptRulesAddErrors(c *C) {
+	for _, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"prompt-rules", "add", "--path-pattern=/foo", "--permissions=read", "--outcome=allow"}, "the required flag `--snap' was not specified"},
+		{[]string{"prompt-rules", "add", "--snap=firefox", "--permissions=read", "--outcome=allow"}, "a path pattern is required, use --path-pattern"},
+		{[]string{"prompt-rules", "add", "--snap=firefox", "--path-pattern=/foo", "--outcome=allow"}, "permissions are required, use --permissions"},
+		{[]string{"prompt-rules", "add", "--snap=firefox", "--path-pattern=/foo", "--permissions=read"}, "an outcome is required, use --outcome=allow or --outcome=deny"},
+	} {
+		_, err := snap.Parser(snap.Client()).ParseArgs(t.args)
+		c.Check(err, ErrorMatches, t.err, Commentf("%v", t.args))
+	}
+}
+
+func (s *SnapSuite) TestPromptRulesRemoveByID(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "POST")
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/rules/0000000000000010")
+		c.Check(decodedBody(c, r), DeepEquals, map[string]interface{}{"action": "remove"})
+		fmt.Fprintf(w, `{"type": "sync", "result": %s}`, promptRuleJSON)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "remove", "0000000000000010"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "Removed rule 0000000000000010.\n")
+}
+
+func (s *SnapSuite) TestPromptRulesRemoveBySnap(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "POST")
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/rules")
+		c.Check(decodedBody(c, r), DeepEquals, map[string]interface{}{
+			"action":   "remove",
+			"selector": map[string]interface{}{"snap": "firefox"},
+		})
+		fmt.Fprintf(w, `{"type": "sync", "result": [%s, %s]}`, promptRuleJSON, promptRuleTimespanJSON)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "remove", "--snap=firefox"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "Removed rules 0000000000000010, 0000000000000011.\n")
+}
+
+func (s *SnapSuite) TestPromptRulesRemoveErrors(c *C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "remove"})
+	c.Check(err, ErrorMatches, "a rule ID or --snap is required")
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "remove", "--snap=firefox", "0000000000000010"})
+	c.Check(err, ErrorMatches, "cannot remove a given rule together with --snap or --interface")
+}
+
+func (s *SnapSuite) TestPromptRulesPatch(c *C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/rules/0000000000000010")
+		switch n {
+		case 1:
+			c.Check(r.Method, Equals, "GET")
+			fmt.Fprintf(w, `{"type": "sync", "result": %s}`, promptRuleJSON)
+		case 2:
+			c.Check(r.Method, Equals, "POST")
+			c.Check(decodedBody(c, r), DeepEquals, map[string]interface{}{
+				"action": "patch",
+				"rule": map[string]interface{}{
+					"constraints": map[string]interface{}{
+						"path-pattern": "/home/test/Downloads/**",
+						"permissions":  []interface{}{"read"},
+					},
+					"outcome": "deny",
+				},
+			})
+			fmt.Fprintf(w, `{"type": "sync", "result": %s}`, promptRuleJSON)
+		default:
+			c.Fatalf("expected 2 requests, got %d", n)
+		}
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "patch", "--permissions=read", "--outcome=deny", "0000000000000010"})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 2)
+	c.Check(s.Stdout(), Equals, "Patched rule 0000000000000010.\n")
+}
+
+func (s *SnapSuite) TestPromptRulesPatchNothing(c *C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "patch", "0000000000000010"})
+	c.Assert(err, ErrorMatches, "nothing to change, give at least one property of the rule")
+}
+
+func (s *SnapSuite) TestPromptRulesExplain(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/rules")
+		q := r.URL.Query()
+		c.Check(q, HasLen, 4)
+		c.Check(q.Get("snap"), Equals, "firefox")
+		c.Check(q.Get("interface"), Equals, "home")
+		c.Check(q.Get("explain"), Equals, "/home/test/Downloads/foo.txt")
+		c.Check(q.Get("permission"), Equals, "read")
+		fmt.Fprintf(w, `{"type": "sync", "result": {
+			"snap": "firefox",
+			"interface": "home",
+			"path": "/home/test/Downloads/foo.txt",
+			"permission": "read",
+			"outcome": "deny",
+			"matches": [
+				{"variant": "/home/test/Downloads/*.txt", "outcome": "deny", "rules": [
+					{"id": "0000000000000012", "snap": "firefox", "interface": "home", "constraints": {"path-pattern": "/home/test/{Downloads,Documents}/*.txt", "permissions": ["read"]},
+					 "outcome": "deny", "lifespan": "timespan", "expiration": "2026-03-01T10:10:00Z", "remaining": "9m59s"}
+				]},
+				{"variant": "/home/test/Downloads/**", "outcome": "allow", "rules": [%s]}
+			]
+		}}`, promptRuleJSON)
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "explain", "--abs-time", "--snap=firefox", "--permission=read", "/home/test/Downloads/foo.txt"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `
+Outcome: deny, decided by the match with the highest precedence.
+
+Precedence  Variant                     Outcome  Rule              Path pattern                            Lifespan  Expires
+1           /home/test/Downloads/*.txt  deny     0000000000000012  /home/test/{Downloads,Documents}/*.txt  timespan  2026-03-01T10:10:00Z (in 9m59s)
+2           /home/test/Downloads/**     allow    0000000000000010  /home/test/Downloads/**                 forever   -
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestPromptRulesExplainNoMatch(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		c.Check(q.Get("interface"), Equals, "camera")
+		c.Check(q.Get("user-id"), Equals, "1000")
+		fmt.Fprint(w, `{"type": "sync", "result": {"snap": "cheese", "interface": "camera", "path": "/dev/video0", "permission": "access", "matches": []}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "explain", "--snap=cheese", "--interface=camera", "--permission=access", "--user-id=1000", "/dev/video0"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "No prompting rule matches, the request results in a prompt.\n")
+}
+
+const promptPolicyJSON = `{
+	"version": 1,
+	"rules": [
+		{
+			"snap": "libreoffice",
+			"interface": "home",
+			"path-pattern": "~/Documents/**",
+			"permissions": ["read", "write"],
+			"outcome": "allow",
+			"lifespan": "forever"
+		}
+	]
+}`
+
+func (s *SnapSuite) TestPromptRulesExport(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/policy")
+		fmt.Fprintf(w, `{"type": "sync", "result": %s}`, promptPolicyJSON)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "export"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, `
+version: 1
+rules:
+- snap: libreoffice
+  interface: home
+  path-pattern: ~/Documents/**
+  permissions:
+  - read
+  - write
+  outcome: allow
+  lifespan: forever
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+
+	s.ResetStdStreams()
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "export", "--format=json"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, `
+{
+  "version": 1,
+  "rules": [
+    {
+      "snap": "libreoffice",
+      "interface": "home",
+      "path-pattern": "~/Documents/**",
+      "permissions": [
+        "read",
+        "write"
+      ],
+      "outcome": "allow",
+      "lifespan": "forever"
+    }
+  ]
+}
+`[1:])
+}
+
+func (s *SnapSuite) TestPromptRulesExportEmpty(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"type": "sync", "result": {"version": 1, "rules": []}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "export"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "version: 1\nrules: []\n")
+}
+
+func (s *SnapSuite) TestPromptRulesImport(c *C) {
+	expectedAction := "merge"
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "POST")
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/policy")
+		c.Check(decodedBody(c, r), DeepEquals, map[string]interface{}{
+			"action": expectedAction,
+			"policy": map[string]interface{}{
+				"version": float64(1),
+				"rules": []interface{}{
+					map[string]interface{}{
+						"snap":         "libreoffice",
+						"interface":    "home",
+						"path-pattern": "~/Documents/**",
+						"permissions":  []interface{}{"read", "write"},
+						"outcome":      "allow",
+						"lifespan":     "forever",
+					},
+				},
+			},
+		})
+		if expectedAction == "replace" {
+			fmt.Fprintf(w, `{"type": "sync", "result": {"added": [%s], "removed": [%s, %s]}}`, promptRuleJSON, promptRuleJSON, promptRuleTimespanJSON)
+		} else {
+			fmt.Fprintf(w, `{"type": "sync", "result": {"added": [%s], "removed": []}}`, promptRuleJSON)
+		}
+	})
+
+	// The policy may be given as JSON
+	policyFile := filepath.Join(c.MkDir(), "policy.json")
+	c.Assert(os.WriteFile(policyFile, []byte(promptPolicyJSON), 0644), IsNil)
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "import", policyFile})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "Added 1 rule.\n")
+
+	// or as YAML on standard input
+	s.ResetStdStreams()
+	expectedAction = "replace"
+	s.stdin.WriteString(`
+version: 1
+rules:
+  - snap: libreoffice
+    interface: home
+    path-pattern: ~/Documents/**
+    permissions: [read, write]
+    outcome: allow
+    lifespan: forever
+`)
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "import", "--replace", "-"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "Removed 2 rules.\nAdded 1 rule.\n")
+}
+
+func (s *SnapSuite) TestPromptRulesImportNothingChanged(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"type": "sync", "result": {"added": [], "removed": []}}`)
+	})
+
+	s.stdin.WriteString(promptPolicyJSON)
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "import", "-"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "")
+	c.Check(s.Stderr(), Equals, "No prompting rules changed.\n")
+}
+
+func (s *SnapSuite) TestPromptRulesImportErrors(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Fatalf("unexpected request")
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "import"})
+	c.Check(err, ErrorMatches, "the required argument `<file>` was not provided")
+
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "import", filepath.Join(c.MkDir(), "missing")})
+	c.Check(err, ErrorMatches, "open .*/missing: no such file or directory")
+
+	s.stdin.WriteString("version: 1\nrulez: []\n")
+	_, err = snap.Parser(snap.Client()).ParseArgs([]string{"prompt-rules", "import", "-"})
+	c.Check(err, ErrorMatches, `(?s)cannot parse policy: .*field rulez not found.*`)
+}
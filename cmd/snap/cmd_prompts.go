@@ -0,0 +1,465 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/strutil"
+)
+
+var shortPromptsHelp = i18n.G("List and reply to permission prompts")
+var longPromptsHelp = i18n.G(`
+The prompts command lists the permission prompts which are waiting for a
+reply and replies to them, for systems without a graphical prompting client.
+`)
+
+var longPromptsReplyHelp = i18n.G(`
+The reply command replies to the given permission prompt.
+
+Unless the lifespan is "single", a prompting rule is created from the reply,
+which applies to future requests matching the given path pattern as well.
+The path pattern defaults to the path of the prompt and the permissions
+default to those requested by the prompt.
+
+With --follow, no prompt is given. Instead, the outstanding prompts are
+shown one by one and, after those, new prompts as they are created, asking
+interactively for the outcome, lifespan and path pattern of each reply.
+`)
+
+type cmdPrompts struct{}
+
+type promptingUserMixin struct {
+	UserID string `long:"user-id"`
+}
+
+var promptingUserDescs = mixinDescs{
+	// TRANSLATORS: This should not start with a lowercase letter.
+	"user-id": i18n.G("Operate on the prompts and rules of the user with the given ID instead (admin only)"),
+}
+
+func (mx promptingUserMixin) promptingOptions() (*client.PromptingOptions, error) {
+	if mx.UserID == "" {
+		return nil, nil
+	}
+	userID, err := strconv.ParseUint(mx.UserID, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("invalid user ID %q"), mx.UserID)
+	}
+	uid := uint32(userID)
+	return &client.PromptingOptions{UserID: &uid}, nil
+}
+
+type promptIDArg struct {
+	ID string
+}
+
+var promptIDArgDesc = []argDesc{{
+	// TRANSLATORS: This needs to begin with < and end with >
+	name: i18n.G("<prompt-id>"),
+	// TRANSLATORS: This should not start with a lowercase letter.
+	desc: i18n.G("The ID of the prompt"),
+}}
+
+type cmdPromptsList struct {
+	clientMixin
+	timeMixin
+	promptingUserMixin
+}
+
+type cmdPromptsShow struct {
+	clientMixin
+	timeMixin
+	promptingUserMixin
+	Positional promptIDArg `positional-args:"yes" required:"yes"`
+}
+
+type cmdPromptsReply struct {
+	clientMixin
+	promptingUserMixin
+	Outcome     string `long:"outcome"`
+	Lifespan    string `long:"lifespan"`
+	Duration    string `long:"duration"`
+	PathPattern string `long:"path-pattern"`
+	Permissions string `long:"permissions"`
+	Follow      bool   `long:"follow"`
+	Positional  struct {
+		ID string
+	} `positional-args:"yes"`
+}
+
+// promptsFollowTimeout is how long a single request for new prompt notices
+// waits before it is repeated.
+var promptsFollowTimeout = 5 * time.Minute
+
+func init() {
+	cmd := addCommand("prompts", shortPromptsHelp, longPromptsHelp, func() flags.Commander {
+		return &cmdPrompts{}
+	}, nil, nil)
+	cmd.subcommands = []*cmdInfo{{
+		name:      "list",
+		shortHelp: i18n.G("List the outstanding permission prompts"),
+		longHelp: i18n.G(`
+The list command lists the permission prompts which are waiting for a reply.
+`),
+		builder:  func() flags.Commander { return &cmdPromptsList{} },
+		optDescs: timeDescs.also(promptingUserDescs),
+	}, {
+		name:      "show",
+		shortHelp: i18n.G("Show the details of a permission prompt"),
+		longHelp: i18n.G(`
+The show command shows the details of the given permission prompt.
+`),
+		builder:  func() flags.Commander { return &cmdPromptsShow{} },
+		optDescs: timeDescs.also(promptingUserDescs),
+		argDescs: promptIDArgDesc,
+	}, {
+		name:      "reply",
+		shortHelp: i18n.G("Reply to permission prompts"),
+		longHelp:  longPromptsReplyHelp,
+		builder:   func() flags.Commander { return &cmdPromptsReply{} },
+		optDescs: promptingUserDescs.also(map[string]string{
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"outcome": i18n.G("Whether to allow or deny the request (allow|deny)"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"lifespan": i18n.G("How long the reply applies (single|timespan|forever, default: single)"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"duration": i18n.G("How long the reply applies when the lifespan is timespan, such as 10m"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"path-pattern": i18n.G("The path pattern the reply applies to"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"permissions": i18n.G("Comma-separated list of the permissions the reply applies to"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"follow": i18n.G("Wait for prompts and reply to them interactively"),
+		}),
+		argDescs: []argDesc{{
+			// TRANSLATORS: This needs to begin with < and end with >
+			name: i18n.G("<prompt-id>"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			desc: i18n.G("The ID of the prompt to reply to"),
+		}},
+	}}
+}
+
+func (x *cmdPrompts) Execute(args []string) error {
+	return flag.ErrHelp
+}
+
+func fmtPromptPermissions(permissions []string) string {
+	if len(permissions) == 0 {
+		return "-"
+	}
+	return strings.Join(permissions, ",")
+}
+
+func (x *cmdPromptsList) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	opts, err := x.promptingOptions()
+	if err != nil {
+		return err
+	}
+
+	prompts, err := x.client.Prompts(opts)
+	if err != nil {
+		return err
+	}
+	if len(prompts) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("No outstanding prompts."))
+		return nil
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintln(w, i18n.G("ID\tTimestamp\tSnap\tInterface\tPath\tRequested"))
+	for _, p := range prompts {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", p.ID, x.fmtTime(p.Timestamp), p.Snap, p.Interface,
+			p.Constraints.Path, fmtPromptPermissions(p.Constraints.RequestedPermissions))
+	}
+	return nil
+}
+
+func (x *cmdPromptsShow) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	opts, err := x.promptingOptions()
+	if err != nil {
+		return err
+	}
+
+	prompt, err := x.client.Prompt(x.Positional.ID, opts)
+	if err != nil {
+		return err
+	}
+
+	w := tabWriter()
+	defer w.Flush()
+
+	fmt.Fprintf(w, "id:\t%s\n", prompt.ID)
+	fmt.Fprintf(w, "timestamp:\t%s\n", x.fmtTime(prompt.Timestamp))
+	fmt.Fprintf(w, "snap:\t%s\n", prompt.Snap)
+	fmt.Fprintf(w, "interface:\t%s\n", prompt.Interface)
+	fmt.Fprintf(w, "path:\t%s\n", prompt.Constraints.Path)
+	fmt.Fprintf(w, "requested-permissions:\t%s\n", fmtPromptPermissions(prompt.Constraints.RequestedPermissions))
+	fmt.Fprintf(w, "available-permissions:\t%s\n", fmtPromptPermissions(prompt.Constraints.AvailablePermissions))
+	return nil
+}
+
+func isPromptNotFound(err error) bool {
+	var e *client.Error
+	return errors.As(err, &e) && e.Kind == client.ErrorKindInterfacesRequestsPromptNotFound
+}
+
+func (x *cmdPromptsReply) Execute(args []string) error {
+	if len(args) > 0 {
+		return ErrExtraArgs
+	}
+	opts, err := x.promptingOptions()
+	if err != nil {
+		return err
+	}
+
+	if x.Follow {
+		if x.Positional.ID != "" {
+			return errors.New(i18n.G("cannot reply to a given prompt with --follow"))
+		}
+		if x.Outcome != "" || x.Lifespan != "" || x.Duration != "" || x.PathPattern != "" || x.Permissions != "" {
+			return errors.New(i18n.G("cannot use reply options with --follow, replies are given interactively"))
+		}
+		return x.follow(opts)
+	}
+
+	if x.Positional.ID == "" {
+		return errors.New(i18n.G("a prompt ID is required unless --follow is given"))
+	}
+	if x.Outcome == "" {
+		return errors.New(i18n.G("an outcome is required, use --outcome=allow or --outcome=deny"))
+	}
+	reply := &client.PromptReply{
+		Outcome:  x.Outcome,
+		Lifespan: x.Lifespan,
+		Duration: x.Duration,
+		Constraints: &client.RuleConstraints{
+			PathPattern: x.PathPattern,
+			Permissions: strutil.CommaSeparatedList(x.Permissions),
+		},
+	}
+	if reply.Lifespan == "" {
+		reply.Lifespan = "single"
+	}
+	if reply.Constraints.PathPattern == "" || len(reply.Constraints.Permissions) == 0 {
+		prompt, err := x.client.Prompt(x.Positional.ID, opts)
+		if err != nil {
+			return err
+		}
+		if reply.Constraints.PathPattern == "" {
+			reply.Constraints.PathPattern = prompt.Constraints.Path
+		}
+		if len(reply.Constraints.Permissions) == 0 {
+			reply.Constraints.Permissions = prompt.Constraints.RequestedPermissions
+		}
+	}
+
+	satisfied, err := x.client.ReplyToPrompt(x.Positional.ID, reply, opts)
+	if err != nil {
+		return err
+	}
+	printPromptReplied(x.Positional.ID, satisfied)
+	return nil
+}
+
+func printPromptReplied(id string, satisfied []string) {
+	others := 0
+	for _, satisfiedID := range satisfied {
+		if satisfiedID != id {
+			others++
+		}
+	}
+	if others == 0 {
+		fmt.Fprintf(Stdout, i18n.G("Replied to prompt %s.\n"), id)
+		return
+	}
+	// TRANSLATORS: %s is a prompt ID, %d the number of other prompts
+	fmt.Fprintf(Stdout, i18n.NG("Replied to prompt %s, which also resolved %d other prompt.\n",
+		"Replied to prompt %s, which also resolved %d other prompts.\n", others), id, others)
+}
+
+// follow replies interactively to the outstanding prompts and then to new
+// prompts as they are created, until the input ends.
+func (x *cmdPromptsReply) follow(opts *client.PromptingOptions) error {
+	noticesOpts := &client.NoticesOptions{
+		Types: []client.NoticeType{client.InterfacesRequestsPromptNotice},
+	}
+	if opts != nil {
+		noticesOpts.UserID = opts.UserID
+	}
+	// only consider notices for prompts created after the outstanding ones
+	// have been listed, those are replied to first
+	notices, err := x.client.Notices(noticesOpts)
+	if err != nil {
+		return err
+	}
+	for _, n := range notices {
+		noticesOpts.After = n.LastRepeated
+	}
+	prompts, err := x.client.Prompts(opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(Stderr, i18n.G("Waiting for prompts, press Ctrl+D to stop."))
+	reader := bufio.NewReader(Stdin)
+	for {
+		for _, prompt := range prompts {
+			if err := x.replyInteractively(reader, prompt, opts); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+		prompts = nil
+
+		noticesOpts.Timeout = promptsFollowTimeout
+		notices, err := x.client.Notices(noticesOpts)
+		if err != nil {
+			return err
+		}
+		for _, n := range notices {
+			noticesOpts.After = n.LastRepeated
+			if n.LastData["resolved"] != "" {
+				continue
+			}
+			prompt, err := x.client.Prompt(n.Key, opts)
+			if isPromptNotFound(err) {
+				// resolved in the meantime
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			prompts = append(prompts, prompt)
+		}
+	}
+}
+
+func (x *cmdPromptsReply) replyInteractively(reader *bufio.Reader, prompt *client.Prompt, opts *client.PromptingOptions) error {
+	fmt.Fprintln(Stdout)
+	// TRANSLATORS: %q is a snap name, the first %s the requested permissions, the second a path and the third an interface name
+	fmt.Fprintf(Stdout, i18n.G("Snap %q requests %s permissions on %s through the %s interface.\n"),
+		prompt.Snap, fmtPromptPermissions(prompt.Constraints.RequestedPermissions), prompt.Constraints.Path, prompt.Interface)
+
+	for {
+		reply, err := askPromptReply(reader, prompt)
+		if err != nil {
+			return err
+		}
+		satisfied, err := x.client.ReplyToPrompt(prompt.ID, reply, opts)
+		if isPromptNotFound(err) {
+			fmt.Fprintf(Stdout, i18n.G("Prompt %s was resolved in the meantime.\n"), prompt.ID)
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintf(Stderr, i18n.G("cannot reply to prompt: %v\n"), err)
+			continue
+		}
+		printPromptReplied(prompt.ID, satisfied)
+		return nil
+	}
+}
+
+func askPromptReply(reader *bufio.Reader, prompt *client.Prompt) (*client.PromptReply, error) {
+	outcome, err := askPromptQuestion(reader, i18n.G("Outcome"), "deny", []string{"allow", "deny"})
+	if err != nil {
+		return nil, err
+	}
+	lifespan, err := askPromptQuestion(reader, i18n.G("Lifespan"), "single", []string{"single", "timespan", "forever"})
+	if err != nil {
+		return nil, err
+	}
+	var duration string
+	if lifespan == "timespan" {
+		duration, err = askPromptQuestion(reader, i18n.G("Duration"), "", nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	pathPattern, err := askPromptQuestion(reader, i18n.G("Path pattern"), prompt.Constraints.Path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &client.PromptReply{
+		Outcome:  outcome,
+		Lifespan: lifespan,
+		Duration: duration,
+		Constraints: &client.RuleConstraints{
+			PathPattern: pathPattern,
+			Permissions: prompt.Constraints.RequestedPermissions,
+		},
+	}, nil
+}
+
+// askPromptQuestion asks the given question until it gets an answer which is
+// one of the given choices, if any. An empty answer stands for the default,
+// if there is one.
+func askPromptQuestion(reader *bufio.Reader, question, def string, choices []string) (string, error) {
+	for {
+		fmt.Fprint(Stdout, question)
+		if len(choices) > 0 {
+			fmt.Fprintf(Stdout, " (%s)", strings.Join(choices, "/"))
+		}
+		if def != "" {
+			fmt.Fprintf(Stdout, " [%s]", def)
+		}
+		fmt.Fprint(Stdout, ": ")
+
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if err != nil && (err != io.EOF || answer == "") {
+			return "", err
+		}
+		if answer == "" {
+			answer = def
+		}
+		switch {
+		case answer == "":
+			fmt.Fprintln(Stderr, i18n.G("an answer is required"))
+		case len(choices) > 0 && !strutil.ListContains(choices, answer):
+			// TRANSLATORS: %q is the given answer, %s the list of valid answers
+			fmt.Fprintf(Stderr, i18n.G("%q is not one of %s\n"), answer, strings.Join(choices, ", "))
+		default:
+			return answer, nil
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	snap "github.com/snapcore/snapd/cmd/snap"
+)
+
+const promptAJSON = `{
+	"id": "000000000000000A",
+	"timestamp": "2026-03-01T10:00:00Z",
+	"snap": "firefox",
+	"interface": "home",
+	"constraints": {
+		"path": "/home/test/Downloads/foo.txt",
+		"requested-permissions": ["read", "write"],
+		"available-permissions": ["read", "write", "execute"]
+	}
+}`
+
+const promptBJSON = `{
+	"id": "000000000000000B",
+	"timestamp": "2026-03-01T10:05:00Z",
+	"snap": "cheese",
+	"interface": "camera",
+	"constraints": {
+		"path": "/dev/video0",
+		"requested-permissions": ["access"],
+		"available-permissions": ["access"]
+	}
+}`
+
+func decodedBody(c *C, r *http.Request) map[string]interface{} {
+	body, err := io.ReadAll(r.Body)
+	c.Assert(err, IsNil)
+	var m map[string]interface{}
+	c.Assert(json.Unmarshal(body, &m), IsNil)
+	return m
+}
+
+func (s *SnapSuite) TestPromptsList(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/prompts")
+		c.Check(r.URL.Query().Get("user-id"), Equals, "1000")
+		fmt.Fprintf(w, `{"type": "sync", "result": [%s, %s]}`, promptAJSON, promptBJSON)
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompts", "list", "--abs-time", "--user-id=1000"})
+	c.Assert(err, IsNil)
+	c.Assert(rest, DeepEquals, []string{})
+	c.Check(s.Stdout(), Equals, `
+ID                Timestamp             Snap     Interface  Path                          Requested
+000000000000000A  2026-03-01T10:00:00Z  firefox  home       /home/test/Downloads/foo.txt  read,write
+000000000000000B  2026-03-01T10:05:00Z  cheese   camera     /dev/video0                   access
+`[1:])
+	c.Check(s.Stderr(), Equals, "")
+}
+
+func (s *SnapSuite) TestPromptsListEmpty(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.RawQuery, Equals, "")
+		fmt.Fprint(w, `{"type": "sync", "result": []}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompts", "list"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, "")
+	c.Check(s.Stderr(), Equals, "No outstanding prompts.\n")
+}
+
+func (s *SnapSuite) TestPromptsBadUserID(c *C) {
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompts", "list", "--user-id=bob"})
+	c.Assert(err, ErrorMatches, `invalid user ID "bob"`)
+}
+
+func (s *SnapSuite) TestPromptsShow(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, Equals, "GET")
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/prompts/000000000000000A")
+		fmt.Fprintf(w, `{"type": "sync", "result": %s}`, promptAJSON)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompts", "show", "--abs-time", "000000000000000A"})
+	c.Assert(err, IsNil)
+	c.Check(s.Stdout(), Equals, `
+id:                     000000000000000A
+timestamp:              2026-03-01T10:00:00Z
+snap:                   firefox
+interface:              home
+path:                   /home/test/Downloads/foo.txt
+requested-permissions:  read,write
+available-permissions:  read,write,execute
+`[1:])
+}
+
+func (s *SnapSuite) TestPromptsShowNotFound(c *C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		fmt.Fprint(w, `{"type": "error", "status-code": 404, "result": {"message": "cannot find prompt with the given ID for the given user", "kind": "interfaces-requests-prompt-not-found"}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompts", "show", "000000000000000A"})
+	c.Assert(err, ErrorMatches, "cannot find prompt with the given ID for the given user")
+}
+
+func (s *SnapSuite) TestPromptsReply(c *C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/prompts/000000000000000A")
+		switch n {
+		case 1:
+			c.Check(r.Method, Equals, "GET")
+			fmt.Fprintf(w, `{"type": "sync", "result": %s}`, promptAJSON)
+		case 2:
+			c.Check(r.Method, Equals, "POST")
+			c.Check(decodedBody(c, r), DeepEquals, map[string]interface{}{
+				"action":   "allow",
+				"lifespan": "single",
+				"constraints": map[string]interface{}{
+					"path-pattern": "/home/test/Downloads/foo.txt",
+					"permissions":  []interface{}{"read", "write"},
+				},
+			})
+			fmt.Fprint(w, `{"type": "sync", "result": ["000000000000000A"]}`)
+		default:
+			c.Fatalf("expected 2 requests, got %d", n)
+		}
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompts", "reply", "--outcome=allow", "000000000000000A"})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 2)
+	c.Check(s.Stdout(), Equals, "Replied to prompt 000000000000000A.\n")
+}
+
+func (s *SnapSuite) TestPromptsReplyAllGiven(c *C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		c.Check(r.Method, Equals, "POST")
+		c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/prompts/000000000000000A")
+		c.Check(decodedBody(c, r), DeepEquals, map[string]interface{}{
+			"action":   "deny",
+			"lifespan": "timespan",
+			"duration": "10m",
+			"constraints": map[string]interface{}{
+				"path-pattern": "/home/test/Downloads/**",
+				"permissions":  []interface{}{"read", "write", "execute"},
+			},
+		})
+		fmt.Fprint(w, `{"type": "sync", "result": ["000000000000000A", "000000000000000C"]}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompts", "reply", "--outcome=deny",
+		"--lifespan=timespan", "--duration=10m", "--path-pattern=/home/test/Downloads/**",
+		"--permissions=read,write,execute", "000000000000000A"})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 1)
+	c.Check(s.Stdout(), Equals, "Replied to prompt 000000000000000A, which also resolved 1 other prompt.\n")
+}
+
+func (s *SnapSuite) TestPromptsReplyErrors(c *C) {
+	for _, t := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"prompts", "reply", "--outcome=allow"}, "a prompt ID is required unless --follow is given"},
+		{[]string{"prompts", "reply", "000000000000000A"}, "an outcome is required, use --outcome=allow or --outcome=deny"},
+		{[]string{"prompts", "reply", "--follow", "000000000000000A"}, "cannot reply to a given prompt with --follow"},
+		{[]string{"prompts", "reply", "--follow", "--outcome=allow"}, "cannot use reply options with --follow, replies are given interactively"},
+	} {
+		_, err := snap.Parser(snap.Client()).ParseArgs(t.args)
+		c.Check(err, ErrorMatches, t.err, Commentf("%v", t.args))
+	}
+}
+
+func (s *SnapSuite) TestPromptsReplyFollow(c *C) {
+	s.stdin.WriteString("maybe\nallow\nforever\n/home/test/Downloads/**\ndeny\n")
+
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		switch n {
+		case 1:
+			c.Check(r.Method, Equals, "GET")
+			c.Check(r.URL.Path, Equals, "/v2/notices")
+			c.Check(r.URL.Query().Get("types"), Equals, "interfaces-requests-prompt")
+			c.Check(r.URL.Query().Get("timeout"), Equals, "")
+			fmt.Fprint(w, `{"type": "sync", "result": [{"id": "1", "type": "interfaces-requests-prompt", "key": "0000000000000009", "last-repeated": "2026-03-01T09:00:00Z", "last-data": {"resolved": "replied"}}]}`)
+		case 2:
+			c.Check(r.Method, Equals, "GET")
+			c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/prompts")
+			fmt.Fprintf(w, `{"type": "sync", "result": [%s]}`, promptAJSON)
+		case 3:
+			c.Check(r.Method, Equals, "POST")
+			c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/prompts/000000000000000A")
+			c.Check(decodedBody(c, r), DeepEquals, map[string]interface{}{
+				"action":   "allow",
+				"lifespan": "forever",
+				"constraints": map[string]interface{}{
+					"path-pattern": "/home/test/Downloads/**",
+					"permissions":  []interface{}{"read", "write"},
+				},
+			})
+			fmt.Fprint(w, `{"type": "sync", "result": ["000000000000000A"]}`)
+		case 4:
+			c.Check(r.Method, Equals, "GET")
+			c.Check(r.URL.Path, Equals, "/v2/notices")
+			c.Check(r.URL.Query().Get("after"), Equals, "2026-03-01T09:00:00Z")
+			c.Check(r.URL.Query().Get("timeout"), Equals, "5m0s")
+			fmt.Fprint(w, `{"type": "sync", "result": [
+				{"id": "2", "type": "interfaces-requests-prompt", "key": "000000000000000A", "last-repeated": "2026-03-01T10:01:00Z", "last-data": {"resolved": "replied"}},
+				{"id": "3", "type": "interfaces-requests-prompt", "key": "000000000000000B", "last-repeated": "2026-03-01T10:05:00Z"}
+			]}`)
+		case 5:
+			c.Check(r.Method, Equals, "GET")
+			c.Check(r.URL.Path, Equals, "/v2/interfaces/requests/prompts/000000000000000B")
+			fmt.Fprintf(w, `{"type": "sync", "result": %s}`, promptBJSON)
+		default:
+			c.Fatalf("expected 5 requests, got %d", n)
+		}
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"prompts", "reply", "--follow"})
+	c.Assert(err, IsNil)
+	c.Check(n, Equals, 5)
+	c.Check(s.Stdout(), Equals, `
+Snap "firefox" requests read,write permissions on /home/test/Downloads/foo.txt through the home interface.
+Outcome (allow/deny) [deny]: Outcome (allow/deny) [deny]: Lifespan (single/timespan/forever) [single]: Path pattern [/home/test/Downloads/foo.txt]: Replied to prompt 000000000000000A.
+
+Snap "cheese" requests access permissions on /dev/video0 through the camera interface.
+Outcome (allow/deny) [deny]: Lifespan (single/timespan/forever) [single]: `)
+	c.Check(s.Stderr(), Equals, `Waiting for prompts, press Ctrl+D to stop.
+"maybe" is not one of allow, deny
+`)
+}
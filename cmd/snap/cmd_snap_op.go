@@ -38,6 +38,7 @@ import (
 	"github.com/snapcore/snapd/release"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/channel"
+	"github.com/snapcore/snapd/snap/snapfile"
 	"github.com/snapcore/snapd/strutil"
 )
 
@@ -71,6 +72,11 @@ override, taking the snap back to the current revision of the channel it's
 tracking.
 
 Use --name to set the instance name when installing from snap file.
+
+The --from-dir option installs all the .snap files in the given directory,
+for example on removable media, in a single transaction. The assertions in
+the .assert files of the directory are acknowledged first, so that the snaps
+are installed as asserted revisions.
 `)
 
 var longRemoveHelp = i18n.G(`
@@ -106,6 +112,17 @@ requests from 'snap refresh target-snap'.
 When snaps are specified --hold is effective on both their auto-refreshes
 and general refresh requests from 'snap refresh'. However, specific snap
 requests from 'snap refresh target-snap' remain unblocked and will proceed.
+
+The --dry-run option shows the revisions the snaps would be refreshed to, the
+download sizes, and whether the refreshes need a reboot, install additional
+snaps or components, are constrained by validation sets, or have their
+auto-refreshes held, without refreshing anything.
+
+The --from-dir option refreshes the installed snaps from the .snap files in
+the given directory, for example on removable media, in a single transaction.
+The assertions in the .assert files of the directory are acknowledged first,
+so that the snaps are refreshed to asserted revisions. Snap files of snaps
+which are not installed are skipped.
 `)
 
 var longTryHelp = i18n.G(`
@@ -131,6 +148,7 @@ and the snap can easily be enabled again.
 
 type cmdRemove struct {
 	waitMixin
+	scheduleMixin
 
 	Revision   string `long:"revision"`
 	Purge      bool   `long:"purge"`
@@ -310,7 +328,10 @@ func (x *cmdRemove) removeMany(opts *client.SnapOptions) error {
 }
 
 func (x *cmdRemove) Execute([]string) error {
-	opts := &client.SnapOptions{Revision: x.Revision, Purge: x.Purge, Terminate: x.Terminate}
+	if err := x.setScheduledTime(&x.waitMixin); err != nil {
+		return err
+	}
+	opts := &client.SnapOptions{Revision: x.Revision, Purge: x.Purge, Terminate: x.Terminate, At: x.At}
 	if len(x.Positional.Snaps) == 1 {
 		return x.removeOne(opts)
 	}
@@ -321,6 +342,28 @@ func (x *cmdRemove) Execute([]string) error {
 	return x.removeMany(opts)
 }
 
+type scheduleMixin struct {
+	At string `long:"at"`
+}
+
+var scheduleDescs = mixinDescs{
+	// TRANSLATORS: This should not start with a lowercase letter.
+	"at": i18n.G("Start the operation no earlier than the given time (in RFC3339 format)"),
+}
+
+// setScheduledTime validates the time given with --at and makes the
+// wait mixin not wait for the scheduled change.
+func (mx *scheduleMixin) setScheduledTime(wmx *waitMixin) error {
+	if mx.At == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, mx.At); err != nil {
+		return fmt.Errorf(i18n.G("cannot parse --at time %q: expected RFC3339 format, e.g. 2006-01-02T15:04:05Z"), mx.At)
+	}
+	wmx.scheduledAt = mx.At
+	return nil
+}
+
 type channelMixin struct {
 	Channel string `long:"channel"`
 
@@ -686,6 +729,7 @@ func (mx modeMixin) setModes(opts *client.SnapOptions) {
 type cmdInstall struct {
 	colorMixin
 	waitMixin
+	scheduleMixin
 
 	channelMixin
 	modeMixin
@@ -706,9 +750,10 @@ type cmdInstall struct {
 	IgnoreRunning    bool                   `long:"ignore-running" hidden:"yes"`
 	Transaction      client.TransactionType `long:"transaction" default:"per-snap" choice:"all-snaps" choice:"per-snap"`
 	QuotaGroupName   string                 `long:"quota-group"`
+	FromDir          string                 `long:"from-dir"`
 	Positional       struct {
-		Snaps []remoteSnapName `positional-arg-name:"<snap>" required:"1"`
-	} `positional-args:"yes" required:"yes"`
+		Snaps []remoteSnapName `positional-arg-name:"<snap>"`
+	} `positional-args:"yes"`
 }
 
 func (x *cmdInstall) installOne(nameOrPath, desiredName string, opts *client.SnapOptions) error {
@@ -765,6 +810,74 @@ func (x *cmdInstall) installOne(nameOrPath, desiredName string, opts *client.Sna
 	return showDone(x.client, chg, changedSnaps, "install", opts, x.getEscapes())
 }
 
+// snapsAndAssertionsInDir returns the paths of the snap files and of the
+// assertion files in the given directory.
+func snapsAndAssertionsInDir(dir string) (snaps, assertions []string, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		switch filepath.Ext(entry.Name()) {
+		case ".snap":
+			snaps = append(snaps, path)
+		case ".assert":
+			assertions = append(assertions, path)
+		}
+	}
+	if len(snaps) == 0 {
+		return nil, nil, fmt.Errorf(i18n.G("cannot find any snaps in %q"), dir)
+	}
+	return snaps, assertions, nil
+}
+
+// sideloadWithAssertions installs the given snap files, as asserted by the
+// given assertion files, in a single transaction.
+func sideloadWithAssertions(wmx waitMixin, snaps, assertions []string, op string, opts *client.SnapOptions, esc *escapes) error {
+	// don't log the request's body because the encoded snaps are large
+	wmx.client.SetMayLogBody(false)
+	opts.Transaction = client.TransactionAllSnaps
+	changeID, err := wmx.client.InstallPathManyWithAssertions(snaps, assertions, opts)
+	if err != nil {
+		return err
+	}
+
+	chg, err := wmx.wait(changeID)
+	if err != nil {
+		if err == noWait {
+			return nil
+		}
+		return err
+	}
+
+	changedSnaps, err := changedSnapsFromChange(chg)
+	if err != nil && err != client.ErrNoData {
+		return err
+	}
+	if changedSnaps != nil && changedSnaps.hasChanges() {
+		return showDone(wmx.client, chg, changedSnaps, op, opts, esc)
+	}
+	return nil
+}
+
+func (x *cmdInstall) installFromDir(opts *client.SnapOptions) error {
+	if len(x.Positional.Snaps) > 0 || x.asksForChannel() || x.asksForMode() ||
+		x.Revision != "" || opts.Dangerous || x.Name != "" || x.Cohort != "" ||
+		x.IgnoreValidation || x.Prefer || x.At != "" {
+		return errors.New(i18n.G("cannot use --from-dir with snap names, channel, mode, revision or scheduling options"))
+	}
+
+	snaps, assertions, err := snapsAndAssertionsInDir(x.FromDir)
+	if err != nil {
+		return err
+	}
+	return sideloadWithAssertions(x.waitMixin, snaps, assertions, "install", opts, x.getEscapes())
+}
+
 func isLocalContainer(name string) bool {
 	return strings.Contains(name, "/") ||
 		strings.HasSuffix(name, ".snap") || strings.Contains(name, ".snap.") ||
@@ -862,6 +975,9 @@ func (x *cmdInstall) Execute([]string) error {
 	if err := x.validateMode(); err != nil {
 		return err
 	}
+	if err := x.setScheduledTime(&x.waitMixin); err != nil {
+		return err
+	}
 
 	dangerous := x.Dangerous || x.ForceDangerous
 	opts := &client.SnapOptions{
@@ -875,14 +991,28 @@ func (x *cmdInstall) Execute([]string) error {
 		Transaction:      x.Transaction,
 		QuotaGroupName:   x.QuotaGroupName,
 		Prefer:           x.Prefer,
+		At:               x.At,
 	}
 	x.setModes(opts)
 
+	if x.FromDir != "" {
+		return x.installFromDir(opts)
+	}
+
 	names := remoteSnapNames(x.Positional.Snaps)
+	if len(names) == 0 {
+		return &flags.Error{
+			Type:    flags.ErrRequired,
+			Message: i18n.G("the required argument `<snap> (at least 1 argument)` was not provided"),
+		}
+	}
 	for _, name := range names {
 		if len(name) == 0 {
 			return errors.New(i18n.G("cannot install snap with empty name"))
 		}
+		if x.At != "" && isLocalContainer(name) {
+			return errors.New(i18n.G("cannot schedule the installation of local snaps"))
+		}
 	}
 
 	if len(names) == 1 {
@@ -909,6 +1039,7 @@ type cmdRefresh struct {
 	colorMixin
 	timeMixin
 	waitMixin
+	scheduleMixin
 	channelMixin
 	modeMixin
 
@@ -923,6 +1054,8 @@ type cmdRefresh struct {
 	Transaction      client.TransactionType `long:"transaction" default:"per-snap" choice:"all-snaps" choice:"per-snap"`
 	Hold             string                 `long:"hold" optional:"yes" optional-value:"forever"`
 	Unhold           bool                   `long:"unhold"`
+	DryRun           bool                   `long:"dry-run"`
+	FromDir          string                 `long:"from-dir"`
 	Positional       struct {
 		Snaps []installedSnapName `positional-arg-name:"<snap>"`
 	} `positional-args:"yes"`
@@ -963,6 +1096,55 @@ func (x *cmdRefresh) refreshMany(snaps []string, opts *client.SnapOptions) error
 	return nil
 }
 
+var snapNameFromFile = func(path string) (string, error) {
+	snapf, err := snapfile.Open(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := snap.ReadInfoFromSnapFile(snapf, nil)
+	if err != nil {
+		return "", err
+	}
+	return info.SnapName(), nil
+}
+
+func (x *cmdRefresh) refreshFromDir() error {
+	snaps, assertions, err := snapsAndAssertionsInDir(x.FromDir)
+	if err != nil {
+		return err
+	}
+
+	installed, err := x.client.List(nil, nil)
+	if err != nil && err != client.ErrNoSnapsInstalled {
+		return err
+	}
+	isInstalled := make(map[string]bool, len(installed))
+	for _, sn := range installed {
+		isInstalled[sn.Name] = true
+	}
+
+	// only snaps which are already installed are refreshed
+	var toRefresh []string
+	for _, path := range snaps {
+		name, err := snapNameFromFile(path)
+		if err != nil {
+			return fmt.Errorf(i18n.G("cannot read snap file %q: %v"), path, err)
+		}
+		if !isInstalled[name] {
+			fmt.Fprintf(Stderr, i18n.G("Skipping %s: snap %q is not installed\n"), path, name)
+			continue
+		}
+		toRefresh = append(toRefresh, path)
+	}
+	if len(toRefresh) == 0 {
+		fmt.Fprintf(Stderr, i18n.G("No snaps in %q are installed.\n"), x.FromDir)
+		return nil
+	}
+
+	opts := &client.SnapOptions{IgnoreRunning: x.IgnoreRunning}
+	return sideloadWithAssertions(x.waitMixin, toRefresh, assertions, "refresh", opts, x.getEscapes())
+}
+
 func (x *cmdRefresh) refreshOne(name string, opts *client.SnapOptions) error {
 	snapName, comps := snap.SplitSnapInstanceAndComponents(name)
 	if name == "" {
@@ -1043,6 +1225,36 @@ func (x *cmdRefresh) showRefreshTimes() error {
 	} else {
 		fmt.Fprintf(Stdout, "next: n/a\n")
 	}
+
+	if len(sysinfo.Refresh.Snaps) == 0 {
+		return nil
+	}
+	// snaps with their own timer are not subject to the system timer
+	names := make([]string, 0, len(sysinfo.Refresh.Snaps))
+	for name := range sysinfo.Refresh.Snaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(Stdout, "snaps:\n")
+	for _, name := range names {
+		info := sysinfo.Refresh.Snaps[name]
+		fmt.Fprintf(Stdout, "  %s:\n", name)
+		fmt.Fprintf(Stdout, "    timer: %s\n", info.Timer)
+		if last := parseSysinfoTime(info.Last); !last.IsZero() {
+			fmt.Fprintf(Stdout, "    last: %s\n", x.fmtTime(last))
+		} else {
+			fmt.Fprintf(Stdout, "    last: n/a\n")
+		}
+		next := parseSysinfoTime(info.Next)
+		switch {
+		case next.IsZero():
+			fmt.Fprintf(Stdout, "    next: n/a\n")
+		case !next.After(hold):
+			fmt.Fprintf(Stdout, "    next: %s (but held)\n", x.fmtTime(next))
+		default:
+			fmt.Fprintf(Stdout, "    next: %s\n", x.fmtTime(next))
+		}
+	}
 	return nil
 }
 
@@ -1081,6 +1293,16 @@ func (x *cmdRefresh) Execute([]string) error {
 		return err
 	}
 
+	if x.FromDir != "" {
+		if len(x.Positional.Snaps) > 0 || x.asksForMode() || x.asksForChannel() ||
+			x.Amend || x.Revision != "" || x.Cohort != "" || x.LeaveCohort ||
+			x.List || x.Time || x.IgnoreValidation || x.Hold != "" || x.Unhold ||
+			x.DryRun || x.At != "" {
+			return errors.New(i18n.G("cannot use --from-dir with snap names or with other options"))
+		}
+		return x.refreshFromDir()
+	}
+
 	if x.Time {
 		if x.asksForMode() || x.asksForChannel() {
 			return errors.New(i18n.G("--time does not take mode or channel flags"))
@@ -1103,7 +1325,7 @@ func (x *cmdRefresh) Execute([]string) error {
 
 	otherFlags := x.Amend || x.Revision != "" || x.Cohort != "" ||
 		x.LeaveCohort || x.List || x.Time || x.IgnoreValidation || x.IgnoreRunning ||
-		x.Transaction != client.TransactionPerSnap
+		x.Transaction != client.TransactionPerSnap || x.At != "" || x.DryRun
 
 	if x.Hold != "" && (x.Unhold || otherFlags) {
 		return errors.New(i18n.G("cannot use --hold with other flags"))
@@ -1115,9 +1337,18 @@ func (x *cmdRefresh) Execute([]string) error {
 		return x.unholdRefreshes()
 	}
 
+	if x.DryRun && x.At != "" {
+		return errors.New(i18n.G("cannot use --dry-run with --at"))
+	}
+
+	if err := x.setScheduledTime(&x.waitMixin); err != nil {
+		return err
+	}
+
 	names := installedSnapNames(x.Positional.Snaps)
 	if len(names) == 1 {
 		opts := &client.SnapOptions{
+			At:               x.At,
 			Amend:            x.Amend,
 			Channel:          x.Channel,
 			IgnoreValidation: x.IgnoreValidation,
@@ -1128,6 +1359,9 @@ func (x *cmdRefresh) Execute([]string) error {
 			Transaction:      x.Transaction,
 		}
 		x.setModes(opts)
+		if x.DryRun {
+			return x.showRefreshPlan(names, opts)
+		}
 		return x.refreshOne(names[0], opts)
 	}
 	// transaction flag and ignore-running flags are the only ones with meaning when
@@ -1135,6 +1369,7 @@ func (x *cmdRefresh) Execute([]string) error {
 	opts := &client.SnapOptions{
 		IgnoreRunning: x.IgnoreRunning,
 		Transaction:   x.Transaction,
+		At:            x.At,
 	}
 
 	if x.asksForMode() || x.asksForChannel() {
@@ -1145,9 +1380,62 @@ func (x *cmdRefresh) Execute([]string) error {
 		return errors.New(i18n.G("a single snap name must be specified when ignoring validation"))
 	}
 
+	if x.DryRun {
+		return x.showRefreshPlan(names, opts)
+	}
 	return x.refreshMany(names, opts)
 }
 
+func (x *cmdRefresh) showRefreshPlan(snaps []string, opts *client.SnapOptions) error {
+	const forInstall = true
+	names, compsBySnap, err := snapInstancesAndComponentsFromNames(snaps, forInstall)
+	if err != nil {
+		return err
+	}
+
+	plan, err := x.client.RefreshPlan(names, compsBySnap, opts)
+	if err != nil {
+		return err
+	}
+	if len(plan.Refreshes) == 0 {
+		fmt.Fprintln(Stderr, i18n.G("All snaps up to date."))
+		return nil
+	}
+
+	w := tabWriter()
+	fmt.Fprintln(w, i18n.G("Name\tVersion\tCurrent\tNew\tChannel\tSize\tNotes"))
+	for _, it := range plan.Refreshes {
+		var notes []string
+		if it.NeedsReboot {
+			notes = append(notes, i18n.G("reboot"))
+		}
+		if len(it.Components) > 0 {
+			notes = append(notes, fmt.Sprintf(i18n.G("components: %s"), strings.Join(it.Components, ",")))
+		}
+		if len(it.Prerequisites) > 0 {
+			notes = append(notes, fmt.Sprintf(i18n.G("prerequisites: %s"), strings.Join(it.Prerequisites, ",")))
+		}
+		if len(it.ValidationSets) > 0 {
+			notes = append(notes, fmt.Sprintf(i18n.G("validation-sets: %s"), strings.Join(it.ValidationSets, ",")))
+		}
+		if holders := plan.Held[it.Name]; len(holders) > 0 {
+			notes = append(notes, fmt.Sprintf(i18n.G("held-by: %s"), strings.Join(holders, ",")))
+		}
+		size := "-"
+		if it.DownloadSize > 0 {
+			size = strutil.SizeToStr(it.DownloadSize)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", it.Name, dashIfEmpty(it.Version),
+			it.CurrentRevision, it.Revision, dashIfEmpty(it.Channel), size, dashIfEmpty(strings.Join(notes, "; ")))
+	}
+	w.Flush()
+
+	if plan.RequiredSpace > 0 {
+		fmt.Fprintf(Stdout, i18n.G("Estimated disk space required: %s\n"), strutil.SizeToStr(int64(plan.RequiredSpace)))
+	}
+	return nil
+}
+
 func (x *cmdRefresh) holdRefreshes() (err error) {
 	var opts client.SnapOptions
 
@@ -1384,6 +1672,7 @@ func (x *cmdDisable) Execute([]string) error {
 
 type cmdRevert struct {
 	waitMixin
+	scheduleMixin
 
 	modeMixin
 	Revision      string `long:"revision"`
@@ -1411,11 +1700,15 @@ func (x *cmdRevert) Execute(args []string) error {
 	if err := x.validateMode(); err != nil {
 		return err
 	}
+	if err := x.setScheduledTime(&x.waitMixin); err != nil {
+		return err
+	}
 
 	name := string(x.Positional.Snap)
 	opts := &client.SnapOptions{
 		Revision:      x.Revision,
 		IgnoreRunning: x.IgnoreRunning,
+		At:            x.At,
 	}
 	x.setModes(opts)
 	changeID, err := x.client.Revert(name, opts)
@@ -1500,7 +1793,7 @@ func (x cmdSwitch) Execute(args []string) error {
 
 func init() {
 	addCommand("remove", shortRemoveHelp, longRemoveHelp, func() flags.Commander { return &cmdRemove{} },
-		waitDescs.also(map[string]string{
+		waitDescs.also(scheduleDescs).also(map[string]string{
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"revision": i18n.G("Remove only the given revision"),
 			// TRANSLATORS: This should not start with a lowercase letter.
@@ -1509,7 +1802,7 @@ func init() {
 			"terminate": i18n.G("Terminate running processes associated with a snap before removal"),
 		}), nil)
 	addCommand("install", shortInstallHelp, longInstallHelp, func() flags.Commander { return &cmdInstall{} },
-		colorDescs.also(waitDescs).also(channelDescs).also(modeDescs).also(map[string]string{
+		colorDescs.also(waitDescs).also(scheduleDescs).also(channelDescs).also(modeDescs).also(map[string]string{
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"revision": i18n.G("Install the given revision of a snap"),
 			// TRANSLATORS: This should not start with a lowercase letter.
@@ -1532,9 +1825,11 @@ func init() {
 			"quota-group": i18n.G("Add the snap to a quota group on install"),
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"prefer": i18n.G("Enable all aliases of the given snap in preference to conflicting aliases of other snaps"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"from-dir": i18n.G("Install all snap files in the given directory, as asserted by the assertion files there"),
 		}), nil)
 	addCommand("refresh", shortRefreshHelp, longRefreshHelp, func() flags.Commander { return &cmdRefresh{} },
-		colorDescs.also(waitDescs).also(channelDescs).also(modeDescs).also(timeDescs).also(map[string]string{
+		colorDescs.also(waitDescs).also(scheduleDescs).also(channelDescs).also(modeDescs).also(timeDescs).also(map[string]string{
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"amend": i18n.G("Allow refresh attempt on snap unknown to the store"),
 			// TRANSLATORS: This should not start with a lowercase letter.
@@ -1557,11 +1852,15 @@ func init() {
 			"hold": i18n.G("Hold refreshes for a specified duration (or forever, if no value is specified)"),
 			// TRANSLATORS: This should not start with a lowercase letter.
 			"unhold": i18n.G("Remove refresh hold"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"dry-run": i18n.G("Show what the refresh would do without refreshing anything"),
+			// TRANSLATORS: This should not start with a lowercase letter.
+			"from-dir": i18n.G("Refresh installed snaps from the snap files in the given directory, as asserted by the assertion files there"),
 		}), nil)
 	addCommand("try", shortTryHelp, longTryHelp, func() flags.Commander { return &cmdTry{} }, waitDescs.also(modeDescs), nil)
 	addCommand("enable", shortEnableHelp, longEnableHelp, func() flags.Commander { return &cmdEnable{} }, waitDescs, nil)
 	addCommand("disable", shortDisableHelp, longDisableHelp, func() flags.Commander { return &cmdDisable{} }, waitDescs, nil)
-	addCommand("revert", shortRevertHelp, longRevertHelp, func() flags.Commander { return &cmdRevert{} }, waitDescs.also(modeDescs).also(map[string]string{
+	addCommand("revert", shortRevertHelp, longRevertHelp, func() flags.Commander { return &cmdRevert{} }, waitDescs.also(scheduleDescs).also(modeDescs).also(map[string]string{
 		// TRANSLATORS: This should not start with a lowercase letter.
 		"revision": i18n.G("Revert to the given revision"),
 		// TRANSLATORS: This should not start with a lowercase letter.
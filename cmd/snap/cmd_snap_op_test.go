@@ -1668,6 +1668,192 @@ func (s *SnapOpSuite) TestInstallPathMany(c *check.C) {
 	c.Check(n, check.Equals, total)
 }
 
+func (s *SnapOpSuite) mockSnapsDir(c *check.C) string {
+	dir := c.MkDir()
+	for name, content := range map[string]string{
+		"foo_1.snap":  "foo-data",
+		"bar_2.snap":  "bar-data",
+		"all.assert":  "assert-data",
+		"README":      "readme",
+		"other.snap~": "backup",
+	} {
+		c.Assert(os.WriteFile(filepath.Join(dir, name), []byte(content), 0644), check.IsNil)
+	}
+	c.Assert(os.Mkdir(filepath.Join(dir, "sub.snap"), 0755), check.IsNil)
+	return dir
+}
+
+func checkFormFiles(c *check.C, form *multipart.Form, field string, expected map[string]string) {
+	files := make(map[string]string)
+	for _, h := range form.File[field] {
+		body, err := h.Open()
+		c.Assert(err, check.IsNil)
+		content, err := io.ReadAll(body)
+		body.Close()
+		c.Assert(err, check.IsNil)
+		files[h.Filename] = string(content)
+	}
+	c.Check(files, check.DeepEquals, expected)
+}
+
+func (s *SnapOpSuite) TestInstallFromDir(c *check.C) {
+	dir := s.mockSnapsDir(c)
+
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.URL.Path, check.Equals, "/v2/snaps")
+			c.Check(r.Method, check.Equals, "POST")
+
+			form := testForm(r, c)
+			defer form.RemoveAll()
+			c.Check(form.Value["action"], check.DeepEquals, []string{"install"})
+			c.Check(form.Value["transaction"], check.DeepEquals, []string{"all-snaps"})
+			c.Check(form.Value["dangerous"], check.IsNil)
+			checkFormFiles(c, form, "snap", map[string]string{
+				"foo_1.snap": "foo-data",
+				"bar_2.snap": "bar-data",
+			})
+			checkFormFiles(c, form, "assertion", map[string]string{
+				"all.assert": "assert-data",
+			})
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type":"async", "change": "42", "status-code": 202}`)
+		case 1:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/changes/42")
+			fmt.Fprintln(w, `{"type": "sync", "result": {"ready": true, "status": "Done", "data": {"snap-names": ["bar","foo"]}}}`)
+		case 2:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/snaps")
+			fmt.Fprintf(w, `{"type": "sync", "result": [{"name": "bar", "version": "2.0", "developer": "baz", "publisher": {"id": "baz-id", "username": "baz", "display-name": "Baz"}},{"name": "foo", "version": "1.0", "developer": "bar", "publisher": {"id": "bar-id", "username": "bar", "display-name": "Bar"}}]}\n`)
+		default:
+			c.Fatalf("expected to get 3 requests, now on %d", n+1)
+		}
+		n++
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"install", "--from-dir", dir})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Matches, `(?sm).*bar 2.0 from Baz installed`)
+	c.Check(s.Stdout(), check.Matches, `(?sm).*foo 1.0 from Bar installed`)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(n, check.Equals, 3)
+}
+
+func (s *SnapOpSuite) TestInstallFromDirErrors(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Fatalf("unexpected request to %s", r.URL.Path)
+	})
+
+	for _, args := range [][]string{
+		{"install", "--from-dir", "/dir", "foo"},
+		{"install", "--from-dir", "/dir", "--beta"},
+		{"install", "--from-dir", "/dir", "--devmode"},
+		{"install", "--from-dir", "/dir", "--dangerous"},
+		{"install", "--from-dir", "/dir", "--revision", "1"},
+	} {
+		_, err := snap.Parser(snap.Client()).ParseArgs(args)
+		c.Check(err, check.ErrorMatches, "cannot use --from-dir with snap names, channel, mode, revision or scheduling options", check.Commentf("%q", args))
+	}
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"install", "--from-dir", c.MkDir()})
+	c.Check(err, check.ErrorMatches, `cannot find any snaps in ".*"`)
+}
+
+func (s *SnapOpSuite) TestRefreshFromDir(c *check.C) {
+	dir := s.mockSnapsDir(c)
+	restore := snap.MockSnapNameFromFile(func(path string) (string, error) {
+		return strings.SplitN(filepath.Base(path), "_", 2)[0], nil
+	})
+	defer restore()
+
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/snaps")
+			fmt.Fprintln(w, `{"type": "sync", "result": [{"name": "foo", "version": "1.0"}, {"name": "baz", "version": "3.0"}]}`)
+		case 1:
+			c.Check(r.URL.Path, check.Equals, "/v2/snaps")
+			c.Check(r.Method, check.Equals, "POST")
+
+			form := testForm(r, c)
+			defer form.RemoveAll()
+			c.Check(form.Value["action"], check.DeepEquals, []string{"install"})
+			c.Check(form.Value["transaction"], check.DeepEquals, []string{"all-snaps"})
+			checkFormFiles(c, form, "snap", map[string]string{
+				"foo_1.snap": "foo-data",
+			})
+			checkFormFiles(c, form, "assertion", map[string]string{
+				"all.assert": "assert-data",
+			})
+			w.WriteHeader(202)
+			fmt.Fprintln(w, `{"type":"async", "change": "42", "status-code": 202}`)
+		case 2:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/changes/42")
+			fmt.Fprintln(w, `{"type": "sync", "result": {"ready": true, "status": "Done", "data": {"snap-names": ["foo"]}}}`)
+		case 3:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/snaps")
+			fmt.Fprintln(w, `{"type": "sync", "result": [{"name": "foo", "version": "1.1", "developer": "bar", "publisher": {"id": "bar-id", "username": "bar", "display-name": "Bar"}}]}`)
+		default:
+			c.Fatalf("expected to get 4 requests, now on %d", n+1)
+		}
+		n++
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--from-dir", dir})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Matches, `(?sm).*foo 1.1 from Bar refreshed`)
+	c.Check(s.Stderr(), check.Equals, fmt.Sprintf("Skipping %s: snap \"bar\" is not installed\n", filepath.Join(dir, "bar_2.snap")))
+	c.Check(n, check.Equals, 4)
+}
+
+func (s *SnapOpSuite) TestRefreshFromDirNothingInstalled(c *check.C) {
+	dir := s.mockSnapsDir(c)
+	restore := snap.MockSnapNameFromFile(func(path string) (string, error) {
+		return strings.SplitN(filepath.Base(path), "_", 2)[0], nil
+	})
+	defer restore()
+
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, check.Equals, "GET")
+		c.Check(r.URL.Path, check.Equals, "/v2/snaps")
+		fmt.Fprintln(w, `{"type": "sync", "result": []}`)
+		n++
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--from-dir", dir})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Matches, fmt.Sprintf(`(?s).*No snaps in %q are installed.\n`, dir))
+	c.Check(n, check.Equals, 1)
+}
+
+func (s *SnapOpSuite) TestRefreshFromDirErrors(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Fatalf("unexpected request to %s", r.URL.Path)
+	})
+
+	for _, args := range [][]string{
+		{"refresh", "--from-dir", "/dir", "foo"},
+		{"refresh", "--from-dir", "/dir", "--edge"},
+		{"refresh", "--from-dir", "/dir", "--list"},
+		{"refresh", "--from-dir", "/dir", "--dry-run"},
+		{"refresh", "--from-dir", "/dir", "--hold"},
+	} {
+		_, err := snap.Parser(snap.Client()).ParseArgs(args)
+		c.Check(err, check.ErrorMatches, "cannot use --from-dir with snap names or with other options", check.Commentf("%q", args))
+	}
+}
+
 func formFiles(form *multipart.Form, c *check.C) (names, filenames []string, contents [][]byte) {
 	for name, fheaders := range form.File {
 		for _, h := range fheaders {
@@ -1899,6 +2085,42 @@ next: 2017-04-26T00:58:00+02:00
 	c.Check(n, check.Equals, 1)
 }
 
+func (s *SnapSuite) TestRefreshTimerSnaps(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch n {
+		case 0:
+			c.Check(r.Method, check.Equals, "GET")
+			c.Check(r.URL.Path, check.Equals, "/v2/system-info")
+			fmt.Fprintln(w, `{"type": "sync", "status-code": 200, "result": {"refresh": {"timer": "0:00-24:00/4", "last": "2017-04-25T17:35:00+02:00", "next": "2017-04-26T00:58:00+02:00", "snaps": {
+"kiosk": {"timer": "2:00-4:00", "last": "2017-04-25T02:10:00+02:00", "next": "2017-04-26T03:12:00+02:00"},
+"agent": {"timer": "0:00~24:00/96"}}}}}`)
+		default:
+			c.Fatalf("expected to get 1 requests, now on %d", n+1)
+		}
+
+		n++
+	})
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--time", "--abs-time"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(s.Stdout(), check.Equals, `timer: 0:00-24:00/4
+last: 2017-04-25T17:35:00+02:00
+next: 2017-04-26T00:58:00+02:00
+snaps:
+  agent:
+    timer: 0:00~24:00/96
+    last: n/a
+    next: n/a
+  kiosk:
+    timer: 2:00-4:00
+    last: 2017-04-25T02:10:00+02:00
+    next: 2017-04-26T03:12:00+02:00
+`)
+	c.Check(s.Stderr(), check.Equals, "")
+	c.Check(n, check.Equals, 1)
+}
+
 func (s *SnapSuite) TestRefreshTimeShowsHolds(c *check.C) {
 	type testcase struct {
 		in  string
@@ -3057,6 +3279,32 @@ func (s *SnapOpSuite) TestInstallInsufficientDiskSpace(c *check.C) {
 	c.Check(s.Stderr(), check.Equals, "")
 }
 
+func (s *SnapOpSuite) TestRefreshInsufficientDiskSpaceEstimate(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{
+			"type": "error",
+			"result": {
+				"message": "disk space error",
+				"kind": "insufficient-disk-space",
+				"value": {
+					"snap-names": ["foo", "bar"],
+					"change-kind": "refresh",
+					"required-space": 3000000,
+					"space-estimates": [
+						{"name": "foo", "snap": 1000000, "total": 1000000},
+						{"name": "bar", "snap": 1000000, "snapshot": 1000000, "total": 2000000}
+					]
+				},
+				"status-code": 507
+				}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "foo"})
+	c.Check(err, check.ErrorMatches, `(?s)cannot refresh "foo", "bar" due to low disk space \(3MB needed: foo 1MB,\s+bar 2MB\)`)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
 func (s *SnapOpSuite) TestRefreshInsufficientDiskSpace(c *check.C) {
 	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintln(w, `{
@@ -3379,6 +3627,119 @@ func (s *SnapOpSuite) TestNoWait(c *check.C) {
 	}
 }
 
+func (s *SnapOpSuite) TestScheduled(c *check.C) {
+	s.srv.checker = func(r *http.Request) {
+		c.Check(DecodedRequestBody(c, r)["at"], check.Equals, "2026-01-02T03:04:05Z")
+	}
+
+	cmds := [][]string{
+		{"remove", "--at=2026-01-02T03:04:05Z", "foo"},
+		{"remove", "--at=2026-01-02T03:04:05Z", "foo", "bar"},
+		{"install", "--at=2026-01-02T03:04:05Z", "foo"},
+		{"install", "--at=2026-01-02T03:04:05Z", "foo", "bar"},
+		{"revert", "--at=2026-01-02T03:04:05Z", "foo"},
+		{"refresh", "--at=2026-01-02T03:04:05Z", "foo"},
+		{"refresh", "--at=2026-01-02T03:04:05Z", "foo", "bar"},
+		{"refresh", "--at=2026-01-02T03:04:05Z"},
+	}
+
+	s.RedirectClientToTestServer(s.srv.handle)
+	for _, cmd := range cmds {
+		rest, err := snap.Parser(snap.Client()).ParseArgs(cmd)
+		c.Assert(err, check.IsNil, check.Commentf("%v", cmd))
+		c.Assert(rest, check.DeepEquals, []string{})
+		c.Check(s.Stdout(), check.Equals, "Change 42 scheduled to start at 2026-01-02T03:04:05Z\n")
+		c.Check(s.Stderr(), check.Equals, "")
+		c.Check(s.srv.n, check.Equals, 2)
+		// reset
+		s.srv.n = 0
+		s.stdout.Reset()
+	}
+}
+
+func (s *SnapOpSuite) TestScheduledErrors(c *check.C) {
+	for _, tc := range []struct {
+		cmd []string
+		err string
+	}{
+		{[]string{"remove", "--at=tomorrow", "foo"}, `cannot parse --at time "tomorrow": expected RFC3339 format, .*`},
+		{[]string{"install", "--at=2026-01-02T03:04:05Z", "./foo.snap"}, `cannot schedule the installation of local snaps`},
+		{[]string{"refresh", "--at=2026-01-02T03:04:05Z", "--hold", "foo"}, `cannot use --hold with other flags`},
+	} {
+		_, err := snap.Parser(snap.Client()).ParseArgs(tc.cmd)
+		c.Check(err, check.ErrorMatches, tc.err, check.Commentf("%v", tc.cmd))
+	}
+}
+
+func (s *SnapOpSuite) TestRefreshDryRun(c *check.C) {
+	n := 0
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, check.Equals, "POST")
+		c.Check(r.URL.Path, check.Equals, "/v2/snaps")
+		c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+			"action":      "refresh",
+			"snaps":       []interface{}{"foo", "bar"},
+			"transaction": "per-snap",
+			"dry-run":     true,
+		})
+		fmt.Fprintln(w, `{"type": "sync", "result": {
+  "refreshes": [
+    {"name": "bar", "type": "app", "current-revision": "1", "revision": "2", "channel": "latest/stable", "version": "1.1"},
+    {"name": "foo", "type": "kernel", "current-revision": "5", "revision": "7", "channel": "24/stable", "version": "2.0",
+     "download-size": 1048576, "components": ["wifi"], "prerequisites": ["core24"], "needs-reboot": true,
+     "validation-sets": ["acme/base"], "required-space": 3145728}
+  ],
+  "held": {"bar": ["gating-snap"]},
+  "required-space": 3145728
+}}`)
+		n++
+	})
+
+	rest, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--dry-run", "foo", "bar"})
+	c.Assert(err, check.IsNil)
+	c.Assert(rest, check.DeepEquals, []string{})
+	c.Check(n, check.Equals, 1)
+	c.Check(s.Stdout(), check.Equals, `
+Name  Version  Current  New  Channel        Size  Notes
+bar   1.1      1        2    latest/stable  -     held-by: gating-snap
+foo   2.0      5        7    24/stable      1MB   reboot; components: wifi; prerequisites: core24; validation-sets: acme/base
+Estimated disk space required: 3MB
+`[1:])
+	c.Check(s.Stderr(), check.Equals, "")
+}
+
+func (s *SnapOpSuite) TestRefreshDryRunOne(c *check.C) {
+	s.RedirectClientToTestServer(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, check.Equals, "/v2/snaps/foo")
+		c.Check(DecodedRequestBody(c, r), check.DeepEquals, map[string]interface{}{
+			"action":      "refresh",
+			"channel":     "edge",
+			"transaction": "per-snap",
+			"dry-run":     true,
+		})
+		fmt.Fprintln(w, `{"type": "sync", "result": {"refreshes": []}}`)
+	})
+
+	_, err := snap.Parser(snap.Client()).ParseArgs([]string{"refresh", "--dry-run", "--edge", "foo"})
+	c.Assert(err, check.IsNil)
+	c.Check(s.Stdout(), check.Equals, "")
+	c.Check(s.Stderr(), check.Equals, "All snaps up to date.\n")
+}
+
+func (s *SnapOpSuite) TestRefreshDryRunErrors(c *check.C) {
+	for _, tc := range []struct {
+		cmd []string
+		err string
+	}{
+		{[]string{"refresh", "--dry-run", "--hold", "foo"}, `cannot use --hold with other flags`},
+		{[]string{"refresh", "--dry-run", "--unhold", "foo"}, `cannot use --unhold with other flags`},
+		{[]string{"refresh", "--dry-run", "--at=2026-01-02T03:04:05Z", "foo"}, `cannot use --dry-run with --at`},
+	} {
+		_, err := snap.Parser(snap.Client()).ParseArgs(tc.cmd)
+		c.Check(err, check.ErrorMatches, tc.err, check.Commentf("%v", tc.cmd))
+	}
+}
+
 func (s *SnapOpSuite) TestNoWaitImmediateError(c *check.C) {
 
 	cmds := [][]string{
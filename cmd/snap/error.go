@@ -258,9 +258,9 @@ If you understand and want to proceed repeat the command including --classic.
 			case "remove":
 				msg = fmt.Sprintf(i18n.G("cannot remove %s due to low disk space for automatic snapshot, use --purge to avoid creating a snapshot"), names)
 			case "install":
-				msg = fmt.Sprintf(i18n.G("cannot install %s due to low disk space"), names)
+				msg = fmt.Sprintf(i18n.G("cannot install %s due to low disk space"), names) + spaceEstimateSummary(values)
 			case "refresh":
-				msg = fmt.Sprintf(i18n.G("cannot refresh %s due to low disk space"), names)
+				msg = fmt.Sprintf(i18n.G("cannot refresh %s due to low disk space"), names) + spaceEstimateSummary(values)
 			default:
 				msg = err.Error()
 			}
@@ -446,3 +446,28 @@ func archsForChannels(cs []*channel.Channel) []string {
 	}
 	return archs
 }
+
+// spaceEstimateSummary describes the disk space that the operation which
+// failed with an insufficient-disk-space error was estimated to need, if
+// the error carries the estimate.
+func spaceEstimateSummary(values map[string]interface{}) string {
+	required, _ := values["required-space"].(float64)
+	if required <= 0 {
+		return ""
+	}
+	estimates, _ := values["space-estimates"].([]interface{})
+	perSnap := make([]string, 0, len(estimates))
+	for _, v := range estimates {
+		est, _ := v.(map[string]interface{})
+		name, _ := est["name"].(string)
+		total, _ := est["total"].(float64)
+		if name == "" {
+			continue
+		}
+		perSnap = append(perSnap, fmt.Sprintf("%s %s", name, strutil.SizeToStr(int64(total))))
+	}
+	if len(perSnap) == 0 {
+		return fmt.Sprintf(i18n.G(" (%s needed)"), strutil.SizeToStr(int64(required)))
+	}
+	return fmt.Sprintf(i18n.G(" (%s needed: %s)"), strutil.SizeToStr(int64(required)), strings.Join(perSnap, ", "))
+}
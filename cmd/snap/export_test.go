@@ -473,3 +473,29 @@ func MockSeedWriterReadManifest(f func(manifestFile string) (*seedwriter.Manifes
 	seedwriterReadManifest = f
 	return restore
 }
+
+func MockUserLookup(f func(name string) (*user.User, error)) (restore func()) {
+	old := userLookup
+	userLookup = f
+	return func() {
+		userLookup = old
+	}
+}
+
+func MockSnapNameFromFile(f func(path string) (string, error)) (restore func()) {
+	restore = testutil.Backup(&snapNameFromFile)
+	snapNameFromFile = f
+	return restore
+}
+
+func MockSnapFileDigest(f func(path string) (string, uint64, error)) (restore func()) {
+	restore = testutil.Backup(&snapFileDigest)
+	snapFileDigest = f
+	return restore
+}
+
+func MockSnapYamlFromFile(f func(path string) ([]byte, error)) (restore func()) {
+	restore = testutil.Backup(&snapYamlFromFile)
+	snapYamlFromFile = f
+	return restore
+}
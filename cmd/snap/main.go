@@ -91,6 +91,9 @@ type cmdInfo struct {
 	argDescs       []argDesc
 	alias          string
 	extra          func(*flags.Command)
+	// subcommands are registered under the command like top-level
+	// commands are, for commands such as "snap prompts list"
+	subcommands []*cmdInfo
 }
 
 // commands holds information about all non-debug commands.
@@ -303,6 +306,9 @@ func registerCommands(cli *client.Client, parser *flags.Parser, baseCmd *flags.C
 		if c.extra != nil {
 			c.extra(cmd)
 		}
+		if len(c.subcommands) > 0 {
+			registerCommands(cli, parser, cmd, c.subcommands, checkUnique)
+		}
 	}
 }
 
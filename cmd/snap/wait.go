@@ -43,6 +43,9 @@ type waitMixin struct {
 
 	// Wait also for tasks in the "wait" state.
 	waitForTasksInWaitStatus bool
+
+	// Do not wait for changes scheduled to start at this time.
+	scheduledAt string
 }
 
 var waitDescs = mixinDescs{
@@ -58,6 +61,17 @@ func (wmx waitMixin) wait(id string) (*client.Change, error) {
 		return nil, noWait
 	}
 	cli := wmx.client
+	if wmx.scheduledAt != "" {
+		chg, err := cli.Change(id)
+		if err != nil {
+			return nil, err
+		}
+		// changes with nothing to do are ready right away
+		if !chg.Ready {
+			fmt.Fprintf(Stdout, i18n.G("Change %s scheduled to start at %s\n"), id, wmx.scheduledAt)
+			return nil, noWait
+		}
+	}
 	// Intercept sigint
 	c := make(chan os.Signal, 2)
 	signal.Notify(c, os.Interrupt)
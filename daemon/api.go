@@ -46,12 +46,15 @@ var api = []*Command{
 	snapsCmd,
 	snapCmd,
 	snapFileCmd,
+	snapExportCmd,
 	snapDownloadCmd,
 	snapConfCmd,
+	snapHistoryCmd,
 	interfacesCmd,
 	assertsCmd,
 	assertsFindManyCmd,
 	stateChangeCmd,
+	stateChangeGraphCmd,
 	stateChangesCmd,
 	createUserCmd,
 	buyCmd,
@@ -65,6 +68,8 @@ var api = []*Command{
 	logsCmd,
 	warningsCmd,
 	debugPprofCmd,
+	metricsCmd,
+	auditCmd,
 	debugCmd,
 	snapshotCmd,
 	snapshotExportCmd,
@@ -82,6 +87,7 @@ var api = []*Command{
 	systemRecoveryKeysCmd,
 	quotaGroupsCmd,
 	quotaGroupInfoCmd,
+	applyCmd,
 	registryCmd,
 	noticesCmd,
 	noticeCmd,
@@ -89,6 +95,7 @@ var api = []*Command{
 	requestsPromptCmd,
 	requestsRulesCmd,
 	requestsRuleCmd,
+	requestsPolicyCmd,
 }
 
 const (
@@ -150,6 +157,7 @@ var (
 	snapstateInstallComponentPath           = snapstate.InstallComponentPath
 	snapstateInstallComponents              = snapstate.InstallComponents
 	snapstateRefreshCandidates              = snapstate.RefreshCandidates
+	snapstateRefreshPlanFromTaskSets        = snapstate.RefreshPlanFromTaskSets
 	snapstateTryPath                        = snapstate.TryPath
 	snapstateStoreUpdateGoal                = snapstate.StoreUpdateGoal
 	snapstateUpdateWithGoal                 = snapstate.UpdateWithGoal
@@ -165,6 +173,7 @@ var (
 	snapstateLongestGatingHold              = snapstate.LongestGatingHold
 	snapstateSystemHold                     = snapstate.SystemHold
 	snapstateRemoveComponents               = snapstate.RemoveComponents
+	snapstateRevisionRemovalTimes           = snapstate.RevisionRemovalTimes
 
 	configstateConfigureInstalled = configstate.ConfigureInstalled
 
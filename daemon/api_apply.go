@@ -0,0 +1,98 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/manifeststate"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+var (
+	applyCmd = &Command{
+		Path: "/v2/apply",
+		POST: postApply,
+		// quota groups can be set up by a manifest
+		WriteAccess: rootAccess{},
+	}
+)
+
+var (
+	manifeststatePlan  = manifeststate.Plan
+	manifeststateApply = manifeststate.Apply
+)
+
+type applyRequest struct {
+	// Manifest is the manifest in YAML format.
+	Manifest string `json:"manifest"`
+	// Diff asks only for the operations that applying the manifest would
+	// carry out.
+	Diff bool `json:"diff"`
+}
+
+func postApply(c *Command, r *http.Request, user *auth.UserState) Response {
+	var req applyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return BadRequest("cannot decode request body into apply request: %v", err)
+	}
+	m, err := manifeststate.ParseManifest([]byte(req.Manifest))
+	if err != nil {
+		return BadRequest("%v", err)
+	}
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	if req.Diff {
+		ops, err := manifeststatePlan(st, m)
+		if err != nil {
+			return errToResponse(err, nil, BadRequest, "cannot compute manifest operations: %v")
+		}
+		if ops == nil {
+			ops = []*manifeststate.Operation{}
+		}
+		return SyncResponse(map[string]interface{}{"operations": ops})
+	}
+
+	var userID int
+	if user != nil {
+		userID = user.ID
+	}
+	tss, ops, err := manifeststateApply(r.Context(), st, m, userID)
+	if err != nil {
+		return errToResponse(err, m.SnapNames(), BadRequest, "cannot apply manifest: %v")
+	}
+	if ops == nil {
+		ops = []*manifeststate.Operation{}
+	}
+
+	chg := newChange(st, "apply-manifest", i18n.G("Apply device manifest"), tss, m.SnapNames())
+	if len(tss) == 0 {
+		chg.SetStatus(state.DoneStatus)
+	}
+	ensureStateSoon(st)
+
+	return AsyncResponse(map[string]interface{}{"operations": ops}, chg.ID())
+}
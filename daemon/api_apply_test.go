@@ -0,0 +1,147 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/overlord/manifeststate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+var _ = check.Suite(&applySuite{})
+
+type applySuite struct {
+	apiBaseSuite
+}
+
+func (s *applySuite) SetUpTest(c *check.C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectRootAccess()
+
+	_, restore := daemon.MockEnsureStateSoon(func(st *state.State) {})
+	s.AddCleanup(restore)
+}
+
+func (s *applySuite) applyReq(c *check.C, manifest string, diff bool) *http.Request {
+	body, err := json.Marshal(map[string]interface{}{"manifest": manifest, "diff": diff})
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest("POST", "/v2/apply", bytes.NewReader(body))
+	c.Assert(err, check.IsNil)
+	return req
+}
+
+var applyOps = []*manifeststate.Operation{
+	{Kind: manifeststate.OpInstall, Snap: "foo", Summary: `Install snap "foo"`},
+	{Kind: manifeststate.OpConfigure, Snap: "foo", Summary: `Configure "key" of snap "foo"`},
+}
+
+func (s *applySuite) TestApplyDiff(c *check.C) {
+	s.daemon(c)
+
+	defer daemon.MockManifeststatePlan(func(st *state.State, m *manifeststate.Manifest) ([]*manifeststate.Operation, error) {
+		c.Check(m.SnapNames(), check.DeepEquals, []string{"foo"})
+		return applyOps, nil
+	})()
+	defer daemon.MockManifeststateApply(func(ctx context.Context, st *state.State, m *manifeststate.Manifest, userID int) ([]*state.TaskSet, []*manifeststate.Operation, error) {
+		c.Fatal("unexpected apply")
+		return nil, nil, nil
+	})()
+
+	rsp := s.syncReq(c, s.applyReq(c, "snaps: {foo: {config: {key: value}}}", true), nil)
+	c.Check(rsp.Result, check.DeepEquals, map[string]interface{}{"operations": applyOps})
+}
+
+func (s *applySuite) TestApplyDiffNothingToDo(c *check.C) {
+	s.daemon(c)
+
+	defer daemon.MockManifeststatePlan(func(st *state.State, m *manifeststate.Manifest) ([]*manifeststate.Operation, error) {
+		return nil, nil
+	})()
+
+	rsp := s.syncReq(c, s.applyReq(c, "snaps: {foo: {}}", true), nil)
+	c.Check(rsp.Result, check.DeepEquals, map[string]interface{}{"operations": []*manifeststate.Operation{}})
+}
+
+func (s *applySuite) TestApply(c *check.C) {
+	d := s.daemon(c)
+
+	defer daemon.MockManifeststateApply(func(ctx context.Context, st *state.State, m *manifeststate.Manifest, userID int) ([]*state.TaskSet, []*manifeststate.Operation, error) {
+		c.Check(m.SnapNames(), check.DeepEquals, []string{"foo"})
+		t := st.NewTask("fake-install", "Install foo")
+		return []*state.TaskSet{state.NewTaskSet(t)}, applyOps, nil
+	})()
+
+	rsp := s.asyncReq(c, s.applyReq(c, "snaps: {foo: {config: {key: value}}}", false), nil)
+	c.Check(rsp.Result, check.DeepEquals, map[string]interface{}{"operations": applyOps})
+
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	c.Assert(chg, check.NotNil)
+	c.Check(chg.Kind(), check.Equals, "apply-manifest")
+	c.Check(chg.Tasks(), check.HasLen, 1)
+	c.Check(chg.Status(), check.Equals, state.DoStatus)
+	var names []string
+	c.Assert(chg.Get("snap-names", &names), check.IsNil)
+	c.Check(names, check.DeepEquals, []string{"foo"})
+}
+
+func (s *applySuite) TestApplyNothingToDo(c *check.C) {
+	d := s.daemon(c)
+
+	defer daemon.MockManifeststateApply(func(ctx context.Context, st *state.State, m *manifeststate.Manifest, userID int) ([]*state.TaskSet, []*manifeststate.Operation, error) {
+		return nil, nil, nil
+	})()
+
+	rsp := s.asyncReq(c, s.applyReq(c, "snaps: {foo: {}}", false), nil)
+	c.Check(rsp.Result, check.DeepEquals, map[string]interface{}{"operations": []*manifeststate.Operation{}})
+
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	c.Check(st.Change(rsp.Change).Status(), check.Equals, state.DoneStatus)
+}
+
+func (s *applySuite) TestApplyErrors(c *check.C) {
+	s.daemon(c)
+
+	defer daemon.MockManifeststateApply(func(ctx context.Context, st *state.State, m *manifeststate.Manifest, userID int) ([]*state.TaskSet, []*manifeststate.Operation, error) {
+		return nil, nil, &snapstate.ChangeConflictError{Snap: "foo", ChangeKind: "refresh-snap"}
+	})()
+
+	rspe := s.errorReq(c, s.applyReq(c, "snaps: [foo]", false), nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Matches, `(?s)cannot parse manifest: .*`)
+
+	rspe = s.errorReq(c, s.applyReq(c, "snaps: {foo: {}}", false), nil)
+	c.Check(rspe.Status, check.Equals, 409)
+	c.Check(rspe.Kind, check.Equals, client.ErrorKindSnapChangeConflict)
+}
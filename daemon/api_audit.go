@@ -0,0 +1,70 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/snapcore/snapd/overlord/auth"
+)
+
+var auditCmd = &Command{
+	Path:       "/v2/audit",
+	GET:        getAudit,
+	ReadAccess: rootAccess{},
+}
+
+func getAudit(c *Command, r *http.Request, user *auth.UserState) Response {
+	query := r.URL.Query()
+
+	since, err := parseOptionalTime(query.Get("since"))
+	if err != nil {
+		return BadRequest(`invalid "since" timestamp: %v`, err)
+	}
+	until, err := parseOptionalTime(query.Get("until"))
+	if err != nil {
+		return BadRequest(`invalid "until" timestamp: %v`, err)
+	}
+	filter := &auditFilter{
+		Since: since,
+		Until: until,
+	}
+	if s := query.Get("uid"); s != "" {
+		uid, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return BadRequest(`invalid "uid" parameter: %q is not a valid uid`, s)
+		}
+		uid32 := uint32(uid)
+		filter.UID = &uid32
+	}
+
+	if c.d.auditLog == nil {
+		return SyncResponse([]*auditEntry{})
+	}
+	entries, err := c.d.auditLog.Entries(filter)
+	if err != nil {
+		return InternalError("cannot read audit log: %v", err)
+	}
+	if entries == nil {
+		entries = []*auditEntry{}
+	}
+	return SyncResponse(entries)
+}
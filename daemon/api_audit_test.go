@@ -0,0 +1,199 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/testutil"
+)
+
+var _ = check.Suite(&auditSuite{})
+
+type auditSuite struct {
+	apiBaseSuite
+}
+
+func (s *auditSuite) SetUpTest(c *check.C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.AddCleanup(daemon.MockCgroupSnapNameFromPid(func(pid int) (string, error) {
+		if pid == 42 {
+			return "some-snap", nil
+		}
+		return "", fmt.Errorf("not a snap")
+	}))
+	_, restore := daemon.MockEnsureStateSoon(func(st *state.State) {})
+	s.AddCleanup(restore)
+}
+
+func (s *auditSuite) serve(c *check.C, method, path string, body []byte, remoteAddr string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, path, bytes.NewReader(body))
+	c.Assert(err, check.IsNil)
+	req.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	s.serveHTTP(c, rec, req)
+	return rec
+}
+
+func (s *auditSuite) audit(c *check.C, query string) []map[string]interface{} {
+	rec := s.serve(c, "GET", "/v2/audit"+query, nil, fmt.Sprintf("pid=100;uid=0;socket=%s;", dirs.SnapdSocket))
+	c.Assert(rec.Code, check.Equals, 200, check.Commentf("%s", rec.Body))
+	var rsp struct {
+		Result []map[string]interface{} `json:"result"`
+	}
+	c.Assert(json.Unmarshal(rec.Body.Bytes(), &rsp), check.IsNil)
+	return rsp.Result
+}
+
+func (s *auditSuite) TestAuditMutatingRequests(c *check.C) {
+	d := s.daemon(c)
+
+	st := d.Overlord().State()
+	st.Lock()
+	chg := st.NewChange("install", "...")
+	chg.AddTask(st.NewTask("download", "..."))
+	st.Unlock()
+
+	rootAddr := fmt.Sprintf("pid=100;uid=0;socket=%s;", dirs.SnapdSocket)
+	snapAddr := fmt.Sprintf("pid=42;uid=1000;socket=%s;", dirs.SnapdSocket)
+
+	// reads are not recorded
+	rec := s.serve(c, "GET", "/v2/changes/"+chg.ID(), nil, snapAddr)
+	c.Assert(rec.Code, check.Equals, 200)
+	c.Check(s.audit(c, ""), check.HasLen, 0)
+
+	t0 := time.Now()
+	rec = s.serve(c, "POST", "/v2/changes/"+chg.ID(), []byte(`{"action": "pause"}`), rootAddr)
+	c.Assert(rec.Code, check.Equals, 200)
+	// forbidden requests are recorded as well
+	rec = s.serve(c, "POST", "/v2/debug", []byte(`{"action": "ensure-state-soon"}`), snapAddr)
+	c.Assert(rec.Code, check.Equals, 403)
+
+	entries := s.audit(c, "")
+	c.Assert(entries, check.HasLen, 2)
+	c.Check(entries[0]["uid"], check.Equals, 0.0)
+	c.Check(entries[0]["pid"], check.Equals, 100.0)
+	c.Check(entries[0]["snap"], check.IsNil)
+	c.Check(entries[0]["method"], check.Equals, "POST")
+	c.Check(entries[0]["endpoint"], check.Equals, "/v2/changes/"+chg.ID())
+	c.Check(entries[0]["status-code"], check.Equals, 200.0)
+	c.Check(entries[0]["error"], check.IsNil)
+	c.Check(entries[1]["uid"], check.Equals, 1000.0)
+	c.Check(entries[1]["pid"], check.Equals, 42.0)
+	c.Check(entries[1]["snap"], check.Equals, "some-snap")
+	c.Check(entries[1]["endpoint"], check.Equals, "/v2/debug")
+	c.Check(entries[1]["status-code"], check.Equals, 403.0)
+	c.Check(entries[1]["error"], check.Equals, "access denied")
+
+	entryTime, err := time.Parse(time.RFC3339Nano, entries[0]["time"].(string))
+	c.Assert(err, check.IsNil)
+	c.Check(entryTime.Before(t0), check.Equals, false)
+
+	// filtering
+	c.Check(s.audit(c, "?uid=1000"), check.HasLen, 1)
+	c.Check(s.audit(c, "?uid=1001"), check.HasLen, 0)
+	c.Check(s.audit(c, "?since="+t0.Add(-time.Hour).Format(time.RFC3339)), check.HasLen, 2)
+	c.Check(s.audit(c, "?since="+t0.Add(time.Hour).Format(time.RFC3339)), check.HasLen, 0)
+	c.Check(s.audit(c, "?until="+t0.Add(-time.Hour).Format(time.RFC3339)), check.HasLen, 0)
+}
+
+func (s *auditSuite) TestAuditChangeAndUser(c *check.C) {
+	d := s.daemon(c)
+
+	req, err := http.NewRequest("POST", "/v2/snaps/foo", nil)
+	c.Assert(err, check.IsNil)
+	user := &auth.UserState{ID: 1, Username: "someone"}
+	ucred := &daemon.Ucrednet{Uid: 1000, Pid: 100, Socket: dirs.SnapdSocket}
+	d.AuditRequest(req, ucred, user, 202, daemon.AsyncResponse(nil, "42"))
+
+	entries := s.audit(c, "")
+	c.Assert(entries, check.HasLen, 1)
+	c.Check(entries[0]["user"], check.Equals, "someone")
+	c.Check(entries[0]["change"], check.Equals, "42")
+	c.Check(entries[0]["status-code"], check.Equals, 202.0)
+}
+
+func (s *auditSuite) TestAuditRotation(c *check.C) {
+	s.AddCleanup(daemon.MockAuditLog(200, 2))
+	d := s.daemon(c)
+
+	req, err := http.NewRequest("POST", "/v2/snaps", nil)
+	c.Assert(err, check.IsNil)
+	for i := 0; i < 10; i++ {
+		d.AuditRequest(req, nil, nil, 0, nil)
+	}
+
+	c.Check(dirs.SnapAuditLogFile, check.Not(check.Equals), "")
+	for _, suffix := range []string{"", ".1", ".2"} {
+		fi, err := os.Stat(dirs.SnapAuditLogFile + suffix)
+		c.Assert(err, check.IsNil)
+		c.Check(fi.Size() <= 200, check.Equals, true)
+	}
+	c.Check(dirs.SnapAuditLogFile+".3", testutil.FileAbsent)
+
+	// entries are read across the rotated logs, oldest first
+	entries := s.audit(c, "")
+	c.Check(len(entries) > 2, check.Equals, true)
+	c.Check(len(entries) < 10, check.Equals, true)
+	for _, e := range entries {
+		c.Check(e["endpoint"], check.Equals, "/v2/snaps")
+		c.Check(e["status-code"], check.Equals, 200.0)
+	}
+}
+
+func (s *auditSuite) TestAuditBadRequest(c *check.C) {
+	s.daemon(c)
+
+	for _, t := range []struct {
+		query string
+		err   string
+	}{
+		{"?since=yesterday", `invalid "since" timestamp: .*`},
+		{"?until=tomorrow", `invalid "until" timestamp: .*`},
+		{"?uid=-1", `invalid "uid" parameter: "-1" is not a valid uid`},
+	} {
+		req, err := http.NewRequest("GET", "/v2/audit"+t.query, nil)
+		c.Assert(err, check.IsNil)
+		s.expectReadAccess(daemon.RootAccess{})
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, 400)
+		c.Check(rspe.Message, check.Matches, t.err)
+	}
+}
+
+func (s *auditSuite) TestAuditNotRoot(c *check.C) {
+	s.daemon(c)
+
+	rec := s.serve(c, "GET", "/v2/audit", nil, fmt.Sprintf("pid=100;uid=1000;socket=%s;", dirs.SnapdSocket))
+	c.Check(rec.Code, check.Equals, 403)
+}
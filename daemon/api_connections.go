@@ -20,7 +20,9 @@
 package daemon
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"sort"
 
@@ -32,9 +34,11 @@ import (
 )
 
 var connectionsCmd = &Command{
-	Path:       "/v2/connections",
-	GET:        getConnections,
-	ReadAccess: openAccess{},
+	Path:        "/v2/connections",
+	GET:         getConnections,
+	POST:        postConnections,
+	ReadAccess:  openAccess{},
+	WriteAccess: authenticatedAccess{Polkit: polkitActionManageInterfaces},
 }
 
 type collectFilter struct {
@@ -286,3 +290,54 @@ func getConnections(c *Command, r *http.Request, user *auth.UserState) Response
 
 	return SyncResponse(connsjson)
 }
+
+// postConnections acts on existing connections. Refreshing a connection
+// updates its dynamic attributes without disconnecting it.
+func postConnections(c *Command, r *http.Request, user *auth.UserState) Response {
+	var a connectionAction
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&a); err != nil {
+		return BadRequest("cannot decode request body into a connection action: %v", err)
+	}
+	if a.Action == "" {
+		return BadRequest("connection action not specified")
+	}
+	if a.Action != "refresh" {
+		return BadRequest("unsupported connection action: %q", a.Action)
+	}
+	if a.Plug.Snap == "" || a.Plug.Name == "" || a.Slot.Snap == "" || a.Slot.Name == "" {
+		return BadRequest("plug and slot must be fully specified")
+	}
+
+	connRef := &interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: ifacestate.RemapSnapFromRequest(a.Plug.Snap), Name: a.Plug.Name},
+		SlotRef: interfaces.SlotRef{Snap: ifacestate.RemapSnapFromRequest(a.Slot.Snap), Name: a.Slot.Name},
+	}
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	for _, snapName := range []string{connRef.PlugRef.Snap, connRef.SlotRef.Snap} {
+		var snapst snapstate.SnapState
+		err := snapstate.Get(st, snapName, &snapst)
+		if (err == nil && !snapst.IsInstalled()) || errors.Is(err, state.ErrNoState) {
+			return BadRequest("snap %q is not installed", snapName)
+		}
+		if err != nil {
+			return InternalError("cannot get state of snap %q: %v", snapName, err)
+		}
+	}
+
+	ts, err := ifacestate.RefreshConnection(st, connRef, a.PlugAttrs, a.SlotAttrs, nil)
+	if err != nil {
+		return errToResponse(err, nil, BadRequest, "%v")
+	}
+
+	summary := fmt.Sprintf("Refresh connection of %s:%s to %s:%s", connRef.PlugRef.Snap, connRef.PlugRef.Name, connRef.SlotRef.Snap, connRef.SlotRef.Name)
+	affected := snapNamesFromConns([]*interfaces.ConnRef{connRef})
+	change := newChange(st, "refresh-connection", summary, []*state.TaskSet{ts}, affected)
+	st.EnsureBefore(0)
+
+	return AsyncResponse(nil, change.ID())
+}
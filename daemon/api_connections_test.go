@@ -20,6 +20,7 @@
 package daemon_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -1042,3 +1043,111 @@ slots:
 		"type":        "sync",
 	})
 }
+
+// Tests for POST /v2/connections
+
+func (s *interfacesSuite) mockRefreshableConnection(c *check.C) *daemon.Daemon {
+	d := s.daemon(c)
+
+	mockIface(c, d, &ifacetest.TestInterface{InterfaceName: "test"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+
+	repo := d.Overlord().InterfaceManager().Repository()
+	connRef := &interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+		SlotRef: interfaces.SlotRef{Snap: "producer", Name: "slot"},
+	}
+	_, err := repo.Connect(connRef, nil, map[string]interface{}{"dynamic": "old"}, nil, nil, nil)
+	c.Assert(err, check.IsNil)
+
+	st := d.Overlord().State()
+	st.Lock()
+	st.Set("conns", map[string]interface{}{
+		"consumer:plug producer:slot": map[string]interface{}{
+			"interface":    "test",
+			"plug-static":  map[string]interface{}{"key": "value", "label": "label"},
+			"plug-dynamic": map[string]interface{}{"dynamic": "old"},
+			"slot-static":  map[string]interface{}{"key": "value", "label": "label"},
+		},
+	})
+	st.Unlock()
+
+	return d
+}
+
+func (s *interfacesSuite) TestRefreshConnection(c *check.C) {
+	d := s.mockRefreshableConnection(c)
+
+	d.Overlord().Loop()
+	defer d.Overlord().Stop()
+
+	buf := bytes.NewBufferString(`{"action": "refresh", "plug": {"snap": "consumer", "plug": "plug"}, "slot": {"snap": "producer", "slot": "slot"}, "plug-attrs": {"dynamic": "new"}, "slot-attrs": {"other": 42}}`)
+	req, err := http.NewRequest("POST", "/v2/connections", buf)
+	c.Assert(err, check.IsNil)
+	rsp := s.asyncReq(c, req, nil)
+
+	st := d.Overlord().State()
+	st.Lock()
+	chg := st.Change(rsp.Change)
+	c.Assert(chg, check.NotNil)
+	c.Check(chg.Kind(), check.Equals, "refresh-connection")
+	c.Check(chg.Summary(), check.Equals, "Refresh connection of consumer:plug to producer:slot")
+	var affected []string
+	c.Assert(chg.Get("snap-names", &affected), check.IsNil)
+	c.Check(affected, check.DeepEquals, []string{"consumer", "producer"})
+	st.Unlock()
+
+	<-chg.Ready()
+
+	st.Lock()
+	defer st.Unlock()
+	c.Assert(chg.Err(), check.IsNil)
+
+	var conns map[string]interface{}
+	c.Assert(st.Get("conns", &conns), check.IsNil)
+	c.Check(conns, check.DeepEquals, map[string]interface{}{
+		"consumer:plug producer:slot": map[string]interface{}{
+			"interface":    "test",
+			"plug-static":  map[string]interface{}{"key": "value", "label": "label"},
+			"plug-dynamic": map[string]interface{}{"dynamic": "new"},
+			"slot-static":  map[string]interface{}{"key": "value", "label": "label"},
+			"slot-dynamic": map[string]interface{}{"other": 42.0},
+		},
+	})
+}
+
+func (s *interfacesSuite) TestRefreshConnectionErrors(c *check.C) {
+	s.mockRefreshableConnection(c)
+
+	for _, t := range []struct {
+		body string
+		err  string
+	}{
+		{`}`, `cannot decode request body into a connection action: .*`},
+		{`{}`, `connection action not specified`},
+		{`{"action": "connect"}`, `unsupported connection action: "connect"`},
+		{`{"action": "refresh", "plug": {"snap": "consumer", "plug": "plug"}}`, `plug and slot must be fully specified`},
+		{`{"action": "refresh", "plug": {"snap": "other", "plug": "plug"}, "slot": {"snap": "producer", "slot": "slot"}}`, `snap "other" is not installed`},
+		{`{"action": "refresh", "plug": {"snap": "consumer", "plug": "nope"}, "slot": {"snap": "producer", "slot": "slot"}}`, `cannot refresh connection "consumer:nope producer:slot": not connected`},
+		{`{"action": "refresh", "plug": {"snap": "consumer", "plug": "plug"}, "slot": {"snap": "producer", "slot": "slot"}, "slot-attrs": {"key": "other"}}`, `cannot refresh connection "consumer:plug producer:slot": slot attribute "key" cannot be overwritten`},
+	} {
+		req, err := http.NewRequest("POST", "/v2/connections", bytes.NewBufferString(t.body))
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, 400, check.Commentf(t.body))
+		c.Check(rspe.Message, check.Matches, t.err, check.Commentf(t.body))
+	}
+}
+
+func (s *interfacesSuite) TestRefreshConnectionConflict(c *check.C) {
+	s.mockRefreshableConnection(c)
+	s.simulateConflict("consumer")
+
+	buf := bytes.NewBufferString(`{"action": "refresh", "plug": {"snap": "consumer", "plug": "plug"}, "slot": {"snap": "producer", "slot": "slot"}}`)
+	req, err := http.NewRequest("POST", "/v2/connections", buf)
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 409)
+	c.Check(rspe.Message, check.Equals, `snap "consumer" has "manip" change in progress`)
+}
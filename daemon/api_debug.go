@@ -52,6 +52,18 @@ type debugAction struct {
 		ChgID string `json:"chg-id"`
 
 		RecoverySystemLabel string `json:"recovery-system-label"`
+
+		Plug connectionPolicyEnd `json:"plug"`
+		Slot connectionPolicyEnd `json:"slot"`
+		Auto bool                `json:"auto"`
+
+		Snap     string `json:"snap"`
+		SnapYaml string `json:"snap-yaml"`
+		App      string `json:"app"`
+		Backend  string `json:"backend"`
+
+		SnapSHA3_384 string `json:"snap-sha3-384"`
+		SnapSize     uint64 `json:"snap-size"`
 	} `json:"params"`
 	Snaps []string `json:"snaps"`
 }
@@ -370,6 +382,13 @@ func getDebug(c *Command, r *http.Request, user *auth.UserState) Response {
 		return getGadgetDiskMapping(st)
 	case "disks":
 		return getDisks(st)
+	case "denials":
+		// ucred is nil if unknown, which denialsAccess refuses
+		ucred, _ := ucrednetGet(r.RemoteAddr)
+		if rspe := denialsAccess.CheckAccess(c.d, r, ucred, user); rspe != nil {
+			return rspe
+		}
+		return getDenials(st, c.d.overlord.InterfaceManager().Repository(), query.Get("snap"), query.Get("since"))
 	default:
 		return BadRequest("unknown debug aspect %q", aspect)
 	}
@@ -411,6 +430,15 @@ func postDebug(c *Command, r *http.Request, user *auth.UserState) Response {
 		return createRecovery(st, a.Params.RecoverySystemLabel)
 	case "migrate-home":
 		return migrateHome(st, a.Snaps)
+	case "connection-policy":
+		repo := c.d.overlord.InterfaceManager().Repository()
+		return explainConnectionPolicy(st, repo, a.Params.Plug, a.Params.Slot, a.Params.Auto)
+	case "sandbox-profiles":
+		ifacemgr := c.d.overlord.InterfaceManager()
+		return previewSandboxProfiles(st, ifacemgr, a.Params.Snap, a.Params.SnapYaml, a.Params.App, a.Params.Backend)
+	case "autoconnect-preview":
+		ifacemgr := c.d.overlord.InterfaceManager()
+		return previewAutoConnections(st, ifacemgr, a.Params.Snap, a.Params.SnapYaml, a.Params.SnapSHA3_384, a.Params.SnapSize)
 	default:
 		return BadRequest("unknown debug action: %v", a.Action)
 	}
@@ -0,0 +1,132 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/asserts/snapasserts"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/overlord/assertstate"
+	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+var ifacemgrPreviewAutoConnections = (*ifacestate.InterfaceManager).PreviewAutoConnections
+
+type autoConnectPreviewCandidate struct {
+	Plug *interfaces.PlugRef `json:"plug,omitempty"`
+	Slot *interfaces.SlotRef `json:"slot,omitempty"`
+	Auto bool                `json:"auto"`
+	// Policy explains the decision of the declarations, if there is a
+	// candidate to connect to.
+	Policy *connectionPolicyResult `json:"policy,omitempty"`
+	Note   string                  `json:"note,omitempty"`
+}
+
+type autoConnectPreview struct {
+	Snap string `json:"snap"`
+	// SnapID is set if the snap is asserted, that is its snap-revision
+	// and snap-declaration are known.
+	SnapID     string                        `json:"snap-id,omitempty"`
+	Candidates []autoConnectPreviewCandidate `json:"candidates"`
+}
+
+// snapFileSideInfo returns the side info of the snap file with the given
+// digest and size, and the given snap.yaml, according to the snap-revision
+// and snap-declaration found in the assertion database. Unlike when
+// installing the snap the file itself is not available.
+func snapFileSideInfo(st *state.State, info *snap.Info, digest string, size uint64) (*snap.SideInfo, error) {
+	db := assertstate.DB(st)
+	a, err := db.Find(asserts.SnapRevisionType, map[string]string{
+		"snap-sha3-384": digest,
+		"provenance":    info.Provenance(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	snapRev := a.(*asserts.SnapRevision)
+	if snapRev.SnapSize() != size {
+		return nil, fmt.Errorf("snap file does not have the expected size according to signatures: %d != %d", size, snapRev.SnapSize())
+	}
+	snapDecl, err := assertstate.SnapDeclaration(st, snapRev.SnapID())
+	if err != nil {
+		return nil, fmt.Errorf("cannot find snap declaration: %v", err)
+	}
+	if snapDecl.SnapName() != info.SnapName() {
+		return nil, fmt.Errorf("snap file is signed for snap %q", snapDecl.SnapName())
+	}
+	return snapasserts.SideInfoFromSnapAssertions(snapDecl, snapRev), nil
+}
+
+// previewAutoConnections reports which plugs and slots of the given snap
+// would be auto-connected if it was installed on this device, and why. A
+// snap which is not installed can be given by its snap.yaml, in which case
+// its snap-revision and snap-declaration are looked up in the assertion
+// database using the digest and size of its snap file, if given.
+func previewAutoConnections(st *state.State, ifacemgr *ifacestate.InterfaceManager, instanceName, snapYaml, digest string, size uint64) Response {
+	if instanceName == "" && snapYaml == "" {
+		return BadRequest("a snap must be given")
+	}
+	info, rspe := sandboxProfilesSnapInfo(st, instanceName, snapYaml)
+	if rspe != nil {
+		return rspe
+	}
+	if snapYaml != "" && digest != "" {
+		si, err := snapFileSideInfo(st, info, digest, size)
+		switch {
+		case errors.Is(err, &asserts.NotFoundError{}):
+			// the snap is unasserted
+		case err != nil:
+			return BadRequest("cannot use the assertions of snap %q: %v", info.InstanceName(), err)
+		default:
+			info.SnapID = si.SnapID
+			info.Revision = si.Revision
+		}
+	}
+
+	candidates, err := ifacemgrPreviewAutoConnections(ifacemgr, info)
+	if err != nil {
+		return InternalError("cannot preview auto-connections of snap %q: %v", info.InstanceName(), err)
+	}
+
+	result := &autoConnectPreview{
+		Snap:       info.InstanceName(),
+		SnapID:     info.SnapID,
+		Candidates: make([]autoConnectPreviewCandidate, 0, len(candidates)),
+	}
+	for _, cand := range candidates {
+		rc := autoConnectPreviewCandidate{Auto: cand.Auto, Note: cand.Note}
+		if cand.Plug != nil {
+			rc.Plug = &interfaces.PlugRef{Snap: cand.Plug.Snap.InstanceName(), Name: cand.Plug.Name}
+		}
+		if cand.Slot != nil {
+			rc.Slot = &interfaces.SlotRef{Snap: cand.Slot.Snap.InstanceName(), Name: cand.Slot.Name}
+		}
+		if cand.Explanation != nil {
+			rc.Policy = newConnectionPolicyResult(cand.Explanation, cand.Plug, cand.Slot)
+		}
+		result.Candidates = append(result.Candidates, rc)
+	}
+	return SyncResponse(result)
+}
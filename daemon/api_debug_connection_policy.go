@@ -0,0 +1,162 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/policy"
+	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
+)
+
+var ifacestateExplainConnection = ifacestate.ExplainConnection
+
+// connectionPolicyEnd identifies the plug or slot of a connection whose
+// policy is explained.
+type connectionPolicyEnd struct {
+	Snap string `json:"snap"`
+	Name string `json:"name"`
+	// SnapYaml is the snap.yaml of a snap which is not installed, as
+	// found in its snap file. Such snaps are considered unasserted.
+	SnapYaml string `json:"snap-yaml,omitempty"`
+}
+
+type connectionPolicyResult struct {
+	Allowed bool   `json:"allowed"`
+	Kind    string `json:"kind"`
+	// Declaration, SnapName, Side and Constraint identify the rule which
+	// decided, if any.
+	Declaration string `json:"declaration,omitempty"`
+	SnapName    string `json:"snap-name,omitempty"`
+	Side        string `json:"side,omitempty"`
+	Constraint  string `json:"constraint,omitempty"`
+	// Alternative is the index of the matching alternative of the
+	// constraint, if any.
+	Alternative *int   `json:"alternative,omitempty"`
+	Mismatch    string `json:"mismatch,omitempty"`
+	Error       string `json:"error,omitempty"`
+	// Unasserted are the snaps which have no snap declaration.
+	Unasserted []string `json:"unasserted,omitempty"`
+}
+
+// systemSnapNames are the names the snap providing the implicit slots can
+// have, in order of preference.
+var systemSnapNames = []string{"snapd", "core", "ubuntu-core"}
+
+func connectionPolicySnapYaml(end connectionPolicyEnd) (*snap.Info, *apiError) {
+	info, err := snap.InfoFromSnapYaml([]byte(end.SnapYaml))
+	if err != nil {
+		return nil, BadRequest("cannot read snap.yaml of %q: %v", end.Snap, err)
+	}
+	if end.Snap != "" && end.Snap != info.InstanceName() {
+		return nil, BadRequest("snap.yaml is for snap %q, not %q", info.InstanceName(), end.Snap)
+	}
+	return info, nil
+}
+
+func connectionPolicyPlug(repo *interfaces.Repository, end connectionPolicyEnd) (*snap.PlugInfo, *apiError) {
+	if end.SnapYaml != "" {
+		info, rspe := connectionPolicySnapYaml(end)
+		if rspe != nil {
+			return nil, rspe
+		}
+		if plug := info.Plugs[end.Name]; plug != nil {
+			return plug, nil
+		}
+		return nil, NotFound("snap %q has no plug named %q", info.InstanceName(), end.Name)
+	}
+	if plug := repo.Plug(end.Snap, end.Name); plug != nil {
+		return plug, nil
+	}
+	return nil, NotFound("snap %q has no plug named %q", end.Snap, end.Name)
+}
+
+func connectionPolicySlot(repo *interfaces.Repository, end connectionPolicyEnd) (*snap.SlotInfo, *apiError) {
+	if end.SnapYaml != "" {
+		info, rspe := connectionPolicySnapYaml(end)
+		if rspe != nil {
+			return nil, rspe
+		}
+		if slot := info.Slots[end.Name]; slot != nil {
+			return slot, nil
+		}
+		return nil, NotFound("snap %q has no slot named %q", info.InstanceName(), end.Name)
+	}
+	names := []string{end.Snap}
+	if end.Snap == "" {
+		names = systemSnapNames
+	}
+	for _, name := range names {
+		if slot := repo.Slot(name, end.Name); slot != nil {
+			return slot, nil
+		}
+	}
+	return nil, NotFound("snap %q has no slot named %q", end.Snap, end.Name)
+}
+
+// explainConnectionPolicy reports which rule of the base declaration or of
+// the snap declarations decides whether the given plug can be connected,
+// or auto-connected, to the given slot.
+func explainConnectionPolicy(st *state.State, repo *interfaces.Repository, plugEnd, slotEnd connectionPolicyEnd, auto bool) Response {
+	if plugEnd.Name == "" || slotEnd.Name == "" {
+		return BadRequest("both a plug and a slot must be given")
+	}
+	plug, rspe := connectionPolicyPlug(repo, plugEnd)
+	if rspe != nil {
+		return rspe
+	}
+	slot, rspe := connectionPolicySlot(repo, slotEnd)
+	if rspe != nil {
+		return rspe
+	}
+
+	expl, err := ifacestateExplainConnection(st, plug, slot, auto)
+	if err != nil {
+		return InternalError("cannot explain connection policy: %v", err)
+	}
+	return SyncResponse(newConnectionPolicyResult(expl, plug, slot))
+}
+
+func newConnectionPolicyResult(expl *policy.ConnectionExplanation, plug *snap.PlugInfo, slot *snap.SlotInfo) *connectionPolicyResult {
+	result := &connectionPolicyResult{
+		Allowed:     expl.Allowed(),
+		Kind:        expl.Kind,
+		Declaration: expl.Declaration,
+		SnapName:    expl.SnapName,
+		Side:        expl.Side,
+		Constraint:  expl.Constraint,
+		Mismatch:    expl.Mismatch,
+	}
+	if expl.Alternative >= 0 {
+		alt := expl.Alternative
+		result.Alternative = &alt
+	}
+	if expl.Err != nil {
+		result.Error = expl.Err.Error()
+	}
+	for _, info := range []*snap.Info{plug.Snap, slot.Snap} {
+		if info.SnapID == "" && !strutil.ListContains(result.Unasserted, info.InstanceName()) {
+			result.Unasserted = append(result.Unasserted, info.InstanceName())
+		}
+	}
+	return result
+}
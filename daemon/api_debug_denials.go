@@ -0,0 +1,165 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/systemd"
+)
+
+// denialsAccess guards the denials debug aspect: the kernel and audit logs
+// the denials are read from are not readable by regular users, and they
+// mention the files and processes of all the users of the system.
+var denialsAccess = authenticatedAccess{Polkit: polkitActionManage}
+
+// defaultDenialsWindow is how far back denials are looked for when no
+// start time is given, so as not to go through the whole journal.
+const defaultDenialsWindow = 24 * time.Hour
+
+type denialSuggestion struct {
+	Interface string `json:"interface"`
+	Plug      string `json:"plug,omitempty"`
+	Connected bool   `json:"connected,omitempty"`
+}
+
+type denialGroup struct {
+	Snap        string             `json:"snap"`
+	App         string             `json:"app"`
+	Kind        string             `json:"kind"`
+	Access      string             `json:"access"`
+	Comm        string             `json:"comm,omitempty"`
+	Count       int                `json:"count"`
+	First       time.Time          `json:"first"`
+	Last        time.Time          `json:"last"`
+	Suggestions []denialSuggestion `json:"suggestions,omitempty"`
+}
+
+// readDenials returns the denials of the apps and hooks of snaps logged
+// since the given time, optionally only those of the given snap.
+func readDenials(since time.Time, snapName string) ([]*denials.Denial, error) {
+	log, err := systemd.AuditLog(since)
+	if err != nil {
+		return nil, err
+	}
+	defer log.Close()
+
+	var result []*denials.Denial
+	dec := json.NewDecoder(log)
+	for {
+		var entry systemd.Log
+		if err := dec.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return result, nil
+			}
+			return nil, err
+		}
+		d := denials.Parse(entry.Message())
+		if d == nil || d.Snap() == "" || (snapName != "" && d.Snap() != snapName) {
+			continue
+		}
+		if d.Time, err = entry.Time(); err != nil {
+			logger.Debugf("cannot get the time of denial %q: %v", entry.Message(), err)
+		}
+		result = append(result, d)
+	}
+}
+
+// getDenials returns the sandbox denials of snaps logged since the given
+// time, or over the last defaultDenialsWindow, grouped, along with the
+// interfaces which would allow them.
+func getDenials(st *state.State, repo *interfaces.Repository, snapName, sinceStr string) Response {
+	since, err := parseOptionalTime(sinceStr)
+	if err != nil {
+		return BadRequest(`invalid "since" timestamp: %v`, err)
+	}
+	if since.IsZero() {
+		since = time.Now().Add(-defaultDenialsWindow)
+	}
+
+	// reading the journal can take a while
+	st.Unlock()
+	found, err := readDenials(since, snapName)
+	st.Lock()
+	if err != nil {
+		return InternalError("cannot read the denials from the journal: %v", err)
+	}
+
+	appSets := make(map[string]*interfaces.SnapAppSet)
+	groups := []denialGroup{}
+	for _, g := range denials.GroupDenials(found) {
+		group := denialGroup{
+			Snap:   g.Snap(),
+			App:    g.App(),
+			Kind:   string(g.Kind),
+			Access: g.Access(),
+			Comm:   g.Comm,
+			Count:  g.Count,
+			First:  g.First,
+			Last:   g.Last,
+		}
+		appSet, ok := appSets[group.Snap]
+		if !ok {
+			appSet, err = currentAppSet(st, group.Snap)
+			if err != nil {
+				// the snap may have been removed since
+				logger.Debugf("cannot get the apps of snap %q: %v", group.Snap, err)
+			}
+			appSets[group.Snap] = appSet
+		}
+		if appSet != nil {
+			for _, s := range denials.Suggest(repo, appSet, g.Denial) {
+				group.Suggestions = append(group.Suggestions, denialSuggestion{
+					Interface: s.Interface,
+					Plug:      s.Plug,
+					Connected: s.Connected,
+				})
+			}
+		}
+		groups = append(groups, group)
+	}
+	return SyncResponse(groups)
+}
+
+// currentAppSet returns the app set of the current revision of the given
+// snap.
+func currentAppSet(st *state.State, snapName string) (*interfaces.SnapAppSet, error) {
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(st, snapName, &snapst); err != nil {
+		return nil, err
+	}
+	info, err := snapst.CurrentInfo()
+	if err != nil {
+		return nil, err
+	}
+	compInfos, err := snapst.ComponentInfosForRevision(info.Revision)
+	if err != nil {
+		return nil, err
+	}
+	return interfaces.NewSnapAppSet(info, compInfos)
+}
@@ -0,0 +1,119 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+var ifacemgrPreviewProfiles = (*ifacestate.InterfaceManager).PreviewProfiles
+
+type sandboxProfile struct {
+	Backend string `json:"backend"`
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// sandboxProfilesSnapInfo returns the information of the given installed
+// snap, or of the snap with the given snap.yaml if one is given.
+func sandboxProfilesSnapInfo(st *state.State, instanceName, snapYaml string) (*snap.Info, *apiError) {
+	if snapYaml == "" {
+		info, err := snapstate.CurrentInfo(st, instanceName)
+		if err != nil {
+			var notInstalled *snap.NotInstalledError
+			if errors.As(err, &notInstalled) {
+				return nil, SnapNotInstalled(instanceName, err)
+			}
+			return nil, InternalError("%v", err)
+		}
+		return info, nil
+	}
+	info, err := snap.InfoFromSnapYaml([]byte(snapYaml))
+	if err != nil {
+		return nil, BadRequest("cannot read snap.yaml: %v", err)
+	}
+	if instanceName != "" && instanceName != info.InstanceName() {
+		return nil, BadRequest("snap.yaml is for snap %q, not %q", info.InstanceName(), instanceName)
+	}
+	// snaps which are not installed from the store get local revisions
+	info.Revision = snap.R(-1)
+	return info, nil
+}
+
+// isAppProfile returns whether the given profile file belongs to the app or
+// hook with the given security tag, rather than to the snap as a whole.
+func isAppProfile(path, securityTag string) bool {
+	name := filepath.Base(path)
+	return name == securityTag || strings.HasPrefix(name, securityTag+".")
+}
+
+// previewSandboxProfiles returns the security profiles that would be set up
+// for the given snap, optionally only those of the given app or backend.
+func previewSandboxProfiles(st *state.State, ifacemgr *ifacestate.InterfaceManager, instanceName, snapYaml, appName, backend string) Response {
+	if instanceName == "" && snapYaml == "" {
+		return BadRequest("a snap must be given")
+	}
+	info, rspe := sandboxProfilesSnapInfo(st, instanceName, snapYaml)
+	if rspe != nil {
+		return rspe
+	}
+	var securityTag string
+	if appName != "" {
+		app := info.Apps[appName]
+		if app == nil {
+			return NotFound("snap %q has no app %q", info.InstanceName(), appName)
+		}
+		securityTag = app.SecurityTag()
+	}
+
+	preview, err := ifacemgrPreviewProfiles(ifacemgr, info, backend)
+	if err != nil {
+		return BadRequest("cannot preview sandbox profiles of snap %q: %v", info.InstanceName(), err)
+	}
+
+	profiles := []sandboxProfile{}
+	for backendName, files := range preview {
+		for path, content := range files {
+			if securityTag != "" && !isAppProfile(path, securityTag) {
+				continue
+			}
+			profiles = append(profiles, sandboxProfile{
+				Backend: backendName,
+				Path:    path,
+				Content: string(content),
+			})
+		}
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Backend != profiles[j].Backend {
+			return profiles[i].Backend < profiles[j].Backend
+		}
+		return profiles[i].Path < profiles[j].Path
+	})
+	return SyncResponse(profiles)
+}
@@ -23,15 +23,24 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/check.v1"
 
+	"github.com/snapcore/snapd/asserts"
 	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces/policy"
+	"github.com/snapcore/snapd/overlord/ifacestate"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/systemd"
 	"github.com/snapcore/snapd/testutil"
 	"github.com/snapcore/snapd/timings"
 )
@@ -336,3 +345,460 @@ func (s *postDebugSuite) TestMigrateHomeInternalError(c *check.C) {
 	c.Check(apiErr.Status, check.Equals, 500)
 	c.Check(apiErr.Message, check.Equals, `boom`)
 }
+
+func (s *postDebugSuite) TestPostDebugConnectionPolicy(c *check.C) {
+	s.daemon(c)
+	s.expectRootAccess()
+
+	var calls int
+	restore := daemon.MockIfacestateExplainConnection(func(st *state.State, plug *snap.PlugInfo, slot *snap.SlotInfo, auto bool) (*policy.ConnectionExplanation, error) {
+		calls++
+		c.Check(plug.Snap.InstanceName(), check.Equals, "consumer")
+		c.Check(plug.Name, check.Equals, "data")
+		c.Check(plug.Attrs["content"], check.Equals, "foo")
+		c.Check(slot.Snap.InstanceName(), check.Equals, "producer")
+		c.Check(slot.Name, check.Equals, "data")
+		c.Check(auto, check.Equals, true)
+		return &policy.ConnectionExplanation{
+			Kind:        "auto-connection",
+			Declaration: "base-declaration",
+			Side:        "slot",
+			Constraint:  "allow-auto-connection",
+			Alternative: -1,
+			Mismatch:    `attribute "content" value "foo" does not match $PLUG(content)`,
+			Err:         errors.New(`auto-connection not allowed by slot rule of interface "content"`),
+		}, nil
+	})
+	defer restore()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"action": "connection-policy",
+		"params": map[string]interface{}{
+			"plug": map[string]interface{}{
+				"snap":      "consumer",
+				"name":      "data",
+				"snap-yaml": "name: consumer\nplugs:\n  data:\n    interface: content\n    content: foo\n",
+			},
+			"slot": map[string]interface{}{
+				"name":      "data",
+				"snap-yaml": "name: producer\nslots:\n  data:\n    interface: content\n    content: bar\n",
+			},
+			"auto": true,
+		},
+	})
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest("POST", "/v2/debug", bytes.NewReader(body))
+	c.Assert(err, check.IsNil)
+
+	rsp := s.syncReq(c, req, nil)
+	c.Check(calls, check.Equals, 1)
+
+	data, err := json.Marshal(rsp.Result)
+	c.Assert(err, check.IsNil)
+	var result map[string]interface{}
+	c.Assert(json.Unmarshal(data, &result), check.IsNil)
+	c.Check(result, check.DeepEquals, map[string]interface{}{
+		"allowed":     false,
+		"kind":        "auto-connection",
+		"declaration": "base-declaration",
+		"side":        "slot",
+		"constraint":  "allow-auto-connection",
+		"mismatch":    `attribute "content" value "foo" does not match $PLUG(content)`,
+		"error":       `auto-connection not allowed by slot rule of interface "content"`,
+		"unasserted":  []interface{}{"consumer", "producer"},
+	})
+}
+
+func (s *postDebugSuite) TestPostDebugConnectionPolicyErrors(c *check.C) {
+	s.daemon(c)
+	s.expectRootAccess()
+
+	restore := daemon.MockIfacestateExplainConnection(func(st *state.State, plug *snap.PlugInfo, slot *snap.SlotInfo, auto bool) (*policy.ConnectionExplanation, error) {
+		c.Fatalf("unexpected call")
+		return nil, nil
+	})
+	defer restore()
+
+	const yaml = "name: consumer\nplugs:\n  data:\n    interface: content\n"
+	for _, t := range []struct {
+		params string
+		status int
+		err    string
+	}{
+		{`{"plug": {"snap": "consumer", "name": "data"}}`, 400, `both a plug and a slot must be given`},
+		{`{"plug": {"snap": "consumer", "name": "data"}, "slot": {"snap": "producer", "name": "data"}}`, 404, `snap "consumer" has no plug named "data"`},
+		{`{"plug": {"snap": "consumer", "name": "other", "snap-yaml": ` + strconv.Quote(yaml) + `}, "slot": {"name": "data"}}`, 404, `snap "consumer" has no plug named "other"`},
+		{`{"plug": {"snap": "other", "name": "data", "snap-yaml": ` + strconv.Quote(yaml) + `}, "slot": {"name": "data"}}`, 400, `snap.yaml is for snap "consumer", not "other"`},
+		{`{"plug": {"snap": "consumer", "name": "data", "snap-yaml": ` + strconv.Quote(yaml) + `}, "slot": {"name": "data"}}`, 404, `snap "" has no slot named "data"`},
+		{`{"plug": {"snap": "consumer", "name": "data", "snap-yaml": "name: [foo"}, "slot": {"name": "data"}}`, 400, `cannot read snap.yaml of "consumer": .*`},
+	} {
+		buf := bytes.NewBufferString(`{"action": "connection-policy", "params": ` + t.params + `}`)
+		req, err := http.NewRequest("POST", "/v2/debug", buf)
+		c.Assert(err, check.IsNil)
+
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, t.status, check.Commentf("%s", t.params))
+		c.Check(rspe.Message, check.Matches, t.err, check.Commentf("%s", t.params))
+	}
+}
+
+func (s *postDebugSuite) TestPostDebugSandboxProfiles(c *check.C) {
+	s.daemon(c)
+	s.expectRootAccess()
+	s.mockSnap(c, "name: foo\nversion: 1\napps:\n  app:\n  other:\n")
+
+	var calls int
+	restore := daemon.MockIfacemgrPreviewProfiles(func(m *ifacestate.InterfaceManager, info *snap.Info, backend string) (map[string]map[string][]byte, error) {
+		calls++
+		c.Check(info.InstanceName(), check.Equals, "foo")
+		c.Check(info.Revision, check.Equals, snap.R(1))
+		c.Check(backend, check.Equals, "")
+		return map[string]map[string][]byte{
+			"seccomp": {
+				"/seccomp/snap.foo.other.src": []byte("other"),
+				"/seccomp/snap.foo.app.src":   []byte("app"),
+			},
+			"apparmor": {
+				"/apparmor/snap.foo.app":         []byte("app"),
+				"/apparmor/snap-update-ns.foo":   []byte("update-ns"),
+				"/apparmor/snap.foo.application": []byte("application"),
+			},
+		}, nil
+	})
+	defer restore()
+
+	req, err := http.NewRequest("POST", "/v2/debug", strings.NewReader(`{"action": "sandbox-profiles", "params": {"snap": "foo"}}`))
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.DeepEquals, []daemon.SandboxProfile{
+		{Backend: "apparmor", Path: "/apparmor/snap-update-ns.foo", Content: "update-ns"},
+		{Backend: "apparmor", Path: "/apparmor/snap.foo.app", Content: "app"},
+		{Backend: "apparmor", Path: "/apparmor/snap.foo.application", Content: "application"},
+		{Backend: "seccomp", Path: "/seccomp/snap.foo.app.src", Content: "app"},
+		{Backend: "seccomp", Path: "/seccomp/snap.foo.other.src", Content: "other"},
+	})
+
+	// only the profiles of the given app
+	req, err = http.NewRequest("POST", "/v2/debug", strings.NewReader(`{"action": "sandbox-profiles", "params": {"snap": "foo", "app": "app"}}`))
+	c.Assert(err, check.IsNil)
+	rsp = s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.DeepEquals, []daemon.SandboxProfile{
+		{Backend: "apparmor", Path: "/apparmor/snap.foo.app", Content: "app"},
+		{Backend: "seccomp", Path: "/seccomp/snap.foo.app.src", Content: "app"},
+	})
+	c.Check(calls, check.Equals, 2)
+}
+
+func (s *postDebugSuite) TestPostDebugSandboxProfilesSnapYaml(c *check.C) {
+	s.daemon(c)
+	s.expectRootAccess()
+
+	restore := daemon.MockIfacemgrPreviewProfiles(func(m *ifacestate.InterfaceManager, info *snap.Info, backend string) (map[string]map[string][]byte, error) {
+		c.Check(info.InstanceName(), check.Equals, "bar")
+		c.Check(info.Revision, check.Equals, snap.R(-1))
+		c.Check(backend, check.Equals, "udev")
+		return map[string]map[string][]byte{}, nil
+	})
+	defer restore()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"action": "sandbox-profiles",
+		"params": map[string]interface{}{
+			"snap-yaml": "name: bar\nversion: 1\n",
+			"backend":   "udev",
+		},
+	})
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest("POST", "/v2/debug", bytes.NewReader(body))
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.DeepEquals, []daemon.SandboxProfile{})
+}
+
+func (s *postDebugSuite) TestPostDebugSandboxProfilesErrors(c *check.C) {
+	s.daemon(c)
+	s.expectRootAccess()
+	s.mockSnap(c, "name: foo\nversion: 1\napps:\n  app:\n")
+
+	restore := daemon.MockIfacemgrPreviewProfiles(func(m *ifacestate.InterfaceManager, info *snap.Info, backend string) (map[string]map[string][]byte, error) {
+		return nil, errors.New(`unknown security backend "foo"`)
+	})
+	defer restore()
+
+	for _, t := range []struct {
+		params string
+		status int
+		err    string
+	}{
+		{`{}`, 400, `a snap must be given`},
+		{`{"snap": "missing"}`, 400, `snap "missing" is not installed`},
+		{`{"snap": "foo", "app": "missing"}`, 404, `snap "foo" has no app "missing"`},
+		{`{"snap": "other", "snap-yaml": "name: foo\nversion: 1\n"}`, 400, `snap.yaml is for snap "foo", not "other"`},
+		{`{"snap-yaml": "name: [foo"}`, 400, `cannot read snap.yaml: .*`},
+		{`{"snap": "foo", "backend": "foo"}`, 400, `cannot preview sandbox profiles of snap "foo": unknown security backend "foo"`},
+	} {
+		buf := bytes.NewBufferString(`{"action": "sandbox-profiles", "params": ` + t.params + `}`)
+		req, err := http.NewRequest("POST", "/v2/debug", buf)
+		c.Assert(err, check.IsNil)
+
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, t.status, check.Commentf("%s", t.params))
+		c.Check(rspe.Message, check.Matches, t.err, check.Commentf("%s", t.params))
+	}
+}
+
+func (s *postDebugSuite) TestGetDebugDenials(c *check.C) {
+	s.daemon(c)
+	s.mockSnap(c, "name: core\nversion: 1\ntype: os\nslots:\n  network-observe:\n  hardware-observe:\n")
+	s.mockSnap(c, "name: foo\nversion: 1\napps:\n  app:\n    plugs: [network-observe]\n")
+
+	const denial = `audit: type=1400 audit(1700000000.123:456): apparmor=\"DENIED\" operation=\"open\" profile=\"snap.foo.app\" name=\"/sys/devices/virtual/net/lo/statistics/rx_bytes\" pid=1234 comm=\"foo\" requested_mask=\"r\" denied_mask=\"r\" fsuid=1000 ouid=0`
+	var since []time.Time
+	restore := systemd.MockAuditLog(func(t time.Time) (io.ReadCloser, error) {
+		since = append(since, t)
+		return io.NopCloser(strings.NewReader(`
+{"MESSAGE":"` + denial + `","__REALTIME_TIMESTAMP":"1700000060000000"}
+{"MESSAGE":"eth0: link up","__REALTIME_TIMESTAMP":"1700000030000000"}
+{"MESSAGE":"` + denial + `","__REALTIME_TIMESTAMP":"1700000000000000"}
+{"MESSAGE":"` + strings.Replace(denial, "snap.foo.app", "snap.bar.app", 1) + `","__REALTIME_TIMESTAMP":"1700000000000000"}
+`)), nil
+	})
+	defer restore()
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=denials&snap=foo&since=2023-11-14T22:00:00Z", nil)
+	c.Assert(err, check.IsNil)
+	s.asRootAuth(req)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(since, check.DeepEquals, []time.Time{time.Date(2023, 11, 14, 22, 0, 0, 0, time.UTC)})
+
+	data, err := json.Marshal(rsp.Result)
+	c.Assert(err, check.IsNil)
+	var result []map[string]interface{}
+	c.Assert(json.Unmarshal(data, &result), check.IsNil)
+	c.Check(result, check.DeepEquals, []map[string]interface{}{{
+		"snap":   "foo",
+		"app":    "app",
+		"kind":   "apparmor",
+		"access": "open /sys/devices/virtual/net/lo/statistics/rx_bytes (r)",
+		"comm":   "foo",
+		"count":  2.0,
+		"first":  time.Unix(1700000000, 0).Format(time.RFC3339Nano),
+		"last":   time.Unix(1700000060, 0).Format(time.RFC3339Nano),
+		"suggestions": []interface{}{
+			map[string]interface{}{"interface": "hardware-observe"},
+			map[string]interface{}{"interface": "network-observe", "plug": "network-observe"},
+		},
+	}})
+}
+
+func (s *postDebugSuite) TestGetDebugDenialsErrors(c *check.C) {
+	s.daemon(c)
+
+	restore := systemd.MockAuditLog(func(t time.Time) (io.ReadCloser, error) {
+		return nil, errors.New("boom")
+	})
+	defer restore()
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=denials", nil)
+	c.Assert(err, check.IsNil)
+	s.asRootAuth(req)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 500)
+	c.Check(rspe.Message, check.Equals, "cannot read the denials from the journal: boom")
+
+	req, err = http.NewRequest("GET", "/v2/debug?aspect=denials&since=yesterday", nil)
+	c.Assert(err, check.IsNil)
+	s.asRootAuth(req)
+	rspe = s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Matches, `invalid "since" timestamp: .*`)
+}
+
+func (s *postDebugSuite) TestGetDebugDenialsDefaultSince(c *check.C) {
+	s.daemon(c)
+
+	var since []time.Time
+	restore := systemd.MockAuditLog(func(t time.Time) (io.ReadCloser, error) {
+		since = append(since, t)
+		return io.NopCloser(strings.NewReader("")), nil
+	})
+	defer restore()
+
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=denials", nil)
+	c.Assert(err, check.IsNil)
+	s.asRootAuth(req)
+	before := time.Now()
+	rsp := s.syncReq(c, req, nil)
+	after := time.Now()
+	data, err := json.Marshal(rsp.Result)
+	c.Assert(err, check.IsNil)
+	c.Check(string(data), check.Equals, "[]")
+	c.Assert(since, check.HasLen, 1)
+	c.Check(since[0].Before(before.Add(-24*time.Hour)), check.Equals, false)
+	c.Check(since[0].After(after.Add(-24*time.Hour)), check.Equals, false)
+}
+
+func (s *postDebugSuite) TestGetDebugDenialsAccess(c *check.C) {
+	s.daemon(c)
+
+	restore := systemd.MockAuditLog(func(t time.Time) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("")), nil
+	})
+	defer restore()
+	var polkitAuthorized bool
+	restore = daemon.MockCheckPolkitAction(func(r *http.Request, ucred *daemon.Ucrednet, action string) *daemon.APIError {
+		c.Check(action, check.Equals, "io.snapcraft.snapd.manage")
+		if polkitAuthorized {
+			return nil
+		}
+		return daemon.Forbidden("access denied")
+	})
+	defer restore()
+
+	// the other debug aspects are open to everyone, but the logs of the
+	// denials are only readable by administrators
+	req, err := http.NewRequest("GET", "/v2/debug?aspect=denials", nil)
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 403)
+
+	req.RemoteAddr = fmt.Sprintf("pid=100;uid=1000;socket=%s;", dirs.SnapdSocket)
+	rspe = s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 403)
+
+	polkitAuthorized = true
+	s.syncReq(c, req, nil)
+}
+
+func (s *postDebugSuite) TestPostDebugAutoConnectPreview(c *check.C) {
+	s.daemon(c)
+	s.expectRootAccess()
+	s.mockSnap(c, "name: core\nversion: 1\ntype: os\nslots:\n  network:\n")
+	foo := s.mockSnap(c, "name: foo\nversion: 1\nplugs:\n  network:\n  camera:\n")
+	core := s.d.Overlord().InterfaceManager().Repository().Slot("core", "network")
+	c.Assert(core, check.NotNil)
+
+	var calls int
+	restore := daemon.MockIfacemgrPreviewAutoConnections(func(m *ifacestate.InterfaceManager, info *snap.Info) ([]*ifacestate.AutoConnectCandidate, error) {
+		calls++
+		c.Check(info.InstanceName(), check.Equals, "foo")
+		c.Check(info.Revision, check.Equals, snap.R(1))
+		return []*ifacestate.AutoConnectCandidate{
+			{Plug: foo.Plugs["camera"]},
+			{
+				Plug: foo.Plugs["network"],
+				Slot: core,
+				Auto: true,
+				Explanation: &policy.ConnectionExplanation{
+					Kind:        "auto-connection",
+					Declaration: "base-declaration",
+					Side:        "slot",
+					Constraint:  "allow-auto-connection",
+					Alternative: -1,
+				},
+			},
+		}, nil
+	})
+	defer restore()
+
+	req, err := http.NewRequest("POST", "/v2/debug", strings.NewReader(`{"action": "autoconnect-preview", "params": {"snap": "foo"}}`))
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(calls, check.Equals, 1)
+
+	data, err := json.Marshal(rsp.Result)
+	c.Assert(err, check.IsNil)
+	var result map[string]interface{}
+	c.Assert(json.Unmarshal(data, &result), check.IsNil)
+	c.Check(result, check.DeepEquals, map[string]interface{}{
+		"snap":    "foo",
+		"snap-id": "ididid",
+		"candidates": []interface{}{
+			map[string]interface{}{
+				"plug": map[string]interface{}{"snap": "foo", "plug": "camera"},
+				"auto": false,
+			},
+			map[string]interface{}{
+				"plug": map[string]interface{}{"snap": "foo", "plug": "network"},
+				"slot": map[string]interface{}{"snap": "core", "slot": "network"},
+				"auto": true,
+				"policy": map[string]interface{}{
+					"allowed":     true,
+					"kind":        "auto-connection",
+					"declaration": "base-declaration",
+					"side":        "slot",
+					"constraint":  "allow-auto-connection",
+					"unasserted":  []interface{}{"foo", "core"},
+				},
+			},
+		},
+	})
+}
+
+func (s *postDebugSuite) TestPostDebugAutoConnectPreviewSnapYaml(c *check.C) {
+	d := s.daemon(c)
+	s.expectRootAccess()
+	// the assertions of the snap are in the database
+	info := s.mkInstalledInState(c, d, "foo", "bar", "v1", snap.R(10), true, "")
+	digest, _, err := asserts.SnapFileSHA3_384(info.MountFile())
+	c.Assert(err, check.IsNil)
+
+	var infos []*snap.Info
+	restore := daemon.MockIfacemgrPreviewAutoConnections(func(m *ifacestate.InterfaceManager, info *snap.Info) ([]*ifacestate.AutoConnectCandidate, error) {
+		infos = append(infos, info)
+		return nil, nil
+	})
+	defer restore()
+
+	for _, params := range []map[string]interface{}{
+		{"snap-yaml": "name: foo\nversion: 2\n", "snap-sha3-384": digest, "snap-size": 999},
+		// unknown snap files are unasserted
+		{"snap-yaml": "name: foo\nversion: 2\n", "snap-sha3-384": digest, "snap-size": 42},
+		{"snap-yaml": "name: foo\nversion: 2\n"},
+	} {
+		body, err := json.Marshal(map[string]interface{}{"action": "autoconnect-preview", "params": params})
+		c.Assert(err, check.IsNil)
+		req, err := http.NewRequest("POST", "/v2/debug", bytes.NewReader(body))
+		c.Assert(err, check.IsNil)
+		if params["snap-size"] == 42 {
+			rspe := s.errorReq(c, req, nil)
+			c.Check(rspe.Status, check.Equals, 400)
+			c.Check(rspe.Message, check.Equals, `cannot use the assertions of snap "foo": snap file does not have the expected size according to signatures: 42 != 999`)
+			continue
+		}
+		rsp := s.syncReq(c, req, nil)
+		c.Check(rsp.Result.(*daemon.AutoConnectPreview).Candidates, check.HasLen, 0)
+	}
+	c.Assert(infos, check.HasLen, 2)
+	c.Check(infos[0].SnapID, check.Equals, "foo-id")
+	c.Check(infos[0].Revision, check.Equals, snap.R(10))
+	c.Check(infos[0].Version, check.Equals, "2")
+	c.Check(infos[1].SnapID, check.Equals, "")
+	c.Check(infos[1].Revision, check.Equals, snap.R(-1))
+}
+
+func (s *postDebugSuite) TestPostDebugAutoConnectPreviewErrors(c *check.C) {
+	s.daemon(c)
+	s.expectRootAccess()
+
+	restore := daemon.MockIfacemgrPreviewAutoConnections(func(m *ifacestate.InterfaceManager, info *snap.Info) ([]*ifacestate.AutoConnectCandidate, error) {
+		return nil, errors.New("boom")
+	})
+	defer restore()
+
+	for _, t := range []struct {
+		params  string
+		status  int
+		message string
+	}{
+		{`{}`, 400, "a snap must be given"},
+		{`{"snap": "foo"}`, 400, `snap "foo" is not installed`},
+		{`{"snap-yaml": "name: foo\nversion: 1\n"}`, 500, `cannot preview auto-connections of snap "foo": boom`},
+	} {
+		req, err := http.NewRequest("POST", "/v2/debug", strings.NewReader(`{"action": "autoconnect-preview", "params": `+t.params+`}`))
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, t.status, check.Commentf(t.params))
+		c.Check(rspe.Message, check.Equals, t.message, check.Commentf(t.params))
+	}
+}
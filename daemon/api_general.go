@@ -61,11 +61,17 @@ var (
 	stateChangeCmd = &Command{
 		Path:        "/v2/changes/{id}",
 		GET:         getChange,
-		POST:        abortChange,
+		POST:        postChange,
 		ReadAccess:  interfaceOpenAccess{Interfaces: []string{"snap-refresh-observe"}},
 		WriteAccess: authenticatedAccess{Polkit: polkitActionManage},
 	}
 
+	stateChangeGraphCmd = &Command{
+		Path:       "/v2/changes/{id}/graph",
+		GET:        getChangeGraph,
+		ReadAccess: interfaceOpenAccess{Interfaces: []string{"snap-refresh-observe"}},
+	}
+
 	stateChangesCmd = &Command{
 		Path:       "/v2/changes",
 		GET:        getChanges,
@@ -116,6 +122,10 @@ func sysInfo(c *Command, r *http.Request, user *auth.UserState) Response {
 	if err != nil {
 		return InternalError("cannot get refresh schedule: %s", err)
 	}
+	snapRefreshSchedules, err := snapMgr.SnapRefreshSchedules()
+	if err != nil {
+		return InternalError("cannot get snap refresh schedules: %s", err)
+	}
 	users, err := auth.Users(st)
 	if err != nil && !errors.Is(err, state.ErrNoState) {
 		return InternalError("cannot get user auth data: %s", err)
@@ -131,6 +141,16 @@ func sysInfo(c *Command, r *http.Request, user *auth.UserState) Response {
 	} else {
 		refreshInfo.Schedule = refreshScheduleStr
 	}
+	if len(snapRefreshSchedules) > 0 {
+		refreshInfo.Snaps = make(map[string]*client.SnapRefreshInfo, len(snapRefreshSchedules))
+		for name, sched := range snapRefreshSchedules {
+			refreshInfo.Snaps[name] = &client.SnapRefreshInfo{
+				Timer: sched.Timer,
+				Last:  formatRefreshTime(sched.Last),
+				Next:  formatRefreshTime(sched.Next),
+			}
+		}
+	}
 
 	m := map[string]interface{}{
 		"series":         release.Series,
@@ -217,6 +237,19 @@ func getChange(c *Command, r *http.Request, user *auth.UserState) Response {
 	return SyncResponse(change2changeInfo(chg))
 }
 
+func getChangeGraph(c *Command, r *http.Request, user *auth.UserState) Response {
+	chID := muxVars(r)["id"]
+	state := c.d.overlord.State()
+	state.Lock()
+	defer state.Unlock()
+	chg := state.Change(chID)
+	if chg == nil {
+		return NotFound("cannot find change with id %q", chID)
+	}
+
+	return SyncResponse(change2changeGraph(chg))
+}
+
 func getChanges(c *Command, r *http.Request, user *auth.UserState) Response {
 	query := r.URL.Query()
 	qselect := query.Get("select")
@@ -276,7 +309,7 @@ func getChanges(c *Command, r *http.Request, user *auth.UserState) Response {
 	return SyncResponse(chgInfos)
 }
 
-func abortChange(c *Command, r *http.Request, user *auth.UserState) Response {
+func postChange(c *Command, r *http.Request, user *auth.UserState) Response {
 	chID := muxVars(r)["id"]
 	state := c.d.overlord.State()
 	state.Lock()
@@ -295,18 +328,31 @@ func abortChange(c *Command, r *http.Request, user *auth.UserState) Response {
 		return BadRequest("cannot decode data from request body: %v", err)
 	}
 
-	if reqData.Action != "abort" {
+	switch reqData.Action {
+	case "abort", "pause", "resume":
+	default:
 		return BadRequest("change action %q is unsupported", reqData.Action)
 	}
 
 	if chg.IsReady() {
-		return BadRequest("cannot abort change %s with nothing pending", chID)
+		return BadRequest("cannot %s change %s with nothing pending", reqData.Action, chID)
+	}
+
+	switch reqData.Action {
+	case "abort":
+		// flag the change
+		chg.Abort()
+	case "pause":
+		// running tasks are let finish but no new ones are started
+		chg.Pause()
+	case "resume":
+		if !chg.IsPaused() {
+			return BadRequest("cannot resume change %s which is not paused", chID)
+		}
+		chg.Resume()
 	}
 
-	// flag the change
-	chg.Abort()
-
-	// actually ask to proceed with the abort
+	// actually ask to proceed with the action
 	ensureStateSoon(state)
 
 	return SyncResponse(change2changeInfo(chg))
@@ -319,6 +365,7 @@ type changeInfo struct {
 	Status  string      `json:"status"`
 	Tasks   []*taskInfo `json:"tasks,omitempty"`
 	Ready   bool        `json:"ready"`
+	Paused  bool        `json:"paused,omitempty"`
 	Err     string      `json:"err,omitempty"`
 
 	SpawnTime time.Time  `json:"spawn-time,omitempty"`
@@ -355,6 +402,7 @@ func change2changeInfo(chg *state.Change) *changeInfo {
 		Summary: chg.Summary(),
 		Status:  status.String(),
 		Ready:   status.Ready(),
+		Paused:  chg.IsPaused() && !status.Ready(),
 
 		SpawnTime: chg.SpawnTime(),
 	}
@@ -403,6 +451,107 @@ func change2changeInfo(chg *state.Change) *changeInfo {
 	return chgInfo
 }
 
+type changeGraph struct {
+	ID      string           `json:"id"`
+	Kind    string           `json:"kind"`
+	Summary string           `json:"summary"`
+	Status  string           `json:"status"`
+	Tasks   []*taskGraphNode `json:"tasks"`
+	Edges   []*taskGraphEdge `json:"edges,omitempty"`
+}
+
+type taskGraphNode struct {
+	ID      string     `json:"id"`
+	Kind    string     `json:"kind"`
+	Summary string     `json:"summary"`
+	Status  string     `json:"status"`
+	Lanes   []int      `json:"lanes,omitempty"`
+	AtTime  *time.Time `json:"at-time,omitempty"`
+	// Change is set only for tasks outside of the graphed change which
+	// tasks of the change depend on.
+	Change string `json:"change,omitempty"`
+}
+
+type taskGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Kind is "wait" when To can only run once From is done, or "undo"
+	// when To can only be undone once From is no longer in progress.
+	Kind string `json:"kind"`
+	// Blocking is set when the edge is currently keeping To from being
+	// run by the task runner.
+	Blocking bool `json:"blocking,omitempty"`
+}
+
+func task2taskGraphNode(t *state.Task) *taskGraphNode {
+	node := &taskGraphNode{
+		ID:      t.ID(),
+		Kind:    t.Kind(),
+		Summary: t.Summary(),
+		Status:  t.Status().String(),
+		Lanes:   t.Lanes(),
+	}
+	if atTime := t.AtTime(); !atTime.IsZero() {
+		node.AtTime = &atTime
+	}
+	return node
+}
+
+// change2changeGraph returns the tasks of the change together with the
+// dependencies between them, mirroring the conditions the task runner
+// uses to decide whether a task can run.
+func change2changeGraph(chg *state.Change) *changeGraph {
+	graph := &changeGraph{
+		ID:      chg.ID(),
+		Kind:    chg.Kind(),
+		Summary: chg.Summary(),
+		Status:  chg.Status().String(),
+	}
+
+	tasks := chg.Tasks()
+	graph.Tasks = make([]*taskGraphNode, 0, len(tasks))
+	for _, t := range tasks {
+		graph.Tasks = append(graph.Tasks, task2taskGraphNode(t))
+	}
+
+	external := make(map[string]bool)
+	addExternal := func(t *state.Task) {
+		if t.Change() == chg || external[t.ID()] {
+			return
+		}
+		external[t.ID()] = true
+		node := task2taskGraphNode(t)
+		if other := t.Change(); other != nil {
+			node.Change = other.ID()
+		}
+		graph.Tasks = append(graph.Tasks, node)
+	}
+
+	for _, t := range tasks {
+		status := t.Status()
+		for _, wt := range t.WaitTasks() {
+			addExternal(wt)
+			graph.Edges = append(graph.Edges, &taskGraphEdge{
+				From:     wt.ID(),
+				To:       t.ID(),
+				Kind:     "wait",
+				Blocking: status == state.DoStatus && wt.Status() != state.DoneStatus,
+			})
+		}
+		for _, ht := range t.HaltTasks() {
+			addExternal(ht)
+			graph.Edges = append(graph.Edges, &taskGraphEdge{
+				From:     ht.ID(),
+				To:       t.ID(),
+				Kind:     "undo",
+				Blocking: status == state.UndoStatus && !ht.Status().Ready(),
+			})
+		}
+	}
+
+	return graph
+}
+
 var snapstateSnapsAffectedByTask = snapstate.SnapsAffectedByTask
 
 // taskApiData returns a map similar to change data which is currently
@@ -33,6 +33,7 @@ import (
 
 	"github.com/snapcore/snapd/arch"
 	"github.com/snapcore/snapd/boot"
+	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/daemon"
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/features"
@@ -397,6 +398,35 @@ func (s *generalSuite) TestSysInfoIsManaged(c *check.C) {
 	c.Check(rsp.Result.(map[string]interface{})["managed"], check.Equals, true)
 }
 
+func (s *generalSuite) TestSysInfoSnapRefreshTimers(c *check.C) {
+	s.expectSystemInfoReadAccess()
+	d := s.daemon(c)
+	s.mockSnap(c, "name: kiosk\nversion: 1")
+
+	last := time.Date(2026, 10, 1, 2, 30, 0, 0, time.UTC)
+	st := d.Overlord().State()
+	st.Lock()
+	tr := config.NewTransaction(st)
+	tr.Set("core", "refresh.snap-timers.kiosk", "2:00-4:00")
+	// not installed
+	tr.Set("core", "refresh.snap-timers.agent", "0:00~24:00/96")
+	tr.Commit()
+	st.Set("last-snap-refresh", map[string]time.Time{"kiosk": last})
+	st.Unlock()
+
+	req, err := http.NewRequest("GET", "/v2/system-info", nil)
+	c.Assert(err, check.IsNil)
+
+	rsp := s.syncReq(c, req, nil)
+	refreshInfo := rsp.Result.(map[string]interface{})["refresh"].(client.RefreshInfo)
+	c.Check(refreshInfo.Snaps, check.DeepEquals, map[string]*client.SnapRefreshInfo{
+		"kiosk": {
+			Timer: "2:00-4:00",
+			Last:  "2026-10-01T02:30:00Z",
+		},
+	})
+}
+
 func (s *generalSuite) TestSysInfoWorksDegraded(c *check.C) {
 	s.expectSystemInfoReadAccess()
 	d := s.daemon(c)
@@ -686,6 +716,74 @@ func (s *generalSuite) TestStateChange(c *check.C) {
 	})
 }
 
+func (s *generalSuite) TestStateChangeGraph(c *check.C) {
+	// Setup
+	s.expectChangesReadAccess()
+	d := s.daemon(c)
+	st := d.Overlord().State()
+	st.Lock()
+	chg := st.NewChange("install", "install...")
+	t1 := st.NewTask("download", "1...")
+	t2 := st.NewTask("mount", "2...")
+	t3 := st.NewTask("link", "3...")
+	t2.WaitFor(t1)
+	t3.WaitFor(t2)
+	lane := st.NewLane()
+	t1.JoinLane(lane)
+	t2.JoinLane(lane)
+	chg.AddAll(state.NewTaskSet(t1, t2, t3))
+	t1.SetStatus(state.DoneStatus)
+	// a task from another change
+	other := st.NewChange("remove", "remove...")
+	t4 := st.NewTask("unlink", "4...")
+	other.AddTask(t4)
+	t3.WaitFor(t4)
+	st.Unlock()
+
+	// Execute
+	req, err := http.NewRequest("GET", "/v2/changes/"+chg.ID()+"/graph", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	rec := httptest.NewRecorder()
+	rsp.ServeHTTP(rec, req)
+
+	// Verify
+	c.Check(rec.Code, check.Equals, 200)
+	var body map[string]interface{}
+	err = json.Unmarshal(rec.Body.Bytes(), &body)
+	c.Check(err, check.IsNil)
+	c.Check(body["result"], check.DeepEquals, map[string]interface{}{
+		"id":      chg.ID(),
+		"kind":    "install",
+		"summary": "install...",
+		"status":  "Do",
+		"tasks": []interface{}{
+			map[string]interface{}{"id": t1.ID(), "kind": "download", "summary": "1...", "status": "Done", "lanes": []interface{}{1.}},
+			map[string]interface{}{"id": t2.ID(), "kind": "mount", "summary": "2...", "status": "Do", "lanes": []interface{}{1.}},
+			map[string]interface{}{"id": t3.ID(), "kind": "link", "summary": "3...", "status": "Do", "lanes": []interface{}{0.}},
+			map[string]interface{}{"id": t4.ID(), "kind": "unlink", "summary": "4...", "status": "Do", "lanes": []interface{}{0.}, "change": other.ID()},
+		},
+		"edges": []interface{}{
+			map[string]interface{}{"from": t2.ID(), "to": t1.ID(), "kind": "undo"},
+			map[string]interface{}{"from": t1.ID(), "to": t2.ID(), "kind": "wait"},
+			map[string]interface{}{"from": t3.ID(), "to": t2.ID(), "kind": "undo"},
+			map[string]interface{}{"from": t2.ID(), "to": t3.ID(), "kind": "wait", "blocking": true},
+			map[string]interface{}{"from": t4.ID(), "to": t3.ID(), "kind": "wait", "blocking": true},
+		},
+	})
+}
+
+func (s *generalSuite) TestStateChangeGraphNotFound(c *check.C) {
+	s.expectChangesReadAccess()
+	s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/changes/42/graph", nil)
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 404)
+	c.Check(rspe.Message, check.Equals, `cannot find change with id "42"`)
+}
+
 func (s *generalSuite) expectManageAccess() {
 	s.expectWriteAccess(daemon.AuthenticatedAccess{Polkit: "io.snapcraft.snapd.manage"})
 }
@@ -798,6 +896,78 @@ func (s *generalSuite) TestStateChangeAbortIsReady(c *check.C) {
 	})
 }
 
+func (s *generalSuite) TestStateChangePauseResume(c *check.C) {
+	soon := 0
+	_, restore := daemon.MockEnsureStateSoon(func(st *state.State) {
+		soon++
+	})
+	defer restore()
+
+	// Setup
+	s.expectChangesReadAccess()
+	d := s.daemon(c)
+	st := d.Overlord().State()
+	st.Lock()
+	ids := setupChanges(st)
+	st.Unlock()
+
+	s.expectManageAccess()
+
+	// Pause
+	buf := bytes.NewBufferString(`{"action": "pause"}`)
+	req, err := http.NewRequest("POST", "/v2/changes/"+ids[0], buf)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Status, check.Equals, 200)
+	c.Assert(rsp.Result, check.FitsTypeOf, &daemon.ChangeInfo{})
+	c.Check(rsp.Result.(*daemon.ChangeInfo).Paused, check.Equals, true)
+	c.Check(soon, check.Equals, 1)
+
+	st.Lock()
+	c.Check(st.Change(ids[0]).IsPaused(), check.Equals, true)
+	st.Unlock()
+
+	// Resume
+	buf = bytes.NewBufferString(`{"action": "resume"}`)
+	req, err = http.NewRequest("POST", "/v2/changes/"+ids[0], buf)
+	c.Assert(err, check.IsNil)
+	rsp = s.syncReq(c, req, nil)
+	c.Check(rsp.Status, check.Equals, 200)
+	c.Check(rsp.Result.(*daemon.ChangeInfo).Paused, check.Equals, false)
+	c.Check(soon, check.Equals, 2)
+
+	st.Lock()
+	c.Check(st.Change(ids[0]).IsPaused(), check.Equals, false)
+	st.Unlock()
+
+	// Resuming a change which is not paused fails
+	buf = bytes.NewBufferString(`{"action": "resume"}`)
+	req, err = http.NewRequest("POST", "/v2/changes/"+ids[0], buf)
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, fmt.Sprintf("cannot resume change %s which is not paused", ids[0]))
+}
+
+func (s *generalSuite) TestStateChangePauseIsReady(c *check.C) {
+	s.expectChangesReadAccess()
+	d := s.daemon(c)
+	st := d.Overlord().State()
+	st.Lock()
+	ids := setupChanges(st)
+	st.Change(ids[0]).SetStatus(state.DoneStatus)
+	st.Unlock()
+
+	s.expectManageAccess()
+
+	buf := bytes.NewBufferString(`{"action": "pause"}`)
+	req, err := http.NewRequest("POST", "/v2/changes/"+ids[0], buf)
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, fmt.Sprintf("cannot pause change %s with nothing pending", ids[0]))
+}
+
 func (s *generalSuite) testWarnings(c *check.C, all bool, body io.Reader) (calls string, result interface{}) {
 	s.daemon(c)
 
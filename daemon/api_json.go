@@ -64,6 +64,15 @@ type interfaceAction struct {
 	Slots  []slotJSON `json:"slots,omitempty"`
 }
 
+// connectionAction is an action performed on a single connection.
+type connectionAction struct {
+	Action    string                 `json:"action"`
+	Plug      interfaces.PlugRef     `json:"plug"`
+	Slot      interfaces.SlotRef     `json:"slot"`
+	PlugAttrs map[string]interface{} `json:"plug-attrs,omitempty"`
+	SlotAttrs map[string]interface{} `json:"slot-attrs,omitempty"`
+}
+
 // connectionsJSON aids in marshalling information about a single connection
 // into JSON
 type connectionJSON struct {
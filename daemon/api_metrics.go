@@ -0,0 +1,97 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/snapcore/snapd/metrics"
+	"github.com/snapcore/snapd/overlord/auth"
+)
+
+var metricsCmd = &Command{
+	Path:       "/v2/metrics",
+	GET:        getMetrics,
+	ReadAccess: rootAccess{},
+}
+
+var (
+	apiRequests = metrics.MustRegister(metrics.NewCounterVec(
+		"snapd_api_requests",
+		"Requests served by the API, by method, endpoint and status code.",
+		"method", "path", "code"))
+
+	apiRequestDuration = metrics.MustRegister(metrics.NewHistogramVec(
+		"snapd_api_request_duration_seconds",
+		"Time spent serving API requests, by method and endpoint.",
+		nil, "method", "path"))
+)
+
+// observeAPIRequest records a request served by the given command.
+func observeAPIRequest(c *Command, r *http.Request, status int, d time.Duration) {
+	path := c.Path
+	if path == "" {
+		path = c.PathPrefix
+	}
+	if status == 0 {
+		// nothing called WriteHeader explicitly
+		status = http.StatusOK
+	}
+	apiRequests.With(r.Method, path, strconv.Itoa(status)).Inc()
+	apiRequestDuration.With(r.Method, path).Observe(d.Seconds())
+}
+
+func getMetrics(c *Command, r *http.Request, user *auth.UserState) Response {
+	info := metrics.NewGaugeVec("snapd_info", "Information about the running snapd.", "version")
+	info.With(c.d.Version).Set(1)
+	changes := metrics.NewGaugeVec("snapd_changes", "Changes in the state, by kind and status.", "kind", "status")
+	tasks := metrics.NewGaugeVec("snapd_tasks", "Tasks in the state, by kind and status.", "kind", "status")
+
+	st := c.d.overlord.State()
+	st.Lock()
+	for _, chg := range st.Changes() {
+		changes.With(chg.Kind(), chg.Status().String()).Add(1)
+	}
+	for _, t := range st.Tasks() {
+		tasks.With(t.Kind(), t.Status().String()).Add(1)
+	}
+	st.Unlock()
+
+	collectors := append(metrics.DefaultRegistry.Collectors(), info, changes, tasks)
+	return metricsResponse(collectors)
+}
+
+// metricsResponse is a Response which writes the given metrics in the
+// OpenMetrics text format.
+type metricsResponse []metrics.Collector
+
+func (m metricsResponse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := metrics.Write(&buf, m); err != nil {
+		InternalError("cannot write metrics: %v", err).ServeHTTP(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", metrics.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,83 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/metrics"
+)
+
+var _ = check.Suite(&metricsSuite{})
+
+type metricsSuite struct {
+	apiBaseSuite
+}
+
+func (s *metricsSuite) TestGetMetrics(c *check.C) {
+	d := s.daemon(c)
+
+	st := d.Overlord().State()
+	st.Lock()
+	chg := st.NewChange("install-snap", "...")
+	chg.AddTask(st.NewTask("download-snap", "..."))
+	chg.AddTask(st.NewTask("download-snap", "..."))
+	chg.AddTask(st.NewTask("mount-snap", "..."))
+	st.Unlock()
+
+	req, err := http.NewRequest("GET", "/v2/metrics", nil)
+	c.Assert(err, check.IsNil)
+	s.asRootAuth(req)
+
+	rec := httptest.NewRecorder()
+	s.serveHTTP(c, rec, req)
+	c.Assert(rec.Code, check.Equals, 200)
+	c.Check(rec.Header().Get("Content-Type"), check.Equals, metrics.ContentType)
+
+	body := rec.Body.String()
+	c.Check(body, check.Matches, `(?s).*\nsnapd_info{version=".*"} 1\n.*`)
+	c.Check(body, check.Matches, `(?s).*\nsnapd_changes{kind="install-snap",status="Do"} 1\n.*`)
+	c.Check(body, check.Matches, `(?s).*\nsnapd_tasks{kind="download-snap",status="Do"} 2\n.*`)
+	c.Check(body, check.Matches, `(?s).*\nsnapd_tasks{kind="mount-snap",status="Do"} 1\n.*`)
+	c.Check(body, check.Matches, `(?s).*# TYPE snapd_state_checkpoint_duration_seconds histogram\n.*`)
+	c.Check(strings.HasSuffix(body, "# EOF\n"), check.Equals, true)
+
+	// the request itself is accounted for in the next scrape
+	rec = httptest.NewRecorder()
+	s.serveHTTP(c, rec, req)
+	c.Assert(rec.Code, check.Equals, 200)
+	c.Check(rec.Body.String(), check.Matches, `(?s).*\nsnapd_api_requests_total{method="GET",path="/v2/metrics",code="200"} [1-9][0-9]*\n.*`)
+}
+
+func (s *metricsSuite) TestGetMetricsNotRoot(c *check.C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/metrics", nil)
+	c.Assert(err, check.IsNil)
+	s.asUserAuth(c, req)
+
+	rec := httptest.NewRecorder()
+	s.serveHTTP(c, rec, req)
+	c.Check(rec.Code, check.Equals, 403)
+}
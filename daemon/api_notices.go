@@ -34,6 +34,7 @@ var noticeReadInterfaces = map[state.NoticeType][]string{
 	state.ChangeUpdateNotice:                 {"snap-refresh-observe"},
 	state.RefreshInhibitNotice:               {"snap-refresh-observe"},
 	state.SnapRunInhibitNotice:               {"snap-refresh-observe"},
+	state.SnapAutoRollbackNotice:             {"snap-refresh-observe"},
 	state.InterfacesRequestsPromptNotice:     {"snap-interfaces-requests-control"},
 	state.InterfacesRequestsRuleUpdateNotice: {"snap-interfaces-requests-control"},
 }
@@ -124,6 +125,11 @@ func getNotices(c *Command, r *http.Request, user *auth.UserState) Response {
 		return BadRequest("invalid timeout: %v", err)
 	}
 
+	// Keep track of prompting clients so prompts are not left outstanding
+	// when no client is around to reply to them.
+	done := registerPromptClient(c, userID, types)
+	defer done()
+
 	st := c.d.overlord.State()
 	st.Lock()
 	defer st.Unlock()
@@ -155,6 +161,37 @@ func getNotices(c *Command, r *http.Request, user *auth.UserState) Response {
 	return SyncResponse(notices)
 }
 
+// registerPromptClient records that a prompting client for the given user is
+// listening for prompt notices, if the given notice types include them or are
+// not filtered, and returns a function which must be called once the client
+// stops listening.
+func registerPromptClient(c *Command, userID *uint32, types []state.NoticeType) (done func()) {
+	noop := func() {}
+	if userID == nil {
+		return noop
+	}
+	// no types filter means all types of notices, prompt ones included
+	promptNoticesRequested := len(types) == 0
+	for _, t := range types {
+		if t == state.InterfacesRequestsPromptNotice {
+			promptNoticesRequested = true
+			break
+		}
+	}
+	if !promptNoticesRequested {
+		return noop
+	}
+	ifaceMgr := getInterfaceManager(c)
+	if ifaceMgr == nil || !ifaceMgr.AppArmorPromptingRunning() {
+		return noop
+	}
+	promptingMgr := ifaceMgr.InterfacesRequestsManager()
+	if promptingMgr == nil {
+		return noop
+	}
+	return promptingMgr.RegisterPromptClient(*userID)
+}
+
 // Get the UID of the request. If the UID is not known, return an error.
 func uidFromRequest(r *http.Request) (uint32, error) {
 	cred, err := ucrednetGet(r.RemoteAddr)
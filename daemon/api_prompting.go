@@ -66,6 +66,14 @@ var (
 		ReadAccess:  interfaceOpenAccess{Interfaces: []string{"snap-interfaces-requests-control"}},
 		WriteAccess: interfaceAuthenticatedAccess{Interfaces: []string{"snap-interfaces-requests-control"}, Polkit: polkitActionManage},
 	}
+
+	requestsPolicyCmd = &Command{
+		Path:        "/v2/interfaces/requests/policy",
+		GET:         getPolicy,
+		POST:        postPolicy,
+		ReadAccess:  interfaceOpenAccess{Interfaces: []string{"snap-interfaces-requests-control"}},
+		WriteAccess: interfaceAuthenticatedAccess{Interfaces: []string{"snap-interfaces-requests-control"}, Polkit: polkitActionManage},
+	}
 )
 
 // getUserID returns the UID specified by the user-id parameter of the query,
@@ -279,7 +287,12 @@ type interfaceManager interface {
 }
 
 var getInterfaceManager = func(c *Command) interfaceManager {
-	return c.d.overlord.InterfaceManager()
+	mgr := c.d.overlord.InterfaceManager()
+	if mgr == nil {
+		// avoid returning a non-nil interface holding a nil pointer
+		return nil
+	}
+	return mgr
 }
 
 type postPromptBody struct {
@@ -321,6 +334,16 @@ type postRuleRequestBody struct {
 	PatchRule *patchRuleContents `json:"rule,omitempty"`
 }
 
+type postPolicyRequestBody struct {
+	Action string               `json:"action"`
+	Policy *requestrules.Policy `json:"policy,omitempty"`
+}
+
+type postPolicyResult struct {
+	Added   []*requestrules.Rule `json:"added"`
+	Removed []*requestrules.Rule `json:"removed"`
+}
+
 func getPrompts(c *Command, r *http.Request, user *auth.UserState) Response {
 	userID, errorResp := getUserID(r)
 	if errorResp != nil {
@@ -418,6 +441,10 @@ func getRules(c *Command, r *http.Request, user *auth.UserState) Response {
 	snap := query.Get("snap")
 	iface := query.Get("interface")
 
+	if len(query["explain"]) > 0 {
+		return explainRules(c, userID, snap, iface, query.Get("explain"), query.Get("permission"))
+	}
+
 	rules, err := getInterfaceManager(c).InterfacesRequestsManager().Rules(userID, snap, iface)
 	if err != nil {
 		// Should be impossible, Rules() always returns nil error
@@ -431,6 +458,21 @@ func getRules(c *Command, r *http.Request, user *auth.UserState) Response {
 	return SyncResponse(rules)
 }
 
+// explainRules returns which of the rules of the given user, snap, and
+// interface apply to a request for the given path and permission.
+func explainRules(c *Command, userID uint32, snap, iface, path, permission string) Response {
+	if snap == "" || iface == "" || path == "" || permission == "" {
+		return BadRequest(`"explain" requires a path along with the "snap", "interface" and "permission" parameters`)
+	}
+
+	explanation, err := getInterfaceManager(c).InterfacesRequestsManager().ExplainRules(userID, snap, iface, path, permission)
+	if err != nil {
+		return promptingError(err)
+	}
+
+	return SyncResponse(explanation)
+}
+
 func postRules(c *Command, r *http.Request, user *auth.UserState) Response {
 	userID, errorResp := getUserID(r)
 	if errorResp != nil {
@@ -544,3 +586,66 @@ func postRule(c *Command, r *http.Request, user *auth.UserState) Response {
 		return BadRequest(`action must be "add" or "remove"`)
 	}
 }
+
+func getPolicy(c *Command, r *http.Request, user *auth.UserState) Response {
+	userID, errorResp := getUserID(r)
+	if errorResp != nil {
+		return errorResp
+	}
+
+	if !getInterfaceManager(c).AppArmorPromptingRunning() {
+		return promptingNotRunningError()
+	}
+
+	policy, err := getInterfaceManager(c).InterfacesRequestsManager().ExportRules(userID)
+	if err != nil {
+		return promptingError(err)
+	}
+
+	return SyncResponse(policy)
+}
+
+func postPolicy(c *Command, r *http.Request, user *auth.UserState) Response {
+	userID, errorResp := getUserID(r)
+	if errorResp != nil {
+		return errorResp
+	}
+
+	if !getInterfaceManager(c).AppArmorPromptingRunning() {
+		return promptingNotRunningError()
+	}
+
+	var postBody postPolicyRequestBody
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&postBody); err != nil {
+		return promptingError(fmt.Errorf("cannot decode request body for policy endpoint: %w", err))
+	}
+
+	var replace bool
+	switch postBody.Action {
+	case "merge":
+	case "replace":
+		replace = true
+	default:
+		return BadRequest(`"action" field must be "merge" or "replace"`)
+	}
+	if postBody.Policy == nil {
+		return BadRequest(`must include "policy" field in request body`)
+	}
+
+	added, removed, err := getInterfaceManager(c).InterfacesRequestsManager().ImportRules(userID, postBody.Policy, replace)
+	if err != nil {
+		return promptingError(err)
+	}
+	if len(added) == 0 {
+		added = []*requestrules.Rule{}
+	}
+	if len(removed) == 0 {
+		removed = []*requestrules.Rule{}
+	}
+
+	return SyncResponse(&postPolicyResult{
+		Added:   added,
+		Removed: removed,
+	})
+}
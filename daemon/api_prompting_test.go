@@ -31,6 +31,7 @@ import (
 
 	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/interfaces/prompting"
 	prompting_errors "github.com/snapcore/snapd/interfaces/prompting/errors"
 	"github.com/snapcore/snapd/interfaces/prompting/patterns"
@@ -49,6 +50,9 @@ type fakeInterfacesRequestsManager struct {
 	prompt       *requestprompts.Prompt
 	rule         *requestrules.Rule
 	satisfiedIDs []prompting.IDType
+	explanation  *requestrules.Explanation
+	policy       *requestrules.Policy
+	removed      []*requestrules.Rule
 	err          error
 
 	// Store most recent received values
@@ -56,10 +60,17 @@ type fakeInterfacesRequestsManager struct {
 	snap        string
 	iface       string
 	id          prompting.IDType // used for prompt ID or rule ID
+	path        string
+	permission  string
 	constraints *prompting.Constraints
 	outcome     prompting.OutcomeType
 	lifespan    prompting.LifespanType
 	duration    string
+	replace     bool
+
+	// Record prompting clients
+	registeredClients []uint32
+	doneClients       int
 }
 
 func (m *fakeInterfacesRequestsManager) Prompts(userID uint32) ([]*requestprompts.Prompt, error) {
@@ -90,6 +101,15 @@ func (m *fakeInterfacesRequestsManager) Rules(userID uint32, snap string, iface
 	return m.rules, m.err
 }
 
+func (m *fakeInterfacesRequestsManager) ExplainRules(userID uint32, snap string, iface string, path string, permission string) (*requestrules.Explanation, error) {
+	m.userID = userID
+	m.snap = snap
+	m.iface = iface
+	m.path = path
+	m.permission = permission
+	return m.explanation, m.err
+}
+
 func (m *fakeInterfacesRequestsManager) AddRule(userID uint32, snap string, iface string, constraints *prompting.Constraints, outcome prompting.OutcomeType, lifespan prompting.LifespanType, duration string) (*requestrules.Rule, error) {
 	m.userID = userID
 	m.snap = snap
@@ -130,6 +150,25 @@ func (m *fakeInterfacesRequestsManager) RemoveRule(userID uint32, ruleID prompti
 	return m.rule, m.err
 }
 
+func (m *fakeInterfacesRequestsManager) ExportRules(userID uint32) (*requestrules.Policy, error) {
+	m.userID = userID
+	return m.policy, m.err
+}
+
+func (m *fakeInterfacesRequestsManager) ImportRules(userID uint32, policy *requestrules.Policy, replace bool) ([]*requestrules.Rule, []*requestrules.Rule, error) {
+	m.userID = userID
+	m.policy = policy
+	m.replace = replace
+	return m.rules, m.removed, m.err
+}
+
+func (m *fakeInterfacesRequestsManager) RegisterPromptClient(userID uint32) (done func()) {
+	m.registeredClients = append(m.registeredClients, userID)
+	return func() {
+		m.doneClients++
+	}
+}
+
 type promptingSuite struct {
 	apiBaseSuite
 
@@ -671,6 +710,45 @@ func (s *promptingSuite) makeSyncReq(c *C, method string, path string, uid uint3
 	return rsp
 }
 
+func (s *promptingSuite) TestGetNoticesRegistersPromptClient(c *C) {
+	s.daemon(c)
+
+	noticesAccess := daemon.InterfaceOpenAccess{Interfaces: []string{"snap-refresh-observe", "snap-interfaces-requests-control"}}
+
+	for _, testCase := range []struct {
+		query    string
+		uid      uint32
+		running  bool
+		expected []uint32
+	}{
+		{"types=interfaces-requests-prompt", 1000, true, []uint32{1000}},
+		{"types=change-update,interfaces-requests-prompt&timeout=1ms", 1000, true, []uint32{1000}},
+		{"types=interfaces-requests-prompt&user-id=1001", 0, true, []uint32{1001}},
+		// No types filter includes prompt notices
+		{"", 1000, true, []uint32{1000}},
+		{"timeout=1ms", 1000, true, []uint32{1000}},
+		// Not a prompting client
+		{"types=change-update", 1000, true, nil},
+		{"types=interfaces-requests-prompt&users=all", 0, true, nil},
+		// Prompting is not running
+		{"types=interfaces-requests-prompt", 1000, false, nil},
+	} {
+		s.manager.registeredClients = nil
+		s.manager.doneClients = 0
+		s.appArmorPromptingRunning = testCase.running
+		s.expectReadAccess(noticesAccess)
+
+		req, err := http.NewRequest("GET", "/v2/notices?"+testCase.query, nil)
+		c.Assert(err, IsNil)
+		req.RemoteAddr = fmt.Sprintf("pid=100;uid=%d;socket=%s;", testCase.uid, dirs.SnapdSocket)
+		rsp := s.syncReq(c, req, nil)
+		c.Check(rsp.Status, Equals, 200)
+
+		c.Check(s.manager.registeredClients, DeepEquals, testCase.expected, Commentf("query: %s", testCase.query))
+		c.Check(s.manager.doneClients, Equals, len(testCase.expected), Commentf("query: %s", testCase.query))
+	}
+}
+
 func (s *promptingSuite) TestGetPromptHappy(c *C) {
 	s.daemon(c)
 
@@ -799,6 +877,71 @@ func (s *promptingSuite) TestGetRulesHappy(c *C) {
 	}
 }
 
+func (s *promptingSuite) TestGetRulesExplain(c *C) {
+	s.daemon(c)
+
+	rule := &requestrules.Rule{
+		ID:        prompting.IDType(0xabcd),
+		Timestamp: time.Now(),
+		User:      1234,
+		Snap:      "firefox",
+		Interface: "home",
+		Constraints: &prompting.Constraints{
+			PathPattern: mustParsePathPattern(c, "/home/test/**"),
+			Permissions: []string{"write"},
+		},
+		Outcome:  prompting.OutcomeDeny,
+		Lifespan: prompting.LifespanForever,
+	}
+	s.manager.explanation = &requestrules.Explanation{
+		Snap:       "firefox",
+		Interface:  "home",
+		Path:       "/home/test/foo",
+		Permission: "write",
+		Outcome:    prompting.OutcomeDeny,
+		Matches: []*requestrules.ExplanationMatch{{
+			Variant: "/home/test/**",
+			Outcome: prompting.OutcomeDeny,
+			Rules:   []*requestrules.ExplainedRule{{Rule: rule}},
+		}},
+	}
+
+	rsp := s.makeSyncReq(c, "GET", "/v2/interfaces/requests/rules?snap=firefox&interface=home&explain=/home/test/foo&permission=write", 1234, nil)
+
+	c.Check(s.manager.userID, Equals, uint32(1234))
+	c.Check(s.manager.snap, Equals, "firefox")
+	c.Check(s.manager.iface, Equals, "home")
+	c.Check(s.manager.path, Equals, "/home/test/foo")
+	c.Check(s.manager.permission, Equals, "write")
+	c.Check(rsp.Result, Equals, s.manager.explanation)
+}
+
+func (s *promptingSuite) TestGetRulesExplainErrors(c *C) {
+	s.daemon(c)
+
+	for _, query := range []string{
+		"explain=/home/test/foo&interface=home&permission=write",
+		"explain=/home/test/foo&snap=firefox&permission=write",
+		"explain=/home/test/foo&snap=firefox&interface=home",
+		"explain=&snap=firefox&interface=home&permission=write",
+	} {
+		req, err := http.NewRequest("GET", "/v2/interfaces/requests/rules?"+query, nil)
+		c.Assert(err, IsNil)
+		req.RemoteAddr = "pid=100;uid=1234;socket=;"
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, Equals, 400, Commentf(query))
+		c.Check(rspe.Message, Equals, `"explain" requires a path along with the "snap", "interface" and "permission" parameters`)
+	}
+
+	s.manager.err = prompting_errors.NewInvalidPermissionsError("home", []string{"access"}, []string{"read", "write", "execute"})
+	req, err := http.NewRequest("GET", "/v2/interfaces/requests/rules?explain=/home/test/foo&snap=firefox&interface=home&permission=access", nil)
+	c.Assert(err, IsNil)
+	req.RemoteAddr = "pid=100;uid=1234;socket=;"
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 400)
+	c.Check(rspe.Kind, Equals, client.ErrorKindInterfacesRequestsInvalidFields)
+}
+
 func (s *promptingSuite) TestPostRulesAddHappy(c *C) {
 	s.expectWriteAccess(daemon.InterfaceAuthenticatedAccess{Interfaces: []string{"snap-interfaces-requests-control"}, Polkit: "io.snapcraft.snapd.manage"})
 
@@ -1059,3 +1202,130 @@ func (s *promptingSuite) TestPostRuleRemoveHappy(c *C) {
 	c.Check(ok, Equals, true)
 	c.Check(rule, DeepEquals, s.manager.rule)
 }
+
+func (s *promptingSuite) TestGetPolicyHappy(c *C) {
+	s.daemon(c)
+
+	s.manager.policy = &requestrules.Policy{
+		Version: requestrules.PolicyVersion,
+		Rules: []*requestrules.PolicyRule{
+			{
+				Snap:        "libreoffice",
+				Interface:   "home",
+				PathPattern: "~/Documents/**",
+				Permissions: []string{"read"},
+				Outcome:     prompting.OutcomeAllow,
+				Lifespan:    prompting.LifespanForever,
+			},
+		},
+	}
+
+	rsp := s.makeSyncReq(c, "GET", "/v2/interfaces/requests/policy?user-id=1000", 0, nil)
+
+	c.Check(s.manager.userID, Equals, uint32(1000))
+	c.Check(rsp.Result, Equals, s.manager.policy)
+}
+
+func (s *promptingSuite) TestPostPolicyHappy(c *C) {
+	s.expectWriteAccess(daemon.InterfaceAuthenticatedAccess{Interfaces: []string{"snap-interfaces-requests-control"}, Polkit: "io.snapcraft.snapd.manage"})
+
+	s.daemon(c)
+
+	policy := &requestrules.Policy{
+		Version: requestrules.PolicyVersion,
+		Rules: []*requestrules.PolicyRule{
+			{
+				Snap:        "libreoffice",
+				Interface:   "home",
+				PathPattern: "~/Documents/**",
+				Permissions: []string{"read"},
+				Outcome:     prompting.OutcomeAllow,
+			},
+		},
+	}
+
+	for _, testCase := range []struct {
+		action  string
+		replace bool
+	}{
+		{"merge", false},
+		{"replace", true},
+	} {
+		s.manager = &fakeInterfacesRequestsManager{}
+		if testCase.replace {
+			s.manager.removed = []*requestrules.Rule{{ID: prompting.IDType(1)}}
+		}
+		s.manager.rules = []*requestrules.Rule{{ID: prompting.IDType(2)}}
+
+		marshalled, err := json.Marshal(&daemon.PostPolicyRequestBody{
+			Action: testCase.action,
+			Policy: policy,
+		})
+		c.Assert(err, IsNil)
+
+		rsp := s.makeSyncReq(c, "POST", "/v2/interfaces/requests/policy", 1234, marshalled)
+
+		c.Check(s.manager.userID, Equals, uint32(1234))
+		c.Check(s.manager.policy, DeepEquals, policy)
+		c.Check(s.manager.replace, Equals, testCase.replace)
+
+		result, ok := rsp.Result.(*daemon.PostPolicyResult)
+		c.Assert(ok, Equals, true, Commentf("%T", rsp.Result))
+		c.Check(result.Added, DeepEquals, s.manager.rules)
+		if testCase.replace {
+			c.Check(result.Removed, DeepEquals, s.manager.removed)
+		} else {
+			c.Check(result.Removed, DeepEquals, []*requestrules.Rule{})
+		}
+	}
+}
+
+func (s *promptingSuite) TestPostPolicyErrors(c *C) {
+	s.expectWriteAccess(daemon.InterfaceAuthenticatedAccess{Interfaces: []string{"snap-interfaces-requests-control"}, Polkit: "io.snapcraft.snapd.manage"})
+
+	s.daemon(c)
+
+	for _, testCase := range []struct {
+		body   string
+		status int
+		errStr string
+	}{
+		{
+			`{"action": "foo", "policy": {"version": 1, "rules": []}}`,
+			400,
+			`"action" field must be "merge" or "replace"`,
+		},
+		{
+			`{"action": "merge"}`,
+			400,
+			`must include "policy" field in request body`,
+		},
+		{
+			`{"action": "merge", "policy": {"version": 1, "rules": [{"outcome": "foo"}]}}`,
+			400,
+			`cannot decode request body for policy endpoint: invalid outcome: "foo"`,
+		},
+	} {
+		req, err := http.NewRequest("POST", "/v2/interfaces/requests/policy", bytes.NewBufferString(testCase.body))
+		c.Assert(err, IsNil)
+		req.RemoteAddr = "pid=100;uid=1234;socket=;"
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, Equals, testCase.status, Commentf(testCase.body))
+		c.Check(rspe.Message, Equals, testCase.errStr)
+	}
+
+	// Errors from the manager are mapped to prompting API errors
+	s.manager.err = &prompting_errors.RuleConflictError{
+		Conflicts: []prompting_errors.RuleConflict{{
+			Permission:    "read",
+			Variant:       "/home/test/Documents/**",
+			ConflictingID: "0000000000000001",
+		}},
+	}
+	req, err := http.NewRequest("POST", "/v2/interfaces/requests/policy", bytes.NewBufferString(`{"action": "merge", "policy": {"version": 1, "rules": []}}`))
+	c.Assert(err, IsNil)
+	req.RemoteAddr = "pid=100;uid=1234;socket=;"
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, Equals, 409)
+	c.Check(rspe.Kind, Equals, client.ErrorKindInterfacesRequestsRuleConflict)
+}
@@ -193,6 +193,10 @@ func sideloadOrTrySnap(ctx context.Context, c *Command, body io.ReadCloser, boun
 	st.Lock()
 	defer st.Unlock()
 
+	if errRsp := addSideloadAssertions(st, form.FileRefs["assertion"]); errRsp != nil {
+		return errRsp
+	}
+
 	var chg *state.Change
 	if len(snapFiles) > 1 {
 		chg, errRsp = sideloadManySnaps(ctx, st, snapFiles, sideloadFlags, user)
@@ -217,6 +221,36 @@ func sideloadOrTrySnap(ctx context.Context, c *Command, body io.ReadCloser, boun
 	return AsyncResponse(nil, chg.ID())
 }
 
+// addSideloadAssertions adds the assertions uploaded together with the snaps
+// to the system assertion database, so that the snaps can be installed as
+// asserted revisions. The assertions are kept even if the snaps cannot be
+// installed afterwards, as with snap ack.
+func addSideloadAssertions(st *state.State, refs []*FileReference) *apiError {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	batch := asserts.NewBatch(nil)
+	for _, ref := range refs {
+		f, err := os.Open(ref.TmpPath)
+		if err != nil {
+			return InternalError("cannot open assertions %q: %v", ref.Filename, err)
+		}
+		_, err = batch.AddStream(f)
+		f.Close()
+		if err != nil {
+			return BadRequest("cannot decode assertions %q: %v", ref.Filename, err)
+		}
+	}
+
+	if err := assertstate.AddBatch(st, batch, &asserts.CommitOptions{
+		Precheck: true,
+	}); err != nil {
+		return BadRequest("cannot add assertions: %v", err)
+	}
+	return nil
+}
+
 // sideloadedInfo contains information from a bunch of sideloaded snaps
 type sideloadedInfo struct {
 	// snaps contains the set of snaps that should be sideloaded. Any components
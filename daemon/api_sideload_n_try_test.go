@@ -39,6 +39,7 @@ import (
 	"github.com/snapcore/snapd/daemon"
 	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/assertstate"
 	"github.com/snapcore/snapd/overlord/assertstate/assertstatetest"
 	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/snapstate/sequence"
@@ -1433,6 +1434,99 @@ func (s *sideloadSuite) TestSideloadManySnapsAsserted(c *check.C) {
 
 }
 
+func (s *sideloadSuite) TestSideloadManySnapsWithAssertions(c *check.C) {
+	d := s.daemonWithOverlordMockAndStore()
+	s.markSeeded(d)
+	st := d.Overlord().State()
+	snaps := []string{"one", "two"}
+	snapData, assertions := s.signedSnaps(c, snaps)
+
+	expectedFlags := snapstate.Flags{RemoveSnapPath: true, Transaction: client.TransactionAllSnaps, Lane: 1}
+
+	restore := daemon.MockSnapstateUpdateWithGoal(func(ctx context.Context, st *state.State, g snapstate.UpdateGoal, filter func(*snap.Info, *snapstate.SnapState) bool, opts snapstate.Options) ([]string, *snapstate.UpdateTaskSets, error) {
+		goal := g.(*pathUpdateGoalRecorder)
+
+		c.Check(opts.Flags, check.DeepEquals, expectedFlags)
+
+		var tss []*state.TaskSet
+		var names []string
+		for i, sn := range goal.snaps {
+			c.Check(*sn.SideInfo, check.DeepEquals, snap.SideInfo{
+				RealName: snaps[i],
+				SnapID:   snaps[i] + "-id",
+				Revision: snap.R(41),
+			})
+
+			ts := state.NewTaskSet(st.NewTask("fake-install-snap", fmt.Sprintf("Doing a fake install of %q", sn.SideInfo.RealName)))
+			tss = append(tss, ts)
+			names = append(names, sn.InstanceName)
+		}
+
+		return names, &snapstate.UpdateTaskSets{Refresh: tss}, nil
+	})
+	defer restore()
+
+	bodyBuf := bytes.NewBufferString("----hello--\r\n")
+	bodyBuf.WriteString("Content-Disposition: form-data; name=\"transaction\"\r\n\r\nall-snaps\r\n----hello--\r\n")
+	bodyBuf.WriteString("Content-Disposition: form-data; name=\"assertion\"; filename=\"all.assert\"\r\n\r\n")
+	for _, a := range assertions {
+		bodyBuf.Write(asserts.Encode(a))
+		bodyBuf.WriteString("\n")
+	}
+	bodyBuf.WriteString("\r\n----hello--\r\n")
+	fileSnaps := make([]string, len(snaps))
+	for i, snap := range snaps {
+		fileSnaps[i] = "file-" + snap
+		bodyBuf.WriteString("Content-Disposition: form-data; name=\"snap\"; filename=\"" + fileSnaps[i] + "\"\r\n\r\n")
+		bodyBuf.Write(snapData[i])
+		bodyBuf.WriteString("\r\n----hello--\r\n")
+	}
+
+	req, err := http.NewRequest("POST", "/v2/snaps", bodyBuf)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "multipart/thing; boundary=--hello--")
+	rsp := s.asyncReq(c, req, nil)
+
+	c.Check(rsp.Status, check.Equals, 202)
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	c.Assert(chg, check.NotNil)
+	c.Check(chg.Summary(), check.Equals, fmt.Sprintf(`Install snaps %s from files %s`, strutil.Quoted(snaps), strutil.Quoted(fileSnaps)))
+
+	// the assertions were added to the database
+	_, err = assertstate.SnapDeclaration(st, "one-id")
+	c.Check(err, check.IsNil)
+
+	// only the snap files passed into the change remain
+	matches, err := filepath.Glob(filepath.Join(dirs.SnapBlobDir, dirs.LocalInstallBlobTempPrefix+"*"))
+	c.Assert(err, check.IsNil)
+	c.Check(matches, check.HasLen, len(snaps))
+}
+
+func (s *sideloadSuite) TestSideloadSnapInvalidAssertions(c *check.C) {
+	d := s.daemonWithOverlordMockAndStore()
+	s.markSeeded(d)
+
+	body := "----hello--\r\n" +
+		"Content-Disposition: form-data; name=\"assertion\"; filename=\"bad.assert\"\r\n" +
+		"\r\n" +
+		"not an assertion\r\n" +
+		"----hello--\r\n" +
+		"Content-Disposition: form-data; name=\"snap\"; filename=\"x\"\r\n" +
+		"\r\n" +
+		"xyzzy\r\n" +
+		"----hello--\r\n"
+
+	req, err := http.NewRequest("POST", "/v2/snaps", bytes.NewBufferString(body))
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "multipart/thing; boundary=--hello--")
+	rsp := s.errorReq(c, req, nil)
+
+	c.Check(rsp.Status, check.Equals, 400)
+	c.Check(rsp.Message, check.Matches, `cannot decode assertions "bad.assert": .*`)
+}
+
 func (s *sideloadSuite) TestSideloadManySnapsOneNotAsserted(c *check.C) {
 	d := s.daemonWithOverlordMockAndStore()
 	s.markSeeded(d)
@@ -1465,6 +1559,17 @@ version: 1`, nil)
 }
 
 func (s *sideloadSuite) mockAssertions(c *check.C, st *state.State, snaps []string) (snapData [][]byte) {
+	snapData, assertions := s.signedSnaps(c, snaps)
+
+	st.Lock()
+	assertstatetest.AddMany(st, assertions...)
+	st.Unlock()
+
+	return snapData
+}
+
+func (s *sideloadSuite) signedSnaps(c *check.C, snaps []string) (snapData [][]byte, assertions []asserts.Assertion) {
+	assertions = append(assertions, s.StoreSigning.StoreAccountKey(""))
 	for _, snap := range snaps {
 		thisSnap := snaptest.MakeTestSnapWithFiles(c, fmt.Sprintf(`name: %s
 version: 1`, snap), nil)
@@ -1493,12 +1598,10 @@ version: 1`, snap), nil)
 		}, nil, "")
 		c.Assert(err, check.IsNil)
 
-		st.Lock()
-		assertstatetest.AddMany(st, s.StoreSigning.StoreAccountKey(""), dev1Acct, snapDecl, snapRev)
-		st.Unlock()
+		assertions = append(assertions, dev1Acct, snapDecl, snapRev)
 	}
 
-	return snapData
+	return snapData, assertions
 }
 
 type trySuite struct {
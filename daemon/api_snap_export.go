@@ -0,0 +1,183 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/assertstate"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+var snapExportCmd = &Command{
+	Path:       "/v2/snaps/{name}/export",
+	GET:        getSnapExport,
+	ReadAccess: authenticatedAccess{Polkit: polkitActionManage},
+}
+
+func getSnapExport(c *Command, r *http.Request, user *auth.UserState) Response {
+	name := muxVars(r)["name"]
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(st, name, &snapst); err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return SnapNotFound(name, err)
+		}
+		return InternalError("cannot export snap %q: %v", name, err)
+	}
+	if snapst.TryMode {
+		return BadRequest("cannot export try-mode snap %q", name)
+	}
+
+	rev := snapst.Current
+	if s := r.URL.Query().Get("revision"); s != "" {
+		var err error
+		rev, err = snap.ParseRevision(s)
+		if err != nil {
+			return BadRequest("invalid revision %q: %v", s, err)
+		}
+	}
+	idx := snapst.LastIndex(rev)
+	if idx < 0 {
+		return BadRequest("revision %s of snap %q is not installed", rev, name)
+	}
+	si := snapst.Sequence.Revisions[idx].Snap
+
+	info, err := snap.ReadInfo(name, si)
+	if err != nil {
+		return InternalError("cannot export snap %q: %v", name, err)
+	}
+
+	var comps []*snap.ComponentSideInfo
+	paths := []string{info.MountFile()}
+	for _, cs := range snapst.Sequence.ComponentsForRevision(rev) {
+		comps = append(comps, cs.SideInfo)
+		cpi := snap.MinimalComponentContainerPlaceInfo(cs.SideInfo.Component.ComponentName, cs.SideInfo.Revision, name)
+		paths = append(paths, cpi.MountFile())
+	}
+
+	assertions, err := assertstate.SnapAssertions(st, si, info.Provenance(), comps)
+	if err != nil {
+		return BadRequest("cannot export snap %q: %v", name, err)
+	}
+
+	rsp := &snapExportResponse{
+		name:       fmt.Sprintf("%s_%s", name, rev),
+		assertions: assertions,
+	}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			rsp.close()
+			return InternalError("cannot export snap %q: %v", name, err)
+		}
+		rsp.files = append(rsp.files, f)
+	}
+	return rsp
+}
+
+// snapExportResponse streams a tar archive with the blobs of a snap revision
+// and of its components, together with the assertions needed to install
+// them.
+type snapExportResponse struct {
+	// name is the base name of the archive and of the assertions file in it
+	name       string
+	assertions []asserts.Assertion
+	files      []*os.File
+}
+
+func (s *snapExportResponse) close() {
+	for _, f := range s.files {
+		f.Close()
+	}
+}
+
+// ServeHTTP from the Response interface
+func (s *snapExportResponse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer s.close()
+
+	w.Header().Set("Content-Type", client.SnapExportMediaType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.tar", s.name))
+	if err := s.writeTo(w); err != nil {
+		logger.Noticef("cannot export snap: %v", err)
+	}
+}
+
+func (s *snapExportResponse) writeTo(w io.Writer) error {
+	var buf bytes.Buffer
+	enc := asserts.NewEncoder(&buf)
+	for _, a := range s.assertions {
+		if err := enc.Encode(a); err != nil {
+			return err
+		}
+	}
+
+	tw := tar.NewWriter(w)
+	// the assertions come first, so that they can be acknowledged
+	// before the blobs are looked at
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    s.name + ".assert",
+		Mode:    0644,
+		Size:    int64(buf.Len()),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	for _, f := range s.files {
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    filepath.Base(f.Name()),
+			Mode:    0644,
+			Size:    fi.Size(),
+			ModTime: fi.ModTime(),
+		}); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, f); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
@@ -0,0 +1,205 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/asserts"
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/assertstate/assertstatetest"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/sequence"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/naming"
+)
+
+var _ = check.Suite(&snapExportSuite{})
+
+type snapExportSuite struct {
+	apiBaseSuite
+}
+
+func (s *snapExportSuite) SetUpTest(c *check.C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	// blobs of private or paid snaps must not be handed out to anyone
+	s.expectReadAccess(daemon.AuthenticatedAccess{Polkit: "io.snapcraft.snapd.manage"})
+}
+
+func (s *snapExportSuite) export(c *check.C, url string) (header http.Header, files map[string][]byte) {
+	req, err := http.NewRequest("GET", url, nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.req(c, req, nil)
+
+	rec := httptest.NewRecorder()
+	rsp.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, 200)
+
+	files = make(map[string][]byte)
+	tr := tar.NewReader(rec.Body)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, check.IsNil)
+		data, err := io.ReadAll(tr)
+		c.Assert(err, check.IsNil)
+		files[hdr.Name] = data
+	}
+	return rec.Header(), files
+}
+
+func decodeAssertionTypes(c *check.C, data []byte) []string {
+	var types []string
+	dec := asserts.NewDecoder(bytes.NewReader(data))
+	for {
+		a, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, check.IsNil)
+		types = append(types, a.Type().Name)
+	}
+	return types
+}
+
+func (s *snapExportSuite) TestExport(c *check.C) {
+	d := s.daemonWithOverlordMockAndStore()
+	info := s.mkInstalledInState(c, d, "foo", "bar", "v1", snap.R(5), true, "")
+	blob, err := os.ReadFile(info.MountFile())
+	c.Assert(err, check.IsNil)
+
+	header, files := s.export(c, "/v2/snaps/foo/export")
+	c.Check(header.Get("Content-Type"), check.Equals, client.SnapExportMediaType)
+	c.Check(header.Get("Content-Disposition"), check.Equals, "attachment; filename=foo_5.tar")
+
+	c.Assert(files, check.HasLen, 2)
+	c.Check(files["foo_5.snap"], check.DeepEquals, blob)
+	c.Check(decodeAssertionTypes(c, files["foo_5.assert"]), check.DeepEquals, []string{
+		"account-key", "account", "snap-declaration", "snap-revision",
+	})
+}
+
+func (s *snapExportSuite) TestExportRevisionWithComponents(c *check.C) {
+	d := s.daemonWithOverlordMockAndStore()
+	s.mkInstalledInState(c, d, "foo", "bar", "v1", snap.R(5), false, "")
+	s.mkInstalledInState(c, d, "foo", "bar", "v1", snap.R(6), true, "")
+
+	st := d.Overlord().State()
+	st.Lock()
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(st, "foo", &snapst), check.IsNil)
+	csi := snap.NewComponentSideInfo(naming.NewComponentRef("foo", "comp"), snap.R(2))
+	c.Assert(snapst.Sequence.AddComponentForRevision(snap.R(5), sequence.NewComponentState(csi, snap.StandardComponent)), check.IsNil)
+	snapstate.Set(st, "foo", &snapst)
+
+	resRev, err := s.StoreSigning.Sign(asserts.SnapResourceRevisionType, map[string]interface{}{
+		"snap-id":           "foo-id",
+		"resource-name":     "comp",
+		"resource-sha3-384": strings.Repeat("c", 64),
+		"resource-revision": "2",
+		"resource-size":     "100",
+		"developer-id":      "bar-id",
+		"timestamp":         time.Now().Format(time.RFC3339),
+	}, nil, "")
+	c.Assert(err, check.IsNil)
+	resPair, err := s.StoreSigning.Sign(asserts.SnapResourcePairType, map[string]interface{}{
+		"snap-id":           "foo-id",
+		"resource-name":     "comp",
+		"resource-revision": "2",
+		"snap-revision":     "5",
+		"developer-id":      "bar-id",
+		"timestamp":         time.Now().Format(time.RFC3339),
+	}, nil, "")
+	c.Assert(err, check.IsNil)
+	assertstatetest.AddMany(st, resRev, resPair)
+	st.Unlock()
+
+	compBlob := filepath.Join(dirs.SnapBlobDir, "foo+comp_2.comp")
+	c.Assert(os.WriteFile(compBlob, []byte("comp-data"), 0644), check.IsNil)
+
+	_, files := s.export(c, "/v2/snaps/foo/export?revision=5")
+	c.Assert(files, check.HasLen, 3)
+	c.Check(files["foo_5.snap"], check.NotNil)
+	c.Check(string(files["foo+comp_2.comp"]), check.Equals, "comp-data")
+	c.Check(decodeAssertionTypes(c, files["foo_5.assert"]), check.DeepEquals, []string{
+		"account-key", "account", "snap-declaration", "snap-revision",
+		"snap-resource-revision", "snap-resource-pair",
+	})
+}
+
+func (s *snapExportSuite) TestExportErrors(c *check.C) {
+	d := s.daemonWithOverlordMockAndStore()
+	s.mkInstalledInState(c, d, "foo", "bar", "v1", snap.R(5), true, "")
+	s.mkInstalledInState(c, d, "local", "", "v1", snap.R(-1), true, "")
+
+	for _, t := range []struct {
+		url    string
+		status int
+		err    string
+	}{
+		{"/v2/snaps/bar/export", 404, `no state entry for key`},
+		{"/v2/snaps/foo/export?revision=x", 400, `invalid revision "x": .*`},
+		{"/v2/snaps/foo/export?revision=7", 400, `revision 7 of snap "foo" is not installed`},
+		{"/v2/snaps/local/export", 400, `cannot export snap "local": cannot find assertions for unasserted snap "local"`},
+	} {
+		req, err := http.NewRequest("GET", t.url, nil)
+		c.Assert(err, check.IsNil)
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, t.status, check.Commentf(t.url))
+		c.Check(rspe.Message, check.Matches, t.err, check.Commentf(t.url))
+	}
+}
+
+func (s *snapExportSuite) TestExportNotAuthorized(c *check.C) {
+	d := s.daemonWithOverlordMockAndStore()
+	s.mkInstalledInState(c, d, "foo", "bar", "v1", snap.R(5), true, "")
+
+	polkitCalls := 0
+	restore := daemon.MockCheckPolkitAction(func(r *http.Request, ucred *daemon.Ucrednet, action string) *daemon.APIError {
+		polkitCalls++
+		c.Check(action, check.Equals, "io.snapcraft.snapd.manage")
+		return daemon.Forbidden("access denied")
+	})
+	defer restore()
+
+	req, err := http.NewRequest("GET", "/v2/snaps/foo/export", nil)
+	c.Assert(err, check.IsNil)
+	req.RemoteAddr = fmt.Sprintf("pid=100;uid=1000;socket=%s;", dirs.SnapdSocket)
+	rec := httptest.NewRecorder()
+	s.serveHTTP(c, rec, req)
+	c.Check(rec.Code, check.Equals, 403)
+	c.Check(polkitCalls, check.Equals, 1)
+}
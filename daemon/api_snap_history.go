@@ -0,0 +1,77 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+var (
+	snapHistoryCmd = &Command{
+		Path:       "/v2/snaps/{name}/history",
+		GET:        getSnapHistory,
+		ReadAccess: interfaceOpenAccess{Interfaces: []string{"snap-refresh-observe"}},
+	}
+)
+
+func formatHistoryRevision(rev snap.Revision) string {
+	if rev.Unset() {
+		return ""
+	}
+	return rev.String()
+}
+
+func getSnapHistory(c *Command, r *http.Request, user *auth.UserState) Response {
+	name := muxVars(r)["name"]
+
+	st := c.d.overlord.State()
+	st.Lock()
+	defer st.Unlock()
+
+	history, err := snapstate.SnapHistory(st, name)
+	if err != nil {
+		if errors.Is(err, state.ErrNoState) {
+			return SnapNotFound(name, err)
+		}
+		return InternalError("cannot get history of snap %q: %v", name, err)
+	}
+
+	result := make([]*client.SnapHistoryEntry, 0, len(history))
+	for _, e := range history {
+		result = append(result, &client.SnapHistoryEntry{
+			Action:       e.Action,
+			FromRevision: formatHistoryRevision(e.FromRevision),
+			ToRevision:   formatHistoryRevision(e.ToRevision),
+			Channel:      e.Channel,
+			Time:         e.Time,
+			Trigger:      e.Trigger,
+			Outcome:      e.Outcome,
+			ChangeID:     e.ChangeID,
+		})
+	}
+	return SyncResponse(result)
+}
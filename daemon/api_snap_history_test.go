@@ -0,0 +1,112 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon_test
+
+import (
+	"net/http"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/client"
+	"github.com/snapcore/snapd/daemon"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/snap"
+)
+
+var _ = check.Suite(&snapHistorySuite{})
+
+type snapHistorySuite struct {
+	apiBaseSuite
+}
+
+func (s *snapHistorySuite) SetUpTest(c *check.C) {
+	s.apiBaseSuite.SetUpTest(c)
+
+	s.expectReadAccess(daemon.InterfaceOpenAccess{Interfaces: []string{"snap-refresh-observe"}})
+}
+
+func (s *snapHistorySuite) TestGetSnapHistory(c *check.C) {
+	d := s.daemon(c)
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	st := d.Overlord().State()
+	st.Lock()
+	// the snap was removed, its history is kept
+	st.Set("snap-history", map[string][]*snapstate.HistoryEntry{
+		"foo": {{
+			Action:     snapstate.HistoryActionInstall,
+			ToRevision: snap.R(7),
+			Channel:    "latest/stable",
+			Time:       now,
+			Trigger:    snapstate.HistoryTriggerManual,
+			Outcome:    snapstate.HistoryOutcomeSucceeded,
+			ChangeID:   "1",
+		}, {
+			Action:       snapstate.HistoryActionRemove,
+			FromRevision: snap.R(7),
+			Time:         now.Add(time.Hour),
+			Trigger:      snapstate.HistoryTriggerManual,
+			Outcome:      snapstate.HistoryOutcomeSucceeded,
+			ChangeID:     "2",
+		}},
+	})
+	st.Unlock()
+
+	req, err := http.NewRequest("GET", "/v2/snaps/foo/history", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.DeepEquals, []*client.SnapHistoryEntry{{
+		Action:     "install",
+		ToRevision: "7",
+		Channel:    "latest/stable",
+		Time:       now,
+		Trigger:    "manual",
+		Outcome:    "succeeded",
+		ChangeID:   "1",
+	}, {
+		Action:       "remove",
+		FromRevision: "7",
+		Time:         now.Add(time.Hour),
+		Trigger:      "manual",
+		Outcome:      "succeeded",
+		ChangeID:     "2",
+	}})
+}
+
+func (s *snapHistorySuite) TestGetSnapHistoryInstalledWithoutHistory(c *check.C) {
+	s.daemon(c)
+	s.mockSnap(c, "name: foo\nversion: 1")
+
+	req, err := http.NewRequest("GET", "/v2/snaps/foo/history", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+	c.Check(rsp.Result, check.HasLen, 0)
+}
+
+func (s *snapHistorySuite) TestGetSnapHistoryNotFound(c *check.C) {
+	s.daemon(c)
+
+	req, err := http.NewRequest("GET", "/v2/snaps/foo/history", nil)
+	c.Assert(err, check.IsNil)
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 404)
+	c.Check(rspe.Kind, check.Equals, client.ErrorKindSnapNotFound)
+}
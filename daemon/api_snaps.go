@@ -156,6 +156,10 @@ func postSnap(c *Command, r *http.Request, user *auth.UserState) Response {
 		return inst.errToResponse(err)
 	}
 
+	if inst.DryRun {
+		return refreshPlanResponse(st, res)
+	}
+
 	chg := newChange(st, inst.Action+"-snap", res.Summary, res.Tasksets, res.Affected)
 	if len(res.Tasksets) == 0 {
 		chg.SetStatus(state.DoneStatus)
@@ -172,6 +176,10 @@ func postSnap(c *Command, r *http.Request, user *auth.UserState) Response {
 	if len(res.AffectedComponents) > 0 {
 		apiData["components"] = res.AffectedComponents
 	}
+	if at := inst.scheduledTime(); !at.IsZero() && len(res.Tasksets) > 0 {
+		scheduleChange(chg, at)
+		apiData["scheduled-time"] = at
+	}
 
 	chg.Set("api-data", apiData)
 
@@ -233,6 +241,8 @@ type snapInstruction struct {
 	QuotaGroupName         string                           `json:"quota-group"`
 	Time                   string                           `json:"time"`
 	HoldLevel              string                           `json:"hold-level"`
+	At                     string                           `json:"at"`
+	DryRun                 bool                             `json:"dry-run"`
 
 	// The fields below should not be unmarshalled into. Do not export them.
 	userID int
@@ -287,6 +297,17 @@ func (inst *snapInstruction) installFlags() (snapstate.Flags, error) {
 	return flags, nil
 }
 
+// scheduledTime returns the time the change for the instruction should
+// start at, or the zero time if it should start right away.
+func (inst *snapInstruction) scheduledTime() time.Time {
+	if inst.At == "" {
+		return time.Time{}
+	}
+	// validated already
+	at, _ := time.Parse(time.RFC3339, inst.At)
+	return at
+}
+
 func (inst *snapInstruction) holdLevel() snapstate.HoldLevel {
 	switch inst.HoldLevel {
 	case "auto-refresh":
@@ -392,6 +413,35 @@ func (inst *snapInstruction) validate() error {
 		}
 	}
 
+	if inst.DryRun {
+		if inst.Action != "refresh" {
+			return fmt.Errorf("dry-run can only be specified for refresh")
+		}
+		if inst.At != "" {
+			return fmt.Errorf("cannot specify both dry-run and at")
+		}
+		// enforcing validation sets cannot be planned without changing
+		// their tracking
+		if len(inst.ValidationSets) > 0 {
+			return fmt.Errorf("cannot specify both dry-run and validation-sets")
+		}
+	}
+
+	if inst.At != "" {
+		switch inst.Action {
+		case "install", "refresh", "remove", "revert":
+		default:
+			return fmt.Errorf(`at can only be specified for install, refresh, remove or revert`)
+		}
+		at, err := time.Parse(time.RFC3339, inst.At)
+		if err != nil {
+			return fmt.Errorf("at must be in RFC3339 format: %v", err)
+		}
+		if !at.After(time.Now()) {
+			return fmt.Errorf("cannot schedule %s in the past", inst.Action)
+		}
+	}
+
 	if inst.Unaliased && inst.Prefer {
 		return errUnaliasedPreferConflict
 	}
@@ -556,9 +606,12 @@ func snapUpdate(ctx context.Context, inst *snapInstruction, st *state.State) (*s
 		flags.Amend = true
 	}
 
-	// we need refreshed snap-declarations to enforce refresh-control as best as we can
-	if err = assertstateRefreshSnapAssertions(st, inst.userID, nil); err != nil {
-		return nil, err
+	// we need refreshed snap-declarations to enforce refresh-control as best
+	// as we can, but a dry-run must not change the assertion database
+	if !inst.DryRun {
+		if err = assertstateRefreshSnapAssertions(st, inst.userID, nil); err != nil {
+			return nil, err
+		}
 	}
 
 	// TODO: once we completely move away from the old snapstate API, this
@@ -822,6 +875,10 @@ func snapOpMany(c *Command, r *http.Request, user *auth.UserState) Response {
 		return inst.errToResponse(err)
 	}
 
+	if inst.DryRun {
+		return refreshPlanResponse(st, res)
+	}
+
 	chg := newChange(st, inst.Action+"-snap", res.Summary, res.Tasksets, res.Affected)
 	if len(res.Tasksets) == 0 {
 		chg.SetStatus(state.DoneStatus)
@@ -838,6 +895,10 @@ func snapOpMany(c *Command, r *http.Request, user *auth.UserState) Response {
 	if len(res.AffectedComponents) > 0 {
 		apiData["components"] = res.AffectedComponents
 	}
+	if at := inst.scheduledTime(); !at.IsZero() && len(res.Tasksets) > 0 {
+		scheduleChange(chg, at)
+		apiData["scheduled-time"] = at
+	}
 
 	chg.Set("api-data", apiData)
 
@@ -846,6 +907,80 @@ func snapOpMany(c *Command, r *http.Request, user *auth.UserState) Response {
 	return AsyncResponse(res.Result, chg.ID())
 }
 
+type refreshPlanItem struct {
+	Name            string        `json:"name"`
+	Type            snap.Type     `json:"type,omitempty"`
+	CurrentRevision snap.Revision `json:"current-revision"`
+	CurrentChannel  string        `json:"current-channel,omitempty"`
+	Revision        snap.Revision `json:"revision"`
+	Channel         string        `json:"channel,omitempty"`
+	Version         string        `json:"version,omitempty"`
+	DownloadSize    int64         `json:"download-size,omitempty"`
+	Components      []string      `json:"components,omitempty"`
+	Prerequisites   []string      `json:"prerequisites,omitempty"`
+	NeedsReboot     bool          `json:"needs-reboot,omitempty"`
+	ValidationSets  []string      `json:"validation-sets,omitempty"`
+	RequiredSpace   uint64        `json:"required-space,omitempty"`
+}
+
+type refreshPlan struct {
+	Refreshes []*refreshPlanItem `json:"refreshes"`
+	// Held maps snaps whose auto-refreshes are held to the holding snaps.
+	Held          map[string][]string `json:"held,omitempty"`
+	RequiredSpace uint64              `json:"required-space,omitempty"`
+}
+
+// refreshPlanResponse reports what the task sets of the given result would
+// do. Computing the plan throws them away, without ever creating a change for
+// them.
+func refreshPlanResponse(st *state.State, res *snapInstructionResult) Response {
+	plan, err := snapstateRefreshPlanFromTaskSets(st, res.Tasksets)
+	if err != nil {
+		return InternalError("cannot compute refresh plan: %v", err)
+	}
+
+	result := &refreshPlan{
+		Refreshes:     make([]*refreshPlanItem, 0, len(plan.Refreshes)),
+		Held:          plan.Held,
+		RequiredSpace: plan.RequiredSpace,
+	}
+	for _, it := range plan.Refreshes {
+		result.Refreshes = append(result.Refreshes, &refreshPlanItem{
+			Name:            it.InstanceName,
+			Type:            it.Type,
+			CurrentRevision: it.CurrentRevision,
+			CurrentChannel:  it.CurrentChannel,
+			Revision:        it.Revision,
+			Channel:         it.Channel,
+			Version:         it.Version,
+			DownloadSize:    it.DownloadSize,
+			Components:      it.Components,
+			Prerequisites:   it.Prerequisites,
+			NeedsReboot:     it.NeedsReboot,
+			ValidationSets:  it.ValidationSets,
+			RequiredSpace:   it.Space.Total(),
+		})
+	}
+	return SyncResponse(result)
+}
+
+// scheduleChange makes the change start no earlier than at, by scheduling
+// the tasks of the change which do not wait for other tasks in it.
+func scheduleChange(chg *state.Change, at time.Time) {
+	for _, t := range chg.Tasks() {
+		first := true
+		for _, wt := range t.WaitTasks() {
+			if wt.Change() == chg {
+				first = false
+				break
+			}
+		}
+		if first {
+			t.At(at)
+		}
+	}
+}
+
 type snapManyActionFunc func(context.Context, *snapInstruction, *state.State) (*snapInstructionResult, error)
 
 func (inst *snapInstruction) dispatchForMany() (op snapManyActionFunc) {
@@ -977,12 +1112,16 @@ func snapUpdateMany(ctx context.Context, inst *snapInstruction, st *state.State)
 	// we need refreshed snap-declarations to enforce refresh-control as best as
 	// we can, this also ensures that snap-declarations and their prerequisite
 	// assertions are updated regularly; update validation sets assertions only
-	// if refreshing all snaps (no snap names explicitly requested).
+	// if refreshing all snaps (no snap names explicitly requested). A dry-run
+	// must not change the assertion database though.
 	opts := &assertstate.RefreshAssertionsOptions{
 		IsRefreshOfAllSnaps: len(inst.Snaps) == 0,
 	}
-	if err := assertstateRefreshSnapAssertions(st, inst.userID, opts); err != nil {
-		return nil, err
+	refreshedAssertions := !inst.DryRun
+	if refreshedAssertions {
+		if err := assertstateRefreshSnapAssertions(st, inst.userID, opts); err != nil {
+			return nil, err
+		}
 	}
 
 	updates := make([]snapstate.StoreUpdate, 0, len(inst.Snaps))
@@ -1009,7 +1148,7 @@ func snapUpdateMany(ctx context.Context, inst *snapInstruction, st *state.State)
 		Flags: flags,
 	})
 	if err != nil {
-		if opts.IsRefreshOfAllSnaps {
+		if refreshedAssertions && opts.IsRefreshOfAllSnaps {
 			if err := assertstateRestoreValidationSetsTracking(st); err != nil && !errors.Is(err, state.ErrNoState) {
 				return nil, err
 			}
@@ -42,8 +42,10 @@ import (
 	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/daemon"
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/overlord/assertstate"
 	"github.com/snapcore/snapd/overlord/auth"
+	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/healthstate"
 	"github.com/snapcore/snapd/overlord/snapstate"
 	"github.com/snapcore/snapd/overlord/snapstate/sequence"
@@ -296,6 +298,68 @@ func (s *snapsSuite) TestSnapsInfoAll(c *check.C) {
 	}
 }
 
+func (s *snapsSuite) TestSnapsInfoAllRemovableFrom(c *check.C) {
+	s.expectSnapsReadAccess()
+	d := s.daemon(c)
+
+	s.mkInstalledInState(c, d, "local", "foo", "v1", snap.R(1), false, "")
+	s.mkInstalledInState(c, d, "local", "foo", "v2", snap.R(2), false, "")
+	s.mkInstalledInState(c, d, "local", "foo", "v3", snap.R(3), true, "")
+
+	st := d.Overlord().State()
+	st.Lock()
+	tr := config.NewTransaction(st)
+	c.Assert(tr.Set("core", "refresh.retain", 3), check.IsNil)
+	tr.Commit()
+	st.Unlock()
+
+	req, err := http.NewRequest("GET", "/v2/snaps?select=all", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+
+	snaps := snapList(rsp.Result)
+	c.Assert(snaps, check.HasLen, 3)
+	removable := map[string]bool{}
+	for _, sn := range snaps {
+		_, ok := sn["removable-from"]
+		removable[sn["revision"].(string)] = ok
+	}
+	// only the revision over refresh.retain, counting the one a refresh
+	// would add, is removable
+	c.Check(removable, check.DeepEquals, map[string]bool{
+		"1": true,
+		"2": false,
+		"3": false,
+	})
+}
+
+func (s *snapsSuite) TestSnapsInfoAllRemovableFromError(c *check.C) {
+	s.expectSnapsReadAccess()
+	d := s.daemon(c)
+
+	s.mkInstalledInState(c, d, "local", "foo", "v1", snap.R(1), false, "")
+	s.mkInstalledInState(c, d, "local", "foo", "v2", snap.R(2), true, "")
+
+	restore := daemon.MockSnapstateRevisionRemovalTimes(func(st *state.State) (map[string]map[snap.Revision]time.Time, error) {
+		return nil, errors.New("boom")
+	})
+	defer restore()
+	logbuf, restore := logger.MockLogger()
+	defer restore()
+
+	req, err := http.NewRequest("GET", "/v2/snaps?select=all", nil)
+	c.Assert(err, check.IsNil)
+	rsp := s.syncReq(c, req, nil)
+
+	// the revisions are listed, without their removal time
+	snaps := snapList(rsp.Result)
+	c.Assert(snaps, check.HasLen, 2)
+	for _, sn := range snaps {
+		c.Check(sn["removable-from"], check.IsNil)
+	}
+	c.Check(logbuf.String(), testutil.Contains, `cannot get removal times of snap revisions: boom`)
+}
+
 func (s *snapsSuite) TestSnapsInfoOnlyStore(c *check.C) {
 	s.expectSnapsReadAccess()
 	d := s.daemon(c)
@@ -629,6 +693,259 @@ func (s *snapsSuite) TestPostSnapsRemoveManyWithPurge(c *check.C) {
 	c.Check(chg.Summary(), check.Equals, `Remove snaps "foo", "bar"`)
 }
 
+func (s *snapsSuite) TestPostSnapScheduled(c *check.C) {
+	d := s.daemonWithOverlordMockAndStore()
+
+	defer daemon.MockSnapstateRemove(func(st *state.State, name string, revision snap.Revision, flags *snapstate.RemoveFlags) (*state.TaskSet, error) {
+		t1 := st.NewTask("fake-remove-1", "Remove one")
+		t2 := st.NewTask("fake-remove-2", "Remove two")
+		t2.WaitFor(t1)
+		return state.NewTaskSet(t1, t2), nil
+	})()
+
+	buf := strings.NewReader(`{"action": "remove", "at": "2999-01-02T03:04:05Z"}`)
+	req, err := http.NewRequest("POST", "/v2/snaps/foo", buf)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp := s.jsonReq(c, req, nil)
+	c.Check(rsp.Status, check.Equals, 202)
+
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	tasks := chg.Tasks()
+	c.Assert(tasks, check.HasLen, 2)
+	at := time.Date(2999, 1, 2, 3, 4, 5, 0, time.UTC)
+	// only the first task is scheduled
+	c.Check(tasks[0].AtTime().Equal(at), check.Equals, true)
+	c.Check(tasks[1].AtTime().IsZero(), check.Equals, true)
+
+	var apiData map[string]interface{}
+	c.Assert(chg.Get("api-data", &apiData), check.IsNil)
+	c.Check(apiData["scheduled-time"], check.Equals, "2999-01-02T03:04:05Z")
+}
+
+func (s *snapsSuite) TestPostSnapsScheduled(c *check.C) {
+	d := s.daemonWithOverlordMockAndStore()
+
+	defer daemon.MockSnapstateRemoveMany(func(s *state.State, names []string, opts *snapstate.RemoveFlags) ([]string, []*state.TaskSet, error) {
+		t1 := s.NewTask("fake-remove-1", "Remove one")
+		t2 := s.NewTask("fake-remove-2", "Remove two")
+		return names, []*state.TaskSet{state.NewTaskSet(t1), state.NewTaskSet(t2)}, nil
+	})()
+
+	buf := strings.NewReader(`{"action": "remove", "snaps":["foo", "bar"], "at": "2999-01-02T03:04:05Z"}`)
+	req, err := http.NewRequest("POST", "/v2/snaps", buf)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp := s.jsonReq(c, req, nil)
+	c.Check(rsp.Status, check.Equals, 202)
+
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	chg := st.Change(rsp.Change)
+	at := time.Date(2999, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, t := range chg.Tasks() {
+		c.Check(t.AtTime().Equal(at), check.Equals, true)
+	}
+}
+
+func (s *snapsSuite) TestPostSnapScheduledErrors(c *check.C) {
+	s.daemonWithOverlordMockAndStore()
+
+	for _, tc := range []struct {
+		body string
+		err  string
+	}{
+		{`{"action": "enable", "at": "2999-01-02T03:04:05Z"}`, `at can only be specified for install, refresh, remove or revert`},
+		{`{"action": "refresh", "at": "tomorrow"}`, `at must be in RFC3339 format: .*`},
+		{`{"action": "refresh", "at": "2000-01-02T03:04:05Z"}`, `cannot schedule refresh in the past`},
+	} {
+		req, err := http.NewRequest("POST", "/v2/snaps/foo", strings.NewReader(tc.body))
+		c.Assert(err, check.IsNil)
+		req.Header.Set("Content-Type", "application/json")
+
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, 400)
+		c.Check(rspe.Message, check.Matches, tc.err)
+	}
+}
+
+func (s *snapsSuite) TestPostSnapsRefreshDryRun(c *check.C) {
+	defer daemon.MockAssertstateRefreshSnapAssertions(func(*state.State, int, *assertstate.RefreshAssertionsOptions) error {
+		c.Error("a dry-run must not refresh assertions")
+		return nil
+	})()
+	defer daemon.MockSnapstateUpdateWithGoal(func(_ context.Context, st *state.State, g snapstate.UpdateGoal, filter func(*snap.Info, *snapstate.SnapState) bool, opts snapstate.Options) ([]string, *snapstate.UpdateTaskSets, error) {
+		t := st.NewTask("fake-refresh", "Refreshing foo")
+		return []string{"foo"}, &snapstate.UpdateTaskSets{Refresh: []*state.TaskSet{state.NewTaskSet(t)}}, nil
+	})()
+	defer daemon.MockSnapstateRefreshPlanFromTaskSets(func(st *state.State, tss []*state.TaskSet) (*snapstate.RefreshPlan, error) {
+		c.Assert(tss, check.HasLen, 1)
+		c.Check(tss[0].Tasks()[0].Kind(), check.Equals, "fake-refresh")
+		// computing the plan throws the tasks away
+		st.DiscardTasks(tss[0].Tasks()...)
+		return &snapstate.RefreshPlan{
+			Refreshes: []*snapstate.RefreshPlanItem{{
+				InstanceName:    "foo",
+				Type:            snap.TypeKernel,
+				CurrentRevision: snap.R(5),
+				CurrentChannel:  "latest/stable",
+				Revision:        snap.R(7),
+				Channel:         "latest/stable",
+				Version:         "2.0",
+				DownloadSize:    1024,
+				Components:      []string{"comp"},
+				Prerequisites:   []string{"content-provider"},
+				NeedsReboot:     true,
+				ValidationSets:  []string{"acme/base"},
+				Space:           snapstate.SnapSpaceEstimate{Snap: 2000, Components: 24},
+			}},
+			Held:          map[string][]string{"bar": {"system"}},
+			RequiredSpace: 2024,
+		}, nil
+	})()
+
+	d := s.daemonWithOverlordMockAndStore()
+
+	buf := strings.NewReader(`{"action": "refresh", "snaps": ["foo"], "dry-run": true}`)
+	req, err := http.NewRequest("POST", "/v2/snaps", buf)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp := s.syncReq(c, req, nil)
+	data, err := json.Marshal(rsp.Result)
+	c.Assert(err, check.IsNil)
+	var result map[string]interface{}
+	c.Assert(json.Unmarshal(data, &result), check.IsNil)
+	c.Check(result, check.DeepEquals, map[string]interface{}{
+		"refreshes": []interface{}{
+			map[string]interface{}{
+				"name":             "foo",
+				"type":             "kernel",
+				"current-revision": "5",
+				"current-channel":  "latest/stable",
+				"revision":         "7",
+				"channel":          "latest/stable",
+				"version":          "2.0",
+				"download-size":    1024.0,
+				"components":       []interface{}{"comp"},
+				"prerequisites":    []interface{}{"content-provider"},
+				"needs-reboot":     true,
+				"validation-sets":  []interface{}{"acme/base"},
+				"required-space":   2024.0,
+			},
+		},
+		"held": map[string]interface{}{
+			"bar": []interface{}{"system"},
+		},
+		"required-space": 2024.0,
+	})
+
+	// no change was created and the tasks were thrown away
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	c.Check(st.Changes(), check.HasLen, 0)
+	c.Check(st.TaskCount(), check.Equals, 0)
+}
+
+func (s *snapsSuite) TestPostSnapRefreshDryRun(c *check.C) {
+	defer daemon.MockAssertstateRefreshSnapAssertions(func(*state.State, int, *assertstate.RefreshAssertionsOptions) error {
+		c.Error("a dry-run must not refresh assertions")
+		return nil
+	})()
+	defer daemon.MockSnapstateUpdateOne(func(ctx context.Context, st *state.State, goal snapstate.UpdateGoal, filter func(*snap.Info, *snapstate.SnapState) bool, opts snapstate.Options) (*state.TaskSet, error) {
+		return state.NewTaskSet(st.NewTask("fake-refresh", "Refreshing foo")), nil
+	})()
+	defer daemon.MockSnapstateRefreshPlanFromTaskSets(func(st *state.State, tss []*state.TaskSet) (*snapstate.RefreshPlan, error) {
+		c.Assert(tss, check.HasLen, 1)
+		st.DiscardTasks(tss[0].Tasks()...)
+		return &snapstate.RefreshPlan{
+			Refreshes: []*snapstate.RefreshPlanItem{{
+				InstanceName:    "foo",
+				Type:            snap.TypeApp,
+				CurrentRevision: snap.R(5),
+				Revision:        snap.R(7),
+				Channel:         "latest/edge",
+			}},
+		}, nil
+	})()
+
+	d := s.daemonWithOverlordMockAndStore()
+
+	buf := strings.NewReader(`{"action": "refresh", "channel": "latest/edge", "dry-run": true}`)
+	req, err := http.NewRequest("POST", "/v2/snaps/foo", buf)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp := s.syncReq(c, req, nil)
+	data, err := json.Marshal(rsp.Result)
+	c.Assert(err, check.IsNil)
+	c.Check(string(data), check.Equals, `{"refreshes":[{"name":"foo","type":"app","current-revision":"5","revision":"7","channel":"latest/edge"}]}`)
+
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	c.Check(st.Changes(), check.HasLen, 0)
+	c.Check(st.TaskCount(), check.Equals, 0)
+}
+
+func (s *snapsSuite) TestPostSnapDryRunErrors(c *check.C) {
+	s.daemonWithOverlordMockAndStore()
+
+	for _, tc := range []struct {
+		body string
+		err  string
+	}{
+		{`{"action": "install", "dry-run": true}`, `dry-run can only be specified for refresh`},
+		{`{"action": "refresh", "dry-run": true, "at": "2999-01-02T03:04:05Z"}`, `cannot specify both dry-run and at`},
+	} {
+		req, err := http.NewRequest("POST", "/v2/snaps/foo", strings.NewReader(tc.body))
+		c.Assert(err, check.IsNil)
+		req.Header.Set("Content-Type", "application/json")
+
+		rspe := s.errorReq(c, req, nil)
+		c.Check(rspe.Status, check.Equals, 400)
+		c.Check(rspe.Message, check.Matches, tc.err)
+	}
+}
+
+func (s *snapsSuite) TestPostSnapsDryRunValidationSets(c *check.C) {
+	defer daemon.MockAssertstateRefreshSnapAssertions(func(*state.State, int, *assertstate.RefreshAssertionsOptions) error {
+		c.Error("a dry-run must not refresh assertions")
+		return nil
+	})()
+	defer daemon.MockAssertstateTryEnforceValidationSets(func(st *state.State, validationSets []string, userID int, snaps []*snapasserts.InstalledSnap, ignoreValidation map[string]bool) error {
+		c.Error("a dry-run must not enforce validation sets")
+		return nil
+	})()
+
+	d := s.daemonWithOverlordMockAndStore()
+
+	buf := strings.NewReader(`{"action": "refresh", "validation-sets": ["foo/bar=2"], "dry-run": true}`)
+	req, err := http.NewRequest("POST", "/v2/snaps", buf)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/json")
+
+	rspe := s.errorReq(c, req, nil)
+	c.Check(rspe.Status, check.Equals, 400)
+	c.Check(rspe.Message, check.Equals, "cannot specify both dry-run and validation-sets")
+
+	// validation-set tracking is left untouched
+	st := d.Overlord().State()
+	st.Lock()
+	defer st.Unlock()
+	tracking, err := assertstate.ValidationSets(st)
+	c.Assert(err, check.IsNil)
+	c.Check(tracking, check.HasLen, 0)
+	c.Check(st.Changes(), check.HasLen, 0)
+}
+
 func (s *snapsSuite) TestPostSnapsOptionsClean(c *check.C) {
 	var snapshotSaveCalled int
 	defer daemon.MockSnapshotSave(func(s *state.State, snaps, users []string,
@@ -0,0 +1,217 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/auth"
+)
+
+var (
+	// auditLogMaxSize is the size after which the audit log is rotated.
+	auditLogMaxSize int64 = 10 * 1024 * 1024
+	// auditLogKeep is the number of rotated audit logs which are kept.
+	auditLogKeep = 4
+)
+
+// auditEntry records a single state-changing request made to the API.
+type auditEntry struct {
+	Time time.Time `json:"time"`
+	// UID and PID are those of the peer, if known.
+	UID *uint32 `json:"uid,omitempty"`
+	PID int32   `json:"pid,omitempty"`
+	// Snap is the name of the snap the peer is running as, if any.
+	Snap string `json:"snap,omitempty"`
+	// User is the snapd user the request was authenticated as, if any.
+	User     string `json:"user,omitempty"`
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	// Change is the ID of the change resulting from the request, if any.
+	Change     string `json:"change,omitempty"`
+	StatusCode int    `json:"status-code"`
+	Error      string `json:"error,omitempty"`
+}
+
+// auditFilter selects audit entries.
+type auditFilter struct {
+	Since time.Time
+	Until time.Time
+	UID   *uint32
+}
+
+func (f *auditFilter) matches(e *auditEntry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	if f.UID != nil && (e.UID == nil || *e.UID != *f.UID) {
+		return false
+	}
+	return true
+}
+
+// auditLog is an append-only log of audit entries, one JSON object per
+// line, which is rotated once it grows beyond auditLogMaxSize.
+type auditLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newAuditLog(path string) *auditLog {
+	return &auditLog{path: path}
+}
+
+func (l *auditLog) rotatedPath(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+// rotate must be called with the lock held.
+func (l *auditLog) rotate() error {
+	os.Remove(l.rotatedPath(auditLogKeep))
+	for n := auditLogKeep - 1; n > 0; n-- {
+		if err := os.Rename(l.rotatedPath(n), l.rotatedPath(n+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if auditLogKeep == 0 {
+		return os.Remove(l.path)
+	}
+	return os.Rename(l.path, l.rotatedPath(1))
+}
+
+// Append adds the given entry to the log, rotating it first if needed.
+func (l *auditLog) Append(e *auditEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return err
+	}
+	if fi, err := os.Stat(l.path); err == nil && fi.Size()+int64(len(data)) > auditLogMaxSize {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("cannot rotate audit log: %v", err)
+		}
+	}
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// Entries returns the entries of the log, including the rotated ones,
+// which match the given filter, oldest first.
+func (l *auditLog) Entries(filter *auditFilter) ([]*auditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var entries []*auditEntry
+	for n := auditLogKeep; n >= 0; n-- {
+		path := l.path
+		if n > 0 {
+			path = l.rotatedPath(n)
+		}
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 1024*1024)
+		for scanner.Scan() {
+			var e auditEntry
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				// a partially written entry
+				continue
+			}
+			if filter.matches(&e) {
+				entries = append(entries, &e)
+			}
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cannot read audit log: %v", err)
+		}
+	}
+	return entries, nil
+}
+
+// auditRequest records a state-changing request in the audit log of the
+// daemon. Errors are logged but otherwise ignored, the request has already
+// been served at this point.
+func (d *Daemon) auditRequest(r *http.Request, ucred *ucrednet, user *auth.UserState, status int, rsp Response) {
+	if d.auditLog == nil || r.Method == "GET" {
+		return
+	}
+	if status == 0 {
+		status = http.StatusOK
+	}
+	e := &auditEntry{
+		Time:       time.Now().UTC(),
+		Method:     r.Method,
+		Endpoint:   r.URL.Path,
+		StatusCode: status,
+	}
+	if ucred != nil {
+		uid := ucred.Uid
+		e.UID = &uid
+		e.PID = ucred.Pid
+		if snapName, err := cgroupSnapNameFromPid(int(ucred.Pid)); err == nil {
+			e.Snap = snapName
+		}
+	}
+	if user != nil {
+		e.User = user.Username
+	}
+	if srsp, ok := rsp.(StructuredResponse); ok {
+		rjson := srsp.JSON()
+		e.Change = rjson.Change
+		if errRes, ok := rjson.Result.(*errorResult); ok {
+			e.Error = errRes.Message
+		}
+	}
+	if err := d.auditLog.Append(e); err != nil {
+		logger.Noticef("cannot write audit log entry: %v", err)
+	}
+}
@@ -90,6 +90,9 @@ type Daemon struct {
 
 	expectedRebootDidNotHappen bool
 
+	// auditLog records the state-changing requests served
+	auditLog *auditLog
+
 	mu     sync.Mutex
 	cancel func()
 }
@@ -114,22 +117,36 @@ type Command struct {
 }
 
 func (c *Command) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ww := &wrappedWriter{w: w}
+	t0 := time.Now()
+	var ucred *ucrednet
+	var user *auth.UserState
+	var rsp Response
+	defer func() {
+		observeAPIRequest(c, r, ww.s, time.Since(t0))
+		c.d.auditRequest(r, ucred, user, ww.s, rsp)
+	}()
+	w = ww
+
 	st := c.d.state
 	st.Lock()
 	// TODO Look at the error and fail if there's an attempt to authenticate with invalid data.
-	user, _ := userFromRequest(st, r)
+	user, _ = userFromRequest(st, r)
 	st.Unlock()
 
 	// check if we are in degradedMode
 	if c.d.degradedErr != nil && r.Method != "GET" {
-		InternalError(c.d.degradedErr.Error()).ServeHTTP(w, r)
+		rsp = InternalError(c.d.degradedErr.Error())
+		rsp.ServeHTTP(w, r)
 		return
 	}
 
-	ucred, err := ucrednetGet(r.RemoteAddr)
+	var err error
+	ucred, err = ucrednetGet(r.RemoteAddr)
 	if err != nil && err != errNoID {
 		logger.Noticef("unexpected error when attempting to get UID: %s", err)
-		InternalError(err.Error()).ServeHTTP(w, r)
+		rsp = InternalError(err.Error())
+		rsp.ServeHTTP(w, r)
 		return
 	}
 
@@ -152,16 +169,18 @@ func (c *Command) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if rspf == nil {
-		MethodNotAllowed("method %q not allowed", r.Method).ServeHTTP(w, r)
+		rsp = MethodNotAllowed("method %q not allowed", r.Method)
+		rsp.ServeHTTP(w, r)
 		return
 	}
 
 	if rspe := access.CheckAccess(c.d, r, ucred, user); rspe != nil {
+		rsp = rspe
 		rspe.ServeHTTP(w, r)
 		return
 	}
 
-	rsp := rspf(c, r, user)
+	rsp = rspf(c, r, user)
 
 	if srsp, ok := rsp.(StructuredResponse); ok {
 		rjson := srsp.JSON()
@@ -742,5 +761,6 @@ func New() (*Daemon, error) {
 	}
 	d.overlord = ovld
 	d.state = ovld.State()
+	d.auditLog = newAuditLog(dirs.SnapAuditLogFile)
 	return d, nil
 }
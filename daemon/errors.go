@@ -238,6 +238,26 @@ func QuotaChangeConflict(qce *servicestate.QuotaChangeConflictError) *apiError {
 	}
 }
 
+// spaceEstimate is the disk space an operation is estimated to need for a
+// single snap.
+type spaceEstimate struct {
+	Name       string `json:"name"`
+	Snap       uint64 `json:"snap,omitempty"`
+	Components uint64 `json:"components,omitempty"`
+	Snapshot   uint64 `json:"snapshot,omitempty"`
+	Total      uint64 `json:"total"`
+}
+
+func newSpaceEstimate(est *snapstate.SnapSpaceEstimate) *spaceEstimate {
+	return &spaceEstimate{
+		Name:       est.InstanceName,
+		Snap:       est.Snap,
+		Components: est.Components,
+		Snapshot:   est.Snapshot,
+		Total:      est.Total(),
+	}
+}
+
 // InsufficientSpace is an error responder used when an operation cannot
 // be performed due to low disk space.
 func InsufficientSpace(dserr *snapstate.InsufficientSpaceError) *apiError {
@@ -248,6 +268,16 @@ func InsufficientSpace(dserr *snapstate.InsufficientSpaceError) *apiError {
 	if dserr.ChangeKind != "" {
 		value["change-kind"] = dserr.ChangeKind
 	}
+	if dserr.RequiredSpace > 0 {
+		value["required-space"] = dserr.RequiredSpace
+	}
+	if len(dserr.Estimates) > 0 {
+		estimates := make([]*spaceEstimate, len(dserr.Estimates))
+		for i := range dserr.Estimates {
+			estimates[i] = newSpaceEstimate(&dserr.Estimates[i])
+		}
+		value["space-estimates"] = estimates
+	}
 	return &apiError{
 		Status:  507,
 		Message: dserr.Error(),
@@ -242,6 +242,37 @@ func (s *errorsSuite) TestErrToResponseInsufficentSpace(c *C) {
 	})
 }
 
+func (s *errorsSuite) TestErrToResponseInsufficentSpaceEstimates(c *C) {
+	err := &snapstate.InsufficientSpaceError{
+		Snaps:         []string{"foo", "bar"},
+		ChangeKind:    "refresh",
+		Path:          "/path",
+		RequiredSpace: 70,
+		Estimates: []snapstate.SnapSpaceEstimate{
+			{InstanceName: "foo", Snap: 10, Components: 5},
+			{InstanceName: "bar", Snap: 20, Snapshot: 20},
+		},
+	}
+	rspe := daemon.ErrToResponse(err, nil, daemon.BadRequest, "%s: %v", "ERR")
+	c.Assert(rspe.Kind, Equals, client.ErrorKindInsufficientDiskSpace)
+	c.Check(rspe.Status, Equals, 507)
+
+	// check the value as seen by clients
+	data, jerr := json.Marshal(rspe.Value)
+	c.Assert(jerr, IsNil)
+	var value map[string]interface{}
+	c.Assert(json.Unmarshal(data, &value), IsNil)
+	c.Check(value, DeepEquals, map[string]interface{}{
+		"snap-names":     []interface{}{"foo", "bar"},
+		"change-kind":    "refresh",
+		"required-space": 70.0,
+		"space-estimates": []interface{}{
+			map[string]interface{}{"name": "foo", "snap": 10.0, "components": 5.0, "total": 15.0},
+			map[string]interface{}{"name": "bar", "snap": 20.0, "snapshot": 20.0, "total": 40.0},
+		},
+	})
+}
+
 func (s *errorsSuite) TestAuthCancelled(c *C) {
 	c.Check(daemon.AuthCancelled("auth cancelled"), DeepEquals, &daemon.APIError{
 		Status:  403,
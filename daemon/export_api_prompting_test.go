@@ -47,6 +47,9 @@ type PostRuleRequestBody struct {
 	PatchRule *PatchRuleContents `json:"rule,omitempty"`
 }
 
+type PostPolicyRequestBody postPolicyRequestBody
+type PostPolicyResult = postPolicyResult
+
 func MockInterfaceManager(manager interfaceManager) (restore func()) {
 	restore = testutil.Backup(&getInterfaceManager)
 	getInterfaceManager = func(c *Command) interfaceManager {
@@ -29,10 +29,14 @@ import (
 	"github.com/snapcore/snapd/asserts/snapasserts"
 	"github.com/snapcore/snapd/boot"
 	"github.com/snapcore/snapd/client/clientutil"
+	"github.com/snapcore/snapd/interfaces/policy"
 	"github.com/snapcore/snapd/osutil/user"
 	"github.com/snapcore/snapd/overlord"
 	"github.com/snapcore/snapd/overlord/assertstate"
+	"github.com/snapcore/snapd/overlord/auth"
 	"github.com/snapcore/snapd/overlord/hookstate"
+	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/manifeststate"
 	"github.com/snapcore/snapd/overlord/registrystate"
 	"github.com/snapcore/snapd/overlord/restart"
 	"github.com/snapcore/snapd/overlord/snapstate"
@@ -154,6 +158,10 @@ func MockSnapstateUpdateWithGoal(mock func(ctx context.Context, st *state.State,
 	return testutil.Mock(&snapstateUpdateWithGoal, mock)
 }
 
+func MockSnapstateRevisionRemovalTimes(mock func(st *state.State) (map[string]map[snap.Revision]time.Time, error)) (restore func()) {
+	return testutil.Mock(&snapstateRevisionRemovalTimes, mock)
+}
+
 func MockSnapstatePathUpdateGoal(mock func(snaps ...snapstate.PathSnap) snapstate.UpdateGoal) (restore func()) {
 	return testutil.Mock(&snapstatePathUpdateGoal, mock)
 }
@@ -166,6 +174,30 @@ func MockSnapstateUpdateOne(mock func(ctx context.Context, st *state.State, goal
 	}
 }
 
+func MockManifeststatePlan(mock func(st *state.State, m *manifeststate.Manifest) ([]*manifeststate.Operation, error)) (restore func()) {
+	return testutil.Mock(&manifeststatePlan, mock)
+}
+
+func MockManifeststateApply(mock func(ctx context.Context, st *state.State, m *manifeststate.Manifest, userID int) ([]*state.TaskSet, []*manifeststate.Operation, error)) (restore func()) {
+	return testutil.Mock(&manifeststateApply, mock)
+}
+
+func MockSnapstateRefreshPlanFromTaskSets(mock func(st *state.State, tss []*state.TaskSet) (*snapstate.RefreshPlan, error)) (restore func()) {
+	return testutil.Mock(&snapstateRefreshPlanFromTaskSets, mock)
+}
+
+func MockIfacestateExplainConnection(mock func(st *state.State, plug *snap.PlugInfo, slot *snap.SlotInfo, auto bool) (*policy.ConnectionExplanation, error)) (restore func()) {
+	return testutil.Mock(&ifacestateExplainConnection, mock)
+}
+
+func MockIfacemgrPreviewProfiles(mock func(m *ifacestate.InterfaceManager, info *snap.Info, backend string) (map[string]map[string][]byte, error)) (restore func()) {
+	return testutil.Mock(&ifacemgrPreviewProfiles, mock)
+}
+
+func MockIfacemgrPreviewAutoConnections(mock func(m *ifacestate.InterfaceManager, info *snap.Info) ([]*ifacestate.AutoConnectCandidate, error)) (restore func()) {
+	return testutil.Mock(&ifacemgrPreviewAutoConnections, mock)
+}
+
 func MockSnapstateInstallComponents(mock func(ctx context.Context, st *state.State, names []string, info *snap.Info, opts snapstate.Options) ([]*state.TaskSet, error)) (restore func()) {
 	old := snapstateInstallComponents
 	snapstateInstallComponents = mock
@@ -428,3 +460,19 @@ func MockRegistrystateGetView(f func(_ *state.State, _, _, _ string) (*registry.
 func MockRegistrystateSetViaView(f func(registry.DataBag, *registry.View, map[string]interface{}) error) (restore func()) {
 	return testutil.Mock(&registrystateSetViaView, f)
 }
+
+func MockAuditLog(maxSize int64, keep int) (restore func()) {
+	oldMaxSize, oldKeep := auditLogMaxSize, auditLogKeep
+	auditLogMaxSize, auditLogKeep = maxSize, keep
+	return func() {
+		auditLogMaxSize, auditLogKeep = oldMaxSize, oldKeep
+	}
+}
+
+func (d *Daemon) AuditRequest(r *http.Request, ucred *Ucrednet, user *auth.UserState, status int, rsp Response) {
+	d.auditRequest(r, ucred, user, status, rsp)
+}
+
+type SandboxProfile = sandboxProfile
+
+type AutoConnectPreview = autoConnectPreview
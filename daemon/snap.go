@@ -47,6 +47,8 @@ type aboutSnap struct {
 
 	hold       time.Time
 	gatingHold time.Time
+
+	removableFrom time.Time
 }
 
 // localSnapInfo returns the information about the current snap for the given
@@ -124,6 +126,18 @@ func allLocalSnapInfos(st *state.State, sel snapSelect, wanted map[string]bool)
 	st.Lock()
 	defer st.Unlock()
 
+	var removalTimes map[string]map[snap.Revision]time.Time
+	if sel == snapSelectAll {
+		// this releases the state lock for a while, so it is done before
+		// anything else is read from the state
+		var err error
+		removalTimes, err = snapstateRevisionRemovalTimes(st)
+		if err != nil {
+			// not worth failing the whole listing for
+			logger.Noticef("cannot get removal times of snap revisions: %v", err)
+		}
+	}
+
 	snapStates, err := snapstate.All(st)
 	if err != nil {
 		return nil, err
@@ -179,6 +193,7 @@ func allLocalSnapInfos(st *state.State, sel snapSelect, wanted map[string]bool)
 					refreshInhibit: refreshInhibit,
 					hold:           userHold,
 					gatingHold:     gatingHold,
+					removableFrom:  removalTimes[name][si.Revision],
 				}
 				aboutThis = append(aboutThis, abSnap)
 			}
@@ -274,6 +289,9 @@ func mapLocal(about aboutSnap, sd clientutil.StatusDecorator) *client.Snap {
 	if !about.gatingHold.IsZero() {
 		result.GatingHold = &about.gatingHold
 	}
+	if !about.removableFrom.IsZero() {
+		result.RemovableFrom = &about.removableFrom
+	}
 
 	if len(about.info.Components) > 0 {
 		result.Components = fillComponentInfo(about)
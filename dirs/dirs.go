@@ -81,6 +81,9 @@ var (
 	SnapStateLockFile string
 	SnapSystemKeyFile string
 
+	SnapAuditDir     string
+	SnapAuditLogFile string
+
 	SnapRepairConfigFile string
 	SnapRepairDir        string
 	SnapRepairStateFile  string
@@ -500,6 +503,9 @@ func SetRootDir(rootdir string) {
 	SnapStateLockFile = SnapStateLockFileUnder(rootdir)
 	SnapSystemKeyFile = filepath.Join(rootdir, snappyDir, "system-key")
 
+	SnapAuditDir = filepath.Join(rootdir, snappyDir, "audit")
+	SnapAuditLogFile = filepath.Join(SnapAuditDir, "audit.log")
+
 	SnapCacheDir = filepath.Join(rootdir, "/var/cache/snapd")
 	SnapNamesFile = filepath.Join(SnapCacheDir, "names")
 	SnapSectionsFile = filepath.Join(SnapCacheDir, "sections")
@@ -75,6 +75,8 @@ const (
 	Registries
 	// AppArmorPrompting enables AppArmor to prompt the user for permission when apps perform certain operations.
 	AppArmorPrompting
+	// JournaledState enables persisting the snapd state incrementally through a journal.
+	JournaledState
 
 	// lastFeature is the final known feature, it is only used for testing.
 	lastFeature
@@ -123,6 +125,8 @@ var featureNames = map[SnapdFeature]string{
 	Registries:            "registries",
 
 	AppArmorPrompting: "apparmor-prompting",
+
+	JournaledState: "journaled-state",
 }
 
 // featuresEnabledWhenUnset contains a set of features that are enabled when not explicitly configured.
@@ -148,6 +152,8 @@ var featuresExported = map[SnapdFeature]bool{
 	RefreshAppAwarenessUX: true,
 	Registries:            true,
 	AppArmorPrompting:     true,
+
+	JournaledState: true,
 }
 
 var (
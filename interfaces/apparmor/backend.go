@@ -344,11 +344,12 @@ type profilePathsResults struct {
 	removed   []string
 }
 
-func (b *Backend) prepareProfiles(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (prof *profilePathsResults, err error) {
-	snapName := appSet.InstanceName()
+// specification returns the apparmor specification of the given snap,
+// including the snippets derived from its layouts.
+func (b *Backend) specification(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (*Specification, error) {
 	spec, err := repo.SnapSpecification(b.Name(), appSet, opts)
 	if err != nil {
-		return nil, fmt.Errorf("cannot obtain apparmor specification for snap %q: %s", snapName, err)
+		return nil, fmt.Errorf("cannot obtain apparmor specification for snap %q: %s", appSet.InstanceName(), err)
 	}
 
 	snapInfo := appSet.Info()
@@ -362,6 +363,18 @@ func (b *Backend) prepareProfiles(appSet *interfaces.SnapAppSet, opts interfaces
 	// Add additional mount layouts rules for the snap.
 	spec.(*Specification).AddExtraLayouts(snapInfo, opts.ExtraLayouts)
 
+	return spec.(*Specification), nil
+}
+
+func (b *Backend) prepareProfiles(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (prof *profilePathsResults, err error) {
+	snapName := appSet.InstanceName()
+	spec, err := b.specification(appSet, opts, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	snapInfo := appSet.Info()
+
 	// core on classic is special
 	if snapName == "core" && release.OnClassic && apparmor_sandbox.ProbedLevel() != apparmor_sandbox.Unsupported {
 		if err := b.setupSnapConfineReexec(snapInfo); err != nil {
@@ -396,7 +409,7 @@ func (b *Backend) prepareProfiles(appSet *interfaces.SnapAppSet, opts interfaces
 	}
 
 	// Get the files that this snap should have
-	content := b.deriveContent(spec.(*Specification), appSet, opts)
+	content := b.deriveContent(spec, appSet, opts)
 
 	dir := dirs.SnapAppArmorDir
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -484,6 +497,20 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 	return errRemoveCached
 }
 
+// PreviewProfiles returns the apparmor profiles that Setup would write for
+// the given snap, without writing or loading them.
+func (b *Backend) PreviewProfiles(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (map[string][]byte, error) {
+	spec, err := b.specification(appSet, opts, repo)
+	if err != nil {
+		return nil, err
+	}
+	preview := make(map[string][]byte)
+	if err := interfaces.PreviewContent(preview, dirs.SnapAppArmorDir, b.deriveContent(spec, appSet, opts)); err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
 // SetupMany creates and loads apparmor profiles for multiple snaps.
 // The snaps can be in developer mode to make security violations non-fatal to
 // the offending application process.
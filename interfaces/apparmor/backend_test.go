@@ -2986,3 +2986,10 @@ func (s *backendSuite) TestRemoveAllSnapAppArmorProfiles(c *C) {
 		c.Check(os.IsNotExist(err), Equals, true)
 	}
 }
+
+func (s *backendSuite) TestPreviewProfiles(c *C) {
+	preview := s.PreviewAndInstallSnap(c, interfaces.ConfinementOptions{}, ifacetest.SambaYamlV1, 1)
+	c.Assert(preview, HasLen, 2)
+	c.Check(string(preview[filepath.Join(dirs.SnapAppArmorDir, "snap.samba.smbd")]), testutil.Contains, `profile "snap.samba.smbd"`)
+	c.Check(string(preview[filepath.Join(dirs.SnapAppArmorDir, "snap-update-ns.samba")]), testutil.Contains, `profile snap-update-ns.samba`)
+}
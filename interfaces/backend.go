@@ -20,6 +20,11 @@
 package interfaces
 
 import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/timings"
 )
@@ -136,3 +141,31 @@ type SecurityBackendDiscardingLate interface {
 	// step of the remove change.
 	RemoveLate(snapName string, rev snap.Revision, typ snap.Type) error
 }
+
+// SecurityBackendPreview interface may be implemented by backends that can
+// compute the security artefacts of a snap without writing or loading them,
+// so that they can be reviewed before the snap is installed.
+type SecurityBackendPreview interface {
+	// PreviewProfiles returns the content of the files that Setup would
+	// write for the given snap, indexed by their absolute path.
+	PreviewProfiles(appSet *SnapAppSet, opts ConfinementOptions, repo *Repository) (map[string][]byte, error)
+}
+
+// PreviewContent reads the given desired state of the files of a directory,
+// as passed to osutil.EnsureDirState, and adds their content to preview,
+// indexed by their path in dir.
+func PreviewContent(preview map[string][]byte, dir string, content map[string]osutil.FileState) error {
+	for name, state := range content {
+		r, _, _, err := state.State()
+		if err != nil {
+			return fmt.Errorf("cannot preview %q: %v", name, err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return fmt.Errorf("cannot preview %q: %v", name, err)
+		}
+		preview[filepath.Join(dir, name)] = data
+	}
+	return nil
+}
@@ -54,6 +54,14 @@ const audioRecordConnectedPlugAppArmor = `
 # interface is connected.
 `
 
+// When AppArmor prompting is enabled, direct access to the capture devices
+// can be granted by the user on a per-access basis. Without prompting, audio
+// recording is only ever mediated by the audio service.
+const audioRecordConnectedPlugAppArmorPrompt = `
+# Allow prompting for direct access to audio capture devices.
+###PROMPT### /dev/snd/pcmC[0-9]*D[0-9]*c rw,
+`
+
 type audioRecordInterface struct{}
 
 func (iface *audioRecordInterface) Name() string {
@@ -70,6 +78,9 @@ func (iface *audioRecordInterface) StaticInfo() interfaces.StaticInfo {
 
 func (iface *audioRecordInterface) AppArmorConnectedPlug(spec *apparmor.Specification, plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) error {
 	spec.AddSnippet(audioRecordConnectedPlugAppArmor)
+	if spec.UsePromptPrefix() {
+		spec.AddSnippet(audioRecordConnectedPlugAppArmorPrompt)
+	}
 	return nil
 }
 
@@ -101,6 +101,13 @@ func (s *AudioRecordInterfaceSuite) TestAppArmor(c *C) {
 	c.Assert(spec.AddConnectedPlug(s.iface, s.plug, s.coreSlot), IsNil)
 	c.Assert(spec.SecurityTags(), DeepEquals, []string{"snap.consumer.app"})
 	c.Check(spec.SnippetForTag("snap.consumer.app"), testutil.Contains, "# Access for communication with audio recording service done via\n")
+	c.Check(spec.SnippetForTag("snap.consumer.app"), Not(testutil.Contains), "/dev/snd/pcmC")
+
+	// connected plug to core slot with prompting enabled
+	backend := &apparmor.Backend{}
+	promptSpec := backend.NewSpecification(s.plug.AppSet(), interfaces.ConfinementOptions{AppArmorPrompting: true}).(*apparmor.Specification)
+	c.Assert(promptSpec.AddConnectedPlug(s.iface, s.plug, s.coreSlot), IsNil)
+	c.Check(promptSpec.SnippetForTag("snap.consumer.app"), testutil.Contains, "###PROMPT### /dev/snd/pcmC[0-9]*D[0-9]*c rw,\n")
 
 	// connected core slot to plug
 	spec = apparmor.NewSpecification(s.coreSlot.AppSet())
@@ -31,7 +31,7 @@ const cameraBaseDeclarationSlots = `
 
 const cameraConnectedPlugAppArmor = `
 # Until we have proper device assignment, allow access to all cameras
-/dev/video[0-9]* rw,
+###PROMPT### /dev/video[0-9]* rw,
 
 # VideoCore cameras (shared device with VideoCore/EGL)
 /dev/vchiq rw,
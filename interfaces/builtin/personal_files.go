@@ -78,6 +78,7 @@ func init() {
 			},
 			apparmorHeader:    personalFilesConnectedPlugAppArmor,
 			extraPathValidate: validateSinglePathHome,
+			promptable:        true,
 		},
 	})
 }
@@ -82,13 +82,13 @@ func (s *personalFilesInterfaceSuite) TestConnectedPlugAppArmorHappy(c *C) {
 # Description: Can access specific personal files or directories in the 
 # users's home directory.
 # This is restricted because it gives file access to arbitrary locations.
-owner "@{HOME}/.read-dir{,/,/**}" rk,
-owner "@{HOME}/.read-file{,/,/**}" rk,
-owner "@{HOME}/.local/share/target{,/,/**}" rk,
-owner "@{HOME}/.write-dir{,/,/**}" rwkl,
-owner "@{HOME}/.write-file{,/,/**}" rwkl,
-owner "@{HOME}/.local/share/target{,/,/**}" rwkl,
-owner "@{HOME}/.local/share/dir1/dir2/target{,/,/**}" rwkl,
+###PROMPT### owner "@{HOME}/.read-dir{,/,/**}" rk,
+###PROMPT### owner "@{HOME}/.read-file{,/,/**}" rk,
+###PROMPT### owner "@{HOME}/.local/share/target{,/,/**}" rk,
+###PROMPT### owner "@{HOME}/.write-dir{,/,/**}" rwkl,
+###PROMPT### owner "@{HOME}/.write-file{,/,/**}" rwkl,
+###PROMPT### owner "@{HOME}/.local/share/target{,/,/**}" rwkl,
+###PROMPT### owner "@{HOME}/.local/share/dir1/dir2/target{,/,/**}" rwkl,
 `)
 
 	c.Check("\n"+strings.Join(apparmorSpec.UpdateNS(), "\n"), Equals, `
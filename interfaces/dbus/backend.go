@@ -192,6 +192,20 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 	return nil
 }
 
+// PreviewProfiles returns the DBus configuration files that Setup would write
+// for the given snap, without writing them.
+func (b *Backend) PreviewProfiles(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (map[string][]byte, error) {
+	spec, err := repo.SnapSpecification(b.Name(), appSet, opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot obtain dbus specification for snap %q: %s", appSet.InstanceName(), err)
+	}
+	preview := make(map[string][]byte)
+	if err := interfaces.PreviewContent(preview, dirs.SnapDBusSystemPolicyDir, b.deriveContent(spec.(*Specification), appSet)); err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
 func profileGlobs(snapName string) []string {
 	var globs []string
 	for _, g := range interfaces.SecurityTagGlobs(snapName) {
@@ -575,3 +575,13 @@ func (s *backendSuite) TestSetupWritesDbusFilesBothSnapdAndCoreInstalled(c *C) {
 		c.Check(filepath.Join(dirs.GlobalRootDir, fn), testutil.FileEquals, fmt.Sprintf("content of %s for snap snapd", filepath.Base(fn)))
 	}
 }
+
+func (s *backendSuite) TestPreviewProfiles(c *C) {
+	s.Iface.DBusPermanentSlotCallback = func(spec *dbus.Specification, slot *snap.SlotInfo) error {
+		spec.AddSnippet("<policy/>")
+		return nil
+	}
+	preview := s.PreviewAndInstallSnap(c, interfaces.ConfinementOptions{}, ifacetest.SambaYamlV1, 0)
+	c.Assert(preview, HasLen, 1)
+	c.Check(string(preview[filepath.Join(dirs.SnapDBusSystemPolicyDir, "snap.samba.smbd.conf")]), testutil.Contains, "<policy/>")
+}
@@ -0,0 +1,218 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/snapcore/snapd/strutil"
+)
+
+// aaVariables are the regular expressions the AppArmor variables used in
+// the snippets of interfaces expand to. Rules using other variables are
+// ignored.
+var aaVariables = map[string]string{
+	"HOME":        `(/home/[^/]+|/root)`,
+	"HOMEDIRS":    `/home`,
+	"PROC":        `/proc`,
+	"pid":         `[0-9]+`,
+	"pids":        `[0-9]+`,
+	"tid":         `[0-9]+`,
+	"sys":         `/sys`,
+	"run":         `/run`,
+	"etc_ro":      `/etc`,
+	"etc_rw":      `/etc`,
+	"multiarch":   `[^/]+`,
+	"INSTALL_DIR": `(/snap|/var/lib/snapd/snap)`,
+	// the snap specific variables are replaced by the name of the snap
+	"SNAP_NAME":           "",
+	"SNAP_INSTANCE_NAME":  "",
+	"SNAP_REVISION":       `[^/]+`,
+	"SNAP_COMPONENT_NAME": `[^/]+`,
+}
+
+// aaGlobToRegexp converts an AppArmor path glob into an anchored regular
+// expression.
+func aaGlobToRegexp(glob, snapName string) (*regexp.Regexp, bool) {
+	var re strings.Builder
+	re.WriteString("^")
+	braces := 0
+	for i := 0; i < len(glob); i++ {
+		switch ch := glob[i]; {
+		case strings.HasPrefix(glob[i:], "@{"):
+			end := strings.IndexByte(glob[i:], '}')
+			if end < 0 {
+				return nil, false
+			}
+			name := glob[i+2 : i+end]
+			expansion, ok := aaVariables[name]
+			if !ok {
+				return nil, false
+			}
+			if name == "SNAP_NAME" || name == "SNAP_INSTANCE_NAME" {
+				expansion = regexp.QuoteMeta(snapName)
+			}
+			re.WriteString(expansion)
+			i += end
+		case strings.HasPrefix(glob[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case ch == '*':
+			re.WriteString("[^/]*")
+		case ch == '?':
+			re.WriteString("[^/]")
+		case ch == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				return nil, false
+			}
+			re.WriteString(glob[i : i+end+1])
+			i += end
+		case ch == '{':
+			braces++
+			re.WriteString("(?:")
+		case ch == ',' && braces > 0:
+			re.WriteString("|")
+		case ch == '}' && braces > 0:
+			braces--
+			re.WriteString(")")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	if braces != 0 {
+		return nil, false
+	}
+	re.WriteString("$")
+	compiled, err := regexp.Compile(re.String())
+	if err != nil {
+		return nil, false
+	}
+	return compiled, true
+}
+
+// aaPermsAllow returns whether the permissions of a file rule allow all
+// the permissions of the given denied mask.
+func aaPermsAllow(perms, denied string) bool {
+	for _, p := range denied {
+		var ok bool
+		switch p {
+		case ':':
+			continue
+		case 'c', 'd', 'w':
+			// creating and deleting files requires write permission
+			ok = strings.ContainsRune(perms, 'w')
+		case 'a':
+			ok = strings.ContainsAny(perms, "aw")
+		default:
+			ok = strings.ContainsRune(perms, p)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// aaRuleAllows returns whether the given rule of an AppArmor snippet allows
+// the access which was denied.
+func aaRuleAllows(rule string, d *Denial, snapName string) bool {
+	rule = strings.TrimSpace(rule)
+	if i := strings.IndexByte(rule, '#'); i >= 0 {
+		rule = strings.TrimSpace(rule[:i])
+	}
+	rule = strings.TrimSuffix(rule, ",")
+	fields := strings.Fields(rule)
+	owner := false
+	for len(fields) > 0 {
+		switch fields[0] {
+		case "audit", "allow", "file":
+			fields = fields[1:]
+			continue
+		case "owner":
+			owner = true
+			fields = fields[1:]
+			continue
+		case "deny":
+			return false
+		}
+		break
+	}
+	if len(fields) == 0 {
+		return false
+	}
+
+	switch {
+	case fields[0] == "capability":
+		return d.Capability != "" && (len(fields) == 1 || strutil.ListContains(fields[1:], d.Capability))
+	case fields[0] == "network":
+		if d.Family == "" {
+			return false
+		}
+		// "network," allows all families
+		if len(fields) == 1 {
+			return true
+		}
+		return fields[1] == d.Family && (len(fields) == 2 || d.SockType == "" || fields[2] == d.SockType)
+	case d.Path == "" || d.Capability != "" || d.Family != "":
+		return false
+	}
+
+	if len(fields) < 2 || (owner && !d.Owner) {
+		return false
+	}
+	path, perms := strings.Trim(fields[0], `"`), fields[1]
+	if !strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "@{") {
+		// the permissions may come first
+		path, perms = strings.Trim(fields[1], `"`), fields[0]
+		if !strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "@{") {
+			return false
+		}
+	}
+	if !aaPermsAllow(perms, d.Mask) {
+		return false
+	}
+	re, ok := aaGlobToRegexp(path, snapName)
+	return ok && re.MatchString(d.Path)
+}
+
+// aaSnippetAllows returns whether any of the rules of the given AppArmor
+// snippet allows the access which was denied.
+func aaSnippetAllows(snippet string, d *Denial, snapName string) bool {
+	for _, line := range strings.Split(snippet, "\n") {
+		if aaRuleAllows(line, d, snapName) {
+			return true
+		}
+	}
+	return false
+}
+
+// seccompSnippetAllows returns whether the given seccomp snippet allows the
+// system call which was denied, possibly only for some of its arguments.
+func seccompSnippetAllows(snippet string, d *Denial) bool {
+	for _, line := range strings.Split(snippet, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == d.Syscall {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package denials parses the AppArmor and seccomp denials logged by the
+// kernel and suggests the interfaces that would allow the denied access.
+package denials
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snapcore/snapd/snap/naming"
+)
+
+// Kind is the kind of sandbox that denied an access.
+type Kind string
+
+const (
+	KindAppArmor Kind = "apparmor"
+	KindSeccomp  Kind = "seccomp"
+)
+
+// Denial is an access denied to a process by its sandbox.
+type Denial struct {
+	Time time.Time
+	Kind Kind
+	// Label is the AppArmor label of the process, which for snap apps and
+	// hooks is their security tag.
+	Label string
+	Comm  string
+
+	// Operation is the AppArmor operation which was denied, such as "open",
+	// "capable" or "create".
+	Operation string
+	// Path is the path of the file which was accessed, if any.
+	Path string
+	// Mask is the set of file permissions which were denied.
+	Mask string
+	// Owner is set when the process owns the accessed file.
+	Owner bool
+	// Capability is the name of the capability which was denied, if any.
+	Capability string
+	// Family and SockType describe the socket which was denied, if any.
+	Family   string
+	SockType string
+
+	// Syscall is the name of the system call denied by seccomp, or its
+	// number if the name is not known.
+	Syscall string
+}
+
+// Snap returns the instance name of the snap the denied process belongs to,
+// or the empty string if it does not belong to a snap.
+func (d *Denial) Snap() string {
+	tag, err := naming.ParseSecurityTag(d.Label)
+	if err != nil {
+		return ""
+	}
+	return tag.InstanceName()
+}
+
+// App returns the name of the app or hook, as hook:<name>, the denied
+// process belongs to, or the empty string if it does not belong to a snap.
+func (d *Denial) App() string {
+	tag, err := naming.ParseSecurityTag(d.Label)
+	if err != nil {
+		return ""
+	}
+	switch tag := tag.(type) {
+	case naming.AppSecurityTag:
+		return tag.AppName()
+	case naming.HookSecurityTag:
+		return "hook:" + tag.HookName()
+	}
+	return ""
+}
+
+// Access returns a short description of the denied access.
+func (d *Denial) Access() string {
+	switch {
+	case d.Kind == KindSeccomp:
+		return "syscall " + d.Syscall
+	case d.Capability != "":
+		return "capability " + d.Capability
+	case d.Family != "":
+		return strings.TrimSpace(fmt.Sprintf("network %s %s", d.Family, d.SockType))
+	case d.Path != "":
+		return fmt.Sprintf("%s %s (%s)", d.Operation, d.Path, d.Mask)
+	}
+	return d.Operation
+}
+
+// auditFields returns the key=value fields of an audit message; quoted
+// values are unquoted and hex encoded values are decoded.
+func auditFields(msg string) map[string]string {
+	fields := make(map[string]string)
+	for len(msg) > 0 {
+		eq := strings.IndexByte(msg, '=')
+		if eq < 0 {
+			break
+		}
+		key := msg[:eq]
+		if sp := strings.LastIndexByte(key, ' '); sp >= 0 {
+			key = key[sp+1:]
+		}
+		msg = msg[eq+1:]
+		var value string
+		if strings.HasPrefix(msg, `"`) {
+			if end := strings.IndexByte(msg[1:], '"'); end >= 0 {
+				value = msg[1 : end+1]
+				msg = msg[end+2:]
+			} else {
+				value = msg[1:]
+				msg = ""
+			}
+		} else {
+			end := strings.IndexByte(msg, ' ')
+			if end < 0 {
+				end = len(msg)
+			}
+			value = msg[:end]
+			msg = msg[end:]
+			// values with special characters are hex encoded
+			if key == "name" || key == "comm" || key == "profile" {
+				if decoded, err := hex.DecodeString(value); err == nil {
+					value = string(decoded)
+				}
+			}
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// Parse parses a kernel audit message and returns the denial it is about,
+// or nil if it is not about an AppArmor or seccomp denial.
+func Parse(msg string) *Denial {
+	if !strings.Contains(msg, `apparmor="DENIED"`) && !strings.Contains(msg, "syscall=") {
+		return nil
+	}
+	fields := auditFields(msg)
+
+	if fields["apparmor"] == "DENIED" {
+		d := &Denial{
+			Kind:       KindAppArmor,
+			Label:      fields["profile"],
+			Comm:       fields["comm"],
+			Operation:  fields["operation"],
+			Path:       fields["name"],
+			Mask:       fields["denied_mask"],
+			Capability: fields["capname"],
+			Family:     fields["family"],
+			SockType:   fields["sock_type"],
+		}
+		d.Owner = fields["fsuid"] != "" && fields["fsuid"] == fields["ouid"]
+		return d
+	}
+
+	syscall, ok := fields["syscall"]
+	if !ok || (fields["type"] != "1326" && fields["type"] != "SECCOMP" && !strings.HasPrefix(msg, "SECCOMP ")) {
+		return nil
+	}
+	// the label is followed by the mode of the profile, if any
+	label, _, _ := strings.Cut(fields["subj"], " ")
+	d := &Denial{
+		Kind:    KindSeccomp,
+		Label:   label,
+		Comm:    fields["comm"],
+		Syscall: syscall,
+	}
+	if nr, err := strconv.ParseUint(syscall, 10, 32); err == nil && fields["compat"] != "1" {
+		if name, ok := syscallName(fields["arch"], uintptr(nr)); ok {
+			d.Syscall = name
+		}
+	}
+	return d
+}
+
+// Group is a set of identical denials.
+type Group struct {
+	*Denial
+	Count int
+	First time.Time
+	Last  time.Time
+}
+
+func (d *Denial) key() string {
+	return strings.Join([]string{string(d.Kind), d.Label, d.Operation, d.Path, d.Mask,
+		d.Capability, d.Family, d.SockType, d.Syscall}, "\x00")
+}
+
+// GroupDenials groups identical denials, other than for their time and the
+// command that was denied, sorting the groups by snap, app and time.
+func GroupDenials(denials []*Denial) []*Group {
+	byKey := make(map[string]*Group)
+	var groups []*Group
+	for _, d := range denials {
+		g := byKey[d.key()]
+		if g == nil {
+			g = &Group{Denial: d, First: d.Time}
+			byKey[d.key()] = g
+			groups = append(groups, g)
+		}
+		g.Count++
+		if d.Time.Before(g.First) {
+			g.First = d.Time
+		}
+		if d.Time.After(g.Last) {
+			g.Last = d.Time
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Label != groups[j].Label {
+			return groups[i].Label < groups[j].Label
+		}
+		return groups[i].First.Before(groups[j].First)
+	})
+	return groups
+}
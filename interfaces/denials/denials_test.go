@@ -0,0 +1,308 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/builtin"
+	"github.com/snapcore/snapd/interfaces/denials"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type denialsSuite struct{}
+
+var _ = Suite(&denialsSuite{})
+
+const fileDenial = `audit: type=1400 audit(1700000000.123:456): apparmor="DENIED" operation="open" class="file" profile="snap.foo.app" name="/sys/devices/virtual/net/lo/statistics/rx_bytes" pid=1234 comm="foo" requested_mask="r" denied_mask="r" fsuid=1000 ouid=0`
+
+func (s *denialsSuite) TestParseAppArmorFile(c *C) {
+	d := denials.Parse(fileDenial)
+	c.Assert(d, NotNil)
+	c.Check(d, DeepEquals, &denials.Denial{
+		Kind:      denials.KindAppArmor,
+		Label:     "snap.foo.app",
+		Comm:      "foo",
+		Operation: "open",
+		Path:      "/sys/devices/virtual/net/lo/statistics/rx_bytes",
+		Mask:      "r",
+	})
+	c.Check(d.Snap(), Equals, "foo")
+	c.Check(d.App(), Equals, "app")
+	c.Check(d.Access(), Equals, "open /sys/devices/virtual/net/lo/statistics/rx_bytes (r)")
+}
+
+func (s *denialsSuite) TestParseAppArmorHexEncodedOwner(c *C) {
+	// "/home/user/my file" is hex encoded as it contains a space
+	d := denials.Parse(`apparmor="DENIED" operation="mknod" profile="snap.foo.hook.configure" name=2F686F6D652F757365722F6D792066696C65 pid=1 comm="foo" requested_mask="c" denied_mask="c" fsuid=1000 ouid=1000`)
+	c.Assert(d, NotNil)
+	c.Check(d.Path, Equals, "/home/user/my file")
+	c.Check(d.Owner, Equals, true)
+	c.Check(d.Snap(), Equals, "foo")
+	c.Check(d.App(), Equals, "hook:configure")
+}
+
+func (s *denialsSuite) TestParseAppArmorCapabilityAndNetwork(c *C) {
+	d := denials.Parse(`apparmor="DENIED" operation="capable" profile="snap.foo.app" pid=1 comm="foo" capability=12 capname="net_admin"`)
+	c.Assert(d, NotNil)
+	c.Check(d.Capability, Equals, "net_admin")
+	c.Check(d.Access(), Equals, "capability net_admin")
+
+	d = denials.Parse(`apparmor="DENIED" operation="create" profile="snap.foo.app" pid=1 comm="foo" family="netlink" sock_type="raw" protocol=0 requested_mask="create" denied_mask="create"`)
+	c.Assert(d, NotNil)
+	c.Check(d.Family, Equals, "netlink")
+	c.Check(d.SockType, Equals, "raw")
+	c.Check(d.Access(), Equals, "network netlink raw")
+}
+
+func (s *denialsSuite) TestParseSeccomp(c *C) {
+	arch, ok := denials.NativeAuditArch["amd64"]
+	c.Assert(ok, Equals, true)
+	restore := denials.MockGoarch("amd64")
+	defer restore()
+
+	// the names are known for the system call numbers of the architecture
+	// the test is built for
+	msg := fmt.Sprintf(`audit: type=1326 audit(1700000000.456:457): auid=1000 uid=1000 gid=1000 ses=2 subj=snap.foo.app (enforce) pid=1234 comm="foo" exe="/snap/foo/x1/bin/foo" sig=0 arch=%s syscall=%d compat=0 ip=0x7f code=0x50000`, arch, unix.SYS_MOUNT)
+	d := denials.Parse(msg)
+	c.Assert(d, NotNil)
+	c.Check(d.Kind, Equals, denials.KindSeccomp)
+	c.Check(d.Label, Equals, "snap.foo.app")
+	c.Check(d.Comm, Equals, "foo")
+	c.Check(d.Syscall, Equals, "mount")
+	c.Check(d.Access(), Equals, "syscall mount")
+
+	// foreign architectures are reported by number
+	d = denials.Parse(`type=SECCOMP msg=audit(1700000000.456:457): subj=snap.foo.app pid=1 comm="foo" arch=40000003 syscall=21 compat=1`)
+	c.Assert(d, NotNil)
+	c.Check(d.Syscall, Equals, "21")
+}
+
+func (s *denialsSuite) TestParseNotADenial(c *C) {
+	for _, msg := range []string{
+		"",
+		"kernel: eth0: link up",
+		`audit: type=1400 audit(1700000000.123:456): apparmor="STATUS" operation="profile_load" profile="unconfined" name="snap.foo.app" pid=1 comm="apparmor_parser"`,
+		`audit: type=1300 audit(1700000000.123:456): arch=c000003e syscall=59 success=yes`,
+	} {
+		c.Check(denials.Parse(msg), IsNil, Commentf("%q", msg))
+	}
+	c.Check(denials.Parse(`apparmor="DENIED" operation="open" profile="/usr/sbin/cupsd" name="/etc/shadow" denied_mask="r"`).Snap(), Equals, "")
+}
+
+func (s *denialsSuite) TestGroupDenials(c *C) {
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	a := denials.Parse(fileDenial)
+	a.Time = t0.Add(time.Minute)
+	b := denials.Parse(fileDenial)
+	b.Time = t0
+	b.Comm = "other"
+	other := denials.Parse(`apparmor="DENIED" operation="capable" profile="snap.bar.app" capname="sys_admin"`)
+	other.Time = t0.Add(time.Hour)
+
+	groups := denials.GroupDenials([]*denials.Denial{a, other, b})
+	c.Assert(groups, HasLen, 2)
+	c.Check(groups[0].Label, Equals, "snap.bar.app")
+	c.Check(groups[0].Count, Equals, 1)
+	c.Check(groups[1].Denial, Equals, a)
+	c.Check(groups[1].Count, Equals, 2)
+	c.Check(groups[1].First, Equals, t0)
+	c.Check(groups[1].Last, Equals, t0.Add(time.Minute))
+}
+
+func (s *denialsSuite) TestAppArmorGlobs(c *C) {
+	for _, t := range []struct {
+		glob, path string
+		match      bool
+	}{
+		{"/etc/foo", "/etc/foo", true},
+		{"/etc/foo", "/etc/foobar", false},
+		{"/etc/*", "/etc/foo", true},
+		{"/etc/*", "/etc/foo/bar", false},
+		{"/etc/**", "/etc/foo/bar", true},
+		{"/dev/tty?", "/dev/tty1", true},
+		{"/dev/tty[0-9]", "/dev/ttyS", false},
+		{"/dev/{video,media}[0-9]*", "/dev/media0", true},
+		{"@{PROC}/@{pid}/stat", "/proc/42/stat", true},
+		{"@{HOME}/.config/", "/home/user/.config/", true},
+		{"/var/snap/@{SNAP_INSTANCE_NAME}/**", "/var/snap/foo/common/x", true},
+		{"/var/snap/@{SNAP_INSTANCE_NAME}/**", "/var/snap/bar/common/x", false},
+	} {
+		re, ok := denials.AaGlobToRegexp(t.glob, "foo")
+		c.Assert(ok, Equals, true, Commentf("%s", t.glob))
+		c.Check(re.MatchString(t.path), Equals, t.match, Commentf("%s %s", t.glob, t.path))
+	}
+
+	_, ok := denials.AaGlobToRegexp("@{UNKNOWN}/foo", "foo")
+	c.Check(ok, Equals, false)
+}
+
+func (s *denialsSuite) TestAppArmorRules(c *C) {
+	open := &denials.Denial{Kind: denials.KindAppArmor, Operation: "open", Path: "/etc/foo", Mask: "r"}
+	write := &denials.Denial{Kind: denials.KindAppArmor, Operation: "open", Path: "/etc/foo", Mask: "w", Owner: true}
+	capable := &denials.Denial{Kind: denials.KindAppArmor, Operation: "capable", Capability: "net_admin"}
+	network := &denials.Denial{Kind: denials.KindAppArmor, Operation: "create", Family: "netlink", SockType: "raw"}
+
+	for _, t := range []struct {
+		rule   string
+		d      *denials.Denial
+		allows bool
+	}{
+		{"/etc/foo r,", open, true},
+		{"  /etc/{foo,bar} rw, # comment", open, true},
+		{"/etc/foo w,", open, false},
+		{"r /etc/foo,", open, true},
+		{`"/etc/foo" rk,`, open, true},
+		{"deny /etc/foo r,", open, false},
+		{"audit owner /etc/foo r,", open, false},
+		{"owner /etc/foo rw,", write, true},
+		{"/etc/foo a,", write, false},
+		{"capability net_admin,", capable, true},
+		{"capability net_raw sys_admin,", capable, false},
+		{"capability net_admin,", open, false},
+		{"network netlink raw,", network, true},
+		{"network netlink dgram,", network, false},
+		{"network,", network, true},
+		{"network inet,", network, false},
+		{"#include <abstractions/base>", open, false},
+	} {
+		c.Check(denials.AaRuleAllows(t.rule, t.d, "foo"), Equals, t.allows, Commentf("%q", t.rule))
+	}
+}
+
+func (s *denialsSuite) TestSeccompSnippet(c *C) {
+	d := &denials.Denial{Kind: denials.KindSeccomp, Syscall: "mount"}
+	c.Check(denials.SeccompSnippetAllows("# comment\numount\nmount - - - - -\n", d), Equals, true)
+	c.Check(denials.SeccompSnippetAllows("umount\nmount_setattr\n", d), Equals, false)
+}
+
+const systemYaml = `name: snapd
+version: 1
+type: snapd
+slots:
+  network-observe:
+  network-control:
+  mount-control:
+  hardware-observe:
+  home:
+`
+
+const fooYaml = `name: foo
+version: 1
+apps:
+  app:
+    plugs: [network-observe]
+  other:
+plugs:
+  hw:
+    interface: hardware-observe
+`
+
+func (s *denialsSuite) repo(c *C) (*interfaces.Repository, *interfaces.SnapAppSet) {
+	repo := interfaces.NewRepository()
+	for _, iface := range builtin.Interfaces() {
+		c.Assert(repo.AddInterface(iface), IsNil)
+	}
+	systemInfo := snaptest.MockInfo(c, systemYaml, nil)
+	systemAppSet, err := interfaces.NewSnapAppSet(systemInfo, nil)
+	c.Assert(err, IsNil)
+	c.Assert(repo.AddAppSet(systemAppSet), IsNil)
+
+	info := snaptest.MockInfo(c, fooYaml, &snap.SideInfo{Revision: snap.R(1)})
+	appSet, err := interfaces.NewSnapAppSet(info, nil)
+	c.Assert(err, IsNil)
+	c.Assert(repo.AddAppSet(appSet), IsNil)
+
+	_, err = repo.Connect(&interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "foo", Name: "network-observe"},
+		SlotRef: interfaces.SlotRef{Snap: "snapd", Name: "network-observe"},
+	}, nil, nil, nil, nil, nil)
+	c.Assert(err, IsNil)
+	return repo, appSet
+}
+
+func (s *denialsSuite) TestSuggest(c *C) {
+	repo, appSet := s.repo(c)
+
+	d := denials.Parse(fileDenial)
+	c.Check(denials.Suggest(repo, appSet, d), DeepEquals, []denials.Suggestion{
+		{Interface: "hardware-observe", Plug: "hw"},
+		{Interface: "network-observe", Plug: "network-observe", Connected: true},
+	})
+
+	// the plug of the other app is not bound to it
+	d.Label = "snap.foo.other"
+	c.Check(denials.Suggest(repo, appSet, d), DeepEquals, []denials.Suggestion{
+		{Interface: "hardware-observe", Plug: "hw"},
+		{Interface: "network-observe"},
+	})
+
+	d = &denials.Denial{Kind: denials.KindAppArmor, Label: "snap.foo.app", Operation: "capable", Capability: "net_admin"}
+	c.Check(denials.Suggest(repo, appSet, d), DeepEquals, []denials.Suggestion{
+		{Interface: "network-control"},
+	})
+
+	d = &denials.Denial{Kind: denials.KindSeccomp, Label: "snap.foo.app", Syscall: "mount"}
+	c.Check(denials.Suggest(repo, appSet, d), DeepEquals, []denials.Suggestion{
+		{Interface: "mount-control"},
+		{Interface: "network-control"},
+	})
+
+	// nothing grants access to the files of other snaps
+	d = &denials.Denial{Kind: denials.KindAppArmor, Label: "snap.foo.app", Operation: "open", Path: "/var/snap/bar/common/secret", Mask: "r"}
+	c.Check(denials.Suggest(repo, appSet, d), HasLen, 0)
+
+	// denials of other snaps are not considered
+	d.Label = "snap.bar.app"
+	c.Check(denials.Suggest(repo, appSet, d), IsNil)
+}
+
+func (s *denialsSuite) TestSuggestAllInterfaces(c *C) {
+	// all the interfaces which can have a slot with default attributes
+	// can be considered
+	repo := interfaces.NewRepository()
+	systemInfo := &snap.Info{SuggestedName: "snapd", Version: "1", SnapType: snap.TypeSnapd, Slots: map[string]*snap.SlotInfo{}}
+	for _, iface := range builtin.Interfaces() {
+		c.Assert(repo.AddInterface(iface), IsNil)
+		slot := &snap.SlotInfo{Snap: systemInfo, Name: iface.Name(), Interface: iface.Name()}
+		// slots which need attributes cannot be implicit
+		if interfaces.BeforePrepareSlot(iface, slot) == nil {
+			systemInfo.Slots[iface.Name()] = slot
+		}
+	}
+	systemAppSet, err := interfaces.NewSnapAppSet(systemInfo, nil)
+	c.Assert(err, IsNil)
+	c.Assert(repo.AddAppSet(systemAppSet), IsNil)
+	info := snaptest.MockInfo(c, fooYaml, &snap.SideInfo{Revision: snap.R(1)})
+	appSet, err := interfaces.NewSnapAppSet(info, nil)
+	c.Assert(err, IsNil)
+
+	suggestions := denials.Suggest(repo, appSet, denials.Parse(fileDenial))
+	c.Check(len(suggestions) > 0, Equals, true)
+}
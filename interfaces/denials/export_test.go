@@ -0,0 +1,37 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials
+
+import (
+	"github.com/snapcore/snapd/testutil"
+)
+
+var (
+	AaGlobToRegexp       = aaGlobToRegexp
+	AaRuleAllows         = aaRuleAllows
+	SeccompSnippetAllows = seccompSnippetAllows
+	NativeAuditArch      = nativeAuditArch
+)
+
+func MockGoarch(arch string) (restore func()) {
+	restore = testutil.Backup(&goarch)
+	goarch = arch
+	return restore
+}
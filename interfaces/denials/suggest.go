@@ -0,0 +1,126 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials
+
+import (
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/apparmor"
+	"github.com/snapcore/snapd/interfaces/seccomp"
+	"github.com/snapcore/snapd/snap"
+)
+
+// Suggestion is an interface which would allow a denied access.
+type Suggestion struct {
+	Interface string
+	// Plug is the name of the plug of the snap with that interface, or
+	// the empty string if the snap does not have a suitable plug.
+	Plug string
+	// Connected is set when the plug is already connected.
+	Connected bool
+}
+
+// systemSnapNames are the names of the snaps which can provide the implicit
+// slots of the system, in order of preference.
+var systemSnapNames = []string{"snapd", "core", "ubuntu-core"}
+
+// systemSlot returns the slot of the system with the given interface, if any.
+func systemSlot(repo *interfaces.Repository, ifaceName string) *snap.SlotInfo {
+	for _, name := range systemSnapNames {
+		for _, slot := range repo.Slots(name) {
+			if slot.Interface == ifaceName {
+				return slot
+			}
+		}
+	}
+	return nil
+}
+
+// allows returns whether connecting the given plug to the given slot would
+// allow the access which was denied.
+func allows(iface interfaces.Interface, appSet *interfaces.SnapAppSet, plug *snap.PlugInfo, slot *snap.SlotInfo, d *Denial) bool {
+	slotAppSet, err := interfaces.NewSnapAppSet(slot.Snap, nil)
+	if err != nil {
+		return false
+	}
+	cplug := interfaces.NewConnectedPlug(plug, appSet, nil, nil)
+	cslot := interfaces.NewConnectedSlot(slot, slotAppSet, nil, nil)
+
+	switch d.Kind {
+	case KindAppArmor:
+		spec := apparmor.NewSpecification(appSet)
+		if err := spec.AddConnectedPlug(iface, cplug, cslot); err != nil {
+			return false
+		}
+		return aaSnippetAllows(spec.SnippetForTag(d.Label), d, appSet.Info().SnapName())
+	case KindSeccomp:
+		spec := seccomp.NewSpecification(appSet)
+		if err := spec.AddConnectedPlug(iface, cplug, cslot); err != nil {
+			return false
+		}
+		return seccompSnippetAllows(spec.SnippetForTag(d.Label), d)
+	}
+	return false
+}
+
+// Suggest returns the interfaces, among those for which the system provides
+// a slot, which would allow the access denied to the given snap. The plugs
+// of the snap are preferred over plugs the snap would need to declare.
+func Suggest(repo *interfaces.Repository, appSet *interfaces.SnapAppSet, d *Denial) []Suggestion {
+	info := appSet.Info()
+	if d.Snap() != info.InstanceName() {
+		return nil
+	}
+
+	var suggestions []Suggestion
+	for _, iface := range repo.AllInterfaces() {
+		slot := systemSlot(repo, iface.Name())
+		if slot == nil {
+			continue
+		}
+
+		var suggestion *Suggestion
+		for _, plug := range repo.Plugs(info.InstanceName()) {
+			if plug.Interface != iface.Name() || !allows(iface, appSet, plug, slot, d) {
+				continue
+			}
+			conns, _ := repo.Connected(info.InstanceName(), plug.Name)
+			suggestion = &Suggestion{Interface: iface.Name(), Plug: plug.Name, Connected: len(conns) > 0}
+			if suggestion.Connected {
+				break
+			}
+		}
+		if suggestion == nil {
+			// a plug with default attributes, bound to all apps and hooks
+			plug := &snap.PlugInfo{
+				Snap:      info,
+				Name:      iface.Name(),
+				Interface: iface.Name(),
+				Apps:      info.Apps,
+				Unscoped:  true,
+			}
+			if interfaces.BeforePreparePlug(iface, plug) != nil || !allows(iface, appSet, plug, slot, d) {
+				continue
+			}
+			suggestion = &Suggestion{Interface: iface.Name()}
+		}
+		suggestions = append(suggestions, *suggestion)
+	}
+	return suggestions
+}
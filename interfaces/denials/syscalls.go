@@ -0,0 +1,123 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package denials
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// nativeAuditArch is the audit architecture of native system calls, as
+// logged by seccomp, indexed by GOARCH.
+var nativeAuditArch = map[string]string{
+	"386":     "40000003",
+	"amd64":   "c000003e",
+	"arm":     "40000028",
+	"arm64":   "c00000b7",
+	"ppc64le": "c0000015",
+	"riscv64": "c00000f3",
+	"s390x":   "80000016",
+}
+
+// syscallNumbers are the native numbers of the system calls which the
+// seccomp snippets of interfaces typically allow.
+var syscallNumbers = map[string]uintptr{
+	"acct":               unix.SYS_ACCT,
+	"add_key":            unix.SYS_ADD_KEY,
+	"adjtimex":           unix.SYS_ADJTIMEX,
+	"bpf":                unix.SYS_BPF,
+	"chroot":             unix.SYS_CHROOT,
+	"clock_settime":      unix.SYS_CLOCK_SETTIME,
+	"delete_module":      unix.SYS_DELETE_MODULE,
+	"fanotify_init":      unix.SYS_FANOTIFY_INIT,
+	"fchmodat":           unix.SYS_FCHMODAT,
+	"fchown":             unix.SYS_FCHOWN,
+	"fchownat":           unix.SYS_FCHOWNAT,
+	"finit_module":       unix.SYS_FINIT_MODULE,
+	"fsmount":            unix.SYS_FSMOUNT,
+	"fsopen":             unix.SYS_FSOPEN,
+	"init_module":        unix.SYS_INIT_MODULE,
+	"ioprio_get":         unix.SYS_IOPRIO_GET,
+	"ioprio_set":         unix.SYS_IOPRIO_SET,
+	"kcmp":               unix.SYS_KCMP,
+	"kexec_load":         unix.SYS_KEXEC_LOAD,
+	"keyctl":             unix.SYS_KEYCTL,
+	"mbind":              unix.SYS_MBIND,
+	"mknodat":            unix.SYS_MKNODAT,
+	"mount":              unix.SYS_MOUNT,
+	"mount_setattr":      unix.SYS_MOUNT_SETATTR,
+	"move_mount":         unix.SYS_MOVE_MOUNT,
+	"move_pages":         unix.SYS_MOVE_PAGES,
+	"name_to_handle_at":  unix.SYS_NAME_TO_HANDLE_AT,
+	"open_by_handle_at":  unix.SYS_OPEN_BY_HANDLE_AT,
+	"open_tree":          unix.SYS_OPEN_TREE,
+	"perf_event_open":    unix.SYS_PERF_EVENT_OPEN,
+	"personality":        unix.SYS_PERSONALITY,
+	"pivot_root":         unix.SYS_PIVOT_ROOT,
+	"process_vm_readv":   unix.SYS_PROCESS_VM_READV,
+	"process_vm_writev":  unix.SYS_PROCESS_VM_WRITEV,
+	"ptrace":             unix.SYS_PTRACE,
+	"quotactl":           unix.SYS_QUOTACTL,
+	"reboot":             unix.SYS_REBOOT,
+	"request_key":        unix.SYS_REQUEST_KEY,
+	"sched_setattr":      unix.SYS_SCHED_SETATTR,
+	"sched_setscheduler": unix.SYS_SCHED_SETSCHEDULER,
+	"set_mempolicy":      unix.SYS_SET_MEMPOLICY,
+	"setdomainname":      unix.SYS_SETDOMAINNAME,
+	"setfsgid":           unix.SYS_SETFSGID,
+	"setfsuid":           unix.SYS_SETFSUID,
+	"setgid":             unix.SYS_SETGID,
+	"setgroups":          unix.SYS_SETGROUPS,
+	"sethostname":        unix.SYS_SETHOSTNAME,
+	"setns":              unix.SYS_SETNS,
+	"setpriority":        unix.SYS_SETPRIORITY,
+	"setresgid":          unix.SYS_SETRESGID,
+	"setresuid":          unix.SYS_SETRESUID,
+	"settimeofday":       unix.SYS_SETTIMEOFDAY,
+	"setuid":             unix.SYS_SETUID,
+	"swapoff":            unix.SYS_SWAPOFF,
+	"swapon":             unix.SYS_SWAPON,
+	"syslog":             unix.SYS_SYSLOG,
+	"umount2":            unix.SYS_UMOUNT2,
+	"unshare":            unix.SYS_UNSHARE,
+	"userfaultfd":        unix.SYS_USERFAULTFD,
+}
+
+var syscallNames map[uintptr]string
+
+func init() {
+	syscallNames = make(map[uintptr]string, len(syscallNumbers))
+	for name, nr := range syscallNumbers {
+		syscallNames[nr] = name
+	}
+}
+
+var goarch = runtime.GOARCH
+
+// syscallName returns the name of the native system call with the given
+// number, for the audit architecture as logged by seccomp.
+func syscallName(arch string, nr uintptr) (string, bool) {
+	if arch != nativeAuditArch[goarch] {
+		return "", false
+	}
+	name, ok := syscallNames[nr]
+	return name, ok
+}
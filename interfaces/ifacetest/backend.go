@@ -129,3 +129,18 @@ func (b *TestSecurityBackendDiscardingLate) RemoveLate(snapName string, rev snap
 	}
 	return b.RemoveLateCallback(snapName, rev, typ)
 }
+
+// TestSecurityBackendPreview implements PreviewProfiles on top of TestSecurityBackend.
+type TestSecurityBackendPreview struct {
+	TestSecurityBackend
+
+	// PreviewProfilesCallback is a callback that is optionally called in PreviewProfiles
+	PreviewProfilesCallback func(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (map[string][]byte, error)
+}
+
+func (b *TestSecurityBackendPreview) PreviewProfiles(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (map[string][]byte, error) {
+	if b.PreviewProfilesCallback == nil {
+		return nil, nil
+	}
+	return b.PreviewProfilesCallback(appSet, opts, repo)
+}
@@ -324,3 +324,33 @@ func (s *BackendSuite) RemoveSnap(c *C, snapInfo *snap.Info) {
 	c.Assert(err, IsNil)
 	s.Repo.RemoveSnap(snapInfo.InstanceName())
 }
+
+// PreviewAndInstallSnap previews the security artefacts of a snap, checks
+// that none of them was written, then "installs" the snap and checks that
+// Setup wrote exactly the previewed content.
+func (s *BackendSuite) PreviewAndInstallSnap(c *C, opts interfaces.ConfinementOptions, snapYaml string, revision int) map[string][]byte {
+	snapInfo := snaptest.MockInfo(c, snapYaml, &snap.SideInfo{
+		Revision: snap.R(revision),
+	})
+
+	appSet, err := interfaces.NewSnapAppSet(snapInfo, nil)
+	c.Assert(err, IsNil)
+
+	err = s.Repo.AddAppSet(appSet)
+	c.Assert(err, IsNil)
+
+	previewer, ok := s.Backend.(interfaces.SecurityBackendPreview)
+	c.Assert(ok, Equals, true)
+	preview, err := previewer.PreviewProfiles(appSet, opts, s.Repo)
+	c.Assert(err, IsNil)
+	for path := range preview {
+		c.Check(path, testutil.FileAbsent)
+	}
+
+	err = s.Backend.Setup(appSet, opts, s.Repo, s.meas)
+	c.Assert(err, IsNil)
+	for path, content := range preview {
+		c.Check(path, testutil.FileEquals, string(content))
+	}
+	return preview
+}
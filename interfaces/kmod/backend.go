@@ -140,6 +140,24 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 	return nil
 }
 
+// PreviewProfiles returns the kernel module configuration files that Setup
+// would write for the given snap, without writing them or loading modules.
+func (b *Backend) PreviewProfiles(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (map[string][]byte, error) {
+	spec, err := repo.SnapSpecification(b.Name(), appSet, opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot obtain kmod specification for snap %q: %s", appSet.InstanceName(), err)
+	}
+	preview := make(map[string][]byte)
+	modules, _ := deriveContent(spec.(*Specification), appSet)
+	if err := interfaces.PreviewContent(preview, dirs.SnapKModModulesDir, modules); err != nil {
+		return nil, err
+	}
+	if err := interfaces.PreviewContent(preview, dirs.SnapKModModprobeDir, prepareModprobeDirContents(spec.(*Specification), appSet)); err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
 // Remove removes modules config file specific to a given snap.
 //
 // This method should be called after removing a snap.
@@ -199,3 +199,16 @@ func (s *backendSuite) TestSecurityIsStable(c *C) {
 func (s *backendSuite) TestSandboxFeatures(c *C) {
 	c.Assert(s.Backend.SandboxFeatures(), DeepEquals, []string{"mediated-modprobe"})
 }
+
+func (s *backendSuite) TestPreviewProfiles(c *C) {
+	s.Iface.KModPermanentSlotCallback = func(spec *kmod.Specification, slot *snap.SlotInfo) error {
+		spec.AddModule("module1")
+		spec.DisallowModule("module2")
+		return nil
+	}
+	preview := s.PreviewAndInstallSnap(c, interfaces.ConfinementOptions{}, ifacetest.SambaYamlV1, 0)
+	c.Check(preview, DeepEquals, map[string][]byte{
+		filepath.Join(dirs.SnapKModModulesDir, "snap.samba.conf"):  []byte("# This file is automatically generated.\nmodule1\n"),
+		filepath.Join(dirs.SnapKModModprobeDir, "snap.samba.conf"): []byte("# Generated by snapd. Do not edit\n\nblacklist module2\n"),
+	})
+}
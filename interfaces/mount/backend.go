@@ -99,6 +99,26 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 	return nil
 }
 
+// PreviewProfiles returns the mount profiles that Setup would write for the
+// given snap, without writing them or updating its mount namespace.
+func (b *Backend) PreviewProfiles(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (map[string][]byte, error) {
+	spec, err := repo.SnapSpecification(b.Name(), appSet, opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot obtain mount security snippets for snap %q: %s", appSet.InstanceName(), err)
+	}
+
+	snapInfo := appSet.Info()
+
+	spec.(*Specification).AddOvername(snapInfo)
+	spec.(*Specification).AddLayout(snapInfo)
+	spec.(*Specification).AddExtraLayouts(opts.ExtraLayouts)
+	preview := make(map[string][]byte)
+	if err := interfaces.PreviewContent(preview, dirs.SnapMountPolicyDir, deriveContent(spec.(*Specification), snapInfo)); err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
 // Remove removes mount configuration files of a given snap.
 //
 // This method should be called after removing a snap.
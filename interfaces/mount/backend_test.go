@@ -421,3 +421,15 @@ func (s *backendSuite) TestSetupUpdatesErrorDiscardsNs(c *C) {
 	got := strings.Split(string(content), "\n")
 	c.Check(got, testutil.DeepUnsortedMatches, expected)
 }
+
+func (s *backendSuite) TestPreviewProfiles(c *C) {
+	fsEntry := osutil.MountEntry{Name: "/src-1", Dir: "/dst-1", Type: "none", Options: []string{"bind", "ro"}}
+	s.Iface.MountPermanentPlugCallback = func(spec *mount.Specification, plug *snap.PlugInfo) error {
+		return spec.AddMountEntry(fsEntry)
+	}
+	// the namespace of the snap is not updated when previewing
+	preview := s.PreviewAndInstallSnap(c, interfaces.ConfinementOptions{}, mockSnapYaml, 0)
+	c.Check(preview, DeepEquals, map[string][]byte{
+		filepath.Join(dirs.SnapMountPolicyDir, "snap.snap-name.fstab"): []byte(fsEntry.String() + "\n"),
+	})
+}
@@ -151,19 +151,19 @@ func checkPlugConnectionConstraints1(connc *ConnectCandidate, constraints *asser
 	return nil
 }
 
-func checkPlugConnectionAltConstraints(connc *ConnectCandidate, altConstraints []*asserts.PlugConnectionConstraints) (*asserts.PlugConnectionConstraints, error) {
+func checkPlugConnectionAltConstraints(connc *ConnectCandidate, altConstraints []*asserts.PlugConnectionConstraints) (*asserts.PlugConnectionConstraints, int, error) {
 	var firstErr error
 	// OR of constraints
-	for _, constraints := range altConstraints {
+	for i, constraints := range altConstraints {
 		err := checkPlugConnectionConstraints1(connc, constraints)
 		if err == nil {
-			return constraints, nil
+			return constraints, i, nil
 		}
 		if firstErr == nil {
 			firstErr = err
 		}
 	}
-	return nil, firstErr
+	return nil, -1, firstErr
 }
 
 func checkSlotConnectionConstraints1(connc *ConnectCandidate, constraints *asserts.SlotConnectionConstraints) error {
@@ -207,19 +207,19 @@ func checkSlotConnectionConstraints1(connc *ConnectCandidate, constraints *asser
 	return nil
 }
 
-func checkSlotConnectionAltConstraints(connc *ConnectCandidate, altConstraints []*asserts.SlotConnectionConstraints) (*asserts.SlotConnectionConstraints, error) {
+func checkSlotConnectionAltConstraints(connc *ConnectCandidate, altConstraints []*asserts.SlotConnectionConstraints) (*asserts.SlotConnectionConstraints, int, error) {
 	var firstErr error
 	// OR of constraints
-	for _, constraints := range altConstraints {
+	for i, constraints := range altConstraints {
 		err := checkSlotConnectionConstraints1(connc, constraints)
 		if err == nil {
-			return constraints, nil
+			return constraints, i, nil
 		}
 		if firstErr == nil {
 			firstErr = err
 		}
 	}
-	return nil, firstErr
+	return nil, -1, firstErr
 }
 
 func checkSnapTypeSlotInstallationConstraints1(slot *snap.SlotInfo, constraints *asserts.SlotInstallationConstraints) error {
@@ -183,79 +183,165 @@ func (connc *ConnectCandidate) SlotPublisherID() string {
 	return "" // never a valid publisher-id
 }
 
-func (connc *ConnectCandidate) checkPlugRule(kind string, rule *asserts.PlugRule, snapRule bool) (interfaces.SideArity, error) {
+// ConnectionExplanation describes which rule of which declaration decided
+// whether a connection or an auto-connection is allowed, and why.
+type ConnectionExplanation struct {
+	// Kind is either "connection" or "auto-connection".
+	Kind string
+	// Declaration is either "snap-declaration" or "base-declaration", or
+	// empty if no rule applies to the interface.
+	Declaration string
+	// SnapName is the name of the snap whose snap-declaration has the
+	// deciding rule.
+	SnapName string
+	// Side is either "plug" or "slot", the side the deciding rule is for.
+	Side string
+	// Constraint is the constraint of the rule which decided, for example
+	// "deny-auto-connection" or "allow-connection".
+	Constraint string
+	// Alternative is the index of the alternative of Constraint which
+	// matched, or -1 if none did.
+	Alternative int
+	// Mismatch describes why the allow constraints did not match, for
+	// example which attribute constraint failed.
+	Mismatch string
+	// Err is the error the check results in, nil if the connection is
+	// allowed.
+	Err error
+
+	arity interfaces.SideArity
+}
+
+// Allowed returns whether the connection is allowed.
+func (e *ConnectionExplanation) Allowed() bool {
+	return e.Err == nil
+}
+
+func (connc *ConnectCandidate) explainPlugRule(expl *ConnectionExplanation, rule *asserts.PlugRule, snapRule bool) {
 	context := ""
+	expl.Declaration = "base-declaration"
 	if snapRule {
 		context = fmt.Sprintf(" for %q snap", connc.PlugSnapDeclaration.SnapName())
+		expl.Declaration = "snap-declaration"
+		expl.SnapName = connc.PlugSnapDeclaration.SnapName()
 	}
+	expl.Side = "plug"
 	denyConst := rule.DenyConnection
 	allowConst := rule.AllowConnection
-	if kind == "auto-connection" {
+	denyName, allowName := "deny-connection", "allow-connection"
+	if expl.Kind == "auto-connection" {
 		denyConst = rule.DenyAutoConnection
 		allowConst = rule.AllowAutoConnection
+		denyName, allowName = "deny-auto-connection", "allow-auto-connection"
 	}
-	if _, err := checkPlugConnectionAltConstraints(connc, denyConst); err == nil {
-		return nil, fmt.Errorf("%s denied by plug rule of interface %q%s", kind, connc.Plug.Interface(), context)
+	if _, i, err := checkPlugConnectionAltConstraints(connc, denyConst); err == nil {
+		expl.Constraint, expl.Alternative = denyName, i
+		expl.Err = fmt.Errorf("%s denied by plug rule of interface %q%s", expl.Kind, connc.Plug.Interface(), context)
+		return
 	}
 
-	allowedConstraints, err := checkPlugConnectionAltConstraints(connc, allowConst)
+	expl.Constraint = allowName
+	allowedConstraints, i, err := checkPlugConnectionAltConstraints(connc, allowConst)
 	if err != nil {
-		return nil, fmt.Errorf("%s not allowed by plug rule of interface %q%s", kind, connc.Plug.Interface(), context)
+		expl.Alternative, expl.Mismatch = -1, err.Error()
+		expl.Err = fmt.Errorf("%s not allowed by plug rule of interface %q%s", expl.Kind, connc.Plug.Interface(), context)
+		return
 	}
-	return sideArity{allowedConstraints.SlotsPerPlug}, nil
+	expl.Alternative = i
+	expl.arity = sideArity{allowedConstraints.SlotsPerPlug}
 }
 
-func (connc *ConnectCandidate) checkSlotRule(kind string, rule *asserts.SlotRule, snapRule bool) (interfaces.SideArity, error) {
+func (connc *ConnectCandidate) explainSlotRule(expl *ConnectionExplanation, rule *asserts.SlotRule, snapRule bool) {
 	context := ""
+	expl.Declaration = "base-declaration"
 	if snapRule {
 		context = fmt.Sprintf(" for %q snap", connc.SlotSnapDeclaration.SnapName())
+		expl.Declaration = "snap-declaration"
+		expl.SnapName = connc.SlotSnapDeclaration.SnapName()
 	}
+	expl.Side = "slot"
 	denyConst := rule.DenyConnection
 	allowConst := rule.AllowConnection
-	if kind == "auto-connection" {
+	denyName, allowName := "deny-connection", "allow-connection"
+	if expl.Kind == "auto-connection" {
 		denyConst = rule.DenyAutoConnection
 		allowConst = rule.AllowAutoConnection
+		denyName, allowName = "deny-auto-connection", "allow-auto-connection"
 	}
-	if _, err := checkSlotConnectionAltConstraints(connc, denyConst); err == nil {
-		return nil, fmt.Errorf("%s denied by slot rule of interface %q%s", kind, connc.Plug.Interface(), context)
+	if _, i, err := checkSlotConnectionAltConstraints(connc, denyConst); err == nil {
+		expl.Constraint, expl.Alternative = denyName, i
+		expl.Err = fmt.Errorf("%s denied by slot rule of interface %q%s", expl.Kind, connc.Plug.Interface(), context)
+		return
 	}
 
-	allowedConstraints, err := checkSlotConnectionAltConstraints(connc, allowConst)
+	expl.Constraint = allowName
+	allowedConstraints, i, err := checkSlotConnectionAltConstraints(connc, allowConst)
 	if err != nil {
-		return nil, fmt.Errorf("%s not allowed by slot rule of interface %q%s", kind, connc.Plug.Interface(), context)
+		expl.Alternative, expl.Mismatch = -1, err.Error()
+		expl.Err = fmt.Errorf("%s not allowed by slot rule of interface %q%s", expl.Kind, connc.Plug.Interface(), context)
+		return
 	}
-	return sideArity{allowedConstraints.SlotsPerPlug}, nil
+	expl.Alternative = i
+	expl.arity = sideArity{allowedConstraints.SlotsPerPlug}
 }
 
-func (connc *ConnectCandidate) check(kind string) (interfaces.SideArity, error) {
+func (connc *ConnectCandidate) explain(kind string) *ConnectionExplanation {
+	expl := &ConnectionExplanation{Kind: kind, Alternative: -1}
+
 	baseDecl := connc.BaseDeclaration
 	if baseDecl == nil {
-		return nil, fmt.Errorf("internal error: improperly initialized ConnectCandidate")
+		expl.Err = fmt.Errorf("internal error: improperly initialized ConnectCandidate")
+		return expl
 	}
 
 	iface := connc.Plug.Interface()
 
 	if connc.Slot.Interface() != iface {
-		return nil, fmt.Errorf("cannot connect mismatched plug interface %q to slot interface %q", iface, connc.Slot.Interface())
+		expl.Err = fmt.Errorf("cannot connect mismatched plug interface %q to slot interface %q", iface, connc.Slot.Interface())
+		return expl
 	}
 
 	if plugDecl := connc.PlugSnapDeclaration; plugDecl != nil {
 		if rule := plugDecl.PlugRule(iface); rule != nil {
-			return connc.checkPlugRule(kind, rule, true)
+			connc.explainPlugRule(expl, rule, true)
+			return expl
 		}
 	}
 	if slotDecl := connc.SlotSnapDeclaration; slotDecl != nil {
 		if rule := slotDecl.SlotRule(iface); rule != nil {
-			return connc.checkSlotRule(kind, rule, true)
+			connc.explainSlotRule(expl, rule, true)
+			return expl
 		}
 	}
 	if rule := baseDecl.PlugRule(iface); rule != nil {
-		return connc.checkPlugRule(kind, rule, false)
+		connc.explainPlugRule(expl, rule, false)
+		return expl
 	}
 	if rule := baseDecl.SlotRule(iface); rule != nil {
-		return connc.checkSlotRule(kind, rule, false)
+		connc.explainSlotRule(expl, rule, false)
+		return expl
+	}
+	return expl
+}
+
+func (connc *ConnectCandidate) check(kind string) (interfaces.SideArity, error) {
+	expl := connc.explain(kind)
+	if expl.Err != nil {
+		return nil, expl.Err
 	}
-	return nil, nil
+	return expl.arity, nil
+}
+
+// Explain describes which rule decides whether the connection is allowed,
+// as checked by Check.
+func (connc *ConnectCandidate) Explain() *ConnectionExplanation {
+	return connc.explain("connection")
+}
+
+// ExplainAutoConnect describes which rule decides whether the connection
+// is allowed to auto-connect, as checked by CheckAutoConnect.
+func (connc *ConnectCandidate) ExplainAutoConnect() *ConnectionExplanation {
+	return connc.explain("auto-connection")
 }
 
 // Check checks whether the connection is allowed.
@@ -1146,6 +1146,76 @@ func (s *policySuite) TestSnapDeclAllowDenyAutoConnection(c *C) {
 	}
 }
 
+func (s *policySuite) TestExplainConnection(c *C) {
+	tests := []struct {
+		iface string
+		auto  bool
+		decls bool
+
+		declaration string
+		snapName    string
+		side        string
+		constraint  string
+		alternative int
+		mismatch    string
+		err         string
+	}{
+		{iface: "random", alternative: -1},
+		{iface: "base-plug-allow", declaration: "base-declaration", side: "plug", constraint: "allow-connection"},
+		{iface: "base-plug-deny", declaration: "base-declaration", side: "plug", constraint: "deny-connection",
+			err: `connection denied by plug rule of interface "base-plug-deny"`},
+		{iface: "base-plug-not-allow-slots", declaration: "base-declaration", side: "plug", constraint: "allow-connection", alternative: -1,
+			mismatch: `attribute "s" has constraints but is unset`, err: `connection not allowed by plug rule of interface "base-plug-not-allow-slots"`},
+		{iface: "plug-or-p2-s2", declaration: "base-declaration", side: "plug", constraint: "allow-connection", alternative: 1},
+		{iface: "slot-or-p1-s2", declaration: "base-declaration", side: "slot", constraint: "allow-connection", alternative: -1,
+			mismatch: `.*`, err: `connection not allowed by slot rule of interface "slot-or"`},
+		{iface: "auto-base-plug-deny", auto: true, declaration: "base-declaration", side: "plug", constraint: "deny-auto-connection",
+			err: `auto-connection denied by plug rule of interface "auto-base-plug-deny"`},
+		{iface: "snap-slot-deny", decls: true, declaration: "snap-declaration", snapName: "slot-snap", side: "slot", constraint: "deny-connection",
+			err: `connection denied by slot rule of interface "snap-slot-deny" for "slot-snap" snap`},
+	}
+
+	for _, t := range tests {
+		cand := policy.ConnectCandidate{
+			Plug:            interfaces.NewConnectedPlug(s.plugSnap.Plugs[t.iface], s.plugAppSet, nil, nil),
+			Slot:            interfaces.NewConnectedSlot(s.slotSnap.Slots[t.iface], s.slotAppSet, nil, nil),
+			BaseDeclaration: s.baseDecl,
+		}
+		if t.decls {
+			cand.PlugSnapDeclaration = s.plugDecl
+			cand.SlotSnapDeclaration = s.slotDecl
+		}
+
+		var expl *policy.ConnectionExplanation
+		kind := "connection"
+		if t.auto {
+			expl = cand.ExplainAutoConnect()
+			kind = "auto-connection"
+		} else {
+			expl = cand.Explain()
+		}
+		comment := Commentf("%s", t.iface)
+		c.Check(expl.Kind, Equals, kind, comment)
+		c.Check(expl.Declaration, Equals, t.declaration, comment)
+		c.Check(expl.SnapName, Equals, t.snapName, comment)
+		c.Check(expl.Side, Equals, t.side, comment)
+		c.Check(expl.Constraint, Equals, t.constraint, comment)
+		c.Check(expl.Alternative, Equals, t.alternative, comment)
+		if t.mismatch == "" {
+			c.Check(expl.Mismatch, Equals, "", comment)
+		} else {
+			c.Check(expl.Mismatch, Matches, t.mismatch, comment)
+		}
+		if t.err == "" {
+			c.Check(expl.Allowed(), Equals, true, comment)
+			c.Check(expl.Err, IsNil, comment)
+		} else {
+			c.Check(expl.Allowed(), Equals, false, comment)
+			c.Check(expl.Err, ErrorMatches, t.err, comment)
+		}
+	}
+}
+
 func (s *policySuite) TestSnapTypeCheckConnection(c *C) {
 	gadgetAppSet := ifacetest.MockInfoAndAppSet(c, `
 name: gadget
@@ -21,7 +21,9 @@ package prompting
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
+	"strings"
 
 	prompting_errors "github.com/snapcore/snapd/interfaces/prompting/errors"
 	"github.com/snapcore/snapd/interfaces/prompting/patterns"
@@ -45,7 +47,10 @@ func (c *Constraints) ValidateForInterface(iface string) error {
 	if c.PathPattern == nil {
 		return prompting_errors.NewInvalidPathPatternError("", "no path pattern")
 	}
-	return c.validatePermissions(iface)
+	if err := c.validatePermissions(iface); err != nil {
+		return err
+	}
+	return c.validatePathPattern(iface)
 }
 
 // validatePermissions checks that the permissions for the given constraints
@@ -82,6 +87,52 @@ func (c *Constraints) validatePermissions(iface string) error {
 	return nil
 }
 
+// ValidatePermission returns nil if the given permission is available for the
+// given interface, otherwise returns an error.
+func ValidatePermission(iface string, permission string) error {
+	availablePerms, ok := interfacePermissionsAvailable[iface]
+	if !ok {
+		return prompting_errors.NewInvalidInterfaceError(iface, availableInterfaces())
+	}
+	if !strutil.ListContains(availablePerms, permission) {
+		return prompting_errors.NewInvalidPermissionsError(iface, []string{permission}, availablePerms)
+	}
+	return nil
+}
+
+// validatePathPattern checks that every variant of the path pattern for the
+// given constraints is restricted to the paths which may be accessed through
+// the given interface. Interfaces without path prefixes are unrestricted.
+func (c *Constraints) validatePathPattern(iface string) error {
+	prefixes, ok := interfacePathPrefixes[iface]
+	if !ok {
+		return nil
+	}
+	var invalidVariant string
+	c.PathPattern.RenderAllVariants(func(index int, variant patterns.PatternVariant) {
+		if invalidVariant != "" {
+			return
+		}
+		if !hasAnyPrefix(variant.String(), prefixes) {
+			invalidVariant = variant.String()
+		}
+	})
+	if invalidVariant != "" {
+		reason := fmt.Sprintf("pattern for the %s interface must begin with one of %s", iface, strutil.Quoted(prefixes))
+		return prompting_errors.NewInvalidPathPatternError(c.PathPattern.String(), reason)
+	}
+	return nil
+}
+
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // Match returns true if the constraints match the given path, otherwise false.
 //
 // If the constraints or path are invalid, returns an error.
@@ -112,7 +163,19 @@ var (
 	// List of permissions available for each interface. This also defines the
 	// order in which the permissions should be presented.
 	interfacePermissionsAvailable = map[string][]string{
-		"home": {"read", "write", "execute"},
+		"home":            {"read", "write", "execute"},
+		"personal-files":  {"read", "write", "execute"},
+		"removable-media": {"read", "write", "execute"},
+		"camera":          {"access"},
+		"audio-record":    {"access"},
+	}
+
+	// Path prefixes to which path patterns for each interface are restricted.
+	// Interfaces which are not included may be used with any path pattern.
+	interfacePathPrefixes = map[string][]string{
+		"removable-media": {"/media/", "/run/media/", "/mnt/"},
+		"camera":          {"/dev/video"},
+		"audio-record":    {"/dev/snd/"},
 	}
 
 	// A mapping from interfaces which support AppArmor file permissions to
@@ -127,9 +190,109 @@ var (
 			"write":   notify.AA_MAY_WRITE | notify.AA_MAY_APPEND | notify.AA_MAY_CREATE | notify.AA_MAY_DELETE | notify.AA_MAY_RENAME | notify.AA_MAY_SETATTR | notify.AA_MAY_CHMOD | notify.AA_MAY_LOCK | notify.AA_MAY_LINK,
 			"execute": notify.AA_MAY_EXEC | notify.AA_EXEC_MMAP,
 		},
+		"personal-files": {
+			"read":    notify.AA_MAY_READ | notify.AA_MAY_GETATTR,
+			"write":   notify.AA_MAY_WRITE | notify.AA_MAY_APPEND | notify.AA_MAY_CREATE | notify.AA_MAY_DELETE | notify.AA_MAY_RENAME | notify.AA_MAY_SETATTR | notify.AA_MAY_CHMOD | notify.AA_MAY_LOCK | notify.AA_MAY_LINK,
+			"execute": notify.AA_MAY_EXEC | notify.AA_EXEC_MMAP,
+		},
+		"removable-media": {
+			"read":    notify.AA_MAY_READ | notify.AA_MAY_GETATTR,
+			"write":   notify.AA_MAY_WRITE | notify.AA_MAY_APPEND | notify.AA_MAY_CREATE | notify.AA_MAY_DELETE | notify.AA_MAY_RENAME | notify.AA_MAY_SETATTR | notify.AA_MAY_CHMOD | notify.AA_MAY_LOCK | notify.AA_MAY_LINK,
+			"execute": notify.AA_MAY_EXEC | notify.AA_EXEC_MMAP,
+		},
+		// Devices are either accessible or not, so any file operation on the
+		// device node is covered by a single permission.
+		"camera": {
+			"access": notify.AA_MAY_READ | notify.AA_MAY_WRITE | notify.AA_MAY_APPEND | notify.AA_MAY_GETATTR | notify.AA_MAY_SETATTR | notify.AA_MAY_LOCK,
+		},
+		"audio-record": {
+			"access": notify.AA_MAY_READ | notify.AA_MAY_WRITE | notify.AA_MAY_APPEND | notify.AA_MAY_GETATTR | notify.AA_MAY_SETATTR | notify.AA_MAY_LOCK,
+		},
 	}
 )
 
+// InterfaceForPath returns the interface through which a request to access
+// the given path was mediated by AppArmor prompting.
+//
+// The kernel does not report which interface granted the rule which triggered
+// the request, so the interface is derived from the paths which the prompting
+// interfaces connected to the plugs of the snap grant access to. These are
+// given as a map from interface name to the paths listed in the attributes of
+// the plugs of that interface, if any, with $HOME standing for the given home
+// directory of the user. If none or more than one of the connected interfaces
+// grant access to the path, an error is returned.
+func InterfaceForPath(path string, homeDir string, connected map[string][]string) (string, error) {
+	var candidates []string
+	for iface, plugPaths := range connected {
+		if _, ok := interfaceFilePermissionsMaps[iface]; !ok {
+			continue
+		}
+		var covered bool
+		switch iface {
+		case "home":
+			covered = isInHomeInterface(path, homeDir)
+		case "personal-files":
+			covered = isInPlugPaths(path, homeDir, plugPaths)
+		default:
+			covered = hasAnyPrefix(path, interfacePathPrefixes[iface])
+		}
+		if covered {
+			candidates = append(candidates, iface)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("cannot find a connected interface granting access to %q", path)
+	case 1:
+		return candidates[0], nil
+	}
+	sort.Strings(candidates)
+	return "", fmt.Errorf("cannot tell which interface granted access to %q: any of %s could have", path, strutil.Quoted(candidates))
+}
+
+// isInHomeInterface returns true if the given path is covered by the prompted
+// rules of the home interface, which grant access to the home directory of
+// the user except for hidden files and directories in its top level and for
+// the contents of the snap directory.
+func isInHomeInterface(path, homeDir string) bool {
+	homeDir = strings.TrimSuffix(homeDir, "/")
+	if homeDir == "" {
+		return false
+	}
+	if path == homeDir || path == homeDir+"/" {
+		return true
+	}
+	if !strings.HasPrefix(path, homeDir+"/") {
+		return false
+	}
+	components := strings.SplitN(strings.TrimPrefix(path, homeDir+"/"), "/", 2)
+	if strings.HasPrefix(components[0], ".") {
+		return false
+	}
+	if components[0] == "snap" && len(components) == 2 && components[1] != "" {
+		return false
+	}
+	return true
+}
+
+// isInPlugPaths returns true if the given path is, or is within, one of the
+// given plug paths, in which $HOME stands for the given home directory.
+func isInPlugPaths(path, homeDir string, plugPaths []string) bool {
+	for _, plugPath := range plugPaths {
+		if strings.HasPrefix(plugPath, "$HOME/") {
+			if homeDir == "" {
+				continue
+			}
+			plugPath = strings.TrimSuffix(homeDir, "/") + strings.TrimPrefix(plugPath, "$HOME")
+		}
+		plugPath = filepath.Clean(plugPath)
+		if path == plugPath || strings.HasPrefix(path, plugPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // availableInterfaces returns the list of supported interfaces.
 func availableInterfaces() []string {
 	interfaces := make([]string, 0, len(interfacePermissionsAvailable))
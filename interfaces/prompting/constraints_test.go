@@ -20,9 +20,12 @@
 package prompting_test
 
 import (
+	"errors"
+
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/interfaces/prompting"
+	prompting_errors "github.com/snapcore/snapd/interfaces/prompting/errors"
 	"github.com/snapcore/snapd/interfaces/prompting/patterns"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/sandbox/apparmor/notify"
@@ -45,6 +48,70 @@ func (s *constraintsSuite) TestConstraintsValidateForInterface(c *C) {
 	err = constraints.ValidateForInterface("home")
 	c.Check(err, IsNil)
 
+	// Happy for the other interfaces
+	for _, testCase := range []struct {
+		iface   string
+		pattern string
+		perms   []string
+	}{
+		{"personal-files", "/home/test/.config/foo/**", []string{"read", "write"}},
+		{"removable-media", "/media/test/usb/**", []string{"read"}},
+		{"removable-media", "/{run/media,mnt}/**", []string{"write"}},
+		{"camera", "/dev/video0", []string{"access"}},
+		{"audio-record", "/dev/snd/pcmC0D0c", []string{"access"}},
+	} {
+		pathPattern, err := patterns.ParsePathPattern(testCase.pattern)
+		c.Assert(err, IsNil)
+		constraints := &prompting.Constraints{
+			PathPattern: pathPattern,
+			Permissions: testCase.perms,
+		}
+		err = constraints.ValidateForInterface(testCase.iface)
+		c.Check(err, IsNil, Commentf("testCase: %+v", testCase))
+	}
+
+	// Path pattern not matching the interface
+	for _, testCase := range []struct {
+		iface   string
+		pattern string
+		perms   []string
+		errStr  string
+	}{
+		{
+			"removable-media",
+			"/home/test/foo",
+			[]string{"read"},
+			`invalid path pattern: pattern for the removable-media interface must begin with one of "/media/", "/run/media/", "/mnt/": "/home/test/foo"`,
+		},
+		{
+			"removable-media",
+			"/{media,home}/**",
+			[]string{"read"},
+			`invalid path pattern: pattern for the removable-media interface must begin with one of .*: "/{media,home}/\*\*"`,
+		},
+		{
+			"camera",
+			"/dev/snd/pcmC0D0c",
+			[]string{"access"},
+			`invalid path pattern: pattern for the camera interface must begin with one of "/dev/video": "/dev/snd/pcmC0D0c"`,
+		},
+		{
+			"audio-record",
+			"/**",
+			[]string{"access"},
+			`invalid path pattern: pattern for the audio-record interface must begin with one of "/dev/snd/": "/\*\*"`,
+		},
+	} {
+		pathPattern, err := patterns.ParsePathPattern(testCase.pattern)
+		c.Assert(err, IsNil)
+		constraints := &prompting.Constraints{
+			PathPattern: pathPattern,
+			Permissions: testCase.perms,
+		}
+		err = constraints.ValidateForInterface(testCase.iface)
+		c.Check(err, ErrorMatches, testCase.errStr, Commentf("testCase: %+v", testCase))
+	}
+
 	// Bad interface or permissions
 	cases := []struct {
 		iface  string
@@ -105,6 +172,16 @@ func (s *constraintsSuite) TestValidatePermissionsHappy(c *C) {
 			[]string{"write", "write", "write"},
 			[]string{"write"},
 		},
+		{
+			"removable-media",
+			[]string{"execute", "read"},
+			[]string{"read", "execute"},
+		},
+		{
+			"camera",
+			[]string{"access", "access"},
+			[]string{"access"},
+		},
 	}
 	for _, testCase := range cases {
 		constraints := prompting.Constraints{
@@ -142,6 +219,16 @@ func (s *constraintsSuite) TestValidatePermissionsUnhappy(c *C) {
 			[]string{},
 			"invalid permissions for home interface: permissions list empty",
 		},
+		{
+			"camera",
+			[]string{"read"},
+			`invalid permissions for camera interface: "read"`,
+		},
+		{
+			"audio-record",
+			[]string{"access", "write"},
+			`invalid permissions for audio-record interface: "write"`,
+		},
 	}
 	for _, testCase := range cases {
 		constraints := prompting.Constraints{
@@ -324,6 +411,20 @@ func (s *constraintsSuite) TestAvailablePermissions(c *C) {
 	c.Check(available, IsNil)
 }
 
+func (s *constraintsSuite) TestValidatePermission(c *C) {
+	for iface, perms := range prompting.InterfacePermissionsAvailable {
+		for _, perm := range perms {
+			c.Check(prompting.ValidatePermission(iface, perm), IsNil)
+		}
+	}
+	err := prompting.ValidatePermission("foo", "read")
+	c.Check(err, ErrorMatches, `invalid interface: "foo"`)
+	c.Check(errors.Is(err, prompting_errors.ErrUnsupportedValue), Equals, true)
+	err = prompting.ValidatePermission("camera", "read")
+	c.Check(err, ErrorMatches, `invalid permissions for camera interface: "read"`)
+	c.Check(errors.Is(err, prompting_errors.ErrUnsupportedValue), Equals, true)
+}
+
 func (s *constraintsSuite) TestAbstractPermissionsFromAppArmorPermissionsHappy(c *C) {
 	cases := []struct {
 		iface string
@@ -360,6 +461,26 @@ func (s *constraintsSuite) TestAbstractPermissionsFromAppArmorPermissionsHappy(c
 			notify.AA_MAY_EXEC | notify.AA_MAY_WRITE | notify.AA_MAY_READ,
 			[]string{"read", "write", "execute"},
 		},
+		{
+			"personal-files",
+			notify.AA_MAY_OPEN | notify.AA_MAY_READ,
+			[]string{"read"},
+		},
+		{
+			"removable-media",
+			notify.AA_MAY_WRITE | notify.AA_MAY_CREATE,
+			[]string{"write"},
+		},
+		{
+			"camera",
+			notify.AA_MAY_OPEN | notify.AA_MAY_READ | notify.AA_MAY_WRITE,
+			[]string{"access"},
+		},
+		{
+			"audio-record",
+			notify.AA_MAY_OPEN,
+			[]string{"access"},
+		},
 	}
 	for _, testCase := range cases {
 		perms, err := prompting.AbstractPermissionsFromAppArmorPermissions(testCase.iface, testCase.perms)
@@ -453,6 +574,11 @@ func (s *constraintsSuite) TestAbstractPermissionsToAppArmorPermissionsHappy(c *
 			[]string{"execute", "write", "read"},
 			notify.AA_MAY_OPEN | notify.AA_MAY_READ | notify.AA_MAY_GETATTR | notify.AA_MAY_EXEC | notify.AA_EXEC_MMAP | notify.AA_MAY_WRITE | notify.AA_MAY_APPEND | notify.AA_MAY_CREATE | notify.AA_MAY_DELETE | notify.AA_MAY_RENAME | notify.AA_MAY_SETATTR | notify.AA_MAY_CHMOD | notify.AA_MAY_LOCK | notify.AA_MAY_LINK,
 		},
+		{
+			"camera",
+			[]string{"access"},
+			notify.AA_MAY_OPEN | notify.AA_MAY_READ | notify.AA_MAY_WRITE | notify.AA_MAY_APPEND | notify.AA_MAY_GETATTR | notify.AA_MAY_SETATTR | notify.AA_MAY_LOCK,
+		},
 	}
 	for _, testCase := range cases {
 		ret, err := prompting.AbstractPermissionsToAppArmorPermissions(testCase.iface, testCase.list)
@@ -463,6 +589,97 @@ func (s *constraintsSuite) TestAbstractPermissionsToAppArmorPermissionsHappy(c *
 	}
 }
 
+func (s *constraintsSuite) TestInterfaceForPath(c *C) {
+	connected := map[string][]string{
+		"home":            nil,
+		"personal-files":  {"$HOME/.config/foo", "$HOME/Private/notes"},
+		"removable-media": nil,
+		"camera":          nil,
+		"audio-record":    nil,
+		// interfaces without prompting support are not considered
+		"network": nil,
+	}
+	for _, testCase := range []struct {
+		path    string
+		homeDir string
+		iface   string
+	}{
+		{"/home/test/foo", "/home/test", "home"},
+		{"/home/test/Documents/.hidden", "/home/test", "home"},
+		{"/home/test", "/home/test", "home"},
+		{"/home/test/", "/home/test/", "home"},
+		{"/home/test/snap", "/home/test", "home"},
+		{"/home/test/snapfoo/bar", "/home/test", "home"},
+		{"/home/test/.config/foo", "/home/test", "personal-files"},
+		{"/home/test/.config/foo/bar/baz", "/home/test", "personal-files"},
+		// home directories are not necessarily in /home
+		{"/root/foo", "/root", "home"},
+		{"/var/lib/test/.config/foo/bar", "/var/lib/test", "personal-files"},
+		{"/media/test/usb/foo", "/home/test", "removable-media"},
+		{"/run/media/test/usb", "/home/test", "removable-media"},
+		{"/mnt/foo", "/home/test", "removable-media"},
+		{"/dev/video0", "/home/test", "camera"},
+		{"/dev/snd/pcmC0D0c", "/home/test", "audio-record"},
+	} {
+		iface, err := prompting.InterfaceForPath(testCase.path, testCase.homeDir, connected)
+		c.Check(err, IsNil, Commentf("path: %s", testCase.path))
+		c.Check(iface, Equals, testCase.iface, Commentf("path: %s", testCase.path))
+	}
+
+	// personal-files may grant access to paths which are not hidden
+	iface, err := prompting.InterfaceForPath("/home/test/Private/notes/todo.txt", "/home/test", map[string][]string{
+		"personal-files": {"$HOME/Private/notes"},
+	})
+	c.Check(err, IsNil)
+	c.Check(iface, Equals, "personal-files")
+}
+
+func (s *constraintsSuite) TestInterfaceForPathUnhappy(c *C) {
+	for _, testCase := range []struct {
+		path      string
+		homeDir   string
+		connected map[string][]string
+		err       string
+	}{
+		{
+			"/home/test/foo", "/home/test", nil,
+			`cannot find a connected interface granting access to "/home/test/foo"`,
+		},
+		{
+			"/home/test/.config/foo", "/home/test", map[string][]string{"home": nil},
+			`cannot find a connected interface granting access to "/home/test/.config/foo"`,
+		},
+		{
+			"/home/test/snap/foo/common", "/home/test", map[string][]string{"home": nil},
+			`cannot find a connected interface granting access to .*`,
+		},
+		{
+			"/home/other/foo", "/home/test", map[string][]string{"home": nil},
+			`cannot find a connected interface granting access to .*`,
+		},
+		{
+			"/home/test/.config/foobar", "/home/test", map[string][]string{"personal-files": {"$HOME/.config/foo"}},
+			`cannot find a connected interface granting access to .*`,
+		},
+		{
+			"/home/test/foo", "", map[string][]string{"home": nil, "personal-files": {"$HOME/foo"}},
+			`cannot find a connected interface granting access to .*`,
+		},
+		{
+			"/mntfoo", "/home/test", map[string][]string{"removable-media": nil},
+			`cannot find a connected interface granting access to .*`,
+		},
+		{
+			"/home/test/Private/notes", "/home/test", map[string][]string{"home": nil, "personal-files": {"$HOME/Private"}},
+			`cannot tell which interface granted access to "/home/test/Private/notes": any of "home", "personal-files" could have`,
+		},
+	} {
+		iface, err := prompting.InterfaceForPath(testCase.path, testCase.homeDir, testCase.connected)
+		c.Check(err, ErrorMatches, testCase.err, Commentf("path: %s", testCase.path))
+		c.Check(iface, Equals, "")
+	}
+}
+
 func (s *constraintsSuite) TestAbstractPermissionsToAppArmorPermissionsUnhappy(c *C) {
 	cases := []struct {
 		iface  string
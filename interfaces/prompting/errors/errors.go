@@ -25,6 +25,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/snapcore/snapd/strutil"
@@ -133,6 +134,19 @@ func NewPermissionsListEmptyError(iface string, supported []string) *Unsupported
 	}
 }
 
+func NewInvalidPolicyVersionError(unsupported int, supported []int) *UnsupportedValueError {
+	supportedStrs := make([]string, 0, len(supported))
+	for _, version := range supported {
+		supportedStrs = append(supportedStrs, strconv.Itoa(version))
+	}
+	return &UnsupportedValueError{
+		Field:     "version",
+		Msg:       fmt.Sprintf("invalid policy version: %d", unsupported),
+		Value:     []string{strconv.Itoa(unsupported)},
+		Supported: supportedStrs,
+	}
+}
+
 // Marker for ParseError, should never be returned as an actual error value.
 var ErrParseError = errors.New("parse error")
 
@@ -176,6 +190,14 @@ func NewInvalidPathPatternError(invalid string, reason string) *ParseError {
 	}
 }
 
+func NewInvalidSnapError(invalid string, reason string) *ParseError {
+	return &ParseError{
+		Field:   "snap",
+		Msg:     fmt.Sprintf("invalid snap: %s: %q", reason, invalid),
+		Invalid: invalid,
+	}
+}
+
 // Validation errors, which are all uniquely defined here
 
 // RequestedPathNotMatchedError stores a path pattern from a reply which doesn't
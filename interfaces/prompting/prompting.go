@@ -115,6 +115,58 @@ func (outcome OutcomeType) AsBool() (bool, error) {
 	}
 }
 
+// DefaultOutcomeType describes how a prompt is resolved when no client replies
+// to it, either because the prompt timed out or because no prompting client
+// was listening for prompts when it was created.
+type DefaultOutcomeType string
+
+const (
+	// DefaultOutcomeDeny indicates that the remaining permissions of the
+	// prompt should be denied.
+	DefaultOutcomeDeny DefaultOutcomeType = "deny"
+	// DefaultOutcomeAllowOnce indicates that the permissions of the prompt
+	// should be allowed for the outstanding requests only.
+	DefaultOutcomeAllowOnce DefaultOutcomeType = "allow-once"
+	// DefaultOutcomeStatic indicates that the requests should be resolved as
+	// if prompting were disabled, that is, according to the static AppArmor
+	// profile of the snap.
+	DefaultOutcomeStatic DefaultOutcomeType = "static"
+)
+
+// SupportedDefaultOutcomes is the list of valid default outcomes.
+var SupportedDefaultOutcomes = []string{string(DefaultOutcomeDeny), string(DefaultOutcomeAllowOnce), string(DefaultOutcomeStatic)}
+
+// ParseDefaultOutcome parses the given string as a default outcome, returning
+// an error if it is not one of the supported default outcomes. The empty
+// string is interpreted as DefaultOutcomeDeny.
+func ParseDefaultOutcome(value string) (DefaultOutcomeType, error) {
+	switch DefaultOutcomeType(value) {
+	case "":
+		return DefaultOutcomeDeny, nil
+	case DefaultOutcomeDeny, DefaultOutcomeAllowOnce, DefaultOutcomeStatic:
+		return DefaultOutcomeType(value), nil
+	default:
+		return "", prompting_errors.NewInvalidOutcomeError(value, SupportedDefaultOutcomes)
+	}
+}
+
+// ParsePromptTimeout parses the given duration after which outstanding prompts
+// are resolved with the default outcome. The empty string is interpreted as
+// no timeout, in which case zero is returned.
+func ParsePromptTimeout(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse prompt timeout: %v", err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("cannot have zero or negative prompt timeout: %q", value)
+	}
+	return timeout, nil
+}
+
 // LifespanType describes the temporal scope for which a reply or rule applies.
 type LifespanType string
 
@@ -115,6 +115,49 @@ func (s *promptingSuite) TestUnmarshalOutcomeUnhappy(c *C) {
 	}
 }
 
+func (s *promptingSuite) TestParseDefaultOutcome(c *C) {
+	for _, testCase := range []struct {
+		value    string
+		expected prompting.DefaultOutcomeType
+	}{
+		{"", prompting.DefaultOutcomeDeny},
+		{"deny", prompting.DefaultOutcomeDeny},
+		{"allow-once", prompting.DefaultOutcomeAllowOnce},
+		{"static", prompting.DefaultOutcomeStatic},
+	} {
+		outcome, err := prompting.ParseDefaultOutcome(testCase.value)
+		c.Check(err, IsNil)
+		c.Check(outcome, Equals, testCase.expected)
+	}
+
+	for _, value := range []string{"allow", "foo"} {
+		_, err := prompting.ParseDefaultOutcome(value)
+		c.Check(err, ErrorMatches, fmt.Sprintf(`invalid outcome: %q`, value))
+	}
+}
+
+func (s *promptingSuite) TestParsePromptTimeout(c *C) {
+	timeout, err := prompting.ParsePromptTimeout("")
+	c.Check(err, IsNil)
+	c.Check(timeout, Equals, time.Duration(0))
+
+	timeout, err = prompting.ParsePromptTimeout("90s")
+	c.Check(err, IsNil)
+	c.Check(timeout, Equals, 90*time.Second)
+
+	for _, testCase := range []struct {
+		value  string
+		errStr string
+	}{
+		{"foo", `cannot parse prompt timeout: time: invalid duration "foo"`},
+		{"0s", `cannot have zero or negative prompt timeout: "0s"`},
+		{"-5m", `cannot have zero or negative prompt timeout: "-5m"`},
+	} {
+		_, err := prompting.ParsePromptTimeout(testCase.value)
+		c.Check(err, ErrorMatches, testCase.errStr)
+	}
+}
+
 type fakeLifespanWrapper struct {
 	Field1 prompting.LifespanType `json:"field1"`
 	Field2 prompting.LifespanType `json:"field2,omitempty"`
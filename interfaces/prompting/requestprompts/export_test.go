@@ -51,3 +51,15 @@ func (pdb *PromptDB) PerUser() map[uint32]*userPromptDB {
 func (pdb *PromptDB) NextID() (prompting.IDType, error) {
 	return pdb.maxIDMmap.NextID()
 }
+
+func MockClientGracePeriod(d time.Duration) (restore func()) {
+	old := clientGracePeriod
+	clientGracePeriod = d
+	return func() {
+		clientGracePeriod = old
+	}
+}
+
+func (pdb *PromptDB) ClientListening(user uint32) bool {
+	return pdb.clientListening(user)
+}
@@ -47,6 +47,9 @@ type Prompt struct {
 	Interface    string
 	Constraints  *promptConstraints
 	listenerReqs []*listener.Request
+	// timer resolves the prompt with the default outcome once it expires.
+	// If nil, the prompt never expires.
+	timer *time.Timer
 }
 
 // jsonPrompt defines the marshalled json structure of a Prompt.
@@ -192,6 +195,9 @@ func (udb *userPromptDB) remove(id prompting.IDType) (*Prompt, error) {
 		return nil, prompting_errors.ErrPromptNotFound
 	}
 	prompt := udb.prompts[index]
+	if prompt.timer != nil {
+		prompt.timer.Stop()
+	}
 	// Remove the prompt with the given ID by copying the final prompt in
 	// udb.prompts to its index.
 	udb.prompts[index] = udb.prompts[len(udb.prompts)-1]
@@ -220,6 +226,42 @@ type PromptDB struct {
 	// notifyPrompt is a closure which will be called to record a notice when a
 	// prompt is added, merged, modified, or resolved.
 	notifyPrompt func(userID uint32, promptID prompting.IDType, data map[string]string) error
+	// policy determines how prompts which are not replied to are resolved.
+	policy Policy
+
+	// clientsMutex protects clients, and is independent of the prompt DB
+	// mutex so that clients may be registered without waiting for prompts
+	// to be processed.
+	clientsMutex sync.Mutex
+	// clients maps UID to the activity of prompting clients for that user.
+	clients map[uint32]*clientActivity
+}
+
+// Policy determines how prompts are resolved when no prompting client replies
+// to them.
+type Policy struct {
+	// Timeout is the duration after which an outstanding prompt is resolved
+	// with the default outcome. If zero, prompts do not expire, unless
+	// NoClientTimeout applies.
+	Timeout time.Duration
+	// NoClientTimeout is the duration after which a prompt which was created
+	// while no prompting client was listening is resolved with the default
+	// outcome, unless a client showed up in the meantime. If zero, prompts
+	// are kept outstanding whether or not a client is listening.
+	NoClientTimeout time.Duration
+	// DefaultOutcome is the outcome with which expired prompts are resolved.
+	DefaultOutcome prompting.DefaultOutcomeType
+}
+
+// clientActivity records whether a prompting client is listening for prompt
+// notices.
+type clientActivity struct {
+	// active is the number of ongoing requests for prompt notices.
+	active int
+	// lastSeen is the time at which the most recent request for prompt
+	// notices completed, or at which prompts were last retrieved or replied
+	// to.
+	lastSeen time.Time
 }
 
 const (
@@ -228,6 +270,13 @@ const (
 	maxOutstandingPromptsPerUser int = 1000
 )
 
+var (
+	// clientGracePeriod is the duration after a request for prompt notices
+	// completes during which the client is still considered to be listening,
+	// to account for the time between consecutive requests.
+	clientGracePeriod = 10 * time.Second
+)
+
 // New creates and returns a new prompt database.
 //
 // The given notifyPrompt closure will be called when a prompt is added,
@@ -247,10 +296,93 @@ func New(notifyPrompt func(userID uint32, promptID prompting.IDType, data map[st
 		perUser:      make(map[uint32]*userPromptDB),
 		notifyPrompt: notifyPrompt,
 		maxIDMmap:    maxIDMmap,
+		policy: Policy{
+			DefaultOutcome: prompting.DefaultOutcomeDeny,
+		},
+		clients: make(map[uint32]*clientActivity),
 	}
 	return &pdb, nil
 }
 
+// SetPolicy sets the policy which determines how prompts created from now on
+// are resolved when no prompting client replies to them.
+func (pdb *PromptDB) SetPolicy(policy Policy) {
+	pdb.mutex.Lock()
+	defer pdb.mutex.Unlock()
+	pdb.policy = policy
+}
+
+// RegisterClient records that a prompting client for the given user is
+// listening for prompt notices, and returns a function which must be called
+// once the client stops listening, such as when its request for notices
+// completes.
+func (pdb *PromptDB) RegisterClient(user uint32) (done func()) {
+	pdb.clientsMutex.Lock()
+	defer pdb.clientsMutex.Unlock()
+	activity, ok := pdb.clients[user]
+	if !ok {
+		activity = &clientActivity{}
+		pdb.clients[user] = activity
+	}
+	activity.active++
+	return func() {
+		pdb.clientsMutex.Lock()
+		defer pdb.clientsMutex.Unlock()
+		activity.active--
+		activity.lastSeen = time.Now()
+	}
+}
+
+// NoteClientActivity records that a prompting client for the given user
+// retrieved or replied to prompts, so that it is considered to be listening
+// for the grace period, even if it is not currently waiting for prompt
+// notices, such as while it is showing a prompt to the user.
+func (pdb *PromptDB) NoteClientActivity(user uint32) {
+	pdb.clientsMutex.Lock()
+	defer pdb.clientsMutex.Unlock()
+	activity, ok := pdb.clients[user]
+	if !ok {
+		activity = &clientActivity{}
+		pdb.clients[user] = activity
+	}
+	activity.lastSeen = time.Now()
+}
+
+// clientListening returns true if a prompting client for the given user is
+// listening for prompt notices, or was active within the grace period.
+func (pdb *PromptDB) clientListening(user uint32) bool {
+	return pdb.clientSeenSince(user, time.Now().Add(-clientGracePeriod))
+}
+
+// clientSeenSince returns true if a prompting client for the given user is
+// listening for prompt notices, or was active after the given time.
+func (pdb *PromptDB) clientSeenSince(user uint32, since time.Time) bool {
+	pdb.clientsMutex.Lock()
+	defer pdb.clientsMutex.Unlock()
+	activity, ok := pdb.clients[user]
+	if !ok {
+		return false
+	}
+	return activity.active > 0 || activity.lastSeen.After(since)
+}
+
+// promptTimeout returns the duration after which a new prompt for the given
+// user should expire, or zero if it should not expire, and whether that
+// duration is the one applied because no prompting client is listening.
+//
+// The caller must hold the prompt DB mutex.
+func (pdb *PromptDB) promptTimeout(user uint32) (timeout time.Duration, noClient bool) {
+	timeout = pdb.policy.Timeout
+	noClientTimeout := pdb.policy.NoClientTimeout
+	if noClientTimeout == 0 || pdb.clientListening(user) {
+		return timeout, false
+	}
+	if timeout == 0 || timeout > noClientTimeout {
+		return noClientTimeout, true
+	}
+	return timeout, false
+}
+
 // AddOrMerge checks if the given prompt contents are identical to an existing
 // prompt and, if so, merges with it by adding the given listenerReq to it.
 // Otherwise, adds a new prompt with the given contents to the prompt DB.
@@ -325,6 +457,12 @@ func (pdb *PromptDB) AddOrMerge(metadata *prompting.Metadata, path string, reque
 		Constraints:  constraints,
 		listenerReqs: []*listener.Request{listenerReq},
 	}
+	if timeout, noClient := pdb.promptTimeout(metadata.User); timeout > 0 {
+		user := metadata.User
+		prompt.timer = time.AfterFunc(timeout, func() {
+			pdb.handleExpiration(user, id, noClient)
+		})
+	}
 	userEntry.add(prompt)
 	pdb.notifyPrompt(metadata.User, id, nil)
 	return prompt, false, nil
@@ -358,6 +496,61 @@ func responseForInterfaceConstraintsOutcome(iface string, constraints *promptCon
 	return allowedPermission
 }
 
+// responseForDefaultOutcome returns the response to the given listener
+// request of the given prompt when the prompt is resolved with the given
+// default outcome.
+func responseForDefaultOutcome(prompt *Prompt, listenerReq *listener.Request, outcome prompting.DefaultOutcomeType) any {
+	switch outcome {
+	case prompting.DefaultOutcomeAllowOnce:
+		return responseForInterfaceConstraintsOutcome(prompt.Interface, prompt.Constraints, prompting.OutcomeAllow)
+	case prompting.DefaultOutcomeStatic:
+		// The static profile allows everything covered by the prompt rule
+		// which triggered the request, so allow all requested permissions.
+		return listenerReq.Permission
+	default:
+		return responseForInterfaceConstraintsOutcome(prompt.Interface, prompt.Constraints, prompting.OutcomeDeny)
+	}
+}
+
+// handleExpiration resolves the prompt with the given ID for the given user
+// with the default outcome, if it is still outstanding.
+//
+// If the prompt expired because no prompting client was listening when it was
+// created, but a client showed up since, the client may still reply, so the
+// prompt is instead kept outstanding until the timeout of the policy, if any.
+//
+// Records a notice for the prompt if it was resolved.
+func (pdb *PromptDB) handleExpiration(user uint32, id prompting.IDType, noClient bool) {
+	pdb.mutex.Lock()
+	defer pdb.mutex.Unlock()
+	userEntry, prompt, err := pdb.promptWithID(user, id)
+	if err != nil {
+		// The prompt was already resolved, or the prompt DB was closed
+		return
+	}
+	if noClient && pdb.clientSeenSince(user, prompt.Timestamp) {
+		prompt.timer = nil
+		if pdb.policy.Timeout == 0 {
+			return
+		}
+		remaining := pdb.policy.Timeout - time.Since(prompt.Timestamp)
+		if remaining > 0 {
+			prompt.timer = time.AfterFunc(remaining, func() {
+				pdb.handleExpiration(user, id, false)
+			})
+			return
+		}
+	}
+	outcome := pdb.policy.DefaultOutcome
+	logger.Debugf("prompt %s for user %d expired without reply; resolving with default outcome %q", id, user, outcome)
+	for _, listenerReq := range prompt.listenerReqs {
+		sendReply(listenerReq, responseForDefaultOutcome(prompt, listenerReq, outcome))
+	}
+	userEntry.remove(id)
+	data := map[string]string{"resolved": "expired"}
+	pdb.notifyPrompt(user, id, data)
+}
+
 // Prompts returns a slice of all outstanding prompts for the given user.
 func (pdb *PromptDB) Prompts(user uint32) ([]*Prompt, error) {
 	pdb.mutex.RLock()
@@ -529,6 +722,11 @@ func (pdb *PromptDB) Close() error {
 	// not want to send {"resolved": "cancelled"} in the notice data.
 	data := map[string]string{"resolved": "cancelled"}
 	for user, userEntry := range pdb.perUser {
+		for _, prompt := range userEntry.prompts {
+			if prompt.timer != nil {
+				prompt.timer.Stop()
+			}
+		}
 		for id := range userEntry.ids {
 			pdb.notifyPrompt(user, id, data)
 		}
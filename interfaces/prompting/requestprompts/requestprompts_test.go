@@ -550,6 +550,350 @@ func (s *requestpromptsSuite) TestReply(c *C) {
 	}
 }
 
+func (s *requestpromptsSuite) TestReplyOtherInterfaces(c *C) {
+	listenerReqChan := make(chan *listener.Request, 1)
+	replyChan := make(chan any, 1)
+	restore := requestprompts.MockSendReply(func(listenerReq *listener.Request, allowedPermission any) error {
+		listenerReqChan <- listenerReq
+		replyChan <- allowedPermission
+		return nil
+	})
+	defer restore()
+
+	pdb, err := requestprompts.New(s.defaultNotifyPrompt)
+	c.Assert(err, IsNil)
+	defer pdb.Close()
+
+	for _, testCase := range []struct {
+		iface       string
+		path        string
+		permissions []string
+		expected    notify.FilePermission
+	}{
+		{
+			"personal-files",
+			"/home/test/.config/foo",
+			[]string{"read"},
+			notify.AA_MAY_OPEN | notify.AA_MAY_READ | notify.AA_MAY_GETATTR,
+		},
+		{
+			"removable-media",
+			"/media/test/usb/foo.txt",
+			[]string{"read", "execute"},
+			notify.AA_MAY_OPEN | notify.AA_MAY_READ | notify.AA_MAY_GETATTR | notify.AA_MAY_EXEC | notify.AA_EXEC_MMAP,
+		},
+		{
+			"camera",
+			"/dev/video0",
+			[]string{"access"},
+			notify.AA_MAY_OPEN | notify.AA_MAY_READ | notify.AA_MAY_WRITE | notify.AA_MAY_APPEND | notify.AA_MAY_GETATTR | notify.AA_MAY_SETATTR | notify.AA_MAY_LOCK,
+		},
+		{
+			"audio-record",
+			"/dev/snd/pcmC0D0c",
+			[]string{"access"},
+			notify.AA_MAY_OPEN | notify.AA_MAY_READ | notify.AA_MAY_WRITE | notify.AA_MAY_APPEND | notify.AA_MAY_GETATTR | notify.AA_MAY_SETATTR | notify.AA_MAY_LOCK,
+		},
+	} {
+		metadata := &prompting.Metadata{
+			User:      s.defaultUser,
+			Snap:      "nextcloud",
+			Interface: testCase.iface,
+		}
+		listenerReq := &listener.Request{}
+
+		prompt, merged, err := pdb.AddOrMerge(metadata, testCase.path, testCase.permissions, testCase.permissions, listenerReq)
+		c.Assert(err, IsNil)
+		c.Check(merged, Equals, false)
+		c.Check(prompt.Interface, Equals, testCase.iface)
+		s.checkNewNoticesSimple(c, []prompting.IDType{prompt.ID}, nil)
+
+		repliedPrompt, err := pdb.Reply(metadata.User, prompt.ID, prompting.OutcomeAllow)
+		c.Check(err, IsNil)
+		c.Check(repliedPrompt, Equals, prompt)
+		receivedReq, allowedPermission, err := s.waitForListenerReqAndReply(c, listenerReqChan, replyChan)
+		c.Check(err, IsNil)
+		c.Check(receivedReq, Equals, listenerReq)
+		c.Check(allowedPermission, Equals, testCase.expected, Commentf("testCase: %+v", testCase))
+
+		expectedData := map[string]string{"resolved": "replied"}
+		s.checkNewNoticesSimple(c, []prompting.IDType{repliedPrompt.ID}, expectedData)
+	}
+}
+
+func (s *requestpromptsSuite) TestExpirationDefaultOutcomes(c *C) {
+	listenerReqChan := make(chan *listener.Request, 1)
+	replyChan := make(chan any, 1)
+	restore := requestprompts.MockSendReply(func(listenerReq *listener.Request, allowedPermission any) error {
+		listenerReqChan <- listenerReq
+		replyChan <- allowedPermission
+		return nil
+	})
+	defer restore()
+
+	pdb, err := requestprompts.New(s.defaultNotifyPrompt)
+	c.Assert(err, IsNil)
+	defer pdb.Close()
+
+	// Register a client so the prompt timeout from the policy applies
+	done := pdb.RegisterClient(s.defaultUser)
+	defer done()
+
+	metadata := &prompting.Metadata{
+		User:      s.defaultUser,
+		Snap:      "nextcloud",
+		Interface: "home",
+	}
+	path := "/home/test/Documents/foo.txt"
+	// Read was already allowed by a rule, write remains
+	requestedPermissions := []string{"read", "write"}
+	remainingPermissions := []string{"write"}
+
+	for _, testCase := range []struct {
+		outcome  prompting.DefaultOutcomeType
+		expected any
+	}{
+		{
+			prompting.DefaultOutcomeDeny,
+			notify.AA_MAY_OPEN | notify.AA_MAY_READ | notify.AA_MAY_GETATTR,
+		},
+		{
+			prompting.DefaultOutcomeAllowOnce,
+			notify.AA_MAY_OPEN | notify.AA_MAY_READ | notify.AA_MAY_GETATTR | notify.AA_MAY_WRITE | notify.AA_MAY_APPEND | notify.AA_MAY_CREATE | notify.AA_MAY_DELETE | notify.AA_MAY_RENAME | notify.AA_MAY_SETATTR | notify.AA_MAY_CHMOD | notify.AA_MAY_LOCK | notify.AA_MAY_LINK,
+		},
+		{
+			prompting.DefaultOutcomeStatic,
+			notify.AA_MAY_READ | notify.AA_MAY_WRITE | notify.AA_MAY_CHOWN,
+		},
+	} {
+		pdb.SetPolicy(requestprompts.Policy{
+			Timeout:        10 * time.Millisecond,
+			DefaultOutcome: testCase.outcome,
+		})
+
+		listenerReq := &listener.Request{
+			Permission: notify.AA_MAY_READ | notify.AA_MAY_WRITE | notify.AA_MAY_CHOWN,
+		}
+		prompt, merged, err := pdb.AddOrMerge(metadata, path, requestedPermissions, remainingPermissions, listenerReq)
+		c.Assert(err, IsNil)
+		c.Check(merged, Equals, false)
+
+		receivedReq, allowedPermission, err := s.waitForListenerReqAndReply(c, listenerReqChan, replyChan)
+		c.Check(err, IsNil)
+		c.Check(receivedReq, Equals, listenerReq)
+		c.Check(allowedPermission, Equals, testCase.expected, Commentf("outcome: %s", testCase.outcome))
+
+		// Expired prompt was removed
+		prompts, err := pdb.Prompts(s.defaultUser)
+		c.Check(err, IsNil)
+		c.Check(prompts, HasLen, 0)
+
+		s.checkNewNotices(c, []*noticeInfo{
+			{promptID: prompt.ID, data: nil},
+			{promptID: prompt.ID, data: map[string]string{"resolved": "expired"}},
+		})
+	}
+
+	// Prompts which are replied to do not expire
+	pdb.SetPolicy(requestprompts.Policy{
+		Timeout:        50 * time.Millisecond,
+		DefaultOutcome: prompting.DefaultOutcomeAllowOnce,
+	})
+	listenerReq := &listener.Request{}
+	prompt, _, err := pdb.AddOrMerge(metadata, path, requestedPermissions, remainingPermissions, listenerReq)
+	c.Assert(err, IsNil)
+	_, err = pdb.Reply(s.defaultUser, prompt.ID, prompting.OutcomeDeny)
+	c.Assert(err, IsNil)
+	_, _, err = s.waitForListenerReqAndReply(c, listenerReqChan, replyChan)
+	c.Check(err, IsNil)
+	select {
+	case <-listenerReqChan:
+		c.Fatalf("unexpected reply sent for expired prompt")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func (s *requestpromptsSuite) TestExpirationNoClient(c *C) {
+	restore := requestprompts.MockClientGracePeriod(time.Hour)
+	defer restore()
+
+	listenerReqChan := make(chan *listener.Request, 1)
+	replyChan := make(chan any, 1)
+	restore = requestprompts.MockSendReply(func(listenerReq *listener.Request, allowedPermission any) error {
+		listenerReqChan <- listenerReq
+		replyChan <- allowedPermission
+		return nil
+	})
+	defer restore()
+
+	pdb, err := requestprompts.New(s.defaultNotifyPrompt)
+	c.Assert(err, IsNil)
+	defer pdb.Close()
+
+	metadata := &prompting.Metadata{
+		User:      s.defaultUser,
+		Snap:      "nextcloud",
+		Interface: "home",
+	}
+	path := "/home/test/Documents/foo.txt"
+	permissions := []string{"read"}
+
+	// Without a no-client timeout, prompts do not expire even though no
+	// client is listening
+	listenerReq := &listener.Request{}
+	prompt, _, err := pdb.AddOrMerge(metadata, path, permissions, permissions, listenerReq)
+	c.Assert(err, IsNil)
+	select {
+	case <-listenerReqChan:
+		c.Fatalf("unexpected reply sent for prompt")
+	case <-time.After(100 * time.Millisecond):
+	}
+	_, err = pdb.Reply(s.defaultUser, prompt.ID, prompting.OutcomeDeny)
+	c.Assert(err, IsNil)
+	_, _, err = s.waitForListenerReqAndReply(c, listenerReqChan, replyChan)
+	c.Assert(err, IsNil)
+	s.checkNewNotices(c, []*noticeInfo{
+		{promptID: prompt.ID, data: nil},
+		{promptID: prompt.ID, data: map[string]string{"resolved": "replied"}},
+	})
+
+	// No client is listening, so the prompt expires even without timeout
+	pdb.SetPolicy(requestprompts.Policy{
+		NoClientTimeout: 10 * time.Millisecond,
+		DefaultOutcome:  prompting.DefaultOutcomeDeny,
+	})
+	listenerReq = &listener.Request{}
+	prompt, _, err = pdb.AddOrMerge(metadata, path, permissions, permissions, listenerReq)
+	c.Assert(err, IsNil)
+	receivedReq, allowedPermission, err := s.waitForListenerReqAndReply(c, listenerReqChan, replyChan)
+	c.Check(err, IsNil)
+	c.Check(receivedReq, Equals, listenerReq)
+	c.Check(allowedPermission, Equals, notify.FilePermission(0))
+	prompts, err := pdb.Prompts(s.defaultUser)
+	c.Check(err, IsNil)
+	c.Check(prompts, HasLen, 0)
+	s.checkNewNotices(c, []*noticeInfo{
+		{promptID: prompt.ID, data: nil},
+		{promptID: prompt.ID, data: map[string]string{"resolved": "expired"}},
+	})
+
+	// Once a client has been listening, prompts do not expire without timeout
+	done := pdb.RegisterClient(s.defaultUser)
+	done()
+	listenerReq = &listener.Request{}
+	prompt, _, err = pdb.AddOrMerge(metadata, path, permissions, permissions, listenerReq)
+	c.Assert(err, IsNil)
+	select {
+	case <-listenerReqChan:
+		c.Fatalf("unexpected reply sent for prompt")
+	case <-time.After(100 * time.Millisecond):
+	}
+	prompts, err = pdb.Prompts(s.defaultUser)
+	c.Check(err, IsNil)
+	c.Check(prompts, DeepEquals, []*requestprompts.Prompt{prompt})
+}
+
+func (s *requestpromptsSuite) TestExpirationNoClientClientShowsUp(c *C) {
+	restore := requestprompts.MockClientGracePeriod(0)
+	defer restore()
+
+	listenerReqChan := make(chan *listener.Request, 1)
+	replyChan := make(chan any, 1)
+	restore = requestprompts.MockSendReply(func(listenerReq *listener.Request, allowedPermission any) error {
+		listenerReqChan <- listenerReq
+		replyChan <- allowedPermission
+		return nil
+	})
+	defer restore()
+
+	pdb, err := requestprompts.New(s.defaultNotifyPrompt)
+	c.Assert(err, IsNil)
+	defer pdb.Close()
+
+	pdb.SetPolicy(requestprompts.Policy{
+		Timeout:         300 * time.Millisecond,
+		NoClientTimeout: 50 * time.Millisecond,
+		DefaultOutcome:  prompting.DefaultOutcomeDeny,
+	})
+
+	metadata := &prompting.Metadata{
+		User:      s.defaultUser,
+		Snap:      "nextcloud",
+		Interface: "home",
+	}
+	path := "/home/test/Documents/foo.txt"
+	permissions := []string{"read"}
+
+	listenerReq := &listener.Request{}
+	whenAdded := time.Now()
+	prompt, _, err := pdb.AddOrMerge(metadata, path, permissions, permissions, listenerReq)
+	c.Assert(err, IsNil)
+
+	// A client retrieves the prompt before the no-client timeout elapses
+	pdb.NoteClientActivity(s.defaultUser)
+
+	// so the prompt only expires with the timeout of the policy
+	receivedReq, _, err := s.waitForListenerReqAndReply(c, listenerReqChan, replyChan)
+	c.Assert(err, IsNil)
+	c.Check(receivedReq, Equals, listenerReq)
+	c.Check(time.Since(whenAdded) >= 300*time.Millisecond, Equals, true)
+	s.checkNewNotices(c, []*noticeInfo{
+		{promptID: prompt.ID, data: nil},
+		{promptID: prompt.ID, data: map[string]string{"resolved": "expired"}},
+	})
+}
+
+func (s *requestpromptsSuite) TestRegisterClient(c *C) {
+	restore := requestprompts.MockClientGracePeriod(time.Hour)
+	defer restore()
+
+	pdb, err := requestprompts.New(s.defaultNotifyPrompt)
+	c.Assert(err, IsNil)
+	defer pdb.Close()
+
+	c.Check(pdb.ClientListening(s.defaultUser), Equals, false)
+
+	done1 := pdb.RegisterClient(s.defaultUser)
+	done2 := pdb.RegisterClient(s.defaultUser)
+	c.Check(pdb.ClientListening(s.defaultUser), Equals, true)
+	c.Check(pdb.ClientListening(s.defaultUser+1), Equals, false)
+
+	// Client is considered listening within the grace period
+	done1()
+	done2()
+	c.Check(pdb.ClientListening(s.defaultUser), Equals, true)
+
+	restore = requestprompts.MockClientGracePeriod(0)
+	defer restore()
+	c.Check(pdb.ClientListening(s.defaultUser), Equals, false)
+
+	done := pdb.RegisterClient(s.defaultUser)
+	c.Check(pdb.ClientListening(s.defaultUser), Equals, true)
+	done()
+	c.Check(pdb.ClientListening(s.defaultUser), Equals, false)
+}
+
+func (s *requestpromptsSuite) TestNoteClientActivity(c *C) {
+	restore := requestprompts.MockClientGracePeriod(time.Hour)
+	defer restore()
+
+	pdb, err := requestprompts.New(s.defaultNotifyPrompt)
+	c.Assert(err, IsNil)
+	defer pdb.Close()
+
+	c.Check(pdb.ClientListening(s.defaultUser), Equals, false)
+
+	// A client which retrieved prompts is considered listening within the
+	// grace period
+	pdb.NoteClientActivity(s.defaultUser)
+	c.Check(pdb.ClientListening(s.defaultUser), Equals, true)
+	c.Check(pdb.ClientListening(s.defaultUser+1), Equals, false)
+
+	restore = requestprompts.MockClientGracePeriod(0)
+	defer restore()
+	c.Check(pdb.ClientListening(s.defaultUser), Equals, false)
+}
+
 func (s *requestpromptsSuite) waitForListenerReqAndReply(c *C, listenerReqChan <-chan *listener.Request, replyChan <-chan any) (req *listener.Request, allowedPermission any, err error) {
 	select {
 	case req = <-listenerReqChan:
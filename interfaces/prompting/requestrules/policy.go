@@ -0,0 +1,271 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package requestrules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/snapcore/snapd/interfaces/prompting"
+	prompting_errors "github.com/snapcore/snapd/interfaces/prompting/errors"
+	"github.com/snapcore/snapd/interfaces/prompting/patterns"
+	"github.com/snapcore/snapd/snap/naming"
+)
+
+// PolicyVersion is the version of the policy format written by ExportPolicy
+// and the only version accepted by ImportPolicy.
+const PolicyVersion = 1
+
+// homeDirPrefix is the prefix of path patterns in a policy which are relative
+// to the home directory of the user to whom the policy is applied.
+const homeDirPrefix = "~/"
+
+// Policy is a portable set of rules which can be exported from the rule
+// database of one user and imported into that of another user, possibly on
+// another system.
+type Policy struct {
+	Version int           `json:"version"`
+	Rules   []*PolicyRule `json:"rules"`
+}
+
+// PolicyRule holds the contents of a rule in a policy.
+//
+// Unlike a Rule, a PolicyRule carries no ID, timestamp, or user, and its path
+// pattern may begin with "~/" to refer to the home directory of the user to
+// whom the policy is applied. If the lifespan is omitted, it is "forever".
+type PolicyRule struct {
+	Snap        string                 `json:"snap"`
+	Interface   string                 `json:"interface"`
+	PathPattern string                 `json:"path-pattern"`
+	Permissions []string               `json:"permissions"`
+	Outcome     prompting.OutcomeType  `json:"outcome"`
+	Lifespan    prompting.LifespanType `json:"lifespan,omitempty"`
+	Duration    string                 `json:"duration,omitempty"`
+}
+
+// Validate checks that the policy has a supported version and that each of
+// its rules is valid, without applying the policy to any user.
+func (p *Policy) Validate() error {
+	// Any absolute directory will do to check patterns relative to home.
+	_, err := p.buildRules(0, "/home/user", time.Now())
+	return err
+}
+
+// buildRules creates and validates the rules of the policy for the given user,
+// expanding path patterns relative to the given home directory. The returned
+// rules are not yet assigned IDs.
+func (p *Policy) buildRules(user uint32, homeDir string, currTime time.Time) ([]*Rule, error) {
+	if p.Version != PolicyVersion {
+		return nil, prompting_errors.NewInvalidPolicyVersionError(p.Version, []int{PolicyVersion})
+	}
+	rules := make([]*Rule, 0, len(p.Rules))
+	for i, policyRule := range p.Rules {
+		rule, err := policyRule.build(user, homeDir, currTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %d in policy: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (pr *PolicyRule) build(user uint32, homeDir string, currTime time.Time) (*Rule, error) {
+	if err := naming.ValidateInstance(pr.Snap); err != nil {
+		return nil, prompting_errors.NewInvalidSnapError(pr.Snap, err.Error())
+	}
+	pattern := pr.PathPattern
+	if strings.HasPrefix(pattern, homeDirPrefix) {
+		pattern = strings.TrimSuffix(homeDir, "/") + "/" + pattern[len(homeDirPrefix):]
+	}
+	pathPattern, err := patterns.ParsePathPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	constraints := &prompting.Constraints{
+		PathPattern: pathPattern,
+		Permissions: append([]string(nil), pr.Permissions...),
+	}
+	lifespan := pr.Lifespan
+	if lifespan == prompting.LifespanUnset {
+		lifespan = prompting.LifespanForever
+	}
+	return buildRule(user, pr.Snap, pr.Interface, constraints, pr.Outcome, lifespan, pr.Duration, currTime)
+}
+
+// sameContents returns true if the given rules apply to the same user, snap,
+// interface, path pattern, and permissions, with the same outcome and
+// lifespan, regardless of their IDs, timestamps, and expirations.
+func sameContents(a, b *Rule) bool {
+	if a.User != b.User || a.Snap != b.Snap || a.Interface != b.Interface {
+		return false
+	}
+	if a.Outcome != b.Outcome || a.Lifespan != b.Lifespan {
+		return false
+	}
+	if a.Constraints.PathPattern.String() != b.Constraints.PathPattern.String() {
+		return false
+	}
+	if len(a.Constraints.Permissions) != len(b.Constraints.Permissions) {
+		return false
+	}
+	// Permissions are sorted during validation, so compare them in order.
+	for i, perm := range a.Constraints.Permissions {
+		if b.Constraints.Permissions[i] != perm {
+			return false
+		}
+	}
+	return true
+}
+
+// ExportPolicy returns a policy holding the rules of the given user which have
+// a lifespan of "forever", ordered by ID. Rules with other lifespans are
+// transient, so they are not exported.
+//
+// Path patterns beginning with the given home directory are written relative
+// to "~/", so that the policy may be imported for other users.
+func (rdb *RuleDB) ExportPolicy(user uint32, homeDir string) *Policy {
+	rdb.mutex.RLock()
+	defer rdb.mutex.RUnlock()
+
+	ruleFilter := func(rule *Rule) bool {
+		return rule.User == user && rule.Lifespan == prompting.LifespanForever
+	}
+	rules := rdb.rulesInternal(ruleFilter)
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	homePrefix := strings.TrimSuffix(homeDir, "/") + "/"
+	policy := &Policy{
+		Version: PolicyVersion,
+		Rules:   make([]*PolicyRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		pattern := rule.Constraints.PathPattern.String()
+		if homeDir != "" && strings.HasPrefix(pattern, homePrefix) {
+			pattern = homeDirPrefix + pattern[len(homePrefix):]
+		}
+		policy.Rules = append(policy.Rules, &PolicyRule{
+			Snap:        rule.Snap,
+			Interface:   rule.Interface,
+			PathPattern: pattern,
+			Permissions: append([]string(nil), rule.Constraints.Permissions...),
+			Outcome:     rule.Outcome,
+			Lifespan:    rule.Lifespan,
+		})
+	}
+	return policy
+}
+
+// ImportPolicy adds the rules of the given policy to the rule database for the
+// given user, expanding path patterns relative to the given home directory.
+//
+// If replace is true, all existing rules of the user are removed first.
+// Otherwise, the policy is merged with the existing rules, and rules whose
+// contents are identical to those of an existing rule are skipped, so that
+// importing the same policy twice has no further effect.
+//
+// The import is atomic: if any rule of the policy is invalid or conflicts with
+// another rule, or if the database cannot be saved, returns an error and the
+// rule database is left unchanged. Otherwise, returns the rules which were
+// added and those which were removed.
+func (rdb *RuleDB) ImportPolicy(user uint32, homeDir string, policy *Policy, replace bool) (added []*Rule, removed []*Rule, err error) {
+	rdb.mutex.Lock()
+	defer rdb.mutex.Unlock()
+
+	if rdb.maxIDMmap.IsClosed() {
+		return nil, nil, prompting_errors.ErrRulesClosed
+	}
+
+	newRules, err := policy.buildRules(user, homeDir, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var existing []*Rule
+	if replace {
+		removed = rdb.rulesInternal(func(rule *Rule) bool {
+			return rule.User == user
+		})
+	} else {
+		existing = rdb.rulesInternal(func(rule *Rule) bool {
+			return rule.User == user
+		})
+	}
+
+	toAdd := make([]*Rule, 0, len(newRules))
+	for _, rule := range newRules {
+		if containsSameContents(existing, rule) || containsSameContents(toAdd, rule) {
+			continue
+		}
+		toAdd = append(toAdd, rule)
+	}
+
+	for _, rule := range removed {
+		// We know the rule exists, so this should not error
+		rdb.removeRuleByID(rule.ID)
+	}
+
+	rollback := func() {
+		for i := len(added) - 1; i >= 0; i-- {
+			rdb.removeRuleByID(added[i].ID)
+		}
+		for _, rule := range removed {
+			// The rules were present before, so they can be re-added
+			rdb.addRule(rule)
+		}
+	}
+
+	added = make([]*Rule, 0, len(toAdd))
+	for _, rule := range toAdd {
+		// Don't consume an ID until now, when we know the rule is valid
+		rule.ID, _ = rdb.maxIDMmap.NextID()
+		if err := rdb.addRule(rule); err != nil {
+			rollback()
+			return nil, nil, fmt.Errorf("cannot import policy: %w", err)
+		}
+		added = append(added, rule)
+	}
+
+	if err := rdb.save(); err != nil {
+		rollback()
+		return nil, nil, err
+	}
+
+	data := map[string]string{"removed": "removed"}
+	for _, rule := range removed {
+		rdb.notifyRule(user, rule.ID, data)
+	}
+	for _, rule := range added {
+		rdb.notifyRule(user, rule.ID, nil)
+	}
+	return added, removed, nil
+}
+
+func containsSameContents(rules []*Rule, rule *Rule) bool {
+	for _, r := range rules {
+		if sameContents(r, rule) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package requestrules_test
+
+import (
+	"encoding/json"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/interfaces/prompting"
+	"github.com/snapcore/snapd/interfaces/prompting/requestrules"
+)
+
+func (s *requestrulesSuite) TestExportPolicy(c *C) {
+	rdb, err := requestrules.New(s.defaultNotifyRule)
+	c.Assert(err, IsNil)
+
+	template := &addRuleContents{
+		User:        s.defaultUser,
+		Snap:        "libreoffice",
+		Interface:   "home",
+		Permissions: []string{"read"},
+		Outcome:     prompting.OutcomeAllow,
+		Lifespan:    prompting.LifespanForever,
+	}
+	for _, ruleContents := range []*addRuleContents{
+		{PathPattern: "/home/test/Documents/**", Permissions: []string{"write", "read"}},
+		{PathPattern: "/home/test/Downloads/*.odt", Lifespan: prompting.LifespanTimespan, Duration: "1h"},
+		{PathPattern: "/home/test/.ssh/**", Snap: "firefox", Outcome: prompting.OutcomeDeny},
+		{PathPattern: "/home/testing/foo"},
+		{PathPattern: "/home/test/Music/**", User: s.defaultUser + 1},
+	} {
+		_, err := addRuleFromTemplate(c, rdb, template, ruleContents)
+		c.Assert(err, IsNil)
+	}
+
+	policy := rdb.ExportPolicy(s.defaultUser, "/home/test")
+	c.Check(policy, DeepEquals, &requestrules.Policy{
+		Version: requestrules.PolicyVersion,
+		Rules: []*requestrules.PolicyRule{
+			{
+				Snap:        "libreoffice",
+				Interface:   "home",
+				PathPattern: "~/Documents/**",
+				Permissions: []string{"read", "write"},
+				Outcome:     prompting.OutcomeAllow,
+				Lifespan:    prompting.LifespanForever,
+			},
+			{
+				Snap:        "firefox",
+				Interface:   "home",
+				PathPattern: "~/.ssh/**",
+				Permissions: []string{"read"},
+				Outcome:     prompting.OutcomeDeny,
+				Lifespan:    prompting.LifespanForever,
+			},
+			{
+				Snap:        "libreoffice",
+				Interface:   "home",
+				PathPattern: "/home/testing/foo",
+				Permissions: []string{"read"},
+				Outcome:     prompting.OutcomeAllow,
+				Lifespan:    prompting.LifespanForever,
+			},
+		},
+	})
+
+	// A user without rules exports an empty policy
+	policy = rdb.ExportPolicy(s.defaultUser+2, "/home/other")
+	c.Check(policy, DeepEquals, &requestrules.Policy{
+		Version: requestrules.PolicyVersion,
+		Rules:   []*requestrules.PolicyRule{},
+	})
+}
+
+func (s *requestrulesSuite) TestImportPolicyMerge(c *C) {
+	rdb, err := requestrules.New(s.defaultNotifyRule)
+	c.Assert(err, IsNil)
+
+	existing, err := addRuleFromTemplate(c, rdb, &addRuleContents{
+		User:        s.defaultUser,
+		Snap:        "libreoffice",
+		Interface:   "home",
+		PathPattern: "/home/test/Documents/**",
+		Permissions: []string{"read"},
+		Outcome:     prompting.OutcomeAllow,
+		Lifespan:    prompting.LifespanForever,
+	}, &addRuleContents{})
+	c.Assert(err, IsNil)
+	s.checkNewNoticesSimple(c, nil, existing)
+
+	var policy requestrules.Policy
+	c.Assert(json.Unmarshal([]byte(`{
+	"version": 1,
+	"rules": [
+		{"snap": "libreoffice", "interface": "home", "path-pattern": "~/Documents/**", "permissions": ["read"], "outcome": "allow"},
+		{"snap": "libreoffice", "interface": "home", "path-pattern": "~/Templates/**", "permissions": ["read", "write"], "outcome": "allow"},
+		{"snap": "firefox", "interface": "camera", "path-pattern": "/dev/video*", "permissions": ["access"], "outcome": "deny", "lifespan": "timespan", "duration": "1h"},
+		{"snap": "firefox", "interface": "camera", "path-pattern": "/dev/video*", "permissions": ["access"], "outcome": "deny", "lifespan": "timespan", "duration": "1h"}
+	]
+}`), &policy), IsNil)
+
+	added, removed, err := rdb.ImportPolicy(s.defaultUser, "/home/test", &policy, false)
+	c.Assert(err, IsNil)
+	c.Check(removed, HasLen, 0)
+	// The rule identical to the existing one and the duplicate are skipped
+	c.Assert(added, HasLen, 2)
+	c.Check(added[0].User, Equals, s.defaultUser)
+	c.Check(added[0].Snap, Equals, "libreoffice")
+	c.Check(added[0].Constraints.PathPattern.String(), Equals, "/home/test/Templates/**")
+	c.Check(added[0].Lifespan, Equals, prompting.LifespanForever)
+	c.Check(added[1].Interface, Equals, "camera")
+	c.Check(added[1].Lifespan, Equals, prompting.LifespanTimespan)
+	c.Check(added[1].Expiration.IsZero(), Equals, false)
+
+	s.checkWrittenRuleDB(c, []*requestrules.Rule{existing, added[0], added[1]})
+	s.checkNewNoticesSimple(c, nil, added...)
+
+	// Importing the same policy again has no effect on forever rules
+	added, removed, err = rdb.ImportPolicy(s.defaultUser, "/home/test", &policy, false)
+	c.Assert(err, IsNil)
+	c.Check(removed, HasLen, 0)
+	c.Check(added, HasLen, 0)
+}
+
+func (s *requestrulesSuite) TestImportPolicyReplace(c *C) {
+	rdb, err := requestrules.New(s.defaultNotifyRule)
+	c.Assert(err, IsNil)
+
+	template := &addRuleContents{
+		User:        s.defaultUser,
+		Snap:        "libreoffice",
+		Interface:   "home",
+		Permissions: []string{"read"},
+		Outcome:     prompting.OutcomeAllow,
+		Lifespan:    prompting.LifespanForever,
+	}
+	var userRules []*requestrules.Rule
+	for _, ruleContents := range []*addRuleContents{
+		{PathPattern: "/home/test/Documents/**"},
+		{PathPattern: "/home/test/Pictures/**", Outcome: prompting.OutcomeDeny},
+	} {
+		rule, err := addRuleFromTemplate(c, rdb, template, ruleContents)
+		c.Assert(err, IsNil)
+		userRules = append(userRules, rule)
+	}
+	otherRule, err := addRuleFromTemplate(c, rdb, template, &addRuleContents{User: s.defaultUser + 1, PathPattern: "/home/other/**"})
+	c.Assert(err, IsNil)
+	s.checkNewNoticesSimple(c, nil, userRules[0], userRules[1], otherRule)
+
+	// The new policy would conflict with an existing rule if merged
+	policy := &requestrules.Policy{
+		Version: requestrules.PolicyVersion,
+		Rules: []*requestrules.PolicyRule{
+			{
+				Snap:        "libreoffice",
+				Interface:   "home",
+				PathPattern: "~/Pictures/**",
+				Permissions: []string{"read"},
+				Outcome:     prompting.OutcomeAllow,
+			},
+		},
+	}
+	_, _, err = rdb.ImportPolicy(s.defaultUser, "/home/test", policy, false)
+	c.Check(err, ErrorMatches, "cannot import policy: a rule with conflicting path pattern and permission already exists.*")
+	s.checkWrittenRuleDB(c, []*requestrules.Rule{userRules[0], userRules[1], otherRule})
+	s.checkNewNotices(c, nil)
+
+	added, removed, err := rdb.ImportPolicy(s.defaultUser, "/home/test", policy, true)
+	c.Assert(err, IsNil)
+	c.Check(removed, HasLen, 2)
+	c.Assert(added, HasLen, 1)
+	c.Check(added[0].Constraints.PathPattern.String(), Equals, "/home/test/Pictures/**")
+
+	c.Check(rdb.Rules(s.defaultUser), DeepEquals, added)
+	c.Check(rdb.Rules(s.defaultUser+1), DeepEquals, []*requestrules.Rule{otherRule})
+
+	expectedNotices := []*noticeInfo{}
+	for _, rule := range removed {
+		expectedNotices = append(expectedNotices, &noticeInfo{userID: s.defaultUser, ruleID: rule.ID, data: map[string]string{"removed": "removed"}})
+	}
+	expectedNotices = append(expectedNotices, &noticeInfo{userID: s.defaultUser, ruleID: added[0].ID})
+	s.checkNewNotices(c, expectedNotices)
+}
+
+func (s *requestrulesSuite) TestImportPolicyConflictRollback(c *C) {
+	rdb, err := requestrules.New(s.defaultNotifyRule)
+	c.Assert(err, IsNil)
+
+	existing, err := addRuleFromTemplate(c, rdb, &addRuleContents{
+		User:        s.defaultUser,
+		Snap:        "libreoffice",
+		Interface:   "home",
+		PathPattern: "/home/test/Documents/**",
+		Permissions: []string{"read"},
+		Outcome:     prompting.OutcomeAllow,
+		Lifespan:    prompting.LifespanForever,
+	}, &addRuleContents{})
+	c.Assert(err, IsNil)
+	s.checkNewNoticesSimple(c, nil, existing)
+
+	// The second rule of the policy conflicts with the first one
+	policy := &requestrules.Policy{
+		Version: requestrules.PolicyVersion,
+		Rules: []*requestrules.PolicyRule{
+			{
+				Snap:        "libreoffice",
+				Interface:   "home",
+				PathPattern: "~/Templates/**",
+				Permissions: []string{"read"},
+				Outcome:     prompting.OutcomeAllow,
+			},
+			{
+				Snap:        "libreoffice",
+				Interface:   "home",
+				PathPattern: "~/{Templates,Music}/**",
+				Permissions: []string{"read"},
+				Outcome:     prompting.OutcomeDeny,
+			},
+		},
+	}
+	for _, replace := range []bool{false, true} {
+		_, _, err = rdb.ImportPolicy(s.defaultUser, "/home/test", policy, replace)
+		c.Check(err, ErrorMatches, "cannot import policy: a rule with conflicting path pattern and permission already exists.*")
+
+		// The rule DB is left unchanged
+		c.Check(rdb.Rules(s.defaultUser), DeepEquals, []*requestrules.Rule{existing})
+		s.checkWrittenRuleDB(c, []*requestrules.Rule{existing})
+		s.checkNewNotices(c, nil)
+		allowed, err := rdb.IsPathAllowed(s.defaultUser, "libreoffice", "home", "/home/test/Documents/foo", "read")
+		c.Check(err, IsNil)
+		c.Check(allowed, Equals, true)
+	}
+}
+
+func (s *requestrulesSuite) TestImportPolicyErrors(c *C) {
+	rdb, err := requestrules.New(s.defaultNotifyRule)
+	c.Assert(err, IsNil)
+
+	valid := requestrules.PolicyRule{
+		Snap:        "libreoffice",
+		Interface:   "home",
+		PathPattern: "~/Documents/**",
+		Permissions: []string{"read"},
+		Outcome:     prompting.OutcomeAllow,
+	}
+
+	for _, testCase := range []struct {
+		version int
+		modify  func(rule *requestrules.PolicyRule)
+		errStr  string
+	}{
+		{
+			version: 2,
+			modify:  func(rule *requestrules.PolicyRule) {},
+			errStr:  `invalid policy version: 2`,
+		},
+		{
+			modify: func(rule *requestrules.PolicyRule) { rule.Snap = "" },
+			errStr: `invalid rule 2 in policy: invalid snap: .*: ""`,
+		},
+		{
+			modify: func(rule *requestrules.PolicyRule) { rule.Interface = "foo" },
+			errStr: `invalid rule 2 in policy: invalid interface: "foo"`,
+		},
+		{
+			modify: func(rule *requestrules.PolicyRule) { rule.Permissions = []string{"access"} },
+			errStr: `invalid rule 2 in policy: invalid permissions for home interface: "access"`,
+		},
+		{
+			modify: func(rule *requestrules.PolicyRule) { rule.PathPattern = "Documents/**" },
+			errStr: `invalid rule 2 in policy: invalid path pattern: .*`,
+		},
+		{
+			modify: func(rule *requestrules.PolicyRule) { rule.Outcome = prompting.OutcomeUnset },
+			errStr: `invalid rule 2 in policy: invalid outcome: ""`,
+		},
+		{
+			modify: func(rule *requestrules.PolicyRule) { rule.Lifespan = prompting.LifespanSingle },
+			errStr: `invalid rule 2 in policy: cannot create rule with lifespan "single"`,
+		},
+		{
+			modify: func(rule *requestrules.PolicyRule) { rule.Duration = "1h" },
+			errStr: `invalid rule 2 in policy: invalid duration: .*`,
+		},
+	} {
+		version := testCase.version
+		if version == 0 {
+			version = requestrules.PolicyVersion
+		}
+		invalid := valid
+		testCase.modify(&invalid)
+		policy := &requestrules.Policy{
+			Version: version,
+			Rules:   []*requestrules.PolicyRule{&valid, &invalid},
+		}
+		c.Check(policy.Validate(), ErrorMatches, testCase.errStr)
+		_, _, err = rdb.ImportPolicy(s.defaultUser, "/home/test", policy, true)
+		c.Check(err, ErrorMatches, testCase.errStr)
+		c.Check(rdb.Rules(s.defaultUser), HasLen, 0)
+		s.checkNewNotices(c, nil)
+	}
+
+	c.Assert(rdb.Close(), IsNil)
+	policy := &requestrules.Policy{
+		Version: requestrules.PolicyVersion,
+		Rules:   []*requestrules.PolicyRule{&valid},
+	}
+	_, _, err = rdb.ImportPolicy(s.defaultUser, "/home/test", policy, false)
+	c.Check(err, ErrorMatches, "rules backend has already been closed")
+}
@@ -28,6 +28,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -747,7 +748,21 @@ func (rdb *RuleDB) AddRule(user uint32, snap string, iface string, constraints *
 // of the rule which is returned. If any of the given parameters are invalid,
 // returns a corresponding error.
 func (rdb *RuleDB) makeNewRule(user uint32, snap string, iface string, constraints *prompting.Constraints, outcome prompting.OutcomeType, lifespan prompting.LifespanType, duration string) (*Rule, error) {
-	currTime := time.Now()
+	newRule, err := buildRule(user, snap, iface, constraints, outcome, lifespan, duration, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't consume an ID until now, when we know the rule is valid
+	id, _ := rdb.maxIDMmap.NextID()
+	newRule.ID = id
+
+	return newRule, nil
+}
+
+// buildRule creates and validates a new Rule with the given contents and the
+// given timestamp, but does not assign it an ID.
+func buildRule(user uint32, snap string, iface string, constraints *prompting.Constraints, outcome prompting.OutcomeType, lifespan prompting.LifespanType, duration string, currTime time.Time) (*Rule, error) {
 	expiration, err := lifespan.ParseDuration(duration, currTime)
 	if err != nil {
 		return nil, err
@@ -768,10 +783,6 @@ func (rdb *RuleDB) makeNewRule(user uint32, snap string, iface string, constrain
 		return nil, err
 	}
 
-	// Don't consume an ID until now, when we know the rule is valid
-	id, _ := rdb.maxIDMmap.NextID()
-	newRule.ID = id
-
 	return &newRule, nil
 }
 
@@ -825,6 +836,123 @@ func (rdb *RuleDB) IsPathAllowed(user uint32, snap string, iface string, path st
 	return matchingEntry.Outcome.AsBool()
 }
 
+// Explanation describes how the rules for a given user, snap, and interface
+// apply to a request for a given path and permission.
+type Explanation struct {
+	Snap       string `json:"snap"`
+	Interface  string `json:"interface"`
+	Path       string `json:"path"`
+	Permission string `json:"permission"`
+	// Outcome is the outcome of the match with the highest precedence. If no
+	// rule matches, it is unset, and the request results in a prompt.
+	Outcome prompting.OutcomeType `json:"outcome,omitempty"`
+	// Matches holds the path pattern variants which match the path, ordered
+	// from the highest precedence to the lowest.
+	Matches []*ExplanationMatch `json:"matches"`
+}
+
+// ExplanationMatch is a path pattern variant which matches the path of an
+// explanation, along with the rules whose path patterns render to it.
+type ExplanationMatch struct {
+	Variant string                `json:"variant"`
+	Outcome prompting.OutcomeType `json:"outcome"`
+	Rules   []*ExplainedRule      `json:"rules"`
+}
+
+// ExplainedRule is a rule which matches the path of an explanation.
+type ExplainedRule struct {
+	*Rule
+	// Remaining is the time left until the rule expires, if it has a
+	// lifespan of timespan.
+	Remaining string `json:"remaining,omitempty"`
+}
+
+// Explain returns which of the rules for the given user, snap, and interface
+// match the given path and permission, and which of them decides the outcome
+// of a request for that path and permission, if any.
+func (rdb *RuleDB) Explain(user uint32, snap string, iface string, path string, permission string) (*Explanation, error) {
+	if err := prompting.ValidatePermission(iface, permission); err != nil {
+		return nil, err
+	}
+
+	rdb.mutex.RLock()
+	defer rdb.mutex.RUnlock()
+
+	explanation := &Explanation{
+		Snap:       snap,
+		Interface:  iface,
+		Path:       path,
+		Permission: permission,
+		Matches:    []*ExplanationMatch{},
+	}
+	permissionMap, ok := rdb.permissionDBForUserSnapInterfacePermission(user, snap, iface, permission)
+	if !ok || permissionMap == nil {
+		return explanation, nil
+	}
+
+	matches := make(map[string]*ExplanationMatch)
+	var matchingVariants []patterns.PatternVariant
+	currTime := time.Now()
+	for variantStr, variantEntry := range permissionMap.VariantEntries {
+		matched, err := patterns.PathPatternMatches(variantStr, path)
+		if err != nil {
+			// Only possible error is ErrBadPattern, which should not occur
+			return nil, fmt.Errorf("internal error: while matching path pattern: %w", err)
+		}
+		if !matched {
+			continue
+		}
+		var rules []*ExplainedRule
+		for id := range variantEntry.RuleIDs {
+			rule, err := rdb.lookupRuleByID(id)
+			if err != nil || rule.Expired(currTime) {
+				continue
+			}
+			explained := &ExplainedRule{Rule: rule}
+			if rule.Lifespan == prompting.LifespanTimespan {
+				explained.Remaining = rule.Expiration.Sub(currTime).Round(time.Second).String()
+			}
+			rules = append(rules, explained)
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		sort.Slice(rules, func(i, j int) bool {
+			return rules[i].ID < rules[j].ID
+		})
+		matches[variantStr] = &ExplanationMatch{
+			Variant: variantStr,
+			Outcome: variantEntry.Outcome,
+			Rules:   rules,
+		}
+		matchingVariants = append(matchingVariants, variantEntry.Variant)
+	}
+
+	// Sort by the rendered variants first, so the order is deterministic
+	// even among variants of equal precedence.
+	sort.Slice(matchingVariants, func(i, j int) bool {
+		return matchingVariants[i].String() < matchingVariants[j].String()
+	})
+	var compareErr error
+	sort.SliceStable(matchingVariants, func(i, j int) bool {
+		result, err := matchingVariants[i].Compare(matchingVariants[j], path)
+		if err != nil && compareErr == nil {
+			compareErr = err
+		}
+		return result > 0
+	})
+	if compareErr != nil {
+		return nil, compareErr
+	}
+	for _, variant := range matchingVariants {
+		explanation.Matches = append(explanation.Matches, matches[variant.String()])
+	}
+	if len(explanation.Matches) > 0 {
+		explanation.Outcome = explanation.Matches[0].Outcome
+	}
+	return explanation, nil
+}
+
 // RuleWithID returns the rule with the given ID.
 // If the rule is not found, returns ErrRuleNotFound.
 // If the rule does not apply to the given user, returns
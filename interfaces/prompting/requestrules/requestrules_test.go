@@ -1008,6 +1008,64 @@ func (s *requestrulesSuite) TestIsPathAllowedSimple(c *C) {
 	}
 }
 
+func (s *requestrulesSuite) TestIsPathAllowedOtherInterfaces(c *C) {
+	rdb, err := requestrules.New(s.defaultNotifyRule)
+	c.Assert(err, IsNil)
+	c.Assert(rdb, NotNil)
+
+	var addedRules []*requestrules.Rule
+	for _, testCase := range []struct {
+		iface       string
+		pathPattern string
+		path        string
+		permission  string
+	}{
+		{"personal-files", "/home/test/.config/foo/**", "/home/test/.config/foo/bar", "write"},
+		{"removable-media", "/media/test/usb/**", "/media/test/usb/file.txt", "read"},
+		{"camera", "/dev/video*", "/dev/video0", "access"},
+		{"audio-record", "/dev/snd/pcmC*D*c", "/dev/snd/pcmC0D0c", "access"},
+	} {
+		template := &addRuleContents{
+			User:        s.defaultUser,
+			Snap:        "firefox",
+			Interface:   testCase.iface,
+			PathPattern: testCase.pathPattern,
+			Permissions: []string{testCase.permission},
+			Outcome:     prompting.OutcomeAllow,
+			Lifespan:    prompting.LifespanForever,
+		}
+		rule, err := addRuleFromTemplate(c, rdb, template, nil)
+		c.Assert(err, IsNil, Commentf("testCase: %+v", testCase))
+		addedRules = append(addedRules, rule)
+		s.checkWrittenRuleDB(c, addedRules)
+		s.checkNewNoticesSimple(c, nil, rule)
+
+		allowed, err := rdb.IsPathAllowed(s.defaultUser, "firefox", testCase.iface, testCase.path, testCase.permission)
+		c.Check(err, IsNil, Commentf("testCase: %+v", testCase))
+		c.Check(allowed, Equals, true, Commentf("testCase: %+v", testCase))
+
+		// Rules only apply to requests for the interface for which they were created
+		_, err = rdb.IsPathAllowed(s.defaultUser, "firefox", "home", testCase.path, "read")
+		c.Check(err, Equals, prompting_errors.ErrNoMatchingRule, Commentf("testCase: %+v", testCase))
+	}
+
+	// Path patterns outside of the paths of the interface are rejected
+	template := &addRuleContents{
+		User:        s.defaultUser,
+		Snap:        "firefox",
+		Interface:   "removable-media",
+		PathPattern: "/home/test/**",
+		Permissions: []string{"read"},
+		Outcome:     prompting.OutcomeAllow,
+		Lifespan:    prompting.LifespanForever,
+	}
+	rule, err := addRuleFromTemplate(c, rdb, template, nil)
+	c.Check(err, ErrorMatches, `invalid path pattern: pattern for the removable-media interface must begin with one of .*`)
+	c.Check(rule, IsNil)
+	s.checkWrittenRuleDB(c, addedRules)
+	s.checkNewNoticesSimple(c, nil)
+}
+
 func (s *requestrulesSuite) TestIsPathAllowedPrecedence(c *C) {
 	// Target
 	user := s.defaultUser
@@ -1146,6 +1204,102 @@ func (s *requestrulesSuite) TestIsPathAllowedExpiration(c *C) {
 	}
 }
 
+func (s *requestrulesSuite) TestExplain(c *C) {
+	user := s.defaultUser
+	snap := "firefox"
+	iface := "home"
+	path := "/home/test/Documents/foo/bar/file.txt"
+
+	template := &addRuleContents{
+		User:        user,
+		Snap:        snap,
+		Interface:   iface,
+		Permissions: []string{"read"},
+		Outcome:     prompting.OutcomeAllow,
+		Lifespan:    prompting.LifespanForever,
+	}
+
+	rdb, err := requestrules.New(s.defaultNotifyRule)
+	c.Assert(err, IsNil)
+
+	explanation, err := rdb.Explain(user, snap, iface, path, "read")
+	c.Assert(err, IsNil)
+	c.Check(explanation, DeepEquals, &requestrules.Explanation{
+		Snap:       snap,
+		Interface:  iface,
+		Path:       path,
+		Permission: "read",
+		Matches:    []*requestrules.ExplanationMatch{},
+	})
+
+	var rules []*requestrules.Rule
+	for _, ruleContents := range []*addRuleContents{
+		{PathPattern: "/home/test/**"},
+		{PathPattern: "/home/test/Documents/**", Outcome: prompting.OutcomeDeny, Lifespan: prompting.LifespanTimespan, Duration: "1h"},
+		{PathPattern: "/home/test/Documents/foo/{bar,baz}/*.txt"},
+		{PathPattern: "/home/test/Documents/foo/bar/*.{txt,md}"},
+		// does not match the path
+		{PathPattern: "/home/test/Pictures/**", Outcome: prompting.OutcomeDeny},
+		// does not apply to the permission
+		{PathPattern: "/home/test/Documents/foo/bar/file.txt", Permissions: []string{"write"}},
+	} {
+		rule, err := addRuleFromTemplate(c, rdb, template, ruleContents)
+		c.Assert(err, IsNil)
+		rules = append(rules, rule)
+	}
+
+	explanation, err = rdb.Explain(user, snap, iface, path, "read")
+	c.Assert(err, IsNil)
+	c.Check(explanation.Outcome, Equals, prompting.OutcomeAllow)
+	c.Assert(explanation.Matches, HasLen, 3)
+
+	// Both rules render to the most specific variant
+	c.Check(explanation.Matches[0].Variant, Equals, "/home/test/Documents/foo/bar/*.txt")
+	c.Check(explanation.Matches[0].Outcome, Equals, prompting.OutcomeAllow)
+	c.Assert(explanation.Matches[0].Rules, HasLen, 2)
+	c.Check(explanation.Matches[0].Rules[0].Rule, Equals, rules[2])
+	c.Check(explanation.Matches[0].Rules[1].Rule, Equals, rules[3])
+	c.Check(explanation.Matches[0].Rules[0].Remaining, Equals, "")
+
+	c.Check(explanation.Matches[1].Variant, Equals, "/home/test/Documents/**")
+	c.Check(explanation.Matches[1].Outcome, Equals, prompting.OutcomeDeny)
+	c.Assert(explanation.Matches[1].Rules, HasLen, 1)
+	c.Check(explanation.Matches[1].Rules[0].Rule, Equals, rules[1])
+	remaining, err := time.ParseDuration(explanation.Matches[1].Rules[0].Remaining)
+	c.Assert(err, IsNil)
+	c.Check(remaining > 59*time.Minute && remaining <= time.Hour, Equals, true, Commentf("remaining: %v", remaining))
+
+	c.Check(explanation.Matches[2].Variant, Equals, "/home/test/**")
+	c.Check(explanation.Matches[2].Rules[0].Rule, Equals, rules[0])
+
+	// Expired rules are not considered
+	rules[2].Expiration = time.Now().Add(-time.Second)
+	rules[2].Lifespan = prompting.LifespanTimespan
+	rules[3].Expiration = time.Now().Add(-time.Second)
+	rules[3].Lifespan = prompting.LifespanTimespan
+	explanation, err = rdb.Explain(user, snap, iface, path, "read")
+	c.Assert(err, IsNil)
+	c.Check(explanation.Outcome, Equals, prompting.OutcomeDeny)
+	c.Assert(explanation.Matches, HasLen, 2)
+	c.Check(explanation.Matches[0].Variant, Equals, "/home/test/Documents/**")
+
+	// Rules of other users do not apply
+	explanation, err = rdb.Explain(user+1, snap, iface, path, "read")
+	c.Assert(err, IsNil)
+	c.Check(explanation.Outcome, Equals, prompting.OutcomeUnset)
+	c.Check(explanation.Matches, HasLen, 0)
+}
+
+func (s *requestrulesSuite) TestExplainErrors(c *C) {
+	rdb, err := requestrules.New(s.defaultNotifyRule)
+	c.Assert(err, IsNil)
+
+	_, err = rdb.Explain(s.defaultUser, "firefox", "foo", "/home/test/foo", "read")
+	c.Check(err, ErrorMatches, `invalid interface: "foo"`)
+	_, err = rdb.Explain(s.defaultUser, "firefox", "home", "/home/test/foo", "access")
+	c.Check(err, ErrorMatches, `invalid permissions for home interface: "access"`)
+}
+
 func (s *requestrulesSuite) TestRuleWithID(c *C) {
 	rdb, _ := requestrules.New(s.defaultNotifyRule)
 
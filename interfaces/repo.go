@@ -69,6 +69,51 @@ func NewRepository() *Repository {
 	return repo
 }
 
+// Copy returns a copy of the repository which shares its interfaces,
+// backends and snap information, but whose snaps, plugs, slots and
+// connections can be changed without affecting the original.
+func (r *Repository) Copy() *Repository {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	repo := NewRepository()
+	for name, iface := range r.ifaces {
+		repo.ifaces[name] = iface
+	}
+	for name, iface := range r.hotplugIfaces {
+		repo.hotplugIfaces[name] = iface
+	}
+	for snapName, plugs := range r.plugs {
+		repo.plugs[snapName] = make(map[string]*snap.PlugInfo, len(plugs))
+		for name, plug := range plugs {
+			repo.plugs[snapName][name] = plug
+		}
+	}
+	for snapName, slots := range r.slots {
+		repo.slots[snapName] = make(map[string]*snap.SlotInfo, len(slots))
+		for name, slot := range slots {
+			repo.slots[snapName][name] = slot
+		}
+	}
+	for slot, plugs := range r.slotPlugs {
+		repo.slotPlugs[slot] = make(map[*snap.PlugInfo]*Connection, len(plugs))
+		for plug, conn := range plugs {
+			repo.slotPlugs[slot][plug] = conn
+		}
+	}
+	for plug, slots := range r.plugSlots {
+		repo.plugSlots[plug] = make(map[*snap.SlotInfo]*Connection, len(slots))
+		for slot, conn := range slots {
+			repo.plugSlots[plug][slot] = conn
+		}
+	}
+	repo.backends = append(repo.backends, r.backends...)
+	for snapName, appSet := range r.appSets {
+		repo.appSets[snapName] = appSet
+	}
+	return repo
+}
+
 func ResetRepository(repo *Repository) {
 	osutil.MustBeTestBinary("cannot use the ResetRepository method outside of tests")
 	repo.ifaces = make(map[string]Interface)
@@ -168,6 +168,34 @@ func addPlugsSlotsFromInstances(c *C, repo *Repository, iys []instanceNameAndYam
 	return result
 }
 
+// Tests for Repository.Copy()
+
+func (s *RepositorySuite) TestCopy(c *C) {
+	c.Assert(s.testRepo.AddAppSet(s.consumer), IsNil)
+	c.Assert(s.testRepo.AddAppSet(s.producer), IsNil)
+	connRef := NewConnRef(s.consumerPlug, s.producerSlot)
+	_, err := s.testRepo.Connect(connRef, nil, nil, nil, nil, nil)
+	c.Assert(err, IsNil)
+
+	repo := s.testRepo.Copy()
+	c.Check(repo.Interface("interface"), Equals, s.iface)
+	c.Check(repo.Interfaces(), DeepEquals, s.testRepo.Interfaces())
+
+	// changes to the copy do not affect the original
+	_, err = repo.DisconnectSnap("producer")
+	c.Assert(err, IsNil)
+	c.Assert(repo.RemoveSnap("producer"), IsNil)
+	c.Check(repo.Slot("producer", "slot"), IsNil)
+	conns, err := repo.Connected("consumer", "plug")
+	c.Assert(err, IsNil)
+	c.Check(conns, HasLen, 0)
+
+	c.Check(s.testRepo.Slot("producer", "slot"), Equals, s.producerSlot)
+	conns, err = s.testRepo.Connected("consumer", "plug")
+	c.Assert(err, IsNil)
+	c.Check(conns, DeepEquals, []*ConnRef{connRef})
+}
+
 // Tests for Repository.AddInterface()
 
 func (s *RepositorySuite) TestAddInterface(c *C) {
@@ -245,6 +245,25 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 	return parallelCompile(b.snapSeccomp, changed)
 }
 
+// PreviewProfiles returns the seccomp profiles that Setup would write for the
+// given snap, without writing or compiling them.
+func (b *Backend) PreviewProfiles(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (map[string][]byte, error) {
+	snapName := appSet.InstanceName()
+	spec, err := repo.SnapSpecification(b.Name(), appSet, opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot obtain seccomp specification for snap %q: %s", snapName, err)
+	}
+	content, err := b.deriveContent(spec.(*Specification), opts, appSet)
+	if err != nil {
+		return nil, fmt.Errorf("cannot obtain expected security files for snap %q: %s", snapName, err)
+	}
+	preview := make(map[string][]byte)
+	if err := interfaces.PreviewContent(preview, dirs.SnapSeccompDir, content); err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
 // Remove removes seccomp profiles of a given snap.
 func (b *Backend) Remove(snapName string) error {
 	globs := interfaces.SecurityTagGlobs(snapName)
@@ -1087,3 +1087,11 @@ func (s *backendSuite) TestParallelCompileRemovesFirst(c *C) {
 	err = seccomp.ParallelCompile(&m, []string{"profile-001"})
 	c.Assert(err, ErrorMatches, "remove .*/profile-001.bin2: permission denied")
 }
+
+func (s *backendSuite) TestPreviewProfiles(c *C) {
+	preview := s.PreviewAndInstallSnap(c, interfaces.ConfinementOptions{}, ifacetest.SambaYamlV1, 0)
+	c.Assert(preview, HasLen, 1)
+	c.Check(string(preview[filepath.Join(dirs.SnapSeccompDir, "snap.samba.smbd.src")]), testutil.Contains, "# - create_module, init_module, finit_module, delete_module (kernel modules)\n")
+	// the previewed profile was not compiled before the snap was set up
+	c.Check(s.snapSeccomp.Calls(), HasLen, 1)
+}
@@ -110,22 +110,8 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 			needReload = true
 		}
 	} else {
-		var rulesBuf bytes.Buffer
-		rulesBuf.WriteString("# This file is automatically generated.\n")
-		if (opts.DevMode || opts.Classic) && !opts.JailMode {
-			rulesBuf.WriteString("# udev tagging/device cgroups disabled with non-strict mode snaps\n")
-		}
-		for _, snippet := range content {
-			if (opts.DevMode || opts.Classic) && !opts.JailMode {
-				rulesBuf.WriteRune('#')
-				snippet = strings.Replace(snippet, "\n", "\n#", -1)
-			}
-			rulesBuf.WriteString(snippet)
-			rulesBuf.WriteByte('\n')
-		}
-
 		rulesFileState := &osutil.MemoryFileState{
-			Content: rulesBuf.Bytes(),
+			Content: rulesContent(content, opts),
 			Mode:    0644,
 		}
 
@@ -151,6 +137,39 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 		}
 	}
 
+	// the file serves as a checkpoint that udev backend was set up
+	err = osutil.EnsureFileState(selfManageDeviceCgroupPath, &osutil.MemoryFileState{
+		Content: deviceCgroupContent(udevSpec, opts),
+		Mode:    0644,
+	})
+	if err != nil && !errors.Is(err, osutil.ErrSameState) {
+		return err
+	}
+	return nil
+}
+
+// rulesContent returns the content of the udev rules file of a snap with the
+// given rules.
+func rulesContent(content []string, opts interfaces.ConfinementOptions) []byte {
+	var rulesBuf bytes.Buffer
+	rulesBuf.WriteString("# This file is automatically generated.\n")
+	if (opts.DevMode || opts.Classic) && !opts.JailMode {
+		rulesBuf.WriteString("# udev tagging/device cgroups disabled with non-strict mode snaps\n")
+	}
+	for _, snippet := range content {
+		if (opts.DevMode || opts.Classic) && !opts.JailMode {
+			rulesBuf.WriteRune('#')
+			snippet = strings.Replace(snippet, "\n", "\n#", -1)
+		}
+		rulesBuf.WriteString(snippet)
+		rulesBuf.WriteByte('\n')
+	}
+	return rulesBuf.Bytes()
+}
+
+// deviceCgroupContent returns the content of the file with the device cgroup
+// flags of a snap for snap-confine.
+func deviceCgroupContent(udevSpec *Specification, opts interfaces.ConfinementOptions) []byte {
 	var deviceBuf bytes.Buffer
 	deviceBuf.WriteString("# This file is automatically generated.\n")
 
@@ -166,16 +185,26 @@ func (b *Backend) Setup(appSet *interfaces.SnapAppSet, opts interfaces.Confineme
 		deviceBuf.WriteString("# snap uses non-strict confinement.\n")
 		deviceBuf.WriteString("non-strict=true\n")
 	}
+	return deviceBuf.Bytes()
+}
 
-	// the file serves as a checkpoint that udev backend was set up
-	err = osutil.EnsureFileState(selfManageDeviceCgroupPath, &osutil.MemoryFileState{
-		Content: deviceBuf.Bytes(),
-		Mode:    0644,
-	})
-	if err != nil && !errors.Is(err, osutil.ErrSameState) {
-		return err
+// PreviewProfiles returns the udev rules and device cgroup flags that Setup
+// would write for the given snap, without writing them or reloading udev.
+func (b *Backend) PreviewProfiles(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (map[string][]byte, error) {
+	snapName := appSet.InstanceName()
+	spec, err := repo.SnapSpecification(b.Name(), appSet, opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot obtain udev specification for snap %q: %w", snapName, err)
 	}
-	return nil
+
+	udevSpec := spec.(*Specification)
+	preview := map[string][]byte{
+		snapDeviceCgroupSelfManageFilePath(snapName): deviceCgroupContent(udevSpec, opts),
+	}
+	if content := b.deriveContent(udevSpec); len(content) > 0 && !udevSpec.ControlsDeviceCgroup() {
+		preview[snapRulesFilePath(snapName)] = rulesContent(content, opts)
+	}
+	return preview, nil
 }
 
 // Remove removes udev rules specific to a given snap.
@@ -659,3 +659,15 @@ func (s *backendSuite) TestPreseed(c *C) {
 
 	c.Check(s.udevadmCmd.Calls(), HasLen, 0)
 }
+
+func (s *backendSuite) TestPreviewProfiles(c *C) {
+	s.Iface.UDevPermanentSlotCallback = func(spec *udev.Specification, slot *snap.SlotInfo) error {
+		spec.AddSnippet("sample")
+		return nil
+	}
+	preview := s.PreviewAndInstallSnap(c, interfaces.ConfinementOptions{DevMode: true}, ifacetest.SambaYamlV1, 0)
+	c.Check(preview, DeepEquals, map[string][]byte{
+		filepath.Join(dirs.SnapUdevRulesDir, "70-snap.samba.rules"):  []byte("# This file is automatically generated.\n# udev tagging/device cgroups disabled with non-strict mode snaps\n#sample\n"),
+		filepath.Join(dirs.SnapCgroupPolicyDir, "snap.samba.device"): []byte("# This file is automatically generated.\n# snap uses non-strict confinement.\nnon-strict=true\n"),
+	})
+}
@@ -0,0 +1,476 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package metrics implements simple in-process counters, gauges and
+// histograms which can be exposed in the OpenMetrics text format.
+//
+// Metrics are declared at package level and registered with the default
+// registry, for example:
+//
+//	var requests = metrics.MustRegister(metrics.NewCounterVec(
+//		"snapd_store_requests", "Store requests by method and code.", "method", "code"))
+//
+//	requests.With("GET", "200").Inc()
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the exposition format produced by
+// Write.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Type is the type of a metric family.
+type Type string
+
+const (
+	CounterType   Type = "counter"
+	GaugeType     Type = "gauge"
+	HistogramType Type = "histogram"
+)
+
+// DefBuckets are the default histogram buckets, in seconds, suitable for
+// durations of operations ranging from milliseconds to minutes.
+var DefBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+
+// Collector is implemented by metric families which can be written out.
+type Collector interface {
+	// Name returns the name of the metric family.
+	Name() string
+	// Write writes the metric family in the OpenMetrics text format.
+	Write(w io.Writer) error
+}
+
+type desc struct {
+	name   string
+	help   string
+	typ    Type
+	labels []string
+}
+
+func (d *desc) Name() string {
+	return d.name
+}
+
+func (d *desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("internal error: metric %q expects %d label values, got %d", d.name, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\x00")
+}
+
+func (d *desc) writeHeader(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# TYPE %s %s\n# HELP %s %s\n", d.name, d.typ, d.name, escapeHelp(d.help))
+	return err
+}
+
+// formatLabels formats the given label names and values as a label set,
+// including the braces, or returns an empty string if there are none.
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	var buf strings.Builder
+	buf.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, "%s=\"%s\"", name, escapeLabelValue(values[i]))
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
+
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(v string) string {
+	return helpReplacer.Replace(v)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// sortedKeys returns the keys of the given map in a stable order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func splitKey(key string, n int) []string {
+	if n == 0 {
+		return nil
+	}
+	return strings.Split(key, "\x00")
+}
+
+// Counter is a monotonically increasing value.
+type Counter struct {
+	mu    sync.Mutex
+	value float64
+}
+
+// Inc increments the counter by one.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds the given non-negative value to the counter.
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		panic("internal error: counters cannot decrease")
+	}
+	c.mu.Lock()
+	c.value += v
+	c.mu.Unlock()
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+// CounterVec is a family of counters partitioned by label values.
+type CounterVec struct {
+	desc
+	mu       sync.Mutex
+	counters map[string]*Counter
+}
+
+// NewCounterVec returns a new family of counters with the given name, help
+// text and label names. The "_total" suffix is appended to the name of the
+// samples when writing them out.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{
+		desc:     desc{name: name, help: help, typ: CounterType, labels: labels},
+		counters: make(map[string]*Counter),
+	}
+}
+
+// With returns the counter for the given label values, creating it if
+// needed.
+func (v *CounterVec) With(values ...string) *Counter {
+	key := v.key(values)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	c := v.counters[key]
+	if c == nil {
+		c = &Counter{}
+		v.counters[key] = c
+	}
+	return c
+}
+
+// Write writes the counter family in the OpenMetrics text format.
+func (v *CounterVec) Write(w io.Writer) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.writeHeader(w); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(v.counters) {
+		labels := formatLabels(v.labels, splitKey(key, len(v.labels)))
+		if _, err := fmt.Fprintf(w, "%s_total%s %s\n", v.name, labels, formatFloat(v.counters[key].Value())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Gauge is a value which can go up and down.
+type Gauge struct {
+	mu    sync.Mutex
+	value float64
+}
+
+// Set sets the gauge to the given value.
+func (g *Gauge) Set(v float64) {
+	g.mu.Lock()
+	g.value = v
+	g.mu.Unlock()
+}
+
+// Add adds the given, possibly negative, value to the gauge.
+func (g *Gauge) Add(v float64) {
+	g.mu.Lock()
+	g.value += v
+	g.mu.Unlock()
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.value
+}
+
+// GaugeVec is a family of gauges partitioned by label values.
+type GaugeVec struct {
+	desc
+	mu     sync.Mutex
+	gauges map[string]*Gauge
+}
+
+// NewGaugeVec returns a new family of gauges with the given name, help text
+// and label names.
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{
+		desc:   desc{name: name, help: help, typ: GaugeType, labels: labels},
+		gauges: make(map[string]*Gauge),
+	}
+}
+
+// With returns the gauge for the given label values, creating it if needed.
+func (v *GaugeVec) With(values ...string) *Gauge {
+	key := v.key(values)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	g := v.gauges[key]
+	if g == nil {
+		g = &Gauge{}
+		v.gauges[key] = g
+	}
+	return g
+}
+
+// Write writes the gauge family in the OpenMetrics text format.
+func (v *GaugeVec) Write(w io.Writer) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.writeHeader(w); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(v.gauges) {
+		labels := formatLabels(v.labels, splitKey(key, len(v.labels)))
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", v.name, labels, formatFloat(v.gauges[key].Value())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Histogram counts observations in cumulative buckets.
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+// Observe records a single observation.
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, upper := range h.buckets {
+		if v <= upper {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// Sum returns the sum of all observations.
+func (h *Histogram) Sum() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sum
+}
+
+// HistogramVec is a family of histograms partitioned by label values.
+type HistogramVec struct {
+	desc
+	buckets    []float64
+	mu         sync.Mutex
+	histograms map[string]*Histogram
+}
+
+// NewHistogramVec returns a new family of histograms with the given name,
+// help text, bucket upper bounds and label names. If no buckets are given
+// DefBuckets are used.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &HistogramVec{
+		desc:       desc{name: name, help: help, typ: HistogramType, labels: labels},
+		buckets:    buckets,
+		histograms: make(map[string]*Histogram),
+	}
+}
+
+// With returns the histogram for the given label values, creating it if
+// needed.
+func (v *HistogramVec) With(values ...string) *Histogram {
+	key := v.key(values)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	h := v.histograms[key]
+	if h == nil {
+		h = &Histogram{
+			buckets: v.buckets,
+			counts:  make([]uint64, len(v.buckets)),
+		}
+		v.histograms[key] = h
+	}
+	return h
+}
+
+// Write writes the histogram family in the OpenMetrics text format.
+func (v *HistogramVec) Write(w io.Writer) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.writeHeader(w); err != nil {
+		return err
+	}
+	leLabels := append(append([]string(nil), v.labels...), "le")
+	for _, key := range sortedKeys(v.histograms) {
+		values := splitKey(key, len(v.labels))
+		h := v.histograms[key]
+		h.mu.Lock()
+		var err error
+		for i, upper := range h.buckets {
+			labels := formatLabels(leLabels, append(append([]string(nil), values...), formatFloat(upper)))
+			if _, err = fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, labels, h.counts[i]); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			labels := formatLabels(leLabels, append(append([]string(nil), values...), "+Inf"))
+			_, err = fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, labels, h.count)
+		}
+		if err == nil {
+			labels := formatLabels(v.labels, values)
+			_, err = fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n", v.name, labels, formatFloat(h.sum), v.name, labels, h.count)
+		}
+		h.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Registry holds a set of metric families.
+type Registry struct {
+	mu         sync.Mutex
+	collectors map[string]Collector
+}
+
+// NewRegistry returns a new, empty registry.
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]Collector)}
+}
+
+// Register adds the given collector to the registry. It returns an error if
+// a collector with the same name is already registered.
+func (r *Registry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.collectors[c.Name()]; ok {
+		return fmt.Errorf("metric %q is already registered", c.Name())
+	}
+	r.collectors[c.Name()] = c
+	return nil
+}
+
+// Unregister removes the collector with the given name from the registry.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.collectors, name)
+}
+
+// Collectors returns the registered collectors.
+func (r *Registry) Collectors() []Collector {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	collectors := make([]Collector, 0, len(r.collectors))
+	for _, name := range sortedKeys(r.collectors) {
+		collectors = append(collectors, r.collectors[name])
+	}
+	return collectors
+}
+
+// Write writes the given collectors, sorted by name, in the OpenMetrics
+// text format, followed by the terminating EOF marker.
+func Write(w io.Writer, collectors []Collector) error {
+	sorted := append([]Collector(nil), collectors...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+	for _, c := range sorted {
+		if err := c.Write(w); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "# EOF\n")
+	return err
+}
+
+// DefaultRegistry is the registry used by MustRegister.
+var DefaultRegistry = NewRegistry()
+
+// MustRegister registers the given collector with the default registry and
+// returns it. It panics if a collector with the same name is already
+// registered.
+func MustRegister[T Collector](c T) T {
+	if err := DefaultRegistry.Register(c); err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,128 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package metrics_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/metrics"
+)
+
+func TestMetrics(t *testing.T) { TestingT(t) }
+
+type metricsSuite struct{}
+
+var _ = Suite(&metricsSuite{})
+
+func (s *metricsSuite) TestCounter(c *C) {
+	cv := metrics.NewCounterVec("requests", "Number of requests.", "method", "code")
+	cv.With("GET", "200").Inc()
+	cv.With("GET", "200").Add(2)
+	cv.With("POST", "500").Inc()
+	c.Check(cv.With("GET", "200").Value(), Equals, 3.0)
+
+	var buf bytes.Buffer
+	c.Assert(metrics.Write(&buf, []metrics.Collector{cv}), IsNil)
+	c.Check(buf.String(), Equals, `# TYPE requests counter
+# HELP requests Number of requests.
+requests_total{method="GET",code="200"} 3
+requests_total{method="POST",code="500"} 1
+# EOF
+`)
+}
+
+func (s *metricsSuite) TestCounterCannotDecrease(c *C) {
+	cv := metrics.NewCounterVec("requests", "")
+	c.Check(func() { cv.With().Add(-1) }, PanicMatches, "internal error: counters cannot decrease")
+}
+
+func (s *metricsSuite) TestWrongLabelCount(c *C) {
+	cv := metrics.NewCounterVec("requests", "", "method")
+	c.Check(func() { cv.With("GET", "200") }, PanicMatches, `internal error: metric "requests" expects 1 label values, got 2`)
+}
+
+func (s *metricsSuite) TestGaugeEscaping(c *C) {
+	gv := metrics.NewGaugeVec("info", "Some\nhelp with \\.", "version")
+	gv.With(`1.0"beta\`).Set(1)
+	gv.With("2.0").Set(5)
+	gv.With("2.0").Add(-2)
+
+	var buf bytes.Buffer
+	c.Assert(metrics.Write(&buf, []metrics.Collector{gv}), IsNil)
+	c.Check(buf.String(), Equals, `# TYPE info gauge
+# HELP info Some\nhelp with \\.
+info{version="1.0\"beta\\"} 1
+info{version="2.0"} 3
+# EOF
+`)
+}
+
+func (s *metricsSuite) TestHistogram(c *C) {
+	hv := metrics.NewHistogramVec("duration_seconds", "Durations.", []float64{1, 0.5}, "kind")
+	hv.With("a").Observe(0.25)
+	hv.With("a").Observe(0.75)
+	hv.With("a").Observe(3)
+	c.Check(hv.With("a").Count(), Equals, uint64(3))
+	c.Check(hv.With("a").Sum(), Equals, 4.0)
+
+	var buf bytes.Buffer
+	c.Assert(metrics.Write(&buf, []metrics.Collector{hv}), IsNil)
+	c.Check(buf.String(), Equals, `# TYPE duration_seconds histogram
+# HELP duration_seconds Durations.
+duration_seconds_bucket{kind="a",le="0.5"} 1
+duration_seconds_bucket{kind="a",le="1"} 2
+duration_seconds_bucket{kind="a",le="+Inf"} 3
+duration_seconds_sum{kind="a"} 4
+duration_seconds_count{kind="a"} 3
+# EOF
+`)
+}
+
+func (s *metricsSuite) TestRegistry(c *C) {
+	r := metrics.NewRegistry()
+	b := metrics.NewCounterVec("b", "")
+	a := metrics.NewGaugeVec("a", "")
+	c.Assert(r.Register(b), IsNil)
+	c.Assert(r.Register(a), IsNil)
+	c.Check(r.Register(metrics.NewGaugeVec("a", "")), ErrorMatches, `metric "a" is already registered`)
+	c.Check(r.Collectors(), DeepEquals, []metrics.Collector{a, b})
+
+	r.Unregister("a")
+	c.Check(r.Collectors(), DeepEquals, []metrics.Collector{b})
+}
+
+func (s *metricsSuite) TestWriteSorted(c *C) {
+	b := metrics.NewCounterVec("b", "B.")
+	a := metrics.NewGaugeVec("a", "A.")
+	b.With().Inc()
+
+	var buf bytes.Buffer
+	c.Assert(metrics.Write(&buf, []metrics.Collector{b, a}), IsNil)
+	c.Check(buf.String(), Equals, `# TYPE a gauge
+# HELP a A.
+# TYPE b counter
+# HELP b B.
+b_total 1
+# EOF
+`)
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/snapcore/snapd/registry"
 	"github.com/snapcore/snapd/release"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/naming"
 )
 
 // Add the given assertion to the system assertion database.
@@ -322,6 +323,84 @@ func Store(s *state.State, store string) (*asserts.Store, error) {
 	return a.(*asserts.Store), nil
 }
 
+// findWithProvenance returns the assertion of the given type matching the
+// given headers which carries the given provenance.
+func findWithProvenance(db asserts.RODatabase, assertType *asserts.AssertionType, headers map[string]string, provenance string) (asserts.Assertion, error) {
+	as, err := db.FindMany(assertType, headers)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range as {
+		p := a.HeaderString("provenance")
+		if p == "" {
+			p = naming.DefaultProvenance
+		}
+		if p == provenance {
+			return a, nil
+		}
+	}
+	return nil, &asserts.NotFoundError{Type: assertType, Headers: headers}
+}
+
+// SnapAssertions returns the assertions from the system assertion database
+// which are needed to install the snap revision with the given side info and
+// provenance, together with the given components, on another device. These
+// are the snap-revision and snap-declaration of the snap, the
+// snap-resource-revision and snap-resource-pair of the components, and the
+// account and account-key assertions they depend on. Assertions are
+// returned after the ones they depend on, predefined ones are left out.
+func SnapAssertions(s *state.State, si *snap.SideInfo, provenance string, comps []*snap.ComponentSideInfo) ([]asserts.Assertion, error) {
+	if si.SnapID == "" {
+		return nil, fmt.Errorf("cannot find assertions for unasserted snap %q", si.RealName)
+	}
+
+	db := DB(s)
+	var result []asserts.Assertion
+	retrieve := func(ref *asserts.Ref) (asserts.Assertion, error) {
+		return ref.Resolve(db.Find)
+	}
+	save := func(a asserts.Assertion) error {
+		result = append(result, a)
+		return nil
+	}
+	f := asserts.NewFetcher(db, retrieve, save)
+
+	snapRev, err := findWithProvenance(db, asserts.SnapRevisionType, map[string]string{
+		"snap-id":       si.SnapID,
+		"snap-revision": si.Revision.String(),
+	}, provenance)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find snap-revision assertion for snap %q revision %s: %v", si.RealName, si.Revision, err)
+	}
+	if err := f.Save(snapRev); err != nil {
+		return nil, err
+	}
+
+	pairProvenance := provenance
+	if pairProvenance == naming.DefaultProvenance {
+		pairProvenance = ""
+	}
+	for _, csi := range comps {
+		compName := csi.Component.ComponentName
+		resRev, err := findWithProvenance(db, asserts.SnapResourceRevisionType, map[string]string{
+			"snap-id":           si.SnapID,
+			"resource-name":     compName,
+			"resource-revision": csi.Revision.String(),
+		}, provenance)
+		if err != nil {
+			return nil, fmt.Errorf("cannot find snap-resource-revision assertion for component %q revision %s: %v", csi.Component, csi.Revision, err)
+		}
+		if err := f.Save(resRev); err != nil {
+			return nil, err
+		}
+		if err := snapasserts.FetchResourcePairAssertion(f, si, compName, csi.Revision, pairProvenance); err != nil {
+			return nil, fmt.Errorf("cannot find snap-resource-pair assertion for component %q revision %s: %v", csi.Component, csi.Revision, err)
+		}
+	}
+
+	return result, nil
+}
+
 // AutoAliases returns the explicit automatic aliases alias=>app mapping for the given installed snap.
 func AutoAliases(s *state.State, info *snap.Info) (map[string]string, error) {
 	if info.SnapID == "" {
@@ -2532,6 +2532,92 @@ func (s *assertMgrSuite) TestPublisher(c *C) {
 	c.Check(acct.Username(), Equals, "developer1")
 }
 
+func (s *assertMgrSuite) TestSnapAssertions(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	storeKey := s.storeSigning.StoreAccountKey("")
+	snapDeclFoo := s.snapDecl(c, "foo", nil)
+	digest := strings.Repeat("a", 64)
+	snapRev, err := s.storeSigning.Sign(asserts.SnapRevisionType, map[string]interface{}{
+		"snap-id":       "foo-id",
+		"snap-sha3-384": digest,
+		"snap-size":     "1000",
+		"snap-revision": "5",
+		"developer-id":  s.dev1Acct.AccountID(),
+		"timestamp":     time.Now().Format(time.RFC3339),
+	}, nil, "")
+	c.Assert(err, IsNil)
+	// another revision of the snap
+	otherSnapRev, err := s.storeSigning.Sign(asserts.SnapRevisionType, map[string]interface{}{
+		"snap-id":       "foo-id",
+		"snap-sha3-384": strings.Repeat("b", 64),
+		"snap-size":     "1000",
+		"snap-revision": "6",
+		"developer-id":  s.dev1Acct.AccountID(),
+		"timestamp":     time.Now().Format(time.RFC3339),
+	}, nil, "")
+	c.Assert(err, IsNil)
+	resRev, err := s.storeSigning.Sign(asserts.SnapResourceRevisionType, map[string]interface{}{
+		"snap-id":           "foo-id",
+		"resource-name":     "comp",
+		"resource-sha3-384": strings.Repeat("c", 64),
+		"resource-revision": "2",
+		"resource-size":     "100",
+		"developer-id":      s.dev1Acct.AccountID(),
+		"timestamp":         time.Now().Format(time.RFC3339),
+	}, nil, "")
+	c.Assert(err, IsNil)
+	resPair, err := s.storeSigning.Sign(asserts.SnapResourcePairType, map[string]interface{}{
+		"snap-id":           "foo-id",
+		"resource-name":     "comp",
+		"resource-revision": "2",
+		"snap-revision":     "5",
+		"developer-id":      s.dev1Acct.AccountID(),
+		"timestamp":         time.Now().Format(time.RFC3339),
+	}, nil, "")
+	c.Assert(err, IsNil)
+
+	for _, a := range []asserts.Assertion{storeKey, s.dev1Acct, snapDeclFoo, snapRev, otherSnapRev, resRev, resPair} {
+		c.Assert(assertstate.Add(s.state, a), IsNil)
+	}
+
+	si := &snap.SideInfo{RealName: "foo", SnapID: "foo-id", Revision: snap.R(5)}
+	comps := []*snap.ComponentSideInfo{
+		snap.NewComponentSideInfo(naming.NewComponentRef("foo", "comp"), snap.R(2)),
+	}
+	as, err := assertstate.SnapAssertions(s.state, si, naming.DefaultProvenance, comps)
+	c.Assert(err, IsNil)
+
+	refs := make([]string, len(as))
+	for i, a := range as {
+		refs[i] = a.Ref().Unique()
+	}
+	expected := []asserts.Assertion{storeKey, s.dev1Acct, snapDeclFoo, snapRev, resRev, resPair}
+	expectedRefs := make([]string, len(expected))
+	for i, a := range expected {
+		expectedRefs[i] = a.Ref().Unique()
+	}
+	c.Check(refs, DeepEquals, expectedRefs)
+
+	// without components
+	as, err = assertstate.SnapAssertions(s.state, si, naming.DefaultProvenance, nil)
+	c.Assert(err, IsNil)
+	c.Check(as, HasLen, 4)
+}
+
+func (s *assertMgrSuite) TestSnapAssertionsErrors(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	_, err := assertstate.SnapAssertions(s.state, &snap.SideInfo{RealName: "local", Revision: snap.R(-1)}, naming.DefaultProvenance, nil)
+	c.Check(err, ErrorMatches, `cannot find assertions for unasserted snap "local"`)
+
+	si := &snap.SideInfo{RealName: "foo", SnapID: "foo-id", Revision: snap.R(5)}
+	_, err = assertstate.SnapAssertions(s.state, si, naming.DefaultProvenance, nil)
+	c.Check(err, ErrorMatches, `cannot find snap-revision assertion for snap "foo" revision 5: .*not found`)
+}
+
 func (s *assertMgrSuite) TestPublisherStoreAccount(c *C) {
 	s.state.Lock()
 	defer s.state.Unlock()
@@ -1,7 +1,7 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 
 /*
- * Copyright (C) 2016 Canonical Ltd
+ * Copyright (C) 2016-2026 Canonical Ltd
  *
  * This program is free software: you can redistribute it and/or modify
  * it under the terms of the GNU General Public License version 3 as
@@ -20,9 +20,16 @@
 package overlord
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sync"
 	"time"
 
+	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/state"
 )
 
 type overlordStateBackend struct {
@@ -37,3 +44,274 @@ func (osb *overlordStateBackend) Checkpoint(data []byte) error {
 func (osb *overlordStateBackend) EnsureBefore(d time.Duration) {
 	osb.ensureBefore(d)
 }
+
+// journalCompactionThreshold is the size the state journal can grow to
+// before its deltas are folded back into the state file.
+var journalCompactionThreshold int64 = 4 * 1024 * 1024
+
+// journaledStateBackend is a state backend that appends the deltas of the
+// state to a journal next to the state file. Once the journal grows past
+// journalCompactionThreshold its deltas are folded into the state file in
+// the background.
+//
+// As the deltas can be applied more than once, as long as they are applied
+// in order, the state file is always updated before the folded deltas are
+// dropped from the journal, so that a crash at any point leaves behind a
+// state file and a journal that can be combined to obtain the last
+// checkpointed state. A torn delta at the end of the journal, as left
+// behind by a crash while appending it, is discarded.
+type journaledStateBackend struct {
+	path         string
+	ensureBefore func(d time.Duration)
+
+	mu          sync.Mutex
+	journal     *os.File
+	journalSize int64
+	// journalUnusable is set when a partially written delta could not be
+	// dropped from the end of the journal, nothing can be appended to it
+	// until it is replaced, by a full checkpoint or a compaction
+	journalUnusable bool
+	compacting      bool
+	compactions     sync.WaitGroup
+}
+
+var errJournalUnusable = errors.New("cannot append to the state journal until the state is checkpointed in full")
+
+// openJournal opens the journal for appending, truncating any torn delta
+// left at its end. It must be called with the backend lock held.
+func (jsb *journaledStateBackend) openJournal() error {
+	if jsb.journal != nil {
+		return nil
+	}
+	f, err := os.OpenFile(state.JournalFile(jsb.path), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	_, size, err := state.ReadJournal(f)
+	if err == nil {
+		err = f.Truncate(size)
+	}
+	if err == nil {
+		_, err = f.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	jsb.journal = f
+	jsb.journalSize = size
+	return nil
+}
+
+func (jsb *journaledStateBackend) appendJournal(delta []byte) error {
+	if jsb.journalUnusable {
+		return errJournalUnusable
+	}
+	if err := state.WriteJournalRecord(jsb.journal, delta); err != nil {
+		// drop anything partially written
+		if rerr := jsb.truncateJournal(); rerr != nil {
+			logger.Noticef("cannot drop partially written delta from the state journal: %v", rerr)
+			jsb.journalUnusable = true
+		}
+		return err
+	}
+	if err := jsb.journal.Sync(); err != nil {
+		return err
+	}
+	size, err := jsb.journal.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	jsb.journalSize = size
+	jsb.maybeCompact()
+	return nil
+}
+
+// truncateJournal drops anything after the last complete delta from the
+// journal. It must be called with the backend lock held.
+func (jsb *journaledStateBackend) truncateJournal() error {
+	if err := jsb.journal.Truncate(jsb.journalSize); err != nil {
+		return err
+	}
+	_, err := jsb.journal.Seek(jsb.journalSize, io.SeekStart)
+	return err
+}
+
+// Checkpoint writes the full serialized state. When the journal is empty
+// it replaces the state file directly, otherwise the full state is
+// appended to the journal as a delta so that the older deltas are never
+// applied on top of it. If the journal is unusable it is replaced by one
+// holding just the full state.
+func (jsb *journaledStateBackend) Checkpoint(data []byte) error {
+	jsb.mu.Lock()
+	defer jsb.mu.Unlock()
+	if err := jsb.openJournal(); err != nil {
+		return err
+	}
+	if jsb.journalSize == 0 && !jsb.journalUnusable {
+		return osutil.AtomicWriteFile(jsb.path, data, 0600, 0)
+	}
+	delta, err := state.FullDelta(data)
+	if err != nil {
+		return err
+	}
+	if jsb.journalUnusable {
+		if jsb.compacting {
+			// the compaction relies on the journal only being
+			// appended to
+			return errors.New("cannot replace the state journal while it is being compacted")
+		}
+		var buf bytes.Buffer
+		if err := state.WriteJournalRecord(&buf, delta); err != nil {
+			return err
+		}
+		if err := jsb.replaceJournal(buf.Bytes()); err != nil {
+			return err
+		}
+		jsb.maybeCompact()
+		return nil
+	}
+	return jsb.appendJournal(delta)
+}
+
+// CheckpointDelta appends the given delta to the journal.
+func (jsb *journaledStateBackend) CheckpointDelta(delta []byte) error {
+	jsb.mu.Lock()
+	defer jsb.mu.Unlock()
+	if err := jsb.openJournal(); err != nil {
+		return err
+	}
+	return jsb.appendJournal(delta)
+}
+
+func (jsb *journaledStateBackend) EnsureBefore(d time.Duration) {
+	jsb.ensureBefore(d)
+}
+
+// maybeCompact starts a background compaction if the journal is big
+// enough. It must be called with the backend lock held.
+func (jsb *journaledStateBackend) maybeCompact() {
+	if jsb.compacting || jsb.journalSize < journalCompactionThreshold {
+		return
+	}
+	jsb.compacting = true
+	size := jsb.journalSize
+	jsb.compactions.Add(1)
+	go func() {
+		defer jsb.compactions.Done()
+		if err := jsb.compact(size); err != nil {
+			logger.Noticef("cannot compact state journal: %v", err)
+		}
+	}()
+}
+
+// compact folds the first size bytes of deltas in the journal into the
+// state file and then drops them from the journal. Only the final steps
+// are performed with the backend lock held.
+func (jsb *journaledStateBackend) compact(size int64) error {
+	defer func() {
+		jsb.mu.Lock()
+		jsb.compacting = false
+		jsb.mu.Unlock()
+	}()
+
+	data, err := os.ReadFile(jsb.path)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(state.JournalFile(jsb.path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	deltas, _, err := state.ReadJournal(io.LimitReader(f, size))
+	if err != nil {
+		return err
+	}
+	merged, err := state.ApplyDeltas(data, deltas)
+	if err != nil {
+		return err
+	}
+	aw, err := osutil.NewAtomicFile(jsb.path, 0600, 0, osutil.NoChown, osutil.NoChown)
+	if err != nil {
+		return err
+	}
+	// Cancel once committed is a no-op
+	defer aw.Cancel()
+	if _, err := aw.Write(merged); err != nil {
+		return err
+	}
+
+	jsb.mu.Lock()
+	defer jsb.mu.Unlock()
+	if err := aw.Commit(); err != nil {
+		return err
+	}
+	tail := make([]byte, jsb.journalSize-size)
+	if _, err := f.ReadAt(tail, size); err != nil {
+		return err
+	}
+	return jsb.replaceJournal(tail)
+}
+
+// replaceJournal atomically replaces the journal with one holding the
+// given records. It must be called with the backend lock held.
+func (jsb *journaledStateBackend) replaceJournal(records []byte) error {
+	journalPath := state.JournalFile(jsb.path)
+	if err := osutil.AtomicWriteFile(journalPath, records, 0600, 0); err != nil {
+		return err
+	}
+	if jsb.journal != nil {
+		jsb.journal.Close()
+		jsb.journal = nil
+	}
+	jsb.journalUnusable = false
+	return jsb.openJournal()
+}
+
+// Close waits for any background compaction and then folds all the
+// remaining deltas into the state file, leaving the journal empty.
+func (jsb *journaledStateBackend) Close() error {
+	jsb.compactions.Wait()
+
+	jsb.mu.Lock()
+	if err := jsb.openJournal(); err != nil {
+		jsb.mu.Unlock()
+		return err
+	}
+	if jsb.journalSize == 0 {
+		err := jsb.journal.Close()
+		jsb.journal = nil
+		jsb.mu.Unlock()
+		return err
+	}
+	jsb.compacting = true
+	size := jsb.journalSize
+	jsb.mu.Unlock()
+
+	if err := jsb.compact(size); err != nil {
+		return err
+	}
+
+	jsb.mu.Lock()
+	defer jsb.mu.Unlock()
+	if jsb.journal == nil {
+		return nil
+	}
+	err := jsb.journal.Close()
+	jsb.journal = nil
+	return err
+}
+
+// foldStateJournal folds the deltas left behind in the journal of the state
+// file at statePath, if any, into the latter and removes the journal.
+func foldStateJournal(statePath string) error {
+	if !osutil.FileExists(state.JournalFile(statePath)) {
+		return nil
+	}
+	jsb := &journaledStateBackend{path: statePath}
+	if err := jsb.Close(); err != nil {
+		return err
+	}
+	return os.Remove(state.JournalFile(statePath))
+}
@@ -0,0 +1,217 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package overlord_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type journaledBackendSuite struct {
+	testutil.BaseTest
+
+	statePath string
+}
+
+var _ = Suite(&journaledBackendSuite{})
+
+func (s *journaledBackendSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	s.statePath = filepath.Join(c.MkDir(), "state.json")
+}
+
+func (s *journaledBackendSuite) readState(c *C) *state.State {
+	st, err := state.ReadStateFile(nil, s.statePath)
+	c.Assert(err, IsNil)
+	return st
+}
+
+func (s *journaledBackendSuite) getInt(c *C, st *state.State, key string) int {
+	st.Lock()
+	defer st.Unlock()
+	var v int
+	c.Assert(st.Get(key, &v), IsNil)
+	return v
+}
+
+func (s *journaledBackendSuite) journalSize(c *C) int64 {
+	fi, err := os.Stat(state.JournalFile(s.statePath))
+	c.Assert(err, IsNil)
+	return fi.Size()
+}
+
+func (s *journaledBackendSuite) TestCheckpointDeltas(c *C) {
+	b := overlord.NewJournaledStateBackend(s.statePath)
+	st := state.New(b)
+	st.Lock()
+	st.Set("a", 1)
+	st.Unlock()
+
+	// the first checkpoint writes the state file
+	c.Check(s.statePath, testutil.FileContains, `"a":1`)
+	c.Check(s.journalSize(c), Equals, int64(0))
+
+	st.Lock()
+	st.Set("a", 2)
+	st.Unlock()
+
+	// while the following ones are appended to the journal
+	c.Check(s.statePath, testutil.FileContains, `"a":1`)
+	c.Check(s.journalSize(c) > 0, Equals, true)
+	c.Check(s.getInt(c, s.readState(c), "a"), Equals, 2)
+
+	c.Assert(b.Close(), IsNil)
+	c.Check(s.statePath, testutil.FileContains, `"a":2`)
+	c.Check(s.journalSize(c), Equals, int64(0))
+}
+
+func (s *journaledBackendSuite) TestConvertsExistingState(c *C) {
+	err := os.WriteFile(s.statePath, []byte(`{"data":{"a":1},"changes":{},"tasks":{},"last-change-id":0,"last-task-id":0,"last-lane-id":0}`), 0600)
+	c.Assert(err, IsNil)
+
+	b := overlord.NewJournaledStateBackend(s.statePath)
+	st, err := state.ReadStateFile(b, s.statePath)
+	c.Assert(err, IsNil)
+	st.Lock()
+	st.Set("b", 2)
+	st.Unlock()
+
+	c.Check(s.journalSize(c) > 0, Equals, true)
+	st2 := s.readState(c)
+	c.Check(s.getInt(c, st2, "a"), Equals, 1)
+	c.Check(s.getInt(c, st2, "b"), Equals, 2)
+}
+
+func (s *journaledBackendSuite) TestCompaction(c *C) {
+	s.AddCleanup(overlord.MockJournalCompactionThreshold(1))
+
+	b := overlord.NewJournaledStateBackend(s.statePath)
+	st := state.New(b)
+	st.Lock()
+	st.Set("a", 1)
+	st.Unlock()
+
+	st.Lock()
+	st.Set("a", 2)
+	st.Unlock()
+	b.WaitCompactions()
+
+	c.Check(s.statePath, testutil.FileContains, `"a":2`)
+	c.Check(s.journalSize(c), Equals, int64(0))
+
+	st.Lock()
+	st.Set("a", 3)
+	st.Unlock()
+	b.WaitCompactions()
+
+	c.Check(s.getInt(c, s.readState(c), "a"), Equals, 3)
+}
+
+func (s *journaledBackendSuite) TestTornDeltaDiscarded(c *C) {
+	b := overlord.NewJournaledStateBackend(s.statePath)
+	st := state.New(b)
+	st.Lock()
+	st.Set("a", 1)
+	st.Unlock()
+	st.Lock()
+	st.Set("a", 2)
+	st.Unlock()
+
+	// simulate a crash in the middle of appending a delta
+	validSize := s.journalSize(c)
+	f, err := os.OpenFile(state.JournalFile(s.statePath), os.O_WRONLY|os.O_APPEND, 0600)
+	c.Assert(err, IsNil)
+	_, err = f.Write([]byte{0xff, 0, 0, 0, 1, 2})
+	c.Assert(err, IsNil)
+	c.Assert(f.Close(), IsNil)
+
+	st2 := s.readState(c)
+	c.Check(s.getInt(c, st2, "a"), Equals, 2)
+
+	// a new backend drops the torn delta before appending
+	b2 := overlord.NewJournaledStateBackend(s.statePath)
+	st3, err := state.ReadStateFile(b2, s.statePath)
+	c.Assert(err, IsNil)
+	st3.Lock()
+	st3.Set("a", 3)
+	st3.Unlock()
+	c.Check(s.journalSize(c) > validSize, Equals, true)
+	c.Check(s.getInt(c, s.readState(c), "a"), Equals, 3)
+}
+
+func (s *journaledBackendSuite) TestFullCheckpointWithNonEmptyJournal(c *C) {
+	b := overlord.NewJournaledStateBackend(s.statePath)
+	st := state.New(b)
+	st.Lock()
+	st.Set("a", 1)
+	st.Unlock()
+	st.Lock()
+	st.Set("a", 2)
+	st.Unlock()
+
+	// a new state checkpoints in full
+	st2 := state.New(b)
+	st2.Lock()
+	st2.Set("b", 1)
+	st2.Unlock()
+
+	st3 := s.readState(c)
+	st3.Lock()
+	c.Check(st3.Has("a"), Equals, false)
+	st3.Unlock()
+	c.Check(s.getInt(c, st3, "b"), Equals, 1)
+}
+
+func (s *journaledBackendSuite) TestJournalReplacedAfterFailedAppend(c *C) {
+	b := overlord.NewJournaledStateBackend(s.statePath)
+	st := state.New(b)
+	st.Lock()
+	st.Set("a", 1)
+	st.Unlock()
+	st.Lock()
+	st.Set("a", 2)
+	st.Unlock()
+
+	// neither appending the delta nor dropping what was written of it
+	// works anymore
+	c.Assert(b.BreakJournal(), IsNil)
+	st.Lock()
+	st.Set("a", 3)
+	st.Unlock()
+
+	// the journal was replaced by one holding the full state
+	c.Check(s.getInt(c, s.readState(c), "a"), Equals, 3)
+
+	// and can be appended to again
+	st.Lock()
+	st.Set("a", 4)
+	st.Unlock()
+	c.Check(s.getInt(c, s.readState(c), "a"), Equals, 4)
+
+	c.Assert(b.Close(), IsNil)
+	c.Check(s.statePath, testutil.FileContains, `"a":4`)
+	c.Check(s.journalSize(c), Equals, int64(0))
+}
@@ -21,12 +21,15 @@
 package configcore
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/snapcore/snapd/client"
 	"github.com/snapcore/snapd/features"
 	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/prompting"
+	"github.com/snapcore/snapd/interfaces/prompting/requestrules"
 	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/ifacestate"
 	"github.com/snapcore/snapd/overlord/restart"
@@ -35,8 +38,21 @@ import (
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/release"
 	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
 )
 
+func init() {
+	// add supported configuration of this module
+	supportedConfigurations["core.prompting.timeout"] = true
+	supportedConfigurations["core.prompting.no-client-timeout"] = true
+	supportedConfigurations["core.prompting.default-outcome"] = true
+	// The default policy is a document, so changes to it are reported for
+	// each of its top-level fields.
+	supportedConfigurations["core.prompting.default-policy"] = true
+	supportedConfigurations["core.prompting.default-policy.version"] = true
+	supportedConfigurations["core.prompting.default-policy.rules"] = true
+}
+
 var restartRequest = restart.Request
 
 var servicestateControl = servicestate.Control
@@ -192,3 +208,56 @@ func doExperimentalApparmorPromptingDaemonRestart(c RunTransaction, opts *fsOnly
 
 	return nil
 }
+
+// validatePromptingSettings checks the settings which determine how prompts
+// are resolved when no prompting client replies to them.
+func validatePromptingSettings(tr RunTransaction) error {
+	timeout, err := coreCfg(tr, "prompting.timeout")
+	if err != nil {
+		return err
+	}
+	if _, err := prompting.ParsePromptTimeout(timeout); err != nil {
+		return fmt.Errorf("invalid prompting.timeout: %v", err)
+	}
+
+	noClientTimeout, err := coreCfg(tr, "prompting.no-client-timeout")
+	if err != nil {
+		return err
+	}
+	if _, err := prompting.ParsePromptTimeout(noClientTimeout); err != nil {
+		return fmt.Errorf("invalid prompting.no-client-timeout: %v", err)
+	}
+
+	outcome, err := coreCfg(tr, "prompting.default-outcome")
+	if err != nil {
+		return err
+	}
+	if _, err := prompting.ParseDefaultOutcome(outcome); err != nil {
+		return fmt.Errorf("invalid prompting.default-outcome: %q, must be one of %s", outcome, strutil.Quoted(prompting.SupportedDefaultOutcomes))
+	}
+	return validatePromptingDefaultPolicy(tr)
+}
+
+// validatePromptingDefaultPolicy checks the policy of prompting rules which is
+// provisioned for each user, typically set through the gadget defaults.
+func validatePromptingDefaultPolicy(tr RunTransaction) error {
+	var value interface{}
+	if err := tr.GetMaybe("core", "prompting.default-policy", &value); err != nil {
+		return err
+	}
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("invalid prompting.default-policy: %v", err)
+	}
+	var policy requestrules.Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return fmt.Errorf("invalid prompting.default-policy: %v", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("invalid prompting.default-policy: %v", err)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@
 package configcore_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -648,3 +649,138 @@ plugs:
 
 	s.state.Set("conns", conns)
 }
+
+func (s *promptingSuite) TestValidatePromptingSettingsHappy(c *C) {
+	for _, conf := range []map[string]interface{}{
+		{"prompting.timeout": "30s"},
+		{"prompting.timeout": ""},
+		{"prompting.no-client-timeout": "10s"},
+		{"prompting.no-client-timeout": ""},
+		{"prompting.default-outcome": "deny"},
+		{"prompting.default-outcome": "allow-once"},
+		{"prompting.timeout": "5m", "prompting.default-outcome": "static"},
+		{"prompting.default-policy": map[string]interface{}{
+			"version": json.Number("1"),
+			"rules": []interface{}{
+				map[string]interface{}{
+					"snap":         "libreoffice",
+					"interface":    "home",
+					"path-pattern": "~/Documents/**",
+					"permissions":  []interface{}{"read"},
+					"outcome":      "allow",
+				},
+			},
+		}},
+		{"prompting.default-policy": map[string]interface{}{
+			"version": json.Number("1"),
+			"rules":   []interface{}{},
+		}},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state: s.state,
+			conf:  conf,
+		})
+		c.Check(err, IsNil, Commentf("conf: %v", conf))
+	}
+}
+
+func (s *promptingSuite) TestValidatePromptingSettingsUnhappy(c *C) {
+	for _, testCase := range []struct {
+		conf   map[string]interface{}
+		errStr string
+	}{
+		{
+			map[string]interface{}{"prompting.timeout": "foo"},
+			`invalid prompting.timeout: cannot parse prompt timeout: .*`,
+		},
+		{
+			map[string]interface{}{"prompting.timeout": "-1s"},
+			`invalid prompting.timeout: cannot have zero or negative prompt timeout: "-1s"`,
+		},
+		{
+			map[string]interface{}{"prompting.no-client-timeout": "foo"},
+			`invalid prompting.no-client-timeout: cannot parse prompt timeout: .*`,
+		},
+		{
+			map[string]interface{}{"prompting.default-outcome": "allow"},
+			`invalid prompting.default-outcome: "allow", must be one of "deny", "allow-once", "static"`,
+		},
+		{
+			map[string]interface{}{"prompting.default-policy": "foo"},
+			`invalid prompting.default-policy: json: cannot unmarshal string .*`,
+		},
+		{
+			map[string]interface{}{"prompting.default-policy": map[string]interface{}{
+				"version": json.Number("2"),
+			}},
+			`invalid prompting.default-policy: invalid policy version: 2`,
+		},
+		{
+			map[string]interface{}{"prompting.default-policy": map[string]interface{}{
+				"version": json.Number("1"),
+				"rules": []interface{}{
+					map[string]interface{}{
+						"snap":         "libreoffice",
+						"interface":    "home",
+						"path-pattern": "~/Documents/**",
+						"permissions":  []interface{}{"read"},
+						"outcome":      "allow-forever",
+					},
+				},
+			}},
+			`invalid prompting.default-policy: invalid outcome: "allow-forever"`,
+		},
+		{
+			map[string]interface{}{"prompting.default-policy": map[string]interface{}{
+				"version": json.Number("1"),
+				"rules": []interface{}{
+					map[string]interface{}{
+						"snap":         "libreoffice",
+						"interface":    "home",
+						"path-pattern": "~/Documents/**",
+						"permissions":  []interface{}{"read", "print"},
+						"outcome":      "allow",
+					},
+				},
+			}},
+			`invalid prompting.default-policy: invalid rule 1 in policy: invalid permissions for home interface: "print"`,
+		},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state: s.state,
+			conf:  testCase.conf,
+		})
+		c.Check(err, ErrorMatches, testCase.errStr, Commentf("conf: %v", testCase.conf))
+	}
+}
+
+func (s *promptingSuite) TestValidatePromptingDefaultPolicyNested(c *C) {
+	// Gadget defaults set the policy as a nested document, changes to which
+	// are reported for each of its fields.
+	s.state.Lock()
+	rt := configcore.NewRunTransaction(config.NewTransaction(s.state), nil)
+	s.state.Unlock()
+
+	c.Assert(rt.Set("core", "prompting", map[string]interface{}{
+		"default-policy": map[string]interface{}{
+			"version": 1,
+			"rules": []interface{}{
+				map[string]interface{}{
+					"snap":         "libreoffice",
+					"interface":    "home",
+					"path-pattern": "~/Documents/**",
+					"permissions":  []interface{}{"read"},
+					"outcome":      "allow",
+				},
+			},
+		},
+	}), IsNil)
+	c.Check(rt.Changes(), DeepEquals, []string{"core.prompting.default-policy.rules", "core.prompting.default-policy.version"})
+	c.Check(configcore.Run(classicDev, rt), IsNil)
+
+	s.state.Lock()
+	rt = configcore.NewRunTransaction(config.NewTransaction(s.state), nil)
+	s.state.Unlock()
+	c.Assert(rt.Set("core", "prompting.default-policy.version", 3), IsNil)
+	c.Check(configcore.Run(classicDev, rt), ErrorMatches, `invalid prompting.default-policy: invalid policy version: 3`)
+}
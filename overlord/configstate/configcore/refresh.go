@@ -25,7 +25,9 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/devicestate"
+	"github.com/snapcore/snapd/snap/naming"
 	"github.com/snapcore/snapd/strutil"
 	"github.com/snapcore/snapd/timeutil"
 )
@@ -33,6 +35,9 @@ import (
 const (
 	minInhibitionDays = 1
 	maxInhibitionDays = 21
+
+	minAutoRollbackWindow = time.Minute
+	maxAutoRollbackWindow = 24 * time.Hour
 )
 
 func init() {
@@ -41,8 +46,14 @@ func init() {
 	supportedConfigurations["core.refresh.timer"] = true
 	supportedConfigurations["core.refresh.metered"] = true
 	supportedConfigurations["core.refresh.retain"] = true
+	supportedConfigurations["core.refresh.retain-max-age"] = true
+	supportedConfigurations["core.refresh.retain-max-size"] = true
 	supportedConfigurations["core.refresh.rate-limit"] = true
 	supportedConfigurations["core.refresh.max-inhibition-days"] = true
+	supportedConfigurations["core.refresh.auto-rollback"] = true
+	supportedConfigurations["core.refresh.auto-rollback-snaps"] = true
+	supportedConfigurations["core.refresh.auto-rollback-window"] = true
+	supportedConfigurations["core.refresh.snap-timers"] = true
 }
 
 func reportOrIgnoreInvalidManageRefreshes(tr RunTransaction, optName string) error {
@@ -77,6 +88,24 @@ func validateRefreshSchedule(tr RunTransaction) error {
 			return fmt.Errorf("retain must be a number between 2 and 20, not %q", refreshRetainStr)
 		}
 	}
+	refreshRetainMaxAgeStr, err := coreCfg(tr, "refresh.retain-max-age")
+	if err != nil {
+		return err
+	}
+	if refreshRetainMaxAgeStr != "" {
+		if d, err := time.ParseDuration(refreshRetainMaxAgeStr); err != nil || d <= 0 {
+			return fmt.Errorf("retain-max-age must be a positive duration, not %q", refreshRetainMaxAgeStr)
+		}
+	}
+	refreshRetainMaxSizeStr, err := coreCfg(tr, "refresh.retain-max-size")
+	if err != nil {
+		return err
+	}
+	if refreshRetainMaxSizeStr != "" {
+		if sz, err := strutil.ParseByteSize(refreshRetainMaxSizeStr); err != nil || sz <= 0 {
+			return fmt.Errorf("retain-max-size must be a positive size, not %q", refreshRetainMaxSizeStr)
+		}
+	}
 
 	refreshHoldStr, err := coreCfg(tr, "refresh.hold")
 	if err != nil {
@@ -163,3 +192,56 @@ func validateRefreshRateLimit(tr RunTransaction) error {
 	}
 	return nil
 }
+
+func validateRefreshAutoRollback(tr RunTransaction) error {
+	if err := validateBoolFlag(tr, "refresh.auto-rollback"); err != nil {
+		return err
+	}
+
+	snaps, err := coreCfg(tr, "refresh.auto-rollback-snaps")
+	if err != nil {
+		return err
+	}
+	if snaps != "" {
+		for _, name := range strutil.CommaSeparatedList(snaps) {
+			if err := naming.ValidateInstance(name); err != nil {
+				return fmt.Errorf("refresh.auto-rollback-snaps: %v", err)
+			}
+		}
+	}
+
+	window, err := coreCfg(tr, "refresh.auto-rollback-window")
+	if err != nil {
+		return err
+	}
+	if window != "" {
+		d, err := time.ParseDuration(window)
+		if err != nil || d < minAutoRollbackWindow || d > maxAutoRollbackWindow {
+			return fmt.Errorf("refresh.auto-rollback-window must be a duration between %v and %v, not %q", minAutoRollbackWindow, maxAutoRollbackWindow, window)
+		}
+	}
+	return nil
+}
+
+func validateRefreshSnapTimers(tr RunTransaction) error {
+	var timers map[string]interface{}
+	if err := tr.Get("core", "refresh.snap-timers", &timers); err != nil && !config.IsNoOption(err) {
+		return err
+	}
+	for name, v := range timers {
+		if err := naming.ValidateInstance(name); err != nil {
+			return fmt.Errorf("cannot set refresh timer of snap %q: %v", name, err)
+		}
+		timer, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("cannot set refresh timer of snap %q: timer must be a string", name)
+		}
+		if timer == "" {
+			continue
+		}
+		if _, err := timeutil.ParseSchedule(timer); err != nil {
+			return fmt.Errorf("cannot set refresh timer of snap %q: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -221,6 +221,32 @@ func (s *refreshSuite) TestConfigureRefreshRetainInvalid(c *C) {
 	c.Assert(err, ErrorMatches, `retain must be a number between 2 and 20, not "invalid"`)
 }
 
+func (s *refreshSuite) TestConfigureRefreshRetainMaxAgeAndSize(c *C) {
+	for _, t := range []struct {
+		key, val string
+		err      string
+	}{
+		{key: "refresh.retain-max-age", val: "336h"},
+		{key: "refresh.retain-max-age", val: "14d", err: `retain-max-age must be a positive duration, not "14d"`},
+		{key: "refresh.retain-max-age", val: "-1h", err: `retain-max-age must be a positive duration, not "-1h"`},
+		{key: "refresh.retain-max-size", val: "2GB"},
+		{key: "refresh.retain-max-size", val: "many", err: `retain-max-size must be a positive size, not "many"`},
+		{key: "refresh.retain-max-size", val: "0GB", err: `retain-max-size must be a positive size, not "0GB"`},
+	} {
+		err := configcore.Run(classicDev, &mockConf{
+			state: s.state,
+			conf: map[string]interface{}{
+				t.key: t.val,
+			},
+		})
+		if t.err == "" {
+			c.Check(err, IsNil, Commentf("%s=%s", t.key, t.val))
+		} else {
+			c.Check(err, ErrorMatches, t.err)
+		}
+	}
+}
+
 func (s *refreshSuite) TestConfigureRefreshMaxInhibitionDays(c *C) {
 	data := []struct {
 		val interface{}
@@ -252,3 +278,62 @@ func (s *refreshSuite) TestConfigureRefreshMaxInhibitionDays(c *C) {
 		}
 	}
 }
+
+func (s *refreshSuite) TestConfigureRefreshAutoRollback(c *C) {
+	data := []struct {
+		conf map[string]interface{}
+		err  string
+	}{
+		{conf: map[string]interface{}{"refresh.auto-rollback": "maybe"}, err: `refresh.auto-rollback can only be set to 'true' or 'false'`},
+		{conf: map[string]interface{}{"refresh.auto-rollback-snaps": "foo,Bar"}, err: `refresh.auto-rollback-snaps: invalid snap name: "Bar"`},
+		{conf: map[string]interface{}{"refresh.auto-rollback-window": "30s"}, err: `refresh.auto-rollback-window must be a duration between 1m0s and 24h0m0s, not "30s"`},
+		{conf: map[string]interface{}{"refresh.auto-rollback-window": "2d"}, err: `refresh.auto-rollback-window must be a duration between 1m0s and 24h0m0s, not "2d"`},
+		// happy cases
+		{conf: map[string]interface{}{"refresh.auto-rollback": true}},
+		{conf: map[string]interface{}{"refresh.auto-rollback": "false"}},
+		{conf: map[string]interface{}{"refresh.auto-rollback-snaps": "foo, bar_1"}},
+		{conf: map[string]interface{}{"refresh.auto-rollback-window": "1h30m"}},
+	}
+	for _, tc := range data {
+		err := configcore.Run(classicDev, &mockConf{
+			state: s.state,
+			conf:  tc.conf,
+		})
+		if tc.err != "" {
+			c.Check(err, ErrorMatches, tc.err, Commentf("%v", tc.conf))
+		} else {
+			c.Check(err, IsNil, Commentf("%v", tc.conf))
+		}
+	}
+}
+
+func (s *refreshSuite) TestConfigureRefreshSnapTimers(c *C) {
+	data := []struct {
+		timers map[string]interface{}
+		err    string
+	}{
+		{timers: map[string]interface{}{"foo": "invalid"}, err: `cannot set refresh timer of snap "foo": cannot parse "invalid": .*`},
+		{timers: map[string]interface{}{"Foo": "23:00-05:00"}, err: `cannot set refresh timer of snap "Foo": invalid snap name: "Foo"`},
+		{timers: map[string]interface{}{"foo": 5}, err: `cannot set refresh timer of snap "foo": timer must be a string`},
+		// happy cases
+		{timers: map[string]interface{}{"foo": "23:00-05:00"}},
+		{timers: map[string]interface{}{"foo": "0:00-24:00/96", "bar_1": "mon-fri,9:00"}},
+		{timers: map[string]interface{}{"foo": ""}},
+	}
+	for _, tc := range data {
+		changes := make(map[string]interface{}, len(tc.timers))
+		for name, timer := range tc.timers {
+			changes["refresh.snap-timers."+name] = timer
+		}
+		err := configcore.Run(classicDev, &mockConf{
+			state:   s.state,
+			conf:    map[string]interface{}{"refresh.snap-timers": tc.timers},
+			changes: changes,
+		})
+		if tc.err != "" {
+			c.Check(err, ErrorMatches, tc.err, Commentf("%v", tc.timers))
+		} else {
+			c.Check(err, IsNil, Commentf("%v", tc.timers))
+		}
+	}
+}
@@ -59,6 +59,8 @@ func init() {
 	validateOnly := &flags{validatedOnlyStateConfig: true}
 	addWithStateHandler(validateRefreshSchedule, nil, validateOnly)
 	addWithStateHandler(validateRefreshRateLimit, nil, validateOnly)
+	addWithStateHandler(validateRefreshAutoRollback, nil, validateOnly)
+	addWithStateHandler(validateRefreshSnapTimers, nil, validateOnly)
 	addWithStateHandler(validateAutomaticSnapshotsExpiration, nil, validateOnly)
 
 	// netplan.*
@@ -75,6 +77,9 @@ func init() {
 
 	// experimental.apparmor-prompting
 	addWithStateHandler(nil, doExperimentalApparmorPromptingDaemonRestart, nil)
+
+	// prompting.{timeout,no-client-timeout,default-outcome,default-policy}
+	addWithStateHandler(validatePromptingSettings, nil, validateOnly)
 }
 
 // RunTransaction is an interface describing how to access
@@ -165,6 +170,8 @@ func applyHandlers(dev sysconfig.Device, cfg RunTransaction, handlers []configHa
 			if !validCertOption(k) {
 				return fmt.Errorf("cannot set store ssl certificate under name %q: name must only contain word characters or a dash", k)
 			}
+		case strings.HasPrefix(k, "core.refresh.snap-timers."):
+			// validated by validateRefreshSnapTimers
 		case isNetplanChange(k):
 			if release.OnClassic {
 				return fmt.Errorf("cannot set netplan configuration on classic")
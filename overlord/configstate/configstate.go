@@ -55,7 +55,7 @@ func ConfigureHookTimeout() time.Duration {
 	return timeout
 }
 
-func canConfigure(st *state.State, snapName string) error {
+func canConfigure(st *state.State, snapName string, fromChange string) error {
 	// the "core" snap/pseudonym can always be configured as it
 	// is handled internally
 	if snapName == "core" {
@@ -90,14 +90,20 @@ func canConfigure(st *state.State, snapName string) error {
 		return fmt.Errorf("cannot configure snap %q because it is of type 'base'", snapName)
 	}
 
-	return snapstate.CheckChangeConflict(st, snapName, nil)
+	return snapstate.CheckChangeConflictMany(st, []string{snapName}, fromChange)
 }
 
 // ConfigureInstalled returns a taskset to apply the given
 // configuration patch for an installed snap. It returns
 // snap.NotInstalledError if the snap is not installed.
 func ConfigureInstalled(st *state.State, snapName string, patch map[string]interface{}, flags int) (*state.TaskSet, error) {
-	if err := canConfigure(st, snapName); err != nil {
+	return ConfigureInstalledFromChange(st, snapName, patch, flags, "")
+}
+
+// ConfigureInstalledFromChange is like ConfigureInstalled but the tasks of
+// the change with the given ID, if any, do not count as conflicting.
+func ConfigureInstalledFromChange(st *state.State, snapName string, patch map[string]interface{}, flags int, fromChange string) (*state.TaskSet, error) {
+	if err := canConfigure(st, snapName, fromChange); err != nil {
 		return nil, err
 	}
 
@@ -35,6 +35,35 @@ var (
 	LockWithTimeout = lockWithTimeout
 )
 
+type JournaledStateBackend = journaledStateBackend
+
+// NewJournaledStateBackend returns a journaled state backend for the state
+// file at path.
+func NewJournaledStateBackend(path string) *JournaledStateBackend {
+	return &journaledStateBackend{
+		path:         path,
+		ensureBefore: func(time.Duration) {},
+	}
+}
+
+// BreakJournal makes any further writes to the open journal fail.
+func (jsb *journaledStateBackend) BreakJournal() error {
+	jsb.mu.Lock()
+	defer jsb.mu.Unlock()
+	return jsb.journal.Close()
+}
+
+// WaitCompactions waits for any background compaction of the journal.
+func (jsb *journaledStateBackend) WaitCompactions() {
+	jsb.compactions.Wait()
+}
+
+// MockJournalCompactionThreshold sets the size past which the state
+// journal is compacted.
+func MockJournalCompactionThreshold(size int64) (restore func()) {
+	return testutil.Mock(&journalCompactionThreshold, size)
+}
+
 // MockEnsureInterval sets the overlord ensure interval for tests.
 func MockEnsureInterval(d time.Duration) (restore func()) {
 	old := ensureInterval
@@ -21,6 +21,11 @@ package healthstate
 
 import (
 	"time"
+
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/systemd"
+	"github.com/snapcore/snapd/testutil"
 )
 
 func MockCheckTimeout(t time.Duration) (restore func()) {
@@ -32,3 +37,15 @@ func MockCheckTimeout(t time.Duration) (restore func()) {
 }
 
 var KnownStatuses = knownStatuses
+
+func MockTimeNow(f func() time.Time) (restore func()) {
+	return testutil.Mock(&timeNow, f)
+}
+
+func MockSnapstateRevert(f func(st *state.State, name string, flags snapstate.Flags, fromChange string) (*state.TaskSet, error)) (restore func()) {
+	return testutil.Mock(&snapstateRevert, f)
+}
+
+func MockServiceStatus(f func(units []string) ([]*systemd.UnitStatus, error)) (restore func()) {
+	return testutil.Mock(&serviceStatus, f)
+}
@@ -177,6 +177,11 @@ func appendHealth(ctx *hookstate.Context, health *HealthState) error {
 	hs[ctx.InstanceName()] = health
 	st.Set("health", hs)
 
+	if health.Status == ErrorStatus {
+		// let the auto-rollback policy act on it promptly
+		st.EnsureBefore(0)
+	}
+
 	return nil
 }
 
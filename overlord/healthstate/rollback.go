@@ -0,0 +1,358 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package healthstate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
+	"github.com/snapcore/snapd/systemd"
+)
+
+const defaultAutoRollbackWindow = 10 * time.Minute
+
+var (
+	// autoRollbackCheckInterval is how often snaps are checked while
+	// they are within their auto-rollback window.
+	autoRollbackCheckInterval = time.Minute
+
+	timeNow = time.Now
+
+	snapstateRevert = snapstate.Revert
+
+	serviceStatus = func(units []string) ([]*systemd.UnitStatus, error) {
+		return systemd.New(systemd.SystemMode, nil).Status(units)
+	}
+)
+
+// HealthManager reverts snaps which turn out to be unhealthy shortly
+// after having been refreshed, if configured to do so with the
+// refresh.auto-rollback system options.
+type HealthManager struct {
+	state *state.State
+}
+
+// Manager returns a new HealthManager.
+func Manager(st *state.State) *HealthManager {
+	return &HealthManager{state: st}
+}
+
+// autoRollback records a revision of a snap that was automatically
+// reverted.
+type autoRollback struct {
+	Revision snap.Revision `json:"revision"`
+	Time     time.Time     `json:"time"`
+	Reason   string        `json:"reason"`
+}
+
+type autoRollbackPolicy struct {
+	all    bool
+	snaps  []string
+	window time.Duration
+}
+
+func (p *autoRollbackPolicy) appliesTo(snapName string) bool {
+	return p.all || strutil.ListContains(p.snaps, snapName)
+}
+
+func coreCfg(tr *config.Transaction, key string) (string, error) {
+	var v interface{} = ""
+	if err := tr.Get("core", key, &v); err != nil && !config.IsNoOption(err) {
+		return "", err
+	}
+	return fmt.Sprintf("%v", v), nil
+}
+
+func getAutoRollbackPolicy(st *state.State) (*autoRollbackPolicy, error) {
+	tr := config.NewTransaction(st)
+
+	all, err := coreCfg(tr, "refresh.auto-rollback")
+	if err != nil {
+		return nil, err
+	}
+	snaps, err := coreCfg(tr, "refresh.auto-rollback-snaps")
+	if err != nil {
+		return nil, err
+	}
+	window, err := coreCfg(tr, "refresh.auto-rollback-window")
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &autoRollbackPolicy{
+		all:    all == "true",
+		window: defaultAutoRollbackWindow,
+	}
+	if snaps != "" {
+		policy.snaps = strutil.CommaSeparatedList(snaps)
+	}
+	if window != "" {
+		policy.window, err = time.ParseDuration(window)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse refresh.auto-rollback-window: %v", err)
+		}
+	}
+	return policy, nil
+}
+
+// rollbackCandidate is a snap which was refreshed recently enough to be
+// reverted if it turns out to be unhealthy.
+type rollbackCandidate struct {
+	name        string
+	revision    snap.Revision
+	refreshTime time.Time
+	// reason is why the snap is unhealthy according to its health
+	// check, if it is
+	reason string
+	// units are the system services of the snap, which are checked
+	// if the health check did not report an error
+	units []string
+	err   error
+}
+
+// Ensure is part of the overlord.StateManager interface.
+func (m *HealthManager) Ensure() error {
+	st := m.state
+	st.Lock()
+	candidates, err := rollbackCandidates(st)
+	st.Unlock()
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// querying systemd can take a while, so it is done without holding
+	// the state lock
+	for _, cand := range candidates {
+		if cand.reason != "" || cand.err != nil || len(cand.units) == 0 {
+			continue
+		}
+		cand.reason, cand.err = failedServicesReason(cand.units)
+	}
+
+	st.Lock()
+	defer st.Unlock()
+
+	var rollbacks map[string]*autoRollback
+	if err := st.Get("auto-rollbacks", &rollbacks); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	if rollbacks == nil {
+		rollbacks = make(map[string]*autoRollback)
+	}
+
+	now := timeNow()
+	recheck := false
+	for _, cand := range candidates {
+		name := cand.name
+		if cand.err != nil {
+			logger.Noticef("cannot check health of snap %q: %v", name, cand.err)
+			recheck = true
+			continue
+		}
+		if cand.reason == "" {
+			recheck = true
+			continue
+		}
+
+		// the snap might have changed while the state was unlocked
+		var snapst snapstate.SnapState
+		if err := snapstate.Get(st, name, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+			return err
+		}
+		if !snapst.Active || snapst.Current != cand.revision || snapst.LastRefreshTime == nil || !snapst.LastRefreshTime.Equal(cand.refreshTime) {
+			recheck = true
+			continue
+		}
+
+		ts, err := snapstateRevert(st, name, snapstate.Flags{}, "")
+		if err != nil {
+			// for example a conflict with the change still finishing
+			// the refresh, try again later
+			logger.Noticef("cannot automatically revert snap %q: %v", name, err)
+			recheck = true
+			continue
+		}
+		chg := st.NewChange("revert-snap", fmt.Sprintf("Automatically revert %q snap", name))
+		chg.AddAll(ts)
+		snapstate.SetHistoryTrigger(chg, snapstate.HistoryTriggerAuto)
+
+		rollbacks[name] = &autoRollback{
+			Revision: cand.revision,
+			Time:     now,
+			Reason:   cand.reason,
+		}
+		st.Set("auto-rollbacks", rollbacks)
+
+		st.Warnf("snap %q was automatically reverted from revision %s after a refresh: %s", name, cand.revision, cand.reason)
+		if _, err := st.AddNotice(nil, state.SnapAutoRollbackNotice, name, &state.AddNoticeOptions{
+			Data: map[string]string{
+				"revision":  cand.revision.String(),
+				"reason":    cand.reason,
+				"change-id": chg.ID(),
+			},
+		}); err != nil {
+			return err
+		}
+		st.EnsureBefore(0)
+	}
+
+	if recheck {
+		st.EnsureBefore(autoRollbackCheckInterval)
+	}
+	return nil
+}
+
+// rollbackCandidates returns the snaps which the auto-rollback policy
+// applies to and which are within their auto-rollback window.
+// The state must be locked by the caller.
+func rollbackCandidates(st *state.State) ([]*rollbackCandidate, error) {
+	policy, err := getAutoRollbackPolicy(st)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.all && len(policy.snaps) == 0 {
+		return nil, nil
+	}
+
+	var rollbacks map[string]*autoRollback
+	if err := st.Get("auto-rollbacks", &rollbacks); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+
+	snapStates, err := snapstate.All(st)
+	if err != nil {
+		return nil, err
+	}
+
+	now := timeNow()
+	var candidates []*rollbackCandidate
+	for name, snapst := range snapStates {
+		if !policy.appliesTo(name) || !snapst.Active || snapst.LastRefreshTime == nil {
+			continue
+		}
+		refreshTime := *snapst.LastRefreshTime
+		if now.Sub(refreshTime) > policy.window {
+			continue
+		}
+		if snapst.LastIndex(snapst.Current) <= 0 {
+			// installed rather than refreshed, or nothing to go back to
+			continue
+		}
+		if rb := rollbacks[name]; rb != nil && rb.Time.After(refreshTime) {
+			// already reverted after the last refresh
+			continue
+		}
+		reverted, err := revertedByUser(st, name, snapst.Current)
+		if err != nil {
+			return nil, err
+		}
+		if reverted {
+			// the user chose to go back to this revision
+			continue
+		}
+
+		cand := &rollbackCandidate{
+			name:        name,
+			revision:    snapst.Current,
+			refreshTime: refreshTime,
+		}
+		cand.reason, cand.units, cand.err = unhealthyReason(st, name, snapst, refreshTime)
+		candidates = append(candidates, cand)
+	}
+	return candidates, nil
+}
+
+// revertedByUser returns whether the given current revision of the snap
+// was reverted to at the request of the user.
+func revertedByUser(st *state.State, name string, current snap.Revision) (bool, error) {
+	history, err := snapstate.SnapHistory(st, name)
+	if err != nil {
+		return false, err
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		e := history[i]
+		if e.Outcome != snapstate.HistoryOutcomeSucceeded || e.ToRevision != current {
+			continue
+		}
+		switch e.Action {
+		case snapstate.HistoryActionInstall, snapstate.HistoryActionRefresh, snapstate.HistoryActionRevert:
+			return e.Action == snapstate.HistoryActionRevert && e.Trigger != snapstate.HistoryTriggerAuto, nil
+		}
+	}
+	return false, nil
+}
+
+// unhealthyReason returns why the current revision of the given snap is
+// considered unhealthy since the given time according to its health check,
+// or the empty string if it is not. In the latter case the system services
+// of the snap are returned, so that they can be checked as well.
+func unhealthyReason(st *state.State, name string, snapst *snapstate.SnapState, since time.Time) (reason string, units []string, err error) {
+	health, err := Get(st, name)
+	if err != nil {
+		return "", nil, err
+	}
+	if health != nil && health.Status == ErrorStatus && health.Revision == snapst.Current && !health.Timestamp.Before(since) {
+		if health.Message != "" {
+			return fmt.Sprintf("health check reported an error: %s", health.Message), nil, nil
+		}
+		return "health check reported an error", nil, nil
+	}
+
+	info, err := snapst.CurrentInfo()
+	if err != nil {
+		return "", nil, err
+	}
+	for _, app := range info.Services() {
+		if app.DaemonScope == snap.SystemDaemon {
+			units = append(units, app.ServiceName())
+		}
+	}
+	return "", units, nil
+}
+
+// failedServicesReason returns why a snap with the given system services is
+// considered unhealthy, or the empty string if none of them failed.
+func failedServicesReason(units []string) (string, error) {
+	sts, err := serviceStatus(units)
+	if err != nil {
+		return "", err
+	}
+	var failed []string
+	for _, st := range sts {
+		if st.Failed {
+			failed = append(failed, st.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Sprintf("services failed: %s", strings.Join(failed, ", ")), nil
+	}
+	return "", nil
+}
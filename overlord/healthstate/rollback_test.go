@@ -0,0 +1,282 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package healthstate_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/healthstate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/systemd"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type rollbackSuite struct {
+	testutil.BaseTest
+	state   *state.State
+	mgr     *healthstate.HealthManager
+	now     time.Time
+	reverts []string
+	failed  map[string]bool
+}
+
+var _ = check.Suite(&rollbackSuite{})
+
+const rollbackSnapYaml = `name: test-snap
+version: v1
+apps:
+  svc:
+    daemon: simple
+`
+
+func (s *rollbackSuite) SetUpTest(c *check.C) {
+	s.BaseTest.SetUpTest(c)
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+
+	s.state = state.New(nil)
+	s.mgr = healthstate.Manager(s.state)
+
+	s.now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.AddCleanup(healthstate.MockTimeNow(func() time.Time { return s.now }))
+
+	s.reverts = nil
+	s.AddCleanup(healthstate.MockSnapstateRevert(func(st *state.State, name string, flags snapstate.Flags, fromChange string) (*state.TaskSet, error) {
+		s.reverts = append(s.reverts, name)
+		return state.NewTaskSet(st.NewTask("fake-revert", "Revert")), nil
+	}))
+	s.failed = nil
+	s.AddCleanup(healthstate.MockServiceStatus(func(units []string) ([]*systemd.UnitStatus, error) {
+		sts := make([]*systemd.UnitStatus, 0, len(units))
+		for _, unit := range units {
+			sts = append(sts, &systemd.UnitStatus{Name: unit, Failed: s.failed[unit]})
+		}
+		return sts, nil
+	}))
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	// refreshed from r1 to r2 five minutes ago
+	refreshTime := s.now.Add(-5 * time.Minute)
+	si1 := &snap.SideInfo{RealName: "test-snap", Revision: snap.R(1)}
+	si2 := &snap.SideInfo{RealName: "test-snap", Revision: snap.R(2)}
+	snapstate.Set(s.state, "test-snap", &snapstate.SnapState{
+		Sequence:        snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si1, si2}),
+		Current:         snap.R(2),
+		Active:          true,
+		SnapType:        "app",
+		LastRefreshTime: &refreshTime,
+	})
+	snaptest.MockSnapCurrent(c, rollbackSnapYaml, si2)
+}
+
+func (s *rollbackSuite) configure(c *check.C, conf map[string]interface{}) {
+	tr := config.NewTransaction(s.state)
+	for k, v := range conf {
+		c.Assert(tr.Set("core", k, v), check.IsNil)
+	}
+	tr.Commit()
+}
+
+func (s *rollbackSuite) setHealth(status healthstate.HealthStatus, rev snap.Revision, ts time.Time) {
+	s.state.Set("health", map[string]*healthstate.HealthState{
+		"test-snap": {
+			Revision:  rev,
+			Timestamp: ts,
+			Status:    status,
+			Message:   "database is gone",
+		},
+	})
+}
+
+func (s *rollbackSuite) ensure(c *check.C) {
+	s.state.Unlock()
+	defer s.state.Lock()
+	c.Assert(s.mgr.Ensure(), check.IsNil)
+}
+
+func (s *rollbackSuite) TestRollbackOnErrorHealth(c *check.C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.configure(c, map[string]interface{}{"refresh.auto-rollback": true})
+	s.setHealth(healthstate.ErrorStatus, snap.R(2), s.now.Add(-time.Minute))
+
+	s.ensure(c)
+	c.Check(s.reverts, check.DeepEquals, []string{"test-snap"})
+
+	chgs := s.state.Changes()
+	c.Assert(chgs, check.HasLen, 1)
+	c.Check(chgs[0].Kind(), check.Equals, "revert-snap")
+	c.Check(chgs[0].Summary(), check.Equals, `Automatically revert "test-snap" snap`)
+
+	warnings := s.state.AllWarnings()
+	c.Assert(warnings, check.HasLen, 1)
+	c.Check(warnings[0].String(), check.Equals, `snap "test-snap" was automatically reverted from revision 2 after a refresh: health check reported an error: database is gone`)
+
+	notices := s.state.Notices(&state.NoticeFilter{Types: []state.NoticeType{state.SnapAutoRollbackNotice}})
+	c.Assert(notices, check.HasLen, 1)
+	n := noticeToMap(c, notices[0])
+	c.Check(n["key"], check.Equals, "test-snap")
+	c.Check(n["last-data"], check.DeepEquals, map[string]interface{}{
+		"revision":  "2",
+		"reason":    "health check reported an error: database is gone",
+		"change-id": chgs[0].ID(),
+	})
+
+	// the same refresh is not rolled back twice
+	s.ensure(c)
+	c.Check(s.reverts, check.HasLen, 1)
+}
+
+func (s *rollbackSuite) TestRollbackOnFailedService(c *check.C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.configure(c, map[string]interface{}{"refresh.auto-rollback-snaps": "other-snap,test-snap"})
+
+	s.ensure(c)
+	c.Check(s.reverts, check.HasLen, 0)
+
+	s.failed = map[string]bool{"snap.test-snap.svc.service": true}
+	s.ensure(c)
+	c.Check(s.reverts, check.DeepEquals, []string{"test-snap"})
+
+	warnings := s.state.AllWarnings()
+	c.Assert(warnings, check.HasLen, 1)
+	c.Check(warnings[0].String(), check.Equals, `snap "test-snap" was automatically reverted from revision 2 after a refresh: services failed: snap.test-snap.svc.service`)
+}
+
+func (s *rollbackSuite) TestNoRollback(c *check.C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.failed = map[string]bool{"snap.test-snap.svc.service": true}
+
+	// not enabled
+	s.ensure(c)
+	c.Check(s.reverts, check.HasLen, 0)
+
+	// enabled for other snaps only
+	s.configure(c, map[string]interface{}{"refresh.auto-rollback-snaps": "other-snap"})
+	s.ensure(c)
+	c.Check(s.reverts, check.HasLen, 0)
+
+	// outside of the window
+	s.configure(c, map[string]interface{}{
+		"refresh.auto-rollback":        true,
+		"refresh.auto-rollback-window": "2m",
+	})
+	s.ensure(c)
+	c.Check(s.reverts, check.HasLen, 0)
+	c.Check(s.state.Changes(), check.HasLen, 0)
+}
+
+func (s *rollbackSuite) TestNoRollbackOnStaleHealth(c *check.C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.configure(c, map[string]interface{}{"refresh.auto-rollback": true})
+
+	// reported before the refresh
+	s.setHealth(healthstate.ErrorStatus, snap.R(2), s.now.Add(-time.Hour))
+	s.ensure(c)
+	c.Check(s.reverts, check.HasLen, 0)
+
+	// reported for another revision
+	s.setHealth(healthstate.ErrorStatus, snap.R(1), s.now.Add(-time.Minute))
+	s.ensure(c)
+	c.Check(s.reverts, check.HasLen, 0)
+
+	// not an error
+	s.setHealth(healthstate.WaitingStatus, snap.R(2), s.now.Add(-time.Minute))
+	s.ensure(c)
+	c.Check(s.reverts, check.HasLen, 0)
+}
+
+func (s *rollbackSuite) TestRollbackChecksServicesWithoutStateLock(c *check.C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.configure(c, map[string]interface{}{"refresh.auto-rollback": true})
+
+	s.AddCleanup(healthstate.MockServiceStatus(func(units []string) ([]*systemd.UnitStatus, error) {
+		// this would block if the state was locked
+		s.state.Lock()
+		s.state.Unlock()
+		return []*systemd.UnitStatus{{Name: units[0], Failed: true}}, nil
+	}))
+
+	s.ensure(c)
+	c.Check(s.reverts, check.DeepEquals, []string{"test-snap"})
+}
+
+func (s *rollbackSuite) TestNoRollbackAfterUserRevert(c *check.C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.configure(c, map[string]interface{}{"refresh.auto-rollback": true})
+	s.failed = map[string]bool{"snap.test-snap.svc.service": true}
+
+	// the user went back to r2 from r3
+	s.state.Set("snap-history", map[string][]*snapstate.HistoryEntry{
+		"test-snap": {{
+			Action:       snapstate.HistoryActionRefresh,
+			FromRevision: snap.R(1),
+			ToRevision:   snap.R(2),
+			Trigger:      snapstate.HistoryTriggerAuto,
+			Outcome:      snapstate.HistoryOutcomeSucceeded,
+		}, {
+			Action:       snapstate.HistoryActionRefresh,
+			FromRevision: snap.R(2),
+			ToRevision:   snap.R(3),
+			Trigger:      snapstate.HistoryTriggerAuto,
+			Outcome:      snapstate.HistoryOutcomeSucceeded,
+		}, {
+			Action:       snapstate.HistoryActionRevert,
+			FromRevision: snap.R(3),
+			ToRevision:   snap.R(2),
+			Trigger:      snapstate.HistoryTriggerManual,
+			Outcome:      snapstate.HistoryOutcomeSucceeded,
+		}},
+	})
+
+	s.ensure(c)
+	c.Check(s.reverts, check.HasLen, 0)
+	c.Check(s.state.Changes(), check.HasLen, 0)
+}
+
+func noticeToMap(c *check.C, notice *state.Notice) map[string]interface{} {
+	buf, err := json.Marshal(notice)
+	c.Assert(err, check.IsNil)
+	var n map[string]interface{}
+	c.Assert(json.Unmarshal(buf, &n), check.IsNil)
+	return n
+}
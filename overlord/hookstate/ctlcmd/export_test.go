@@ -25,6 +25,7 @@ import (
 
 	"github.com/snapcore/snapd/asserts"
 	"github.com/snapcore/snapd/client/clientutil"
+	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/osutil/user"
 	"github.com/snapcore/snapd/overlord/devicestate"
 	"github.com/snapcore/snapd/overlord/hookstate"
@@ -211,3 +212,11 @@ func MockRegistrystateGetStoredTransaction(f func(*state.Task) (*registrystate.T
 		registrystateGetStoredTransaction = old
 	}
 }
+
+func MockIfacestateRefreshConnection(f func(*state.State, *interfaces.ConnRef, map[string]interface{}, map[string]interface{}, *hookstate.Context) (*state.TaskSet, error)) (restore func()) {
+	old := ifacestateRefreshConnection
+	ifacestateRefreshConnection = f
+	return func() {
+		ifacestateRefreshConnection = old
+	}
+}
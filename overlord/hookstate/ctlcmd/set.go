@@ -23,18 +23,26 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/snapcore/snapd/client/clientutil"
 	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/jsonutil"
 	"github.com/snapcore/snapd/overlord/configstate"
 	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/hookstate"
+	"github.com/snapcore/snapd/overlord/ifacestate"
 	"github.com/snapcore/snapd/overlord/registrystate"
+	"github.com/snapcore/snapd/overlord/state"
 )
 
-var registrystateGetTransaction = registrystate.GetTransactionToModify
+var (
+	registrystateGetTransaction = registrystate.GetTransactionToModify
+	ifacestateRefreshConnection = ifacestate.RefreshConnection
+)
 
 type setCommand struct {
 	baseCommand
@@ -70,6 +78,11 @@ Plug and slot attributes may be set in the respective prepare and connect hooks
 by naming the respective plug or slot:
 
     $ snapctl set :myplug path=/dev/ttyS0
+
+Outside of interface hooks, the attributes of a connected plug or slot may be
+updated the same way. The affected connections are refreshed without being
+disconnected: the interface hooks are run again and the security profiles are
+regenerated.
 `)
 
 func init() {
@@ -175,8 +188,13 @@ func setInterfaceAttribute(context *hookstate.Context, staticAttrs map[string]in
 }
 
 func (s *setCommand) setInterfaceSetting(context *hookstate.Context, plugOrSlot string) error {
-	// Make sure set :<plug|slot> is only supported during the execution of prepare-[plug|slot] hooks
-	hookType, _ := interfaceHookType(context.HookName())
+	// Make sure set :<plug|slot> is only supported during the execution of
+	// prepare-[plug|slot] hooks, or outside of interface hooks for
+	// established connections
+	hookType, err := interfaceHookType(context.HookName())
+	if err != nil {
+		return s.refreshConnectionSetting(context, plugOrSlot)
+	}
 	if hookType != preparePlugHook && hookType != prepareSlotHook {
 		return errors.New(i18n.G("interface attributes can only be set during the execution of prepare hooks"))
 	}
@@ -211,6 +229,15 @@ func (s *setCommand) setInterfaceSetting(context *hookstate.Context, plugOrSlot
 		return fmt.Errorf(i18n.G("internal error: cannot get %s from appropriate task, %s"), which, err)
 	}
 
+	if err := s.setInterfaceAttributes(context, staticAttrs, dynamicAttrs); err != nil {
+		return err
+	}
+
+	attrsTask.Set(dynKey, dynamicAttrs)
+	return nil
+}
+
+func (s *setCommand) setInterfaceAttributes(context *hookstate.Context, staticAttrs, dynamicAttrs map[string]interface{}) error {
 	for _, attrValue := range s.Positional.ConfValues {
 		parts := strings.SplitN(attrValue, "=", 2)
 		if len(parts) != 2 {
@@ -222,16 +249,107 @@ func (s *setCommand) setInterfaceSetting(context *hookstate.Context, plugOrSlot
 			// Not valid JSON, save the string as-is
 			value = parts[1]
 		}
-		err = setInterfaceAttribute(context, staticAttrs, dynamicAttrs, parts[0], value)
+		err := setInterfaceAttribute(context, staticAttrs, dynamicAttrs, parts[0], value)
 		if err != nil {
 			return fmt.Errorf(i18n.G("cannot set attribute: %v"), err)
 		}
 	}
-
-	attrsTask.Set(dynKey, dynamicAttrs)
 	return nil
 }
 
+// refreshConnectionSetting updates the dynamic attributes of all the
+// established connections of the given plug or slot of the context snap.
+// The connections are refreshed in place, re-running the interface hooks
+// and regenerating the security profiles, without disconnecting them.
+func (s *setCommand) refreshConnectionSetting(context *hookstate.Context, plugOrSlot string) error {
+	st := context.State()
+	snapName := context.InstanceName()
+
+	st.Lock()
+	conns, err := ifacestate.ConnectionStates(st)
+	if err != nil {
+		st.Unlock()
+		return fmt.Errorf("internal error: cannot get connections: %s", err)
+	}
+
+	var tts []*state.TaskSet
+	crefs := make([]string, 0, len(conns))
+	for cref := range conns {
+		crefs = append(crefs, cref)
+	}
+	sort.Strings(crefs)
+	for _, cref := range crefs {
+		connState := conns[cref]
+		if !connState.Active() {
+			continue
+		}
+		connRef, err := interfaces.ParseConnRef(cref)
+		if err != nil {
+			st.Unlock()
+			return fmt.Errorf("internal error: %s", err)
+		}
+
+		var plugAttrs, slotAttrs map[string]interface{}
+		switch {
+		case connRef.PlugRef.Snap == snapName && connRef.PlugRef.Name == plugOrSlot:
+			plugAttrs = copyAttributes(connState.DynamicPlugAttrs)
+			err = s.setInterfaceAttributes(context, connState.StaticPlugAttrs, plugAttrs)
+		case connRef.SlotRef.Snap == snapName && connRef.SlotRef.Name == plugOrSlot:
+			slotAttrs = copyAttributes(connState.DynamicSlotAttrs)
+			err = s.setInterfaceAttributes(context, connState.StaticSlotAttrs, slotAttrs)
+		default:
+			continue
+		}
+		if err != nil {
+			st.Unlock()
+			return err
+		}
+
+		// passing context so we can ignore self-conflicts with the current change
+		ts, err := ifacestateRefreshConnection(st, connRef, plugAttrs, slotAttrs, context)
+		if err != nil {
+			st.Unlock()
+			return err
+		}
+		ts.JoinLane(st.NewLane())
+		tts = append(tts, ts)
+	}
+	st.Unlock()
+
+	if len(tts) == 0 {
+		return errors.New(i18n.G("interface attributes can only be set during the execution of prepare hooks or for connected plugs and slots"))
+	}
+
+	if !context.IsEphemeral() && context.HookName() == "configure" {
+		return queueCommand(context, tts)
+	}
+
+	st.Lock()
+	chg := st.NewChange("refresh-connection", fmt.Sprintf("Refresh connections of %s:%s", snapName, plugOrSlot))
+	for _, ts := range tts {
+		chg.AddAll(ts)
+	}
+	st.EnsureBefore(0)
+	st.Unlock()
+
+	select {
+	case <-chg.Ready():
+		st.Lock()
+		defer st.Unlock()
+		return chg.Err()
+	case <-time.After(configstate.ConfigureHookTimeout() / 2):
+		return fmt.Errorf("refreshing connections of %s:%s is taking too long", snapName, plugOrSlot)
+	}
+}
+
+func copyAttributes(attrs map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		copied[k] = v
+	}
+	return copied
+}
+
 func setRegistryValues(ctx *hookstate.Context, plugName string, requests map[string]interface{}) error {
 	ctx.Lock()
 	defer ctx.Unlock()
@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	. "gopkg.in/check.v1"
 
@@ -392,17 +393,187 @@ func (s *setAttrSuite) TestSetCommandFailsOutsideOfValidContext(c *C) {
 
 	state := state.New(nil)
 	state.Lock()
-	defer state.Unlock()
-
 	task := state.NewTask("test-task", "my test task")
+	state.Unlock()
+
 	setup := &hookstate.HookSetup{Snap: "test-snap", Revision: snap.R(1), Hook: "not-a-connect-hook"}
 	mockContext, err = hookstate.NewContext(task, task.State(), setup, s.mockHandler, "")
 	c.Assert(err, IsNil)
 
 	stdout, stderr, err := ctlcmd.Run(mockContext, []string{"set", ":aplug", "foo=bar"}, 0)
+	c.Check(err, ErrorMatches, `interface attributes can only be set during the execution of prepare hooks or for connected plugs and slots`)
+	c.Check(string(stdout), Equals, "")
+	c.Check(string(stderr), Equals, "")
+}
+
+func (s *setAttrSuite) TestSetCommandFailsInConnectHook(c *C) {
+	st := state.New(nil)
+	st.Lock()
+	task := st.NewTask("test-task", "my test task")
+	st.Unlock()
+	setup := &hookstate.HookSetup{Snap: "test-snap", Revision: snap.R(1), Hook: "connect-plug-aplug"}
+	mockContext, err := hookstate.NewContext(task, st, setup, s.mockHandler, "")
+	c.Assert(err, IsNil)
+
+	_, _, err = ctlcmd.Run(mockContext, []string{"set", ":aplug", "foo=bar"}, 0)
 	c.Check(err, ErrorMatches, `interface attributes can only be set during the execution of prepare hooks`)
+}
+
+type refreshConnectionCall struct {
+	connRef   *interfaces.ConnRef
+	plugAttrs string
+	slotAttrs string
+	context   *hookstate.Context
+}
+
+func (s *setAttrSuite) mockConnections(c *C, hook string) (mockContext *hookstate.Context, calls *[]refreshConnectionCall, restore func()) {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	st.Set("conns", map[string]interface{}{
+		"test-snap:aplug other:bslot": map[string]interface{}{
+			"interface":    "test",
+			"plug-static":  map[string]interface{}{"lorem": "ipsum"},
+			"plug-dynamic": map[string]interface{}{"dyn": "old"},
+		},
+		"other:cplug test-snap:dslot": map[string]interface{}{
+			"interface":   "test",
+			"slot-static": map[string]interface{}{"lorem": "ipsum"},
+		},
+		"test-snap:aplug third:eslot": map[string]interface{}{
+			"interface": "test",
+			"undesired": true,
+		},
+	})
+
+	var task *state.Task
+	if hook != "" {
+		chg := st.NewChange("mychange", "...")
+		task = st.NewTask("run-hook", "my test task")
+		chg.AddTask(task)
+	}
+	setup := &hookstate.HookSetup{Snap: "test-snap", Revision: snap.R(1), Hook: hook}
+	mockContext, err := hookstate.NewContext(task, st, setup, s.mockHandler, "")
+	c.Assert(err, IsNil)
+
+	calls = &[]refreshConnectionCall{}
+	restore = ctlcmd.MockIfacestateRefreshConnection(func(st *state.State, connRef *interfaces.ConnRef, plugAttrs, slotAttrs map[string]interface{}, ctx *hookstate.Context) (*state.TaskSet, error) {
+		call := refreshConnectionCall{connRef: connRef, context: ctx}
+		if plugAttrs != nil {
+			data, err := json.Marshal(plugAttrs)
+			c.Assert(err, IsNil)
+			call.plugAttrs = string(data)
+		}
+		if slotAttrs != nil {
+			data, err := json.Marshal(slotAttrs)
+			c.Assert(err, IsNil)
+			call.slotAttrs = string(data)
+		}
+		*calls = append(*calls, call)
+		return state.NewTaskSet(st.NewTask("refresh-connection", "...")), nil
+	})
+
+	return mockContext, calls, restore
+}
+
+func (s *setAttrSuite) TestSetConnectedPlugAttributesInConfigureHook(c *C) {
+	mockContext, calls, restore := s.mockConnections(c, "configure")
+	defer restore()
+
+	stdout, stderr, err := ctlcmd.Run(mockContext, []string{"set", ":aplug", "dyn=new", "my.attr=1"}, 0)
+	c.Assert(err, IsNil)
 	c.Check(string(stdout), Equals, "")
 	c.Check(string(stderr), Equals, "")
+
+	c.Assert(*calls, HasLen, 1)
+	call := (*calls)[0]
+	c.Check(call.connRef.ID(), Equals, "test-snap:aplug other:bslot")
+	c.Check(call.plugAttrs, Equals, `{"dyn":"new","my":{"attr":1}}`)
+	c.Check(call.slotAttrs, Equals, "")
+	c.Check(call.context, Equals, mockContext)
+
+	// the refresh was queued in the change of the hook
+	st := mockContext.State()
+	st.Lock()
+	defer st.Unlock()
+	task, _ := mockContext.Task()
+	var kinds []string
+	for _, t := range task.Change().Tasks() {
+		kinds = append(kinds, t.Kind())
+	}
+	c.Check(kinds, DeepEquals, []string{"run-hook", "refresh-connection"})
+	c.Check(st.Changes(), HasLen, 1)
+}
+
+func (s *setAttrSuite) TestSetConnectedSlotAttributes(c *C) {
+	mockContext, calls, restore := s.mockConnections(c, "configure")
+	defer restore()
+
+	_, _, err := ctlcmd.Run(mockContext, []string{"set", ":dslot", "foo=bar"}, 0)
+	c.Assert(err, IsNil)
+
+	c.Assert(*calls, HasLen, 1)
+	call := (*calls)[0]
+	c.Check(call.connRef.ID(), Equals, "other:cplug test-snap:dslot")
+	c.Check(call.plugAttrs, Equals, "")
+	c.Check(call.slotAttrs, Equals, `{"foo":"bar"}`)
+}
+
+func (s *setAttrSuite) TestSetConnectedPlugAttributesEphemeral(c *C) {
+	mockContext, calls, restore := s.mockConnections(c, "")
+	defer restore()
+	st := mockContext.State()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		// act as the task runner
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond):
+			}
+			st.Lock()
+			for _, chg := range st.Changes() {
+				for _, t := range chg.Tasks() {
+					t.SetStatus(state.DoneStatus)
+				}
+			}
+			st.Unlock()
+		}
+	}()
+
+	_, _, err := ctlcmd.Run(mockContext, []string{"set", ":aplug", "dyn=new"}, 0)
+	c.Assert(err, IsNil)
+
+	c.Assert(*calls, HasLen, 1)
+	c.Check((*calls)[0].plugAttrs, Equals, `{"dyn":"new"}`)
+
+	st.Lock()
+	defer st.Unlock()
+	chgs := st.Changes()
+	c.Assert(chgs, HasLen, 1)
+	c.Check(chgs[0].Kind(), Equals, "refresh-connection")
+	c.Check(chgs[0].Summary(), Equals, "Refresh connections of test-snap:aplug")
+	c.Check(chgs[0].Status(), Equals, state.DoneStatus)
+}
+
+func (s *setAttrSuite) TestSetConnectedPlugAttributesErrors(c *C) {
+	mockContext, calls, restore := s.mockConnections(c, "configure")
+	defer restore()
+
+	_, _, err := ctlcmd.Run(mockContext, []string{"set", ":aplug", "lorem=other"}, 0)
+	c.Check(err, ErrorMatches, `cannot set attribute: attribute "lorem" cannot be overwritten`)
+
+	_, _, err = ctlcmd.Run(mockContext, []string{"set", ":aplug", "foo"}, 0)
+	c.Check(err, ErrorMatches, `invalid parameter: "foo" \(want key=value\)`)
+
+	_, _, err = ctlcmd.Run(mockContext, []string{"set", ":other", "foo=bar"}, 0)
+	c.Check(err, ErrorMatches, `interface attributes can only be set during the execution of prepare hooks or for connected plugs and slots`)
+
+	c.Check(*calls, HasLen, 0)
 }
 
 func (s *registrySuite) TestRegistrySetSingleView(c *C) {
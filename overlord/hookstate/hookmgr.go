@@ -430,11 +430,15 @@ func (m *HookManager) runHook(context *Context, snapst *snapstate.SnapState, hoo
 	// some hooks get hijacked, e.g. the core configuration
 	var err error
 	var output []byte
+	t0 := time.Now()
 	if f := m.hijacked(hooksup.Hook, hooksup.Snap); f != nil {
 		err = f(context)
 	} else if hookExists {
 		output, err = runHook(context, tomb)
 	}
+	if hookExists || mustHijack {
+		observeHookRun(hooksup.Hook, time.Since(t0), err)
+	}
 	if err != nil {
 		// TODO: telemetry about errors here
 		err = osutil.OutputErr(output, err)
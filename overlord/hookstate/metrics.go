@@ -0,0 +1,40 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package hookstate
+
+import (
+	"time"
+
+	"github.com/snapcore/snapd/metrics"
+)
+
+var hookRunDuration = metrics.MustRegister(metrics.NewHistogramVec(
+	"snapd_hook_run_duration_seconds",
+	"Time spent running hooks, by hook name and outcome.",
+	[]float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300, 600},
+	"hook", "result"))
+
+func observeHookRun(hook string, d time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	hookRunDuration.With(hook, result).Observe(d.Seconds())
+}
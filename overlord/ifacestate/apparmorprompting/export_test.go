@@ -20,8 +20,10 @@
 package apparmorprompting
 
 import (
+	"github.com/snapcore/snapd/dirs"
 	"github.com/snapcore/snapd/interfaces/prompting/requestprompts"
 	"github.com/snapcore/snapd/interfaces/prompting/requestrules"
+	"github.com/snapcore/snapd/osutil/user"
 	"github.com/snapcore/snapd/sandbox/apparmor/notify/listener"
 	"github.com/snapcore/snapd/testutil"
 )
@@ -105,6 +107,14 @@ func MockRequestReply(f func(req *listener.Request, allowedPermission any) error
 	}
 }
 
+func MockUserLookupId(f func(uid string) (*user.User, error)) (restore func()) {
+	return testutil.Mock(&userLookupId, f)
+}
+
+func MockAllUsers(f func(opts *dirs.SnapDirOptions) ([]*user.User, error)) (restore func()) {
+	return testutil.Mock(&allUsers, f)
+}
+
 func (m *InterfacesRequestsManager) PromptDB() *requestprompts.PromptDB {
 	return m.prompts
 }
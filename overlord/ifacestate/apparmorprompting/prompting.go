@@ -20,19 +20,30 @@
 package apparmorprompting
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"sync"
 
 	"gopkg.in/tomb.v2"
 
+	"github.com/snapcore/snapd/interfaces"
 	"github.com/snapcore/snapd/interfaces/prompting"
 	prompting_errors "github.com/snapcore/snapd/interfaces/prompting/errors"
 	"github.com/snapcore/snapd/interfaces/prompting/requestprompts"
 	"github.com/snapcore/snapd/interfaces/prompting/requestrules"
 	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil/user"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/ifacestate/ifacerepo"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/sandbox/apparmor/notify/listener"
+	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/naming"
 )
 
@@ -44,6 +55,9 @@ var (
 	listenerReqs     = func(l *listener.Listener) <-chan *listener.Request { return l.Reqs() }
 
 	requestReply = func(req *listener.Request, allowedPermission any) error { return req.Reply(allowedPermission) }
+
+	userLookupId = user.LookupId
+	allUsers     = snap.AllUsers
 )
 
 // A Manager holds outstanding prompts and mediates their replies, further it
@@ -53,11 +67,15 @@ type Manager interface {
 	PromptWithID(userID uint32, promptID prompting.IDType) (*requestprompts.Prompt, error)
 	HandleReply(userID uint32, promptID prompting.IDType, constraints *prompting.Constraints, outcome prompting.OutcomeType, lifespan prompting.LifespanType, duration string) ([]prompting.IDType, error)
 	Rules(userID uint32, snap string, iface string) ([]*requestrules.Rule, error)
+	ExplainRules(userID uint32, snap string, iface string, path string, permission string) (*requestrules.Explanation, error)
 	AddRule(userID uint32, snap string, iface string, constraints *prompting.Constraints, outcome prompting.OutcomeType, lifespan prompting.LifespanType, duration string) (*requestrules.Rule, error)
 	RemoveRules(userID uint32, snap string, iface string) ([]*requestrules.Rule, error)
 	RuleWithID(userID uint32, ruleID prompting.IDType) (*requestrules.Rule, error)
 	PatchRule(userID uint32, ruleID prompting.IDType, constraints *prompting.Constraints, outcome prompting.OutcomeType, lifespan prompting.LifespanType, duration string) (*requestrules.Rule, error)
 	RemoveRule(userID uint32, ruleID prompting.IDType) (*requestrules.Rule, error)
+	ExportRules(userID uint32) (*requestrules.Policy, error)
+	ImportRules(userID uint32, policy *requestrules.Policy, replace bool) (added []*requestrules.Rule, removed []*requestrules.Rule, err error)
+	RegisterPromptClient(userID uint32) (done func())
 }
 
 // verify that InterfacesRequestsManager implements Manager
@@ -70,15 +88,28 @@ type InterfacesRequestsManager struct {
 	// or when removing those databases. The lock can be held for reading when
 	// acting on just one or the other, as each has an internal mutex as well.
 	lock     sync.RWMutex
+	state    *state.State
+	repo     *interfaces.Repository
 	listener *listener.Listener
 	prompts  *requestprompts.PromptDB
 	rules    *requestrules.RuleDB
 
 	notifyPrompt func(userID uint32, promptID prompting.IDType, data map[string]string) error
 	notifyRule   func(userID uint32, ruleID prompting.IDType, data map[string]string) error
+
+	// defaultPolicyLock protects defaultPolicy and serializes applying it.
+	// It must be taken before the state lock or the manager lock.
+	defaultPolicyLock sync.Mutex
+	defaultPolicy     defaultPolicyCache
 }
 
 func New(s *state.State) (m *InterfacesRequestsManager, retErr error) {
+	// The repository is only looked up once, so that handling requests does
+	// not require the state lock.
+	s.Lock()
+	repo := ifacerepo.Get(s)
+	s.Unlock()
+
 	notifyPrompt := func(userID uint32, promptID prompting.IDType, data map[string]string) error {
 		// TODO: add some sort of queue so that notifyPrompt calls can return
 		// quickly without waiting for state lock and AddNotice() to return.
@@ -133,6 +164,8 @@ func New(s *state.State) (m *InterfacesRequestsManager, retErr error) {
 	}()
 
 	m = &InterfacesRequestsManager{
+		state:        s,
+		repo:         repo,
 		listener:     listenerBackend,
 		prompts:      promptsBackend,
 		rules:        rulesBackend,
@@ -140,6 +173,13 @@ func New(s *state.State) (m *InterfacesRequestsManager, retErr error) {
 		notifyRule:   notifyRule,
 	}
 
+	// Pick up the prompting options, and provision the rules of the default
+	// policy for the users known so far, so that they show up before the
+	// users make any request.
+	if err := m.EnsureConfig(); err != nil {
+		logger.Noticef("cannot apply prompting configuration: %v", err)
+	}
+
 	m.tomb.Go(m.run)
 
 	return m, nil
@@ -187,6 +227,313 @@ run_loop:
 	return m.disconnect()
 }
 
+// EnsureConfig picks up changes to the prompting core options: it updates the
+// policy for resolving prompts to which no client replies, and applies the
+// default policy as EnsureDefaultPolicy does. It is called when the manager is
+// created and whenever the system configuration may have changed, so that
+// handling requests never requires the state lock.
+//
+// The caller must not hold the state lock or the manager lock.
+func (m *InterfacesRequestsManager) EnsureConfig() error {
+	policyErr := m.updatePromptingPolicy()
+	if err := m.EnsureDefaultPolicy(); err != nil {
+		return err
+	}
+	return policyErr
+}
+
+// updatePromptingPolicy sets the policy of the prompt DB according to the
+// current configuration, falling back to the default policy on error.
+//
+// The caller must not hold the state lock or the manager lock.
+func (m *InterfacesRequestsManager) updatePromptingPolicy() error {
+	policy, err := promptingPolicy(m.state)
+	if err != nil {
+		err = fmt.Errorf("cannot get prompting policy, using defaults: %w", err)
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.prompts != nil {
+		m.prompts.SetPolicy(policy)
+	}
+	return err
+}
+
+// promptingPolicy returns the policy for resolving prompts to which no client
+// replies, according to the prompting.timeout, prompting.no-client-timeout and
+// prompting.default-outcome core options. If an error occurs, the default
+// policy is returned along with the error.
+func promptingPolicy(st *state.State) (requestprompts.Policy, error) {
+	policy := requestprompts.Policy{
+		DefaultOutcome: prompting.DefaultOutcomeDeny,
+	}
+
+	st.Lock()
+	defer st.Unlock()
+	tr := config.NewTransaction(st)
+	var timeoutStr, noClientTimeoutStr, outcomeStr string
+	if err := tr.Get("core", "prompting.timeout", &timeoutStr); err != nil && !config.IsNoOption(err) {
+		return policy, err
+	}
+	if err := tr.Get("core", "prompting.no-client-timeout", &noClientTimeoutStr); err != nil && !config.IsNoOption(err) {
+		return policy, err
+	}
+	if err := tr.Get("core", "prompting.default-outcome", &outcomeStr); err != nil && !config.IsNoOption(err) {
+		return policy, err
+	}
+
+	timeout, err := prompting.ParsePromptTimeout(timeoutStr)
+	if err != nil {
+		return policy, err
+	}
+	noClientTimeout, err := prompting.ParsePromptTimeout(noClientTimeoutStr)
+	if err != nil {
+		return policy, err
+	}
+	outcome, err := prompting.ParseDefaultOutcome(outcomeStr)
+	if err != nil {
+		return policy, err
+	}
+	policy.Timeout = timeout
+	policy.NoClientTimeout = noClientTimeout
+	policy.DefaultOutcome = outcome
+	return policy, nil
+}
+
+// defaultPolicyAppliedKey is the state key under which the digest of the
+// default prompting policy which was last applied is recorded for each user.
+const defaultPolicyAppliedKey = "prompting-default-policy-applied"
+
+// defaultPolicyCache holds the policy set by the prompting.default-policy
+// core option, as last read from the configuration, along with the users to
+// which it has been applied.
+type defaultPolicyCache struct {
+	// raw is the policy as found in the configuration, so that changes to
+	// the option can be told without computing its digest again.
+	raw     json.RawMessage
+	policy  *requestrules.Policy
+	digest  string
+	applied map[uint32]bool
+}
+
+// EnsureDefaultPolicy reads the policy set by the prompting.default-policy
+// core option and, if it changed since it was last read, applies it to the
+// rules of the known users of the system to which it has not been applied
+// yet. It is called when the manager is created and whenever the system
+// configuration may have changed.
+//
+// The caller must not hold the state lock or the manager lock.
+func (m *InterfacesRequestsManager) EnsureDefaultPolicy() error {
+	m.defaultPolicyLock.Lock()
+	defer m.defaultPolicyLock.Unlock()
+
+	pending, err := m.reloadDefaultPolicy()
+	if err != nil {
+		return err
+	}
+	for _, userID := range pending {
+		if err := m.applyDefaultPolicy(userID); err != nil {
+			logger.Noticef("cannot apply default prompting policy for user %d: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// reloadDefaultPolicy updates the cached default policy from the
+// configuration and, if it changed, returns the known users to which it has
+// not been applied yet. Users are known if the default policy was ever
+// applied to them, or if they have run snaps.
+//
+// The caller must hold the default policy lock, but not the state lock.
+func (m *InterfacesRequestsManager) reloadDefaultPolicy() (pending []uint32, err error) {
+	m.state.Lock()
+	defer m.state.Unlock()
+	tr := config.NewTransaction(m.state)
+	var raw json.RawMessage
+	if err := tr.GetMaybe("core", "prompting.default-policy", &raw); err != nil {
+		return nil, err
+	}
+	cache := &m.defaultPolicy
+	if cache.applied != nil && bytes.Equal(raw, cache.raw) {
+		return nil, nil
+	}
+
+	var policy *requestrules.Policy
+	var digest string
+	if len(raw) != 0 {
+		policy = &requestrules.Policy{}
+		if err := json.Unmarshal(raw, policy); err != nil {
+			return nil, err
+		}
+		digestBytes := sha256.Sum256(raw)
+		digest = hex.EncodeToString(digestBytes[:])
+	}
+
+	var recorded map[string]string
+	if err := m.state.Get(defaultPolicyAppliedKey, &recorded); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	applied := make(map[uint32]bool, len(recorded))
+	known := make(map[uint32]bool, len(recorded))
+	for uidStr, userDigest := range recorded {
+		uid, err := strconv.ParseUint(uidStr, 10, 32)
+		if err != nil {
+			continue
+		}
+		known[uint32(uid)] = true
+		if userDigest == digest {
+			applied[uint32(uid)] = true
+		}
+	}
+	users, err := allUsers(nil)
+	if err != nil {
+		logger.Noticef("cannot list the users of the system: %v", err)
+	}
+	for _, u := range users {
+		uid, err := strconv.ParseUint(u.Uid, 10, 32)
+		if err != nil || uid == 0 {
+			// rules do not apply to the root user
+			continue
+		}
+		known[uint32(uid)] = true
+	}
+
+	*cache = defaultPolicyCache{
+		raw:     raw,
+		policy:  policy,
+		digest:  digest,
+		applied: applied,
+	}
+	if policy == nil {
+		return nil, nil
+	}
+	for uid := range known {
+		if !applied[uid] {
+			pending = append(pending, uid)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i] < pending[j] })
+	return pending, nil
+}
+
+// ensureDefaultPolicyApplied applies the cached default policy to the rules
+// of the given user, unless it was already applied to them. This covers users
+// who were not known when the policy was last read.
+//
+// The caller must not hold the state lock or the manager lock.
+func (m *InterfacesRequestsManager) ensureDefaultPolicyApplied(userID uint32) {
+	m.defaultPolicyLock.Lock()
+	defer m.defaultPolicyLock.Unlock()
+	if m.defaultPolicy.policy == nil || m.defaultPolicy.applied[userID] {
+		return
+	}
+	if err := m.applyDefaultPolicy(userID); err != nil {
+		logger.Noticef("cannot apply default prompting policy for user %d: %v", userID, err)
+	}
+}
+
+// applyDefaultPolicy merges the rules of the cached default policy into the
+// rules of the given user.
+//
+// Rules of the default policy which conflict with existing rules are skipped,
+// so rules which the user created themself take precedence over the default
+// policy. Either way, the policy is then recorded as applied, so that rules
+// which the user removed are not provisioned again until the policy changes.
+//
+// The caller must hold the default policy lock, but neither the state lock
+// nor the manager lock.
+func (m *InterfacesRequestsManager) applyDefaultPolicy(userID uint32) error {
+	// Whether or not this succeeds, do not try again until the policy
+	// changes or snapd restarts.
+	m.defaultPolicy.applied[userID] = true
+	policy := m.defaultPolicy.policy
+	homeDir, err := homeDirForUser(userID)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	for _, policyRule := range policy.Rules {
+		single := &requestrules.Policy{
+			Version: policy.Version,
+			Rules:   []*requestrules.PolicyRule{policyRule},
+		}
+		added, _, err := m.rules.ImportPolicy(userID, homeDir, single, false)
+		if err != nil {
+			logger.Noticef("cannot apply default prompting policy rule for snap %q and path pattern %q to user %d: %v", policyRule.Snap, policyRule.PathPattern, userID, err)
+			continue
+		}
+		for _, rule := range added {
+			m.applyRuleToOutstandingPrompts(rule)
+		}
+	}
+	m.lock.Unlock()
+
+	m.state.Lock()
+	defer m.state.Unlock()
+	var recorded map[string]string
+	if err := m.state.Get(defaultPolicyAppliedKey, &recorded); err != nil && !errors.Is(err, state.ErrNoState) {
+		return err
+	}
+	if recorded == nil {
+		recorded = make(map[string]string, 1)
+	}
+	recorded[strconv.FormatUint(uint64(userID), 10)] = m.defaultPolicy.digest
+	m.state.Set(defaultPolicyAppliedKey, recorded)
+	return nil
+}
+
+// homeDirForUser returns the home directory of the user with the given ID.
+func homeDirForUser(userID uint32) (string, error) {
+	u, err := userLookupId(strconv.FormatUint(uint64(userID), 10))
+	if err != nil {
+		return "", err
+	}
+	return u.HomeDir, nil
+}
+
+// connectedPromptingInterfaces returns the interfaces connected to the plugs
+// of the given snap, mapped to the paths listed in the read and write
+// attributes of those plugs, if any.
+func connectedPromptingInterfaces(repo *interfaces.Repository, snapName string) (map[string][]string, error) {
+	connected := make(map[string][]string)
+	if snapName == "" {
+		return connected, nil
+	}
+	connRefs, err := repo.Connections(snapName)
+	if err != nil {
+		return nil, err
+	}
+	for _, connRef := range connRefs {
+		if connRef.PlugRef.Snap != snapName {
+			continue
+		}
+		conn, err := repo.Connection(connRef)
+		if err != nil {
+			return nil, err
+		}
+		iface := conn.Plug.Interface()
+		paths := connected[iface]
+		for _, attr := range []string{"read", "write"} {
+			value, ok := conn.Plug.Lookup(attr)
+			if !ok {
+				continue
+			}
+			list, ok := value.([]interface{})
+			if !ok {
+				continue
+			}
+			for _, item := range list {
+				if path, ok := item.(string); ok {
+					paths = append(paths, path)
+				}
+			}
+		}
+		connected[iface] = paths
+	}
+	return connected, nil
+}
+
 func (m *InterfacesRequestsManager) handleListenerReq(req *listener.Request) error {
 	userID := uint32(req.SubjectUID)
 	if userID == 0 {
@@ -200,10 +547,21 @@ func (m *InterfacesRequestsManager) handleListenerReq(req *listener.Request) err
 		snap = tag.InstanceName()
 	}
 
-	// TODO: when we support interfaces beyond "home", do a proper selection here
-	iface := "home"
-
 	path := req.Path
+	homeDir, err := homeDirForUser(userID)
+	if err != nil {
+		logger.Noticef("cannot get home directory of user %d: %v", userID, err)
+	}
+	connected, err := connectedPromptingInterfaces(m.repo, snap)
+	if err != nil {
+		logger.Noticef("cannot get connected interfaces of snap %q: %v", snap, err)
+		return requestReply(req, nil)
+	}
+	iface, err := prompting.InterfaceForPath(path, homeDir, connected)
+	if err != nil {
+		logger.Noticef("cannot handle request of snap %q: %v", snap, err)
+		return requestReply(req, nil)
+	}
 
 	permissions, err := prompting.AbstractPermissionsFromAppArmorPermissions(iface, req.Permission)
 	if err != nil {
@@ -214,6 +572,11 @@ func (m *InterfacesRequestsManager) handleListenerReq(req *listener.Request) err
 	remainingPerms := make([]string, 0, len(permissions))
 	satisfiedPerms := make([]string, 0, len(permissions))
 
+	// Rules provisioned through the prompting.default-policy option must be
+	// in place before the request is checked against the rules. Applying
+	// them takes the lock itself.
+	m.ensureDefaultPolicyApplied(userID)
+
 	// we're done with early checks, serious business starts now, and we can
 	// take the lock
 	m.lock.Lock()
@@ -345,16 +708,38 @@ func (m *InterfacesRequestsManager) Stop() error {
 }
 
 // Prompts returns all prompts for the user with the given user ID.
+//
+// Since prompts are retrieved by prompting clients, this counts as activity
+// of a client for the user.
 func (m *InterfacesRequestsManager) Prompts(userID uint32) ([]*requestprompts.Prompt, error) {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
+	m.prompts.NoteClientActivity(userID)
 	return m.prompts.Prompts(userID)
 }
 
+// RegisterPromptClient records that a prompting client for the given user is
+// listening for prompt notices, and returns a function which must be called
+// once the client stops listening.
+//
+// If the prompting.no-client-timeout option is set, new prompts created while
+// no client is listening are resolved with the default outcome after that
+// delay rather than remaining outstanding until the prompting.timeout.
+func (m *InterfacesRequestsManager) RegisterPromptClient(userID uint32) (done func()) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.prompts == nil {
+		// Manager has been stopped
+		return func() {}
+	}
+	return m.prompts.RegisterClient(userID)
+}
+
 // PromptWithID returns the prompt with the given ID for the given user.
 func (m *InterfacesRequestsManager) PromptWithID(userID uint32, promptID prompting.IDType) (*requestprompts.Prompt, error) {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
+	m.prompts.NoteClientActivity(userID)
 	return m.prompts.PromptWithID(userID, promptID)
 }
 
@@ -367,6 +752,7 @@ func (m *InterfacesRequestsManager) HandleReply(userID uint32, promptID promptin
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	m.prompts.NoteClientActivity(userID)
 	prompt, err := m.prompts.PromptWithID(userID, promptID)
 	if err != nil {
 		return nil, err
@@ -463,6 +849,8 @@ func (m *InterfacesRequestsManager) applyRuleToOutstandingPrompts(rule *requestr
 // Rules returns all rules for the user with the given user ID and,
 // optionally, only those for the given snap and/or interface.
 func (m *InterfacesRequestsManager) Rules(userID uint32, snap string, iface string) ([]*requestrules.Rule, error) {
+	m.ensureDefaultPolicyApplied(userID)
+
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 
@@ -482,6 +870,16 @@ func (m *InterfacesRequestsManager) Rules(userID uint32, snap string, iface stri
 	return rules, nil
 }
 
+// ExplainRules returns which of the rules of the given user, snap, and
+// interface match the given path and permission, and which of them decides
+// the outcome of a request for that path and permission.
+func (m *InterfacesRequestsManager) ExplainRules(userID uint32, snap string, iface string, path string, permission string) (*requestrules.Explanation, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.rules.Explain(userID, snap, iface, path, permission)
+}
+
 // AddRule creates a new rule with the given contents and then checks it against
 // outstanding prompts, resolving any prompts which it satisfies.
 func (m *InterfacesRequestsManager) AddRule(userID uint32, snap string, iface string, constraints *prompting.Constraints, outcome prompting.OutcomeType, lifespan prompting.LifespanType, duration string) (*requestrules.Rule, error) {
@@ -554,3 +952,44 @@ func (m *InterfacesRequestsManager) RemoveRule(userID uint32, ruleID prompting.I
 	rule, err := m.rules.RemoveRule(userID, ruleID)
 	return rule, err
 }
+
+// ExportRules returns a policy holding the rules of the given user which have
+// a lifespan of "forever", with path patterns in the home directory of the
+// user written relative to "~/".
+func (m *InterfacesRequestsManager) ExportRules(userID uint32) (*requestrules.Policy, error) {
+	m.ensureDefaultPolicyApplied(userID)
+
+	homeDir, err := homeDirForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.rules.ExportPolicy(userID, homeDir), nil
+}
+
+// ImportRules adds the rules of the given policy for the given user, either
+// merging them with the existing rules of the user or replacing those, and
+// then checks the added rules against outstanding prompts, resolving any
+// prompts which they satisfy.
+func (m *InterfacesRequestsManager) ImportRules(userID uint32, policy *requestrules.Policy, replace bool) (added []*requestrules.Rule, removed []*requestrules.Rule, err error) {
+	homeDir, err := homeDirForUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	added, removed, err = m.rules.ImportPolicy(userID, homeDir, policy, replace)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Apply new rules to outstanding prompts.
+	for _, rule := range added {
+		m.applyRuleToOutstandingPrompts(rule)
+	}
+	return added, removed, nil
+}
@@ -21,6 +21,7 @@ package apparmorprompting_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -32,16 +33,22 @@ import (
 	. "gopkg.in/check.v1"
 
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/builtin"
 	"github.com/snapcore/snapd/interfaces/prompting"
 	prompting_errors "github.com/snapcore/snapd/interfaces/prompting/errors"
 	"github.com/snapcore/snapd/interfaces/prompting/patterns"
 	"github.com/snapcore/snapd/interfaces/prompting/requestprompts"
 	"github.com/snapcore/snapd/interfaces/prompting/requestrules"
 	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/osutil/user"
+	"github.com/snapcore/snapd/overlord/configstate/config"
 	"github.com/snapcore/snapd/overlord/ifacestate/apparmorprompting"
+	"github.com/snapcore/snapd/overlord/ifacestate/ifacerepo"
 	"github.com/snapcore/snapd/overlord/state"
 	"github.com/snapcore/snapd/sandbox/apparmor/notify"
 	"github.com/snapcore/snapd/sandbox/apparmor/notify/listener"
+	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/testutil"
 )
 
@@ -66,6 +73,78 @@ func (s *apparmorpromptingSuite) SetUpTest(c *C) {
 
 	s.st = state.New(nil)
 	s.defaultUser = 1000
+
+	s.AddCleanup(apparmorprompting.MockUserLookupId(func(uid string) (*user.User, error) {
+		switch uid {
+		case "1000":
+			return &user.User{Uid: uid, HomeDir: "/home/test"}, nil
+		case "1001":
+			return &user.User{Uid: uid, HomeDir: "/home/other"}, nil
+		}
+		return nil, fmt.Errorf("unknown user %s", uid)
+	}))
+	s.AddCleanup(apparmorprompting.MockAllUsers(func(opts *dirs.SnapDirOptions) ([]*user.User, error) {
+		return []*user.User{{Uid: "0", HomeDir: "/root"}}, nil
+	}))
+
+	s.st.Lock()
+	ifacerepo.Replace(s.st, s.mockRepo(c))
+	s.st.Unlock()
+}
+
+const coreYaml = `name: core
+version: 1
+type: os
+slots:
+  home:
+  personal-files:
+  removable-media:
+  camera:
+  audio-record:
+`
+
+const firefoxYaml = `name: firefox
+version: 1
+plugs:
+  dot-config:
+    interface: personal-files
+    write: [$HOME/.config]
+apps:
+  firefox:
+    command: firefox
+    plugs: [home, dot-config, removable-media, camera, audio-record, network]
+`
+
+// mockRepo returns an interfaces repository in which the prompting
+// interfaces are connected to the plugs of the firefox snap.
+func (s *apparmorpromptingSuite) mockRepo(c *C) *interfaces.Repository {
+	repo := interfaces.NewRepository()
+	for _, iface := range builtin.Interfaces() {
+		c.Assert(repo.AddInterface(iface), IsNil)
+	}
+	for _, yaml := range []string{coreYaml, firefoxYaml} {
+		info := snaptest.MockInfo(c, yaml, nil)
+		appSet, err := interfaces.NewSnapAppSet(info, nil)
+		c.Assert(err, IsNil)
+		c.Assert(repo.AddAppSet(appSet), IsNil)
+	}
+	for _, conn := range []struct{ plug, slot string }{
+		{"home", "home"},
+		{"dot-config", "personal-files"},
+		{"removable-media", "removable-media"},
+		{"camera", "camera"},
+		{"audio-record", "audio-record"},
+	} {
+		plug := repo.Plug("firefox", conn.plug)
+		c.Assert(plug, NotNil)
+		connRef := &interfaces.ConnRef{
+			PlugRef: interfaces.PlugRef{Snap: "firefox", Name: conn.plug},
+			SlotRef: interfaces.SlotRef{Snap: "core", Name: conn.slot},
+		}
+		_, err := repo.Connect(connRef, plug.Attrs, nil, nil, nil, nil)
+		c.Assert(err, IsNil)
+	}
+	return repo
 }
 
 func (s *apparmorpromptingSuite) TestNew(c *C) {
@@ -208,7 +287,9 @@ func (s *apparmorpromptingSuite) TestHandleListenerRequestErrors(c *C) {
 	// Send request with invalid permissions
 	req := &listener.Request{
 		// Most fields don't matter here
+		Label:      "snap.firefox.firefox",
 		SubjectUID: s.defaultUser,
+		Path:       "/home/test/foo",
 		Permission: notify.FilePermission(0),
 	}
 	reqChan <- req
@@ -290,6 +371,354 @@ func (s *apparmorpromptingSuite) TestHandleReplySimple(c *C) {
 	c.Assert(mgr.Stop(), IsNil)
 }
 
+func (s *apparmorpromptingSuite) TestHandleListenerRequestInterfaceSelection(c *C) {
+	reqChan, replyChan, restore := apparmorprompting.MockListener()
+	defer restore()
+
+	mgr, err := apparmorprompting.New(s.st)
+	c.Assert(err, IsNil)
+
+	for _, testCase := range []struct {
+		path        string
+		permission  notify.FilePermission
+		iface       string
+		permissions []string
+	}{
+		{"/home/test/Documents/foo.txt", notify.AA_MAY_READ, "home", []string{"read"}},
+		{"/home/test/.config/foo", notify.AA_MAY_WRITE, "personal-files", []string{"write"}},
+		{"/media/test/usb/foo.txt", notify.AA_MAY_EXEC, "removable-media", []string{"execute"}},
+		{"/dev/video0", notify.AA_MAY_OPEN | notify.AA_MAY_READ | notify.AA_MAY_WRITE, "camera", []string{"access"}},
+		{"/dev/snd/pcmC0D0c", notify.AA_MAY_READ, "audio-record", []string{"access"}},
+	} {
+		whenSent := time.Now()
+		req := &listener.Request{
+			Path:       testCase.path,
+			Permission: testCase.permission,
+		}
+		s.fillInPartialRequest(req)
+		reqChan <- req
+		time.Sleep(10 * time.Millisecond)
+		s.checkRecordedPromptNotices(c, whenSent, 1)
+
+		prompts, err := mgr.Prompts(s.defaultUser)
+		c.Assert(err, IsNil)
+		var prompt *requestprompts.Prompt
+		for _, p := range prompts {
+			if p.Constraints.Path() == testCase.path {
+				prompt = p
+			}
+		}
+		c.Assert(prompt, NotNil, Commentf("no prompt for path: %s", testCase.path))
+		c.Check(prompt.Interface, Equals, testCase.iface)
+		c.Check(prompt.Constraints.RemainingPermissions(), DeepEquals, testCase.permissions)
+
+		// Reply with a rule for the interface of the prompt
+		constraints := &prompting.Constraints{
+			PathPattern: mustParsePathPattern(c, testCase.path),
+			Permissions: testCase.permissions,
+		}
+		_, err = mgr.HandleReply(s.defaultUser, prompt.ID, constraints, prompting.OutcomeAllow, prompting.LifespanForever, "")
+		c.Assert(err, IsNil)
+		resp, err := waitForReply(replyChan)
+		c.Assert(err, IsNil)
+		c.Check(resp.Request, Equals, req)
+
+		// The new rule allows future requests for the same path
+		req = &listener.Request{
+			Path:       testCase.path,
+			Permission: testCase.permission,
+		}
+		s.fillInPartialRequest(req)
+		reqChan <- req
+		resp, err = waitForReply(replyChan)
+		c.Assert(err, IsNil)
+		c.Check(resp.Request, Equals, req)
+		expected, err := prompting.AbstractPermissionsToAppArmorPermissions(testCase.iface, testCase.permissions)
+		c.Assert(err, IsNil)
+		c.Check(resp.AllowedPermission, DeepEquals, expected)
+	}
+
+	rules, err := mgr.Rules(s.defaultUser, "firefox", "")
+	c.Assert(err, IsNil)
+	c.Check(rules, HasLen, 5)
+
+	c.Assert(mgr.Stop(), IsNil)
+}
+
+func (s *apparmorpromptingSuite) TestPromptExpiresWithConfiguredPolicy(c *C) {
+	reqChan, replyChan, restore := apparmorprompting.MockListener()
+	defer restore()
+
+	s.st.Lock()
+	tr := config.NewTransaction(s.st)
+	c.Assert(tr.Set("core", "prompting.timeout", "10ms"), IsNil)
+	c.Assert(tr.Set("core", "prompting.default-outcome", "allow-once"), IsNil)
+	tr.Commit()
+	s.st.Unlock()
+
+	mgr, err := apparmorprompting.New(s.st)
+	c.Assert(err, IsNil)
+
+	// A client is listening, so the configured timeout applies
+	done := mgr.RegisterPromptClient(s.defaultUser)
+	defer done()
+
+	whenSent := time.Now()
+	req := &listener.Request{
+		Permission: notify.AA_MAY_READ,
+	}
+	s.fillInPartialRequest(req)
+	reqChan <- req
+
+	// The prompt expires and is resolved with the default outcome
+	var resp *apparmorprompting.RequestResponse
+	for i := 0; i < 50; i++ {
+		resp, err = waitForReply(replyChan)
+		if err == nil {
+			break
+		}
+	}
+	c.Assert(err, IsNil)
+	c.Check(resp.Request, Equals, req)
+	expected, err := prompting.AbstractPermissionsToAppArmorPermissions("home", []string{"read"})
+	c.Assert(err, IsNil)
+	c.Check(resp.AllowedPermission, DeepEquals, expected)
+
+	prompts, err := mgr.Prompts(s.defaultUser)
+	c.Check(err, IsNil)
+	c.Check(prompts, HasLen, 0)
+
+	// One notice for the new prompt, updated when the prompt expired
+	s.checkRecordedPromptNotices(c, whenSent, 1)
+	s.st.Lock()
+	notices := s.st.Notices(&state.NoticeFilter{
+		Types: []state.NoticeType{state.InterfacesRequestsPromptNotice},
+		After: whenSent,
+	})
+	s.st.Unlock()
+	c.Assert(notices, HasLen, 1)
+	data, err := json.Marshal(notices[0])
+	c.Assert(err, IsNil)
+	var n map[string]any
+	c.Assert(json.Unmarshal(data, &n), IsNil)
+	c.Check(n["last-data"], DeepEquals, map[string]any{"resolved": "expired"})
+
+	c.Assert(mgr.Stop(), IsNil)
+}
+
+func (s *apparmorpromptingSuite) TestPromptExpiresWithoutClient(c *C) {
+	reqChan, replyChan, restore := apparmorprompting.MockListener()
+	defer restore()
+
+	mgr, err := apparmorprompting.New(s.st)
+	c.Assert(err, IsNil)
+
+	// By default, prompts do not expire even though no client is listening
+	req := &listener.Request{}
+	s.fillInPartialRequest(req)
+	reqChan <- req
+	_, err = waitForReply(replyChan)
+	c.Check(err, Equals, errNoReply)
+	prompts, err := mgr.Prompts(s.defaultUser)
+	c.Assert(err, IsNil)
+	c.Assert(prompts, HasLen, 1)
+	constraints := &prompting.Constraints{
+		PathPattern: mustParsePathPattern(c, "/home/test/foo"),
+		Permissions: []string{"read"},
+	}
+	_, err = mgr.HandleReply(s.defaultUser, prompts[0].ID, constraints, prompting.OutcomeDeny, prompting.LifespanSingle, "")
+	c.Assert(err, IsNil)
+	_, err = waitForReply(replyChan)
+	c.Assert(err, IsNil)
+
+	s.st.Lock()
+	tr := config.NewTransaction(s.st)
+	c.Assert(tr.Set("core", "prompting.no-client-timeout", "10ms"), IsNil)
+	tr.Commit()
+	s.st.Unlock()
+	c.Assert(mgr.EnsureConfig(), IsNil)
+
+	// The client which just retrieved and replied to prompts is still
+	// considered to be listening
+	req = &listener.Request{}
+	s.fillInPartialRequest(req)
+	reqChan <- req
+	_, err = waitForReply(replyChan)
+	c.Check(err, Equals, errNoReply)
+
+	c.Assert(mgr.Stop(), IsNil)
+}
+
+func (s *apparmorpromptingSuite) TestPromptingPolicyUpdatedOnEnsureConfig(c *C) {
+	reqChan, replyChan, restore := apparmorprompting.MockListener()
+	defer restore()
+
+	mgr, err := apparmorprompting.New(s.st)
+	c.Assert(err, IsNil)
+
+	done := mgr.RegisterPromptClient(s.defaultUser)
+	defer done()
+
+	s.st.Lock()
+	tr := config.NewTransaction(s.st)
+	c.Assert(tr.Set("core", "prompting.timeout", "10ms"), IsNil)
+	c.Assert(tr.Set("core", "prompting.default-outcome", "allow-once"), IsNil)
+	tr.Commit()
+	s.st.Unlock()
+
+	// The policy is not read again when handling requests
+	req := &listener.Request{}
+	s.fillInPartialRequest(req)
+	reqChan <- req
+	_, err = waitForReply(replyChan)
+	c.Check(err, Equals, errNoReply)
+	prompts, err := mgr.Prompts(s.defaultUser)
+	c.Assert(err, IsNil)
+	c.Assert(prompts, HasLen, 1)
+	constraints := &prompting.Constraints{
+		PathPattern: mustParsePathPattern(c, "/home/test/foo"),
+		Permissions: []string{"read"},
+	}
+	_, err = mgr.HandleReply(s.defaultUser, prompts[0].ID, constraints, prompting.OutcomeDeny, prompting.LifespanSingle, "")
+	c.Assert(err, IsNil)
+	_, err = waitForReply(replyChan)
+	c.Assert(err, IsNil)
+
+	// but only once the configuration is picked up
+	c.Assert(mgr.EnsureConfig(), IsNil)
+
+	req = &listener.Request{}
+	s.fillInPartialRequest(req)
+	reqChan <- req
+	var resp *apparmorprompting.RequestResponse
+	for i := 0; i < 50; i++ {
+		resp, err = waitForReply(replyChan)
+		if err == nil {
+			break
+		}
+	}
+	c.Assert(err, IsNil)
+	c.Check(resp.Request, Equals, req)
+	expected, err := prompting.AbstractPermissionsToAppArmorPermissions("home", []string{"read"})
+	c.Assert(err, IsNil)
+	c.Check(resp.AllowedPermission, DeepEquals, expected)
+
+	c.Assert(mgr.Stop(), IsNil)
+}
+
+func (s *apparmorpromptingSuite) TestHandleListenerRequestWithoutStateLock(c *C) {
+	reqChan, replyChan, restore := apparmorprompting.MockListener()
+	defer restore()
+
+	mgr, err := apparmorprompting.New(s.st)
+	c.Assert(err, IsNil)
+
+	constraints := &prompting.Constraints{
+		PathPattern: mustParsePathPattern(c, "/home/test/foo"),
+		Permissions: []string{"read"},
+	}
+	_, err = mgr.AddRule(s.defaultUser, "firefox", "home", constraints, prompting.OutcomeAllow, prompting.LifespanForever, "")
+	c.Assert(err, IsNil)
+
+	// Requests covered by rules are handled while the state is locked
+	s.st.Lock()
+	defer s.st.Unlock()
+	req := &listener.Request{}
+	s.fillInPartialRequest(req)
+	reqChan <- req
+	resp, err := waitForReply(replyChan)
+	c.Assert(err, IsNil)
+	c.Check(resp.Request, Equals, req)
+	expected, err := prompting.AbstractPermissionsToAppArmorPermissions("home", []string{"read"})
+	c.Assert(err, IsNil)
+	c.Check(resp.AllowedPermission, DeepEquals, expected)
+
+	s.st.Unlock()
+	c.Assert(mgr.Stop(), IsNil)
+	s.st.Lock()
+}
+
+func (s *apparmorpromptingSuite) TestHandleListenerRequestNoInterface(c *C) {
+	// editor has both home and personal-files granting access to
+	// ~/Private, so requests for it cannot be attributed to either
+	repo := s.mockRepo(c)
+	info := snaptest.MockInfo(c, `name: editor
+version: 1
+plugs:
+  private:
+    interface: personal-files
+    write: [$HOME/Private]
+apps:
+  editor:
+    command: editor
+    plugs: [home, private]
+`, nil)
+	appSet, err := interfaces.NewSnapAppSet(info, nil)
+	c.Assert(err, IsNil)
+	c.Assert(repo.AddAppSet(appSet), IsNil)
+	for plugName, slotName := range map[string]string{"home": "home", "private": "personal-files"} {
+		connRef := &interfaces.ConnRef{
+			PlugRef: interfaces.PlugRef{Snap: "editor", Name: plugName},
+			SlotRef: interfaces.SlotRef{Snap: "core", Name: slotName},
+		}
+		_, err := repo.Connect(connRef, repo.Plug("editor", plugName).Attrs, nil, nil, nil, nil)
+		c.Assert(err, IsNil)
+	}
+	s.st.Lock()
+	ifacerepo.Replace(s.st, repo)
+	s.st.Unlock()
+
+	reqChan, replyChan, restore := apparmorprompting.MockListener()
+	defer restore()
+	logbuf, restore := logger.MockLogger()
+	defer restore()
+
+	mgr, err := apparmorprompting.New(s.st)
+	c.Assert(err, IsNil)
+
+	for _, testCase := range []struct {
+		label  string
+		path   string
+		logged string
+	}{
+		{
+			"snap.firefox.firefox",
+			"/home/test/.ssh/id_rsa",
+			`cannot handle request of snap "firefox": cannot find a connected interface granting access to "/home/test/.ssh/id_rsa"`,
+		},
+		{
+			"snap.other.app",
+			"/home/test/foo",
+			`cannot handle request of snap "other": cannot find a connected interface granting access to "/home/test/foo"`,
+		},
+		{
+			"snap.editor.editor",
+			"/home/test/Private/notes.txt",
+			`cannot handle request of snap "editor": cannot tell which interface granted access to "/home/test/Private/notes.txt": any of "home", "personal-files" could have`,
+		},
+	} {
+		req := &listener.Request{
+			Label: testCase.label,
+			Path:  testCase.path,
+		}
+		s.fillInPartialRequest(req)
+		reqChan <- req
+		resp, err := waitForReply(replyChan)
+		c.Assert(err, IsNil)
+		c.Check(resp.Request, Equals, req)
+		c.Check(resp.AllowedPermission, IsNil)
+		logger.WithLoggerLock(func() {
+			c.Check(logbuf.String(), testutil.Contains, testCase.logged)
+		})
+	}
+
+	prompts, err := mgr.Prompts(s.defaultUser)
+	c.Assert(err, IsNil)
+	c.Check(prompts, HasLen, 0)
+
+	c.Assert(mgr.Stop(), IsNil)
+}
+
 func (s *apparmorpromptingSuite) simulateRequest(c *C, reqChan chan *listener.Request, mgr *apparmorprompting.InterfacesRequestsManager, req *listener.Request, shouldMerge bool) (*listener.Request, *requestprompts.Prompt) {
 	prompts, err := mgr.Prompts(s.defaultUser)
 	c.Check(err, IsNil)
@@ -1067,6 +1496,267 @@ func (s *apparmorpromptingSuite) TestRules(c *C) {
 	c.Assert(mgr.Stop(), IsNil)
 }
 
+func (s *apparmorpromptingSuite) TestExplainRules(c *C) {
+	_, _, restore := apparmorprompting.MockListener()
+	defer restore()
+
+	mgr, rules := s.prepManagerWithRules(c)
+
+	explanation, err := mgr.ExplainRules(s.defaultUser, "firefox", "home", "/home/test/1", "read")
+	c.Assert(err, IsNil)
+	c.Check(explanation.Outcome, Equals, prompting.OutcomeAllow)
+	c.Assert(explanation.Matches, HasLen, 1)
+	c.Check(explanation.Matches[0].Variant, Equals, "/home/test/1")
+	c.Assert(explanation.Matches[0].Rules, HasLen, 1)
+	c.Check(explanation.Matches[0].Rules[0].Rule, Equals, rules[0])
+
+	// The rule of thunderbird does not apply to firefox
+	explanation, err = mgr.ExplainRules(s.defaultUser, "firefox", "home", "/home/test/2", "read")
+	c.Assert(err, IsNil)
+	c.Check(explanation.Outcome, Equals, prompting.OutcomeUnset)
+	c.Check(explanation.Matches, HasLen, 0)
+
+	_, err = mgr.ExplainRules(s.defaultUser, "firefox", "home", "/home/test/1", "access")
+	c.Check(err, ErrorMatches, `invalid permissions for home interface: "access"`)
+
+	c.Assert(mgr.Stop(), IsNil)
+}
+
+func (s *apparmorpromptingSuite) TestExportImportRules(c *C) {
+	_, _, restore := apparmorprompting.MockListener()
+	defer restore()
+
+	mgr, _ := s.prepManagerWithRules(c)
+
+	policy, err := mgr.ExportRules(s.defaultUser)
+	c.Assert(err, IsNil)
+	c.Check(policy.Version, Equals, requestrules.PolicyVersion)
+	c.Assert(policy.Rules, HasLen, 3)
+	c.Check(policy.Rules[0].Snap, Equals, "firefox")
+	c.Check(policy.Rules[0].PathPattern, Equals, "~/1")
+
+	// The interface of the last rule was altered behind the back of the rule
+	// DB, so the rule is not valid for import.
+	_, _, err = mgr.ImportRules(s.defaultUser+1, policy, false)
+	c.Check(err, ErrorMatches, `invalid rule 3 in policy: invalid permissions for camera interface: "read"`)
+	policy.Rules = policy.Rules[:2]
+
+	otherUser := s.defaultUser + 1
+	whenImported := time.Now()
+	added, removed, err := mgr.ImportRules(otherUser, policy, false)
+	c.Assert(err, IsNil)
+	c.Check(removed, HasLen, 0)
+	c.Assert(added, HasLen, 2)
+	c.Check(added[0].User, Equals, otherUser)
+	c.Check(added[0].Constraints.PathPattern.String(), Equals, "/home/other/1")
+	s.checkRecordedRuleUpdateNotices(c, whenImported, 2)
+
+	// Replacing with an empty policy removes all rules of the user, including
+	// the one they had before the import
+	empty := &requestrules.Policy{Version: requestrules.PolicyVersion}
+	added, removed, err = mgr.ImportRules(otherUser, empty, true)
+	c.Assert(err, IsNil)
+	c.Check(added, HasLen, 0)
+	c.Check(removed, HasLen, 3)
+	rules, err := mgr.Rules(otherUser, "", "")
+	c.Assert(err, IsNil)
+	c.Check(rules, HasLen, 0)
+
+	// The home directory of unknown users cannot be determined
+	_, err = mgr.ExportRules(1234)
+	c.Check(err, ErrorMatches, "unknown user 1234")
+	_, _, err = mgr.ImportRules(1234, policy, false)
+	c.Check(err, ErrorMatches, "unknown user 1234")
+
+	c.Assert(mgr.Stop(), IsNil)
+}
+
+func (s *apparmorpromptingSuite) TestImportRulesHandlesExistingPrompt(c *C) {
+	reqChan, replyChan, restore := apparmorprompting.MockListener()
+	defer restore()
+
+	mgr, err := apparmorprompting.New(s.st)
+	c.Assert(err, IsNil)
+
+	req, _ := s.simulateRequest(c, reqChan, mgr, &listener.Request{}, false)
+
+	policy := &requestrules.Policy{
+		Version: requestrules.PolicyVersion,
+		Rules: []*requestrules.PolicyRule{
+			{
+				Snap:        "firefox",
+				Interface:   "home",
+				PathPattern: "~/**",
+				Permissions: []string{"read"},
+				Outcome:     prompting.OutcomeAllow,
+			},
+		},
+	}
+	_, _, err = mgr.ImportRules(s.defaultUser, policy, false)
+	c.Assert(err, IsNil)
+
+	resp, err := waitForReply(replyChan)
+	c.Assert(err, IsNil)
+	c.Check(resp.Request, Equals, req)
+	expected, err := prompting.AbstractPermissionsToAppArmorPermissions("home", []string{"read"})
+	c.Assert(err, IsNil)
+	c.Check(resp.AllowedPermission, DeepEquals, expected)
+
+	prompts, err := mgr.Prompts(s.defaultUser)
+	c.Check(err, IsNil)
+	c.Check(prompts, HasLen, 0)
+
+	c.Assert(mgr.Stop(), IsNil)
+}
+
+func (s *apparmorpromptingSuite) setDefaultPolicy(c *C, pathPattern string) {
+	s.st.Lock()
+	defer s.st.Unlock()
+	tr := config.NewTransaction(s.st)
+	c.Assert(tr.Set("core", "prompting.default-policy", map[string]any{
+		"version": 1,
+		"rules": []any{
+			map[string]any{
+				"snap":         "firefox",
+				"interface":    "home",
+				"path-pattern": pathPattern,
+				"permissions":  []any{"read"},
+				"outcome":      "allow",
+			},
+		},
+	}), IsNil)
+	tr.Commit()
+}
+
+func (s *apparmorpromptingSuite) checkDefaultPolicyRules(c *C, mgr *apparmorprompting.InterfacesRequestsManager, userID uint32, pathPatterns ...string) {
+	// Check the rule database directly, since listing rules through the
+	// manager applies the default policy to the user.
+	rules := mgr.RuleDB().Rules(userID)
+	c.Assert(rules, HasLen, len(pathPatterns))
+	for i, rule := range rules {
+		c.Check(rule.Snap, Equals, "firefox")
+		c.Check(rule.Constraints.PathPattern.String(), Equals, pathPatterns[i])
+	}
+}
+
+func (s *apparmorpromptingSuite) TestDefaultPolicyAppliedOnStart(c *C) {
+	_, _, restore := apparmorprompting.MockListener()
+	defer restore()
+	restore = apparmorprompting.MockAllUsers(func(opts *dirs.SnapDirOptions) ([]*user.User, error) {
+		return []*user.User{
+			{Uid: "0", HomeDir: "/root"},
+			{Uid: "1000", HomeDir: "/home/test"},
+		}, nil
+	})
+	defer restore()
+
+	s.setDefaultPolicy(c, "~/**")
+
+	mgr, err := apparmorprompting.New(s.st)
+	c.Assert(err, IsNil)
+
+	// Users who ran snaps are provisioned without making any request
+	s.checkDefaultPolicyRules(c, mgr, s.defaultUser, "/home/test/**")
+	// Other users are not
+	s.checkDefaultPolicyRules(c, mgr, s.defaultUser+1)
+	c.Assert(mgr.Stop(), IsNil)
+
+	// The users to which the policy was applied are recorded, so that they
+	// are provisioned on start even if they are not found otherwise
+	restore = apparmorprompting.MockAllUsers(func(opts *dirs.SnapDirOptions) ([]*user.User, error) {
+		return nil, fmt.Errorf("cannot list users")
+	})
+	defer restore()
+	_, _, restore = apparmorprompting.MockListener()
+	defer restore()
+	s.setDefaultPolicy(c, "~/foo/**")
+
+	mgr, err = apparmorprompting.New(s.st)
+	c.Assert(err, IsNil)
+	s.checkDefaultPolicyRules(c, mgr, s.defaultUser, "/home/test/**", "/home/test/foo/**")
+	c.Assert(mgr.Stop(), IsNil)
+}
+
+func (s *apparmorpromptingSuite) TestDefaultPolicyAppliedOnRequest(c *C) {
+	reqChan, replyChan, restore := apparmorprompting.MockListener()
+	defer restore()
+
+	s.setDefaultPolicy(c, "~/**")
+
+	mgr, err := apparmorprompting.New(s.st)
+	c.Assert(err, IsNil)
+
+	// Users who are not known yet are provisioned when they make a request
+	s.checkDefaultPolicyRules(c, mgr, s.defaultUser)
+
+	req := &listener.Request{}
+	s.fillInPartialRequest(req)
+	reqChan <- req
+
+	resp, err := waitForReply(replyChan)
+	c.Assert(err, IsNil)
+	c.Check(resp.Request, Equals, req)
+	expected, err := prompting.AbstractPermissionsToAppArmorPermissions("home", []string{"read"})
+	c.Assert(err, IsNil)
+	c.Check(resp.AllowedPermission, DeepEquals, expected)
+
+	s.checkDefaultPolicyRules(c, mgr, s.defaultUser, "/home/test/**")
+	// Rules of other users are not affected
+	s.checkDefaultPolicyRules(c, mgr, s.defaultUser+1)
+
+	// or when they list their rules
+	rules, err := mgr.Rules(s.defaultUser+1, "", "")
+	c.Assert(err, IsNil)
+	c.Assert(rules, HasLen, 1)
+	c.Check(rules[0].Constraints.PathPattern.String(), Equals, "/home/other/**")
+
+	// A default rule which the user removed is not provisioned again
+	_, err = mgr.RemoveRules(s.defaultUser, "firefox", "")
+	c.Assert(err, IsNil)
+	s.simulateRequest(c, reqChan, mgr, &listener.Request{}, false)
+	s.checkDefaultPolicyRules(c, mgr, s.defaultUser)
+
+	c.Assert(mgr.Stop(), IsNil)
+}
+
+func (s *apparmorpromptingSuite) TestDefaultPolicyAppliedOnChange(c *C) {
+	_, _, restore := apparmorprompting.MockListener()
+	defer restore()
+	restore = apparmorprompting.MockAllUsers(func(opts *dirs.SnapDirOptions) ([]*user.User, error) {
+		return []*user.User{{Uid: "1000", HomeDir: "/home/test"}}, nil
+	})
+	defer restore()
+
+	mgr, err := apparmorprompting.New(s.st)
+	c.Assert(err, IsNil)
+	s.checkDefaultPolicyRules(c, mgr, s.defaultUser)
+
+	s.setDefaultPolicy(c, "~/**")
+	c.Assert(mgr.EnsureDefaultPolicy(), IsNil)
+	s.checkDefaultPolicyRules(c, mgr, s.defaultUser, "/home/test/**")
+
+	// While the policy is unchanged, removed rules are not provisioned again
+	_, err = mgr.RemoveRules(s.defaultUser, "firefox", "")
+	c.Assert(err, IsNil)
+	c.Assert(mgr.EnsureDefaultPolicy(), IsNil)
+	s.checkDefaultPolicyRules(c, mgr, s.defaultUser)
+
+	// Unless the policy changes
+	s.setDefaultPolicy(c, "~/foo/**")
+	c.Assert(mgr.EnsureDefaultPolicy(), IsNil)
+	s.checkDefaultPolicyRules(c, mgr, s.defaultUser, "/home/test/foo/**")
+
+	// Invalid policies are reported
+	s.st.Lock()
+	tr := config.NewTransaction(s.st)
+	c.Assert(tr.Set("core", "prompting.default-policy", map[string]any{"version": "foo"}), IsNil)
+	tr.Commit()
+	s.st.Unlock()
+	c.Check(mgr.EnsureDefaultPolicy(), NotNil)
+
+	c.Assert(mgr.Stop(), IsNil)
+}
+
 func (s *apparmorpromptingSuite) prepManagerWithRules(c *C) (mgr *apparmorprompting.InterfacesRequestsManager, rules []*requestrules.Rule) {
 	var err error
 	mgr, err = apparmorprompting.New(s.st)
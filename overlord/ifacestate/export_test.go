@@ -132,6 +132,10 @@ func MockCreateInterfacesRequestsManager(new func(s *state.State) (*apparmorprom
 	return testutil.Mock(&createInterfacesRequestsManager, new)
 }
 
+func MockInterfacesRequestsManagerEnsure(new func(m *apparmorprompting.InterfacesRequestsManager) error) (restore func()) {
+	return testutil.Mock(&interfacesRequestsManagerEnsure, new)
+}
+
 func MockInterfacesRequestsManagerStop(new func(m *apparmorprompting.InterfacesRequestsManager) error) (restore func()) {
 	return testutil.Mock(&interfacesRequestsManagerStop, new)
 }
@@ -1056,6 +1056,159 @@ func (m *InterfaceManager) undoConnect(task *state.Task, _ *tomb.Tomb) error {
 	return nil
 }
 
+func (m *InterfaceManager) doRefreshConnection(task *state.Task, _ *tomb.Tomb) (err error) {
+	st := task.State()
+	st.Lock()
+	defer st.Unlock()
+
+	perfTimings := state.TimingsForTask(task)
+	defer perfTimings.Save(st)
+
+	plugRef, slotRef, err := getPlugAndSlotRefs(task)
+	if err != nil {
+		return err
+	}
+	connRef := &interfaces.ConnRef{PlugRef: plugRef, SlotRef: slotRef}
+
+	conns, err := getConns(st)
+	if err != nil {
+		return err
+	}
+	old, ok := conns[connRef.ID()]
+	if !ok || old.Undesired || old.HotplugGone {
+		return fmt.Errorf("cannot refresh connection %q: not connected", connRef.ID())
+	}
+
+	deviceCtx, err := snapstate.DeviceCtx(st, task, nil)
+	if err != nil {
+		return err
+	}
+
+	plugDynamicAttrs, slotDynamicAttrs, err := getDynamicHookAttributes(task)
+	if err != nil {
+		return fmt.Errorf("failed to get hook attributes: %s", err)
+	}
+
+	// the connection is subject to the same policy rules that allowed it
+	// to be established in the first place
+	var policyChecker interfaces.PolicyFunc
+	if old.Auto && !old.ByGadget {
+		autochecker, err := newAutoConnectChecker(st, m.repo, deviceCtx)
+		if err != nil {
+			return err
+		}
+		policyChecker = func(plug *interfaces.ConnectedPlug, slot *interfaces.ConnectedSlot) (bool, error) {
+			ok, _, err := autochecker.check(plug, slot)
+			return ok, err
+		}
+	} else {
+		policyCheck, err := newConnectChecker(st, deviceCtx)
+		if err != nil {
+			return err
+		}
+		policyChecker = policyCheck.check
+	}
+
+	// Connect replaces the connection only once the new attributes were
+	// validated and allowed by policy, otherwise the old one is left
+	// untouched.
+	conn, err := m.repo.Connect(connRef, old.StaticPlugAttrs, plugDynamicAttrs, old.StaticSlotAttrs, slotDynamicAttrs, policyChecker)
+	if err != nil {
+		return err
+	}
+	if conn == nil {
+		return fmt.Errorf("cannot refresh connection %q: new attributes are not allowed by auto-connection rules", connRef.ID())
+	}
+	defer func() {
+		if err != nil {
+			if _, err := m.repo.Connect(connRef, old.StaticPlugAttrs, old.DynamicPlugAttrs, old.StaticSlotAttrs, old.DynamicSlotAttrs, nil); err != nil {
+				logger.Noticef("cannot undo failed connection refresh: %v", err)
+			}
+		}
+	}()
+
+	if err := m.setupConnectionSecurity(task, connRef, perfTimings); err != nil {
+		return err
+	}
+
+	task.Set("old-conn", old)
+
+	updated := *old
+	updated.DynamicPlugAttrs = conn.Plug.DynamicAttrs()
+	updated.DynamicSlotAttrs = conn.Slot.DynamicAttrs()
+	conns[connRef.ID()] = &updated
+	setConns(st, conns)
+
+	// as with connect, the interface might have updated the dynamic
+	// attributes, make the new values visible to connect- hooks
+	setDynamicHookAttributes(task, conn.Plug.DynamicAttrs(), conn.Slot.DynamicAttrs())
+	return nil
+}
+
+func (m *InterfaceManager) undoRefreshConnection(task *state.Task, _ *tomb.Tomb) error {
+	st := task.State()
+	st.Lock()
+	defer st.Unlock()
+
+	perfTimings := state.TimingsForTask(task)
+	defer perfTimings.Save(st)
+
+	plugRef, slotRef, err := getPlugAndSlotRefs(task)
+	if err != nil {
+		return err
+	}
+	connRef := &interfaces.ConnRef{PlugRef: plugRef, SlotRef: slotRef}
+
+	var old schema.ConnState
+	if err := task.Get("old-conn", &old); err != nil {
+		return fmt.Errorf("internal error: cannot get old connection state: %v", err)
+	}
+
+	conns, err := getConns(st)
+	if err != nil {
+		return err
+	}
+	conns[connRef.ID()] = &old
+	setConns(st, conns)
+
+	if _, err := m.repo.Connect(connRef, old.StaticPlugAttrs, old.DynamicPlugAttrs, old.StaticSlotAttrs, old.DynamicSlotAttrs, nil); err != nil {
+		return err
+	}
+
+	return m.setupConnectionSecurity(task, connRef, perfTimings)
+}
+
+// setupConnectionSecurity regenerates the security profiles of the snaps
+// on both sides of the given connection.
+func (m *InterfaceManager) setupConnectionSecurity(task *state.Task, connRef *interfaces.ConnRef, tm timings.Measurer) error {
+	st := task.State()
+	for _, instanceName := range []string{connRef.SlotRef.Snap, connRef.PlugRef.Snap} {
+		var snapst snapstate.SnapState
+		if err := snapstate.Get(st, instanceName, &snapst); err != nil {
+			return err
+		}
+		snapInfo, err := snapst.CurrentInfo()
+		if err != nil {
+			return err
+		}
+		appSet, err := appSetForSnapRevision(st, snapInfo)
+		if err != nil {
+			return fmt.Errorf("building app set for snap %q: %v", instanceName, err)
+		}
+		opts, err := m.buildConfinementOptions(st, snapInfo, snapst.Flags)
+		if err != nil {
+			return err
+		}
+		if err := m.setupSnapSecurity(task, appSet, opts, tm); err != nil {
+			return err
+		}
+		if connRef.SlotRef.Snap == connRef.PlugRef.Snap {
+			break
+		}
+	}
+	return nil
+}
+
 // timeout for shared content retry
 var contentLinkRetryTimeout = 30 * time.Second
 
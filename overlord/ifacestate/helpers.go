@@ -867,6 +867,40 @@ func filterUbuntuCoreSlots(candidates []*snap.SlotInfo, arities []interfaces.Sid
 	return candidates, arities
 }
 
+// applicableSlots returns the slots the given plug would be auto-connected
+// to, after filtering them with the optional filter, together with all the
+// candidate slots that were considered.
+func (c *autoConnectChecker) applicableSlots(plug *snap.PlugInfo, filter func([]*snap.SlotInfo) []*snap.SlotInfo) (applicable, candSlots []*snap.SlotInfo) {
+	candSlots, arities := c.repo.AutoConnectCandidateSlots(plug.Snap.InstanceName(), plug.Name, c.check)
+	if len(candSlots) == 0 {
+		return nil, nil
+	}
+
+	// If we are in a core transition we may have both the
+	// old ubuntu-core snap and the new core snap
+	// providing the same interface. In that situation we
+	// want to ignore any candidates in ubuntu-core and
+	// simply go with those from the new core snap.
+	candSlots, arities = filterUbuntuCoreSlots(candSlots, arities)
+
+	applicable = candSlots
+	// candidate arity check
+	for _, arity := range arities {
+		if !arity.SlotsPerPlugAny() {
+			// ATM not any (*) => none or exactly one
+			if len(candSlots) != 1 {
+				applicable = nil
+			}
+			break
+		}
+	}
+
+	if filter != nil {
+		applicable = filter(applicable)
+	}
+	return applicable, candSlots
+}
+
 // addAutoConnections adds to newconns any applicable auto-connections
 // from the given plugs to corresponding candidates slots after
 // filtering them with optional filter and against preexisting
@@ -875,35 +909,11 @@ func filterUbuntuCoreSlots(candidates []*snap.SlotInfo, arities []interfaces.Sid
 // to handle checkAutoconnectConflicts errors.
 func (c *autoConnectChecker) addAutoConnections(task *state.Task, newconns map[string]*interfaces.ConnRef, plugs []*snap.PlugInfo, filter func([]*snap.SlotInfo) []*snap.SlotInfo, conns map[string]*schema.ConnState, cannotAutoConnectLog func(plug *snap.PlugInfo, candRefs []string) string, conflictError func(*state.Retry, error) error) error {
 	for _, plug := range plugs {
-		candSlots, arities := c.repo.AutoConnectCandidateSlots(plug.Snap.InstanceName(), plug.Name, c.check)
-
+		applicable, candSlots := c.applicableSlots(plug, filter)
 		if len(candSlots) == 0 {
 			continue
 		}
 
-		// If we are in a core transition we may have both the
-		// old ubuntu-core snap and the new core snap
-		// providing the same interface. In that situation we
-		// want to ignore any candidates in ubuntu-core and
-		// simply go with those from the new core snap.
-		candSlots, arities = filterUbuntuCoreSlots(candSlots, arities)
-
-		applicable := candSlots
-		// candidate arity check
-		for _, arity := range arities {
-			if !arity.SlotsPerPlugAny() {
-				// ATM not any (*) => none or exactly one
-				if len(candSlots) != 1 {
-					applicable = nil
-				}
-				break
-			}
-		}
-
-		if filter != nil {
-			applicable = filter(applicable)
-		}
-
 		if len(applicable) == 0 {
 			crefs := make([]string, len(candSlots))
 			for i, candidate := range candSlots {
@@ -103,6 +103,7 @@ func Manager(s *state.State, hookManager *hookstate.HookManager, runner *state.T
 
 	addHandler("connect", m.doConnect, m.undoConnect)
 	addHandler("disconnect", m.doDisconnect, m.undoDisconnect)
+	addHandler("refresh-connection", m.doRefreshConnection, m.undoRefreshConnection)
 	addHandler("setup-profiles", m.doSetupProfiles, m.undoSetupProfiles)
 	addHandler("remove-profiles", m.doRemoveProfiles, m.doSetupProfiles)
 	addHandler("discard-conns", m.doDiscardConns, m.undoDiscardConns)
@@ -204,6 +205,10 @@ func (m *InterfaceManager) StartUp() error {
 		return err
 	}
 
+	// The interfaces requests manager looks up the connections of snaps
+	// in the repository, so it must be available before it is started.
+	ifacerepo.Replace(s, m.repo)
+
 	if m.useAppArmorPrompting {
 		func() {
 			// Must not hold state lock while starting interfaces requests
@@ -232,8 +237,6 @@ func (m *InterfaceManager) StartUp() error {
 Run "systemctl enable --now snapd.apparmor" to correct this.`)
 	}
 
-	ifacerepo.Replace(s, m.repo)
-
 	// wire late profile removal support into snapstate
 	snapstate.SecurityProfilesRemoveLate = m.discardSecurityProfilesLate
 
@@ -244,6 +247,8 @@ Run "systemctl enable --now snapd.apparmor" to correct this.`)
 
 // Ensure implements StateManager.Ensure.
 func (m *InterfaceManager) Ensure() error {
+	m.ensureInterfacesRequestsManager()
+
 	// do not worry about udev monitor in preseeding mode
 	if m.preseed {
 		return nil
@@ -309,6 +314,24 @@ var interfacesRequestsManagerStop = func(interfacesRequestsManager *apparmorprom
 	return interfacesRequestsManager.Stop()
 }
 
+// interfacesRequestsManagerEnsure lets the given manager pick up changes to
+// the system configuration. The state lock must not be held while this
+// function is called.
+var interfacesRequestsManagerEnsure = func(interfacesRequestsManager *apparmorprompting.InterfacesRequestsManager) error {
+	return interfacesRequestsManager.EnsureConfig()
+}
+
+func (m *InterfaceManager) ensureInterfacesRequestsManager() {
+	m.interfacesRequestsManagerMu.Lock()
+	defer m.interfacesRequestsManagerMu.Unlock()
+	if m.interfacesRequestsManager == nil {
+		return
+	}
+	if err := interfacesRequestsManagerEnsure(m.interfacesRequestsManager); err != nil {
+		logger.Noticef("Cannot apply prompting configuration: %s", err)
+	}
+}
+
 func (m *InterfaceManager) stopInterfacesRequestsManager() {
 	m.interfacesRequestsManagerMu.Lock()
 	defer m.interfacesRequestsManagerMu.Unlock()
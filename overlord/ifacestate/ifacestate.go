@@ -89,7 +89,13 @@ type connectOpts struct {
 
 // Connect returns a set of tasks for connecting an interface.
 func Connect(st *state.State, plugSnap, plugName, slotSnap, slotName string) (*state.TaskSet, error) {
-	if err := snapstate.CheckChangeConflictMany(st, []string{plugSnap, slotSnap}, ""); err != nil {
+	return ConnectFromChange(st, plugSnap, plugName, slotSnap, slotName, "")
+}
+
+// ConnectFromChange is like Connect but the tasks of the change with the
+// given ID, if any, do not count as conflicting.
+func ConnectFromChange(st *state.State, plugSnap, plugName, slotSnap, slotName string, fromChange string) (*state.TaskSet, error) {
+	if err := snapstate.CheckChangeConflictMany(st, []string{plugSnap, slotSnap}, fromChange); err != nil {
 		return nil, err
 	}
 
@@ -310,6 +316,118 @@ func initialConnectAttributes(st *state.State, plugSnapInfo *snap.Info, plugSnap
 	return plug.Attrs, slot.Attrs, nil
 }
 
+// RefreshConnection returns a set of tasks for updating the dynamic
+// attributes of an existing connection without disconnecting it. The given
+// plug and slot attributes are merged over the current dynamic attributes of
+// the connection, the interface hooks of both sides are run again and the
+// security profiles of both snaps are regenerated. A non-nil context makes
+// the tasks of the change running the context's hook not count as conflicts.
+func RefreshConnection(st *state.State, connRef *interfaces.ConnRef, plugAttrs, slotAttrs map[string]interface{}, context *hookstate.Context) (*state.TaskSet, error) {
+	plugSnap, plugName := connRef.PlugRef.Snap, connRef.PlugRef.Name
+	slotSnap, slotName := connRef.SlotRef.Snap, connRef.SlotRef.Name
+
+	var ignoreChangeID string
+	if context != nil {
+		ignoreChangeID = context.ChangeID()
+	}
+	if err := snapstate.CheckChangeConflictMany(st, []string{plugSnap, slotSnap}, ignoreChangeID); err != nil {
+		return nil, err
+	}
+
+	conns, err := getConns(st)
+	if err != nil {
+		return nil, err
+	}
+	conn, ok := conns[connRef.ID()]
+	if !ok || conn.Undesired || conn.HotplugGone {
+		return nil, fmt.Errorf("cannot refresh connection %q: not connected", connRef.ID())
+	}
+
+	plugDynamic, err := mergeDynamicAttributes(conn.StaticPlugAttrs, conn.DynamicPlugAttrs, plugAttrs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot refresh connection %q: plug %s", connRef.ID(), err)
+	}
+	slotDynamic, err := mergeDynamicAttributes(conn.StaticSlotAttrs, conn.DynamicSlotAttrs, slotAttrs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot refresh connection %q: slot %s", connRef.ID(), err)
+	}
+
+	plugSnapInfo, err := snapstate.CurrentInfo(st, plugSnap)
+	if err != nil {
+		return nil, err
+	}
+	slotSnapInfo, err := snapstate.CurrentInfo(st, slotSnap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a series of tasks mirroring the ones used when connecting,
+	// except that the hooks have no undo counterparts as the connection
+	// exists before and after the change:
+	//  - prepare-plug-<plug> hook
+	//  - prepare-slot-<slot> hook
+	//  - refresh-connection task
+	//  - connect-slot-<slot> hook
+	//  - connect-plug-<plug> hook
+	refreshConnection := st.NewTask("refresh-connection", fmt.Sprintf(i18n.G("Refresh connection of %s:%s to %s:%s"), plugSnap, plugName, slotSnap, slotName))
+	initialContext := map[string]interface{}{
+		"attrs-task": refreshConnection.ID(),
+	}
+
+	tasks := state.NewTaskSet()
+	var prev *state.Task
+	addTask := func(t *state.Task) {
+		if prev != nil {
+			t.WaitFor(prev)
+		}
+		tasks.AddTask(t)
+		prev = t
+	}
+	addHook := func(info *snap.Info, hookName string) {
+		if info.Hooks[hookName] == nil {
+			return
+		}
+		hookSetup := &hookstate.HookSetup{
+			Snap:     info.InstanceName(),
+			Hook:     hookName,
+			Optional: true,
+		}
+		summary := fmt.Sprintf(i18n.G("Run hook %s of snap %q"), hookSetup.Hook, hookSetup.Snap)
+		addTask(hookstate.HookTask(st, summary, hookSetup, initialContext))
+	}
+
+	addHook(plugSnapInfo, "prepare-plug-"+plugName)
+	addHook(slotSnapInfo, "prepare-slot-"+slotName)
+
+	refreshConnection.Set("slot", connRef.SlotRef)
+	refreshConnection.Set("plug", connRef.PlugRef)
+	refreshConnection.Set("plug-static", conn.StaticPlugAttrs)
+	refreshConnection.Set("slot-static", conn.StaticSlotAttrs)
+	setDynamicHookAttributes(refreshConnection, plugDynamic, slotDynamic)
+	addTask(refreshConnection)
+
+	addHook(slotSnapInfo, "connect-slot-"+slotName)
+	addHook(plugSnapInfo, "connect-plug-"+plugName)
+
+	return tasks, nil
+}
+
+// mergeDynamicAttributes returns a copy of the dynamic attributes with the
+// given top-level attributes set. Static attributes cannot be overwritten.
+func mergeDynamicAttributes(static, dynamic, attrs map[string]interface{}) (map[string]interface{}, error) {
+	merged := make(map[string]interface{}, len(dynamic)+len(attrs))
+	for k, v := range dynamic {
+		merged[k] = v
+	}
+	for k, v := range attrs {
+		if _, ok := static[k]; ok {
+			return nil, fmt.Errorf("attribute %q cannot be overwritten", k)
+		}
+		merged[k] = v
+	}
+	return merged, nil
+}
+
 // Disconnect returns a set of tasks for disconnecting an interface.
 func Disconnect(st *state.State, conn *interfaces.Connection) (*state.TaskSet, error) {
 	plugSnap := conn.Plug.Snap().InstanceName()
@@ -522,6 +640,74 @@ func CheckInterfaces(st *state.State, snapInfo *snap.Info, deviceCtx snapstate.D
 	return ic.Check()
 }
 
+// ExplainConnection evaluates the connection of the given plug to the given
+// slot, or its auto-connection if auto is set, against the base declaration
+// and the snap declarations of their snaps and describes which rule decided
+// the outcome. Snaps without a snap-id are considered unasserted and have
+// no snap declaration.
+func ExplainConnection(st *state.State, plug *snap.PlugInfo, slot *snap.SlotInfo, auto bool) (*policy.ConnectionExplanation, error) {
+	deviceCtx, err := snapstate.DeviceCtx(st, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	modelAs := deviceCtx.Model()
+
+	var storeAs *asserts.Store
+	if modelAs.Store() != "" {
+		storeAs, err = assertstate.Store(st, modelAs.Store())
+		if err != nil && !errors.Is(err, &asserts.NotFoundError{}) {
+			return nil, err
+		}
+	}
+
+	baseDecl, err := assertstate.BaseDeclaration(st)
+	if err != nil {
+		return nil, fmt.Errorf("internal error: cannot find base declaration: %v", err)
+	}
+
+	snapDecl := func(info *snap.Info) (*asserts.SnapDeclaration, error) {
+		if info.SnapID == "" {
+			return nil, nil
+		}
+		snapDecl, err := assertstate.SnapDeclaration(st, info.SnapID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot find snap declaration for %q: %v", info.InstanceName(), err)
+		}
+		return snapDecl, nil
+	}
+	plugDecl, err := snapDecl(plug.Snap)
+	if err != nil {
+		return nil, err
+	}
+	slotDecl, err := snapDecl(slot.Snap)
+	if err != nil {
+		return nil, err
+	}
+
+	plugAppSet, err := interfaces.NewSnapAppSet(plug.Snap, nil)
+	if err != nil {
+		return nil, err
+	}
+	slotAppSet, err := interfaces.NewSnapAppSet(slot.Snap, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ic := policy.ConnectCandidate{
+		Plug:                interfaces.NewConnectedPlug(plug, plugAppSet, nil, nil),
+		PlugSnapDeclaration: plugDecl,
+		Slot:                interfaces.NewConnectedSlot(slot, slotAppSet, nil, nil),
+		SlotSnapDeclaration: slotDecl,
+		BaseDeclaration:     baseDecl,
+		Model:               modelAs,
+		Store:               storeAs,
+	}
+	if auto {
+		return ic.ExplainAutoConnect(), nil
+	}
+	return ic.Explain(), nil
+}
+
 var once sync.Once
 
 func delayedCrossMgrInit() {
@@ -535,6 +721,7 @@ func delayedCrossMgrInit() {
 		// hook into conflict checks mechanisms
 		snapstate.RegisterAffectedSnapsByKind("connect", connectDisconnectAffectedSnaps)
 		snapstate.RegisterAffectedSnapsByKind("disconnect", connectDisconnectAffectedSnaps)
+		snapstate.RegisterAffectedSnapsByKind("refresh-connection", connectDisconnectAffectedSnaps)
 
 		// hook into snap linking/unlinking and activation state changes
 		snapstate.AddLinkSnapParticipant(snapstate.LinkSnapParticipantFunc(OnSnapLinkageChanged))
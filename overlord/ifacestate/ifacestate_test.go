@@ -298,6 +298,7 @@ slots:
 	}))
 
 	s.BaseTest.AddCleanup(ifacestate.MockCreateInterfacesRequestsManager(fakeCreateInterfacesRequestsManager))
+	s.BaseTest.AddCleanup(ifacestate.MockInterfacesRequestsManagerEnsure(fakeInterfacesRequestsManagerEnsure))
 	s.BaseTest.AddCleanup(ifacestate.MockInterfacesRequestsManagerStop(fakeInterfacesRequestsManagerStop))
 }
 
@@ -305,6 +306,10 @@ var fakeCreateInterfacesRequestsManager = func(s *state.State) (*apparmorprompti
 	return nil, nil
 }
 
+var fakeInterfacesRequestsManagerEnsure = func(m *apparmorprompting.InterfacesRequestsManager) error {
+	return nil
+}
+
 var fakeInterfacesRequestsManagerStop = func(m *apparmorprompting.InterfacesRequestsManager) error {
 	return nil
 }
@@ -385,6 +390,17 @@ func (s *interfaceManagerSuite) TestSmokeAppArmorPromptingEnabled(c *C) {
 		return fakeManager, nil
 	})
 	defer restore()
+	ensureCount := 0
+	restore = ifacestate.MockInterfacesRequestsManagerEnsure(func(m *apparmorprompting.InterfacesRequestsManager) error {
+		ensureCount++
+		c.Check(m, Equals, fakeManager)
+		// InterfacesRequestsManager reads the system configuration while
+		// ensuring, so simulate it acquiring the state lock to do so.
+		s.state.Lock()
+		defer s.state.Unlock()
+		return nil
+	})
+	defer restore()
 	stopCount := 0
 	restore = ifacestate.MockInterfacesRequestsManagerStop(func(m *apparmorprompting.InterfacesRequestsManager) error {
 		stopCount++
@@ -400,6 +416,9 @@ func (s *interfaceManagerSuite) TestSmokeAppArmorPromptingEnabled(c *C) {
 	c.Check(createCount, Equals, 1)
 	c.Check(mgr.AppArmorPromptingRunning(), Equals, true)
 	c.Check(mgr.InterfacesRequestsManager(), Equals, fakeManager)
+	c.Check(ensureCount, Equals, 0)
+	c.Check(mgr.Ensure(), IsNil)
+	c.Check(ensureCount, Equals, 1)
 	c.Check(stopCount, Equals, 0)
 	mgr.Stop()
 	c.Check(stopCount, Equals, 1)
@@ -1920,6 +1939,198 @@ components:
 	})
 }
 
+func (s *interfaceManagerSuite) mockRefreshableConnection(c *C) map[string]interface{} {
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
+	s.mockSnap(c, consumerYaml)
+	s.mockSnap(c, producerYaml)
+
+	connState := map[string]interface{}{
+		"consumer:plug producer:slot": map[string]interface{}{
+			"interface":    "test",
+			"auto":         true,
+			"plug-static":  map[string]interface{}{"attr1": "value1"},
+			"plug-dynamic": map[string]interface{}{"dynamic": "plug-dynamic-value"},
+			"slot-static":  map[string]interface{}{"attr2": "value2"},
+			"slot-dynamic": map[string]interface{}{"dynamic": "slot-dynamic-value"},
+		},
+	}
+
+	s.state.Lock()
+	s.state.Set("conns", connState)
+	s.state.Unlock()
+
+	return connState
+}
+
+func (s *interfaceManagerSuite) TestRefreshConnectionTasks(c *C) {
+	s.mockRefreshableConnection(c)
+	_ = s.manager(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	connRef := &interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+		SlotRef: interfaces.SlotRef{Snap: "producer", Name: "slot"},
+	}
+	ts, err := ifacestate.RefreshConnection(s.state, connRef, map[string]interface{}{"new": "plug-new-value"}, nil, nil)
+	c.Assert(err, IsNil)
+
+	tasks := ts.Tasks()
+	waitChain := []string{
+		"hook:prepare-plug-plug",
+		"hook:prepare-slot-slot",
+		"task:refresh-connection",
+		"hook:connect-slot-slot",
+		"hook:connect-plug-plug",
+	}
+	c.Assert(tasks, HasLen, len(waitChain))
+	for i, t := range tasks {
+		c.Check(hookNameOrTaskKind(c, t), Equals, waitChain[i])
+		if i > 0 {
+			c.Check(t.WaitTasks(), DeepEquals, []*state.Task{tasks[i-1]})
+		}
+		if t.Kind() != "run-hook" {
+			continue
+		}
+		// the connection exists before and after the refresh, the
+		// hooks have nothing to undo
+		c.Check(t.Has("undo-hook-setup"), Equals, false)
+		var hookContext map[string]interface{}
+		c.Assert(t.Get("hook-context", &hookContext), IsNil)
+		c.Check(hookContext["attrs-task"], Equals, tasks[2].ID())
+	}
+
+	refresh := ts.Tasks()[2]
+	c.Check(refresh.Summary(), Equals, "Refresh connection of consumer:plug to producer:slot")
+	var plugDynamic, slotDynamic, plugStatic map[string]interface{}
+	c.Assert(refresh.Get("plug-dynamic", &plugDynamic), IsNil)
+	c.Assert(refresh.Get("slot-dynamic", &slotDynamic), IsNil)
+	c.Assert(refresh.Get("plug-static", &plugStatic), IsNil)
+	c.Check(plugDynamic, DeepEquals, map[string]interface{}{"dynamic": "plug-dynamic-value", "new": "plug-new-value"})
+	c.Check(slotDynamic, DeepEquals, map[string]interface{}{"dynamic": "slot-dynamic-value"})
+	c.Check(plugStatic, DeepEquals, map[string]interface{}{"attr1": "value1"})
+}
+
+func (s *interfaceManagerSuite) TestRefreshConnectionErrors(c *C) {
+	s.mockRefreshableConnection(c)
+	_ = s.manager(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	connRef := &interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+		SlotRef: interfaces.SlotRef{Snap: "producer", Name: "slot"},
+	}
+	_, err := ifacestate.RefreshConnection(s.state, connRef, map[string]interface{}{"attr1": "other"}, nil, nil)
+	c.Check(err, ErrorMatches, `cannot refresh connection "consumer:plug producer:slot": plug attribute "attr1" cannot be overwritten`)
+
+	_, err = ifacestate.RefreshConnection(s.state, connRef, nil, map[string]interface{}{"attr2": "other"}, nil)
+	c.Check(err, ErrorMatches, `cannot refresh connection "consumer:plug producer:slot": slot attribute "attr2" cannot be overwritten`)
+
+	otherRef := &interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "consumer", Name: "otherplug"},
+		SlotRef: interfaces.SlotRef{Snap: "producer", Name: "slot"},
+	}
+	_, err = ifacestate.RefreshConnection(s.state, otherRef, nil, nil, nil)
+	c.Check(err, ErrorMatches, `cannot refresh connection "consumer:otherplug producer:slot": not connected`)
+
+	chg := s.state.NewChange("other", "...")
+	t := s.state.NewTask("link-snap", "...")
+	t.Set("snap-setup", &snapstate.SnapSetup{SideInfo: &snap.SideInfo{RealName: "producer"}})
+	chg.AddTask(t)
+
+	_, err = ifacestate.RefreshConnection(s.state, connRef, nil, nil, nil)
+	c.Check(err, ErrorMatches, `snap "producer" has "other" change in progress`)
+}
+
+func (s *interfaceManagerSuite) TestRefreshConnection(c *C) {
+	s.MockModel(c, nil)
+	s.mockRefreshableConnection(c)
+	_ = s.manager(c)
+
+	s.state.Lock()
+	connRef := &interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+		SlotRef: interfaces.SlotRef{Snap: "producer", Name: "slot"},
+	}
+	ts, err := ifacestate.RefreshConnection(s.state, connRef, map[string]interface{}{"dynamic": "plug-new-value"}, map[string]interface{}{"new": "slot-new-value"}, nil)
+	c.Assert(err, IsNil)
+	change := s.state.NewChange("refresh-connection", "...")
+	change.AddAll(ts)
+	s.state.Unlock()
+
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Assert(change.Err(), IsNil)
+	c.Check(change.Status(), Equals, state.DoneStatus)
+
+	var conns map[string]interface{}
+	c.Assert(s.state.Get("conns", &conns), IsNil)
+	c.Check(conns, DeepEquals, map[string]interface{}{
+		"consumer:plug producer:slot": map[string]interface{}{
+			"interface":    "test",
+			"auto":         true,
+			"plug-static":  map[string]interface{}{"attr1": "value1"},
+			"plug-dynamic": map[string]interface{}{"dynamic": "plug-new-value"},
+			"slot-static":  map[string]interface{}{"attr2": "value2"},
+			"slot-dynamic": map[string]interface{}{"dynamic": "slot-dynamic-value", "new": "slot-new-value"},
+		},
+	})
+
+	conn := s.getConnection(c, "consumer", "plug", "producer", "slot")
+	c.Check(conn.Plug.DynamicAttrs(), DeepEquals, map[string]interface{}{"dynamic": "plug-new-value"})
+	c.Check(conn.Slot.DynamicAttrs(), DeepEquals, map[string]interface{}{"dynamic": "slot-dynamic-value", "new": "slot-new-value"})
+
+	// the profiles of both snaps were regenerated, nothing was removed
+	c.Assert(s.secBackend.SetupCalls, HasLen, 2)
+	c.Check(s.secBackend.RemoveCalls, HasLen, 0)
+	c.Check(s.secBackend.SetupCalls[0].AppSet.InstanceName(), Equals, "producer")
+	c.Check(s.secBackend.SetupCalls[1].AppSet.InstanceName(), Equals, "consumer")
+}
+
+func (s *interfaceManagerSuite) TestRefreshConnectionUndo(c *C) {
+	s.MockModel(c, nil)
+	connState := s.mockRefreshableConnection(c)
+	_ = s.manager(c)
+
+	s.state.Lock()
+	connRef := &interfaces.ConnRef{
+		PlugRef: interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+		SlotRef: interfaces.SlotRef{Snap: "producer", Name: "slot"},
+	}
+	ts, err := ifacestate.RefreshConnection(s.state, connRef, map[string]interface{}{"dynamic": "plug-new-value"}, nil, nil)
+	c.Assert(err, IsNil)
+	change := s.state.NewChange("refresh-connection", "...")
+	change.AddAll(ts)
+	terr := s.state.NewTask("error-trigger", "provoking total undo")
+	terr.WaitAll(ts)
+	change.AddTask(terr)
+	s.state.Unlock()
+
+	s.settle(c)
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	c.Check(change.Status(), Equals, state.ErrorStatus)
+	c.Check(ts.Tasks()[2].Status(), Equals, state.UndoneStatus)
+
+	var conns map[string]interface{}
+	c.Assert(s.state.Get("conns", &conns), IsNil)
+	c.Check(conns, DeepEquals, connState)
+
+	conn := s.getConnection(c, "consumer", "plug", "producer", "slot")
+	c.Check(conn.Plug.DynamicAttrs(), DeepEquals, map[string]interface{}{"dynamic": "plug-dynamic-value"})
+
+	// set up on do and again on undo
+	c.Check(s.secBackend.SetupCalls, HasLen, 4)
+}
+
 func (s *interfaceManagerSuite) TestForgetUndo(c *C) {
 	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
 
@@ -6173,6 +6384,223 @@ slots:
 	c.Check(ifacestate.CheckInterfaces(s.state, snapInfo, deviceCtx), ErrorMatches, `installation not allowed.*`)
 }
 
+func (s *interfaceManagerSuite) TestExplainConnection(c *C) {
+	restore := assertstest.MockBuiltinBaseDeclaration([]byte(`
+type: base-declaration
+authority-id: canonical
+series: 16
+slots:
+  test:
+    allow-auto-connection:
+      plug-attributes:
+        attr1: other
+`))
+	defer restore()
+	s.mockIface(&ifacetest.TestInterface{InterfaceName: "test"})
+
+	consumer := s.mockSnap(c, consumerYaml)
+	producer := s.mockSnap(c, producerYaml)
+
+	s.state.Lock()
+	// unasserted snaps are checked against the base declaration only
+	expl, err := ifacestate.ExplainConnection(s.state, consumer.Plugs["plug"], producer.Slots["slot"], true)
+	s.state.Unlock()
+	c.Assert(err, IsNil)
+	c.Check(expl.Allowed(), Equals, false)
+	c.Check(expl.Kind, Equals, "auto-connection")
+	c.Check(expl.Declaration, Equals, "base-declaration")
+	c.Check(expl.SnapName, Equals, "")
+	c.Check(expl.Side, Equals, "slot")
+	c.Check(expl.Constraint, Equals, "allow-auto-connection")
+	c.Check(expl.Alternative, Equals, -1)
+	c.Check(expl.Mismatch, Matches, `attribute "attr1" value "value1" does not match .*`)
+	c.Check(expl.Err, ErrorMatches, `auto-connection not allowed by slot rule of interface "test"`)
+
+	// a rule of the snap declaration takes precedence
+	s.MockSnapDecl(c, "producer", "producer-publisher", map[string]interface{}{
+		"format": "1",
+		"slots": map[string]interface{}{
+			"test": map[string]interface{}{
+				"deny-connection": "true",
+			},
+		},
+	})
+	producer = s.mockSnap(c, producerYaml)
+	c.Assert(producer.SnapID, Not(Equals), "")
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	expl, err = ifacestate.ExplainConnection(s.state, consumer.Plugs["plug"], producer.Slots["slot"], false)
+	c.Assert(err, IsNil)
+	c.Check(expl.Allowed(), Equals, false)
+	c.Check(expl.Kind, Equals, "connection")
+	c.Check(expl.Declaration, Equals, "snap-declaration")
+	c.Check(expl.SnapName, Equals, "producer")
+	c.Check(expl.Side, Equals, "slot")
+	c.Check(expl.Constraint, Equals, "deny-connection")
+	c.Check(expl.Alternative, Equals, 0)
+	c.Check(expl.Err, ErrorMatches, `connection denied by slot rule of interface "test" for "producer" snap`)
+
+	expl, err = ifacestate.ExplainConnection(s.state, consumer.Plugs["plug"], producer.Slots["slot"], true)
+	c.Assert(err, IsNil)
+	c.Check(expl.Allowed(), Equals, true)
+	c.Check(expl.Declaration, Equals, "snap-declaration")
+	c.Check(expl.Constraint, Equals, "allow-auto-connection")
+	c.Check(expl.Alternative, Equals, 0)
+}
+
+func (s *interfaceManagerSuite) TestPreviewProfiles(c *C) {
+	restore := assertstest.MockBuiltinBaseDeclaration([]byte(`
+type: base-declaration
+authority-id: canonical
+series: 16
+slots:
+  test:
+    allow-auto-connection: true
+`))
+	defer restore()
+	s.mockIface(&ifacetest.TestInterface{InterfaceName: "test"})
+	secBackend := &ifacetest.TestSecurityBackendPreview{
+		TestSecurityBackend: ifacetest.TestSecurityBackend{BackendName: "test"},
+		PreviewProfilesCallback: func(appSet *interfaces.SnapAppSet, opts interfaces.ConfinementOptions, repo *interfaces.Repository) (map[string][]byte, error) {
+			c.Check(appSet.InstanceName(), Equals, "consumer")
+			c.Check(opts.DevMode, Equals, true)
+			// the plug that would be auto-connected is connected
+			conns, err := repo.Connected("consumer", "plug")
+			c.Assert(err, IsNil)
+			c.Check(conns, DeepEquals, []*interfaces.ConnRef{{
+				PlugRef: interfaces.PlugRef{Snap: "consumer", Name: "plug"},
+				SlotRef: interfaces.SlotRef{Snap: "producer", Name: "slot"},
+			}})
+			return map[string][]byte{"/profiles/snap.consumer": []byte("profile")}, nil
+		},
+	}
+	s.mockSecBackend(secBackend)
+	s.mockSecBackend(&ifacetest.TestSecurityBackend{BackendName: "other"})
+	s.mockSnap(c, producerYaml)
+	mgr := s.manager(c)
+	secBackend.SetupCalls = nil
+
+	// the consumer snap is not installed
+	consumer := snaptest.MockInfo(c, consumerYaml+"confinement: devmode\n", &snap.SideInfo{Revision: snap.R(1)})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	preview, err := mgr.PreviewProfiles(consumer, "")
+	c.Assert(err, IsNil)
+	c.Check(preview, DeepEquals, map[string]map[string][]byte{
+		"test": {"/profiles/snap.consumer": []byte("profile")},
+	})
+
+	// nothing was set up and the repository is unchanged
+	c.Check(secBackend.SetupCalls, HasLen, 0)
+	c.Check(mgr.Repository().Plug("consumer", "plug"), IsNil)
+	c.Check(mgr.Repository().Interfaces().Connections, HasLen, 0)
+
+	preview, err = mgr.PreviewProfiles(consumer, "test")
+	c.Assert(err, IsNil)
+	c.Check(preview, HasLen, 1)
+
+	_, err = mgr.PreviewProfiles(consumer, "other")
+	c.Check(err, ErrorMatches, `cannot preview the profiles of security backend "other"`)
+	_, err = mgr.PreviewProfiles(consumer, "missing")
+	c.Check(err, ErrorMatches, `unknown security backend "missing"`)
+}
+
+var previewAutoConnectionsBaseDeclaration = []byte(`
+type: base-declaration
+authority-id: canonical
+series: 16
+slots:
+  test:
+    allow-auto-connection: true
+  test2:
+    deny-auto-connection: true
+`)
+
+func (s *interfaceManagerSuite) TestPreviewAutoConnections(c *C) {
+	restore := assertstest.MockBuiltinBaseDeclaration(previewAutoConnectionsBaseDeclaration)
+	defer restore()
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
+	s.mockSnap(c, producerYaml)
+	mgr := s.manager(c)
+
+	// the consumer snap is not installed
+	consumer := snaptest.MockInfo(c, consumerYaml, &snap.SideInfo{Revision: snap.R(1)})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	candidates, err := mgr.PreviewAutoConnections(consumer)
+	c.Assert(err, IsNil)
+	c.Assert(candidates, HasLen, 2)
+	c.Check(candidates[0].Plug.String(), Equals, "consumer:otherplug")
+	c.Check(candidates[0].Slot, IsNil)
+	c.Check(candidates[0].Explanation, IsNil)
+	c.Check(candidates[1].Plug.String(), Equals, "consumer:plug")
+	c.Check(candidates[1].Slot.String(), Equals, "producer:slot")
+	c.Check(candidates[1].Auto, Equals, true)
+	c.Check(candidates[1].Explanation.Allowed(), Equals, true)
+	c.Check(candidates[1].Explanation.Constraint, Equals, "allow-auto-connection")
+	c.Check(candidates[1].Note, Equals, "")
+
+	// the repository is unchanged
+	c.Check(mgr.Repository().Plug("consumer", "plug"), IsNil)
+}
+
+func (s *interfaceManagerSuite) TestPreviewAutoConnectionsManyCandidates(c *C) {
+	restore := assertstest.MockBuiltinBaseDeclaration(previewAutoConnectionsBaseDeclaration)
+	defer restore()
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
+	s.mockSnap(c, producerYaml)
+	s.mockSnap(c, strings.Replace(producerYaml, "name: producer", "name: producer2", 1))
+	mgr := s.manager(c)
+
+	consumer := snaptest.MockInfo(c, consumerYaml, &snap.SideInfo{Revision: snap.R(1)})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	// with more than one candidate slot the plug is not auto-connected
+	candidates, err := mgr.PreviewAutoConnections(consumer)
+	c.Assert(err, IsNil)
+	c.Assert(candidates, HasLen, 3)
+	c.Check(candidates[1].Slot.String(), Equals, "producer:slot")
+	c.Check(candidates[2].Slot.String(), Equals, "producer2:slot")
+	for _, cand := range candidates[1:] {
+		c.Check(cand.Plug.String(), Equals, "consumer:plug")
+		c.Check(cand.Auto, Equals, false)
+		c.Check(cand.Explanation.Allowed(), Equals, true)
+		c.Check(cand.Note, Equals, `plug "consumer:plug" has 2 candidate slots but can only be auto-connected to one`)
+	}
+}
+
+func (s *interfaceManagerSuite) TestPreviewAutoConnectionsSlots(c *C) {
+	restore := assertstest.MockBuiltinBaseDeclaration(previewAutoConnectionsBaseDeclaration)
+	defer restore()
+	s.mockIfaces(&ifacetest.TestInterface{InterfaceName: "test"}, &ifacetest.TestInterface{InterfaceName: "test2"})
+	s.mockSnap(c, producerYaml)
+	s.mockSnap(c, consumerYaml)
+	mgr := s.manager(c)
+
+	// a new revision of the producer snap replaces the installed one
+	producer := snaptest.MockInfo(c, strings.Replace(producerYaml, "interface: test", "interface: test2", 1), &snap.SideInfo{Revision: snap.R(2)})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	candidates, err := mgr.PreviewAutoConnections(producer)
+	c.Assert(err, IsNil)
+	c.Assert(candidates, HasLen, 1)
+	c.Check(candidates[0].Plug.String(), Equals, "consumer:otherplug")
+	c.Check(candidates[0].Slot.String(), Equals, "producer:slot")
+	c.Check(candidates[0].Auto, Equals, false)
+	c.Check(candidates[0].Explanation.Allowed(), Equals, false)
+	c.Check(candidates[0].Explanation.Constraint, Equals, "deny-auto-connection")
+}
+
 func (s *interfaceManagerSuite) TestCheckInterfacesConsidersImplicitSlots(c *C) {
 	deviceCtx := s.TrivialDeviceContext(c, nil)
 	snapInfo := s.mockSnap(c, ubuntuCoreSnapYaml)
@@ -0,0 +1,315 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ifacestate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/interfaces/policy"
+	"github.com/snapcore/snapd/interfaces/utils"
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// repositoryReplacing returns a copy of the interfaces repository in which
+// the given app set replaces any installed revision of its snap, without
+// any connection of the snap.
+func (m *InterfaceManager) repositoryReplacing(appSet *interfaces.SnapAppSet) (*interfaces.Repository, error) {
+	snapName := appSet.InstanceName()
+	repo := m.repo.Copy()
+	if _, err := repo.DisconnectSnap(snapName); err != nil {
+		return nil, err
+	}
+	if err := repo.RemoveSnap(snapName); err != nil {
+		return nil, err
+	}
+	if err := repo.AddAppSet(appSet); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// previewAppSet returns the app set of the given snap, which does not need
+// to be installed, with the implicit slots added if it is the system snap.
+func previewAppSet(st *state.State, info *snap.Info) (*interfaces.SnapAppSet, error) {
+	if err := addImplicitSlots(st, info); err != nil {
+		return nil, err
+	}
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(st, info.InstanceName(), &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	if snapst.IsInstalled() && snapst.LastIndex(info.Revision) >= 0 {
+		return appSetForSnapRevision(st, info)
+	}
+	return interfaces.NewSnapAppSet(info, nil)
+}
+
+// previewRepository returns a copy of the interfaces repository in which the
+// given app set replaces any installed revision of its snap, connected as it
+// would be once set up: the existing connections of the snap are restored
+// where possible and the ones that would be auto-connected on this device
+// are added.
+func (m *InterfaceManager) previewRepository(appSet *interfaces.SnapAppSet) (*interfaces.Repository, error) {
+	st := m.state
+	snapName := appSet.InstanceName()
+
+	repo, err := m.repositoryReplacing(appSet)
+	if err != nil {
+		return nil, err
+	}
+
+	conns, err := getConns(st)
+	if err != nil {
+		return nil, err
+	}
+	for connID, connState := range conns {
+		if connState.Undesired || connState.HotplugGone {
+			continue
+		}
+		connRef, err := interfaces.ParseConnRef(connID)
+		if err != nil {
+			return nil, err
+		}
+		if connRef.PlugRef.Snap != snapName && connRef.SlotRef.Snap != snapName {
+			continue
+		}
+		plug := repo.Plug(connRef.PlugRef.Snap, connRef.PlugRef.Name)
+		slot := repo.Slot(connRef.SlotRef.Snap, connRef.SlotRef.Name)
+		if plug == nil || slot == nil {
+			// the plug or slot does not exist in this revision
+			continue
+		}
+		staticPlugAttrs := utils.NormalizeInterfaceAttributes(plug.Attrs).(map[string]interface{})
+		staticSlotAttrs := utils.NormalizeInterfaceAttributes(slot.Attrs).(map[string]interface{})
+		if _, err := repo.Connect(connRef, staticPlugAttrs, connState.DynamicPlugAttrs, staticSlotAttrs, connState.DynamicSlotAttrs, nil); err != nil {
+			logger.Noticef("cannot preview connection %q: %v", connID, err)
+		}
+	}
+
+	deviceCtx, err := snapstate.DeviceCtx(st, nil, nil)
+	if errors.Is(err, state.ErrNoState) {
+		// no model means no auto-connections
+		return repo, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	autochecker, err := newAutoConnectChecker(st, repo, deviceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	var newconns []*interfaces.ConnRef
+	for _, plug := range repo.Plugs(snapName) {
+		slots, _ := autochecker.applicableSlots(plug, nil)
+		for _, slot := range slots {
+			newconns = append(newconns, interfaces.NewConnRef(plug, slot))
+		}
+	}
+	for _, slot := range repo.Slots(snapName) {
+		for _, plug := range repo.AutoConnectCandidatePlugs(snapName, slot.Name, autochecker.check) {
+			if slots, _ := autochecker.applicableSlots(plug, filterForSlot(slot)); len(slots) > 0 {
+				newconns = append(newconns, interfaces.NewConnRef(plug, slot))
+			}
+		}
+	}
+	// the policy was checked already, but the interfaces can still reject
+	// the connection
+	policyCheck := func(*interfaces.ConnectedPlug, *interfaces.ConnectedSlot) (bool, error) {
+		return true, nil
+	}
+	for _, connRef := range newconns {
+		if _, ok := conns[connRef.ID()]; ok {
+			// already restored above, or undesired
+			continue
+		}
+		plug := repo.Plug(connRef.PlugRef.Snap, connRef.PlugRef.Name)
+		slot := repo.Slot(connRef.SlotRef.Snap, connRef.SlotRef.Name)
+		staticPlugAttrs := utils.NormalizeInterfaceAttributes(plug.Attrs).(map[string]interface{})
+		staticSlotAttrs := utils.NormalizeInterfaceAttributes(slot.Attrs).(map[string]interface{})
+		if _, err := repo.Connect(connRef, staticPlugAttrs, nil, staticSlotAttrs, nil, policyCheck); err != nil {
+			logger.Noticef("cannot preview auto-connection %q: %v", connRef.ID(), err)
+		}
+	}
+	return repo, nil
+}
+
+// PreviewProfiles returns the security profiles and other artefacts that
+// would be set up for the given snap, indexed by security backend and by
+// path, without writing or loading any of them. The snap does not need to
+// be installed; if another revision of it is, the given one replaces it for
+// the preview. The connections of the snap that would be auto-connected on
+// this device are taken into account. If backend is not empty only the
+// security backend with that name is considered.
+//
+// The state must be locked by the caller.
+func (m *InterfaceManager) PreviewProfiles(info *snap.Info, backend string) (map[string]map[string][]byte, error) {
+	st := m.state
+
+	var previewers []interfaces.SecurityBackend
+	for _, b := range m.repo.Backends() {
+		if backend != "" && string(b.Name()) != backend {
+			continue
+		}
+		if _, ok := b.(interfaces.SecurityBackendPreview); !ok {
+			if backend != "" {
+				return nil, fmt.Errorf("cannot preview the profiles of security backend %q", backend)
+			}
+			continue
+		}
+		previewers = append(previewers, b)
+	}
+	if backend != "" && len(previewers) == 0 {
+		return nil, fmt.Errorf("unknown security backend %q", backend)
+	}
+
+	var snapst snapstate.SnapState
+	if err := snapstate.Get(st, info.InstanceName(), &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	flags := snapst.Flags
+	flags.Classic = flags.Classic || info.NeedsClassic()
+	flags.DevMode = flags.DevMode || info.NeedsDevMode()
+	opts, err := m.buildConfinementOptions(st, info, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	appSet, err := previewAppSet(st, info)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := m.previewRepository(appSet)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := make(map[string]map[string][]byte, len(previewers))
+	for _, b := range previewers {
+		profiles, err := b.(interfaces.SecurityBackendPreview).PreviewProfiles(appSet, opts, repo)
+		if err != nil {
+			return nil, err
+		}
+		preview[string(b.Name())] = profiles
+	}
+	return preview, nil
+}
+
+// AutoConnectCandidate is a plug and a slot, one of which belongs to a snap
+// whose auto-connections are previewed, with the same interface.
+type AutoConnectCandidate struct {
+	Plug *snap.PlugInfo
+	Slot *snap.SlotInfo
+	// Auto is set when the plug would be auto-connected to the slot.
+	Auto bool
+	// Explanation describes which rule of the declarations decides whether
+	// the plug can be auto-connected to the slot.
+	Explanation *policy.ConnectionExplanation
+	// Note explains why a plug whose auto-connection to the slot is
+	// allowed would still not be auto-connected.
+	Note string
+}
+
+// PreviewAutoConnections returns the candidates for the auto-connection of
+// the plugs and slots of the given snap on this device, as they would be
+// considered if the snap was installed: against the model, the base
+// declaration, the snap declarations found in the assertion database and
+// the plugs and slots of the installed snaps. The snap does not need to be
+// installed; if another revision of it is, the given one replaces it for
+// the preview. Plugs and slots without candidates are returned as
+// candidates with a nil slot or plug.
+//
+// The state must be locked by the caller.
+func (m *InterfaceManager) PreviewAutoConnections(info *snap.Info) ([]*AutoConnectCandidate, error) {
+	st := m.state
+	snapName := info.InstanceName()
+
+	deviceCtx, err := snapstate.DeviceCtx(st, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot preview auto-connections without a model: %v", err)
+	}
+	appSet, err := previewAppSet(st, info)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := m.repositoryReplacing(appSet)
+	if err != nil {
+		return nil, err
+	}
+	autochecker, err := newAutoConnectChecker(st, repo, deviceCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	candidate := func(plug *snap.PlugInfo, slot *snap.SlotInfo) (*AutoConnectCandidate, error) {
+		expl, err := ExplainConnection(st, plug, slot, true)
+		if err != nil {
+			return nil, err
+		}
+		cand := &AutoConnectCandidate{Plug: plug, Slot: slot, Explanation: expl}
+		applicable, candSlots := autochecker.applicableSlots(plug, filterForSlot(slot))
+		cand.Auto = len(applicable) > 0
+		if expl.Allowed() && !cand.Auto && len(candSlots) > 1 {
+			cand.Note = fmt.Sprintf("plug %q has %d candidate slots but can only be auto-connected to one", plug.String(), len(candSlots))
+		}
+		return cand, nil
+	}
+
+	var candidates []*AutoConnectCandidate
+	for _, plug := range repo.Plugs(snapName) {
+		slots := repo.AllSlots(plug.Interface)
+		if len(slots) == 0 {
+			candidates = append(candidates, &AutoConnectCandidate{Plug: plug})
+			continue
+		}
+		for _, slot := range slots {
+			cand, err := candidate(plug, slot)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, cand)
+		}
+	}
+	for _, slot := range repo.Slots(snapName) {
+		found := false
+		for _, plug := range repo.AllPlugs(slot.Interface) {
+			found = true
+			if plug.Snap.InstanceName() == snapName {
+				// considered along with the plugs of the snap
+				continue
+			}
+			cand, err := candidate(plug, slot)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, cand)
+		}
+		if !found {
+			candidates = append(candidates, &AutoConnectCandidate{Slot: slot})
+		}
+	}
+	return candidates, nil
+}
@@ -0,0 +1,202 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package manifeststate implements bringing a device to the state described
+// by a declarative manifest of its snaps.
+package manifeststate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/channel"
+	"github.com/snapcore/snapd/snap/naming"
+	"github.com/snapcore/snapd/strutil"
+)
+
+// Manifest describes the desired state of the snaps of a device. Only what
+// is mentioned in it is converged to, anything else is left untouched.
+type Manifest struct {
+	Snaps       map[string]*SnapManifest  `yaml:"snaps,omitempty" json:"snaps,omitempty"`
+	Connections []*Connection             `yaml:"connections,omitempty" json:"connections,omitempty"`
+	Quotas      map[string]*QuotaManifest `yaml:"quotas,omitempty" json:"quotas,omitempty"`
+}
+
+// SnapManifest describes the desired state of a single snap.
+type SnapManifest struct {
+	// Channel is the channel the snap should track, the snap is installed
+	// from the default channel if unset.
+	Channel string `yaml:"channel,omitempty" json:"channel,omitempty"`
+	// Revision pins the snap at the given revision, if set.
+	Revision snap.Revision `yaml:"revision,omitempty" json:"revision,omitempty"`
+	// Components are the components that should be installed.
+	Components []string `yaml:"components,omitempty" json:"components,omitempty"`
+	// Aliases maps aliases to the apps of the snap they should point to.
+	Aliases map[string]string `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+	// Config holds the configuration options of the snap, by top-level key.
+	Config map[string]interface{} `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
+// Connection describes a connection that should be established. Plug and
+// slot are given as <snap>:<name>, the snap of the slot can be omitted
+// for slots of the system.
+type Connection struct {
+	Plug string `yaml:"plug" json:"plug"`
+	Slot string `yaml:"slot" json:"slot"`
+}
+
+// QuotaManifest describes the desired state of a quota group.
+type QuotaManifest struct {
+	// Parent is the name of the parent group, it is only used when the
+	// group is created.
+	Parent string `yaml:"parent,omitempty" json:"parent,omitempty"`
+	// Memory is the memory limit of the group, as in 512MB.
+	Memory        string `yaml:"memory,omitempty" json:"memory,omitempty"`
+	CPUPercentage int    `yaml:"cpu-percentage,omitempty" json:"cpu-percentage,omitempty"`
+	Threads       int    `yaml:"threads,omitempty" json:"threads,omitempty"`
+	// Snaps are the snaps that should be in the group.
+	Snaps []string `yaml:"snaps,omitempty" json:"snaps,omitempty"`
+}
+
+// ParseManifest parses and validates a manifest in YAML format.
+func ParseManifest(data []byte) (*Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("cannot parse manifest: %v", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks that the manifest is consistent.
+func (m *Manifest) Validate() error {
+	for _, name := range m.SnapNames() {
+		if err := snap.ValidateInstanceName(name); err != nil {
+			return fmt.Errorf("invalid manifest: %v", err)
+		}
+		sm := m.Snaps[name]
+		if sm == nil {
+			return fmt.Errorf("invalid manifest: snap %q has no entry", name)
+		}
+		if sm.Channel != "" {
+			if _, err := channel.Full(sm.Channel); err != nil {
+				return fmt.Errorf("invalid manifest: snap %q: %v", name, err)
+			}
+		}
+		for _, comp := range sm.Components {
+			if err := naming.ValidateSnap(comp); err != nil {
+				return fmt.Errorf("invalid manifest: snap %q: invalid component name %q", name, comp)
+			}
+		}
+		for alias, app := range sm.Aliases {
+			if err := naming.ValidateAlias(alias); err != nil {
+				return fmt.Errorf("invalid manifest: snap %q: %v", name, err)
+			}
+			if err := naming.ValidateApp(app); err != nil {
+				return fmt.Errorf("invalid manifest: snap %q: %v", name, err)
+			}
+		}
+		for key := range sm.Config {
+			if key == "" {
+				return fmt.Errorf("invalid manifest: snap %q: empty configuration key", name)
+			}
+		}
+	}
+
+	for _, conn := range m.Connections {
+		if _, _, err := parsePlugOrSlot(conn.Plug, false); err != nil {
+			return fmt.Errorf("invalid manifest: %v", err)
+		}
+		if _, _, err := parsePlugOrSlot(conn.Slot, true); err != nil {
+			return fmt.Errorf("invalid manifest: %v", err)
+		}
+	}
+
+	for _, name := range m.quotaNames() {
+		if err := naming.ValidateQuotaGroup(name); err != nil {
+			return fmt.Errorf("invalid manifest: %v", err)
+		}
+		qm := m.Quotas[name]
+		if qm == nil {
+			return fmt.Errorf("invalid manifest: quota group %q has no entry", name)
+		}
+		if qm.Memory != "" {
+			if _, err := strutil.ParseByteSize(qm.Memory); err != nil {
+				return fmt.Errorf("invalid manifest: quota group %q: cannot parse memory limit: %v", name, err)
+			}
+		}
+		if qm.CPUPercentage < 0 || qm.Threads < 0 {
+			return fmt.Errorf("invalid manifest: quota group %q: limits cannot be negative", name)
+		}
+		for _, sn := range qm.Snaps {
+			if err := snap.ValidateInstanceName(sn); err != nil {
+				return fmt.Errorf("invalid manifest: quota group %q: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// SnapNames returns the sorted names of the snaps of the manifest.
+func (m *Manifest) SnapNames() []string {
+	names := make([]string, 0, len(m.Snaps))
+	for name := range m.Snaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *Manifest) quotaNames() []string {
+	names := make([]string, 0, len(m.Quotas))
+	for name := range m.Quotas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parsePlugOrSlot splits a plug or slot reference; the snap can only be
+// omitted for slots, meaning the system snap.
+func parsePlugOrSlot(ref string, slot bool) (snapName, name string, err error) {
+	kind := "plug"
+	if slot {
+		kind = "slot"
+	}
+	snapName, name, ok := strings.Cut(ref, ":")
+	if !ok || name == "" || (snapName == "" && !slot) {
+		return "", "", fmt.Errorf("invalid %s %q: expected <snap>:<name>", kind, ref)
+	}
+	if snapName != "" {
+		if err := snap.ValidateInstanceName(snapName); err != nil {
+			return "", "", fmt.Errorf("invalid %s %q: %v", kind, ref, err)
+		}
+	}
+	return snapName, name, nil
+}
@@ -0,0 +1,112 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package manifeststate_test
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/manifeststate"
+	"github.com/snapcore/snapd/snap"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type manifestSuite struct{}
+
+var _ = Suite(&manifestSuite{})
+
+func (s *manifestSuite) TestParseManifest(c *C) {
+	m, err := manifeststate.ParseManifest([]byte(`
+snaps:
+  foo:
+    channel: 2/stable
+    revision: 42
+    components: [comp1]
+    aliases:
+      foo-alias: app
+    config:
+      key: value
+      nested:
+        a: 1
+  bar_instance: {}
+connections:
+  - plug: foo:network
+    slot: :network
+quotas:
+  grp:
+    memory: 512MB
+    threads: 32
+    snaps: [foo]
+`))
+	c.Assert(err, IsNil)
+	c.Check(m.SnapNames(), DeepEquals, []string{"bar_instance", "foo"})
+	c.Check(m.Snaps["foo"], DeepEquals, &manifeststate.SnapManifest{
+		Channel:    "2/stable",
+		Revision:   snap.R(42),
+		Components: []string{"comp1"},
+		Aliases:    map[string]string{"foo-alias": "app"},
+		Config: map[string]interface{}{
+			"key":    "value",
+			"nested": map[string]interface{}{"a": 1},
+		},
+	})
+	c.Check(m.Connections, DeepEquals, []*manifeststate.Connection{
+		{Plug: "foo:network", Slot: ":network"},
+	})
+	c.Check(m.Quotas["grp"], DeepEquals, &manifeststate.QuotaManifest{
+		Memory:  "512MB",
+		Threads: 32,
+		Snaps:   []string{"foo"},
+	})
+}
+
+func (s *manifestSuite) TestParseManifestEmpty(c *C) {
+	m, err := manifeststate.ParseManifest(nil)
+	c.Assert(err, IsNil)
+	c.Check(m.SnapNames(), HasLen, 0)
+}
+
+func (s *manifestSuite) TestParseManifestErrors(c *C) {
+	for _, t := range []struct {
+		manifest string
+		err      string
+	}{
+		{"snaps: [foo]", `(?s)cannot parse manifest: .*cannot unmarshal !!seq.*`},
+		{"snap: {}", `(?s)cannot parse manifest: .*field snap not found.*`},
+		{"snaps: {foo: {colour: red}}", `(?s)cannot parse manifest: .*field colour not found.*`},
+		{"snaps: {Foo: {}}", `invalid manifest: invalid snap name: "Foo"`},
+		{"snaps: {foo: }", `invalid manifest: snap "foo" has no entry`},
+		{"snaps: {foo: {channel: a/b/c/d}}", `invalid manifest: snap "foo": .*`},
+		{"snaps: {foo: {components: [-comp]}}", `invalid manifest: snap "foo": invalid component name "-comp"`},
+		{"snaps: {foo: {aliases: {'.alias': app}}}", `invalid manifest: snap "foo": invalid alias name: ".alias"`},
+		{"snaps: {foo: {aliases: {alias: 'app!'}}}", `invalid manifest: snap "foo": invalid app name: "app!"`},
+		{"connections: [{plug: foo, slot: bar:slot}]", `invalid manifest: invalid plug "foo": expected <snap>:<name>`},
+		{"connections: [{plug: ':plug', slot: bar:slot}]", `invalid manifest: invalid plug ":plug": expected <snap>:<name>`},
+		{"connections: [{plug: foo:plug, slot: bar}]", `invalid manifest: invalid slot "bar": expected <snap>:<name>`},
+		{"quotas: {-grp: {}}", `invalid manifest: invalid quota group name: .*`},
+		{"quotas: {grp: {memory: lots}}", `invalid manifest: quota group "grp": cannot parse memory limit: .*`},
+		{"quotas: {grp: {threads: -1}}", `invalid manifest: quota group "grp": limits cannot be negative`},
+	} {
+		_, err := manifeststate.ParseManifest([]byte(t.manifest))
+		c.Check(err, ErrorMatches, t.err, Commentf("manifest: %s", t.manifest))
+	}
+}
@@ -0,0 +1,87 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package manifeststate
+
+import (
+	"gopkg.in/tomb.v2"
+
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+// ManifestManager is responsible for converging the device to manifests.
+type ManifestManager struct{}
+
+// Manager returns a new manifest manager.
+func Manager(st *state.State, runner *state.TaskRunner) *ManifestManager {
+	m := &ManifestManager{}
+
+	// the injected tasks take care of their own undo
+	runner.AddHandler("apply-manifest", m.doApplyManifest, nil)
+
+	return m
+}
+
+// Ensure implements StateManager.Ensure.
+func (m *ManifestManager) Ensure() error { return nil }
+
+// doApplyManifest sets up the operations of a manifest which can only be
+// carried out once its snaps are installed, by adding their tasks to the
+// change after the apply-manifest task itself. The operations are computed
+// afresh at this point, against the state as left by the snap operations.
+func (m *ManifestManager) doApplyManifest(t *state.Task, _ *tomb.Tomb) error {
+	st := t.State()
+	st.Lock()
+	defer st.Unlock()
+
+	var manifest Manifest
+	if err := t.Get("manifest", &manifest); err != nil {
+		return err
+	}
+
+	fus, err := computeFollowUps(st, &manifest)
+	if err != nil {
+		return err
+	}
+
+	// the snaps of the manifest were installed or refreshed by earlier
+	// tasks of this very change, which must not count as conflicting;
+	// all the task sets are also created before any is added to the change
+	// so that a failure leaves the change untouched
+	chgID := t.Change().ID()
+	tss := make([]*state.TaskSet, 0, len(fus))
+	for _, fu := range fus {
+		ts, err := fu.tasks(chgID)
+		if err != nil {
+			return err
+		}
+		tss = append(tss, ts)
+	}
+	all := state.NewTaskSet()
+	for i, ts := range tss {
+		if i > 0 {
+			ts.WaitAll(tss[i-1])
+		}
+		all.AddAll(ts)
+		t.Logf("%s", fus[i].op.Summary)
+	}
+	snapstate.InjectTasks(t, all)
+	return nil
+}
@@ -0,0 +1,505 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package manifeststate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/snapcore/snapd/gadget/quantity"
+	"github.com/snapcore/snapd/i18n"
+	"github.com/snapcore/snapd/interfaces"
+	"github.com/snapcore/snapd/jsonutil"
+	"github.com/snapcore/snapd/overlord/configstate"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/servicestate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/channel"
+	"github.com/snapcore/snapd/snap/naming"
+	"github.com/snapcore/snapd/snap/quota"
+	"github.com/snapcore/snapd/strutil"
+)
+
+// Kinds of operations needed to converge to a manifest.
+const (
+	OpInstall           = "install"
+	OpRefresh           = "refresh"
+	OpInstallComponents = "install-components"
+	OpAlias             = "alias"
+	OpConnect           = "connect"
+	OpConfigure         = "configure"
+	OpCreateQuota       = "create-quota"
+	OpUpdateQuota       = "update-quota"
+)
+
+// Operation is a single step needed to bring the device to the state
+// described by a manifest.
+type Operation struct {
+	Kind string `json:"kind"`
+	// Snap is the snap the operation is about, if any.
+	Snap    string `json:"snap,omitempty"`
+	Summary string `json:"summary"`
+}
+
+var (
+	snapstateInstallWithGoal   = snapstate.InstallWithGoal
+	snapstateUpdateWithGoal    = snapstate.UpdateWithGoal
+	snapstateInstallComponents = snapstate.InstallComponents
+)
+
+// snapOps holds the snap operations needed to converge to a manifest.
+type snapOps struct {
+	installs   []snapstate.StoreSnap
+	updates    []snapstate.StoreUpdate
+	components map[string][]string
+	ops        []*Operation
+}
+
+func sameChannel(a, b string) bool {
+	fullA, err := channel.Full(a)
+	if err != nil {
+		return false
+	}
+	fullB, err := channel.Full(b)
+	if err != nil {
+		return false
+	}
+	return fullA == fullB
+}
+
+func revisionOptionsSummary(sm *SnapManifest) string {
+	var parts []string
+	if sm.Channel != "" {
+		parts = append(parts, fmt.Sprintf(i18n.G("channel %q"), sm.Channel))
+	}
+	if !sm.Revision.Unset() {
+		parts = append(parts, fmt.Sprintf(i18n.G("revision %s"), sm.Revision))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func computeSnapOps(st *state.State, m *Manifest) (*snapOps, error) {
+	res := &snapOps{components: make(map[string][]string)}
+	for _, name := range m.SnapNames() {
+		sm := m.Snaps[name]
+		revOpts := snapstate.RevisionOptions{
+			Channel:  sm.Channel,
+			Revision: sm.Revision,
+		}
+
+		var snapst snapstate.SnapState
+		err := snapstate.Get(st, name, &snapst)
+		if err != nil && !errors.Is(err, state.ErrNoState) {
+			return nil, err
+		}
+		if !snapst.IsInstalled() {
+			res.installs = append(res.installs, snapstate.StoreSnap{
+				InstanceName: name,
+				Components:   sm.Components,
+				RevOpts:      revOpts,
+			})
+			summary := fmt.Sprintf(i18n.G("Install snap %q"), name)
+			if details := revisionOptionsSummary(sm); details != "" {
+				summary = fmt.Sprintf(i18n.G("Install snap %q (%s)"), name, details)
+			}
+			res.ops = append(res.ops, &Operation{Kind: OpInstall, Snap: name, Summary: summary})
+			if len(sm.Components) > 0 {
+				res.ops = append(res.ops, &Operation{
+					Kind:    OpInstallComponents,
+					Snap:    name,
+					Summary: fmt.Sprintf(i18n.G("Install components %s of snap %q"), strutil.Quoted(sm.Components), name),
+				})
+			}
+			continue
+		}
+
+		snapName, _ := snap.SplitInstanceName(name)
+		var missing []string
+		for _, comp := range sm.Components {
+			if !snapst.IsComponentInCurrentSeq(naming.NewComponentRef(snapName, comp)) {
+				missing = append(missing, comp)
+			}
+		}
+
+		refresh := (sm.Channel != "" && !sameChannel(sm.Channel, snapst.TrackingChannel)) ||
+			(!sm.Revision.Unset() && sm.Revision != snapst.Current)
+		if refresh {
+			res.updates = append(res.updates, snapstate.StoreUpdate{
+				InstanceName:         name,
+				RevOpts:              revOpts,
+				AdditionalComponents: missing,
+			})
+			res.ops = append(res.ops, &Operation{
+				Kind:    OpRefresh,
+				Snap:    name,
+				Summary: fmt.Sprintf(i18n.G("Refresh snap %q (%s)"), name, revisionOptionsSummary(sm)),
+			})
+		} else if len(missing) > 0 {
+			res.components[name] = missing
+		}
+		if len(missing) > 0 {
+			res.ops = append(res.ops, &Operation{
+				Kind:    OpInstallComponents,
+				Snap:    name,
+				Summary: fmt.Sprintf(i18n.G("Install components %s of snap %q"), strutil.Quoted(missing), name),
+			})
+		}
+	}
+	return res, nil
+}
+
+// followUp is an operation that can only be carried out once the snaps of
+// the manifest are installed.
+type followUp struct {
+	op *Operation
+	// tasks creates the task set carrying out the operation, as part of
+	// the change with the given ID.
+	tasks func(fromChange string) (*state.TaskSet, error)
+}
+
+// normalizeConfigValue returns the given value as it would be read back
+// from the configuration.
+func normalizeConfigValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var norm interface{}
+	if err := jsonutil.DecodeWithNumber(bytes.NewReader(data), &norm); err != nil {
+		return nil, err
+	}
+	return norm, nil
+}
+
+func snapFollowUps(st *state.State, name string, sm *SnapManifest) ([]*followUp, error) {
+	var snapst snapstate.SnapState
+	err := snapstate.Get(st, name, &snapst)
+	if err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	installed := snapst.IsInstalled()
+
+	var fus []*followUp
+
+	aliases := make([]string, 0, len(sm.Aliases))
+	for alias := range sm.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		app := sm.Aliases[alias]
+		if installed {
+			if target := snapst.Aliases[alias]; target != nil && target.Effective(snapst.AutoAliasesDisabled) == app {
+				continue
+			}
+		}
+		alias := alias
+		fus = append(fus, &followUp{
+			op: &Operation{
+				Kind:    OpAlias,
+				Snap:    name,
+				Summary: fmt.Sprintf(i18n.G("Set up alias %q for app %q"), alias, snap.JoinSnapApp(name, app)),
+			},
+			tasks: func(fromChange string) (*state.TaskSet, error) {
+				return snapstate.AliasFromChange(st, name, app, alias, fromChange)
+			},
+		})
+	}
+
+	patch := make(map[string]interface{})
+	var keys []string
+	tr := config.NewTransaction(st)
+	for key, value := range sm.Config {
+		want, err := normalizeConfigValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot use configuration option %q of snap %q: %v", key, name, err)
+		}
+		if installed {
+			var current interface{}
+			err := tr.Get(name, key, &current)
+			if err != nil && !config.IsNoOption(err) {
+				return nil, err
+			}
+			if err == nil && reflect.DeepEqual(current, want) {
+				continue
+			}
+		}
+		patch[key] = value
+		keys = append(keys, key)
+	}
+	if len(patch) > 0 {
+		sort.Strings(keys)
+		fus = append(fus, &followUp{
+			op: &Operation{
+				Kind:    OpConfigure,
+				Snap:    name,
+				Summary: fmt.Sprintf(i18n.G("Configure %s of snap %q"), strutil.Quoted(keys), name),
+			},
+			tasks: func(fromChange string) (*state.TaskSet, error) {
+				return configstate.ConfigureInstalledFromChange(st, name, patch, 0, fromChange)
+			},
+		})
+	}
+	return fus, nil
+}
+
+func connectionFollowUps(st *state.State, conns []*Connection) ([]*followUp, error) {
+	connStates, err := ifacestate.ConnectionStates(st)
+	if err != nil {
+		return nil, err
+	}
+	var fus []*followUp
+	for _, conn := range conns {
+		plugSnap, plugName, err := parsePlugOrSlot(conn.Plug, false)
+		if err != nil {
+			return nil, err
+		}
+		slotSnap, slotName, err := parsePlugOrSlot(conn.Slot, true)
+		if err != nil {
+			return nil, err
+		}
+		if slotSnap == "" || slotSnap == "system" {
+			slotSnap = ifacestate.SystemSnapName()
+		}
+		connRef := interfaces.ConnRef{
+			PlugRef: interfaces.PlugRef{Snap: plugSnap, Name: plugName},
+			SlotRef: interfaces.SlotRef{Snap: slotSnap, Name: slotName},
+		}
+		if cs, ok := connStates[connRef.ID()]; ok && cs.Active() {
+			continue
+		}
+		fus = append(fus, &followUp{
+			op: &Operation{
+				Kind:    OpConnect,
+				Snap:    plugSnap,
+				Summary: fmt.Sprintf(i18n.G("Connect %s:%s to %s:%s"), plugSnap, plugName, slotSnap, slotName),
+			},
+			tasks: func(fromChange string) (*state.TaskSet, error) {
+				return ifacestate.ConnectFromChange(st, plugSnap, plugName, slotSnap, slotName, fromChange)
+			},
+		})
+	}
+	return fus, nil
+}
+
+func quotaFollowUps(st *state.State, m *Manifest) ([]*followUp, error) {
+	var fus []*followUp
+	for _, name := range m.quotaNames() {
+		name := name
+		qm := m.Quotas[name]
+		var memory quantity.Size
+		if qm.Memory != "" {
+			size, err := strutil.ParseByteSize(qm.Memory)
+			if err != nil {
+				return nil, err
+			}
+			memory = quantity.Size(size)
+		}
+
+		grp, err := servicestate.GetQuota(st, name)
+		if err != nil && !errors.Is(err, servicestate.ErrQuotaNotFound) {
+			return nil, err
+		}
+		if errors.Is(err, servicestate.ErrQuotaNotFound) {
+			limits := quota.NewResourcesBuilder()
+			if memory != 0 {
+				limits.WithMemoryLimit(memory)
+			}
+			if qm.CPUPercentage != 0 {
+				limits.WithCPUPercentage(qm.CPUPercentage)
+			}
+			if qm.Threads != 0 {
+				limits.WithThreadLimit(qm.Threads)
+			}
+			opts := servicestate.CreateQuotaOptions{
+				ParentName:     qm.Parent,
+				Snaps:          qm.Snaps,
+				ResourceLimits: limits.Build(),
+			}
+			fus = append(fus, &followUp{
+				op: &Operation{
+					Kind:    OpCreateQuota,
+					Summary: fmt.Sprintf(i18n.G("Create quota group %q"), name),
+				},
+				tasks: func(fromChange string) (*state.TaskSet, error) {
+					opts.FromChange = fromChange
+					return servicestate.CreateQuota(st, name, opts)
+				},
+			})
+			continue
+		}
+
+		if qm.Parent != grp.ParentGroup {
+			return nil, fmt.Errorf("cannot move existing quota group %q to parent %q", name, qm.Parent)
+		}
+		var changed bool
+		limits := quota.NewResourcesBuilder()
+		if memory != 0 && memory != grp.MemoryLimit {
+			limits.WithMemoryLimit(memory)
+			changed = true
+		}
+		if qm.CPUPercentage != 0 && (grp.CPULimit == nil || grp.CPULimit.Percentage != qm.CPUPercentage) {
+			limits.WithCPUPercentage(qm.CPUPercentage)
+			changed = true
+		}
+		if qm.Threads != 0 && qm.Threads != grp.ThreadLimit {
+			limits.WithThreadLimit(qm.Threads)
+			changed = true
+		}
+		var addSnaps []string
+		for _, sn := range qm.Snaps {
+			if !strutil.ListContains(grp.Snaps, sn) {
+				addSnaps = append(addSnaps, sn)
+			}
+		}
+		if !changed && len(addSnaps) == 0 {
+			continue
+		}
+		opts := servicestate.UpdateQuotaOptions{
+			AddSnaps:          addSnaps,
+			NewResourceLimits: limits.Build(),
+		}
+		fus = append(fus, &followUp{
+			op: &Operation{
+				Kind:    OpUpdateQuota,
+				Summary: fmt.Sprintf(i18n.G("Update quota group %q"), name),
+			},
+			tasks: func(fromChange string) (*state.TaskSet, error) {
+				opts.FromChange = fromChange
+				return servicestate.UpdateQuota(st, name, opts)
+			},
+		})
+	}
+	return fus, nil
+}
+
+// computeFollowUps returns the operations, other than installing and
+// refreshing snaps, needed to converge to the manifest. Snaps which are not
+// installed are assumed to need all of their aliases and configuration.
+func computeFollowUps(st *state.State, m *Manifest) ([]*followUp, error) {
+	var fus []*followUp
+	for _, name := range m.SnapNames() {
+		snapFus, err := snapFollowUps(st, name, m.Snaps[name])
+		if err != nil {
+			return nil, err
+		}
+		fus = append(fus, snapFus...)
+	}
+	connFus, err := connectionFollowUps(st, m.Connections)
+	if err != nil {
+		return nil, err
+	}
+	fus = append(fus, connFus...)
+	quotaFus, err := quotaFollowUps(st, m)
+	if err != nil {
+		return nil, err
+	}
+	return append(fus, quotaFus...), nil
+}
+
+// Plan returns the operations needed to bring the device to the state
+// described by the manifest, in the order they would be carried out.
+// Note that the state must be locked by the caller.
+func Plan(st *state.State, m *Manifest) ([]*Operation, error) {
+	sops, err := computeSnapOps(st, m)
+	if err != nil {
+		return nil, err
+	}
+	fus, err := computeFollowUps(st, m)
+	if err != nil {
+		return nil, err
+	}
+	ops := sops.ops
+	for _, fu := range fus {
+		ops = append(ops, fu.op)
+	}
+	return ops, nil
+}
+
+// Apply returns the task sets which bring the device to the state described
+// by the manifest, together with the operations they carry out. Snaps are
+// installed and refreshed first, the remaining operations are then set up
+// by an apply-manifest task once the snaps are in place.
+// Note that the state must be locked by the caller.
+func Apply(ctx context.Context, st *state.State, m *Manifest, userID int) ([]*state.TaskSet, []*Operation, error) {
+	sops, err := computeSnapOps(st, m)
+	if err != nil {
+		return nil, nil, err
+	}
+	fus, err := computeFollowUps(st, m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := snapstate.Options{UserID: userID}
+	var tss []*state.TaskSet
+	if len(sops.installs) > 0 {
+		_, installTss, err := snapstateInstallWithGoal(ctx, st, snapstate.StoreInstallGoal(sops.installs...), opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		tss = append(tss, installTss...)
+	}
+	if len(sops.updates) > 0 {
+		_, uts, err := snapstateUpdateWithGoal(ctx, st, snapstate.StoreUpdateGoal(sops.updates...), nil, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		tss = append(tss, uts.Refresh...)
+	}
+	for _, name := range m.SnapNames() {
+		comps := sops.components[name]
+		if len(comps) == 0 {
+			continue
+		}
+		info, err := snapstate.CurrentInfo(st, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		compTss, err := snapstateInstallComponents(ctx, st, comps, info, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		tss = append(tss, compTss...)
+	}
+
+	ops := sops.ops
+	if len(fus) > 0 {
+		t := st.NewTask("apply-manifest", i18n.G("Apply connections, aliases, configuration and quotas of the manifest"))
+		t.Set("manifest", m)
+		for _, ts := range tss {
+			t.WaitAll(ts)
+		}
+		tss = append(tss, state.NewTaskSet(t))
+		for _, fu := range fus {
+			ops = append(ops, fu.op)
+		}
+	}
+	return tss, ops, nil
+}
@@ -0,0 +1,389 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package manifeststate_test
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/gadget/quantity"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/manifeststate"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/sequence"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/naming"
+	"github.com/snapcore/snapd/snap/quota"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type manifestStateSuite struct {
+	testutil.BaseTest
+	state *state.State
+	mgr   *manifeststate.ManifestManager
+}
+
+var _ = Suite(&manifestStateSuite{})
+
+const fooYaml = `name: foo
+version: 1
+apps:
+  app:
+    command: bin/app
+components:
+  comp1:
+    type: standard
+  comp2:
+    type: standard
+`
+
+// the device as described by convergedManifest
+const convergedManifest = `
+snaps:
+  foo:
+    channel: stable
+    components: [comp1]
+    aliases:
+      foo-alias: app
+    config:
+      key: value
+      nested:
+        a: 1
+connections:
+  - plug: foo:plug
+    slot: bar:slot
+quotas:
+  grp:
+    memory: 1GB
+    snaps: [foo]
+`
+
+func (s *manifestStateSuite) SetUpTest(c *C) {
+	s.BaseTest.SetUpTest(c)
+	dirs.SetRootDir(c.MkDir())
+	s.AddCleanup(func() { dirs.SetRootDir("") })
+
+	s.state = state.New(nil)
+	s.mgr = manifeststate.Manager(s.state, state.NewTaskRunner(s.state))
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	si := &snap.SideInfo{RealName: "foo", SnapID: "foo-id", Revision: snap.R(1)}
+	comp := sequence.NewComponentState(snap.NewComponentSideInfo(naming.NewComponentRef("foo", "comp1"), snap.R(3)), snap.StandardComponent)
+	snapstate.Set(s.state, "foo", &snapstate.SnapState{
+		Sequence:        snapstatetest.NewSequenceFromRevisionSideInfos([]*sequence.RevisionSideState{sequence.NewRevisionSideState(si, []*sequence.ComponentState{comp})}),
+		Current:         snap.R(1),
+		Active:          true,
+		SnapType:        "app",
+		TrackingChannel: "latest/stable",
+		Aliases: map[string]*snapstate.AliasTarget{
+			"foo-alias": {Manual: "app"},
+		},
+	})
+	snaptest.MockSnapCurrent(c, fooYaml, si)
+
+	tr := config.NewTransaction(s.state)
+	c.Assert(tr.Set("foo", "key", "value"), IsNil)
+	c.Assert(tr.Set("foo", "nested", map[string]interface{}{"a": 1}), IsNil)
+	tr.Commit()
+
+	s.state.Set("conns", map[string]interface{}{
+		"foo:plug bar:slot": map[string]interface{}{"interface": "iface"},
+	})
+	s.state.Set("quotas", map[string]*quota.Group{
+		"grp": {Name: "grp", MemoryLimit: quantity.Size(1000 * 1000 * 1000), Snaps: []string{"foo"}},
+	})
+}
+
+func (s *manifestStateSuite) parse(c *C, manifest string) *manifeststate.Manifest {
+	m, err := manifeststate.ParseManifest([]byte(manifest))
+	c.Assert(err, IsNil)
+	return m
+}
+
+func (s *manifestStateSuite) TestPlanConverged(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	ops, err := manifeststate.Plan(s.state, s.parse(c, convergedManifest))
+	c.Assert(err, IsNil)
+	c.Check(ops, HasLen, 0)
+}
+
+func (s *manifestStateSuite) TestPlanNotInstalled(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	ops, err := manifeststate.Plan(s.state, s.parse(c, `
+snaps:
+  baz:
+    channel: candidate
+    components: [comp]
+    aliases:
+      baz-alias: app
+    config:
+      key: value
+connections:
+  - plug: baz:plug
+    slot: foo:slot
+quotas:
+  new-grp:
+    memory: 1GB
+    snaps: [baz]
+`))
+	c.Assert(err, IsNil)
+	c.Check(ops, DeepEquals, []*manifeststate.Operation{
+		{Kind: manifeststate.OpInstall, Snap: "baz", Summary: `Install snap "baz" (channel "candidate")`},
+		{Kind: manifeststate.OpInstallComponents, Snap: "baz", Summary: `Install components "comp" of snap "baz"`},
+		{Kind: manifeststate.OpAlias, Snap: "baz", Summary: `Set up alias "baz-alias" for app "baz.app"`},
+		{Kind: manifeststate.OpConfigure, Snap: "baz", Summary: `Configure "key" of snap "baz"`},
+		{Kind: manifeststate.OpConnect, Snap: "baz", Summary: `Connect baz:plug to foo:slot`},
+		{Kind: manifeststate.OpCreateQuota, Summary: `Create quota group "new-grp"`},
+	})
+}
+
+func (s *manifestStateSuite) TestPlanChanges(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	ops, err := manifeststate.Plan(s.state, s.parse(c, `
+snaps:
+  foo:
+    channel: latest/candidate
+    components: [comp1, comp2]
+    aliases:
+      foo-alias: app
+      other-alias: app
+    config:
+      key: other-value
+      nested:
+        a: 1
+quotas:
+  grp:
+    memory: 2GB
+    snaps: [foo, bar]
+`))
+	c.Assert(err, IsNil)
+	c.Check(ops, DeepEquals, []*manifeststate.Operation{
+		{Kind: manifeststate.OpRefresh, Snap: "foo", Summary: `Refresh snap "foo" (channel "latest/candidate")`},
+		{Kind: manifeststate.OpInstallComponents, Snap: "foo", Summary: `Install components "comp2" of snap "foo"`},
+		{Kind: manifeststate.OpAlias, Snap: "foo", Summary: `Set up alias "other-alias" for app "foo.app"`},
+		{Kind: manifeststate.OpConfigure, Snap: "foo", Summary: `Configure "key" of snap "foo"`},
+		{Kind: manifeststate.OpUpdateQuota, Summary: `Update quota group "grp"`},
+	})
+}
+
+func (s *manifestStateSuite) TestPlanPinnedRevision(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	ops, err := manifeststate.Plan(s.state, s.parse(c, "snaps: {foo: {revision: 1}}"))
+	c.Assert(err, IsNil)
+	c.Check(ops, HasLen, 0)
+
+	ops, err = manifeststate.Plan(s.state, s.parse(c, "snaps: {foo: {revision: 2}}"))
+	c.Assert(err, IsNil)
+	c.Check(ops, DeepEquals, []*manifeststate.Operation{
+		{Kind: manifeststate.OpRefresh, Snap: "foo", Summary: `Refresh snap "foo" (revision 2)`},
+	})
+}
+
+func (s *manifestStateSuite) TestPlanCannotMoveQuotaGroup(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	_, err := manifeststate.Plan(s.state, s.parse(c, "quotas: {grp: {parent: other}}"))
+	c.Check(err, ErrorMatches, `cannot move existing quota group "grp" to parent "other"`)
+}
+
+func (s *manifestStateSuite) TestApply(c *C) {
+	s.AddCleanup(manifeststate.MockSnapstateInstallWithGoal(func(ctx context.Context, st *state.State, goal snapstate.InstallGoal, opts snapstate.Options) ([]*snap.Info, []*state.TaskSet, error) {
+		c.Check(opts.UserID, Equals, 42)
+		return nil, []*state.TaskSet{state.NewTaskSet(st.NewTask("fake-install", ""))}, nil
+	}))
+	s.AddCleanup(manifeststate.MockSnapstateUpdateWithGoal(func(ctx context.Context, st *state.State, goal snapstate.UpdateGoal, filter func(*snap.Info, *snapstate.SnapState) bool, opts snapstate.Options) ([]string, *snapstate.UpdateTaskSets, error) {
+		c.Fatal("unexpected refresh")
+		return nil, nil, nil
+	}))
+	s.AddCleanup(manifeststate.MockSnapstateInstallComponents(func(ctx context.Context, st *state.State, names []string, info *snap.Info, opts snapstate.Options) ([]*state.TaskSet, error) {
+		c.Check(info.InstanceName(), Equals, "foo")
+		c.Check(names, DeepEquals, []string{"comp2"})
+		return []*state.TaskSet{state.NewTaskSet(st.NewTask("fake-install-component", ""))}, nil
+	}))
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tss, ops, err := manifeststate.Apply(context.Background(), s.state, s.parse(c, `
+snaps:
+  baz: {}
+  foo:
+    components: [comp1, comp2]
+    config:
+      key: other-value
+`), 42)
+	c.Assert(err, IsNil)
+	c.Check(ops, DeepEquals, []*manifeststate.Operation{
+		{Kind: manifeststate.OpInstall, Snap: "baz", Summary: `Install snap "baz"`},
+		{Kind: manifeststate.OpInstallComponents, Snap: "foo", Summary: `Install components "comp2" of snap "foo"`},
+		{Kind: manifeststate.OpConfigure, Snap: "foo", Summary: `Configure "key" of snap "foo"`},
+	})
+	c.Assert(tss, HasLen, 3)
+	c.Check(tss[0].Tasks()[0].Kind(), Equals, "fake-install")
+	c.Check(tss[1].Tasks()[0].Kind(), Equals, "fake-install-component")
+	apply := tss[2].Tasks()[0]
+	c.Check(apply.Kind(), Equals, "apply-manifest")
+	c.Check(apply.WaitTasks(), DeepEquals, []*state.Task{tss[0].Tasks()[0], tss[1].Tasks()[0]})
+	var m manifeststate.Manifest
+	c.Assert(apply.Get("manifest", &m), IsNil)
+	c.Check(m.SnapNames(), DeepEquals, []string{"baz", "foo"})
+}
+
+func (s *manifestStateSuite) TestApplyRefresh(c *C) {
+	s.AddCleanup(manifeststate.MockSnapstateUpdateWithGoal(func(ctx context.Context, st *state.State, goal snapstate.UpdateGoal, filter func(*snap.Info, *snapstate.SnapState) bool, opts snapstate.Options) ([]string, *snapstate.UpdateTaskSets, error) {
+		return []string{"foo"}, &snapstate.UpdateTaskSets{
+			Refresh: []*state.TaskSet{state.NewTaskSet(st.NewTask("fake-refresh", ""))},
+		}, nil
+	}))
+	s.AddCleanup(manifeststate.MockSnapstateInstallComponents(func(ctx context.Context, st *state.State, names []string, info *snap.Info, opts snapstate.Options) ([]*state.TaskSet, error) {
+		c.Fatal("components are installed by the refresh")
+		return nil, nil
+	}))
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tss, ops, err := manifeststate.Apply(context.Background(), s.state, s.parse(c, "snaps: {foo: {channel: edge, components: [comp2]}}"), 0)
+	c.Assert(err, IsNil)
+	c.Check(ops, HasLen, 2)
+	c.Assert(tss, HasLen, 1)
+	c.Check(tss[0].Tasks()[0].Kind(), Equals, "fake-refresh")
+}
+
+func (s *manifestStateSuite) TestApplyConverged(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tss, ops, err := manifeststate.Apply(context.Background(), s.state, s.parse(c, convergedManifest), 0)
+	c.Assert(err, IsNil)
+	c.Check(tss, HasLen, 0)
+	c.Check(ops, HasLen, 0)
+}
+
+func (s *manifestStateSuite) TestDoApplyManifest(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	m := s.parse(c, `
+snaps:
+  foo:
+    aliases:
+      foo-alias: app
+      other-alias: app
+    config:
+      key: other-value
+`)
+	chg := s.state.NewChange("apply-manifest", "...")
+	t := s.state.NewTask("apply-manifest", "...")
+	t.Set("manifest", m)
+	chg.AddTask(t)
+
+	s.state.Unlock()
+	err := s.mgr.DoApplyManifest(t)
+	s.state.Lock()
+	c.Assert(err, IsNil)
+
+	tasks := chg.Tasks()
+	c.Assert(tasks, HasLen, 3)
+	alias, configure := tasks[1], tasks[2]
+	c.Check(alias.Kind(), Equals, "alias")
+	c.Check(alias.WaitTasks(), DeepEquals, []*state.Task{t})
+	c.Check(configure.Kind(), Equals, "run-hook")
+	c.Check(configure.WaitTasks(), testutil.DeepUnsortedMatches, []*state.Task{t, alias})
+}
+
+func (s *manifestStateSuite) TestDoApplyManifestConflict(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	other := s.state.NewChange("other", "...")
+	ot := s.state.NewTask("link-snap", "...")
+	ot.Set("snap-setup", &snapstate.SnapSetup{SideInfo: &snap.SideInfo{RealName: "foo"}})
+	other.AddTask(ot)
+
+	chg := s.state.NewChange("apply-manifest", "...")
+	t := s.state.NewTask("apply-manifest", "...")
+	t.Set("manifest", s.parse(c, "snaps: {foo: {aliases: {other-alias: app}}}"))
+	chg.AddTask(t)
+
+	s.state.Unlock()
+	err := s.mgr.DoApplyManifest(t)
+	s.state.Lock()
+	c.Check(err, ErrorMatches, `snap "foo" has "other" change in progress`)
+	c.Check(chg.Tasks(), HasLen, 1)
+}
+
+func (s *manifestStateSuite) TestDoApplyManifestAfterSnapTasksOfSameChange(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	// the change refreshed foo before getting to apply-manifest
+	chg := s.state.NewChange("apply-manifest", "...")
+	snapsup := &snapstate.SnapSetup{SideInfo: &snap.SideInfo{RealName: "foo"}}
+	var prev *state.Task
+	for _, kind := range []string{"prerequisites", "download-snap", "link-snap"} {
+		st := s.state.NewTask(kind, "...")
+		st.Set("snap-setup", snapsup)
+		st.SetStatus(state.DoneStatus)
+		if prev != nil {
+			st.WaitFor(prev)
+		}
+		chg.AddTask(st)
+		prev = st
+	}
+	t := s.state.NewTask("apply-manifest", "...")
+	t.Set("manifest", s.parse(c, `
+snaps:
+  foo:
+    aliases:
+      other-alias: app
+    config:
+      key: other-value
+`))
+	t.WaitFor(prev)
+	chg.AddTask(t)
+
+	s.state.Unlock()
+	err := s.mgr.DoApplyManifest(t)
+	s.state.Lock()
+	c.Assert(err, IsNil)
+
+	tasks := chg.Tasks()
+	c.Assert(tasks, HasLen, 6)
+	c.Check(tasks[4].Kind(), Equals, "alias")
+	c.Check(tasks[4].WaitTasks(), DeepEquals, []*state.Task{t})
+	c.Check(tasks[5].Kind(), Equals, "run-hook")
+}
@@ -35,6 +35,7 @@ import (
 	"gopkg.in/tomb.v2"
 
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/features"
 	"github.com/snapcore/snapd/logger"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/overlord/assertstate"
@@ -45,6 +46,7 @@ import (
 	"github.com/snapcore/snapd/overlord/healthstate"
 	"github.com/snapcore/snapd/overlord/hookstate"
 	"github.com/snapcore/snapd/overlord/ifacestate"
+	"github.com/snapcore/snapd/overlord/manifeststate"
 	"github.com/snapcore/snapd/overlord/patch"
 	"github.com/snapcore/snapd/overlord/registrystate"
 	"github.com/snapcore/snapd/overlord/restart"
@@ -88,6 +90,8 @@ var pruneTickerC = func(t *time.Ticker) <-chan time.Time {
 // track of all available state managers and related helpers.
 type Overlord struct {
 	stateFLock *osutil.FileLock
+	// stateJournal is set when the state is persisted incrementally
+	stateJournal *journaledStateBackend
 
 	stateEng *StateEngine
 	// ensure loop
@@ -126,10 +130,17 @@ func New(restartHandler restart.Handler) (*Overlord, error) {
 		inited: true,
 	}
 
-	backend := &overlordStateBackend{
+	var backend state.Backend = &overlordStateBackend{
 		path:         dirs.SnapStateFile,
 		ensureBefore: o.ensureBefore,
 	}
+	if features.JournaledState.IsEnabled() {
+		o.stateJournal = &journaledStateBackend{
+			path:         dirs.SnapStateFile,
+			ensureBefore: o.ensureBefore,
+		}
+		backend = o.stateJournal
+	}
 	s, restartMgr, err := o.loadState(backend, restartHandler)
 	if err != nil {
 		return nil, err
@@ -182,11 +193,13 @@ func New(restartHandler restart.Handler) (*Overlord, error) {
 	o.addManager(cmdstate.Manager(s, o.runner))
 	o.addManager(snapshotstate.Manager(s, o.runner))
 	o.addManager(registrystate.Manager(s, hookMgr, o.runner))
+	o.addManager(manifeststate.Manager(s, o.runner))
 
 	if err := configstateInit(s, hookMgr); err != nil {
 		return nil, err
 	}
 	healthstate.Init(hookMgr)
+	o.addManager(healthstate.Manager(s))
 
 	// the shared task runner should be added last!
 	o.stateEng.AddManager(o.runner)
@@ -301,15 +314,16 @@ func (o *Overlord) loadState(backend state.Backend, restartHandler restart.Handl
 		return s, restartMgr, nil
 	}
 
-	r, err := os.Open(dirs.SnapStateFile)
-	if err != nil {
-		return nil, nil, fmt.Errorf("cannot read the state file: %s", err)
+	if _, ok := backend.(state.JournalBackend); !ok {
+		// the state was persisted with the journaled backend before
+		if err := foldStateJournal(dirs.SnapStateFile); err != nil {
+			return nil, nil, fmt.Errorf("cannot fold the state journal: %v", err)
+		}
 	}
-	defer r.Close()
 
 	var s *state.State
 	timings.Run(perfTimings, "read-state", "read snapd state from disk", func(tm timings.Measurer) {
-		s, err = state.ReadState(backend, r)
+		s, err = state.ReadStateFile(backend, dirs.SnapStateFile)
 	})
 	if err != nil {
 		return nil, nil, err
@@ -512,6 +526,11 @@ func (o *Overlord) Stop() error {
 		err = o.loopTomb.Wait()
 	}
 	o.stateEng.Stop()
+	if o.stateJournal != nil {
+		if err := o.stateJournal.Close(); err != nil {
+			logger.Noticef("Cannot compact state journal: %v", err)
+		}
+	}
 	if o.stateFLock != nil {
 		// This will also unlock the file
 		o.stateFLock.Close()
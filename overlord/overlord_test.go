@@ -20,6 +20,7 @@
 package overlord_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -37,6 +38,7 @@ import (
 
 	"github.com/snapcore/snapd/boot"
 	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/features"
 	"github.com/snapcore/snapd/osutil"
 	"github.com/snapcore/snapd/overlord"
 	"github.com/snapcore/snapd/overlord/devicestate/devicestatetest"
@@ -871,6 +873,56 @@ func (ovs *overlordSuite) TestCheckpoint(c *C) {
 	c.Check(dirs.SnapStateFile, testutil.FileContains, `"mark":1`)
 }
 
+func (ovs *overlordSuite) TestCheckpointJournaledState(c *C) {
+	c.Assert(os.MkdirAll(dirs.FeaturesDir, 0755), IsNil)
+	c.Assert(os.WriteFile(features.JournaledState.ControlFile(), nil, 0644), IsNil)
+
+	o, err := overlord.New(nil)
+	c.Assert(err, IsNil)
+
+	s := o.State()
+	s.Lock()
+	s.Set("mark", 1)
+	s.Unlock()
+
+	c.Check(dirs.SnapStateFile, Not(testutil.FileContains), `"mark":1`)
+	c.Check(state.JournalFile(dirs.SnapStateFile), testutil.FileContains, `"mark":1`)
+
+	s.Lock()
+	s.Set("mark", 2)
+	s.Unlock()
+
+	// stopping folds the journal into the state file
+	c.Assert(o.Stop(), IsNil)
+	c.Check(dirs.SnapStateFile, testutil.FileContains, `"mark":2`)
+}
+
+func (ovs *overlordSuite) TestNewWithLeftoverJournal(c *C) {
+	// ensure we don't write state load timing in the state on really
+	// slow architectures (e.g. risc-v)
+	restore := testutil.Mock(&timings.DurationThreshold, 30*time.Second)
+	defer restore()
+
+	fakeState := []byte(fmt.Sprintf(`{"data":{"patch-level":%d,"patch-sublevel":%d,"patch-sublevel-last-version":%q,"refresh-privacy-key":"0123456789ABCDEF","mark":1},"changes":null,"tasks":null,"last-change-id":0,"last-task-id":0,"last-lane-id":0}`, patch.Level, patch.Sublevel, snapdtool.Version))
+	c.Assert(os.WriteFile(dirs.SnapStateFile, fakeState, 0600), IsNil)
+	var journal bytes.Buffer
+	c.Assert(state.WriteJournalRecord(&journal, []byte(`{"data":{"mark":2},"last-change-id":0,"last-task-id":0,"last-lane-id":0,"last-notice-id":0}`)), IsNil)
+	c.Assert(os.WriteFile(state.JournalFile(dirs.SnapStateFile), journal.Bytes(), 0600), IsNil)
+
+	// the journaled state feature is disabled
+	o, err := overlord.New(nil)
+	c.Assert(err, IsNil)
+
+	s := o.State()
+	s.Lock()
+	defer s.Unlock()
+	var mark int
+	c.Assert(s.Get("mark", &mark), IsNil)
+	c.Check(mark, Equals, 2)
+	c.Check(dirs.SnapStateFile, testutil.FileContains, `"mark":2`)
+	c.Check(state.JournalFile(dirs.SnapStateFile), testutil.FileAbsent)
+}
+
 type sampleManager struct {
 	ensureCallback func()
 }
@@ -137,6 +137,10 @@ type CreateQuotaOptions struct {
 
 	// ResourceLimits is the resource limits to be used for the quota group.
 	ResourceLimits quota.Resources
+
+	// FromChange is the ID of a change whose tasks do not count as
+	// conflicting with the snaps added to the quota group.
+	FromChange string
 }
 
 // CreateQuota attempts to create the specified quota group with the specified
@@ -185,7 +189,7 @@ func CreateQuota(st *state.State, name string, createOpts CreateQuotaOptions) (*
 	if err := CheckQuotaChangeConflictMany(st, []string{name}); err != nil {
 		return nil, err
 	}
-	if err := snapstate.CheckChangeConflictMany(st, createOpts.Snaps, ""); err != nil {
+	if err := snapstate.CheckChangeConflictMany(st, createOpts.Snaps, createOpts.FromChange); err != nil {
 		return nil, err
 	}
 
@@ -273,6 +277,10 @@ type UpdateQuotaOptions struct {
 	// NewResourceLimits is the new resource limits to be used for the quota group. A
 	// limit is only changed if the corresponding limit is != nil.
 	NewResourceLimits quota.Resources
+
+	// FromChange is the ID of a change whose tasks do not count as
+	// conflicting with the snaps added to the quota group.
+	FromChange string
 }
 
 // UpdateQuota updates the quota as per the options.
@@ -324,7 +332,7 @@ func UpdateQuota(st *state.State, name string, updateOpts UpdateQuotaOptions) (*
 	if err := CheckQuotaChangeConflictMany(st, []string{name}); err != nil {
 		return nil, err
 	}
-	if err := snapstate.CheckChangeConflictMany(st, updateOpts.AddSnaps, ""); err != nil {
+	if err := snapstate.CheckChangeConflictMany(st, updateOpts.AddSnaps, updateOpts.FromChange); err != nil {
 		return nil, err
 	}
 
@@ -466,6 +466,7 @@ func delayedCrossMgrInit() {
 	snapstate.AutomaticSnapshot = AutomaticSnapshot
 	snapstate.AutomaticSnapshotExpiration = AutomaticSnapshotExpiration
 	snapstate.EstimateSnapshotSize = EstimateSnapshotSize
+	snapstate.SnapshotRevisions = SnapshotRevisions
 }
 
 func MockBackendSave(f func(context.Context, uint64, *snap.Info, map[string]interface{}, []string, *snap.SnapshotOptions, *dirs.SnapDirOptions) (*client.Snapshot, error)) (restore func()) {
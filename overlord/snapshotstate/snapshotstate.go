@@ -126,6 +126,28 @@ func EstimateSnapshotSize(st *state.State, instanceName string, users []string)
 	return sz, nil
 }
 
+// SnapshotRevisions returns, by instance name, the revisions of the snaps
+// which the existing snapshots were taken of. The state does not need to be
+// locked.
+func SnapshotRevisions(st *state.State) (map[string][]snap.Revision, error) {
+	revs := make(map[string][]snap.Revision)
+	seen := make(map[string]map[snap.Revision]bool)
+	err := backendIter(context.TODO(), func(r *backend.Reader) error {
+		if seen[r.Snap] == nil {
+			seen[r.Snap] = make(map[snap.Revision]bool)
+		}
+		if !seen[r.Snap][r.Revision] {
+			seen[r.Snap][r.Revision] = true
+			revs[r.Snap] = append(revs[r.Snap], r.Revision)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revs, nil
+}
+
 func AutomaticSnapshotExpiration(st *state.State) (time.Duration, error) {
 	var expirationStr string
 	tr := config.NewTransaction(st)
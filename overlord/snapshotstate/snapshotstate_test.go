@@ -195,6 +195,28 @@ func (snapshotSuite) TestSnapSummariesInSnapshotSet(c *check.C) {
 	})
 }
 
+func (snapshotSuite) TestSnapshotRevisions(c *check.C) {
+	fakeIter := func(_ context.Context, f func(*backend.Reader) error) error {
+		for _, shot := range []client.Snapshot{
+			{SetID: 1, Snap: "a-snap", Revision: snap.R(1)},
+			{SetID: 2, Snap: "b-snap", Revision: snap.R(2)},
+			{SetID: 3, Snap: "a-snap", Revision: snap.R(3)},
+			{SetID: 4, Snap: "a-snap", Revision: snap.R(1)},
+		} {
+			c.Assert(f(&backend.Reader{Snapshot: shot}), check.IsNil)
+		}
+		return nil
+	}
+	defer snapshotstate.MockBackendIter(fakeIter)()
+
+	revs, err := snapshotstate.SnapshotRevisions(nil)
+	c.Assert(err, check.IsNil)
+	c.Check(revs, check.DeepEquals, map[string][]snap.Revision{
+		"a-snap": {snap.R(1), snap.R(3)},
+		"b-snap": {snap.R(2)},
+	})
+}
+
 func (snapshotSuite) TestSnapSummariesInSnapshotSetSnaps(c *check.C) {
 	shotfile, err := os.Create(filepath.Join(c.MkDir(), "foo.zip"))
 	c.Assert(err, check.IsNil)
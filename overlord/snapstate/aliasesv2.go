@@ -531,6 +531,13 @@ func (m *SnapManager) ensureAliasesV2() error {
 
 // Alias sets up a manual alias from alias to app in snapName.
 func Alias(st *state.State, instanceName, app, alias string) (*state.TaskSet, error) {
+	return AliasFromChange(st, instanceName, app, alias, "")
+}
+
+// AliasFromChange is like Alias but the tasks of the change with the given
+// ID, if any, do not count as conflicting. It is meant for task handlers
+// adding the alias tasks to their own change.
+func AliasFromChange(st *state.State, instanceName, app, alias string, fromChange string) (*state.TaskSet, error) {
 	if err := snap.ValidateAlias(alias); err != nil {
 		return nil, err
 	}
@@ -543,7 +550,7 @@ func Alias(st *state.State, instanceName, app, alias string) (*state.TaskSet, er
 	if err != nil {
 		return nil, err
 	}
-	if err := CheckChangeConflict(st, instanceName, nil); err != nil {
+	if err := checkChangeConflictIgnoringOneChange(st, instanceName, nil, fromChange); err != nil {
 		return nil, err
 	}
 
@@ -131,12 +131,22 @@ type autoRefresh struct {
 	nextRefresh         time.Time
 	lastRefreshAttempt  time.Time
 
+	// lastSnapRefreshSchedules, nextSnapRefreshes and
+	// lastSnapRefreshAttempts track the snaps refreshed on their own
+	// refresh.snap-timers.<snap> schedule.
+	lastSnapRefreshSchedules map[string]string
+	nextSnapRefreshes        map[string]time.Time
+	lastSnapRefreshAttempts  map[string]time.Time
+
 	restoredMonitoring bool
 }
 
 func newAutoRefresh(st *state.State) *autoRefresh {
 	return &autoRefresh{
-		state: st,
+		state:                    st,
+		lastSnapRefreshSchedules: make(map[string]string),
+		nextSnapRefreshes:        make(map[string]time.Time),
+		lastSnapRefreshAttempts:  make(map[string]time.Time),
 	}
 }
 
@@ -319,6 +329,14 @@ func (m *autoRefresh) Ensure() (err error) {
 		return err
 	}
 
+	// snaps with their own refresh timer are refreshed on it regardless of
+	// refresh.timer being managed and of the system-wide refresh hold
+	defer func() {
+		if err == nil {
+			err = m.ensureSnapTimers(lastRefresh)
+		}
+	}()
+
 	refreshSchedule, refreshScheduleStr, _, err := m.refreshScheduleWithDefaultsFallback()
 	if err != nil {
 		return err
@@ -395,7 +413,7 @@ func (m *autoRefresh) Ensure() (err error) {
 				return nil
 			}
 
-			err = m.launchAutoRefresh()
+			err = m.launchAutoRefresh(nil)
 			if _, ok := err.(*httputil.PersistentNetworkError); ok {
 				// refresh will be retried after refreshRetryDelay
 				return err
@@ -412,6 +430,85 @@ func (m *autoRefresh) Ensure() (err error) {
 	return err
 }
 
+// ensureSnapTimers refreshes the snaps which have their own refresh timer
+// once it is due.
+func (m *autoRefresh) ensureSnapTimers(lastRefresh time.Time) error {
+	timers, err := snapRefreshTimers(m.state)
+	if err != nil {
+		return err
+	}
+	confs, err := snapRefreshTimerConfs(m.state)
+	if err != nil {
+		return err
+	}
+	for name := range m.lastSnapRefreshSchedules {
+		if timers[name] == nil {
+			delete(m.nextSnapRefreshes, name)
+			delete(m.lastSnapRefreshSchedules, name)
+			delete(m.lastSnapRefreshAttempts, name)
+		}
+	}
+	if len(timers) == 0 {
+		return nil
+	}
+
+	lastSnapRefreshes, err := lastSnapRefreshTimes(m.state)
+	if err != nil {
+		return err
+	}
+
+	now := timeNow()
+	var due []string
+	for name, sched := range timers {
+		var snapst SnapState
+		if err := Get(m.state, name, &snapst); err != nil {
+			if errors.Is(err, state.ErrNoState) {
+				continue
+			}
+			return err
+		}
+		if m.lastSnapRefreshSchedules[name] != confs[name] {
+			// the timer has changed
+			delete(m.nextSnapRefreshes, name)
+		}
+		m.lastSnapRefreshSchedules[name] = confs[name]
+		if m.nextSnapRefreshes[name].IsZero() {
+			last := lastSnapRefreshes[name]
+			if last.IsZero() {
+				last = lastRefresh
+			}
+			m.nextSnapRefreshes[name] = now.Add(timeutil.Next(sched, last, maxPostponement))
+			logger.Debugf("Next refresh of snap %q scheduled for %s.", name, m.nextSnapRefreshes[name].Format(time.RFC3339))
+		}
+		if m.nextSnapRefreshes[name].After(now) {
+			continue
+		}
+		// do not hammer the store if the last attempt failed
+		if lastAttempt := m.lastSnapRefreshAttempts[name]; !lastAttempt.IsZero() && lastAttempt.Add(refreshRetryDelay).After(now) {
+			continue
+		}
+		due = append(due, name)
+	}
+	if len(due) == 0 || autoRefreshInFlight(m.state) {
+		return nil
+	}
+	sort.Strings(due)
+
+	can, err := m.canRefreshRespectingMetered(now, lastRefresh)
+	if err != nil || !can {
+		return err
+	}
+
+	err = m.launchAutoRefresh(due)
+	if _, ok := err.(*httputil.PersistentNetworkError); ok {
+		return err
+	}
+	for _, name := range due {
+		delete(m.nextSnapRefreshes, name)
+	}
+	return err
+}
+
 func (m *autoRefresh) restoreMonitoring() error {
 	if m.restoredMonitoring {
 		return nil
@@ -512,6 +609,91 @@ func getRefreshScheduleConf(st *state.State) (confStr string, legacy bool, err e
 	return confStr, legacy, nil
 }
 
+// snapRefreshTimerConfs returns the per-snap refresh timers as set with the
+// refresh.snap-timers.<snap> system options.
+func snapRefreshTimerConfs(st *state.State) (map[string]string, error) {
+	var confs map[string]string
+	tr := config.NewTransaction(st)
+	if err := tr.Get("core", "refresh.snap-timers", &confs); err != nil && !config.IsNoOption(err) {
+		return nil, err
+	}
+	for name, conf := range confs {
+		if conf == "" {
+			delete(confs, name)
+		}
+	}
+	return confs, nil
+}
+
+// snapRefreshTimers returns the parsed per-snap refresh timers. Snaps with
+// their own timer are auto-refreshed according to it instead of according
+// to refresh.timer.
+func snapRefreshTimers(st *state.State) (map[string][]*timeutil.Schedule, error) {
+	confs, err := snapRefreshTimerConfs(st)
+	if err != nil {
+		return nil, err
+	}
+	timers := make(map[string][]*timeutil.Schedule, len(confs))
+	for name, conf := range confs {
+		sched, err := timeutil.ParseSchedule(conf)
+		if err != nil {
+			// log instead of fail in order not to prevent auto-refreshes
+			logger.Noticef("cannot use refresh.snap-timers.%s configuration: %v", name, err)
+			continue
+		}
+		timers[name] = sched
+	}
+	return timers, nil
+}
+
+func lastSnapRefreshTimes(st *state.State) (map[string]time.Time, error) {
+	var lastRefreshes map[string]time.Time
+	if err := st.Get("last-snap-refresh", &lastRefreshes); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	if lastRefreshes == nil {
+		lastRefreshes = make(map[string]time.Time)
+	}
+	return lastRefreshes, nil
+}
+
+// SnapRefreshSchedule describes the refresh schedule of a snap with its own
+// refresh timer.
+type SnapRefreshSchedule struct {
+	Timer string
+	Last  time.Time
+	Next  time.Time
+}
+
+// SnapRefreshSchedules returns the refresh schedules of the installed snaps
+// with their own refresh timer.
+func (m *autoRefresh) SnapRefreshSchedules() (map[string]*SnapRefreshSchedule, error) {
+	confs, err := snapRefreshTimerConfs(m.state)
+	if err != nil {
+		return nil, err
+	}
+	lastSnapRefreshes, err := lastSnapRefreshTimes(m.state)
+	if err != nil {
+		return nil, err
+	}
+	scheds := make(map[string]*SnapRefreshSchedule, len(confs))
+	for name, conf := range confs {
+		var snapst SnapState
+		if err := Get(m.state, name, &snapst); err != nil {
+			if errors.Is(err, state.ErrNoState) {
+				continue
+			}
+			return nil, err
+		}
+		scheds[name] = &SnapRefreshSchedule{
+			Timer: conf,
+			Last:  lastSnapRefreshes[name],
+			Next:  m.nextSnapRefreshes[name],
+		}
+	}
+	return scheds, nil
+}
+
 // refreshScheduleWithDefaultsFallback returns the current refresh schedule
 // and refresh string.
 func (m *autoRefresh) refreshScheduleWithDefaultsFallback() (sched []*timeutil.Schedule, scheduleConf string, legacy bool, err error) {
@@ -581,16 +763,24 @@ func (tooSoonError) Is(err error) bool {
 }
 
 // launchAutoRefresh creates the auto-refresh taskset and a change for it.
-func (m *autoRefresh) launchAutoRefresh() error {
-	// Check that we have reasonable delays between attempts.
-	// If the store is under stress we need to make sure we do not
-	// hammer it too often
+// If snapNames is not empty only the given snaps, which have their own
+// refresh timer, are refreshed.
+func (m *autoRefresh) launchAutoRefresh(snapNames []string) error {
 	now := timeNow()
-	minAttempt := m.lastRefreshAttempt.Add(refreshRetryDelay)
-	if !m.lastRefreshAttempt.IsZero() && minAttempt.After(now) {
-		return tooSoonError{}
+	if len(snapNames) == 0 {
+		// Check that we have reasonable delays between attempts.
+		// If the store is under stress we need to make sure we do not
+		// hammer it too often
+		minAttempt := m.lastRefreshAttempt.Add(refreshRetryDelay)
+		if !m.lastRefreshAttempt.IsZero() && minAttempt.After(now) {
+			return tooSoonError{}
+		}
+		m.lastRefreshAttempt = now
+	} else {
+		for _, name := range snapNames {
+			m.lastSnapRefreshAttempts[name] = now
+		}
 	}
-	m.lastRefreshAttempt = now
 
 	perfTimings := timings.New(map[string]string{"ensure": "auto-refresh"})
 	tm := perfTimings.StartSpan("auto-refresh", "query store and setup auto-refresh change")
@@ -600,7 +790,16 @@ func (m *autoRefresh) launchAutoRefresh() error {
 	}()
 
 	// NOTE: this will unlock and re-lock state for network ops
-	updated, updateTss, err := AutoRefresh(auth.EnsureContextTODO(), m.state)
+	var updated []string
+	var updateTss *UpdateTaskSets
+	var err error
+	if len(snapNames) == 0 {
+		updated, updateTss, err = AutoRefresh(auth.EnsureContextTODO(), m.state)
+	} else {
+		updated, updateTss, err = autoRefreshSelected(auth.EnsureContextTODO(), m.state, func(instanceName string) bool {
+			return strutil.ListContains(snapNames, instanceName)
+		})
+	}
 
 	// TODO: we should have some way to lock just creating and starting changes,
 	//       as that would alleviate this race condition we are guarding against
@@ -608,24 +807,38 @@ func (m *autoRefresh) launchAutoRefresh() error {
 	//       conditions elsewhere
 
 	// re-check if the refresh is held because it could have been re-held and
-	// pushed back, in which case we need to abort the auto-refresh and wait
-	held, _, holdErr := m.isRefreshHeld()
-	if holdErr != nil {
-		return holdErr
-	}
+	// pushed back, in which case we need to abort the auto-refresh and wait;
+	// snaps with their own refresh timer are not affected by the hold
+	if len(snapNames) == 0 {
+		held, _, holdErr := m.isRefreshHeld()
+		if holdErr != nil {
+			return holdErr
+		}
 
-	if held {
-		// then a request came in that pushed the refresh out, so we will need
-		// to try again later
-		logger.Noticef("Auto-refresh was delayed mid-way through launching, aborting to try again later")
-		return nil
+		if held {
+			// then a request came in that pushed the refresh out, so we
+			// will need to try again later
+			logger.Noticef("Auto-refresh was delayed mid-way through launching, aborting to try again later")
+			return nil
+		}
 	}
 
 	if _, ok := err.(*httputil.PersistentNetworkError); ok {
 		logger.Noticef("Cannot prepare auto-refresh change due to a permanent network error: %s", err)
 		return err
 	}
-	m.state.Set("last-refresh", timeNow())
+	if len(snapNames) == 0 {
+		m.state.Set("last-refresh", timeNow())
+	} else {
+		lastSnapRefreshes, lerr := lastSnapRefreshTimes(m.state)
+		if lerr != nil {
+			return lerr
+		}
+		for _, name := range snapNames {
+			lastSnapRefreshes[name] = timeNow()
+		}
+		m.state.Set("last-snap-refresh", lastSnapRefreshes)
+	}
 	if err != nil {
 		logger.Noticef("Cannot prepare auto-refresh change: %s", err)
 		return err
@@ -808,9 +808,23 @@ func AutoRefreshForGatingSnap(st *state.State, gatingSnap string) error {
 		return fmt.Errorf("no snaps are held by snap %q", gatingSnap)
 	}
 
+	selected, err := onSystemRefreshTimer(st)
+	if err != nil {
+		return err
+	}
+	if selected != nil {
+		onSystemTimer := selected
+		// snaps with their own refresh timer are only refreshed if
+		// their timer was due and the gating snap held them
+		selected = func(instanceName string) bool {
+			_, held := gating[instanceName][gatingSnap]
+			return onSystemTimer(instanceName) || held
+		}
+	}
+
 	// NOTE: this will unlock and re-lock state for network ops
 	// XXX: should we refresh assertions (just call AutoRefresh()?)
-	updated, tasksets, err := autoRefreshPhase1(auth.EnsureContextTODO(), st, gatingSnap)
+	updated, tasksets, err := autoRefreshPhase1(auth.EnsureContextTODO(), st, gatingSnap, selected)
 	if err != nil {
 		return err
 	}
@@ -47,12 +47,15 @@ import (
 	"github.com/snapcore/snapd/snap"
 	"github.com/snapcore/snapd/snap/snaptest"
 	"github.com/snapcore/snapd/store"
+	"github.com/snapcore/snapd/strutil"
 	"github.com/snapcore/snapd/testutil"
 )
 
 type autoRefreshGatingStore struct {
 	*fakeStore
 	refreshedSnaps []*snap.Info
+	// refreshActions are the names of the snaps asked to be refreshed
+	refreshActions []string
 }
 
 type autorefreshGatingSuite struct {
@@ -102,10 +105,14 @@ func (r *autoRefreshGatingStore) SnapAction(ctx context.Context, currentSnaps []
 		if a.Action != "refresh" {
 			panic("expected refresh actions")
 		}
+		r.refreshActions = append(r.refreshActions, a.InstanceName)
 	}
 
 	res := []store.SnapActionResult{}
 	for _, rs := range r.refreshedSnaps {
+		if !strutil.ListContains(r.refreshActions, rs.InstanceName()) {
+			continue
+		}
 		res = append(res, store.SnapActionResult{Info: rs})
 	}
 
@@ -2734,6 +2741,55 @@ func (s *autorefreshGatingSuite) TestAutoRefreshForGatingSnap(c *C) {
 	c.Check(lr.Equal(lastRefreshTime), Equals, true)
 }
 
+func (s *autorefreshGatingSuite) TestAutoRefreshForGatingSnapSnapRefreshTimers(c *C) {
+	s.store.refreshedSnaps = []*snap.Info{{
+		Architectures: []string{"all"},
+		SnapType:      snap.TypeApp,
+		Base:          "base-snap-b",
+		SideInfo: snap.SideInfo{
+			RealName: "snap-b",
+			Revision: snap.R(2),
+		},
+	}, {
+		Architectures: []string{"all"},
+		SnapType:      snap.TypeBase,
+		SideInfo: snap.SideInfo{
+			RealName: "base-snap-b",
+			Revision: snap.R(3),
+		},
+	}}
+
+	st := s.state
+	st.Lock()
+	defer st.Unlock()
+
+	mockInstalledSnap(c, s.state, snapByaml, useHook)
+	mockInstalledSnap(c, s.state, baseSnapByaml, noHook)
+
+	restore := snapstatetest.MockDeviceModel(DefaultModel())
+	defer restore()
+
+	// both snaps are refreshed on their own timer, snap-b was due and
+	// held itself
+	tr := config.NewTransaction(st)
+	tr.Set("core", "refresh.snap-timers.snap-b", "0:00-24:00")
+	tr.Set("core", "refresh.snap-timers.base-snap-b", "0:00-24:00")
+	tr.Commit()
+	_, err := snapstate.HoldRefresh(st, snapstate.HoldAutoRefresh, "snap-b", 0, "snap-b")
+	c.Assert(err, IsNil)
+
+	// pretend that snap-b triggers auto-refresh (by calling snapctl refresh --proceed)
+	c.Assert(snapstate.AutoRefreshForGatingSnap(st, "snap-b"), IsNil)
+
+	// base-snap-b is left to its own timer
+	c.Check(s.store.refreshActions, DeepEquals, []string{"snap-b"})
+	changes := st.Changes()
+	c.Assert(changes, HasLen, 1)
+	var snapNames []string
+	c.Assert(changes[0].Get("snap-names", &snapNames), IsNil)
+	c.Check(snapNames, DeepEquals, []string{"snap-b"})
+}
+
 func (s *autorefreshGatingSuite) TestAutoRefreshForGatingSnapMoreAffectedSnaps(c *C) {
 	s.store.refreshedSnaps = []*snap.Info{{
 		Architectures: []string{"all"},
@@ -1191,6 +1191,161 @@ func (s *autoRefreshTestSuite) addRefreshableSnap(names ...string) {
 	}
 }
 
+func (s *autoRefreshTestSuite) setSnapRefreshTimer(name, timer string) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tr := config.NewTransaction(s.state)
+	tr.Set("core", "refresh.snap-timers."+name, timer)
+	tr.Commit()
+}
+
+func (s *autoRefreshTestSuite) TestSnapRefreshTimerExcludedFromSystemTimer(c *C) {
+	s.addRefreshableSnap("foo", "bar")
+	s.setSnapRefreshTimer("bar", "0:00-24:00")
+
+	s.state.Lock()
+	// bar was refreshed on its own timer just now
+	s.state.Set("last-snap-refresh", map[string]time.Time{"bar": time.Now()})
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, DeepEquals, []string{"list-refresh"})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	chgs := s.state.Changes()
+	c.Assert(chgs, HasLen, 1)
+	c.Check(chgs[0].Kind(), Equals, "auto-refresh")
+	var names []string
+	c.Assert(chgs[0].Get("snap-names", &names), IsNil)
+	c.Check(names, DeepEquals, []string{"foo"})
+
+	scheds, err := af.SnapRefreshSchedules()
+	c.Assert(err, IsNil)
+	c.Assert(scheds, HasLen, 1)
+	c.Check(scheds["bar"].Timer, Equals, "0:00-24:00")
+	c.Check(scheds["bar"].Next.After(time.Now()), Equals, true)
+}
+
+func (s *autoRefreshTestSuite) TestSnapRefreshTimerDue(c *C) {
+	s.addRefreshableSnap("foo", "bar")
+	s.setSnapRefreshTimer("bar", "0:00-24:00")
+	// not installed
+	s.setSnapRefreshTimer("baz", "0:00-24:00")
+
+	lastRefresh := time.Now()
+	s.state.Lock()
+	s.state.Set("last-refresh", lastRefresh)
+	s.state.Set("last-snap-refresh", map[string]time.Time{"bar": time.Now().Add(-48 * time.Hour)})
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, DeepEquals, []string{"list-refresh"})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	chgs := s.state.Changes()
+	c.Assert(chgs, HasLen, 1)
+	c.Check(chgs[0].Kind(), Equals, "auto-refresh")
+	var names []string
+	c.Assert(chgs[0].Get("snap-names", &names), IsNil)
+	c.Check(names, DeepEquals, []string{"bar"})
+
+	// only the last refresh of bar was updated
+	var storedLastRefresh time.Time
+	c.Assert(s.state.Get("last-refresh", &storedLastRefresh), IsNil)
+	c.Check(storedLastRefresh.Equal(lastRefresh), Equals, true)
+	var lastSnapRefreshes map[string]time.Time
+	c.Assert(s.state.Get("last-snap-refresh", &lastSnapRefreshes), IsNil)
+	c.Check(lastSnapRefreshes, HasLen, 1)
+	c.Check(time.Since(lastSnapRefreshes["bar"]) < time.Minute, Equals, true)
+}
+
+func (s *autoRefreshTestSuite) TestSnapRefreshTimerBackoff(c *C) {
+	s.addRefreshableSnap("bar")
+	s.setSnapRefreshTimer("bar", "0:00-24:00")
+
+	s.state.Lock()
+	s.state.Set("last-refresh", time.Now())
+	s.state.Set("last-snap-refresh", map[string]time.Time{"bar": time.Now().Add(-48 * time.Hour)})
+	s.state.Unlock()
+
+	s.store.err = &httputil.PersistentNetworkError{Err: fmt.Errorf("error")}
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, ErrorMatches, "persistent network error: error")
+	c.Check(s.store.ops, DeepEquals, []string{"list-refresh"})
+
+	// the failed refresh is not retried right away
+	s.store.err = nil
+	err = af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, DeepEquals, []string{"list-refresh"})
+
+	// but after a while
+	restore := snapstate.MockRefreshRetryDelay(0)
+	defer restore()
+	err = af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, DeepEquals, []string{"list-refresh", "list-refresh"})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+	chgs := s.state.Changes()
+	c.Assert(chgs, HasLen, 1)
+	var names []string
+	c.Assert(chgs[0].Get("snap-names", &names), IsNil)
+	c.Check(names, DeepEquals, []string{"bar"})
+}
+
+func (s *autoRefreshTestSuite) testSnapRefreshTimerIndependentOfSystemTimer(c *C, conf map[string]interface{}) {
+	s.addRefreshableSnap("foo", "bar")
+	s.setSnapRefreshTimer("bar", "0:00-24:00")
+
+	s.state.Lock()
+	tr := config.NewTransaction(s.state)
+	for k, v := range conf {
+		tr.Set("core", k, v)
+	}
+	tr.Commit()
+	s.state.Set("last-snap-refresh", map[string]time.Time{"bar": time.Now().Add(-48 * time.Hour)})
+	s.state.Unlock()
+
+	af := snapstate.NewAutoRefresh(s.state)
+	err := af.Ensure()
+	c.Check(err, IsNil)
+	c.Check(s.store.ops, DeepEquals, []string{"list-refresh"})
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	// only bar is refreshed
+	chgs := s.state.Changes()
+	c.Assert(chgs, HasLen, 1)
+	var names []string
+	c.Assert(chgs[0].Get("snap-names", &names), IsNil)
+	c.Check(names, DeepEquals, []string{"bar"})
+}
+
+func (s *autoRefreshTestSuite) TestSnapRefreshTimerWhileRefreshHeld(c *C) {
+	s.testSnapRefreshTimerIndependentOfSystemTimer(c, map[string]interface{}{
+		"refresh.hold": "forever",
+	})
+}
+
+func (s *autoRefreshTestSuite) TestSnapRefreshTimerWhileRefreshManaged(c *C) {
+	s.testSnapRefreshTimerIndependentOfSystemTimer(c, map[string]interface{}{
+		"refresh.timer": "managed",
+	})
+}
+
 func (s *autoRefreshTestSuite) TestTooSoonError(c *C) {
 	c.Check(snapstate.TooSoonError{}, testutil.ErrorIs, snapstate.TooSoonError{})
 	c.Check(snapstate.TooSoonError{}, Not(testutil.ErrorIs), errors.New(""))
@@ -0,0 +1,155 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+// SnapSpaceEstimate is the disk space an operation is estimated to need for
+// a single snap.
+type SnapSpaceEstimate struct {
+	InstanceName string
+	// Snap is the space needed by the new revision of the snap, which is
+	// downloaded directly into place.
+	Snap uint64
+	// Components is the space needed by the new revisions of the
+	// components of the snap.
+	Components uint64
+	// Snapshot is the space needed by the automatic snapshot of the data
+	// of the snap.
+	Snapshot uint64
+}
+
+// Total returns the total space needed for the snap. Note that the boot
+// assets of kernel and gadget snaps are extracted to the boot partitions
+// rather than to the writable one, so they are not accounted for.
+func (e *SnapSpaceEstimate) Total() uint64 {
+	return e.Snap + e.Components + e.Snapshot
+}
+
+// SpaceEstimate is the disk space an operation is estimated to need.
+type SpaceEstimate struct {
+	Snaps []SnapSpaceEstimate
+	// Prerequisites is the space needed by the bases and default content
+	// providers which are installed together with the snaps.
+	Prerequisites uint64
+}
+
+// Total returns the total space needed by the operation.
+func (e *SpaceEstimate) Total() uint64 {
+	total := e.Prerequisites
+	for i := range e.Snaps {
+		total += e.Snaps[i].Total()
+	}
+	return total
+}
+
+// componentsInstallInfo is implemented by the minimalInstallInfos which
+// carry the components to install together with the snap.
+type componentsInstallInfo interface {
+	componentsDownloadSize() uint64
+}
+
+func (ins installSnapInfo) componentsDownloadSize() uint64 {
+	return componentSetupsDownloadSize(ins.components)
+}
+
+func (rc *refreshCandidate) componentsDownloadSize() uint64 {
+	return componentSetupsDownloadSize(rc.Components)
+}
+
+// componentSetupsDownloadSize returns the size of the components which need
+// to be downloaded.
+func componentSetupsDownloadSize(compsups []ComponentSetup) uint64 {
+	var size uint64
+	for _, compsup := range compsups {
+		if compsup.CompPath == "" && compsup.DownloadInfo != nil {
+			size += uint64(compsup.DownloadInfo.Size)
+		}
+	}
+	return size
+}
+
+// estimateInstallSpace estimates the disk space needed to install or
+// refresh the given snaps, together with their components and the
+// prerequisites which are not installed yet.
+// The state must be locked by the caller.
+func estimateInstallSpace(st *state.State, infos []minimalInstallInfo, userID int, prqt PrereqTracker) (*SpaceEstimate, error) {
+	// installSize accounts for the prerequisites as well
+	downloadSize, err := installSize(st, infos, userID, prqt)
+	if err != nil {
+		return nil, err
+	}
+
+	est := estimateDownloadSpace(infos)
+	var snapsSize uint64
+	for _, snapEst := range est.Snaps {
+		snapsSize += snapEst.Snap
+	}
+	if downloadSize > snapsSize {
+		est.Prerequisites = downloadSize - snapsSize
+	}
+	return est, nil
+}
+
+// estimateDownloadSpace estimates the disk space needed by the given snaps
+// and their components, but not by their prerequisites.
+func estimateDownloadSpace(infos []minimalInstallInfo) *SpaceEstimate {
+	est := &SpaceEstimate{
+		Snaps: make([]SnapSpaceEstimate, 0, len(infos)),
+	}
+	for _, info := range infos {
+		snapEst := SnapSpaceEstimate{
+			InstanceName: info.InstanceName(),
+			Snap:         uint64(info.DownloadSize()),
+		}
+		if comps, ok := info.(componentsInstallInfo); ok {
+			snapEst.Components = comps.componentsDownloadSize()
+		}
+		est.Snaps = append(est.Snaps, snapEst)
+	}
+	return est
+}
+
+// checkSpaceEstimate checks that there is enough space in the given
+// directory for the estimated operation, returning an
+// InsufficientSpaceError with the estimate otherwise.
+func checkSpaceEstimate(est *SpaceEstimate, changeKind, dir string) error {
+	total := est.Total()
+	if err := osutilCheckFreeSpace(dir, safetyMarginDiskSpace(total)); err != nil {
+		if _, ok := err.(*osutil.NotEnoughDiskSpaceError); ok {
+			snaps := make([]string, len(est.Snaps))
+			for i, snapEst := range est.Snaps {
+				snaps[i] = snapEst.InstanceName
+			}
+			return &InsufficientSpaceError{
+				Path:          dir,
+				Snaps:         snaps,
+				ChangeKind:    changeKind,
+				RequiredSpace: total,
+				Estimates:     est.Snaps,
+			}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"context"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/osutil"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/release"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/naming"
+)
+
+func (s *snapmgrTestSuite) mockSpaceInfos() []snapstate.MinimalInstallInfo {
+	app := &snap.Info{
+		SideInfo:     snap.SideInfo{RealName: "some-snap"},
+		SnapType:     snap.TypeApp,
+		DownloadInfo: snap.DownloadInfo{Size: 10},
+	}
+	compsups := []snapstate.ComponentSetup{{
+		CompSideInfo: snap.NewComponentSideInfo(naming.NewComponentRef("some-snap", "comp1"), snap.R(1)),
+		DownloadInfo: &snap.DownloadInfo{Size: 3},
+	}, {
+		// local components need no space beyond what they already use
+		CompSideInfo: snap.NewComponentSideInfo(naming.NewComponentRef("some-snap", "comp2"), snap.R(1)),
+		CompPath:     "/some/path/comp2.comp",
+		DownloadInfo: &snap.DownloadInfo{Size: 100},
+	}}
+	kernel := &snap.Info{
+		SideInfo:     snap.SideInfo{RealName: "kernel"},
+		SnapType:     snap.TypeKernel,
+		DownloadInfo: snap.DownloadInfo{Size: 20},
+	}
+	return []snapstate.MinimalInstallInfo{
+		snapstate.NewInstallSnapInfo(app, compsups),
+		snapstate.NewInstallSnapInfo(kernel, nil),
+	}
+}
+
+func (s *snapmgrTestSuite) TestEstimateInstallSpace(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	restore := snapstate.MockInstallSize(func(st *state.State, snaps []snapstate.MinimalInstallInfo, userID int, prqt snapstate.PrereqTracker) (uint64, error) {
+		c.Check(snaps, HasLen, 2)
+		// 7 bytes of prerequisites on top of the snaps
+		return 10 + 20 + 7, nil
+	})
+	defer restore()
+
+	est, err := snapstate.EstimateInstallSpace(s.state, s.mockSpaceInfos(), 0, nil)
+	c.Assert(err, IsNil)
+	c.Check(est, DeepEquals, &snapstate.SpaceEstimate{
+		Snaps: []snapstate.SnapSpaceEstimate{
+			{InstanceName: "some-snap", Snap: 10, Components: 3},
+			// the kernel assets are extracted to the boot partitions
+			// and are not accounted for
+			{InstanceName: "kernel", Snap: 20},
+		},
+		Prerequisites: 7,
+	})
+	c.Check(est.Snaps[0].Total(), Equals, uint64(13))
+	c.Check(est.Snaps[1].Total(), Equals, uint64(20))
+	c.Check(est.Total(), Equals, uint64(40))
+}
+
+func (s *snapmgrTestSuite) TestInstallDiskSpaceErrorEstimate(c *C) {
+	var checked uint64
+	restore := snapstate.MockOsutilCheckFreeSpace(func(path string, required uint64) error {
+		checked = required
+		return &osutil.NotEnoughDiskSpaceError{}
+	})
+	defer restore()
+	restore = snapstate.MockInstallSize(func(st *state.State, snaps []snapstate.MinimalInstallInfo, userID int, prqt snapstate.PrereqTracker) (uint64, error) {
+		// the snap from the fake store is 5 bytes
+		return 5 + 100, nil
+	})
+	defer restore()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tr := config.NewTransaction(s.state)
+	tr.Set("core", "experimental.check-disk-space-install", true)
+	tr.Commit()
+
+	opts := &snapstate.RevisionOptions{Channel: "some-channel"}
+	_, err := snapstate.Install(context.Background(), s.state, "some-snap", opts, s.user.ID, snapstate.Flags{})
+	diskSpaceErr, ok := err.(*snapstate.InsufficientSpaceError)
+	c.Assert(ok, Equals, true, Commentf("unexpected error: %v", err))
+	c.Check(diskSpaceErr.Path, Equals, filepath.Join(dirs.GlobalRootDir, "/var/lib/snapd"))
+	c.Check(diskSpaceErr.Snaps, DeepEquals, []string{"some-snap"})
+	c.Check(diskSpaceErr.ChangeKind, Equals, "install")
+	c.Check(diskSpaceErr.RequiredSpace, Equals, uint64(105))
+	c.Check(diskSpaceErr.Estimates, DeepEquals, []snapstate.SnapSpaceEstimate{
+		{InstanceName: "some-snap", Snap: 5},
+	})
+	c.Check(checked, Equals, snapstate.SafetyMarginDiskSpace(105))
+}
+
+func (s *snapmgrTestSuite) TestUpdateKernelDiskSpaceCheckNoBootAssets(c *C) {
+	restore := release.MockOnClassic(false)
+	defer restore()
+
+	var checked uint64
+	restore = snapstate.MockOsutilCheckFreeSpace(func(path string, required uint64) error {
+		checked = required
+		// the kernel fits, but not twice
+		if required > snapstate.SafetyMarginDiskSpace(5) {
+			return &osutil.NotEnoughDiskSpaceError{}
+		}
+		return nil
+	})
+	defer restore()
+	restore = snapstate.MockInstallSize(func(st *state.State, snaps []snapstate.MinimalInstallInfo, userID int, prqt snapstate.PrereqTracker) (uint64, error) {
+		c.Assert(snaps, HasLen, 1)
+		c.Check(snaps[0].Type(), Equals, snap.TypeKernel)
+		// the kernel from the fake store is 5 bytes
+		return 5, nil
+	})
+	defer restore()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	tr := config.NewTransaction(s.state)
+	tr.Set("core", "experimental.check-disk-space-refresh", true)
+	tr.Commit()
+
+	snapstate.Set(s.state, "kernel", &snapstate.SnapState{
+		Active: true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "kernel", SnapID: "kernel-id", Revision: snap.R(7)},
+		}),
+		Current:  snap.R(7),
+		SnapType: "kernel",
+	})
+
+	// the kernel assets are extracted to the boot partitions, so they must
+	// not be counted against the space in the writable one
+	updates, _, err := snapstate.UpdateMany(context.Background(), s.state, []string{"kernel"}, nil, s.user.ID, nil)
+	c.Assert(err, IsNil)
+	c.Check(updates, DeepEquals, []string{"kernel"})
+	c.Check(checked, Equals, snapstate.SafetyMarginDiskSpace(5))
+}
@@ -128,6 +128,7 @@ var (
 	HasOtherInstances = hasOtherInstances
 
 	SafetyMarginDiskSpace = safetyMarginDiskSpace
+	EstimateInstallSpace  = estimateInstallSpace
 
 	AffectedByRefresh = affectedByRefresh
 
@@ -458,13 +459,16 @@ var (
 	PruneGating                = pruneGating
 	PruneSnapsHold             = pruneSnapsHold
 	CreateGateAutoRefreshHooks = createGateAutoRefreshHooks
-	AutoRefreshPhase1          = autoRefreshPhase1
 	RefreshRetain              = refreshRetain
 	RefreshCheck               = refreshAppsCheck
 
 	ExcludeFromRefreshAppAwareness = excludeFromRefreshAppAwareness
 )
 
+func AutoRefreshPhase1(ctx context.Context, st *state.State, forGatingSnap string) ([]string, []*state.TaskSet, error) {
+	return autoRefreshPhase1(ctx, st, forGatingSnap, nil)
+}
+
 func MockTimeNow(f func() time.Time) (restore func()) {
 	old := timeNow
 	timeNow = f
@@ -609,3 +613,11 @@ type CustomInstallGoal struct {
 func (c *CustomInstallGoal) toInstall(ctx context.Context, st *state.State, opts Options) ([]Target, error) {
 	return c.ToInstall(ctx, st, opts)
 }
+
+func MockSnapHistoryMax(n int) (restore func()) {
+	return testutil.Mock(&snapHistoryMax, n)
+}
+
+func NewInstallSnapInfo(info *snap.Info, compsups []ComponentSetup) installSnapInfo {
+	return installSnapInfo{Info: info, components: compsups}
+}
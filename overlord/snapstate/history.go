@@ -0,0 +1,280 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"errors"
+	"time"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+)
+
+// snapHistoryMax is the number of history entries kept for each snap.
+var snapHistoryMax = 50
+
+const (
+	HistoryActionInstall = "install"
+	HistoryActionRefresh = "refresh"
+	HistoryActionRevert  = "revert"
+	HistoryActionSwitch  = "switch"
+	HistoryActionRemove  = "remove"
+)
+
+const (
+	HistoryTriggerManual        = "manual"
+	HistoryTriggerAuto          = "auto"
+	HistoryTriggerValidationSet = "validation-set"
+	HistoryTriggerRemodel       = "remodel"
+	HistoryTriggerSeed          = "seed"
+)
+
+const (
+	HistoryOutcomeSucceeded = "succeeded"
+	HistoryOutcomeFailed    = "failed"
+)
+
+// HistoryEntry records an operation carried out on a snap.
+type HistoryEntry struct {
+	Action string `json:"action"`
+	// FromRevision is unset for installs, ToRevision for removals.
+	FromRevision snap.Revision `json:"from-revision"`
+	ToRevision   snap.Revision `json:"to-revision"`
+	Channel      string        `json:"channel,omitempty"`
+	Time         time.Time     `json:"time"`
+	Trigger      string        `json:"trigger"`
+	Outcome      string        `json:"outcome"`
+	ChangeID     string        `json:"change-id"`
+}
+
+// SetHistoryTrigger overrides the trigger recorded in the snap history for
+// the operations of the given change, which is otherwise derived from the
+// kind of the change.
+func SetHistoryTrigger(chg *state.Change, trigger string) {
+	chg.Set("history-trigger", trigger)
+}
+
+// SnapHistory returns the recorded history of the given snap, oldest entry
+// first. The history is kept after the snap is removed. The returned error
+// is state.ErrNoState if the snap is neither installed nor has any history.
+func SnapHistory(st *state.State, instanceName string) ([]*HistoryEntry, error) {
+	history, err := allSnapHistory(st)
+	if err != nil {
+		return nil, err
+	}
+	if len(history[instanceName]) == 0 {
+		var snapst SnapState
+		if err := Get(st, instanceName, &snapst); err != nil {
+			return nil, err
+		}
+	}
+	return history[instanceName], nil
+}
+
+func allSnapHistory(st *state.State) (map[string][]*HistoryEntry, error) {
+	var history map[string][]*HistoryEntry
+	if err := st.Get("snap-history", &history); err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	return history, nil
+}
+
+func addSnapHistory(st *state.State, entries map[string]*HistoryEntry) error {
+	history, err := allSnapHistory(st)
+	if err != nil {
+		return err
+	}
+	if history == nil {
+		history = make(map[string][]*HistoryEntry, len(entries))
+	}
+	for name, e := range entries {
+		snapHistory := append(history[name], e)
+		if len(snapHistory) > snapHistoryMax {
+			snapHistory = snapHistory[len(snapHistory)-snapHistoryMax:]
+		}
+		history[name] = snapHistory
+	}
+	st.Set("snap-history", history)
+	return nil
+}
+
+func historyTrigger(chg *state.Change) string {
+	var trigger string
+	if err := chg.Get("history-trigger", &trigger); err == nil && trigger != "" {
+		return trigger
+	}
+	switch chg.Kind() {
+	case "auto-refresh":
+		return HistoryTriggerAuto
+	case "remodel":
+		return HistoryTriggerRemodel
+	case "seed":
+		return HistoryTriggerSeed
+	}
+	for _, t := range chg.Tasks() {
+		if t.Kind() == "enforce-validation-sets" {
+			return HistoryTriggerValidationSet
+		}
+	}
+	return HistoryTriggerManual
+}
+
+// historyTasks are the tasks of a change which determine the history entry
+// of a snap.
+type historyTasks struct {
+	link, switchChannel, unlink, discard *state.Task
+}
+
+func (ht *historyTasks) main() *state.Task {
+	switch {
+	case ht.link != nil:
+		return ht.link
+	case ht.switchChannel != nil:
+		return ht.switchChannel
+	default:
+		return ht.discard
+	}
+}
+
+func historyEntryForTasks(st *state.State, ht *historyTasks) (*HistoryEntry, error) {
+	t := ht.main()
+	if t == nil {
+		// for example a disabled snap
+		return nil, nil
+	}
+	snapsup, err := TaskSnapSetup(t)
+	if err != nil {
+		return nil, err
+	}
+	e := &HistoryEntry{
+		Outcome: HistoryOutcomeSucceeded,
+	}
+	if t.Status() != state.DoneStatus {
+		e.Outcome = HistoryOutcomeFailed
+	}
+
+	switch t {
+	case ht.link:
+		e.ToRevision = snapsup.Revision()
+		e.Channel = snapsup.Channel
+		// old-current is only known once the snap was linked
+		if err := t.Get("old-current", &e.FromRevision); err != nil {
+			if !errors.Is(err, state.ErrNoState) {
+				return nil, err
+			}
+			var snapst SnapState
+			if err := Get(st, snapsup.InstanceName(), &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+				return nil, err
+			}
+			if snapst.Current != e.ToRevision {
+				e.FromRevision = snapst.Current
+			}
+		}
+		switch {
+		case snapsup.Revert:
+			e.Action = HistoryActionRevert
+		case e.FromRevision.Unset():
+			e.Action = HistoryActionInstall
+		default:
+			e.Action = HistoryActionRefresh
+		}
+	case ht.switchChannel:
+		e.Action = HistoryActionSwitch
+		e.FromRevision = snapsup.Revision()
+		e.ToRevision = snapsup.Revision()
+		e.Channel = snapsup.Channel
+	default:
+		e.Action = HistoryActionRemove
+		if ht.unlink != nil {
+			if snapsup, err = TaskSnapSetup(ht.unlink); err != nil {
+				return nil, err
+			}
+		}
+		e.FromRevision = snapsup.Revision()
+	}
+	return e, nil
+}
+
+// processSnapHistory records the operations carried out on snaps by the
+// given change in their history once it is ready.
+func processSnapHistory(chg *state.Change, old, new state.Status) {
+	if !new.Ready() || old.Ready() {
+		return
+	}
+
+	snaps := make(map[string]*historyTasks)
+	for _, t := range chg.Tasks() {
+		switch t.Kind() {
+		case "link-snap", "switch-snap-channel", "switch-snap", "unlink-snap", "discard-snap":
+		default:
+			continue
+		}
+		snapsup, err := TaskSnapSetup(t)
+		if err != nil {
+			logger.Debugf("internal error: cannot get snap associated with task %s: %v", t.ID(), err)
+			continue
+		}
+		ht := snaps[snapsup.InstanceName()]
+		if ht == nil {
+			ht = &historyTasks{}
+			snaps[snapsup.InstanceName()] = ht
+		}
+		var first **state.Task
+		switch t.Kind() {
+		case "link-snap":
+			first = &ht.link
+		case "switch-snap-channel", "switch-snap":
+			first = &ht.switchChannel
+		case "unlink-snap":
+			first = &ht.unlink
+		case "discard-snap":
+			first = &ht.discard
+		}
+		if *first == nil {
+			*first = t
+		}
+	}
+	if len(snaps) == 0 {
+		return
+	}
+
+	st := chg.State()
+	now := timeNow()
+	trigger := historyTrigger(chg)
+	entries := make(map[string]*HistoryEntry, len(snaps))
+	for name, ht := range snaps {
+		e, err := historyEntryForTasks(st, ht)
+		if err != nil {
+			logger.Noticef("cannot record history of snap %q: %v", name, err)
+			continue
+		}
+		if e == nil {
+			continue
+		}
+		e.Time = now
+		e.Trigger = trigger
+		e.ChangeID = chg.ID()
+		entries[name] = e
+	}
+	if err := addSnapHistory(st, entries); err != nil {
+		logger.Noticef("cannot record snap history: %v", err)
+	}
+}
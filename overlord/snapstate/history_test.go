@@ -0,0 +1,158 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"context"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/dirs"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/snaptest"
+	"github.com/snapcore/snapd/testutil"
+)
+
+type historyEntry struct {
+	action, from, to, channel, trigger, outcome string
+}
+
+func checkSnapHistory(c *C, st *state.State, name string, expected []historyEntry) {
+	history, err := snapstate.SnapHistory(st, name)
+	c.Assert(err, IsNil)
+	c.Assert(history, HasLen, len(expected))
+	for i, e := range history {
+		c.Check(historyEntry{
+			action:  e.Action,
+			from:    e.FromRevision.String(),
+			to:      e.ToRevision.String(),
+			channel: e.Channel,
+			trigger: e.Trigger,
+			outcome: e.Outcome,
+		}, Equals, expected[i], Commentf("entry %d", i))
+		c.Check(e.Time.IsZero(), Equals, false)
+		c.Check(e.ChangeID, Not(Equals), "")
+	}
+}
+
+func (s *snapmgrTestSuite) TestSnapHistoryInstall(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	_, err := snapstate.SnapHistory(s.state, "some-snap")
+	c.Check(err, testutil.ErrorIs, state.ErrNoState)
+
+	ts, err := snapstate.Install(context.Background(), s.state, "some-snap", &snapstate.RevisionOptions{Channel: "some-channel"}, s.user.ID, snapstate.Flags{})
+	c.Assert(err, IsNil)
+	s.state.NewChange("install-snap", "...").AddAll(ts)
+	s.settle(c)
+
+	checkSnapHistory(c, s.state, "some-snap", []historyEntry{
+		{"install", "unset", "11", "some-channel", "manual", "succeeded"},
+	})
+}
+
+func (s *snapmgrTestSuite) mockSnapForHistory(c *C) {
+	si := &snap.SideInfo{
+		RealName: "some-snap",
+		SnapID:   "some-snap-id",
+		Revision: snap.R(7),
+	}
+	snaptest.MockSnap(c, "name: some-snap\nversion: 1", si)
+	snapstate.Set(s.state, "some-snap", &snapstate.SnapState{
+		Active:          true,
+		Sequence:        snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{si}),
+		Current:         si.Revision,
+		SnapType:        "app",
+		TrackingChannel: "latest/stable",
+	})
+}
+
+func (s *snapmgrTestSuite) refreshRevertRemoveForHistory(c *C) {
+	ts, err := snapstate.Update(s.state, "some-snap", &snapstate.RevisionOptions{Channel: "some-channel"}, s.user.ID, snapstate.Flags{})
+	c.Assert(err, IsNil)
+	s.state.NewChange("auto-refresh", "...").AddAll(ts)
+	s.settle(c)
+
+	ts, err = snapstate.Revert(s.state, "some-snap", snapstate.Flags{}, "")
+	c.Assert(err, IsNil)
+	chg := s.state.NewChange("revert-snap", "...")
+	chg.AddAll(ts)
+	snapstate.SetHistoryTrigger(chg, snapstate.HistoryTriggerAuto)
+	s.settle(c)
+
+	ts, err = snapstate.Remove(s.state, "some-snap", snap.R(0), nil)
+	c.Assert(err, IsNil)
+	s.state.NewChange("remove-snap", "...").AddAll(ts)
+	s.settle(c)
+}
+
+func (s *snapmgrTestSuite) TestSnapHistory(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapForHistory(c)
+	s.refreshRevertRemoveForHistory(c)
+
+	// the history outlives the snap
+	checkSnapHistory(c, s.state, "some-snap", []historyEntry{
+		{"refresh", "7", "11", "some-channel", "auto", "succeeded"},
+		{"revert", "11", "7", "", "auto", "succeeded"},
+		{"remove", "7", "unset", "", "manual", "succeeded"},
+	})
+}
+
+func (s *snapmgrTestSuite) TestSnapHistoryIsBounded(c *C) {
+	defer snapstate.MockSnapHistoryMax(2)()
+
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapForHistory(c)
+	s.refreshRevertRemoveForHistory(c)
+
+	checkSnapHistory(c, s.state, "some-snap", []historyEntry{
+		{"revert", "11", "7", "", "auto", "succeeded"},
+		{"remove", "7", "unset", "", "manual", "succeeded"},
+	})
+}
+
+func (s *snapmgrTestSuite) TestSnapHistoryFailedRefresh(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.mockSnapForHistory(c)
+
+	s.fakeBackend.linkSnapFailTrigger = filepath.Join(dirs.SnapMountDir, "some-snap/11")
+	ts, err := snapstate.Update(s.state, "some-snap", &snapstate.RevisionOptions{Channel: "some-channel"}, s.user.ID, snapstate.Flags{})
+	c.Assert(err, IsNil)
+	chg := s.state.NewChange("refresh-snap", "...")
+	chg.AddAll(ts)
+	s.settle(c)
+	c.Assert(chg.Status(), Equals, state.ErrorStatus)
+
+	checkSnapHistory(c, s.state, "some-snap", []historyEntry{
+		{"refresh", "7", "11", "some-channel", "manual", "failed"},
+	})
+}
@@ -0,0 +1,199 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/snapcore/snapd/boot"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/naming"
+)
+
+// RefreshPlanItem describes how a single snap would be refreshed.
+type RefreshPlanItem struct {
+	InstanceName string
+	Type         snap.Type
+
+	CurrentRevision snap.Revision
+	CurrentChannel  string
+
+	Revision snap.Revision
+	Channel  string
+	Version  string
+	// DownloadSize is the size of the snap and of the components that
+	// need to be downloaded, zero if nothing needs to be.
+	DownloadSize int64
+	// Components are the components which would be installed or
+	// refreshed together with the snap.
+	Components []string
+	// Prerequisites are the snaps which are not installed yet and would
+	// be installed as default content providers of the snap.
+	Prerequisites []string
+	// NeedsReboot is set if refreshing the snap requires a reboot of
+	// the system.
+	NeedsReboot bool
+	// ValidationSets are the enforced validation sets which constrain
+	// the revision of the snap.
+	ValidationSets []string
+	// Space is the disk space the refresh is estimated to need, as
+	// used by the disk space checks.
+	Space SnapSpaceEstimate
+}
+
+// RefreshPlan describes what refreshing snaps would do.
+type RefreshPlan struct {
+	Refreshes []*RefreshPlanItem
+	// Held maps the snaps which currently have their auto-refreshes held,
+	// by gate-auto-refresh hooks or by the user, to the holding snaps.
+	Held map[string][]string
+	// RequiredSpace is the disk space all the refreshes are estimated to
+	// need.
+	RequiredSpace uint64
+}
+
+// RefreshPlanFromTaskSets describes the refreshes that the given task sets,
+// as created by the update functions, would carry out. The task sets must not
+// have been added to a change: they are only inspected, and then discarded
+// from the state, whether or not the plan could be computed.
+// Note that the state must be locked by the caller.
+func RefreshPlanFromTaskSets(st *state.State, tss []*state.TaskSet) (*RefreshPlan, error) {
+	defer discardTaskSets(st, tss)
+
+	deviceCtx, err := DeviceCtx(st, nil, nil)
+	if err != nil && !errors.Is(err, state.ErrNoState) {
+		return nil, err
+	}
+	enforced, err := EnforcedValidationSets(st)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string]*RefreshPlanItem)
+	item := func(snapsup *SnapSetup) (*RefreshPlanItem, error) {
+		name := snapsup.InstanceName()
+		if it := items[name]; it != nil {
+			return it, nil
+		}
+		it := &RefreshPlanItem{
+			InstanceName: name,
+			Type:         snapsup.Type,
+			Revision:     snapsup.Revision(),
+			Channel:      snapsup.Channel,
+			Version:      snapsup.Version,
+		}
+		var snapst SnapState
+		if err := Get(st, name, &snapst); err != nil && !errors.Is(err, state.ErrNoState) {
+			return nil, err
+		}
+		it.CurrentRevision = snapst.Current
+		it.CurrentChannel = snapst.TrackingChannel
+		if snapsup.DownloadInfo != nil && snapsup.SnapPath == "" {
+			it.DownloadSize += snapsup.DownloadInfo.Size
+			it.Space.Snap = uint64(snapsup.DownloadInfo.Size)
+		}
+		for _, prereq := range snapsup.Prereq {
+			var prereqst SnapState
+			err := Get(st, prereq, &prereqst)
+			if errors.Is(err, state.ErrNoState) {
+				it.Prerequisites = append(it.Prerequisites, prereq)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if deviceCtx != nil && deviceCtx.RunMode() {
+			it.NeedsReboot = boot.SnapTypeParticipatesInBoot(snapsup.Type, deviceCtx)
+		}
+		if enforced != nil {
+			pres, err := enforced.Presence(naming.Snap(snapsup.SnapName()))
+			if err != nil {
+				return nil, err
+			}
+			if pres.Constrained() {
+				for _, key := range pres.Sets {
+					it.ValidationSets = append(it.ValidationSets, key.String())
+				}
+			}
+		}
+		items[name] = it
+		return it, nil
+	}
+
+	for _, ts := range tss {
+		for _, t := range ts.Tasks() {
+			if t.Has("snap-setup") {
+				var snapsup SnapSetup
+				if err := t.Get("snap-setup", &snapsup); err != nil {
+					return nil, err
+				}
+				if _, err := item(&snapsup); err != nil {
+					return nil, err
+				}
+			}
+			if t.Has("component-setup") {
+				compsup, snapsup, err := TaskComponentSetup(t)
+				if err != nil {
+					return nil, err
+				}
+				it, err := item(snapsup)
+				if err != nil {
+					return nil, err
+				}
+				if compsup.CompSideInfo != nil {
+					it.Components = append(it.Components, compsup.CompSideInfo.Component.ComponentName)
+				}
+				if compsup.DownloadInfo != nil && compsup.CompPath == "" {
+					it.DownloadSize += compsup.DownloadInfo.Size
+					it.Space.Components += uint64(compsup.DownloadInfo.Size)
+				}
+			}
+		}
+	}
+
+	plan := &RefreshPlan{
+		Refreshes: make([]*RefreshPlanItem, 0, len(items)),
+	}
+	for _, it := range items {
+		sort.Strings(it.Components)
+		plan.Refreshes = append(plan.Refreshes, it)
+		plan.RequiredSpace += it.Space.Total()
+	}
+	sort.Slice(plan.Refreshes, func(i, j int) bool {
+		return plan.Refreshes[i].InstanceName < plan.Refreshes[j].InstanceName
+	})
+
+	plan.Held, err = HeldSnaps(st, HoldAutoRefresh)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// discardTaskSets removes the tasks of the given task sets, which must not
+// have been added to a change, from the state.
+func discardTaskSets(st *state.State, tss []*state.TaskSet) {
+	for _, ts := range tss {
+		st.DiscardTasks(ts.Tasks()...)
+	}
+}
@@ -0,0 +1,129 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"context"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/snap/naming"
+)
+
+func (s *snapmgrTestSuite) TestRefreshPlanFromTaskSets(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	lastRefresh := time.Now().Add(-time.Hour)
+	snapstate.Set(s.state, "some-snap", &snapstate.SnapState{
+		Active: true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "some-snap", SnapID: "some-snap-id", Revision: snap.R(1)},
+		}),
+		Current:         snap.R(1),
+		SnapType:        "app",
+		TrackingChannel: "latest/stable",
+		LastRefreshTime: &lastRefresh,
+	})
+
+	_, tss, err := snapstate.UpdateMany(context.Background(), s.state, []string{"some-snap"}, nil, 0, nil)
+	c.Assert(err, IsNil)
+	tasks := s.state.TaskCount()
+	c.Assert(tasks > 0, Equals, true)
+
+	plan, err := snapstate.RefreshPlanFromTaskSets(s.state, tss)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Refreshes, HasLen, 1)
+	c.Check(plan.Refreshes[0], DeepEquals, &snapstate.RefreshPlanItem{
+		InstanceName:    "some-snap",
+		Type:            snap.TypeApp,
+		CurrentRevision: snap.R(1),
+		CurrentChannel:  "latest/stable",
+		Revision:        snap.R(11),
+		Channel:         "latest/stable",
+		Version:         "some-snapVer",
+	})
+	c.Check(plan.Held, HasLen, 0)
+	// the tasks were thrown away
+	c.Check(s.state.TaskCount(), Equals, 0)
+
+	// holds are reported
+	c.Assert(snapstate.HoldRefreshesBySystem(s.state, snapstate.HoldAutoRefresh, "forever", []string{"some-snap"}), IsNil)
+	_, tss, err = snapstate.UpdateMany(context.Background(), s.state, []string{"some-snap"}, nil, 0, nil)
+	c.Assert(err, IsNil)
+	plan, err = snapstate.RefreshPlanFromTaskSets(s.state, tss)
+	c.Assert(err, IsNil)
+	c.Check(plan.Held, DeepEquals, map[string][]string{"some-snap": {"system"}})
+	c.Check(s.state.TaskCount(), Equals, 0)
+}
+
+func (s *snapmgrTestSuite) TestRefreshPlanFromTaskSetsErrorDiscardsTasks(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	prereq := s.state.NewTask("prerequisites", "...")
+	prereq.Set("snap-setup", "not-a-snap-setup")
+	link := s.state.NewTask("link-snap", "...")
+	tss := []*state.TaskSet{state.NewTaskSet(prereq, link)}
+
+	_, err := snapstate.RefreshPlanFromTaskSets(s.state, tss)
+	c.Assert(err, NotNil)
+	// the tasks are thrown away even though no plan could be computed
+	c.Check(s.state.TaskCount(), Equals, 0)
+}
+
+func (s *snapmgrTestSuite) TestRefreshPlanFromTaskSetsRequiredSpace(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	snapsup := &snapstate.SnapSetup{
+		SideInfo:     &snap.SideInfo{RealName: "kernel", SnapID: "kernel-id", Revision: snap.R(7)},
+		Type:         snap.TypeKernel,
+		DownloadInfo: &snap.DownloadInfo{Size: 20},
+	}
+	prereq := s.state.NewTask("prerequisites", "...")
+	prereq.Set("snap-setup", snapsup)
+	prepareComp := s.state.NewTask("prepare-component", "...")
+	prepareComp.Set("snap-setup", snapsup)
+	prepareComp.Set("component-setup", &snapstate.ComponentSetup{
+		CompSideInfo: snap.NewComponentSideInfo(naming.NewComponentRef("kernel", "wifi"), snap.R(2)),
+		DownloadInfo: &snap.DownloadInfo{Size: 3},
+	})
+	tss := []*state.TaskSet{state.NewTaskSet(prereq, prepareComp)}
+
+	plan, err := snapstate.RefreshPlanFromTaskSets(s.state, tss)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Refreshes, HasLen, 1)
+	c.Check(plan.Refreshes[0].DownloadSize, Equals, int64(23))
+	c.Check(plan.Refreshes[0].Components, DeepEquals, []string{"wifi"})
+	// the kernel assets are extracted to the boot partitions, so they do not
+	// count towards the space needed
+	c.Check(plan.Refreshes[0].Space, DeepEquals, snapstate.SnapSpaceEstimate{
+		Snap:       20,
+		Components: 3,
+	})
+	c.Check(plan.RequiredSpace, Equals, uint64(23))
+	c.Check(s.state.TaskCount(), Equals, 0)
+}
@@ -0,0 +1,191 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/snapcore/snapd/logger"
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/snapstate/sequence"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/strutil"
+)
+
+// SnapshotRevisions allows to hook snapshot manager's SnapshotRevisions, to
+// find the revisions of the snaps which snapshots were taken of, by instance
+// name. It does not need the state to be locked.
+var SnapshotRevisions func(st *state.State) (map[string][]snap.Revision, error)
+
+// revisionRetention is the policy, on top of refresh.retain, according to
+// which inactive revisions of snaps are garbage collected.
+type revisionRetention struct {
+	// maxAge is how long an inactive revision is kept after it was
+	// installed, unless a snapshot was taken of it.
+	maxAge time.Duration
+	// maxSize is the total size of the revisions of a snap that is kept,
+	// older inactive revisions are removed first.
+	maxSize int64
+}
+
+// revisionRetentionPolicy returns the refresh.retain-max-age and
+// refresh.retain-max-size policy, or nil if neither is set.
+func revisionRetentionPolicy(st *state.State) *revisionRetention {
+	tr := config.NewTransaction(st)
+	var p revisionRetention
+	var maxAgeStr, maxSizeStr string
+	if err := tr.Get("core", "refresh.retain-max-age", &maxAgeStr); err != nil && !config.IsNoOption(err) {
+		logger.Noticef("internal error: refresh.retain-max-age system option is not valid: %v", err)
+	}
+	if maxAgeStr != "" {
+		maxAge, err := time.ParseDuration(maxAgeStr)
+		if err != nil {
+			logger.Noticef("cannot use refresh.retain-max-age system option: %v", err)
+		} else {
+			p.maxAge = maxAge
+		}
+	}
+	if err := tr.Get("core", "refresh.retain-max-size", &maxSizeStr); err != nil && !config.IsNoOption(err) {
+		logger.Noticef("internal error: refresh.retain-max-size system option is not valid: %v", err)
+	}
+	if maxSizeStr != "" {
+		maxSize, err := strutil.ParseByteSize(maxSizeStr)
+		if err != nil {
+			logger.Noticef("cannot use refresh.retain-max-size system option: %v", err)
+		} else {
+			p.maxSize = maxSize
+		}
+	}
+	if p.maxAge == 0 && p.maxSize == 0 {
+		return nil
+	}
+	return &p
+}
+
+// snapshotRevisions returns the revisions of the snaps which snapshots were
+// taken of, if the policy needs them.
+func (p *revisionRetention) snapshotRevisions(st *state.State) (map[string][]snap.Revision, error) {
+	if p.maxAge == 0 || SnapshotRevisions == nil {
+		return nil, nil
+	}
+	return SnapshotRevisions(st)
+}
+
+// removalTimes returns, for the inactive revisions before currentIndex in
+// seq, the time at which they become eligible for removal according to the
+// policy. Revisions which are kept are not included. extraSize accounts for
+// a revision that is being added, snapshotted are the revisions which
+// snapshots were taken of.
+func (p *revisionRetention) removalTimes(instanceName string, seq []*sequence.RevisionSideState, currentIndex int, extraSize int64, snapshotted []snap.Revision) (map[snap.Revision]time.Time, error) {
+	times := make(map[snap.Revision]time.Time)
+	totalSize := extraSize
+	for i := currentIndex; i >= 0; i-- {
+		rev := seq[i].Snap.Revision
+		fi, err := os.Stat(snap.MinimalPlaceInfo(instanceName, rev).MountFile())
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		totalSize += fi.Size()
+		if i == currentIndex {
+			continue
+		}
+		switch {
+		case p.maxSize > 0 && totalSize > p.maxSize:
+			// the size limit is not soft, snapshots do not matter
+			times[rev] = fi.ModTime()
+		case p.maxAge > 0 && !revisionInList(rev, snapshotted):
+			times[rev] = fi.ModTime().Add(p.maxAge)
+		}
+	}
+	return times, nil
+}
+
+func revisionInList(rev snap.Revision, revs []snap.Revision) bool {
+	for _, r := range revs {
+		if r == rev {
+			return true
+		}
+	}
+	return false
+}
+
+// RevisionRemovalTimes returns, by instance name, the time at which the
+// inactive revisions of the installed snaps become eligible for removal by
+// the garbage collection done when the snaps are next refreshed, according
+// to refresh.retain and to the refresh.retain-max-age and
+// refresh.retain-max-size system options. Revisions which are kept are not
+// included.
+// The state must be locked by the caller, it is released while going
+// through the existing snapshots.
+func RevisionRemovalTimes(st *state.State) (map[string]map[snap.Revision]time.Time, error) {
+	p := revisionRetentionPolicy(st)
+	var snapshotted map[string][]snap.Revision
+	if p != nil {
+		// going through all the snapshots can take a while
+		st.Unlock()
+		var err error
+		snapshotted, err = p.snapshotRevisions(st)
+		st.Lock()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	all, err := All(st)
+	if err != nil {
+		return nil, err
+	}
+	now := timeNow()
+	retain := refreshRetain(st) - 1
+	allTimes := make(map[string]map[snap.Revision]time.Time, len(all))
+	for instanceName, snapst := range all {
+		seq := snapst.Sequence.Revisions
+		currentIndex := snapst.LastIndex(snapst.Current)
+		if currentIndex < 0 {
+			continue
+		}
+
+		times := make(map[snap.Revision]time.Time)
+		if p != nil {
+			times, err = p.removalTimes(instanceName, seq, currentIndex, 0, snapshotted[instanceName])
+			if err != nil {
+				return nil, err
+			}
+		}
+		// the revisions over refresh.retain, which can be lowered at any
+		// time, are removed regardless; note that the revision being added
+		// on refresh counts towards it too
+		for i := 0; i <= currentIndex-retain; i++ {
+			times[seq[i].Snap.Revision] = now
+		}
+		// revisions after current are always removed on refresh
+		for i := currentIndex + 1; i < len(seq); i++ {
+			times[seq[i].Snap.Revision] = now
+		}
+		allTimes[instanceName] = times
+	}
+	return allTimes, nil
+}
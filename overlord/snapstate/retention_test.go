@@ -0,0 +1,208 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package snapstate_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/configstate/config"
+	"github.com/snapcore/snapd/overlord/snapstate"
+	"github.com/snapcore/snapd/overlord/snapstate/snapstatetest"
+	"github.com/snapcore/snapd/overlord/state"
+	"github.com/snapcore/snapd/snap"
+	"github.com/snapcore/snapd/testutil"
+)
+
+// mockRevisionsForRetention sets up some-snap with revisions 1 to 3, 3 being
+// current, whose snap files are of the given size and were installed the
+// given number of days ago.
+func (s *snapmgrTestSuite) mockRevisionsForRetention(c *C, size int, daysAgo ...int) {
+	var sis []*snap.SideInfo
+	for i, days := range daysAgo {
+		si := &snap.SideInfo{RealName: "some-snap", SnapID: "some-snap-id", Channel: "some-channel", Revision: snap.R(i + 1)}
+		sis = append(sis, si)
+
+		mountFile := snap.MinimalPlaceInfo("some-snap", si.Revision).MountFile()
+		c.Assert(os.MkdirAll(filepath.Dir(mountFile), 0755), IsNil)
+		c.Assert(os.WriteFile(mountFile, make([]byte, size), 0644), IsNil)
+		mtime := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+		c.Assert(os.Chtimes(mountFile, mtime, mtime), IsNil)
+	}
+	snapstate.Set(s.state, "some-snap", &snapstate.SnapState{
+		Active:   true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos(sis),
+		Current:  sis[len(sis)-1].Revision,
+		SnapType: "app",
+	})
+}
+
+func (s *snapmgrTestSuite) setCoreConfig(c *C, conf map[string]interface{}) {
+	tr := config.NewTransaction(s.state)
+	for k, v := range conf {
+		c.Assert(tr.Set("core", k, v), IsNil)
+	}
+	tr.Commit()
+}
+
+func (s *snapmgrTestSuite) refreshForRetention(c *C) []snap.Revision {
+	s.fakeStore.refreshRevnos = map[string]snap.Revision{"some-snap-id": snap.R(4)}
+	ts, err := snapstate.Update(s.state, "some-snap", &snapstate.RevisionOptions{Channel: "some-channel"}, s.user.ID, snapstate.Flags{})
+	c.Assert(err, IsNil)
+	chg := s.state.NewChange("refresh-snap", "...")
+	chg.AddAll(ts)
+	s.settle(c)
+	c.Assert(chg.Err(), IsNil)
+
+	var snapst snapstate.SnapState
+	c.Assert(snapstate.Get(s.state, "some-snap", &snapst), IsNil)
+	var revs []snap.Revision
+	for _, si := range snapst.Sequence.SideInfos() {
+		revs = append(revs, si.Revision)
+	}
+	return revs
+}
+
+func (s *snapmgrTestSuite) TestRetentionMaxAge(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setCoreConfig(c, map[string]interface{}{
+		"refresh.retain":         5,
+		"refresh.retain-max-age": "336h",
+	})
+	s.mockRevisionsForRetention(c, 10, 30, 1, 0)
+
+	c.Check(s.refreshForRetention(c), DeepEquals, []snap.Revision{snap.R(2), snap.R(3), snap.R(4)})
+}
+
+func (s *snapmgrTestSuite) TestRetentionMaxAgeKeepsSnapshotted(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	restore := testutil.Mock(&snapstate.SnapshotRevisions, func(st *state.State) (map[string][]snap.Revision, error) {
+		return map[string][]snap.Revision{
+			"some-snap":  {snap.R(1)},
+			"other-snap": {snap.R(2)},
+		}, nil
+	})
+	defer restore()
+
+	s.setCoreConfig(c, map[string]interface{}{
+		"refresh.retain":         5,
+		"refresh.retain-max-age": "336h",
+	})
+	s.mockRevisionsForRetention(c, 10, 30, 20, 0)
+
+	c.Check(s.refreshForRetention(c), DeepEquals, []snap.Revision{snap.R(1), snap.R(3), snap.R(4)})
+}
+
+func (s *snapmgrTestSuite) TestRetentionMaxSize(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	s.setCoreConfig(c, map[string]interface{}{
+		"refresh.retain":          5,
+		"refresh.retain-max-size": "250B",
+	})
+	s.mockRevisionsForRetention(c, 100, 3, 2, 1)
+
+	c.Check(s.refreshForRetention(c), DeepEquals, []snap.Revision{snap.R(2), snap.R(3), snap.R(4)})
+}
+
+func (s *snapmgrTestSuite) TestRevisionRemovalTimes(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	now := time.Now()
+	restore := snapstate.MockTimeNow(func() time.Time { return now })
+	defer restore()
+
+	s.setCoreConfig(c, map[string]interface{}{
+		"refresh.retain":         3,
+		"refresh.retain-max-age": "240h",
+	})
+	s.mockRevisionsForRetention(c, 10, 30, 1, 0)
+
+	allTimes, err := snapstate.RevisionRemovalTimes(s.state)
+	c.Assert(err, IsNil)
+	times := allTimes["some-snap"]
+	c.Assert(times, HasLen, 2)
+	// over refresh.retain, counting the next revision
+	c.Check(times[snap.R(1)].Equal(now), Equals, true)
+	// nine days to go
+	c.Check(times[snap.R(2)].Sub(now).Round(time.Hour), Equals, 9*24*time.Hour)
+
+	// without a policy only refresh.retain applies
+	s.setCoreConfig(c, map[string]interface{}{
+		"refresh.retain":         4,
+		"refresh.retain-max-age": nil,
+	})
+	allTimes, err = snapstate.RevisionRemovalTimes(s.state)
+	c.Assert(err, IsNil)
+	c.Check(allTimes["some-snap"], HasLen, 0)
+}
+
+func (s *snapmgrTestSuite) TestRevisionRemovalTimesSnapshotsWithoutLock(c *C) {
+	s.state.Lock()
+	defer s.state.Unlock()
+
+	now := time.Now()
+	restore := snapstate.MockTimeNow(func() time.Time { return now })
+	defer restore()
+
+	calls := 0
+	restore = testutil.Mock(&snapstate.SnapshotRevisions, func(st *state.State) (map[string][]snap.Revision, error) {
+		calls++
+		// the state is not locked while going through the snapshots
+		st.Lock()
+		defer st.Unlock()
+		return map[string][]snap.Revision{"some-snap": {snap.R(2)}}, nil
+	})
+	defer restore()
+
+	s.setCoreConfig(c, map[string]interface{}{
+		"refresh.retain":         3,
+		"refresh.retain-max-age": "240h",
+	})
+	s.mockRevisionsForRetention(c, 10, 30, 20, 0)
+	snapstate.Set(s.state, "other-snap", &snapstate.SnapState{
+		Active: true,
+		Sequence: snapstatetest.NewSequenceFromSnapSideInfos([]*snap.SideInfo{
+			{RealName: "other-snap", SnapID: "other-snap-id", Revision: snap.R(1)},
+		}),
+		Current:  snap.R(1),
+		SnapType: "app",
+	})
+
+	allTimes, err := snapstate.RevisionRemovalTimes(s.state)
+	c.Assert(err, IsNil)
+	// the snapshots are gone through once for all the snaps
+	c.Check(calls, Equals, 1)
+	// revision 2 is kept as it was snapshotted
+	c.Check(allTimes["some-snap"], HasLen, 1)
+	c.Check(allTimes["some-snap"][snap.R(1)].Equal(now), Equals, true)
+	otherTimes, ok := allTimes["other-snap"]
+	c.Check(ok, Equals, true)
+	c.Check(otherTimes, HasLen, 0)
+}
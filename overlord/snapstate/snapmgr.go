@@ -873,6 +873,8 @@ func (m *SnapManager) StartUp() error {
 		processInhibitedAutoRefresh(chg, old, new)
 		// This handler implements marks failed snaps auto-refresh attempts for backoff.
 		processFailedAutoRefresh(chg, old, new)
+		// This handler records the snap operations of the change in the snap history.
+		processSnapHistory(chg, old, new)
 	})
 
 	if CheckExpectedRestart(m.state) == ErrUnexpectedRuntimeRestart {
@@ -955,6 +957,13 @@ func (m *SnapManager) LastRefresh() (time.Time, error) {
 	return m.autoRefresh.LastRefresh()
 }
 
+// SnapRefreshSchedules returns the refresh schedules of the installed snaps
+// which are auto-refreshed on their own timer instead of the system one.
+// The caller should be holding the state lock.
+func (m *SnapManager) SnapRefreshSchedules() (map[string]*SnapRefreshSchedule, error) {
+	return m.autoRefresh.SnapRefreshSchedules()
+}
+
 // RefreshSchedule returns the current refresh schedule as a string suitable for
 // display to a user and a flag indicating whether the schedule is a legacy one.
 // The caller should be holding the state lock.
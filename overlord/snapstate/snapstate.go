@@ -113,6 +113,8 @@ type minimalInstallInfo interface {
 
 type installSnapInfo struct {
 	*snap.Info
+	// components are the components installed together with the snap.
+	components []ComponentSetup
 }
 
 func (ins installSnapInfo) DownloadSize() int64 {
@@ -139,6 +141,10 @@ type InsufficientSpaceError struct {
 	ChangeKind string
 	// Message is optional, otherwise one is composed from the other information
 	Message string
+	// RequiredSpace is the estimated space the operation needs, if known
+	RequiredSpace uint64
+	// Estimates break RequiredSpace down per snap, if known
+	Estimates []SnapSpaceEstimate
 }
 
 func (e *InsufficientSpaceError) Error() string {
@@ -774,6 +780,48 @@ func doInstall(st *state.State, snapst *SnapState, snapsup SnapSetup, compsups [
 			addTasksFromTaskSet(ts)
 		}
 
+		// garbage collect by age and size of the revisions
+		if p := revisionRetentionPolicy(st); p != nil {
+			var targetSize int64
+			if snapsup.DownloadInfo != nil {
+				targetSize = snapsup.DownloadInfo.Size
+			}
+			snapshotted, err := p.snapshotRevisions(st)
+			if err != nil {
+				return nil, err
+			}
+			removalTimes, err := p.removalTimes(snapsup.InstanceName(), seq, currentIndex, targetSize, snapshotted[snapsup.InstanceName()])
+			if err != nil {
+				return nil, err
+			}
+			now := timeNow()
+			for i := 0; i < currentIndex; i++ {
+				si := seq[i]
+				if i <= currentIndex-retain {
+					// already removed above
+					continue
+				}
+				removalTime, ok := removalTimes[si.Snap.Revision]
+				if !ok || removalTime.After(now) {
+					continue
+				}
+				if inUse == nil {
+					inUse, err = inUseCheck(snapsup.Type)
+					if err != nil {
+						return nil, err
+					}
+				}
+				if inUse(snapsup.InstanceName(), si.Snap.Revision) {
+					continue
+				}
+				ts, err := removeInactiveRevision(st, snapst, snapsup.InstanceName(), si.Snap.SnapID, si.Snap.Revision, snapsup.Type)
+				if err != nil {
+					return nil, err
+				}
+				addTasksFromTaskSet(ts)
+			}
+		}
+
 		cleanupTask = st.NewTask("cleanup", fmt.Sprintf("Clean up %q%s install", snapsup.InstanceName(), revisionStr))
 		addTask(cleanupTask)
 	}
@@ -1584,7 +1632,7 @@ func Download(ctx context.Context, st *state.State, name string, blobDirectory s
 	}
 
 	toDownloadTo := filepath.Dir(snapsup.MountFile())
-	if err := checkDiskSpaceDownload([]minimalInstallInfo{installSnapInfo{info}}, toDownloadTo); err != nil {
+	if err := checkDiskSpaceDownload([]minimalInstallInfo{installSnapInfo{Info: info}}, toDownloadTo); err != nil {
 		return nil, nil, err
 	}
 
@@ -2756,6 +2804,33 @@ type AutoRefreshOptions struct {
 // snaps on the system. In addition to that it will also refresh important
 // assertions.
 func AutoRefresh(ctx context.Context, st *state.State) ([]string, *UpdateTaskSets, error) {
+	selected, err := onSystemRefreshTimer(st)
+	if err != nil {
+		return nil, nil, err
+	}
+	return autoRefreshSelected(ctx, st, selected)
+}
+
+// onSystemRefreshTimer returns a selection of the snaps which are
+// auto-refreshed on the system refresh timer, or nil if all of them are.
+// Snaps with their own refresh timer are refreshed on their own schedule
+// instead.
+func onSystemRefreshTimer(st *state.State) (func(instanceName string) bool, error) {
+	timers, err := snapRefreshTimers(st)
+	if err != nil {
+		return nil, err
+	}
+	if len(timers) == 0 {
+		return nil, nil
+	}
+	return func(instanceName string) bool {
+		return timers[instanceName] == nil
+	}, nil
+}
+
+// autoRefreshSelected is like AutoRefresh but only considers the snaps for
+// which selected returns true, or all of them if selected is nil.
+func autoRefreshSelected(ctx context.Context, st *state.State, selected func(instanceName string) bool) ([]string, *UpdateTaskSets, error) {
 	userID := 0
 
 	if AutoRefreshAssertions != nil {
@@ -2773,14 +2848,20 @@ func AutoRefresh(ctx context.Context, st *state.State) ([]string, *UpdateTaskSet
 	}
 	if !gateAutoRefreshHook {
 		// old-style refresh (gate-auto-refresh-hook feature disabled)
-		return updateManyFiltered(ctx, st, nil, nil, userID, nil, &Flags{IsAutoRefresh: true}, "")
+		var filter updateFilter
+		if selected != nil {
+			filter = func(info *snap.Info, _ *SnapState) bool {
+				return selected(info.InstanceName())
+			}
+		}
+		return updateManyFiltered(ctx, st, nil, nil, userID, filter, &Flags{IsAutoRefresh: true}, "")
 	}
 
 	// TODO: rename to autoRefreshTasks when old auto refresh logic gets removed.
 	// TODO2: pass "IsContinuedAutoRefresh" so that the SnapSetup of
 	//        gate-auto-refresh contains this field (required so that
 	//        the update-finished notifications work)
-	updated, tss, err := autoRefreshPhase1(ctx, st, "")
+	updated, tss, err := autoRefreshPhase1(ctx, st, "", selected)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -2791,8 +2872,10 @@ func AutoRefresh(ctx context.Context, st *state.State) ([]string, *UpdateTaskSet
 // autoRefreshPhase1 creates gate-auto-refresh hooks and conditional-auto-refresh
 // task that initiates actual refresh. forGatingSnap is optional and limits auto-refresh
 // to the snaps affecting the given snap only; it defaults to all snaps if nil.
+// If selected is not nil, only the snaps for which it returns true are
+// considered.
 // The state needs to be locked by the caller.
-func autoRefreshPhase1(ctx context.Context, st *state.State, forGatingSnap string) ([]string, []*state.TaskSet, error) {
+func autoRefreshPhase1(ctx context.Context, st *state.State, forGatingSnap string, selected func(instanceName string) bool) ([]string, []*state.TaskSet, error) {
 	user, err := userFromUserID(st, 0)
 	if err != nil {
 		return nil, nil, err
@@ -2803,6 +2886,21 @@ func autoRefreshPhase1(ctx context.Context, st *state.State, forGatingSnap strin
 		return nil, nil, err
 	}
 
+	// refresh candidates of snaps which are not considered are kept
+	var canDropOldNames []string
+	if selected != nil {
+		for name := range allSnaps {
+			if !selected(name) {
+				delete(allSnaps, name)
+				continue
+			}
+			canDropOldNames = append(canDropOldNames, name)
+		}
+		if len(allSnaps) == 0 {
+			return nil, nil, nil
+		}
+	}
+
 	refreshOpts := &store.RefreshOptions{Scheduled: true}
 	// XXX: should we skip refreshCandidates if forGatingSnap isn't empty (meaning we're handling proceed from a snap)?
 	plan, err := storeUpdatePlan(ctx, st, allSnaps, nil, user, refreshOpts, Options{})
@@ -2820,10 +2918,16 @@ func autoRefreshPhase1(ctx context.Context, st *state.State, forGatingSnap strin
 	if err != nil {
 		return nil, nil, err
 	}
-	updateRefreshCandidates(st, hints, nil)
+	updateRefreshCandidates(st, hints, canDropOldNames)
 
 	// prune affecting snaps that are not in refresh candidates from hold state.
-	if err := pruneGating(st, hints); err != nil {
+	allHints := hints
+	if selected != nil {
+		if err := st.Get("refresh-candidates", &allHints); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := pruneGating(st, allHints); err != nil {
 		return nil, nil, err
 	}
 
@@ -2996,12 +3100,8 @@ func autoRefreshPhase2(st *state.State, candidates []*refreshCandidate, flags *F
 }
 
 func checkDiskSpaceDownload(infos []minimalInstallInfo, rootDir string) error {
-	var totalSize uint64
-	for _, info := range infos {
-		totalSize += uint64(info.DownloadSize())
-	}
-
-	return checkForAvailableSpace(totalSize, infos, "download", rootDir)
+	est := estimateDownloadSpace(infos)
+	return checkSpaceEstimate(est, "download", rootDir)
 }
 
 // checkDiskSpace checks if there is enough space for the requested snaps and their prerequisites
@@ -3027,31 +3127,12 @@ func checkDiskSpace(st *state.State, changeKind string, infos []minimalInstallIn
 		return nil
 	}
 
-	totalSize, err := installSize(st, infos, userID, prqt)
+	est, err := estimateInstallSpace(st, infos, userID, prqt)
 	if err != nil {
 		return err
 	}
 
-	return checkForAvailableSpace(totalSize, infos, changeKind, dirs.SnapdStateDir(dirs.GlobalRootDir))
-}
-
-func checkForAvailableSpace(totalSize uint64, infos []minimalInstallInfo, changeKind string, rootDir string) error {
-	requiredSpace := safetyMarginDiskSpace(totalSize)
-	if err := osutilCheckFreeSpace(rootDir, requiredSpace); err != nil {
-		snaps := make([]string, len(infos))
-		for i, up := range infos {
-			snaps[i] = up.InstanceName()
-		}
-		if _, ok := err.(*osutil.NotEnoughDiskSpaceError); ok {
-			return &InsufficientSpaceError{
-				Path:       rootDir,
-				Snaps:      snaps,
-				ChangeKind: changeKind,
-			}
-		}
-		return err
-	}
-	return nil
+	return checkSpaceEstimate(est, changeKind, dirs.SnapdStateDir(dirs.GlobalRootDir))
 }
 
 // MigrateHome migrates a set of snaps to use a ~/Snap sub-directory as HOME.
@@ -3552,10 +3633,13 @@ func Remove(st *state.State, name string, revision snap.Revision, flags *RemoveF
 		if err := osutilCheckFreeSpace(path, requiredSpace); err != nil {
 			if _, ok := err.(*osutil.NotEnoughDiskSpaceError); ok {
 				return nil, &InsufficientSpaceError{
-					Path:       path,
-					Snaps:      []string{name},
-					ChangeKind: "remove",
-					Message:    fmt.Sprintf("cannot create automatic snapshot when removing last revision of the snap: %v", err)}
+					Path:          path,
+					Snaps:         []string{name},
+					ChangeKind:    "remove",
+					Message:       fmt.Sprintf("cannot create automatic snapshot when removing last revision of the snap: %v", err),
+					RequiredSpace: snapshotSize,
+					Estimates:     []SnapSpaceEstimate{{InstanceName: name, Snapshot: snapshotSize}},
+				}
 			}
 			return nil, err
 		}
@@ -3861,6 +3945,7 @@ func RemoveMany(st *state.State, names []string, flags *RemoveFlags) ([]string,
 	tasksets := make([]*state.TaskSet, 0, len(names))
 
 	var totalSnapshotsSize uint64
+	var estimates []SnapSpaceEstimate
 	path := dirs.SnapdStateDir(dirs.GlobalRootDir)
 
 	for _, name := range names {
@@ -3873,6 +3958,9 @@ func RemoveMany(st *state.State, names []string, flags *RemoveFlags) ([]string,
 			return nil, nil, err
 		}
 		totalSnapshotsSize += snapshotSize
+		if snapshotSize > 0 {
+			estimates = append(estimates, SnapSpaceEstimate{InstanceName: name, Snapshot: snapshotSize})
+		}
 		removed = append(removed, name)
 		ts.JoinLane(st.NewLane())
 		tasksets = append(tasksets, ts)
@@ -3885,9 +3973,11 @@ func RemoveMany(st *state.State, names []string, flags *RemoveFlags) ([]string,
 		if err := osutilCheckFreeSpace(path, requiredSpace); err != nil {
 			if _, ok := err.(*osutil.NotEnoughDiskSpaceError); ok {
 				return nil, nil, &InsufficientSpaceError{
-					Path:       path,
-					Snaps:      names,
-					ChangeKind: "remove",
+					Path:          path,
+					Snaps:         names,
+					ChangeKind:    "remove",
+					RequiredSpace: totalSnapshotsSize,
+					Estimates:     estimates,
 				}
 			}
 			return nil, nil, err
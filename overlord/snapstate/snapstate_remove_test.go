@@ -1390,6 +1390,11 @@ func (s *snapmgrTestSuite) TestRemoveManyDiskSpaceError(c *C) {
 	c.Check(diskSpaceErr.Path, Equals, filepath.Join(dirs.GlobalRootDir, "/var/lib/snapd"))
 	c.Check(diskSpaceErr.Snaps, DeepEquals, []string{"one", "two"})
 	c.Check(diskSpaceErr.ChangeKind, Equals, "remove")
+	c.Check(diskSpaceErr.RequiredSpace, Equals, uint64(30))
+	c.Check(diskSpaceErr.Estimates, DeepEquals, []snapstate.SnapSpaceEstimate{
+		{InstanceName: "one", Snapshot: 10},
+		{InstanceName: "two", Snapshot: 20},
+	})
 }
 
 func (s *snapmgrTestSuite) TestRemoveManyDiskSpaceCheckDisabled(c *C) {
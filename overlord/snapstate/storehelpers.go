@@ -198,7 +198,7 @@ var installSize = func(st *state.State, snaps []minimalInstallInfo, userID int,
 		for _, res := range results {
 			snapSizes[res.InstanceName()] = uint64(res.Size)
 			// results may have new base or content providers
-			resolveBaseAndContentProviders(installSnapInfo{res.Info})
+			resolveBaseAndContentProviders(installSnapInfo{Info: res.Info})
 		}
 	}
 
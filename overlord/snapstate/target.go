@@ -607,7 +607,7 @@ func InstallWithGoal(ctx context.Context, st *state.State, goal InstallGoal, opt
 
 	installInfos := make([]minimalInstallInfo, 0, len(targets))
 	for _, t := range targets {
-		installInfos = append(installInfos, installSnapInfo{t.info})
+		installInfos = append(installInfos, installSnapInfo{Info: t.info, components: t.components})
 	}
 
 	if err = checkDiskSpace(st, "install", installInfos, opts.UserID, opts.PrereqTracker); err != nil {
@@ -1020,7 +1020,7 @@ func UpdateWithGoal(ctx context.Context, st *state.State, goal UpdateGoal, filte
 	changeKind := "refresh"
 	installInfos := make([]minimalInstallInfo, 0, len(plan.targets))
 	for _, t := range plan.targets {
-		installInfos = append(installInfos, installSnapInfo{t.info})
+		installInfos = append(installInfos, installSnapInfo{Info: t.info, components: t.components})
 
 		// if any of the snaps are not installed, then we should use the
 		// "install" change as the kind
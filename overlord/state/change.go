@@ -150,6 +150,7 @@ type Change struct {
 	ready                    chan struct{}
 	lastObservedStatus       Status
 	lastRecordedNoticeStatus Status
+	paused                   bool
 
 	spawnTime time.Time
 	readyTime time.Time
@@ -182,6 +183,7 @@ type marshalledChange struct {
 	Clean   bool                        `json:"clean,omitempty"`
 	Data    map[string]*json.RawMessage `json:"data,omitempty"`
 	TaskIDs []string                    `json:"task-ids,omitempty"`
+	Paused  bool                        `json:"paused,omitempty"`
 
 	SpawnTime time.Time  `json:"spawn-time"`
 	ReadyTime *time.Time `json:"ready-time,omitempty"`
@@ -204,6 +206,7 @@ func (c *Change) MarshalJSON() ([]byte, error) {
 		Clean:   c.clean,
 		Data:    c.data,
 		TaskIDs: c.taskIDs,
+		Paused:  c.paused,
 
 		SpawnTime: c.spawnTime,
 		ReadyTime: readyTime,
@@ -233,6 +236,7 @@ func (c *Change) UnmarshalJSON(data []byte) error {
 	}
 	c.data = custData
 	c.taskIDs = unmarshalled.TaskIDs
+	c.paused = unmarshalled.Paused
 	c.ready = make(chan struct{})
 	c.spawnTime = unmarshalled.SpawnTime
 	if unmarshalled.ReadyTime != nil {
@@ -249,6 +253,13 @@ func (c *Change) finishUnmarshal() {
 	}
 }
 
+// writing is like State.writing but also records the change as modified
+// for incremental checkpoints.
+func (c *Change) writing() {
+	c.state.writing()
+	c.state.dirty.markChange(c.id)
+}
+
 // ID returns the individual random key for the change.
 func (c *Change) ID() string {
 	return c.id
@@ -267,7 +278,7 @@ func (c *Change) Summary() string {
 // Set associates value with key for future consulting by managers.
 // The provided value must properly marshal and unmarshal with encoding/json.
 func (c *Change) Set(key string, value interface{}) {
-	c.state.writing()
+	c.writing()
 	c.data.set(key, value)
 }
 
@@ -460,7 +471,7 @@ func (c *Change) notifyStatusChange(new Status) {
 
 // SetStatus sets the change status, overriding the default behavior (see Status method).
 func (c *Change) SetStatus(s Status) {
-	c.state.writing()
+	c.writing()
 	c.status = s
 	if s.Ready() {
 		c.markReady()
@@ -625,18 +636,19 @@ func (c *Change) State() *State {
 // AddTask registers a task as required for the state change to
 // be accomplished.
 func (c *Change) AddTask(t *Task) {
-	c.state.writing()
+	c.writing()
 	if t.change != "" {
 		panic(fmt.Sprintf("internal error: cannot add one %q task to multiple changes", t.Kind()))
 	}
 	t.change = c.id
 	c.taskIDs = addOnce(c.taskIDs, t.ID())
+	c.state.dirty.markTask(t)
 }
 
 // AddAll registers all tasks in the set as required for the state
 // change to be accomplished.
 func (c *Change) AddAll(ts *TaskSet) {
-	c.state.writing()
+	c.writing()
 	for _, t := range ts.tasks {
 		c.AddTask(t)
 	}
@@ -675,7 +687,9 @@ func (c *Change) LaneTasks(lanes ...int) []*Task {
 // Abort flags the change for cancellation, whether in progress or not.
 // Cancellation will proceed at the next ensure pass.
 func (c *Change) Abort() {
-	c.state.writing()
+	c.writing()
+	// undoing must not be held back by a pause
+	c.paused = false
 	tasks := make([]*Task, len(c.taskIDs))
 	for i, tid := range c.taskIDs {
 		tasks[i] = c.state.tasks[tid]
@@ -683,18 +697,40 @@ func (c *Change) Abort() {
 	c.abortTasks(tasks, make(map[int]bool), make(map[string]bool))
 }
 
+// Pause holds the change at task boundaries: tasks already running are
+// allowed to finish, but no new tasks of the change are started by the
+// task runner until Resume is called. Tasks being undone are not held
+// back.
+func (c *Change) Pause() {
+	c.writing()
+	c.paused = true
+}
+
+// Resume lets the task runner start tasks of a paused change again.
+func (c *Change) Resume() {
+	c.writing()
+	c.paused = false
+}
+
+// IsPaused returns whether the change was paused with Pause and not
+// resumed since.
+func (c *Change) IsPaused() bool {
+	c.state.reading()
+	return c.paused
+}
+
 // AbortLanes aborts all tasks in the provided lanes and any tasks waiting on them,
 // except for tasks that are also in a healthy lane (not aborted, and not waiting
 // on aborted).
 func (c *Change) AbortLanes(lanes []int) {
-	c.state.writing()
+	c.writing()
 	c.abortLanes(lanes, make(map[int]bool), make(map[string]bool))
 }
 
 // AbortUnreadyLanes aborts the tasks from lanes that aren't fully ready, where
 // a ready lane is one in which all tasks are ready.
 func (c *Change) AbortUnreadyLanes() {
-	c.state.writing()
+	c.writing()
 	c.abortUnreadyLanes()
 }
 
@@ -20,6 +20,7 @@
 package state_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -1579,3 +1580,28 @@ func (cs *changeSuite) TestChangeLastRecordedNoitceStatusPersisted(c *C) {
 	obtainedStatus := state.Status(chgData["last-recorded-notice-status"].(float64))
 	c.Check(obtainedStatus, Equals, state.DoingStatus)
 }
+
+func (cs *changeSuite) TestPausePersisted(c *C) {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	chg := st.NewChange("change", "summary...")
+	c.Check(chg.IsPaused(), Equals, false)
+	chg.Pause()
+	c.Check(chg.IsPaused(), Equals, true)
+
+	data, err := json.Marshal(st)
+	c.Assert(err, IsNil)
+	st2, err := state.ReadState(nil, bytes.NewReader(data))
+	c.Assert(err, IsNil)
+	st2.Lock()
+	c.Check(st2.Change(chg.ID()).IsPaused(), Equals, true)
+	st2.Unlock()
+
+	chg.Resume()
+	c.Check(chg.IsPaused(), Equals, false)
+	data, err = json.Marshal(chg)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Not(Matches), `.*"paused".*`)
+}
@@ -72,3 +72,7 @@ var (
 func (s *State) NumNotices() int {
 	return len(s.notices)
 }
+
+func TaskRunDurationCount(kind, phase string) uint64 {
+	return taskRunDuration.With(kind, phase).Count()
+}
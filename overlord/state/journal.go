@@ -0,0 +1,418 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/snapcore/snapd/logger"
+)
+
+// A JournalBackend is a Backend that can persist the state incrementally.
+//
+// When the backend of a State implements JournalBackend, unlocking a
+// modified state hands it, via CheckpointDelta, only a serialized delta
+// with the entries that were modified since the last checkpoint. Checkpoint
+// is still used with the full serialized state whenever there is no
+// previous checkpoint to build upon, or after CheckpointDelta failed. The
+// deltas can be folded into a full serialized state with ApplyDeltas.
+type JournalBackend interface {
+	Backend
+	CheckpointDelta(delta []byte) error
+}
+
+// dirtyEntries tracks the entries of the state that were modified since
+// the last checkpoint. All methods are no-ops on a nil *dirtyEntries, which
+// is what is used when the backend does not support incremental
+// checkpoints.
+type dirtyEntries struct {
+	// all is set when nothing was checkpointed yet
+	all bool
+
+	data     map[string]bool
+	changes  map[string]bool
+	tasks    map[string]bool
+	warnings map[string]bool
+	notices  map[string]bool
+}
+
+func newDirtyEntries() *dirtyEntries {
+	d := &dirtyEntries{}
+	d.reset()
+	return d
+}
+
+func (d *dirtyEntries) reset() {
+	if d == nil {
+		return
+	}
+	d.all = false
+	d.data = make(map[string]bool)
+	d.changes = make(map[string]bool)
+	d.tasks = make(map[string]bool)
+	d.warnings = make(map[string]bool)
+	d.notices = make(map[string]bool)
+}
+
+func (d *dirtyEntries) markData(key string) {
+	if d == nil {
+		return
+	}
+	d.data[key] = true
+}
+
+func (d *dirtyEntries) markChange(id string) {
+	if d == nil {
+		return
+	}
+	d.changes[id] = true
+}
+
+// markTask marks the task as modified together with its change, as
+// modifications to a task can affect the persisted status, readiness
+// and cleanliness of the latter.
+func (d *dirtyEntries) markTask(t *Task) {
+	if d == nil {
+		return
+	}
+	d.tasks[t.id] = true
+	if t.change != "" {
+		d.changes[t.change] = true
+	}
+}
+
+func (d *dirtyEntries) markWarning(message string) {
+	if d == nil {
+		return
+	}
+	d.warnings[message] = true
+}
+
+func (d *dirtyEntries) markNotice(id string) {
+	if d == nil {
+		return
+	}
+	d.notices[id] = true
+}
+
+// marshalledDelta is the serialized form of the entries modified between
+// two checkpoints. A null entry means the entry was removed. If State is
+// set the delta instead replaces the whole state with it.
+type marshalledDelta struct {
+	State *json.RawMessage `json:"state,omitempty"`
+
+	Data     map[string]*json.RawMessage `json:"data,omitempty"`
+	Changes  map[string]*json.RawMessage `json:"changes,omitempty"`
+	Tasks    map[string]*json.RawMessage `json:"tasks,omitempty"`
+	Warnings map[string]*json.RawMessage `json:"warnings,omitempty"`
+	Notices  map[string]*json.RawMessage `json:"notices,omitempty"`
+
+	LastChangeId int `json:"last-change-id"`
+	LastTaskId   int `json:"last-task-id"`
+	LastLaneId   int `json:"last-lane-id"`
+	LastNoticeId int `json:"last-notice-id"`
+
+	LastNoticeTimestamp time.Time `json:"last-notice-timestamp,omitempty"`
+}
+
+func marshalDeltaEntry(value interface{}) *json.RawMessage {
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		logger.Panicf("internal error: could not marshal state entry for checkpointing: %v", err)
+	}
+	entry := json.RawMessage(serialized)
+	return &entry
+}
+
+// checkpointDelta returns the serialized delta of the entries modified
+// since the last checkpoint.
+func (s *State) checkpointDelta() []byte {
+	d := s.dirty
+	delta := marshalledDelta{
+		LastChangeId: s.lastChangeId,
+		LastTaskId:   s.lastTaskId,
+		LastLaneId:   s.lastLaneId,
+		LastNoticeId: s.lastNoticeId,
+
+		LastNoticeTimestamp: s.lastNoticeTimestamp,
+	}
+	if len(d.data) > 0 {
+		delta.Data = make(map[string]*json.RawMessage, len(d.data))
+		for key := range d.data {
+			delta.Data[key] = s.data[key]
+		}
+	}
+	if len(d.changes) > 0 {
+		delta.Changes = make(map[string]*json.RawMessage, len(d.changes))
+		for id := range d.changes {
+			if chg := s.changes[id]; chg != nil {
+				delta.Changes[id] = marshalDeltaEntry(chg)
+			} else {
+				delta.Changes[id] = nil
+			}
+		}
+	}
+	if len(d.tasks) > 0 {
+		delta.Tasks = make(map[string]*json.RawMessage, len(d.tasks))
+		for id := range d.tasks {
+			if t := s.tasks[id]; t != nil {
+				delta.Tasks[id] = marshalDeltaEntry(t)
+			} else {
+				delta.Tasks[id] = nil
+			}
+		}
+	}
+	now := time.Now()
+	if len(d.warnings) > 0 {
+		delta.Warnings = make(map[string]*json.RawMessage, len(d.warnings))
+		for message := range d.warnings {
+			// expired warnings are dropped, as when checkpointing
+			// the full state
+			if w := s.warnings[message]; w != nil && !w.ExpiredBefore(now) {
+				delta.Warnings[message] = marshalDeltaEntry(w)
+			} else {
+				delta.Warnings[message] = nil
+			}
+		}
+	}
+	if len(d.notices) > 0 {
+		delta.Notices = make(map[string]*json.RawMessage, len(d.notices))
+		for id := range d.notices {
+			delta.Notices[id] = nil
+		}
+		for _, n := range s.notices {
+			if d.notices[n.id] && !n.expired(now) {
+				delta.Notices[n.id] = marshalDeltaEntry(n)
+			}
+		}
+	}
+	data, err := json.Marshal(delta)
+	if err != nil {
+		logger.Panicf("internal error: could not marshal state delta for checkpointing: %v", err)
+	}
+	return data
+}
+
+// FullDelta returns a delta that replaces the whole state with the given
+// full serialized state.
+func FullDelta(data []byte) ([]byte, error) {
+	full := json.RawMessage(data)
+	return json.Marshal(marshalledDelta{State: &full})
+}
+
+// rawState is the serialized state as produced by State.MarshalJSON
+// with its entries kept in serialized form.
+type rawState struct {
+	Data     map[string]*json.RawMessage `json:"data"`
+	Changes  map[string]*json.RawMessage `json:"changes"`
+	Tasks    map[string]*json.RawMessage `json:"tasks"`
+	Warnings []*json.RawMessage          `json:"warnings,omitempty"`
+	Notices  []*json.RawMessage          `json:"notices,omitempty"`
+
+	LastChangeId int `json:"last-change-id"`
+	LastTaskId   int `json:"last-task-id"`
+	LastLaneId   int `json:"last-lane-id"`
+	LastNoticeId int `json:"last-notice-id"`
+
+	LastNoticeTimestamp time.Time `json:"last-notice-timestamp,omitempty"`
+}
+
+// keyedEntries maps serialized warnings or notices from their identifying
+// field.
+func keyedEntries(entries []*json.RawMessage, field string) (map[string]*json.RawMessage, error) {
+	keyed := make(map[string]*json.RawMessage, len(entries))
+	for _, entry := range entries {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(*entry, &fields); err != nil {
+			return nil, err
+		}
+		key, ok := fields[field].(string)
+		if !ok {
+			return nil, fmt.Errorf("entry without %q", field)
+		}
+		keyed[key] = entry
+	}
+	return keyed, nil
+}
+
+func flattenEntries(keyed map[string]*json.RawMessage) []*json.RawMessage {
+	keys := make([]string, 0, len(keyed))
+	for key := range keyed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	flat := make([]*json.RawMessage, 0, len(keys))
+	for _, key := range keys {
+		flat = append(flat, keyed[key])
+	}
+	return flat
+}
+
+func mergeEntries(into, from map[string]*json.RawMessage) map[string]*json.RawMessage {
+	if into == nil {
+		into = make(map[string]*json.RawMessage, len(from))
+	}
+	for key, entry := range from {
+		if entry == nil {
+			delete(into, key)
+			continue
+		}
+		into[key] = entry
+	}
+	return into
+}
+
+func readRawState(data []byte) (st *rawState, warnings, notices map[string]*json.RawMessage, err error) {
+	st = &rawState{}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot read state: %v", err)
+	}
+	warnings, err = keyedEntries(st.Warnings, "message")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot read state warnings: %v", err)
+	}
+	notices, err = keyedEntries(st.Notices, "id")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot read state notices: %v", err)
+	}
+	return st, warnings, notices, nil
+}
+
+// ApplyDeltas returns the full serialized state obtained by applying, in
+// order, the deltas given to a JournalBackend on top of the serialized
+// state data. Applying a delta more than once, as long as the order is
+// preserved, yields the same result.
+func ApplyDeltas(data []byte, deltas [][]byte) ([]byte, error) {
+	st, warnings, notices, err := readRawState(data)
+	if err != nil {
+		return nil, err
+	}
+	for i, deltaData := range deltas {
+		var delta marshalledDelta
+		if err := json.Unmarshal(deltaData, &delta); err != nil {
+			return nil, fmt.Errorf("cannot read state delta %d: %v", i, err)
+		}
+		if delta.State != nil {
+			st, warnings, notices, err = readRawState(*delta.State)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		st.Data = mergeEntries(st.Data, delta.Data)
+		st.Changes = mergeEntries(st.Changes, delta.Changes)
+		st.Tasks = mergeEntries(st.Tasks, delta.Tasks)
+		warnings = mergeEntries(warnings, delta.Warnings)
+		notices = mergeEntries(notices, delta.Notices)
+		st.LastChangeId = delta.LastChangeId
+		st.LastTaskId = delta.LastTaskId
+		st.LastLaneId = delta.LastLaneId
+		st.LastNoticeId = delta.LastNoticeId
+		st.LastNoticeTimestamp = delta.LastNoticeTimestamp
+	}
+	st.Warnings = flattenEntries(warnings)
+	st.Notices = flattenEntries(notices)
+	return json.Marshal(st)
+}
+
+// JournalFile returns the path of the journal of deltas kept next to the
+// state file at statePath.
+func JournalFile(statePath string) string {
+	return statePath + ".journal"
+}
+
+// journalRecordHeaderSize is the size of the header preceding each delta
+// in a journal: the little-endian 32-bit length of the delta followed by
+// its little-endian 32-bit CRC-32 (IEEE) checksum.
+const journalRecordHeaderSize = 8
+
+// WriteJournalRecord writes the delta to w as a journal record.
+func WriteJournalRecord(w io.Writer, delta []byte) error {
+	record := make([]byte, journalRecordHeaderSize+len(delta))
+	binary.LittleEndian.PutUint32(record[0:4], uint32(len(delta)))
+	binary.LittleEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(delta))
+	copy(record[journalRecordHeaderSize:], delta)
+	_, err := w.Write(record)
+	return err
+}
+
+// ReadJournal returns the deltas recorded in the journal read from r
+// together with the size of the valid records read. A truncated or
+// corrupted record, as left behind by a crash while it was being written,
+// ends the journal.
+func ReadJournal(r io.Reader) (deltas [][]byte, size int64, err error) {
+	header := make([]byte, journalRecordHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return deltas, size, nil
+			}
+			return nil, 0, err
+		}
+		delta := make([]byte, binary.LittleEndian.Uint32(header[0:4]))
+		if _, err := io.ReadFull(r, delta); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return deltas, size, nil
+			}
+			return nil, 0, err
+		}
+		if crc32.ChecksumIEEE(delta) != binary.LittleEndian.Uint32(header[4:8]) {
+			return deltas, size, nil
+		}
+		deltas = append(deltas, delta)
+		size += int64(journalRecordHeaderSize + len(delta))
+	}
+}
+
+// ReadStateFile returns the state read from the state file at statePath,
+// with the deltas recorded in its journal, if any, applied on top.
+func ReadStateFile(backend Backend, statePath string) (*State, error) {
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the state file: %s", err)
+	}
+	f, err := os.Open(JournalFile(statePath))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("cannot read the state journal: %s", err)
+	}
+	if err == nil {
+		defer f.Close()
+		deltas, _, err := ReadJournal(f)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the state journal: %s", err)
+		}
+		if len(deltas) > 0 {
+			data, err = ApplyDeltas(data, deltas)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ReadState(backend, bytes.NewReader(data))
+}
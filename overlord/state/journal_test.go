@@ -0,0 +1,336 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package state_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/snapcore/snapd/overlord/state"
+)
+
+type journalSuite struct{}
+
+var _ = Suite(&journalSuite{})
+
+type fakeJournalBackend struct {
+	fakeStateBackend
+	deltas   [][]byte
+	deltaErr error
+}
+
+func (b *fakeJournalBackend) CheckpointDelta(delta []byte) error {
+	if b.deltaErr != nil {
+		return b.deltaErr
+	}
+	b.deltas = append(b.deltas, delta)
+	return nil
+}
+
+// normalizedState returns the serialized state as generic JSON with the
+// warnings and notices keyed by their identifying fields, so that it
+// does not depend on their order.
+func normalizedState(c *C, data []byte) map[string]interface{} {
+	var st map[string]interface{}
+	c.Assert(json.Unmarshal(data, &st), IsNil)
+	for list, field := range map[string]string{"warnings": "message", "notices": "id"} {
+		keyed := make(map[string]interface{})
+		entries, _ := st[list].([]interface{})
+		for _, entry := range entries {
+			keyed[entry.(map[string]interface{})[field].(string)] = entry
+		}
+		st[list] = keyed
+	}
+	return st
+}
+
+func (b *fakeJournalBackend) replay(c *C) []byte {
+	c.Assert(b.checkpoints, HasLen, 1)
+	data, err := state.ApplyDeltas(b.checkpoints[0], b.deltas)
+	c.Assert(err, IsNil)
+	return data
+}
+
+func (s *journalSuite) TestFirstCheckpointIsFull(c *C) {
+	b := &fakeJournalBackend{}
+	st := state.New(b)
+	st.Lock()
+	st.Set("k", 1)
+	st.Unlock()
+
+	c.Check(b.checkpoints, HasLen, 1)
+	c.Check(b.deltas, HasLen, 0)
+
+	st.Lock()
+	st.Set("k", 2)
+	st.Unlock()
+
+	c.Check(b.checkpoints, HasLen, 1)
+	c.Check(b.deltas, HasLen, 1)
+
+	// unmodified state is not checkpointed
+	st.Lock()
+	st.Unlock()
+	c.Check(b.deltas, HasLen, 1)
+}
+
+func (s *journalSuite) TestFullCheckpointAfterDeltaError(c *C) {
+	b := &fakeJournalBackend{}
+	st := state.New(b)
+	st.Lock()
+	st.Set("k", 1)
+	st.Unlock()
+
+	b.deltaErr = errors.New("cannot append")
+	st.Lock()
+	st.Set("k", 2)
+	st.Unlock()
+
+	// the state is checkpointed in full instead
+	c.Check(b.deltas, HasLen, 0)
+	c.Assert(b.checkpoints, HasLen, 2)
+	st2, err := state.ReadState(nil, bytes.NewReader(b.checkpoints[1]))
+	c.Assert(err, IsNil)
+	st2.Lock()
+	defer st2.Unlock()
+	var k int
+	c.Assert(st2.Get("k", &k), IsNil)
+	c.Check(k, Equals, 2)
+}
+
+func (s *journalSuite) TestDeltaOnlyHasModifiedEntries(c *C) {
+	b := &fakeJournalBackend{}
+	st := state.New(b)
+	st.Lock()
+	st.Set("a", 1)
+	st.Set("b", 2)
+	chg1 := st.NewChange("chg1", "...")
+	chg1.AddTask(st.NewTask("t1", "..."))
+	chg2 := st.NewChange("chg2", "...")
+	t2 := st.NewTask("t2", "...")
+	chg2.AddTask(t2)
+	st.Unlock()
+
+	st.Lock()
+	st.Set("b", nil)
+	t2.Set("x", true)
+	st.Unlock()
+
+	c.Assert(b.deltas, HasLen, 1)
+	var delta map[string]interface{}
+	c.Assert(json.Unmarshal(b.deltas[0], &delta), IsNil)
+	c.Check(delta["data"], DeepEquals, map[string]interface{}{"b": nil})
+	c.Check(delta["tasks"], HasLen, 1)
+	c.Check(delta["tasks"].(map[string]interface{})[t2.ID()], NotNil)
+	// the change of the task is included as well
+	c.Check(delta["changes"], HasLen, 1)
+	c.Check(delta["changes"].(map[string]interface{})[chg2.ID()], NotNil)
+	c.Check(delta["last-task-id"], Equals, 2.0)
+}
+
+func (s *journalSuite) TestReplayMatchesFullCheckpoint(c *C) {
+	b := &fakeJournalBackend{}
+	st := state.New(b)
+	st.Lock()
+	st.Set("a", 1)
+	st.Unlock()
+
+	st.Lock()
+	chg := st.NewChange("install", "...")
+	t1 := st.NewTask("download", "...")
+	t2 := st.NewTask("mount", "...")
+	t2.WaitFor(t1)
+	lane := st.NewLane()
+	t1.JoinLane(lane)
+	t2.JoinLane(lane)
+	chg.AddTask(t1)
+	chg.AddTask(t2)
+	chg.Set("snap-names", []string{"foo"})
+	st.Warnf("hello")
+	st.AddNotice(nil, state.SnapRunInhibitNotice, "foo", nil)
+	st.Unlock()
+
+	st.Lock()
+	t1.SetStatus(state.DoneStatus)
+	t1.Logf("downloaded")
+	t2.SetProgress("mounting", 1, 3)
+	st.Set("a", 2)
+	st.Unlock()
+
+	st.Lock()
+	t2.SetStatus(state.DoneStatus)
+	t1.SetClean()
+	t2.SetClean()
+	st.OkayWarnings(time.Now())
+	c.Assert(st.RemoveWarning("hello"), IsNil)
+	unlinked := st.NewTask("unlinked", "...")
+	st.Unlock()
+
+	st.Lock()
+	state.MockTaskTimes(unlinked, time.Now().Add(-48*time.Hour), time.Time{})
+	st.Prune(time.Now(), time.Hour, time.Hour, 0)
+	full, err := json.Marshal(st)
+	c.Assert(err, IsNil)
+	st.Unlock()
+
+	c.Check(normalizedState(c, b.replay(c)), DeepEquals, normalizedState(c, full))
+
+	st2, err := state.ReadState(nil, bytes.NewReader(b.replay(c)))
+	c.Assert(err, IsNil)
+	st2.Lock()
+	defer st2.Unlock()
+	c.Check(st2.Changes(), HasLen, 0)
+	c.Check(st2.TaskCount(), Equals, 0)
+	var a int
+	c.Assert(st2.Get("a", &a), IsNil)
+	c.Check(a, Equals, 2)
+}
+
+func (s *journalSuite) TestReadStateKeepsTrackingDeltas(c *C) {
+	b := &fakeJournalBackend{}
+	st := state.New(b)
+	st.Lock()
+	st.Set("a", 1)
+	st.Unlock()
+
+	st2, err := state.ReadState(b, bytes.NewReader(b.checkpoints[0]))
+	c.Assert(err, IsNil)
+	st2.Lock()
+	st2.Set("a", 2)
+	st2.Unlock()
+
+	c.Check(b.checkpoints, HasLen, 1)
+	c.Check(b.deltas, HasLen, 1)
+}
+
+func (s *journalSuite) TestApplyDeltasIdempotent(c *C) {
+	b := &fakeJournalBackend{}
+	st := state.New(b)
+	st.Lock()
+	st.Set("a", 1)
+	st.Unlock()
+
+	for i := 0; i < 3; i++ {
+		st.Lock()
+		chg := st.NewChange("chg", "...")
+		chg.AddTask(st.NewTask("t", "..."))
+		st.Set("a", i)
+		st.Unlock()
+	}
+
+	once := b.replay(c)
+	twice, err := state.ApplyDeltas(once, b.deltas)
+	c.Assert(err, IsNil)
+	c.Check(normalizedState(c, twice), DeepEquals, normalizedState(c, once))
+}
+
+func (s *journalSuite) TestApplyDeltasFullDelta(c *C) {
+	base := []byte(`{"data":{"a":1},"changes":{},"tasks":{},"last-change-id":0,"last-task-id":0,"last-lane-id":0,"last-notice-id":0}`)
+	full := []byte(`{"data":{"b":2},"changes":{},"tasks":{},"last-change-id":3,"last-task-id":0,"last-lane-id":0,"last-notice-id":0}`)
+	fullDelta, err := state.FullDelta(full)
+	c.Assert(err, IsNil)
+	delta := []byte(`{"data":{"c":3},"last-change-id":4,"last-task-id":0,"last-lane-id":0,"last-notice-id":0}`)
+
+	data, err := state.ApplyDeltas(base, [][]byte{fullDelta, delta})
+	c.Assert(err, IsNil)
+	st := normalizedState(c, data)
+	c.Check(st["data"], DeepEquals, map[string]interface{}{"b": 2.0, "c": 3.0})
+	c.Check(st["last-change-id"], Equals, 4.0)
+}
+
+func (s *journalSuite) TestApplyDeltasErrors(c *C) {
+	_, err := state.ApplyDeltas([]byte(`{`), nil)
+	c.Check(err, ErrorMatches, "cannot read state: .*")
+	_, err = state.ApplyDeltas([]byte(`{}`), [][]byte{[]byte(`[]`)})
+	c.Check(err, ErrorMatches, "cannot read state delta 0: .*")
+}
+
+func (s *journalSuite) TestReadJournalTornRecord(c *C) {
+	var buf bytes.Buffer
+	c.Assert(state.WriteJournalRecord(&buf, []byte(`{"a":1}`)), IsNil)
+	c.Assert(state.WriteJournalRecord(&buf, []byte(`{"a":2}`)), IsNil)
+	validSize := int64(buf.Len())
+
+	deltas, size, err := state.ReadJournal(bytes.NewReader(buf.Bytes()))
+	c.Assert(err, IsNil)
+	c.Check(deltas, DeepEquals, [][]byte{[]byte(`{"a":1}`), []byte(`{"a":2}`)})
+	c.Check(size, Equals, validSize)
+
+	// a partially written record
+	c.Assert(state.WriteJournalRecord(&buf, []byte(`{"a":3}`)), IsNil)
+	torn := buf.Bytes()[:buf.Len()-2]
+	deltas, size, err = state.ReadJournal(bytes.NewReader(torn))
+	c.Assert(err, IsNil)
+	c.Check(deltas, HasLen, 2)
+	c.Check(size, Equals, validSize)
+
+	// a corrupted record
+	corrupted := append([]byte(nil), buf.Bytes()...)
+	corrupted[len(corrupted)-2] = 'X'
+	deltas, size, err = state.ReadJournal(bytes.NewReader(corrupted))
+	c.Assert(err, IsNil)
+	c.Check(deltas, HasLen, 2)
+	c.Check(size, Equals, validSize)
+}
+
+func (s *journalSuite) TestReadStateFile(c *C) {
+	b := &fakeJournalBackend{}
+	st := state.New(b)
+	st.Lock()
+	st.Set("a", 1)
+	st.Unlock()
+	st.Lock()
+	st.Set("a", 2)
+	st.Unlock()
+
+	statePath := filepath.Join(c.MkDir(), "state.json")
+	c.Assert(os.WriteFile(statePath, b.checkpoints[0], 0600), IsNil)
+
+	// without a journal
+	st2, err := state.ReadStateFile(nil, statePath)
+	c.Assert(err, IsNil)
+	st2.Lock()
+	var a int
+	c.Assert(st2.Get("a", &a), IsNil)
+	st2.Unlock()
+	c.Check(a, Equals, 1)
+
+	// with a journal
+	var journal bytes.Buffer
+	c.Assert(state.WriteJournalRecord(&journal, b.deltas[0]), IsNil)
+	c.Assert(os.WriteFile(state.JournalFile(statePath), journal.Bytes(), 0600), IsNil)
+	st2, err = state.ReadStateFile(nil, statePath)
+	c.Assert(err, IsNil)
+	st2.Lock()
+	c.Assert(st2.Get("a", &a), IsNil)
+	st2.Unlock()
+	c.Check(a, Equals, 2)
+}
+
+func (s *journalSuite) TestReadStateFileMissing(c *C) {
+	_, err := state.ReadStateFile(nil, "/missing-state.json")
+	c.Check(err, ErrorMatches, "cannot read the state file: open /missing-state.json: no such file or directory")
+}
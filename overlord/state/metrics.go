@@ -0,0 +1,37 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package state
+
+import (
+	"github.com/snapcore/snapd/metrics"
+)
+
+var (
+	taskRunDuration = metrics.MustRegister(metrics.NewHistogramVec(
+		"snapd_task_run_duration_seconds",
+		"Time spent running task handlers, by task kind and phase.",
+		nil, "kind", "phase"))
+
+	checkpointDuration = metrics.MustRegister(metrics.NewHistogramVec(
+		"snapd_state_checkpoint_duration_seconds",
+		"Time spent checkpointing the state, by checkpoint type.",
+		[]float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+		"type"))
+)
@@ -214,11 +214,16 @@ const (
 	// expired. The key for interfaces-requests-rule-update notices is the
 	// rule ID.
 	InterfacesRequestsRuleUpdateNotice NoticeType = "interfaces-requests-rule-update"
+
+	// Recorded whenever a snap is automatically reverted because it was
+	// found to be unhealthy after a refresh. The key for snap-auto-rollback
+	// notices is the snap instance name.
+	SnapAutoRollbackNotice NoticeType = "snap-auto-rollback"
 )
 
 func (t NoticeType) Valid() bool {
 	switch t {
-	case ChangeUpdateNotice, WarningNotice, RefreshInhibitNotice, SnapRunInhibitNotice, InterfacesRequestsPromptNotice, InterfacesRequestsRuleUpdateNotice:
+	case ChangeUpdateNotice, WarningNotice, RefreshInhibitNotice, SnapRunInhibitNotice, InterfacesRequestsPromptNotice, InterfacesRequestsRuleUpdateNotice, SnapAutoRollbackNotice:
 		return true
 	}
 	return false
@@ -303,6 +308,7 @@ func (s *State) AddNotice(userID *uint32, noticeType NoticeType, key string, opt
 	notice.lastOccurred = now
 	notice.lastData = options.Data
 	notice.repeatAfter = options.RepeatAfter
+	s.dirty.markNotice(notice.id)
 
 	if newOrRepeated {
 		s.noticeCond.Broadcast()
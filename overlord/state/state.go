@@ -103,6 +103,9 @@ type State struct {
 	noticeCond *sync.Cond
 
 	modified bool
+	// dirty tracks the entries modified since the last checkpoint
+	// when the backend is a JournalBackend, it is nil otherwise
+	dirty *dirtyEntries
 
 	cache map[interface{}]interface{}
 
@@ -128,6 +131,10 @@ func New(backend Backend) *State {
 		taskHandlers:        make(map[int]func(t *Task, old Status, new Status) bool),
 		changeHandlers:      make(map[int]func(chg *Change, old Status, new Status)),
 	}
+	if _, ok := backend.(JournalBackend); ok {
+		st.dirty = newDirtyEntries()
+		st.dirty.all = true
+	}
 	st.noticeCond = sync.NewCond(st) // use State.Lock and State.Unlock
 	return st
 }
@@ -258,14 +265,34 @@ func (s *State) Unlock() {
 		return
 	}
 
-	data := s.checkpointData()
+	checkpoint := s.backend.Checkpoint
+	checkpointType := "full"
+	var data []byte
+	t0 := time.Now()
+	if journal, ok := s.backend.(JournalBackend); ok && !s.dirty.all {
+		checkpoint = journal.CheckpointDelta
+		checkpointType = "delta"
+		data = s.checkpointDelta()
+	} else {
+		data = s.checkpointData()
+	}
 	var err error
 	start := time.Now()
 	for time.Since(start) <= unlockCheckpointRetryMaxTime {
-		if err = s.backend.Checkpoint(data); err == nil {
+		if err = checkpoint(data); err == nil {
+			checkpointDuration.With(checkpointType).Observe(time.Since(t0).Seconds())
 			s.modified = false
+			s.dirty.reset()
 			return
 		}
+		if checkpointType == "delta" {
+			// the backend might not be able to build upon its
+			// previous checkpoints anymore
+			checkpoint = s.backend.Checkpoint
+			checkpointType = "full"
+			data = s.checkpointData()
+			continue
+		}
 		time.Sleep(unlockCheckpointRetryInterval)
 	}
 	logger.Panicf("cannot checkpoint even after %v of retries every %v: %v", unlockCheckpointRetryMaxTime, unlockCheckpointRetryInterval, err)
@@ -322,6 +349,7 @@ func (s *State) Has(key string) bool {
 func (s *State) Set(key string, value interface{}) {
 	s.writing()
 	s.data.set(key, value)
+	s.dirty.markData(key)
 }
 
 // Cached returns the cached value associated with the provided key.
@@ -349,6 +377,7 @@ func (s *State) NewChange(kind, summary string) *Change {
 	id := strconv.Itoa(s.lastChangeId)
 	chg := newChange(s, id, kind, summary)
 	s.changes[id] = chg
+	s.dirty.markChange(id)
 	// Add change-update notice for newly spawned change
 	// NOTE: Implies State.writing()
 	if err := chg.addNotice(); err != nil {
@@ -389,9 +418,24 @@ func (s *State) NewTask(kind, summary string) *Task {
 	id := strconv.Itoa(s.lastTaskId)
 	t := newTask(s, id, kind, summary)
 	s.tasks[id] = t
+	s.dirty.markTask(t)
 	return t
 }
 
+// DiscardTasks removes the given tasks, which must not have been added to a
+// change, from the state. It can be used to throw away tasks that were only
+// created to find out what an operation would do.
+func (s *State) DiscardTasks(tasks ...*Task) {
+	s.writing()
+	for _, t := range tasks {
+		if chg := t.Change(); chg != nil {
+			panic(fmt.Sprintf("internal error: cannot discard task %s which belongs to change %s", t.ID(), chg.ID()))
+		}
+		delete(s.tasks, t.ID())
+		s.dirty.markTask(t)
+	}
+}
+
 // Tasks returns all tasks currently known to the state and linked to changes.
 func (s *State) Tasks() []*Task {
 	s.reading()
@@ -472,12 +516,14 @@ func (s *State) Prune(startOfOperation time.Time, pruneWait, abortWait time.Dura
 	for k, w := range s.warnings {
 		if w.ExpiredBefore(now) {
 			delete(s.warnings, k)
+			s.dirty.markWarning(k)
 		}
 	}
 
 	for k, n := range s.notices {
 		if n.expired(now) {
 			delete(s.notices, k)
+			s.dirty.markNotice(n.id)
 		}
 	}
 
@@ -492,6 +538,7 @@ NextChange:
 			if spawnTime.Before(pruneLimit) && len(chg.Tasks()) == 0 {
 				chg.Abort()
 				delete(s.changes, chg.ID())
+				s.dirty.markChange(chg.ID())
 			} else if spawnTime.Before(abortLimit) {
 				for attr, pending := range s.pendingChangeByAttr {
 					if chg.Has(attr) && pending(chg) {
@@ -507,8 +554,10 @@ NextChange:
 			s.writing()
 			for _, t := range chg.Tasks() {
 				delete(s.tasks, t.ID())
+				s.dirty.markTask(t)
 			}
 			delete(s.changes, chg.ID())
+			s.dirty.markChange(chg.ID())
 			readyChangesCount--
 		}
 	}
@@ -518,6 +567,7 @@ NextChange:
 		if t.Change() == nil && t.SpawnTime().Before(pruneLimit) {
 			s.writing()
 			delete(s.tasks, tid)
+			s.dirty.markTask(t)
 		}
 	}
 }
@@ -610,5 +660,8 @@ func ReadState(backend Backend, r io.Reader) (*State, error) {
 	s.pendingChangeByAttr = make(map[string]func(*Change) bool)
 	s.changeHandlers = make(map[int]func(chg *Change, old Status, new Status))
 	s.taskHandlers = make(map[int]func(t *Task, old Status, new Status) bool)
+	if _, ok := backend.(JournalBackend); ok {
+		s.dirty = newDirtyEntries()
+	}
 	return s, err
 }
@@ -794,6 +794,27 @@ func (ss *stateSuite) TestMethodEntrance(c *C) {
 	}
 }
 
+func (ss *stateSuite) TestDiscardTasks(c *C) {
+	st := state.New(nil)
+	st.Lock()
+	defer st.Unlock()
+
+	chg := st.NewChange("install", "...")
+	linked := st.NewTask("check", "...")
+	chg.AddTask(linked)
+	t1 := st.NewTask("download", "...")
+	t2 := st.NewTask("mount", "...")
+	t2.WaitFor(t1)
+
+	st.DiscardTasks(t1, t2)
+	c.Check(st.Task(t1.ID()), IsNil)
+	c.Check(st.Task(t2.ID()), IsNil)
+	c.Check(st.Task(linked.ID()), Equals, linked)
+	c.Check(st.TaskCount(), Equals, 1)
+
+	c.Check(func() { st.DiscardTasks(linked) }, PanicMatches, `internal error: cannot discard task 1 which belongs to change 1`)
+}
+
 func (ss *stateSuite) TestPrune(c *C) {
 	st := state.New(&fakeStateBackend{})
 	st.Lock()
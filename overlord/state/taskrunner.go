@@ -187,6 +187,7 @@ func (r *TaskRunner) AddBlocked(pred func(t *Task, running []*Task) bool) {
 func (r *TaskRunner) run(t *Task) {
 	var handler HandlerFunc
 	var accuRuntime func(dur time.Duration)
+	var phase string
 	switch t.Status() {
 	case DoStatus:
 		t.SetStatus(DoingStatus)
@@ -194,6 +195,7 @@ func (r *TaskRunner) run(t *Task) {
 	case DoingStatus:
 		handler = r.handlerPair(t).do
 		accuRuntime = t.accumulateDoingTime
+		phase = "do"
 
 	case UndoStatus:
 		t.SetStatus(UndoingStatus)
@@ -201,6 +203,7 @@ func (r *TaskRunner) run(t *Task) {
 	case UndoingStatus:
 		handler = r.handlerPair(t).undo
 		accuRuntime = t.accumulateUndoingTime
+		phase = "undo"
 
 	default:
 		panic("internal error: attempted to run task in status " + t.Status().String())
@@ -210,6 +213,7 @@ func (r *TaskRunner) run(t *Task) {
 	}
 
 	t.At(time.Time{}) // clear schedule
+	kind := t.Kind()
 	tomb := &tomb.Tomb{}
 	r.tombs[t.ID()] = tomb
 	tomb.Go(func() error {
@@ -219,6 +223,7 @@ func (r *TaskRunner) run(t *Task) {
 		t0 := time.Now()
 		tomb.Kill(handler(t, tomb))
 		t1 := time.Now()
+		taskRunDuration.With(kind, phase).Observe(t1.Sub(t0).Seconds())
 
 		// Locks must be acquired in the same order everywhere.
 		r.mu.Lock()
@@ -439,6 +444,12 @@ ConsiderTasks:
 			continue
 		}
 
+		if chg := t.Change(); chg != nil && chg.IsPaused() && status == DoStatus {
+			// Held at task boundaries until resumed. Undoing is
+			// never held back so a failed change can roll back.
+			continue
+		}
+
 		if status == UndoStatus && handlers.undo == nil {
 			// Although this has no dependencies itself, it must have waited
 			// above too since follow up tasks may have handlers again.
@@ -680,6 +680,7 @@ func (ts *taskRunnerSuite) TestStopHandlerJustFinishing(c *C) {
 	defer r.Stop()
 
 	ch := make(chan bool)
+	runs := state.TaskRunDurationCount("just-finish", "do")
 	r.AddHandler("just-finish", func(t *state.Task, tb *tomb.Tomb) error {
 		ch <- true
 		<-tb.Dying()
@@ -702,6 +703,7 @@ func (ts *taskRunnerSuite) TestStopHandlerJustFinishing(c *C) {
 	c.Check(t.Status(), Equals, state.DoneStatus)
 	c.Check(t.DoingTime(), Not(Equals), 0)
 	c.Check(t.UndoingTime(), Equals, time.Duration(0))
+	c.Check(state.TaskRunDurationCount("just-finish", "do"), Equals, runs+1)
 }
 
 func (ts *taskRunnerSuite) TestStopKinds(c *C) {
@@ -1011,6 +1013,149 @@ func (ts *taskRunnerSuite) testTaskSerialization(c *C, setupBlocked func(r *stat
 	c.Check(ensureBeforeTick, HasLen, 0)
 }
 
+func (ts *taskRunnerSuite) TestPausedChange(c *C) {
+	sb := &stateBackend{}
+	st := state.New(sb)
+	r := state.NewTaskRunner(st)
+	defer r.Stop()
+
+	ch := make(chan bool)
+	var ran []string
+	r.AddHandler("blocking", func(t *state.Task, tb *tomb.Tomb) error {
+		ch <- true
+		<-ch
+		return nil
+	}, nil)
+	r.AddHandler("quick", func(t *state.Task, tb *tomb.Tomb) error {
+		ran = append(ran, t.Summary())
+		return nil
+	}, nil)
+
+	st.Lock()
+	chg := st.NewChange("install", "...")
+	t1 := st.NewTask("blocking", "1")
+	t2 := st.NewTask("quick", "2")
+	t2.WaitFor(t1)
+	t3 := st.NewTask("quick", "3")
+	t3.WaitFor(t1)
+	chg.AddAll(state.NewTaskSet(t1, t2, t3))
+	st.Unlock()
+
+	// pause while t1 is running
+	r.Ensure()
+	<-ch
+	st.Lock()
+	chg.Pause()
+	st.Unlock()
+	ch <- true
+	r.Wait()
+
+	// the running task finished, but no new tasks were started
+	st.Lock()
+	c.Check(t1.Status(), Equals, state.DoneStatus)
+	c.Check(t3.Status(), Equals, state.DoStatus)
+	st.Unlock()
+
+	r.Ensure()
+	r.Wait()
+	st.Lock()
+	c.Check(t2.Status(), Equals, state.DoStatus)
+	c.Check(t3.Status(), Equals, state.DoStatus)
+	c.Check(ran, HasLen, 0)
+	c.Check(chg.IsPaused(), Equals, true)
+
+	chg.Resume()
+	c.Check(chg.IsPaused(), Equals, false)
+	st.Unlock()
+
+	ensureChange(c, r, sb, chg)
+	st.Lock()
+	defer st.Unlock()
+	c.Check(chg.Status(), Equals, state.DoneStatus)
+	c.Check(ran, HasLen, 2)
+}
+
+func (ts *taskRunnerSuite) TestAbortPausedChange(c *C) {
+	sb := &stateBackend{}
+	st := state.New(sb)
+	r := state.NewTaskRunner(st)
+	defer r.Stop()
+
+	var undone bool
+	r.AddHandler("foo", func(t *state.Task, tb *tomb.Tomb) error {
+		return nil
+	}, func(t *state.Task, tb *tomb.Tomb) error {
+		undone = true
+		return nil
+	})
+
+	st.Lock()
+	chg := st.NewChange("install", "...")
+	t1 := st.NewTask("foo", "1")
+	t2 := st.NewTask("foo", "2")
+	t2.WaitFor(t1)
+	chg.AddAll(state.NewTaskSet(t1, t2))
+	st.Unlock()
+
+	r.Ensure()
+	r.Wait()
+
+	st.Lock()
+	chg.Pause()
+	chg.Abort()
+	c.Check(chg.IsPaused(), Equals, false)
+	st.Unlock()
+
+	ensureChange(c, r, sb, chg)
+	st.Lock()
+	defer st.Unlock()
+	c.Check(undone, Equals, true)
+	c.Check(t1.Status(), Equals, state.UndoneStatus)
+	c.Check(t2.Status(), Equals, state.HoldStatus)
+}
+
+func (ts *taskRunnerSuite) TestPausedChangeStillUndoes(c *C) {
+	sb := &stateBackend{}
+	st := state.New(sb)
+	r := state.NewTaskRunner(st)
+	defer r.Stop()
+
+	var undone bool
+	r.AddHandler("foo", func(t *state.Task, tb *tomb.Tomb) error {
+		return nil
+	}, func(t *state.Task, tb *tomb.Tomb) error {
+		undone = true
+		return nil
+	})
+	r.AddHandler("fail", func(t *state.Task, tb *tomb.Tomb) error {
+		// the change is paused as it fails
+		st.Lock()
+		defer st.Unlock()
+		t.Change().Pause()
+		return errors.New("boom")
+	}, nil)
+
+	st.Lock()
+	chg := st.NewChange("install", "...")
+	t1 := st.NewTask("foo", "1")
+	t2 := st.NewTask("fail", "2")
+	t2.WaitFor(t1)
+	t3 := st.NewTask("foo", "3")
+	t3.WaitFor(t2)
+	chg.AddAll(state.NewTaskSet(t1, t2, t3))
+	st.Unlock()
+
+	ensureChange(c, r, sb, chg)
+	st.Lock()
+	defer st.Unlock()
+	c.Check(chg.IsPaused(), Equals, true)
+	c.Check(undone, Equals, true)
+	c.Check(t1.Status(), Equals, state.UndoneStatus)
+	c.Check(t2.Status(), Equals, state.ErrorStatus)
+	c.Check(t3.Status(), Equals, state.HoldStatus)
+	c.Check(chg.Status(), Equals, state.ErrorStatus)
+}
+
 func (ts *taskRunnerSuite) TestTaskSerializationSetBlocked(c *C) {
 	// start first do1, and then do2 when nothing else is running
 	startedDo1 := false
@@ -227,6 +227,7 @@ func (s *State) AddWarning(message string, options *AddWarningOptions) {
 
 	warning.lastAdded = now
 	warning.repeatAfter = options.RepeatAfter
+	s.dirty.markWarning(message)
 }
 
 // RemoveWarning removes a warning given its message.
@@ -240,6 +241,7 @@ func (s *State) RemoveWarning(message string) error {
 	}
 
 	delete(s.warnings, message)
+	s.dirty.markWarning(message)
 	return nil
 }
 
@@ -269,6 +271,7 @@ func (s *State) OkayWarnings(t time.Time) int {
 	for _, w := range s.warnings {
 		if w.ShowAfter(t) {
 			w.lastShown = t
+			s.dirty.markWarning(w.message)
 			n++
 		}
 	}
@@ -325,5 +328,6 @@ func (s *State) UnshowAllWarnings() {
 	s.writing()
 	for _, w := range s.warnings {
 		w.lastShown = time.Time{}
+		s.dirty.markWarning(w.message)
 	}
 }
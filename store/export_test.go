@@ -262,3 +262,11 @@ type (
 )
 
 var ReportFetchAssertionsError = reportFetchAssertionsError
+
+func StoreRequestsCount(method, code string) float64 {
+	return storeRequests.With(method, code).Value()
+}
+
+func DownloadedBytesCount() float64 {
+	return downloadedBytes.With().Value()
+}
@@ -0,0 +1,53 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+
+/*
+ * Copyright (C) 2026 Canonical Ltd
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License version 3 as
+ * published by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package store
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/snapcore/snapd/metrics"
+)
+
+var (
+	storeRequests = metrics.MustRegister(metrics.NewCounterVec(
+		"snapd_store_requests",
+		"Requests made to the store, by HTTP method and response code.",
+		"method", "code"))
+
+	downloadedBytes = metrics.MustRegister(metrics.NewCounterVec(
+		"snapd_store_downloaded_bytes",
+		"Bytes downloaded from the store."))
+
+	downloadThroughput = metrics.MustRegister(metrics.NewHistogramVec(
+		"snapd_store_download_throughput_bytes_per_second",
+		"Average throughput of successful downloads from the store.",
+		[]float64{1e4, 1e5, 5e5, 1e6, 5e6, 1e7, 5e7, 1e8, 1e9}))
+)
+
+// countStoreRequest records the outcome of a request to the store, a nil
+// response meaning that the request failed before a response was received.
+func countStoreRequest(method string, resp *http.Response) {
+	code := "error"
+	if resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	storeRequests.With(method, code).Inc()
+}
@@ -731,6 +731,7 @@ func (s *Store) doRequest(ctx context.Context, client *http.Client, reqOptions *
 		}
 
 		resp, err := client.Do(req)
+		countStoreRequest(req.Method, resp)
 		if err != nil {
 			return nil, err
 		}
@@ -426,6 +426,7 @@ func (w *TransferSpeedMonitoringWriter) Write(p []byte) (n int, err error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.written += len(p)
+	downloadedBytes.With().Add(float64(len(p)))
 	return len(p), nil
 }
 
@@ -587,6 +588,9 @@ func downloadImpl(ctx context.Context, name, sha3_384, downloadURL string, user
 		// not using quantity.FormatFoo as this is just for debug
 		dt := time.Since(startTime)
 		r := dlSize / dt.Seconds()
+		if dlSize > 0 {
+			downloadThroughput.With().Observe(r)
+		}
 		var p rune
 		for _, p = range " kMGTPEZY" {
 			if r < 1000 {
@@ -1105,6 +1105,7 @@ func (s *storeDownloadSuite) TestTransferSpeedMonitoringWriterHappy(c *C) {
 	w, ctx := store.NewTransferSpeedMonitoringWriterAndContext(origCtx, 50*time.Millisecond, 1)
 
 	data := []byte{0, 0, 0, 0, 0}
+	written := store.DownloadedBytesCount()
 	quit := w.Monitor()
 
 	// write a few bytes every ~5ms, this should satisfy >=1 speed in 50ms
@@ -1119,6 +1120,8 @@ func (s *storeDownloadSuite) TestTransferSpeedMonitoringWriterHappy(c *C) {
 	close(quit)
 	c.Check(store.Cancelled(ctx), Equals, false)
 	c.Check(w.Err(), IsNil)
+	// all the writes were accounted for
+	c.Check(store.DownloadedBytesCount(), Equals, written+100*float64(len(data)))
 
 	// we should hit at least 100*5/50 = 10 measurement windows
 	c.Assert(w.MeasuredWindowsCount() >= 10, Equals, true, Commentf("%d", w.MeasuredWindowsCount()))
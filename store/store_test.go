@@ -479,6 +479,30 @@ func (s *storeTestSuite) TestDoRequestSetsAuth(c *C) {
 	c.Check(string(responseData), Equals, "response-data")
 }
 
+func (s *storeTestSuite) TestDoRequestCountsRequests(c *C) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(418)
+	}))
+	c.Assert(mockServer, NotNil)
+	defer mockServer.Close()
+
+	sto := store.New(&store.Config{}, nil)
+	endpoint, _ := url.Parse(mockServer.URL)
+
+	before := store.StoreRequestsCount("POST", "418")
+	response, err := sto.DoRequest(s.ctx, sto.Client(), store.NewRequestOptions("POST", endpoint), nil)
+	c.Assert(err, IsNil)
+	response.Body.Close()
+	c.Check(store.StoreRequestsCount("POST", "418"), Equals, before+1)
+
+	// requests which fail without a response are counted as errors
+	mockServer.Close()
+	before = store.StoreRequestsCount("POST", "error")
+	_, err = sto.DoRequest(s.ctx, sto.Client(), store.NewRequestOptions("POST", endpoint), nil)
+	c.Assert(err, NotNil)
+	c.Check(store.StoreRequestsCount("POST", "error"), Equals, before+1)
+}
+
 func (s *storeTestSuite) TestDoRequestDoesNotSetAuthForLocalOnlyUser(c *C) {
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		c.Check(r.UserAgent(), Equals, userAgent)
@@ -22,9 +22,12 @@ package systemd
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"log/syslog"
 	"net"
 	"os"
+	"strconv"
+	"time"
 )
 
 var journalStdoutPath = "/run/systemd/journal/stdout"
@@ -70,3 +73,28 @@ func NewJournalStreamFile(identifier string, priority syslog.Priority, levelPref
 
 	return conn.File()
 }
+
+// auditLog calls journalctl to get the JSON logs of the kernel and of the
+// audit subsystem, which include the AppArmor and seccomp denials, logged
+// since the given time. All the logs are returned if since is zero.
+var auditLog = func(since time.Time) (io.ReadCloser, error) {
+	args := []string{"-o", "json", "--no-pager", "_TRANSPORT=kernel", "_TRANSPORT=audit"}
+	if !since.IsZero() {
+		args = append(args, "--since=@"+strconv.FormatInt(since.Unix(), 10))
+	}
+	return osutilStreamCommand("journalctl", args...)
+}
+
+// AuditLog returns the JSON logs of the kernel and of the audit subsystem
+// logged since the given time.
+func AuditLog(since time.Time) (io.ReadCloser, error) {
+	return auditLog(since)
+}
+
+func MockAuditLog(f func(since time.Time) (io.ReadCloser, error)) (restore func()) {
+	old := auditLog
+	auditLog = f
+	return func() {
+		auditLog = old
+	}
+}
@@ -20,9 +20,12 @@
 package systemd_test
 
 import (
+	"io"
 	"log/syslog"
 	"net"
 	"path"
+	"strings"
+	"time"
 
 	. "gopkg.in/check.v1"
 
@@ -87,3 +90,26 @@ func (j *journalTestSuite) TestStreamFileHeader(c *C) {
 
 	<-doneCh
 }
+
+func (j *journalTestSuite) TestAuditLog(c *C) {
+	var calls [][]string
+	restore := MockOsutilStreamCommand(func(name string, args ...string) (io.ReadCloser, error) {
+		calls = append(calls, append([]string{name}, args...))
+		return io.NopCloser(strings.NewReader("{}\n")), nil
+	})
+	defer restore()
+
+	r, err := AuditLog(time.Time{})
+	c.Assert(err, IsNil)
+	data, err := io.ReadAll(r)
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, "{}\n")
+
+	_, err = AuditLog(time.Unix(1700000000, 500))
+	c.Assert(err, IsNil)
+
+	c.Check(calls, DeepEquals, [][]string{
+		{"journalctl", "-o", "json", "--no-pager", "_TRANSPORT=kernel", "_TRANSPORT=audit"},
+		{"journalctl", "-o", "json", "--no-pager", "_TRANSPORT=kernel", "_TRANSPORT=audit", "--since=@1700000000"},
+	})
+}
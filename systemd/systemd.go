@@ -702,6 +702,8 @@ type UnitStatus struct {
 	Names   []string
 	Enabled bool
 	Active  bool
+	// Failed is true if the unit is in the failed state.
+	Failed bool
 	// Installed is false if the queried unit doesn't exist.
 	Installed bool
 	// NeedDaemonReload is true when systemd reports that the unit on disk
@@ -809,6 +811,7 @@ func (s *systemd) getUnitStatus(properties []string, unitNames []string) ([]*Uni
 		case "ActiveState":
 			// made to match “systemctl is-active” behaviour, at least at systemd 229
 			cur.Active = v == "active" || v == "reloading"
+			cur.Failed = v == "failed"
 		case "UnitFileState":
 			// "static" means it can't be disabled
 			cur.Enabled = v == "enabled" || v == "static"